@@ -0,0 +1,216 @@
+// Package analytics answers how much vendors sell and when. Sales are rolled
+// up by the hour as orders are taken, updated, paid and deleted, in the
+// transaction that writes them, so reports read the rollups rather than the
+// orders. Rollup hours are kept in UTC and
+// read in the time zone asked for, zones that are not a whole number of
+// hours off UTC are read to the hour.
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// DefaultTimezone is the time zone sales are read in unless another one is
+// asked for.
+const DefaultTimezone = "Africa/Nairobi"
+
+// Interval is how long the buckets of a sales series are.
+type Interval string
+
+// Sales series intervals.
+const (
+	Hour  Interval = "hour"
+	Day   Interval = "day"
+	Week  Interval = "week" // Weeks start on Monday.
+	Month Interval = "month"
+)
+
+// Dimension is what a sales series is broken down by, on top of time.
+type Dimension string
+
+// Sales series dimensions.
+const (
+	ByVendor Dimension = "vendor"
+	ByPlace  Dimension = "place"
+	ByStatus Dimension = "status"
+)
+
+// Query selects the sales a report is made of. Sales are taken from From
+// up to but excluding To.
+type Query struct {
+	Vendor   string // Limits sales to a vendor, all vendors when empty.
+	Place    string // Limits sales to a place i.e. inhouse.
+	Status   string // Limits sales to orders of a status i.e. paid.
+	From     time.Time
+	To       time.Time
+	Interval Interval
+	By       Dimension      // Breaks a series down by vendor, place or status, if set.
+	Location *time.Location // The time zone buckets and hours are read in.
+}
+
+// Validate returns an error if the query representation is invalid.
+func (q Query) Validate() error {
+	if q.Location == nil || q.Location == time.Local || !q.From.Before(q.To) {
+		return errors.ErrInvalidQueryParams
+	}
+	switch q.Interval {
+	case Hour, Day, Week, Month:
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+	switch q.By {
+	case "", ByVendor, ByPlace, ByStatus:
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+	if q.Place != "" && !orders.ValidatePlaces(q.Place) {
+		return errors.ErrInvalidQueryParams
+	}
+	if q.Status != "" && !orders.ValidateStatus(q.Status) {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
+
+// Previous returns the query of the period of the same length right
+// before the query's.
+func (q Query) Previous() Query {
+	q.From, q.To = q.From.Add(-q.To.Sub(q.From)), q.From
+	return q
+}
+
+// Summary sums up the orders of a period.
+type Summary struct {
+	Orders        uint64 `json:"orders"`
+	Revenue       uint64 `json:"revenue"`        // What the orders were charged.
+	AverageTicket uint64 `json:"average_ticket"` // Revenue per order.
+}
+
+// NewSummary returns the summary of the orders and the revenue taken.
+func NewSummary(orders, revenue uint64) Summary {
+	s := Summary{Orders: orders, Revenue: revenue}
+	if orders > 0 {
+		s.AverageTicket = revenue / orders
+	}
+	return s
+}
+
+// Bucket is the sales of an interval of a series.
+type Bucket struct {
+	Start  time.Time `json:"start"`
+	Vendor string    `json:"vendor,omitempty"`
+	Place  string    `json:"place,omitempty"`
+	Status string    `json:"status,omitempty"`
+	Summary
+}
+
+// Series is the sales of a period bucketed by interval.
+type Series struct {
+	Buckets []Bucket `json:"buckets"`
+	Total   Summary  `json:"total"`
+}
+
+// TopItem is the sales of a good over a period.
+type TopItem struct {
+	Item     string `json:"item"` // The menu item identifier, or the name of goods not ordered from a menu.
+	Name     string `json:"name"`
+	Quantity uint64 `json:"quantity"`
+	Revenue  uint64 `json:"revenue"` // What the lines were charged, before order discounts.
+}
+
+// HeatCell is the sales of an hour of a day of the week over a period.
+type HeatCell struct {
+	Weekday int `json:"weekday"` // 1 is Monday and 7 is Sunday.
+	Hour    int `json:"hour"`
+	Summary
+}
+
+// Comparison compares the sales of a period with the period of the same
+// length right before it. Changes are in percent, and zero when there were
+// no sales before.
+type Comparison struct {
+	Current       Summary   `json:"current"`
+	Previous      Summary   `json:"previous"`
+	PreviousFrom  time.Time `json:"previous_from"`
+	PreviousTo    time.Time `json:"previous_to"`
+	OrdersChange  float64   `json:"orders_change"`
+	RevenueChange float64   `json:"revenue_change"`
+	TicketChange  float64   `json:"average_ticket_change"`
+}
+
+// Sales is what orders add to or take from an hour of a vendor's sales.
+type Sales struct {
+	Vendor  string
+	Hour    time.Time // The hour in UTC.
+	Place   string
+	Status  string
+	Orders  int64
+	Revenue int64
+}
+
+// ItemSales is what order lines add to or take from an hour of a good's
+// sales.
+type ItemSales struct {
+	Vendor   string
+	Hour     time.Time // The hour in UTC.
+	Place    string
+	Status   string
+	Item     string
+	Name     string
+	Quantity int64
+	Revenue  int64
+}
+
+// Rollup is what orders add to or take from the hourly rollups.
+type Rollup struct {
+	Sales []Sales
+	Items []ItemSales
+}
+
+// Empty reports whether the rollup changes nothing.
+func (r Rollup) Empty() bool {
+	return len(r.Sales) == 0 && len(r.Items) == 0
+}
+
+// Service specifies the sales analytics API.
+type Service interface {
+	// Revenue returns the orders and revenue of a period bucketed by the
+	// query's interval.
+	Revenue(ctx context.Context, token string, q Query) (Series, error)
+
+	// TopItems returns the best selling goods of a period, most sold first.
+	TopItems(ctx context.Context, token string, q Query, limit uint64) ([]TopItem, error)
+
+	// Heatmap returns the sales of a period by day of the week and hour.
+	Heatmap(ctx context.Context, token string, q Query) ([]HeatCell, error)
+
+	// Compare compares the sales of a period with the period before it.
+	Compare(ctx context.Context, token string, q Query) (Comparison, error)
+
+	// Rebuild rolls up the vendor's orders from scratch, all vendors' when
+	// vendor is empty. It returns the number of orders rolled up.
+	Rebuild(ctx context.Context, token, vendor string) (uint64, error)
+}
+
+// Repository specifies the hourly rollups persistence API.
+type Repository interface {
+	// Replace replaces the vendor's rollups, all vendors' when vendor is
+	// empty.
+	Replace(ctx context.Context, vendor string, rollup Rollup) error
+
+	// Revenue returns the buckets of the query's series.
+	Revenue(ctx context.Context, q Query) ([]Bucket, error)
+
+	// Summary sums up the sales of the query's period.
+	Summary(ctx context.Context, q Query) (Summary, error)
+
+	// TopItems returns the best selling goods of the query's period.
+	TopItems(ctx context.Context, q Query, limit uint64) ([]TopItem, error)
+
+	// Heatmap returns the sales of the query's period by day and hour.
+	Heatmap(ctx context.Context, q Query) ([]HeatCell, error)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func revenueEndpoint(svc analytics.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		series, err := svc.Revenue(ctx, req.token, req.query)
+		if err != nil {
+			return nil, err
+		}
+		return seriesRes{Series: series}, nil
+	}
+}
+
+func topItemsEndpoint(svc analytics.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		items, err := svc.TopItems(ctx, req.token, req.query, req.limit)
+		if err != nil {
+			return nil, err
+		}
+		res := topItemsRes{Items: []analytics.TopItem{}}
+		res.Items = append(res.Items, items...)
+		return res, nil
+	}
+}
+
+func heatmapEndpoint(svc analytics.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cells, err := svc.Heatmap(ctx, req.token, req.query)
+		if err != nil {
+			return nil, err
+		}
+		res := heatmapRes{Cells: []analytics.HeatCell{}}
+		res.Cells = append(res.Cells, cells...)
+		return res, nil
+	}
+}
+
+func compareEndpoint(svc analytics.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		comparison, err := svc.Compare(ctx, req.token, req.query)
+		if err != nil {
+			return nil, err
+		}
+		return comparisonRes{Comparison: comparison}, nil
+	}
+}
+
+func rebuildEndpoint(svc analytics.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(rebuildReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		count, err := svc.Rebuild(ctx, req.token, req.vendor)
+		if err != nil {
+			return nil, err
+		}
+		return rebuildRes{Vendor: req.vendor, Orders: count}, nil
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/go-kit/log"
+)
+
+var _ analytics.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    analytics.Service
+}
+
+// LoggingMiddleware adds logging facilities to the analytics service.
+func LoggingMiddleware(svc analytics.Service, logger log.Logger) analytics.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Revenue(ctx context.Context, token string, q analytics.Query) (series analytics.Series, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "sales_revenue",
+			"token", token,
+			"vendor", q.Vendor,
+			"from", q.From,
+			"to", q.To,
+			"interval", q.Interval,
+			"by", q.By,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Revenue(ctx, token, q)
+}
+
+func (lm *loggingMiddleware) TopItems(ctx context.Context, token string, q analytics.Query, limit uint64) (items []analytics.TopItem, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "top_items",
+			"token", token,
+			"vendor", q.Vendor,
+			"from", q.From,
+			"to", q.To,
+			"limit", limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.TopItems(ctx, token, q, limit)
+}
+
+func (lm *loggingMiddleware) Heatmap(ctx context.Context, token string, q analytics.Query) (cells []analytics.HeatCell, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "sales_heatmap",
+			"token", token,
+			"vendor", q.Vendor,
+			"from", q.From,
+			"to", q.To,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Heatmap(ctx, token, q)
+}
+
+func (lm *loggingMiddleware) Compare(ctx context.Context, token string, q analytics.Query) (comparison analytics.Comparison, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "compare_sales",
+			"token", token,
+			"vendor", q.Vendor,
+			"from", q.From,
+			"to", q.To,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Compare(ctx, token, q)
+}
+
+func (lm *loggingMiddleware) Rebuild(ctx context.Context, token, vendor string) (count uint64, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "rebuild_rollups",
+			"token", token,
+			"vendor", vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Rebuild(ctx, token, vendor)
+}
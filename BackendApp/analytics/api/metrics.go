@@ -0,0 +1,74 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ analytics.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     analytics.Service
+}
+
+// MetricsMiddleware instruments the analytics service by tracking request count
+// and latency.
+func MetricsMiddleware(svc analytics.Service, counter metrics.Counter, latency metrics.Histogram) analytics.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Revenue(ctx context.Context, token string, q analytics.Query) (analytics.Series, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "sales_revenue").Add(1)
+		ms.latency.With("method", "sales_revenue").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Revenue(ctx, token, q)
+}
+
+func (ms *metricsMiddleware) TopItems(ctx context.Context, token string, q analytics.Query, limit uint64) ([]analytics.TopItem, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "top_items").Add(1)
+		ms.latency.With("method", "top_items").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TopItems(ctx, token, q, limit)
+}
+
+func (ms *metricsMiddleware) Heatmap(ctx context.Context, token string, q analytics.Query) ([]analytics.HeatCell, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "sales_heatmap").Add(1)
+		ms.latency.With("method", "sales_heatmap").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Heatmap(ctx, token, q)
+}
+
+func (ms *metricsMiddleware) Compare(ctx context.Context, token string, q analytics.Query) (analytics.Comparison, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "compare_sales").Add(1)
+		ms.latency.With("method", "compare_sales").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Compare(ctx, token, q)
+}
+
+func (ms *metricsMiddleware) Rebuild(ctx context.Context, token, vendor string) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rebuild_rollups").Add(1)
+		ms.latency.With("method", "rebuild_rollups").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Rebuild(ctx, token, vendor)
+}
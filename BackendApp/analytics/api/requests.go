@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+const (
+	maxLimitSize = 100
+	defLimit     = 10
+)
+
+type queryReq struct {
+	token string
+	query analytics.Query
+	limit uint64
+}
+
+func (req queryReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type rebuildReq struct {
+	token  string
+	vendor string
+}
+
+func (req rebuildReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return nil
+}
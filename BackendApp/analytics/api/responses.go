@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*seriesRes)(nil)
+	_ Response = (*topItemsRes)(nil)
+	_ Response = (*heatmapRes)(nil)
+	_ Response = (*comparisonRes)(nil)
+	_ Response = (*rebuildRes)(nil)
+)
+
+type seriesRes struct {
+	analytics.Series
+}
+
+func (res seriesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res seriesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res seriesRes) Empty() bool {
+	return false
+}
+
+type topItemsRes struct {
+	Items []analytics.TopItem `json:"items"`
+}
+
+func (res topItemsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res topItemsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res topItemsRes) Empty() bool {
+	return false
+}
+
+type heatmapRes struct {
+	Cells []analytics.HeatCell `json:"cells"`
+}
+
+func (res heatmapRes) Code() int {
+	return http.StatusOK
+}
+
+func (res heatmapRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res heatmapRes) Empty() bool {
+	return false
+}
+
+type comparisonRes struct {
+	analytics.Comparison
+}
+
+func (res comparisonRes) Code() int {
+	return http.StatusOK
+}
+
+func (res comparisonRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res comparisonRes) Empty() bool {
+	return false
+}
+
+type rebuildRes struct {
+	Vendor string `json:"vendor,omitempty"`
+	Orders uint64 `json:"orders"` // The orders rolled up.
+}
+
+func (res rebuildRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rebuildRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rebuildRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	placeKey    = "place"
+	statusKey   = "status"
+	fromKey     = "from"
+	toKey       = "to"
+	intervalKey = "interval"
+	byKey       = "by"
+	tzKey       = "tz"
+)
+
+// MakeAnalyticsHandler returns a HTTP handler for sales analytics API
+// endpoints.
+func MakeAnalyticsHandler(svc analytics.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("GET").Path("/analytics/revenue").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint sales_revenue")(revenueEndpoint(svc)),
+		decodeQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/analytics/items").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint top_items")(topItemsEndpoint(svc)),
+		decodeQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/analytics/heatmap").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint sales_heatmap")(heatmapEndpoint(svc)),
+		decodeQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/analytics/compare").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint compare_sales")(compareEndpoint(svc)),
+		decodeQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/analytics/rebuild").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint rebuild_rollups")(rebuildEndpoint(svc)),
+		decodeRebuild,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeQuery(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	req := queryReq{
+		token: decodeToken(r),
+		query: analytics.Query{
+			Vendor:   q.Get(vendorKey),
+			Place:    q.Get(placeKey),
+			Status:   q.Get(statusKey),
+			Interval: analytics.Interval(q.Get(intervalKey)),
+			By:       analytics.Dimension(q.Get(byKey)),
+		},
+		limit: defLimit,
+	}
+	var err error
+	if req.query.From, req.query.To, err = decodePeriod(r); err != nil {
+		return nil, err
+	}
+	if q.Has(tzKey) {
+		if req.query.Location, err = time.LoadLocation(q.Get(tzKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(limitKey) {
+		if req.limit, err = strconv.ParseUint(q.Get(limitKey), 10, 64); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeRebuild(_ context.Context, r *http.Request) (interface{}, error) {
+	req := rebuildReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	return req, nil
+}
+
+func decodePeriod(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if r.URL.Query().Has(fromKey) {
+		from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return from, to, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrLimitSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
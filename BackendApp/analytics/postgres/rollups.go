@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ analytics.Repository = (*rollupsRepo)(nil)
+
+type rollupsRepo struct {
+	db *sqlx.DB
+}
+
+// NewRollupsRepo instantiates a PostgreSQL implementation of the hourly
+// sales rollups repository.
+func NewRollupsRepo(db *sqlx.DB) analytics.Repository {
+	return &rollupsRepo{
+		db: db,
+	}
+}
+
+func (repo rollupsRepo) Replace(ctx context.Context, vendor string, rollup analytics.Rollup) error {
+	return pgutil.InTx(ctx, repo.db, errors.ErrUpdateEntity, func(tx *sqlx.Tx) error {
+		for _, table := range []string{"sales_rollups", "item_rollups"} {
+			q := fmt.Sprintf(`DELETE FROM %s WHERE $1 = '' OR vendor = $1`, table)
+			if _, err := tx.ExecContext(ctx, q, vendor); err != nil {
+				return errors.Wrap(errors.ErrRemoveEntity, err)
+			}
+		}
+		return apply(ctx, tx, rollup)
+	})
+}
+
+// Record rolls up the orders the orders repository writes, in the
+// transaction that writes them, so the rollups move with the orders. It is a
+// hook of the PostgreSQL orders repository, which keeps its orders in the
+// same database as the rollups.
+func Record(ctx context.Context, tx *sqlx.Tx, before, after []orders.Order) error {
+	rollup := analytics.Change(before, after)
+	if rollup.Empty() {
+		return nil
+	}
+	return apply(ctx, tx, rollup)
+}
+
+// apply adds the rollup to the hourly rollups, dropping the hours and
+// goods that are left without sales.
+func apply(ctx context.Context, tx *sqlx.Tx, rollup analytics.Rollup) error {
+	sq := `INSERT INTO sales_rollups (vendor, hour, place, status, orders, revenue)
+		   VALUES (:vendor, :hour, :place, :status, :orders, :revenue)
+		   ON CONFLICT (vendor, hour, place, status) DO UPDATE
+		   SET orders = sales_rollups.orders + EXCLUDED.orders, revenue = sales_rollups.revenue + EXCLUDED.revenue`
+	sdq := `DELETE FROM sales_rollups
+			WHERE vendor = :vendor AND hour = :hour AND place = :place AND status = :status AND orders = 0`
+	for _, s := range rollup.Sales {
+		dbs := toDBSales(s)
+		if _, err := tx.NamedExecContext(ctx, sq, dbs); err != nil {
+			return errors.Wrap(errors.ErrUpdateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, sdq, dbs); err != nil {
+			return errors.Wrap(errors.ErrRemoveEntity, err)
+		}
+	}
+
+	iq := `INSERT INTO item_rollups (vendor, hour, place, status, item, name, quantity, revenue)
+		   VALUES (:vendor, :hour, :place, :status, :item, :name, :quantity, :revenue)
+		   ON CONFLICT (vendor, hour, place, status, item) DO UPDATE
+		   SET name = CASE WHEN EXCLUDED.name = '' THEN item_rollups.name ELSE EXCLUDED.name END,
+		   quantity = item_rollups.quantity + EXCLUDED.quantity, revenue = item_rollups.revenue + EXCLUDED.revenue`
+	idq := `DELETE FROM item_rollups
+			WHERE vendor = :vendor AND hour = :hour AND place = :place AND status = :status AND item = :item AND quantity = 0`
+	for _, is := range rollup.Items {
+		dbi := toDBItemSales(is)
+		if _, err := tx.NamedExecContext(ctx, iq, dbi); err != nil {
+			return errors.Wrap(errors.ErrUpdateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, idq, dbi); err != nil {
+			return errors.Wrap(errors.ErrRemoveEntity, err)
+		}
+	}
+	return nil
+}
+
+func (repo rollupsRepo) Revenue(ctx context.Context, q analytics.Query) ([]analytics.Bucket, error) {
+	dimension := `''`
+	if q.By != "" {
+		dimension = string(q.By)
+	}
+	where, params := filter(q)
+	params["interval"] = string(q.Interval)
+	query := fmt.Sprintf(`SELECT date_trunc(:interval, (hour AT TIME ZONE 'UTC') AT TIME ZONE :tz) AS start, %s AS dimension,
+			SUM(orders) AS orders, SUM(revenue) AS revenue
+		  FROM sales_rollups %s GROUP BY 1, 2 HAVING SUM(orders) > 0 ORDER BY 1, 2`, dimension, where)
+
+	rows, err := repo.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	buckets := []analytics.Bucket{}
+	for rows.Next() {
+		dbb := dbBucket{}
+		if err := rows.StructScan(&dbb); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		bucket := analytics.Bucket{
+			Start:   inLocation(dbb.Start, q.Location),
+			Summary: analytics.NewSummary(dbb.Orders, dbb.Revenue),
+		}
+		switch q.By {
+		case analytics.ByVendor:
+			bucket.Vendor = dbb.Dimension
+		case analytics.ByPlace:
+			bucket.Place = dbb.Dimension
+		case analytics.ByStatus:
+			bucket.Status = dbb.Dimension
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+func (repo rollupsRepo) Summary(ctx context.Context, q analytics.Query) (analytics.Summary, error) {
+	where, params := filter(q)
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(orders), 0) AS orders, COALESCE(SUM(revenue), 0) AS revenue FROM sales_rollups %s`, where)
+
+	rows, err := repo.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return analytics.Summary{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	dbs := dbSummary{}
+	if rows.Next() {
+		if err := rows.StructScan(&dbs); err != nil {
+			return analytics.Summary{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+	}
+	return analytics.NewSummary(dbs.Orders, dbs.Revenue), nil
+}
+
+func (repo rollupsRepo) TopItems(ctx context.Context, q analytics.Query, limit uint64) ([]analytics.TopItem, error) {
+	where, params := filter(q)
+	params["limit"] = limit
+	query := fmt.Sprintf(`SELECT item, MAX(name) AS name, SUM(quantity) AS quantity, SUM(revenue) AS revenue
+		  FROM item_rollups %s GROUP BY item HAVING SUM(quantity) > 0
+		  ORDER BY quantity DESC, revenue DESC, item LIMIT :limit`, where)
+
+	rows, err := repo.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	items := []analytics.TopItem{}
+	for rows.Next() {
+		dbi := dbTopItem{}
+		if err := rows.StructScan(&dbi); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, analytics.TopItem(dbi))
+	}
+	return items, nil
+}
+
+func (repo rollupsRepo) Heatmap(ctx context.Context, q analytics.Query) ([]analytics.HeatCell, error) {
+	where, params := filter(q)
+	query := fmt.Sprintf(`SELECT CAST(EXTRACT(ISODOW FROM local_hour) AS INT) AS weekday, CAST(EXTRACT(HOUR FROM local_hour) AS INT) AS hour,
+			SUM(orders) AS orders, SUM(revenue) AS revenue
+		  FROM (SELECT (hour AT TIME ZONE 'UTC') AT TIME ZONE :tz AS local_hour, orders, revenue FROM sales_rollups %s) s
+		  GROUP BY 1, 2 HAVING SUM(orders) > 0 ORDER BY 1, 2`, where)
+
+	rows, err := repo.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	cells := []analytics.HeatCell{}
+	for rows.Next() {
+		dbc := dbHeatCell{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		cells = append(cells, analytics.HeatCell{
+			Weekday: dbc.Weekday,
+			Hour:    dbc.Hour,
+			Summary: analytics.NewSummary(dbc.Orders, dbc.Revenue),
+		})
+	}
+	return cells, nil
+}
+
+// filter returns the WHERE clause and the parameters selecting the query's
+// rollups. Rollup hours are in UTC.
+func filter(q analytics.Query) (string, map[string]interface{}) {
+	params := map[string]interface{}{
+		"vendor": q.Vendor,
+		"place":  q.Place,
+		"status": q.Status,
+		"from":   q.From.UTC(),
+		"to":     q.To.UTC(),
+		"tz":     q.Location.String(),
+	}
+	where := []string{"hour >= :from", "hour < :to"}
+	if q.Vendor != "" {
+		where = append(where, "vendor = :vendor")
+	}
+	if q.Place != "" {
+		where = append(where, "place = :place")
+	}
+	if q.Status != "" {
+		where = append(where, "status = :status")
+	}
+	return fmt.Sprintf("WHERE %s", strings.Join(where, " AND ")), params
+}
+
+// inLocation reads the wall clock of t in loc, Postgres returns local
+// timestamps without a time zone.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+type dbSales struct {
+	Vendor  string    `db:"vendor"`
+	Hour    time.Time `db:"hour"`
+	Place   string    `db:"place"`
+	Status  string    `db:"status"`
+	Orders  int64     `db:"orders"`
+	Revenue int64     `db:"revenue"`
+}
+
+func toDBSales(s analytics.Sales) dbSales {
+	return dbSales{
+		Vendor:  s.Vendor,
+		Hour:    s.Hour.UTC(),
+		Place:   s.Place,
+		Status:  s.Status,
+		Orders:  s.Orders,
+		Revenue: s.Revenue,
+	}
+}
+
+type dbItemSales struct {
+	Vendor   string    `db:"vendor"`
+	Hour     time.Time `db:"hour"`
+	Place    string    `db:"place"`
+	Status   string    `db:"status"`
+	Item     string    `db:"item"`
+	Name     string    `db:"name"`
+	Quantity int64     `db:"quantity"`
+	Revenue  int64     `db:"revenue"`
+}
+
+func toDBItemSales(is analytics.ItemSales) dbItemSales {
+	return dbItemSales{
+		Vendor:   is.Vendor,
+		Hour:     is.Hour.UTC(),
+		Place:    is.Place,
+		Status:   is.Status,
+		Item:     is.Item,
+		Name:     is.Name,
+		Quantity: is.Quantity,
+		Revenue:  is.Revenue,
+	}
+}
+
+type dbBucket struct {
+	Start     time.Time `db:"start"`
+	Dimension string    `db:"dimension"`
+	Orders    uint64    `db:"orders"`
+	Revenue   uint64    `db:"revenue"`
+}
+
+type dbSummary struct {
+	Orders  uint64 `db:"orders"`
+	Revenue uint64 `db:"revenue"`
+}
+
+type dbTopItem struct {
+	Item     string `db:"item"`
+	Name     string `db:"name"`
+	Quantity uint64 `db:"quantity"`
+	Revenue  uint64 `db:"revenue"`
+}
+
+type dbHeatCell struct {
+	Weekday int    `db:"weekday"`
+	Hour    int    `db:"hour"`
+	Orders  uint64 `db:"orders"`
+	Revenue uint64 `db:"revenue"`
+}
@@ -0,0 +1,137 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+type salesKey struct {
+	vendor, place, status string
+	hour                  time.Time
+}
+
+type itemKey struct {
+	vendor, place, status, item string
+	hour                        time.Time
+}
+
+// Change returns what orders changing from before to after add to and take
+// from the hourly rollups. Orders taken have nothing before and orders
+// deleted nothing after.
+func Change(before, after []orders.Order) Rollup {
+	r := newRollup()
+	for _, order := range before {
+		r.add(order, -1)
+	}
+	for _, order := range after {
+		r.add(order, 1)
+	}
+	return r.rollup()
+}
+
+// rollup accumulates what orders add to and take from the hourly rollups.
+type rollup struct {
+	sales map[salesKey]*Sales
+	items map[itemKey]*ItemSales
+}
+
+func newRollup() *rollup {
+	return &rollup{
+		sales: map[salesKey]*Sales{},
+		items: map[itemKey]*ItemSales{},
+	}
+}
+
+// add adds the order to the rollup, or takes it off when sign is negative.
+// Orders without item lines are rolled up as a single line of their name.
+func (r *rollup) add(order orders.Order, sign int64) {
+	if order.ID == "" {
+		return
+	}
+	hour := hourOf(order.CreatedAt)
+	sk := salesKey{vendor: order.Vendor, place: order.Place, status: order.Status, hour: hour}
+	s, ok := r.sales[sk]
+	if !ok {
+		s = &Sales{Vendor: order.Vendor, Hour: hour, Place: order.Place, Status: order.Status}
+		r.sales[sk] = s
+	}
+	s.Orders += sign
+	s.Revenue += sign * int64(order.Price)
+
+	items := order.Items
+	if len(items) == 0 {
+		items = []orders.Item{{Name: order.Name, Quantity: 1, Price: order.Price}}
+	}
+	for _, item := range items {
+		id := item.ID
+		if id == "" {
+			id = item.Name
+		}
+		ik := itemKey{vendor: order.Vendor, place: order.Place, status: order.Status, item: id, hour: hour}
+		is, ok := r.items[ik]
+		if !ok {
+			is = &ItemSales{Vendor: order.Vendor, Hour: hour, Place: order.Place, Status: order.Status, Item: id}
+			r.items[ik] = is
+		}
+		if item.Name != "" {
+			is.Name = item.Name
+		}
+		is.Quantity += sign * int64(item.Quantity)
+		is.Revenue += sign * int64(item.Total())
+	}
+}
+
+// rollup returns the changes of the rollup, leaving out what cancelled out.
+// Changes are sorted so concurrent rollups lock rows in the same order.
+func (r *rollup) rollup() Rollup {
+	var res Rollup
+	for _, s := range r.sales {
+		if s.Orders != 0 || s.Revenue != 0 {
+			res.Sales = append(res.Sales, *s)
+		}
+	}
+	for _, is := range r.items {
+		if is.Quantity != 0 || is.Revenue != 0 {
+			res.Items = append(res.Items, *is)
+		}
+	}
+	sort.Slice(res.Sales, func(i, j int) bool {
+		a, b := res.Sales[i], res.Sales[j]
+		if a.Vendor != b.Vendor {
+			return a.Vendor < b.Vendor
+		}
+		if !a.Hour.Equal(b.Hour) {
+			return a.Hour.Before(b.Hour)
+		}
+		if a.Place != b.Place {
+			return a.Place < b.Place
+		}
+		return a.Status < b.Status
+	})
+	sort.Slice(res.Items, func(i, j int) bool {
+		a, b := res.Items[i], res.Items[j]
+		if a.Vendor != b.Vendor {
+			return a.Vendor < b.Vendor
+		}
+		if !a.Hour.Equal(b.Hour) {
+			return a.Hour.Before(b.Hour)
+		}
+		if a.Place != b.Place {
+			return a.Place < b.Place
+		}
+		if a.Status != b.Status {
+			return a.Status < b.Status
+		}
+		return a.Item < b.Item
+	})
+	return res
+}
+
+// hourOf returns the hour in UTC an order was taken in. Orders keep the
+// wall clock of the server that took them, as the drawer's business days
+// do.
+func hourOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local).UTC()
+}
@@ -0,0 +1,37 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+func TestChange(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 30, 0, 0, time.Local)
+	before := orders.Order{ID: "order", Vendor: "mama-oliech", Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered, CreatedAt: at}
+	after := before
+	after.Price, after.Status = 150, orders.StatusPaid
+
+	// An order paid at a new price moves from one status to the other.
+	rollup := analytics.Change([]orders.Order{before}, []orders.Order{after})
+	got := map[string]analytics.Sales{}
+	for _, s := range rollup.Sales {
+		got[s.Status] = s
+	}
+	if s := got[orders.StatusOrdered]; s.Orders != -1 || s.Revenue != -100 {
+		t.Errorf("ordered sales changed by %d orders and %d revenue, want -1 and -100", s.Orders, s.Revenue)
+	}
+	if s := got[orders.StatusPaid]; s.Orders != 1 || s.Revenue != 150 {
+		t.Errorf("paid sales changed by %d orders and %d revenue, want 1 and 150", s.Orders, s.Revenue)
+	}
+
+	// Writing an order as it was changes nothing.
+	if rollup := analytics.Change([]orders.Order{after}, []orders.Order{after}); !rollup.Empty() {
+		t.Errorf("rewriting an order changed the rollups by %+v", rollup)
+	}
+	if rollup := analytics.Change(nil, nil); !rollup.Empty() {
+		t.Errorf("writing no orders changed the rollups by %+v", rollup)
+	}
+}
@@ -0,0 +1,150 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+const pageSize = 100
+
+// Config defines the options the analytics service uses.
+type Config struct {
+	Location *time.Location // The time zone sales are read in when a query has none. Defaults to Africa/Nairobi, or UTC without a time zone database.
+}
+
+var _ Service = (*analyticsService)(nil)
+
+type analyticsService struct {
+	config Config
+	repo   Repository
+	orders orders.OrderService
+}
+
+// NewService instantiates the analytics service implementation. Orders are
+// only read to rebuild the rollups.
+func NewService(config Config, repo Repository, ordersSvc orders.OrderService) Service {
+	if config.Location == nil {
+		config.Location = time.UTC
+		if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+			config.Location = loc
+		}
+	}
+	return &analyticsService{
+		config: config,
+		repo:   repo,
+		orders: ordersSvc,
+	}
+}
+
+func (svc analyticsService) Revenue(ctx context.Context, token string, q Query) (Series, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return Series{}, err
+	}
+	buckets, err := svc.repo.Revenue(ctx, q)
+	if err != nil {
+		return Series{}, err
+	}
+	var count, revenue uint64
+	for _, b := range buckets {
+		count += b.Orders
+		revenue += b.Revenue
+	}
+	return Series{Buckets: buckets, Total: NewSummary(count, revenue)}, nil
+}
+
+func (svc analyticsService) TopItems(ctx context.Context, token string, q Query, limit uint64) ([]TopItem, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return svc.repo.TopItems(ctx, q, limit)
+}
+
+func (svc analyticsService) Heatmap(ctx context.Context, token string, q Query) ([]HeatCell, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return svc.repo.Heatmap(ctx, q)
+}
+
+func (svc analyticsService) Compare(ctx context.Context, token string, q Query) (Comparison, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return Comparison{}, err
+	}
+	prev := q.Previous()
+	current, err := svc.repo.Summary(ctx, q)
+	if err != nil {
+		return Comparison{}, err
+	}
+	previous, err := svc.repo.Summary(ctx, prev)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return Comparison{
+		Current:       current,
+		Previous:      previous,
+		PreviousFrom:  prev.From,
+		PreviousTo:    prev.To,
+		OrdersChange:  change(current.Orders, previous.Orders),
+		RevenueChange: change(current.Revenue, previous.Revenue),
+		TicketChange:  change(current.AverageTicket, previous.AverageTicket),
+	}, nil
+}
+
+func (svc analyticsService) Rebuild(ctx context.Context, token, vendor string) (uint64, error) {
+	r := newRollup()
+	pm := orders.PageMetadata{Vendor: vendor, Limit: pageSize}
+	var count uint64
+	for {
+		page, err := svc.orders.ListOrders(ctx, token, pm)
+		if err != nil {
+			return 0, err
+		}
+		for _, order := range page.Orders {
+			r.add(order, 1)
+		}
+		count += uint64(len(page.Orders))
+		pm.Offset += pageSize
+		if len(page.Orders) < pageSize {
+			break
+		}
+	}
+	if err := svc.repo.Replace(ctx, vendor, r.rollup()); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// query fills in the query's defaults, the last 7 days by day in the
+// configured time zone.
+func (svc analyticsService) query(q Query) Query {
+	if q.Location == nil {
+		q.Location = svc.config.Location
+	}
+	if q.Interval == "" {
+		q.Interval = Day
+	}
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.AddDate(0, 0, -7)
+	}
+	return q
+}
+
+// change returns the change from previous to current in percent, rounded
+// to one decimal.
+func change(current, previous uint64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	pct := (float64(current) - float64(previous)) / float64(previous) * 100
+	return math.Round(pct*10) / 10
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func executeEndpoint(svc batch.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(batchReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		results, err := svc.Execute(ctx, req.token, req.batch)
+		if err != nil {
+			return nil, err
+		}
+		return newBatchRes(results), nil
+	}
+}
+
+func createImportEndpoint(svc batch.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		imp, err := svc.CreateImport(ctx, req.token, req.vendor, req.file, req.dryRun)
+		if err != nil {
+			return nil, err
+		}
+		return importRes{Import: imp, created: true}, nil
+	}
+}
+
+func viewImportEndpoint(svc batch.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewImportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		imp, err := svc.ViewImport(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return importRes{Import: imp}, nil
+	}
+}
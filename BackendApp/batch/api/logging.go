@@ -0,0 +1,82 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/go-kit/log"
+)
+
+var _ batch.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    batch.Service
+}
+
+// LoggingMiddleware adds logging facilities to the batch service.
+func LoggingMiddleware(svc batch.Service, logger log.Logger) batch.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Execute(ctx context.Context, token string, b batch.Batch) (results []batch.Result, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "execute_batch",
+			"token", token,
+			"atomic", b.Atomic,
+			"operations", len(b.Operations),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Execute(ctx, token, b)
+}
+
+func (lm *loggingMiddleware) CreateImport(ctx context.Context, token, vendor string, file io.Reader, dryRun bool) (imp batch.Import, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_import",
+			"token", token,
+			"vendor", vendor,
+			"dry_run", dryRun,
+			"id", imp.ID,
+			"rows", imp.Rows,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateImport(ctx, token, vendor, file, dryRun)
+}
+
+func (lm *loggingMiddleware) ViewImport(ctx context.Context, token, id string) (imp batch.Import, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_import",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewImport(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Run(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "run_imports",
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Run(ctx, token)
+}
@@ -0,0 +1,66 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ batch.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     batch.Service
+}
+
+// MetricsMiddleware instruments the batch service by tracking request count
+// and latency.
+func MetricsMiddleware(svc batch.Service, counter metrics.Counter, latency metrics.Histogram) batch.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Execute(ctx context.Context, token string, b batch.Batch) ([]batch.Result, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "execute_batch").Add(1)
+		ms.latency.With("method", "execute_batch").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Execute(ctx, token, b)
+}
+
+func (ms *metricsMiddleware) CreateImport(ctx context.Context, token, vendor string, file io.Reader, dryRun bool) (batch.Import, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_import").Add(1)
+		ms.latency.With("method", "create_import").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateImport(ctx, token, vendor, file, dryRun)
+}
+
+func (ms *metricsMiddleware) ViewImport(ctx context.Context, token, id string) (batch.Import, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_import").Add(1)
+		ms.latency.With("method", "view_import").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewImport(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Run(ctx context.Context, token string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "run_imports").Add(1)
+		ms.latency.With("method", "run_imports").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Run(ctx, token)
+}
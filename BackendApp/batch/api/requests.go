@@ -0,0 +1,55 @@
+package api
+
+import (
+	"io"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+type batchReq struct {
+	token string
+	batch batch.Batch
+}
+
+func (req batchReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if len(req.batch.Operations) == 0 || len(req.batch.Operations) > batch.MaxOperations {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type importReq struct {
+	token  string
+	vendor string
+	dryRun bool
+	file   io.Reader
+}
+
+func (req importReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type viewImportReq struct {
+	token string
+	id    string
+}
+
+func (req viewImportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
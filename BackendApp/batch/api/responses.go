@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*batchRes)(nil)
+	_ Response = (*importRes)(nil)
+)
+
+type resultRes struct {
+	batch.Result
+	Error string `json:"error,omitempty"`
+}
+
+// batchRes lists how each operation went. Batches that ran answer 200 OK
+// even when operations failed, the results say which.
+type batchRes struct {
+	Results []resultRes `json:"results"`
+	Failed  int         `json:"failed"`
+}
+
+func newBatchRes(results []batch.Result) batchRes {
+	res := batchRes{Results: make([]resultRes, len(results))}
+	for i, r := range results {
+		res.Results[i] = resultRes{Result: r}
+		if r.Failed() {
+			res.Results[i].Error = r.Error.Error()
+			res.Failed++
+		}
+	}
+	return res
+}
+
+func (res batchRes) Code() int {
+	return http.StatusOK
+}
+
+func (res batchRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res batchRes) Empty() bool {
+	return false
+}
+
+type importRes struct {
+	batch.Import
+	created bool
+}
+
+func (res importRes) Code() int {
+	if res.created {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func (res importRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/orders/import/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res importRes) Empty() bool {
+	return false
+}
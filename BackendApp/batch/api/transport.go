@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType    = "application/json"
+	csvContentType = "text/csv"
+	vendorKey      = "vendor"
+	dryRunKey      = "dry_run"
+)
+
+// MakeBatchHandler returns a HTTP handler for batch API endpoints. It has
+// to be registered before the orders handler, whose /orders/{id} would
+// otherwise take /orders/batch.
+func MakeBatchHandler(svc batch.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/orders/batch").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint execute_batch")(executeEndpoint(svc)),
+		decodeBatch,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/orders/import").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_import")(createImportEndpoint(svc)),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/orders/import/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_import")(viewImportEndpoint(svc)),
+		decodeViewImport,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeBatch(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := batchReq{token: decodeToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req.batch); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	// Discounts and taxes of created orders come from their services, not
+	// from the client.
+	for i := range req.batch.Operations {
+		req.batch.Operations[i].Order.Adjustments = nil
+		req.batch.Operations[i].Order.Taxes = nil
+	}
+	return req, nil
+}
+
+// decodeImport takes the CSV file as the request body.
+func decodeImport(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), csvContentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	q := r.URL.Query()
+	req := importReq{
+		token:  decodeToken(r),
+		vendor: q.Get(vendorKey),
+		file:   r.Body,
+	}
+	if q.Has(dryRunKey) {
+		var err error
+		if req.dryRun, err = strconv.ParseBool(q.Get(dryRunKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeViewImport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewImportReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package batch takes many orders at once. Batches mix creating, updating
+// and deleting orders through the orders service, imports bring orders in
+// from a CSV file into the orders repository, as they were taken on another
+// POS or offline.
+package batch
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// MaxOperations is how many operations a batch has at most.
+const MaxOperations = 500
+
+var (
+	// ErrRolledBack indicates an operation of an all or nothing batch that
+	// was undone because another operation failed.
+	ErrRolledBack = errors.New("operation rolled back")
+
+	// ErrInterrupted indicates an import that was still running when the
+	// service stopped.
+	ErrInterrupted = errors.New("import was interrupted")
+)
+
+// Op is what an operation does to an order.
+type Op string
+
+// Batch operations.
+const (
+	Create Op = "create"
+	Update Op = "update"
+	Delete Op = "delete"
+)
+
+// Operation is a single change of a batch.
+type Operation struct {
+	Op    Op           `json:"op"`
+	ID    string       `json:"id,omitempty"` // The order updated or deleted.
+	Order orders.Order `json:"order,omitempty"`
+}
+
+// Validate returns an error if the operation representation is invalid.
+func (op Operation) Validate() error {
+	switch op.Op {
+	case Create:
+		return op.Order.Validate()
+	case Update, Delete:
+		if op.ID == "" {
+			return errors.ErrMissingID
+		}
+		return nil
+	default:
+		return errors.ErrMalformedEntity
+	}
+}
+
+// Batch is a list of operations applied in order. All or nothing batches
+// are applied in a single transaction and stop at the first operation that
+// fails, rolling back the ones applied before it. Other batches carry on.
+type Batch struct {
+	Atomic     bool        `json:"atomic,omitempty"`
+	Operations []Operation `json:"operations"`
+}
+
+// Result is how an operation of a batch went.
+type Result struct {
+	Index int    `json:"index"`
+	Op    Op     `json:"op"`
+	ID    string `json:"id,omitempty"`
+	Error error  `json:"-"`
+}
+
+// Failed reports whether the operation failed or was undone.
+func (r Result) Failed() bool {
+	return r.Error != nil
+}
+
+// ImportStatus is the lifecycle state of an import.
+type ImportStatus string
+
+// Import statuses.
+const (
+	Running ImportStatus = "running"
+	Done    ImportStatus = "done"
+	Failed  ImportStatus = "failed"
+)
+
+// RowError is why a row of an import file was rejected. Rows are numbered
+// as lines of the file, the header being line 1.
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// Import is an import of orders from a CSV file. Imports are all or
+// nothing, a file with a rejected row imports no orders. Dry runs only
+// check the rows. Orders are saved as they were taken, then paid and sent
+// to the kitchen through the orders service.
+type Import struct {
+	ID          string       `json:"id"`
+	Vendor      string       `json:"vendor"`
+	Status      ImportStatus `json:"status"`
+	DryRun      bool         `json:"dry_run"`
+	Rows        uint64       `json:"rows"`     // The rows read from the file.
+	Imported    uint64       `json:"imported"` // The orders saved.
+	Errors      []RowError   `json:"errors,omitempty"`
+	Error       string       `json:"error,omitempty"` // Why saving the orders failed, if it did.
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt time.Time    `json:"completed_at,omitempty"`
+}
+
+// Service specifies the batch API.
+type Service interface {
+	// Execute applies the operations of the batch and returns how each
+	// went. The error is only set when the batch could not be run at all.
+	Execute(ctx context.Context, token string, batch Batch) ([]Result, error)
+
+	// CreateImport reads the orders of a CSV file for the vendor and saves
+	// them in the background, unless it is a dry run or a row is rejected.
+	CreateImport(ctx context.Context, token, vendor string, file io.Reader, dryRun bool) (Import, error)
+
+	// ViewImport retrieves an import by its unique identifier ID.
+	ViewImport(ctx context.Context, token, id string) (Import, error)
+
+	// Run runs the imports created until ctx is done. It first fails the
+	// imports left unfinished when the service last stopped. Imports still
+	// running when ctx is done are cancelled and fail, Run returns once
+	// they have, with the errors recording how imports went.
+	Run(ctx context.Context, token string) error
+}
+
+// ImportRepository specifies an import persistence API.
+type ImportRepository interface {
+	// Save persists an import.
+	Save(ctx context.Context, imp Import) error
+
+	// RetrieveByID retrieves an import by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Import, error)
+
+	// Update updates the status, counts, errors and completion of an
+	// import.
+	Update(ctx context.Context, imp Import) error
+
+	// FailUnfinished fails the imports created before at that are still
+	// running, with reason as their error. It returns the number of imports
+	// failed.
+	FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error)
+}
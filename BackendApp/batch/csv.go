@@ -0,0 +1,165 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+const metadataPrefix = "metadata."
+
+// columns are the columns an import file can have besides metadata ones,
+// i.e. metadata.table. Items are a JSON list of item lines.
+var columns = map[string]bool{
+	"vendor":     true,
+	"name":       true,
+	"price":      true,
+	"place":      true,
+	"status":     true,
+	"items":      true,
+	"paid":       true,
+	"tips":       true,
+	"created_by": true,
+	"created_at": true, // RFC3339, the time of the import when empty.
+}
+
+// readOrders reads the orders of a CSV file for the vendor. Rows that are
+// not valid orders are rejected with the reason.
+func readOrders(vendor string, file io.Reader) ([]orders.Order, []RowError, error) {
+	r := csv.NewReader(file)
+	r.ReuseRecord = true
+	r.FieldsPerRecord = 0
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	header = append([]string(nil), header...)
+	for i, h := range header {
+		header[i] = strings.TrimSpace(h)
+		if !columns[header[i]] && !strings.HasPrefix(header[i], metadataPrefix) {
+			return nil, nil, errors.Wrap(errors.ErrMalformedEntity, fmt.Errorf("unknown column %q", h))
+		}
+	}
+
+	var read []orders.Order
+	var rejected []RowError
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, nil, errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+			rejected = append(rejected, RowError{Row: line, Error: err.Error()})
+			continue
+		}
+		order, err := readOrder(vendor, header, record)
+		if err != nil {
+			rejected = append(rejected, RowError{Row: line, Error: err.Error()})
+			continue
+		}
+		read = append(read, order)
+	}
+	return read, rejected, nil
+}
+
+// readOrder reads a row of an import file as an order of the vendor.
+func readOrder(vendor string, header, record []string) (orders.Order, error) {
+	now := time.Now()
+	order := orders.Order{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		CreatedAt: now,
+	}
+	paid := false
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		var err error
+		switch col := header[i]; col {
+		case "vendor":
+			if value != vendor {
+				return orders.Order{}, errors.Wrap(errors.ErrMalformedEntity, fmt.Errorf("order of vendor %q", value))
+			}
+		case "name":
+			order.Name = value
+		case "price":
+			order.Price, err = strconv.ParseUint(value, 10, 64)
+		case "place":
+			order.Place = value
+		case "status":
+			order.Status = value
+		case "items":
+			err = json.Unmarshal([]byte(value), &order.Items)
+		case "paid":
+			order.Paid, err = strconv.ParseUint(value, 10, 64)
+			paid = true
+		case "tips":
+			order.Tips, err = strconv.ParseUint(value, 10, 64)
+		case "created_by":
+			order.CreatedBy = value
+		case "created_at":
+			// Orders keep the wall clock of the server that took them.
+			order.CreatedAt, err = time.Parse(time.RFC3339, value)
+			order.CreatedAt = order.CreatedAt.In(time.Local)
+		default:
+			if order.Metadata == nil {
+				order.Metadata = orders.Metadata{}
+			}
+			setMetadata(order.Metadata, strings.Split(strings.TrimPrefix(col, metadataPrefix), "."), value)
+		}
+		if err != nil {
+			return orders.Order{}, errors.Wrap(errors.ErrMalformedEntity, fmt.Errorf("%s: %w", header[i], err))
+		}
+	}
+	if err := order.Validate(); err != nil {
+		return orders.Order{}, err
+	}
+	if len(order.Items) > 0 {
+		if order.Price == 0 {
+			order.Price = order.ItemsTotal()
+		}
+		if order.Name == "" {
+			order.Name = order.ItemsName()
+		}
+	}
+	// Orders imported as paid were paid in full unless the file says what
+	// was paid.
+	if order.Status == orders.StatusPaid && !paid {
+		order.Paid = order.Price
+	}
+	// Orders are settled by what was paid.
+	if order.Status == orders.StatusPaid && order.Balance() > 0 {
+		return orders.Order{}, orders.ErrOutstanding
+	}
+	order.UpdatedAt = order.CreatedAt
+	if order.CreatedAt.After(now) {
+		return orders.Order{}, errors.Wrap(errors.ErrMalformedEntity, fmt.Errorf("created_at in the future"))
+	}
+	return order, nil
+}
+
+// setMetadata sets the value at the path of keys, i.e. customer.phone.
+func setMetadata(metadata map[string]interface{}, path []string, value string) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := metadata[key].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			metadata[key] = nested
+		}
+		metadata = nested
+	}
+	metadata[path[len(path)-1]] = value
+}
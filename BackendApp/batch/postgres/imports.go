@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/jmoiron/sqlx"
+)
+
+const importColumns = `id, vendor, status, dry_run, rows, imported, errors, error, created_at, completed_at`
+
+var _ batch.ImportRepository = (*importsRepo)(nil)
+
+type importsRepo struct {
+	db *sqlx.DB
+}
+
+// NewImportsRepo instantiates a PostgreSQL
+// implementation of imports repository.
+func NewImportsRepo(db *sqlx.DB) batch.ImportRepository {
+	return &importsRepo{
+		db: db,
+	}
+}
+
+func (repo importsRepo) Save(ctx context.Context, imp batch.Import) error {
+	q := `INSERT INTO import_jobs (` + importColumns + `)
+		  VALUES (:id, :vendor, :status, :dry_run, :rows, :imported, :errors, :error, :created_at, :completed_at)`
+
+	dbi, err := toDBImport(imp)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, dbi); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo importsRepo) RetrieveByID(ctx context.Context, id string) (batch.Import, error) {
+	q := `SELECT ` + importColumns + ` FROM import_jobs WHERE id = $1`
+
+	dbi := dbImport{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbi); err != nil {
+		if err == sql.ErrNoRows {
+			return batch.Import{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return batch.Import{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toImport(dbi)
+}
+
+func (repo importsRepo) Update(ctx context.Context, imp batch.Import) error {
+	q := `UPDATE import_jobs SET status = :status, imported = :imported, errors = :errors, error = :error,
+		  completed_at = :completed_at WHERE id = :id`
+
+	dbi, err := toDBImport(imp)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	res, err := sqlx.NamedExecContext(ctx, pgutil.DB(ctx, repo.db), q, dbi)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (repo importsRepo) FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error) {
+	q := `UPDATE import_jobs SET status = $1, error = $2, completed_at = $3
+		  WHERE status = $4 AND created_at < $3`
+
+	res, err := repo.db.ExecContext(ctx, q, batch.Failed, reason, at, batch.Running)
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return uint64(cnt), nil
+}
+
+type dbImport struct {
+	ID          string       `db:"id"`
+	Vendor      string       `db:"vendor"`
+	Status      string       `db:"status"`
+	DryRun      bool         `db:"dry_run"`
+	Rows        uint64       `db:"rows"`
+	Imported    uint64       `db:"imported"`
+	Errors      []byte       `db:"errors"`
+	Error       string       `db:"error"`
+	CreatedAt   time.Time    `db:"created_at"`
+	CompletedAt sql.NullTime `db:"completed_at"`
+}
+
+func toDBImport(imp batch.Import) (dbImport, error) {
+	rowErrors := imp.Errors
+	if rowErrors == nil {
+		rowErrors = []batch.RowError{}
+	}
+	data, err := json.Marshal(rowErrors)
+	if err != nil {
+		return dbImport{}, err
+	}
+	return dbImport{
+		ID:          imp.ID,
+		Vendor:      imp.Vendor,
+		Status:      string(imp.Status),
+		DryRun:      imp.DryRun,
+		Rows:        imp.Rows,
+		Imported:    imp.Imported,
+		Errors:      data,
+		Error:       imp.Error,
+		CreatedAt:   imp.CreatedAt,
+		CompletedAt: sql.NullTime{Time: imp.CompletedAt, Valid: !imp.CompletedAt.IsZero()},
+	}, nil
+}
+
+func toImport(dbi dbImport) (batch.Import, error) {
+	var rowErrors []batch.RowError
+	if err := json.Unmarshal(dbi.Errors, &rowErrors); err != nil {
+		return batch.Import{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	imp := batch.Import{
+		ID:        dbi.ID,
+		Vendor:    dbi.Vendor,
+		Status:    batch.ImportStatus(dbi.Status),
+		DryRun:    dbi.DryRun,
+		Rows:      dbi.Rows,
+		Imported:  dbi.Imported,
+		Errors:    rowErrors,
+		Error:     dbi.Error,
+		CreatedAt: dbi.CreatedAt,
+	}
+	if dbi.CompletedAt.Valid {
+		imp.CompletedAt = dbi.CompletedAt.Time
+	}
+	return imp, nil
+}
@@ -0,0 +1,301 @@
+package batch
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/multierr"
+)
+
+const (
+	// importMethod is the payment method of what imported orders were
+	// paid.
+	importMethod = "import"
+	// updateTimeout is how long recording how an import went is given,
+	// and an import cancelled by the service stopping to be marked failed.
+	updateTimeout = 5 * time.Second
+	// updateBackoff is how long the first retry of recording how an import
+	// went waits, the next ones wait longer each.
+	updateBackoff = 100 * time.Millisecond
+)
+
+var _ Service = (*batchService)(nil)
+
+type batchService struct {
+	orders  orders.OrderService
+	repo    orders.OrderRepository
+	imports ImportRepository
+	queue   chan queuedImport
+	started time.Time
+}
+
+// queuedImport is an import created and waiting for Run to run it.
+type queuedImport struct {
+	token  string
+	imp    Import
+	orders []orders.Order
+}
+
+// NewService instantiates the batch service implementation. Batches go
+// through the orders service, all or nothing ones in a transaction of the
+// orders repository. Imports are only run while Run is.
+func NewService(ordersSvc orders.OrderService, repo orders.OrderRepository, imports ImportRepository) Service {
+	return &batchService{
+		orders:  ordersSvc,
+		repo:    repo,
+		imports: imports,
+		queue:   make(chan queuedImport),
+		started: time.Now(),
+	}
+}
+
+func (svc batchService) Execute(ctx context.Context, token string, batch Batch) ([]Result, error) {
+	if len(batch.Operations) == 0 || len(batch.Operations) > MaxOperations {
+		return nil, errors.ErrMalformedEntity
+	}
+	if !batch.Atomic {
+		results := validate(batch.Operations)
+		for i, op := range batch.Operations {
+			if !results[i].Failed() {
+				results[i].ID, results[i].Error = svc.apply(ctx, token, op)
+			}
+		}
+		return results, nil
+	}
+
+	results := validate(batch.Operations)
+	for _, r := range results {
+		if r.Failed() {
+			return rollBack(results, r.Index), nil
+		}
+	}
+	// All or nothing batches run in a single transaction, nothing they
+	// wrote is seen until all of it is committed. A transaction run again
+	// starts over.
+	err := svc.repo.InTransaction(ctx, func(ctx context.Context) error {
+		results = validate(batch.Operations)
+		for i, op := range batch.Operations {
+			results[i].ID, results[i].Error = svc.apply(ctx, token, op)
+			if results[i].Failed() {
+				err := results[i].Error
+				rollBack(results, i)
+				return err
+			}
+		}
+		return nil
+	})
+	for _, r := range results {
+		if r.Failed() {
+			return results, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// validate returns the results of the operations with the ones that are
+// not valid failed.
+func validate(ops []Operation) []Result {
+	results := make([]Result, len(ops))
+	for i, op := range ops {
+		results[i] = Result{Index: i, Op: op.Op, ID: op.ID}
+		results[i].Error = op.Validate()
+	}
+	return results
+}
+
+// apply applies an operation and returns the order it applied to.
+func (svc batchService) apply(ctx context.Context, token string, op Operation) (string, error) {
+	switch op.Op {
+	case Create:
+		return svc.orders.CreateOrder(ctx, token, op.Order)
+	case Update:
+		op.Order.ID = op.ID
+		if _, err := svc.orders.UpdateOrder(ctx, token, op.Order); err != nil {
+			return op.ID, err
+		}
+		return op.ID, nil
+	default:
+		// Deleting an order that is not there fails the operation.
+		if _, err := svc.orders.ViewOrder(ctx, token, op.ID); err != nil {
+			return op.ID, err
+		}
+		return op.ID, svc.orders.DeleteOrder(ctx, token, op.ID)
+	}
+}
+
+// rollBack marks the operations of an all or nothing batch that did not
+// fail at index as rolled back.
+func rollBack(results []Result, index int) []Result {
+	for i := range results {
+		if i != index {
+			results[i].Error = ErrRolledBack
+		}
+	}
+	return results
+}
+
+func (svc batchService) CreateImport(ctx context.Context, token, vendor string, file io.Reader, dryRun bool) (Import, error) {
+	if vendor == "" {
+		return Import{}, errors.ErrMalformedEntity
+	}
+	read, rejected, err := readOrders(vendor, file)
+	if err != nil {
+		return Import{}, err
+	}
+	imp := Import{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		Status:    Running,
+		DryRun:    dryRun,
+		Rows:      uint64(len(read) + len(rejected)),
+		Errors:    rejected,
+		CreatedAt: time.Now(),
+	}
+	if dryRun || len(rejected) > 0 {
+		imp.Status = Done
+		if len(rejected) > 0 {
+			imp.Status = Failed
+		}
+		imp.CompletedAt = imp.CreatedAt
+	}
+	if err := svc.imports.Save(ctx, imp); err != nil {
+		return Import{}, err
+	}
+	if imp.Status == Running {
+		// The import outlives the request that created it, Run runs it.
+		select {
+		case svc.queue <- queuedImport{token: token, imp: imp, orders: read}:
+		case <-ctx.Done():
+			return Import{}, ctx.Err()
+		}
+	}
+	return imp, nil
+}
+
+func (svc batchService) ViewImport(ctx context.Context, token, id string) (Import, error) {
+	return svc.imports.RetrieveByID(ctx, id)
+}
+
+func (svc batchService) Run(ctx context.Context, token string) error {
+	// Imports are only run while the service is, the imports left
+	// unfinished when it last stopped never will be. The ones created since
+	// it started are waiting for Run.
+	if _, err := svc.imports.FailUnfinished(ctx, svc.started, ErrInterrupted.Error()); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs error
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return errs
+		case q := <-svc.queue:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := svc.run(ctx, q.token, q.imp, q.orders); err != nil {
+					mu.Lock()
+					errs = multierr.Append(errs, err)
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+}
+
+// run saves the orders of an import and records how it went. An import
+// cancelled by the service stopping is recorded as interrupted. It returns
+// the error recording how the import went, if it could not be.
+func (svc batchService) run(ctx context.Context, token string, imp Import, read []orders.Order) error {
+	done := imp
+	done.Status = Done
+	done.Imported = uint64(len(read))
+	done.CompletedAt = time.Now()
+	// The import is recorded done in the transaction saving its orders,
+	// when they are kept in the same database.
+	err := svc.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.save(ctx, token, read); err != nil {
+			return err
+		}
+		return svc.imports.Update(ctx, done)
+	})
+	if err == nil {
+		return nil
+	}
+
+	imp.Status = Failed
+	imp.Error = err.Error()
+	imp.CompletedAt = time.Now()
+	if ctx.Err() != nil {
+		imp.Error = ErrInterrupted.Error()
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+	for attempt := 1; ; attempt++ {
+		err := svc.imports.Update(ctx, imp)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * updateBackoff):
+		}
+	}
+}
+
+// save saves the orders of an import as they were ordered, then moves them
+// on through the orders service, so the hooks follow them and their
+// payments are recorded as any other.
+func (svc batchService) save(ctx context.Context, token string, read []orders.Order) error {
+	ordered := make([]orders.Order, len(read))
+	for i, order := range read {
+		order.Status = orders.StatusOrdered
+		order.Paid, order.Tips = 0, 0
+		ordered[i] = order
+	}
+	if _, err := svc.repo.SaveMany(ctx, ordered); err != nil {
+		return err
+	}
+	for _, order := range read {
+		if err := svc.advance(ctx, token, order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// advance moves a saved order on to how it was imported. What was paid is
+// recorded as a single payment, taken by the staff member who took the
+// order.
+func (svc batchService) advance(ctx context.Context, token string, order orders.Order) error {
+	ctx = orders.WithStaff(ctx, order.CreatedBy)
+	if order.Status == orders.StatusPreparing {
+		if _, err := svc.orders.UpdateOrder(ctx, token, orders.Order{ID: order.ID, Status: order.Status}); err != nil {
+			return err
+		}
+	}
+	if order.Paid > 0 || order.Tips > 0 {
+		ctx := orders.WithPaymentKey(ctx, importMethod+":"+order.ID)
+		_, err := svc.orders.RecordPayment(ctx, token, order.ID, importMethod, order.Paid, order.Tips)
+		return err
+	}
+	// Orders paid without a payment cost nothing.
+	if order.Status == orders.StatusPaid {
+		if _, err := svc.orders.UpdateOrder(ctx, token, orders.Order{ID: order.ID, Status: order.Status}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
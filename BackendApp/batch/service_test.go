@@ -0,0 +1,296 @@
+package batch_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+func newOrder() orders.Order {
+	return orders.Order{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		Name:      "Chapati",
+		Price:     100,
+		Place:     "inhouse",
+		Status:    orders.StatusOrdered,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestExecute(t *testing.T) {
+	cases := []struct {
+		desc        string
+		atomic      bool
+		name        string // The name the updated order is left with.
+		transitions int
+	}{
+		{desc: "all or nothing batch", atomic: true, name: "Chapati", transitions: 0},
+		{desc: "batch", atomic: false, name: "Mandazi", transitions: 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			repo := memory.NewOrderRepo()
+			svc := batch.NewService(orders.NewOrderService(repo), repo, nil)
+
+			updated, deleted := newOrder(), newOrder()
+			for _, order := range []orders.Order{updated, deleted} {
+				if _, err := repo.Save(ctx, order); err != nil {
+					t.Fatalf("saving: %s", err)
+				}
+			}
+			created := newOrder()
+			created.ID, created.Status = "", orders.StatusPreparing
+			results, err := svc.Execute(ctx, token, batch.Batch{
+				Atomic: tc.atomic,
+				Operations: []batch.Operation{
+					{Op: batch.Create, Order: created},
+					{Op: batch.Update, ID: updated.ID, Order: orders.Order{Name: "Mandazi", Status: orders.StatusPreparing}},
+					{Op: batch.Delete, ID: deleted.ID},
+					{Op: batch.Update, ID: ulid.Make().String(), Order: orders.Order{Name: "Mandazi"}},
+				},
+			})
+			if err != nil {
+				t.Fatalf("executing: %s", err)
+			}
+			if len(results) != 4 {
+				t.Fatalf("got %d results, want 4", len(results))
+			}
+			if !errors.Contains(results[3].Error, errors.ErrNotFound) {
+				t.Errorf("updating a missing order returned %v, want %s", results[3].Error, errors.ErrNotFound)
+			}
+			for _, r := range results[:3] {
+				if tc.atomic && r.Error != batch.ErrRolledBack {
+					t.Errorf("operation %d returned %v, want %s", r.Index, r.Error, batch.ErrRolledBack)
+				}
+				if !tc.atomic && r.Failed() {
+					t.Errorf("operation %d failed: %s", r.Index, r.Error)
+				}
+			}
+
+			page, err := repo.RetrieveAll(ctx, orders.PageMetadata{Vendor: vendor, Limit: 10})
+			if err != nil {
+				t.Fatalf("listing: %s", err)
+			}
+			// Either the order was created and the other deleted, or neither.
+			if len(page.Orders) != 2 {
+				t.Errorf("listed %d orders, want 2", len(page.Orders))
+			}
+			got, err := repo.RetrieveByID(ctx, updated.ID)
+			if err != nil {
+				t.Fatalf("retrieving: %s", err)
+			}
+			if got.Name != tc.name {
+				t.Errorf("updated order is named %q, want %q", got.Name, tc.name)
+			}
+			if _, err := repo.RetrieveTombstone(ctx, deleted.ID); tc.atomic != errors.Contains(err, errors.ErrNotFound) {
+				t.Errorf("retrieving the tombstone of the deleted order returned %v", err)
+			}
+			// Rolled back orders never reach the hooks.
+			transitions, err := repo.RetrieveTransitions(ctx, 0, 10)
+			if err != nil {
+				t.Fatalf("retrieving transitions: %s", err)
+			}
+			if len(transitions) != tc.transitions {
+				t.Errorf("got %d transitions, want %d", len(transitions), tc.transitions)
+			}
+		})
+	}
+}
+
+// importRepo keeps imports in memory, failing the updates recording an
+// import done while failDone is set.
+type importRepo struct {
+	batch.ImportRepository
+	mu       sync.Mutex
+	imports  map[string]batch.Import
+	failDone bool
+}
+
+func (repo *importRepo) Save(ctx context.Context, imp batch.Import) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.imports[imp.ID] = imp
+	return nil
+}
+
+func (repo *importRepo) RetrieveByID(ctx context.Context, id string) (batch.Import, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	imp, ok := repo.imports[id]
+	if !ok {
+		return batch.Import{}, errors.ErrNotFound
+	}
+	return imp, nil
+}
+
+func (repo *importRepo) Update(ctx context.Context, imp batch.Import) error {
+	repo.mu.Lock()
+	fail := repo.failDone && imp.Status == batch.Done
+	repo.mu.Unlock()
+	if fail {
+		return errors.ErrUpdateEntity
+	}
+	return repo.Save(ctx, imp)
+}
+
+func (repo *importRepo) FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var n uint64
+	for id, imp := range repo.imports {
+		if imp.Status == batch.Running && imp.CreatedAt.Before(at) {
+			imp.Status, imp.Error, imp.CompletedAt = batch.Failed, reason, at
+			repo.imports[id] = imp
+			n++
+		}
+	}
+	return n, nil
+}
+
+// completed waits for the import to complete.
+func completed(t *testing.T, svc batch.Service, id string) batch.Import {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		imp, err := svc.ViewImport(context.Background(), token, id)
+		if err != nil {
+			t.Fatalf("viewing import: %s", err)
+		}
+		if imp.Status != batch.Running {
+			return imp
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("import %s still running", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunImports(t *testing.T) {
+	const file = `name,price,place,status,paid,tips,created_by
+Chapati,100,inhouse,paid,,10,wanjiku
+Chai,50,inhouse,preparing,20,,otieno
+Maji,0,delivery,paid,,,wanjiku
+`
+	repo := memory.NewOrderRepo()
+	stale := batch.Import{ID: ulid.Make().String(), Vendor: vendor, Status: batch.Running, CreatedAt: time.Now().Add(-time.Hour)}
+	imports := &importRepo{imports: map[string]batch.Import{stale.ID: stale}}
+	svc := batch.NewService(orders.NewOrderService(repo), repo, imports)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- svc.Run(ctx, token)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("running imports: %s", err)
+		}
+	}()
+
+	// A file the import fails to record leaves no orders.
+	imports.mu.Lock()
+	imports.failDone = true
+	imports.mu.Unlock()
+	imp, err := svc.CreateImport(context.Background(), token, vendor, strings.NewReader(file), false)
+	if err != nil {
+		t.Fatalf("creating import: %s", err)
+	}
+	// Unfinished imports fail once Run runs, before it takes new ones.
+	if got, _ := svc.ViewImport(ctx, token, stale.ID); got.Status != batch.Failed || got.Error != batch.ErrInterrupted.Error() {
+		t.Errorf("unfinished import is %s with %q, want %s with %q", got.Status, got.Error, batch.Failed, batch.ErrInterrupted)
+	}
+	if got := completed(t, svc, imp.ID); got.Status != batch.Failed || got.Imported != 0 {
+		t.Errorf("import is %s with %d imported, want %s with none", got.Status, got.Imported, batch.Failed)
+	}
+	page, err := repo.RetrieveAll(ctx, orders.PageMetadata{Vendor: vendor, Limit: 10})
+	if err != nil {
+		t.Fatalf("listing: %s", err)
+	}
+	if len(page.Orders) != 0 {
+		t.Errorf("failed import left %d orders", len(page.Orders))
+	}
+
+	imports.mu.Lock()
+	imports.failDone = false
+	imports.mu.Unlock()
+	imp, err = svc.CreateImport(context.Background(), token, vendor, strings.NewReader(file), false)
+	if err != nil {
+		t.Fatalf("creating import: %s", err)
+	}
+	if got := completed(t, svc, imp.ID); got.Status != batch.Done || got.Imported != 3 {
+		t.Errorf("import is %s with %d imported (%s), want %s with 3", got.Status, got.Imported, got.Error, batch.Done)
+	}
+	page, err = repo.RetrieveAll(ctx, orders.PageMetadata{Vendor: vendor, Limit: 10})
+	if err != nil {
+		t.Fatalf("listing: %s", err)
+	}
+	want := []orders.Order{
+		{Name: "Chapati", Status: orders.StatusPaid, Paid: 100, Tips: 10, PaidBy: "wanjiku"},
+		{Name: "Chai", Status: orders.StatusPreparing, Paid: 20},
+		{Name: "Maji", Status: orders.StatusPaid, PaidBy: "wanjiku"},
+	}
+	if len(page.Orders) != len(want) {
+		t.Fatalf("listed %d orders, want %d", len(page.Orders), len(want))
+	}
+	byName := map[string]orders.Order{}
+	for _, order := range page.Orders {
+		byName[order.Name] = order
+	}
+	var ids []string
+	for _, w := range want {
+		got := byName[w.Name]
+		if got.Status != w.Status || got.Paid != w.Paid || got.Tips != w.Tips || got.PaidBy != w.PaidBy {
+			t.Errorf("imported %s is %s with %d paid, %d tips by %q, want %s with %d paid, %d tips by %q",
+				w.Name, got.Status, got.Paid, got.Tips, got.PaidBy, w.Status, w.Paid, w.Tips, w.PaidBy)
+		}
+		ids = append(ids, got.ID)
+	}
+	// What was paid is recorded as payments, and the hooks follow the
+	// orders as they move on.
+	payments, err := repo.RetrievePayments(ctx, ids)
+	if err != nil {
+		t.Fatalf("retrieving payments: %s", err)
+	}
+	if len(payments) != 2 {
+		t.Errorf("got %d payments, want 2", len(payments))
+	}
+	for _, p := range payments {
+		if p.Method != "import" {
+			t.Errorf("payment of %d was taken by %q, want %q", p.Amount, p.Method, "import")
+		}
+	}
+	transitions, err := repo.RetrieveTransitions(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("retrieving transitions: %s", err)
+	}
+	if len(transitions) != 3 {
+		t.Errorf("got %d transitions, want 3", len(transitions))
+	}
+
+	// Orders marked paid with a balance are rejected.
+	imp, err = svc.CreateImport(ctx, token, vendor, strings.NewReader("name,price,place,status,paid\nChapati,100,inhouse,paid,40\n"), false)
+	if err != nil {
+		t.Fatalf("creating import: %s", err)
+	}
+	if imp.Status != batch.Failed || len(imp.Errors) != 1 || imp.Errors[0].Row != 2 {
+		t.Errorf("import of an order paid with a balance is %s with errors %+v, want %s on row 2", imp.Status, imp.Errors, batch.Failed)
+	}
+}
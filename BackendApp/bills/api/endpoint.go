@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func splitBillEndpoint(svc bills.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(splitBillReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		split, err := svc.SplitBill(ctx, req.token, req.request())
+		if err != nil {
+			return nil, err
+		}
+		return splitRes{Split: split, Balance: split.Balance(), created: true}, nil
+	}
+}
+
+func viewSplitEndpoint(svc bills.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		split, err := svc.ViewSplit(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return splitRes{Split: split, Balance: split.Balance()}, nil
+	}
+}
+
+func payShareEndpoint(svc bills.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(payShareReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		payment := bills.Payment{
+			Tip:       req.Tip,
+			Method:    req.Method,
+			Reference: req.Reference,
+		}
+		share, err := svc.PayShare(ctx, req.token, req.splitID, req.shareID, payment)
+		if err != nil {
+			return nil, err
+		}
+		return shareRes{Share: share}, nil
+	}
+}
+
+func cancelSplitEndpoint(svc bills.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.CancelSplit(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return cancelSplitRes{}, nil
+	}
+}
+
+func tipsReportEndpoint(svc bills.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(tipsReportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		query := bills.TipsQuery{
+			Vendor: req.vendor,
+			Staff:  req.staff,
+			From:   req.from,
+			To:     req.to,
+		}
+		report, err := svc.TipsReport(ctx, req.token, query)
+		if err != nil {
+			return nil, err
+		}
+		res := tipsReportRes{Staff: report}
+		for _, tips := range report {
+			res.Total += tips.Tips
+		}
+		return res, nil
+	}
+}
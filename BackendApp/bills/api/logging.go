@@ -0,0 +1,97 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/go-kit/log"
+)
+
+var _ bills.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    bills.Service
+}
+
+// LoggingMiddleware adds logging facilities to the bills service.
+func LoggingMiddleware(svc bills.Service, logger log.Logger) bills.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) SplitBill(ctx context.Context, token string, req bills.Request) (split bills.Split, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "split_bill",
+			"token", token,
+			"orders", len(req.OrderIDs),
+			"split_method", req.Method,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.SplitBill(ctx, token, req)
+}
+
+func (lm *loggingMiddleware) ViewSplit(ctx context.Context, token, id string) (split bills.Split, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_split",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewSplit(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) PayShare(ctx context.Context, token, splitID, shareID string, payment bills.Payment) (share bills.Share, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "pay_share",
+			"token", token,
+			"split_id", splitID,
+			"share_id", shareID,
+			"staff", payment.Tip.Staff,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.PayShare(ctx, token, splitID, shareID, payment)
+}
+
+func (lm *loggingMiddleware) CancelSplit(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "cancel_split",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CancelSplit(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) TipsReport(ctx context.Context, token string, query bills.TipsQuery) (report []bills.StaffTips, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "tips_report",
+			"token", token,
+			"vendor", query.Vendor,
+			"staff", query.Staff,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.TipsReport(ctx, token, query)
+}
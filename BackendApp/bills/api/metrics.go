@@ -0,0 +1,74 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ bills.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     bills.Service
+}
+
+// MetricsMiddleware instruments the bills service by tracking request count
+// and latency.
+func MetricsMiddleware(svc bills.Service, counter metrics.Counter, latency metrics.Histogram) bills.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) SplitBill(ctx context.Context, token string, req bills.Request) (bills.Split, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "split_bill").Add(1)
+		ms.latency.With("method", "split_bill").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SplitBill(ctx, token, req)
+}
+
+func (ms *metricsMiddleware) ViewSplit(ctx context.Context, token, id string) (bills.Split, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_split").Add(1)
+		ms.latency.With("method", "view_split").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSplit(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) PayShare(ctx context.Context, token, splitID, shareID string, payment bills.Payment) (bills.Share, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "pay_share").Add(1)
+		ms.latency.With("method", "pay_share").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PayShare(ctx, token, splitID, shareID, payment)
+}
+
+func (ms *metricsMiddleware) CancelSplit(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "cancel_split").Add(1)
+		ms.latency.With("method", "cancel_split").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CancelSplit(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) TipsReport(ctx context.Context, token string, query bills.TipsQuery) ([]bills.StaffTips, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "tips_report").Add(1)
+		ms.latency.With("method", "tips_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TipsReport(ctx, token, query)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+type splitBillReq struct {
+	token    string
+	OrderIDs []string       `json:"order_ids"`
+	Method   bills.Method   `json:"method"`
+	Count    uint64         `json:"count,omitempty"`
+	Amounts  []uint64       `json:"amounts,omitempty"`
+	Lines    [][]bills.Line `json:"lines,omitempty"`
+	Labels   []string       `json:"labels,omitempty"`
+}
+
+func (req splitBillReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.request().Validate()
+}
+
+func (req splitBillReq) request() bills.Request {
+	return bills.Request{
+		OrderIDs: req.OrderIDs,
+		Method:   req.Method,
+		Count:    req.Count,
+		Amounts:  req.Amounts,
+		Lines:    req.Lines,
+		Labels:   req.Labels,
+	}
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type payShareReq struct {
+	token     string
+	splitID   string
+	shareID   string
+	Tip       bills.Tip `json:"tip"`
+	Method    string    `json:"method,omitempty"`
+	Reference string    `json:"reference,omitempty"`
+}
+
+func (req payShareReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.splitID == "" || req.shareID == "" {
+		return errors.ErrMissingID
+	}
+	if req.Tip.Amount > 0 && req.Tip.Percent > 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type tipsReportReq struct {
+	token  string
+	vendor string
+	staff  string
+	from   time.Time
+	to     time.Time
+}
+
+func (req tipsReportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	if !req.from.IsZero() && !req.to.IsZero() && !req.from.Before(req.to) {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*splitRes)(nil)
+	_ Response = (*shareRes)(nil)
+	_ Response = (*cancelSplitRes)(nil)
+	_ Response = (*tipsReportRes)(nil)
+)
+
+type splitRes struct {
+	bills.Split
+	Balance uint64 `json:"balance"`
+	created bool
+}
+
+func (res splitRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res splitRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/splits/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res splitRes) Empty() bool {
+	return false
+}
+
+type shareRes struct {
+	bills.Share
+}
+
+func (res shareRes) Code() int {
+	return http.StatusOK
+}
+
+func (res shareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return false
+}
+
+type cancelSplitRes struct{}
+
+func (res cancelSplitRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res cancelSplitRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res cancelSplitRes) Empty() bool {
+	return true
+}
+
+type tipsReportRes struct {
+	Staff []bills.StaffTips `json:"staff"`
+	Total uint64            `json:"total"`
+}
+
+func (res tipsReportRes) Code() int {
+	return http.StatusOK
+}
+
+func (res tipsReportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res tipsReportRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	vendorKey   = "vendor"
+	staffKey    = "staff"
+	fromKey     = "from"
+	toKey       = "to"
+)
+
+// MakeBillsHandler returns a HTTP handler for bill splitting and tips API
+// endpoints.
+func MakeBillsHandler(svc bills.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/splits").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint split_bill")(splitBillEndpoint(svc)),
+		decodeSplitBill,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/splits/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_split")(viewSplitEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/splits/{id}/shares/{share_id}/pay").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint pay_share")(payShareEndpoint(svc)),
+		decodePayShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/splits/{id}/cancel").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint cancel_split")(cancelSplitEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tips").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint tips_report")(tipsReportEndpoint(svc)),
+		decodeTipsReport,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeSplitBill(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := splitBillReq{token: decodeToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+// decodePayShare accepts an empty body for payments without a tip.
+func decodePayShare(_ context.Context, r *http.Request) (interface{}, error) {
+	req := payShareReq{
+		token:   decodeToken(r),
+		splitID: mux.Vars(r)["id"],
+		shareID: mux.Vars(r)["share_id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeTipsReport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := tipsReportReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+		staff:  r.URL.Query().Get(staffKey),
+	}
+	var err error
+	if r.URL.Query().Has(fromKey) {
+		req.from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		req.to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, bills.ErrSplitClosed),
+		errors.Contains(err, bills.ErrSharePaid),
+		errors.Contains(err, bills.ErrNothingOutstanding):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, bills.ErrSharesMismatch):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package bills lets a group share the bill of one or more orders. A split
+// divides the outstanding balance into shares that are paid independently,
+// each optionally with a tip for the staff who served the group.
+package bills
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+var (
+	// ErrNothingOutstanding indicates a split of orders that are already paid.
+	ErrNothingOutstanding = errors.New("orders have no outstanding balance")
+
+	// ErrSharesMismatch indicates custom shares or item assignments that do
+	// not cover the outstanding balance exactly.
+	ErrSharesMismatch = errors.New("shares do not add up to the outstanding balance")
+
+	// ErrSplitClosed indicates a change to a split that was settled or
+	// cancelled.
+	ErrSplitClosed = errors.New("split is no longer open")
+
+	// ErrSharePaid indicates a payment of a share that was already paid.
+	ErrSharePaid = errors.New("share is already paid")
+)
+
+// Method describes how a split divides the balance.
+type Method string
+
+// Methods a bill can be split by.
+const (
+	ByItems  Method = "items"  // Each share pays for the items assigned to it.
+	ByEqual  Method = "equal"  // The balance is divided evenly.
+	ByAmount Method = "custom" // Each share pays a custom amount.
+)
+
+// State is the lifecycle state of a split.
+type State string
+
+// Split states.
+const (
+	StateOpen      State = "open"
+	StateSettled   State = "settled"
+	StateCancelled State = "cancelled"
+)
+
+// ShareStatus is the payment status of a share.
+type ShareStatus string
+
+// Share statuses.
+const (
+	SharePending ShareStatus = "pending"
+	SharePaid    ShareStatus = "paid"
+	ShareVoid    ShareStatus = "void"
+)
+
+// Line assigns units of an order's item line to a share.
+type Line struct {
+	OrderID  string `json:"order_id"`
+	ItemID   string `json:"id,omitempty"`   // The menu item of the line, if it was ordered from a menu.
+	Name     string `json:"name,omitempty"` // The name of the line, used when it has no menu item.
+	Quantity uint64 `json:"quantity"`
+}
+
+// Share is the part of a split paid by one person.
+type Share struct {
+	ID          string            `json:"id"`
+	Label       string            `json:"label,omitempty"`     // Who pays the share i.e. "Wanjiku".
+	Lines       []Line            `json:"lines,omitempty"`     // The items the share pays for when splitting by item.
+	Amount      uint64            `json:"amount"`              // The part of the balance the share pays.
+	Allocations map[string]uint64 `json:"allocations"`         // How the amount is applied to each order.
+	Tip         uint64            `json:"tip,omitempty"`       // The tip left with the payment.
+	Staff       string            `json:"staff,omitempty"`     // The staff member the tip is for.
+	Status      ShareStatus       `json:"status"`              // Whether the share is paid.
+	PaidWith    string            `json:"paid_with,omitempty"` // How the share was paid i.e. mpesa.
+	Reference   string            `json:"reference,omitempty"` // The payment reference i.e. an M-Pesa receipt.
+	PaidAt      time.Time         `json:"paid_at,omitempty"`   // When the share was paid.
+}
+
+// Split divides the outstanding balance of one or more orders into shares.
+type Split struct {
+	ID        string    `json:"id"`
+	Vendor    string    `json:"vendor"`
+	OrderIDs  []string  `json:"order_ids"`
+	Method    Method    `json:"method"`
+	State     State     `json:"state"`
+	Total     uint64    `json:"total"` // The balance the split covers.
+	Shares    []Share   `json:"shares"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Balance returns how much of the split is still to be paid.
+func (split Split) Balance() uint64 {
+	var balance uint64
+	for _, share := range split.Shares {
+		if share.Status == SharePending {
+			balance += share.Amount
+		}
+	}
+	return balance
+}
+
+// Request describes how to split a bill. Only the field of the chosen
+// method is used.
+type Request struct {
+	OrderIDs []string
+	Method   Method
+	Count    uint64   // The number of equal shares.
+	Amounts  []uint64 // The custom amounts, they must add up to the balance.
+	Lines    [][]Line // The lines of every share, they must cover every item.
+	Labels   []string // Optional labels of the shares, in order.
+}
+
+// Validate returns an error if the request is malformed.
+func (req Request) Validate() error {
+	if len(req.OrderIDs) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	switch req.Method {
+	case ByEqual:
+		if req.Count == 0 {
+			return errors.ErrMalformedEntity
+		}
+	case ByAmount:
+		if len(req.Amounts) == 0 {
+			return errors.ErrMalformedEntity
+		}
+	case ByItems:
+		if len(req.Lines) == 0 {
+			return errors.ErrMalformedEntity
+		}
+	default:
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Tip is left when paying a share, either as a fixed amount or as a
+// percentage of the share.
+type Tip struct {
+	Amount  uint64 `json:"amount,omitempty"`
+	Percent uint64 `json:"percent,omitempty"`
+	Staff   string `json:"staff,omitempty"`
+}
+
+// Value returns the tip on a share of the amount, rounded to the nearest
+// unit.
+func (tip Tip) Value(amount uint64) uint64 {
+	if tip.Amount > 0 {
+		return tip.Amount
+	}
+	return (amount*tip.Percent + 50) / 100
+}
+
+// Payment settles a share.
+type Payment struct {
+	Tip       Tip
+	Method    string // How the share was paid i.e. cash or mpesa.
+	Reference string // The payment reference i.e. an M-Pesa receipt.
+}
+
+// TipsQuery selects the tips of a tips report.
+type TipsQuery struct {
+	Vendor string
+	Staff  string
+	From   time.Time
+	To     time.Time
+}
+
+// StaffTips sums the tips a staff member received.
+type StaffTips struct {
+	Staff  string `json:"staff"`
+	Tips   uint64 `json:"tips"`
+	Shares uint64 `json:"shares"` // The number of paid shares that carried a tip.
+}
+
+// Service specifies the bill splitting API.
+type Service interface {
+	// SplitBill divides the outstanding balance of the orders into shares.
+	// Orders can only be in one open split at a time.
+	SplitBill(ctx context.Context, token string, req Request) (Split, error)
+
+	// ViewSplit retrieves a split by its unique identifier.
+	ViewSplit(ctx context.Context, token, id string) (Split, error)
+
+	// PayShare settles a share of a split, applying it to the balance of its
+	// orders. Orders become paid once every share covering them is paid.
+	PayShare(ctx context.Context, token, splitID, shareID string, payment Payment) (Share, error)
+
+	// CancelSplit voids the unpaid shares of a split so its orders can be
+	// split again. Paid shares stay applied to the orders.
+	CancelSplit(ctx context.Context, token, id string) error
+
+	// TipsReport sums the tips received per staff member for payouts.
+	TipsReport(ctx context.Context, token string, query TipsQuery) ([]StaffTips, error)
+}
+
+// Repository specifies a split persistence API.
+type Repository interface {
+	// Save persists the split and its shares. If one of the orders is in
+	// another open split errors.ErrConflict is returned.
+	Save(ctx context.Context, split Split) (string, error)
+
+	// RetrieveByID retrieves a split with its shares.
+	RetrieveByID(ctx context.Context, id string) (Split, error)
+
+	// UpdateState updates the state of a split, voiding its pending shares
+	// if it is cancelled.
+	UpdateState(ctx context.Context, id string, state State) error
+
+	// PayShare marks a pending share paid. If the share is not pending
+	// errors.ErrConflict is returned.
+	PayShare(ctx context.Context, splitID string, share Share) error
+
+	// RetrieveTips sums the tips of paid shares per staff member.
+	RetrieveTips(ctx context.Context, query TipsQuery) ([]StaffTips, error)
+}
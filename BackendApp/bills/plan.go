@@ -0,0 +1,174 @@
+package bills
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// plan divides the outstanding balance of the orders into shares and
+// decides how much of each share goes to each order.
+func plan(req Request, outstanding []orders.Order) ([]Share, error) {
+	balances := make([]uint64, len(outstanding))
+	var total uint64
+	for i, order := range outstanding {
+		balances[i] = order.Balance()
+		total += balances[i]
+	}
+
+	var amounts []uint64
+	switch req.Method {
+	case ByEqual:
+		amounts = apportion(total, ones(req.Count))
+	case ByAmount:
+		var sum uint64
+		for _, amount := range req.Amounts {
+			if amount == 0 {
+				return nil, errors.ErrMalformedEntity
+			}
+			sum += amount
+		}
+		if sum != total {
+			return nil, ErrSharesMismatch
+		}
+		amounts = req.Amounts
+	case ByItems:
+		return planItems(req.Lines, outstanding)
+	}
+
+	// Shares fill the orders one after the other, so every order but the
+	// one being filled is either fully covered or untouched.
+	shares := make([]Share, len(amounts))
+	order := 0
+	for i, amount := range amounts {
+		shares[i] = Share{Amount: amount, Allocations: map[string]uint64{}}
+		for amount > 0 {
+			for balances[order] == 0 {
+				order++
+			}
+			part := min(amount, balances[order])
+			shares[i].Allocations[outstanding[order].ID] += part
+			balances[order] -= part
+			amount -= part
+		}
+	}
+	return shares, nil
+}
+
+// planItems charges each share for the items assigned to it. The balance of
+// every order is divided in proportion to the value of the items each share
+// took from it, so partial payments and price adjustments are shared fairly.
+func planItems(assigned [][]Line, outstanding []orders.Order) ([]Share, error) {
+	shares := make([]Share, len(assigned))
+	for i, lines := range assigned {
+		shares[i] = Share{Lines: lines, Allocations: map[string]uint64{}}
+	}
+	for _, order := range outstanding {
+		left := map[string]uint64{}
+		prices := map[string]uint64{}
+		for _, item := range order.Items {
+			key := lineKey(item.ID, item.Name)
+			left[key] += item.Quantity
+			prices[key] = item.Price
+		}
+
+		values := make([]uint64, len(assigned))
+		for i, lines := range assigned {
+			for _, line := range lines {
+				if line.OrderID != order.ID {
+					continue
+				}
+				key := lineKey(line.ItemID, line.Name)
+				if line.Quantity == 0 || line.Quantity > left[key] {
+					return nil, ErrSharesMismatch
+				}
+				left[key] -= line.Quantity
+				values[i] += line.Quantity * prices[key]
+			}
+		}
+		for _, units := range left {
+			if units > 0 {
+				return nil, ErrSharesMismatch
+			}
+		}
+
+		var value uint64
+		for _, v := range values {
+			value += v
+		}
+		if value == 0 {
+			return nil, ErrSharesMismatch
+		}
+		for i, part := range apportion(order.Balance(), values) {
+			if part > 0 {
+				shares[i].Allocations[order.ID] = part
+				shares[i].Amount += part
+			}
+		}
+	}
+
+	ids := map[string]bool{}
+	for _, order := range outstanding {
+		ids[order.ID] = true
+	}
+	for _, share := range shares {
+		for _, line := range share.Lines {
+			if !ids[line.OrderID] {
+				return nil, ErrSharesMismatch
+			}
+		}
+	}
+	return shares, nil
+}
+
+// apportion divides total in proportion to the weights. Units lost to
+// rounding go to the largest remainders so the parts add up to total.
+func apportion(total uint64, weights []uint64) []uint64 {
+	parts := make([]uint64, len(weights))
+	var sum uint64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		return parts
+	}
+	remainders := make([]uint64, len(weights))
+	var given uint64
+	for i, w := range weights {
+		parts[i] = total * w / sum
+		remainders[i] = total * w % sum
+		given += parts[i]
+	}
+	for ; given < total; given++ {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		parts[largest]++
+		remainders[largest] = 0
+	}
+	return parts
+}
+
+func ones(n uint64) []uint64 {
+	weights := make([]uint64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+func min(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func lineKey(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return "name:" + name
+}
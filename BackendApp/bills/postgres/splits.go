@@ -0,0 +1,322 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	splitColumns = `id, vendor, order_ids, method, state, total, created_at, updated_at`
+	shareColumns = `id, split_id, vendor, position, label, lines, amount, allocations, tip, staff, status, paid_with, reference, paid_at`
+)
+
+var _ bills.Repository = (*splitsRepo)(nil)
+
+type splitsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSplitsRepo instantiates a PostgreSQL
+// implementation of bill splits repository.
+func NewSplitsRepo(db *sqlx.DB) bills.Repository {
+	return &splitsRepo{
+		db: db,
+	}
+}
+
+func (repo splitsRepo) Save(ctx context.Context, split bills.Split) (string, error) {
+	dbs, err := toDBSplit(split)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	// Splits of the same vendor are created one at a time so two of them
+	// cannot both claim an order.
+	err = pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		if err := pgutil.Lock(ctx, tx, "bill_splits:"+split.Vendor); err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		cq := `SELECT COUNT(*) FROM bill_splits WHERE state = 'open'
+			   AND order_ids ?| ARRAY(SELECT jsonb_array_elements_text(CAST(:order_ids AS JSONB)))`
+		rows, err := sqlx.NamedQueryContext(ctx, tx, cq, dbs)
+		if err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		var open uint64
+		if rows.Next() {
+			if err := rows.Scan(&open); err != nil {
+				rows.Close()
+				return errors.Wrap(errors.ErrCreateEntity, err)
+			}
+		}
+		rows.Close()
+		if open > 0 {
+			return errors.ErrConflict
+		}
+
+		q := `INSERT INTO bill_splits (` + splitColumns + `)
+			  VALUES (:id, :vendor, :order_ids, :method, :state, :total, :created_at, :updated_at)`
+		if _, err := tx.NamedExecContext(ctx, q, dbs); err != nil {
+			return handleError(err, errors.ErrCreateEntity)
+		}
+		sq := `INSERT INTO bill_shares (` + shareColumns + `)
+			   VALUES (:id, :split_id, :vendor, :position, :label, :lines, :amount, :allocations, :tip, :staff, :status, :paid_with, :reference, :paid_at)`
+		for i, share := range split.Shares {
+			dbsh, err := toDBShare(split, i, share)
+			if err != nil {
+				return errors.Wrap(errors.ErrCreateEntity, err)
+			}
+			if _, err := tx.NamedExecContext(ctx, sq, dbsh); err != nil {
+				return handleError(err, errors.ErrCreateEntity)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return split.ID, nil
+}
+
+func (repo splitsRepo) RetrieveByID(ctx context.Context, id string) (bills.Split, error) {
+	q := `SELECT ` + splitColumns + ` FROM bill_splits WHERE id = $1`
+
+	dbs := dbSplit{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return bills.Split{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return bills.Split{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	split, err := toSplit(dbs)
+	if err != nil {
+		return bills.Split{}, err
+	}
+
+	sq := `SELECT ` + shareColumns + ` FROM bill_shares WHERE split_id = $1 ORDER BY position`
+	rows, err := repo.db.QueryxContext(ctx, sq, id)
+	if err != nil {
+		return bills.Split{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	split.Shares = []bills.Share{}
+	for rows.Next() {
+		dbsh := dbShare{}
+		if err := rows.StructScan(&dbsh); err != nil {
+			return bills.Split{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		share, err := toShare(dbsh)
+		if err != nil {
+			return bills.Split{}, err
+		}
+		split.Shares = append(split.Shares, share)
+	}
+	return split, nil
+}
+
+func (repo splitsRepo) UpdateState(ctx context.Context, id string, state bills.State) error {
+	params := map[string]interface{}{
+		"id":         id,
+		"state":      string(state),
+		"updated_at": time.Now(),
+	}
+	return pgutil.InTx(ctx, repo.db, errors.ErrUpdateEntity, func(tx *sqlx.Tx) error {
+		q := `UPDATE bill_splits SET state = :state, updated_at = :updated_at WHERE id = :id`
+		res, err := tx.NamedExecContext(ctx, q, params)
+		if err != nil {
+			return handleError(err, errors.ErrUpdateEntity)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errors.ErrNotFound
+		}
+		if state == bills.StateCancelled {
+			vq := `UPDATE bill_shares SET status = 'void' WHERE split_id = :id AND status = 'pending'`
+			if _, err := tx.NamedExecContext(ctx, vq, params); err != nil {
+				return handleError(err, errors.ErrUpdateEntity)
+			}
+		}
+		return nil
+	})
+}
+
+func (repo splitsRepo) PayShare(ctx context.Context, splitID string, share bills.Share) error {
+	q := `UPDATE bill_shares SET tip = :tip, staff = :staff, status = :status, paid_with = :paid_with,
+		  reference = :reference, paid_at = :paid_at
+		  WHERE id = :id AND split_id = :split_id AND status = 'pending'`
+
+	dbsh, err := toDBShare(bills.Split{ID: splitID}, 0, share)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, dbsh)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.ErrConflict
+	}
+	return nil
+}
+
+func (repo splitsRepo) RetrieveTips(ctx context.Context, query bills.TipsQuery) ([]bills.StaffTips, error) {
+	filters := []string{"vendor = :vendor", "status = 'paid'", "tip > 0"}
+	if query.Staff != "" {
+		filters = append(filters, "staff = :staff")
+	}
+	if !query.From.IsZero() {
+		filters = append(filters, "paid_at >= :from")
+	}
+	if !query.To.IsZero() {
+		filters = append(filters, "paid_at < :to")
+	}
+	q := fmt.Sprintf(`SELECT staff, SUM(tip) AS tips, COUNT(*) AS shares FROM bill_shares
+		  WHERE %s GROUP BY staff ORDER BY staff`, strings.Join(filters, " AND "))
+
+	params := map[string]interface{}{
+		"vendor": query.Vendor,
+		"staff":  query.Staff,
+		"from":   query.From,
+		"to":     query.To,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	report := []bills.StaffTips{}
+	for rows.Next() {
+		var tips bills.StaffTips
+		if err := rows.Scan(&tips.Staff, &tips.Tips, &tips.Shares); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		report = append(report, tips)
+	}
+	return report, nil
+}
+
+type dbSplit struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	OrderIDs  []byte    `db:"order_ids"`
+	Method    string    `db:"method"`
+	State     string    `db:"state"`
+	Total     uint64    `db:"total"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBSplit(split bills.Split) (dbSplit, error) {
+	ids, err := json.Marshal(split.OrderIDs)
+	if err != nil {
+		return dbSplit{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return dbSplit{
+		ID:        split.ID,
+		Vendor:    split.Vendor,
+		OrderIDs:  ids,
+		Method:    string(split.Method),
+		State:     string(split.State),
+		Total:     split.Total,
+		CreatedAt: split.CreatedAt,
+		UpdatedAt: split.UpdatedAt,
+	}, nil
+}
+
+func toSplit(split dbSplit) (bills.Split, error) {
+	ids := []string{}
+	if split.OrderIDs != nil {
+		if err := json.Unmarshal(split.OrderIDs, &ids); err != nil {
+			return bills.Split{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return bills.Split{
+		ID:        split.ID,
+		Vendor:    split.Vendor,
+		OrderIDs:  ids,
+		Method:    bills.Method(split.Method),
+		State:     bills.State(split.State),
+		Total:     split.Total,
+		CreatedAt: split.CreatedAt,
+		UpdatedAt: split.UpdatedAt,
+	}, nil
+}
+
+type dbShare struct {
+	ID          string       `db:"id"`
+	SplitID     string       `db:"split_id"`
+	Vendor      string       `db:"vendor"`
+	Position    int          `db:"position"`
+	Label       string       `db:"label"`
+	Lines       []byte       `db:"lines"`
+	Amount      uint64       `db:"amount"`
+	Allocations []byte       `db:"allocations"`
+	Tip         uint64       `db:"tip"`
+	Staff       string       `db:"staff"`
+	Status      string       `db:"status"`
+	PaidWith    string       `db:"paid_with"`
+	Reference   string       `db:"reference"`
+	PaidAt      sql.NullTime `db:"paid_at"`
+}
+
+func toDBShare(split bills.Split, position int, share bills.Share) (dbShare, error) {
+	lines := share.Lines
+	if lines == nil {
+		lines = []bills.Line{}
+	}
+	ld, err := json.Marshal(lines)
+	if err != nil {
+		return dbShare{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	ad, err := json.Marshal(share.Allocations)
+	if err != nil {
+		return dbShare{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return dbShare{
+		ID:          share.ID,
+		SplitID:     split.ID,
+		Vendor:      split.Vendor,
+		Position:    position,
+		Label:       share.Label,
+		Lines:       ld,
+		Amount:      share.Amount,
+		Allocations: ad,
+		Tip:         share.Tip,
+		Staff:       share.Staff,
+		Status:      string(share.Status),
+		PaidWith:    share.PaidWith,
+		Reference:   share.Reference,
+		PaidAt:      sql.NullTime{Time: share.PaidAt, Valid: !share.PaidAt.IsZero()},
+	}, nil
+}
+
+func toShare(share dbShare) (bills.Share, error) {
+	var lines []bills.Line
+	if err := json.Unmarshal(share.Lines, &lines); err != nil {
+		return bills.Share{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	allocations := map[string]uint64{}
+	if err := json.Unmarshal(share.Allocations, &allocations); err != nil {
+		return bills.Share{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return bills.Share{
+		ID:          share.ID,
+		Label:       share.Label,
+		Lines:       lines,
+		Amount:      share.Amount,
+		Allocations: allocations,
+		Tip:         share.Tip,
+		Staff:       share.Staff,
+		Status:      bills.ShareStatus(share.Status),
+		PaidWith:    share.PaidWith,
+		Reference:   share.Reference,
+		PaidAt:      share.PaidAt.Time,
+	}, nil
+}
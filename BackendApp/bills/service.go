@@ -0,0 +1,191 @@
+package bills
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+var _ Service = (*billsService)(nil)
+
+type billsService struct {
+	splits Repository
+	orders orders.OrderService
+}
+
+// NewService instantiates the bills service implementation.
+func NewService(splits Repository, ordersSvc orders.OrderService) Service {
+	return &billsService{
+		splits: splits,
+		orders: ordersSvc,
+	}
+}
+
+func (svc billsService) SplitBill(ctx context.Context, token string, req Request) (Split, error) {
+	if err := req.Validate(); err != nil {
+		return Split{}, err
+	}
+	var vendor string
+	var total uint64
+	var outstanding []orders.Order
+	seen := map[string]bool{}
+	for _, id := range req.OrderIDs {
+		if seen[id] {
+			return Split{}, errors.ErrMalformedEntity
+		}
+		seen[id] = true
+		order, err := svc.orders.ViewOrder(ctx, token, id)
+		if err != nil {
+			return Split{}, err
+		}
+		if vendor != "" && order.Vendor != vendor {
+			return Split{}, errors.ErrMalformedEntity
+		}
+		vendor = order.Vendor
+		if order.Balance() > 0 {
+			outstanding = append(outstanding, order)
+			total += order.Balance()
+		}
+	}
+	if total == 0 {
+		return Split{}, ErrNothingOutstanding
+	}
+
+	shares, err := plan(req, outstanding)
+	if err != nil {
+		return Split{}, err
+	}
+	for i := range shares {
+		shares[i].ID = ulid.Make().String()
+		shares[i].Status = SharePending
+		if i < len(req.Labels) {
+			shares[i].Label = req.Labels[i]
+		}
+	}
+	ids := make([]string, len(outstanding))
+	for i, order := range outstanding {
+		ids[i] = order.ID
+	}
+	now := time.Now()
+	split := Split{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		OrderIDs:  ids,
+		Method:    req.Method,
+		State:     StateOpen,
+		Total:     total,
+		Shares:    shares,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := svc.splits.Save(ctx, split); err != nil {
+		return Split{}, err
+	}
+	return split, nil
+}
+
+func (svc billsService) ViewSplit(ctx context.Context, token, id string) (Split, error) {
+	return svc.splits.RetrieveByID(ctx, id)
+}
+
+func (svc billsService) PayShare(ctx context.Context, token, splitID, shareID string, payment Payment) (Share, error) {
+	split, err := svc.splits.RetrieveByID(ctx, splitID)
+	if err != nil {
+		return Share{}, err
+	}
+	if split.State != StateOpen {
+		return Share{}, ErrSplitClosed
+	}
+	var share Share
+	for _, s := range split.Shares {
+		if s.ID == shareID {
+			share = s
+		}
+	}
+	switch share.Status {
+	case "":
+		return Share{}, errors.ErrNotFound
+	case SharePaid:
+		return Share{}, ErrSharePaid
+	case ShareVoid:
+		return Share{}, ErrSplitClosed
+	}
+
+	share.Tip = payment.Tip.Value(share.Amount)
+	share.Staff = payment.Tip.Staff
+	share.PaidWith = payment.Method
+	share.Reference = payment.Reference
+	share.PaidAt = time.Now()
+	share.Status = SharePaid
+
+	// The payments are recorded before the share is marked paid, so a share
+	// whose payments failed stays pending and can be paid again. Each is
+	// keyed on the share and its order, paying it again only records the
+	// payments that are missing. The tip is spread over the orders like the
+	// amount so each order carries the tips it earned.
+	amounts := make([]uint64, len(split.OrderIDs))
+	for i, id := range split.OrderIDs {
+		amounts[i] = share.Allocations[id]
+	}
+	tips := apportion(share.Tip, amounts)
+	for i, id := range split.OrderIDs {
+		if amounts[i] == 0 && tips[i] == 0 {
+			continue
+		}
+		pctx := orders.WithPaymentKey(ctx, paymentKey(split.ID, share.ID, id))
+		if _, err := svc.orders.RecordPayment(pctx, token, id, share.PaidWith, amounts[i], tips[i]); err != nil {
+			return Share{}, err
+		}
+	}
+
+	if err := svc.splits.PayShare(ctx, split.ID, share); err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return Share{}, ErrSharePaid
+		}
+		return Share{}, err
+	}
+
+	if err := svc.settle(ctx, split.ID); err != nil {
+		return Share{}, err
+	}
+	return share, nil
+}
+
+func (svc billsService) CancelSplit(ctx context.Context, token, id string) error {
+	split, err := svc.splits.RetrieveByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if split.State != StateOpen {
+		return ErrSplitClosed
+	}
+	return svc.splits.UpdateState(ctx, id, StateCancelled)
+}
+
+func (svc billsService) TipsReport(ctx context.Context, token string, query TipsQuery) ([]StaffTips, error) {
+	if query.Vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	return svc.splits.RetrieveTips(ctx, query)
+}
+
+// paymentKey returns the key of the payment a share makes to an order.
+func paymentKey(splitID, shareID, orderID string) string {
+	return splitID + "/" + shareID + "/" + orderID
+}
+
+// settle closes the split once every share is paid. It reads the split
+// again so that shares paid concurrently are taken into account.
+func (svc billsService) settle(ctx context.Context, id string) error {
+	split, err := svc.splits.RetrieveByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if split.State != StateOpen || split.Balance() > 0 {
+		return nil
+	}
+	return svc.splits.UpdateState(ctx, id, StateSettled)
+}
@@ -0,0 +1,139 @@
+package bills_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+)
+
+const token = "token"
+
+// splitRepo keeps splits in memory.
+type splitRepo struct {
+	mu     sync.Mutex
+	splits map[string]bills.Split
+}
+
+func (repo *splitRepo) Save(ctx context.Context, split bills.Split) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.splits[split.ID] = split
+	return split.ID, nil
+}
+
+func (repo *splitRepo) RetrieveByID(ctx context.Context, id string) (bills.Split, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	split, ok := repo.splits[id]
+	if !ok {
+		return bills.Split{}, errors.ErrNotFound
+	}
+	split.Shares = append([]bills.Share(nil), split.Shares...)
+	return split, nil
+}
+
+func (repo *splitRepo) UpdateState(ctx context.Context, id string, state bills.State) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	split := repo.splits[id]
+	split.State = state
+	repo.splits[id] = split
+	return nil
+}
+
+func (repo *splitRepo) PayShare(ctx context.Context, splitID string, share bills.Share) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	split := repo.splits[splitID]
+	for i, s := range split.Shares {
+		if s.ID == share.ID {
+			if s.Status != bills.SharePending {
+				return errors.ErrConflict
+			}
+			split.Shares[i] = share
+		}
+	}
+	return nil
+}
+
+func (repo *splitRepo) RetrieveTips(ctx context.Context, query bills.TipsQuery) ([]bills.StaffTips, error) {
+	return nil, nil
+}
+
+// failingOrders fails the payments of one order while fail is set.
+type failingOrders struct {
+	orders.OrderService
+	order string
+	fail  bool
+}
+
+func (svc *failingOrders) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	if svc.fail && id == svc.order {
+		return orders.Order{}, errors.ErrUpdateEntity
+	}
+	return svc.OrderService.RecordPayment(ctx, token, id, method, amount, tip)
+}
+
+func TestPayShareFailedPayment(t *testing.T) {
+	ctx := context.Background()
+	ordersSvc := orders.NewOrderService(memory.NewOrderRepo())
+	var ids []string
+	for _, name := range []string{"Chapati", "Pilau"} {
+		order := orders.Order{Vendor: "mama-oliech", Name: name, Price: 100, Place: "inhouse", Status: orders.StatusOrdered}
+		id, err := ordersSvc.CreateOrder(ctx, token, order)
+		if err != nil {
+			t.Fatalf("creating order: %s", err)
+		}
+		ids = append(ids, id)
+	}
+
+	failing := &failingOrders{OrderService: ordersSvc, order: ids[1], fail: true}
+	splits := &splitRepo{splits: map[string]bills.Split{}}
+	svc := bills.NewService(splits, failing)
+	split, err := svc.SplitBill(ctx, token, bills.Request{OrderIDs: ids, Method: bills.ByAmount, Amounts: []uint64{150, 50}})
+	if err != nil {
+		t.Fatalf("splitting bill: %s", err)
+	}
+	share := split.Shares[0]
+	payment := bills.Payment{Method: "cash", Tip: bills.Tip{Amount: 15}}
+
+	// The share covers both orders, the payment of the second fails.
+	if _, err := svc.PayShare(ctx, token, split.ID, share.ID, payment); err == nil {
+		t.Fatalf("paying share with a failing payment returned no error")
+	}
+	got, err := svc.ViewSplit(ctx, token, split.ID)
+	if err != nil {
+		t.Fatalf("viewing split: %s", err)
+	}
+	if got.Shares[0].Status != bills.SharePending {
+		t.Errorf("share is %s after a failed payment, want %s", got.Shares[0].Status, bills.SharePending)
+	}
+
+	// Paying the share again records the missing payment only.
+	failing.fail = false
+	paid, err := svc.PayShare(ctx, token, split.ID, share.ID, payment)
+	if err != nil {
+		t.Fatalf("paying share again: %s", err)
+	}
+	if paid.Status != bills.SharePaid {
+		t.Errorf("share is %s, want %s", paid.Status, bills.SharePaid)
+	}
+	want := []struct{ paid, tips uint64 }{{100, 10}, {50, 5}}
+	for i, id := range ids {
+		order, err := ordersSvc.ViewOrder(ctx, token, id)
+		if err != nil {
+			t.Fatalf("viewing order: %s", err)
+		}
+		if order.Paid != want[i].paid || order.Tips != want[i].tips {
+			t.Errorf("order %d paid %d with %d tips, want %d with %d", i+1, order.Paid, order.Tips, want[i].paid, want[i].tips)
+		}
+	}
+	if _, err := svc.PayShare(ctx, token, split.ID, share.ID, payment); err != bills.ErrSharePaid {
+		t.Errorf("paying a paid share returned %v, want %s", err, bills.ErrSharePaid)
+	}
+}
@@ -0,0 +1,2 @@
+// Package api contains middlewares shared by all the chatbot channels.
+package api
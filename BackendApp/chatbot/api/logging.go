@@ -0,0 +1,38 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/go-kit/log"
+)
+
+var _ chatbot.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    chatbot.Service
+}
+
+// LoggingMiddleware adds logging facilities to the chatbot service.
+func LoggingMiddleware(svc chatbot.Service, logger log.Logger) chatbot.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Handle(ctx context.Context, msg chatbot.Message) (replies []chatbot.Reply, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "handle_message",
+			"channel", msg.Channel,
+			"from", msg.From,
+			"replies", len(replies),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Handle(ctx, msg)
+}
@@ -0,0 +1,38 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ chatbot.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     chatbot.Service
+}
+
+// MetricsMiddleware instruments the chatbot service by tracking message
+// count and latency per channel.
+func MetricsMiddleware(svc chatbot.Service, counter metrics.Counter, latency metrics.Histogram) chatbot.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Handle(ctx context.Context, msg chatbot.Message) ([]chatbot.Reply, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "handle_message_"+msg.Channel).Add(1)
+		ms.latency.With("method", "handle_message_"+msg.Channel).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Handle(ctx, msg)
+}
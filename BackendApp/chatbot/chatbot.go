@@ -0,0 +1,158 @@
+package chatbot
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var (
+	// ErrSignature indicates that a webhook did not carry a valid signature
+	// or secret from the messaging provider.
+	ErrSignature = errors.New("invalid webhook signature")
+
+	// ErrSend indicates that the messaging provider rejected an outbound message.
+	ErrSend = errors.New("failed to send message over the channel")
+)
+
+// State describes where in the ordering flow a conversation currently is.
+type State string
+
+const (
+	// StateIdle is the state of a new or finished conversation.
+	StateIdle State = "idle"
+	// StateBrowsing is the state after the menu has been shown and items
+	// are being added to the cart.
+	StateBrowsing State = "browsing"
+	// StatePlace is the state where the user chooses inhouse or delivery.
+	StatePlace State = "choosing_place"
+	// StateAddress is the state where the user gives a delivery address.
+	StateAddress State = "address"
+	// StateConfirming is the state where the user confirms the order summary.
+	StateConfirming State = "confirming"
+	// StatePaying is the state where the user chooses how to pay for a
+	// created order.
+	StatePaying State = "paying"
+)
+
+// Payment methods the bot offers once an order is created.
+const (
+	PaymentCash  = "cash"
+	PaymentMpesa = "mpesa"
+)
+
+// Message is an inbound message from a user on a channel.
+type Message struct {
+	ID      string    `json:"id,omitempty"`      // The channel's unique identifier of the message.
+	Channel string    `json:"channel,omitempty"` // The name of the channel the message came from i.e. whatsapp.
+	From    string    `json:"from"`              // The user's address on the channel i.e. a phone number.
+	Name    string    `json:"name,omitempty"`    // The user's display name if the channel knows it.
+	Text    string    `json:"text,omitempty"`    // The free text the user typed.
+	Payload string    `json:"payload,omitempty"` // The ID of the option the user selected, if any.
+	SentAt  time.Time `json:"sent_at,omitempty"` // When the user sent the message.
+}
+
+// Option is a choice presented to the user along with a reply. Channels that
+// support interactive messages render them as buttons or lists, others as
+// numbered text.
+type Option struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// Reply is an outbound message produced by the bot.
+type Reply struct {
+	To       string        `json:"to"`
+	Language Language      `json:"language,omitempty"`
+	Text     string        `json:"text"`
+	Options  []Option      `json:"options,omitempty"`
+	Order    *orders.Order `json:"order,omitempty"` // Set when the reply confirms a created order.
+}
+
+// Channel delivers replies to users over a particular transport.
+type Channel interface {
+	// Name returns the unique name of the channel i.e. whatsapp.
+	Name() string
+
+	// Send delivers the reply to the user.
+	Send(ctx context.Context, reply Reply) error
+}
+
+// Session holds the conversation state of a single user on a single channel.
+type Session struct {
+	ID        string        `json:"id"`
+	Channel   string        `json:"channel"`
+	User      string        `json:"user"`
+	Language  Language      `json:"language"`
+	State     State         `json:"state"`
+	Cart      []orders.Item `json:"cart,omitempty"`
+	Place     string        `json:"place,omitempty"`
+	Address   string        `json:"address,omitempty"`
+	OrderID   string        `json:"order_id,omitempty"`
+	Menu      []menu.Item   `json:"menu,omitempty"` // The last menu shown so numbered choices can be resolved.
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Total returns the price of everything in the cart.
+func (s Session) Total() uint64 {
+	return orders.Order{Items: s.Cart}.ItemsTotal()
+}
+
+// SessionRepository specifies a conversation state persistence API.
+type SessionRepository interface {
+	// Save persists the session.
+	Save(ctx context.Context, session Session) error
+
+	// Retrieve retrieves the session by its unique identifier. If the session
+	// does not exist or has expired errors.ErrNotFound is returned.
+	Retrieve(ctx context.Context, id string) (Session, error)
+
+	// Remove removes the session.
+	Remove(ctx context.Context, id string) error
+}
+
+// Service drives conversations with users and turns them into orders.
+type Service interface {
+	// Handle processes an inbound message and returns the replies that
+	// should be sent back to the user.
+	Handle(ctx context.Context, msg Message) ([]Reply, error)
+}
+
+// Dispatch handles the message and sends every resulting reply over the
+// channel.
+func Dispatch(ctx context.Context, svc Service, ch Channel, msg Message) ([]Reply, error) {
+	if msg.Channel == "" {
+		msg.Channel = ch.Name()
+	}
+	replies, err := svc.Handle(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		if err := ch.Send(ctx, reply); err != nil {
+			return replies, err
+		}
+	}
+	return replies, nil
+}
+
+// SessionID returns the identifier of a user's session on a channel.
+func SessionID(channel, user string) string {
+	return channel + ":" + user
+}
+
+// Truncate shortens s to at most n runes so it fits channel field limits.
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
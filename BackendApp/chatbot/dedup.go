@@ -0,0 +1,66 @@
+package chatbot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Deduplicator detects inbound messages that a provider redelivers because
+// it did not get a timely acknowledgement.
+type Deduplicator interface {
+	// Claim records the message ID and reports whether it is the first time
+	// it has been seen.
+	Claim(ctx context.Context, id string) (bool, error)
+
+	// Release forgets the message ID so that a redelivery of a message that
+	// failed to be handled is processed again.
+	Release(ctx context.Context, id string) error
+}
+
+var _ Deduplicator = (*memoryDeduplicator)(nil)
+
+type memoryDeduplicator struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	swept time.Time
+	seen  map[string]time.Time
+}
+
+// NewMemoryDeduplicator instantiates an in-memory Deduplicator that
+// remembers message IDs for ttl. Providers stop redelivering after a day so
+// that is a sensible ttl.
+func NewMemoryDeduplicator(ttl time.Duration) Deduplicator {
+	return &memoryDeduplicator{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+func (d *memoryDeduplicator) Claim(_ context.Context, id string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if at, ok := d.seen[id]; ok && now.Sub(at) < d.ttl {
+		return false, nil
+	}
+	if now.Sub(d.swept) >= d.ttl {
+		d.swept = now
+		for seen, at := range d.seen {
+			if now.Sub(at) >= d.ttl {
+				delete(d.seen, seen)
+			}
+		}
+	}
+	d.seen[id] = now
+	return true, nil
+}
+
+func (d *memoryDeduplicator) Release(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, id)
+	return nil
+}
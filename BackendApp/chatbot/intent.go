@@ -0,0 +1,284 @@
+package chatbot
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Language is the language a conversation is held in.
+type Language string
+
+const (
+	// English is the default conversation language.
+	English Language = "en"
+	// Swahili is used when the user writes in Swahili.
+	Swahili Language = "sw"
+)
+
+// Intent is what the user wants to do with a message.
+type Intent string
+
+const (
+	IntentUnknown  Intent = "unknown"
+	IntentGreet    Intent = "greet"
+	IntentHelp     Intent = "help"
+	IntentMenu     Intent = "menu"
+	IntentAdd      Intent = "add"
+	IntentRemove   Intent = "remove"
+	IntentCart     Intent = "cart"
+	IntentCheckout Intent = "checkout"
+	IntentInhouse  Intent = "inhouse"
+	IntentDelivery Intent = "delivery"
+	IntentYes      Intent = "yes"
+	IntentNo       Intent = "no"
+	IntentCancel   Intent = "cancel"
+	IntentPay      Intent = "pay"
+	IntentCash     Intent = "cash"
+	IntentMpesa    Intent = "mpesa"
+	IntentSelect   Intent = "select"   // A bare number picking an entry from the last shown menu.
+	IntentTooMany  Intent = "too_many" // Adding or removing more units of an item than maxQuantity.
+)
+
+// maxQuantity is the most units of an item an order can have, as a typo
+// like "3 2000" would otherwise order two thousand.
+const maxQuantity = 99
+
+// Command is a parsed user message.
+type Command struct {
+	Intent   Intent
+	Language Language // Empty when the message gave no hint of its language.
+	Index    int      // 1-based menu entry for IntentSelect, IntentAdd and IntentRemove.
+	Quantity uint64   // Zero when the message did not name a quantity.
+	Query    string   // The free text naming an item i.e. "chapati".
+	Text     string   // The original text.
+}
+
+type keyword struct {
+	phrase   string
+	intent   Intent
+	language Language
+}
+
+// keywords are matched against the start of the normalised message and the
+// longest matching phrase wins, so "my order" beats "order".
+var keywords = []keyword{
+	{"hi", IntentGreet, English},
+	{"hello", IntentGreet, English},
+	{"hey", IntentGreet, English},
+	{"start", IntentGreet, English},
+	{"habari", IntentGreet, Swahili},
+	{"jambo", IntentGreet, Swahili},
+	{"hujambo", IntentGreet, Swahili},
+	{"mambo", IntentGreet, Swahili},
+	{"sasa", IntentGreet, Swahili},
+	{"niaje", IntentGreet, Swahili},
+	{"help", IntentHelp, English},
+	{"msaada", IntentHelp, Swahili},
+	{"saidia", IntentHelp, Swahili},
+	{"menu", IntentMenu, English},
+	{"browse", IntentMenu, English},
+	{"list", IntentMenu, English},
+	{"menyu", IntentMenu, Swahili},
+	{"orodha", IntentMenu, Swahili},
+	{"chakula", IntentMenu, Swahili},
+	{"add", IntentAdd, English},
+	{"i want", IntentAdd, English},
+	{"want", IntentAdd, English},
+	{"order", IntentAdd, English},
+	{"get", IntentAdd, English},
+	{"ongeza", IntentAdd, Swahili},
+	{"nataka", IntentAdd, Swahili},
+	{"nipe", IntentAdd, Swahili},
+	{"agiza", IntentAdd, Swahili},
+	{"remove", IntentRemove, English},
+	{"delete", IntentRemove, English},
+	{"drop", IntentRemove, English},
+	{"ondoa", IntentRemove, Swahili},
+	{"toa", IntentRemove, Swahili},
+	{"futa", IntentRemove, Swahili},
+	{"cart", IntentCart, English},
+	{"basket", IntentCart, English},
+	{"my order", IntentCart, English},
+	{"kikapu", IntentCart, Swahili},
+	{"oda yangu", IntentCart, Swahili},
+	{"checkout", IntentCheckout, English},
+	{"check out", IntentCheckout, English},
+	{"done", IntentCheckout, English},
+	{"finish", IntentCheckout, English},
+	{"that's all", IntentCheckout, English},
+	{"thats all", IntentCheckout, English},
+	{"maliza", IntentCheckout, Swahili},
+	{"nimemaliza", IntentCheckout, Swahili},
+	{"tayari", IntentCheckout, Swahili},
+	{"inhouse", IntentInhouse, English},
+	{"in house", IntentInhouse, English},
+	{"eat in", IntentInhouse, English},
+	{"dine in", IntentInhouse, English},
+	{"here", IntentInhouse, English},
+	{"ndani", IntentInhouse, Swahili},
+	{"hapa", IntentInhouse, Swahili},
+	{"kula hapa", IntentInhouse, Swahili},
+	{"delivery", IntentDelivery, English},
+	{"deliver", IntentDelivery, English},
+	{"take away", IntentDelivery, English},
+	{"takeaway", IntentDelivery, English},
+	{"peleka", IntentDelivery, Swahili},
+	{"niletee", IntentDelivery, Swahili},
+	{"nipelekee", IntentDelivery, Swahili},
+	{"yes", IntentYes, English},
+	{"y", IntentYes, English},
+	{"ok", IntentYes, English},
+	{"okay", IntentYes, English},
+	{"confirm", IntentYes, English},
+	{"sure", IntentYes, English},
+	{"ndio", IntentYes, Swahili},
+	{"ndiyo", IntentYes, Swahili},
+	{"sawa", IntentYes, Swahili},
+	{"thibitisha", IntentYes, Swahili},
+	{"no", IntentNo, English},
+	{"n", IntentNo, English},
+	{"hapana", IntentNo, Swahili},
+	{"la", IntentNo, Swahili},
+	{"cancel", IntentCancel, English},
+	{"stop", IntentCancel, English},
+	{"reset", IntentCancel, English},
+	{"clear", IntentCancel, English},
+	{"ghairi", IntentCancel, Swahili},
+	{"sitisha", IntentCancel, Swahili},
+	{"acha", IntentCancel, Swahili},
+	{"pay", IntentPay, English},
+	{"payment", IntentPay, English},
+	{"lipa", IntentPay, Swahili},
+	{"malipo", IntentPay, Swahili},
+	{"cash", IntentCash, English},
+	{"taslimu", IntentCash, Swahili},
+	{"pesa taslimu", IntentCash, Swahili},
+	{"mpesa", IntentMpesa, ""},
+	{"m-pesa", IntentMpesa, ""},
+}
+
+// Parse turns a free text message into a Command. Both English and Swahili
+// keywords are understood:
+//
+//	"add 2 chapati", "ongeza chapati 2", "3", "3 2" (two of menu entry 3)
+func Parse(text string) Command {
+	cmd := Command{
+		Intent: IntentUnknown,
+		Text:   text,
+	}
+	norm := normalise(text)
+	if norm == "" {
+		return cmd
+	}
+
+	if idx, qty, ok := parseSelection(norm); ok {
+		cmd.Intent = IntentSelect
+		cmd.Index = idx
+		cmd.Quantity = qty
+		if qty > maxQuantity {
+			cmd.Intent = IntentTooMany
+		}
+		return cmd
+	}
+
+	var match keyword
+	for _, kw := range keywords {
+		if norm != kw.phrase && !strings.HasPrefix(norm, kw.phrase+" ") {
+			continue
+		}
+		if len(kw.phrase) > len(match.phrase) {
+			match = kw
+		}
+	}
+	if match.phrase == "" {
+		return cmd
+	}
+	cmd.Intent = match.intent
+	cmd.Language = match.language
+
+	rest := strings.TrimSpace(strings.TrimPrefix(norm, match.phrase))
+	switch cmd.Intent {
+	case IntentAdd, IntentRemove:
+		cmd.Query, cmd.Index, cmd.Quantity = parseItem(rest)
+		if cmd.Quantity > maxQuantity {
+			cmd.Intent = IntentTooMany
+		}
+	}
+	return cmd
+}
+
+// parseSelection recognises "3", "3 2", "3x2" and "3*2" as menu entry 3,
+// with an optional quantity. Quantities above maxQuantity are returned as
+// they are for the caller to reject.
+func parseSelection(norm string) (int, uint64, bool) {
+	fields := strings.FieldsFunc(norm, func(r rune) bool {
+		return unicode.IsSpace(r) || r == 'x' || r == '*'
+	})
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, 0, false
+	}
+	idx, err := strconv.Atoi(fields[0])
+	if err != nil || idx < 1 {
+		return 0, 0, false
+	}
+	qty := uint64(1)
+	if len(fields) == 2 {
+		var ok bool
+		if qty, ok = parseQuantity(fields[1]); !ok {
+			return 0, 0, false
+		}
+	}
+	return idx, qty, true
+}
+
+// parseItem splits "2 chapati", "chapati 2" or "#3 2" into the item
+// query or menu index and a quantity. As with parseSelection, quantities
+// above maxQuantity are left for the caller to reject.
+func parseItem(rest string) (string, int, uint64) {
+	var qty uint64
+	index := 0
+	var words []string
+	for _, field := range strings.Fields(rest) {
+		if strings.HasPrefix(field, "#") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(field, "#")); err == nil && n > 0 {
+				index = n
+				continue
+			}
+		}
+		if n, ok := parseQuantity(field); ok {
+			qty = n
+			continue
+		}
+		switch field {
+		case "a", "an", "the", "of", "some", "please", "tafadhali", "x":
+			continue
+		}
+		words = append(words, field)
+	}
+	return strings.Join(words, " "), index, qty
+}
+
+// parseQuantity parses a positive quantity. Numbers too large to parse are
+// returned as maxQuantity+1, so they are rejected as well instead of being
+// taken for the name of an item.
+func parseQuantity(field string) (uint64, bool) {
+	if field == "" || strings.Trim(field, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(field, 10, 64)
+	switch {
+	case err != nil:
+		return maxQuantity + 1, true
+	case n == 0:
+		return 0, false
+	default:
+		return n, true
+	}
+}
+
+func normalise(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.TrimRight(text, ".!?")
+	return strings.Join(strings.Fields(text), " ")
+}
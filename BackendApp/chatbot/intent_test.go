@@ -0,0 +1,59 @@
+package chatbot_test
+
+import (
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		text     string
+		intent   chatbot.Intent
+		language chatbot.Language
+		index    int
+		quantity uint64
+		query    string
+	}{
+		{text: "Hi!", intent: chatbot.IntentGreet, language: chatbot.English},
+		{text: "habari", intent: chatbot.IntentGreet, language: chatbot.Swahili},
+		{text: "my order", intent: chatbot.IntentCart, language: chatbot.English},
+		{text: "3", intent: chatbot.IntentSelect, index: 3, quantity: 1},
+		{text: "3 2", intent: chatbot.IntentSelect, index: 3, quantity: 2},
+		{text: "3x2", intent: chatbot.IntentSelect, index: 3, quantity: 2},
+		{text: "3*99", intent: chatbot.IntentSelect, index: 3, quantity: 99},
+		{text: "3 100", intent: chatbot.IntentTooMany, index: 3, quantity: 100},
+		{text: "3 99999999999999999999999", intent: chatbot.IntentTooMany, index: 3, quantity: 100},
+		{text: "3 0", intent: chatbot.IntentUnknown},
+		{text: "add 2 chapati", intent: chatbot.IntentAdd, language: chatbot.English, quantity: 2, query: "chapati"},
+		{text: "ongeza chapati 2", intent: chatbot.IntentAdd, language: chatbot.Swahili, quantity: 2, query: "chapati"},
+		{text: "add #3 2", intent: chatbot.IntentAdd, language: chatbot.English, index: 3, quantity: 2},
+		{text: "add 99 chapati", intent: chatbot.IntentAdd, language: chatbot.English, quantity: 99, query: "chapati"},
+		{text: "add 500 chapati", intent: chatbot.IntentTooMany, language: chatbot.English, quantity: 500, query: "chapati"},
+		{text: "nataka chapati 99999999999999999999999", intent: chatbot.IntentTooMany, language: chatbot.Swahili, quantity: 100, query: "chapati"},
+		{text: "remove 1000 chapati", intent: chatbot.IntentTooMany, language: chatbot.English, quantity: 1000, query: "chapati"},
+		{text: "remove chapati", intent: chatbot.IntentRemove, language: chatbot.English, query: "chapati"},
+		{text: "mpesa", intent: chatbot.IntentMpesa},
+		{text: "what is this", intent: chatbot.IntentUnknown},
+	}
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			cmd := chatbot.Parse(tc.text)
+			if cmd.Intent != tc.intent {
+				t.Errorf("expected intent %s, got %s", tc.intent, cmd.Intent)
+			}
+			if cmd.Language != tc.language {
+				t.Errorf("expected language %q, got %q", tc.language, cmd.Language)
+			}
+			if cmd.Index != tc.index {
+				t.Errorf("expected index %d, got %d", tc.index, cmd.Index)
+			}
+			if cmd.Quantity != tc.quantity {
+				t.Errorf("expected quantity %d, got %d", tc.quantity, cmd.Quantity)
+			}
+			if cmd.Query != tc.query {
+				t.Errorf("expected query %q, got %q", tc.query, cmd.Query)
+			}
+		})
+	}
+}
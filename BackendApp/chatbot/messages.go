@@ -0,0 +1,133 @@
+package chatbot
+
+import (
+	"fmt"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+type messageKey string
+
+const (
+	msgWelcome        messageKey = "welcome"
+	msgHelp           messageKey = "help"
+	msgMenu           messageKey = "menu"
+	msgMenuEmpty      messageKey = "menu_empty"
+	msgAdded          messageKey = "added"
+	msgRemoved        messageKey = "removed"
+	msgNoSuchItem     messageKey = "no_such_item"
+	msgTooMany        messageKey = "too_many"
+	msgCart           messageKey = "cart"
+	msgCartEmpty      messageKey = "cart_empty"
+	msgChoosePlace    messageKey = "choose_place"
+	msgAskAddress     messageKey = "ask_address"
+	msgConfirm        messageKey = "confirm"
+	msgOrdered        messageKey = "ordered"
+	msgChoosePayment  messageKey = "choose_payment"
+	msgPayCash        messageKey = "pay_cash"
+	msgPayMpesa       messageKey = "pay_mpesa"
+	msgPayMpesaNoTill messageKey = "pay_mpesa_no_till"
+	msgCancelled      messageKey = "cancelled"
+	msgNotUnderstood  messageKey = "not_understood"
+	msgOptionMenu     messageKey = "option_menu"
+	msgOptionCheckout messageKey = "option_checkout"
+	msgOptionInhouse  messageKey = "option_inhouse"
+	msgOptionDelivery messageKey = "option_delivery"
+	msgOptionYes      messageKey = "option_yes"
+	msgOptionNo       messageKey = "option_no"
+	msgOptionCash     messageKey = "option_cash"
+	msgOptionMpesa    messageKey = "option_mpesa"
+	msgReceipt        messageKey = "receipt"
+	msgReceiptPlace   messageKey = "receipt_place"
+)
+
+var messages = map[Language]map[messageKey]string{
+	English: {
+		msgWelcome:        "Welcome to %s! Reply \"menu\" to see what we are serving today.",
+		msgHelp:           "Reply \"menu\" to browse, a number to add that item (\"3 2\" adds two of item 3), \"cart\" to see your order, \"checkout\" when done or \"cancel\" to start over.",
+		msgMenu:           "Here is our menu. Reply with an item number to add it to your order, or \"checkout\" when done.",
+		msgMenuEmpty:      "Sorry, there is nothing on the menu right now.",
+		msgAdded:          "Added %d x %s. Your order is now KES %d. Add more or reply \"checkout\".",
+		msgRemoved:        "Removed %s. Your order is now KES %d.",
+		msgNoSuchItem:     "Sorry, I could not find that item. Reply \"menu\" to see the menu.",
+		msgTooMany:        "Sorry, an order can have at most %d of an item.",
+		msgCart:           "Your order:\n%s\nTotal: KES %d",
+		msgCartEmpty:      "Your order is empty. Reply \"menu\" to add items.",
+		msgChoosePlace:    "Will you eat in or should we deliver?",
+		msgAskAddress:     "Where should we deliver your order?",
+		msgConfirm:        "Please confirm your order:\n%s\nTotal: KES %d\n%s",
+		msgOrdered:        "Your order %s has been placed. Total: KES %d.",
+		msgChoosePayment:  "How would you like to pay?",
+		msgPayCash:        "Great, please pay KES %d in cash when you get your order. Asante!",
+		msgPayMpesa:       "Please pay KES %d via M-Pesa to till number %s using account %s. Asante!",
+		msgPayMpesaNoTill: "Please pay KES %d via M-Pesa when you get your order. Asante!",
+		msgCancelled:      "Your order has been cleared. Reply \"menu\" to start again.",
+		msgNotUnderstood:  "Sorry, I did not get that. Reply \"help\" to see what I can do.",
+		msgOptionMenu:     "Menu",
+		msgOptionCheckout: "Checkout",
+		msgOptionInhouse:  "Eat in",
+		msgOptionDelivery: "Delivery",
+		msgOptionYes:      "Confirm",
+		msgOptionNo:       "Change order",
+		msgOptionCash:     "Cash",
+		msgOptionMpesa:    "M-Pesa",
+		msgReceipt:        "Order %s from %s\n%s\nTotal: KES %d\n%s",
+		msgReceiptPlace:   "Served: %s",
+	},
+	Swahili: {
+		msgWelcome:        "Karibu %s! Jibu \"menyu\" kuona chakula cha leo.",
+		msgHelp:           "Jibu \"menyu\" kuona chakula, nambari kuongeza chakula hicho (\"3 2\" inaongeza mbili za nambari 3), \"kikapu\" kuona oda yako, \"maliza\" ukimaliza au \"ghairi\" kuanza upya.",
+		msgMenu:           "Hii ndiyo menyu yetu. Jibu na nambari ya chakula kuongeza kwenye oda, au \"maliza\" ukimaliza.",
+		msgMenuEmpty:      "Samahani, hakuna chakula kwenye menyu sasa hivi.",
+		msgAdded:          "Tumeongeza %d x %s. Oda yako sasa ni KES %d. Ongeza zaidi au jibu \"maliza\".",
+		msgRemoved:        "Tumeondoa %s. Oda yako sasa ni KES %d.",
+		msgNoSuchItem:     "Samahani, sijapata chakula hicho. Jibu \"menyu\" kuona menyu.",
+		msgTooMany:        "Samahani, oda inaweza kuwa na hadi %d za chakula kimoja.",
+		msgCart:           "Oda yako:\n%s\nJumla: KES %d",
+		msgCartEmpty:      "Oda yako haina kitu. Jibu \"menyu\" kuongeza chakula.",
+		msgChoosePlace:    "Utakula hapa au tukuletee?",
+		msgAskAddress:     "Tukuletee oda yako wapi?",
+		msgConfirm:        "Tafadhali thibitisha oda yako:\n%s\nJumla: KES %d\n%s",
+		msgOrdered:        "Oda yako %s imepokelewa. Jumla: KES %d.",
+		msgChoosePayment:  "Ungependa kulipa vipi?",
+		msgPayCash:        "Sawa, tafadhali lipa KES %d pesa taslimu ukipokea oda yako. Asante!",
+		msgPayMpesa:       "Tafadhali lipa KES %d kwa M-Pesa kwa till nambari %s ukitumia akaunti %s. Asante!",
+		msgPayMpesaNoTill: "Tafadhali lipa KES %d kwa M-Pesa ukipokea oda yako. Asante!",
+		msgCancelled:      "Oda yako imefutwa. Jibu \"menyu\" kuanza upya.",
+		msgNotUnderstood:  "Samahani, sijaelewa. Jibu \"msaada\" kuona ninachoweza kufanya.",
+		msgOptionMenu:     "Menyu",
+		msgOptionCheckout: "Maliza",
+		msgOptionInhouse:  "Kula hapa",
+		msgOptionDelivery: "Tuletee",
+		msgOptionYes:      "Thibitisha",
+		msgOptionNo:       "Badilisha oda",
+		msgOptionCash:     "Pesa taslimu",
+		msgOptionMpesa:    "M-Pesa",
+		msgReceipt:        "Oda %s kutoka %s\n%s\nJumla: KES %d\n%s",
+		msgReceiptPlace:   "Huduma: %s",
+	},
+}
+
+// translate renders the message in the given language, falling back to
+// English for unknown languages.
+func translate(lang Language, key messageKey, args ...interface{}) string {
+	templates, ok := messages[lang]
+	if !ok {
+		templates = messages[English]
+	}
+	return fmt.Sprintf(templates[key], args...)
+}
+
+// OrderConfirmation renders an order as a receipt style confirmation
+// message that channels can send alongside or instead of the reply text.
+func OrderConfirmation(lang Language, order orders.Order) string {
+	lines := cartLines(order.Items)
+	if len(order.Items) == 0 {
+		lines = fmt.Sprintf("%s - KES %d", order.Name, order.Price)
+	}
+	place := translate(lang, msgOptionInhouse)
+	if order.Place == "delivery" {
+		place = translate(lang, msgOptionDelivery)
+	}
+	return translate(lang, msgReceipt, order.ID, order.Vendor, lines, order.Price, translate(lang, msgReceiptPlace, place))
+}
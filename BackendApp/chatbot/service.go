@@ -0,0 +1,432 @@
+package chatbot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+const (
+	addressKey       = "address"
+	paymentMethodKey = "payment_method"
+	menuPageSize     = 50
+)
+
+// Config defines the options the chatbot uses when placing orders.
+type Config struct {
+	Vendor string // The vendor orders are placed with.
+	Token  string // The token the bot authenticates to the order and menu services with.
+	Till   string // The M-Pesa till number customers pay to. Optional.
+}
+
+var _ Service = (*chatService)(nil)
+
+type chatService struct {
+	config   Config
+	orders   orders.OrderService
+	menu     menu.Service
+	sessions SessionRepository
+}
+
+// NewService instantiates the chatbot service implementation.
+func NewService(config Config, ordersSvc orders.OrderService, menuSvc menu.Service, sessions SessionRepository) Service {
+	return &chatService{
+		config:   config,
+		orders:   ordersSvc,
+		menu:     menuSvc,
+		sessions: sessions,
+	}
+}
+
+func (svc chatService) Handle(ctx context.Context, msg Message) ([]Reply, error) {
+	if msg.From == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	session, err := svc.session(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd Command
+	switch msg.Payload {
+	case "":
+		cmd = Parse(msg.Text)
+		if cmd.Language != "" {
+			session.Language = cmd.Language
+		}
+	default:
+		// Option IDs are language neutral so they must not switch the
+		// conversation language.
+		cmd = Parse(msg.Payload)
+	}
+
+	replies, err := svc.transition(ctx, &session, cmd)
+	if err != nil {
+		return nil, err
+	}
+	session.UpdatedAt = time.Now()
+	if err := svc.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+	for i := range replies {
+		replies[i].To = msg.From
+		replies[i].Language = session.Language
+	}
+	return replies, nil
+}
+
+func (svc chatService) session(ctx context.Context, msg Message) (Session, error) {
+	id := SessionID(msg.Channel, msg.From)
+	session, err := svc.sessions.Retrieve(ctx, id)
+	switch {
+	case err == nil:
+		return session, nil
+	case errors.Contains(err, errors.ErrNotFound):
+		return Session{
+			ID:       id,
+			Channel:  msg.Channel,
+			User:     msg.From,
+			Language: English,
+			State:    StateIdle,
+		}, nil
+	default:
+		return Session{}, err
+	}
+}
+
+// transition moves the session to its next state for the given command and
+// returns the replies for the user.
+func (svc chatService) transition(ctx context.Context, s *Session, cmd Command) ([]Reply, error) {
+	// Commands that are understood in any state.
+	switch cmd.Intent {
+	case IntentCancel:
+		s.reset()
+		return svc.text(s, msgCancelled), nil
+	case IntentHelp:
+		return svc.text(s, msgHelp), nil
+	case IntentMenu:
+		return svc.showMenu(ctx, s)
+	case IntentCart:
+		return svc.showCart(s), nil
+	case IntentGreet:
+		if s.State == StateIdle || s.State == StatePaying {
+			s.reset()
+			return []Reply{{
+				Text:    translate(s.Language, msgWelcome, svc.config.Vendor),
+				Options: []Option{svc.option(s, "menu", msgOptionMenu)},
+			}}, nil
+		}
+	}
+
+	switch s.State {
+	case StateIdle, StateBrowsing:
+		switch cmd.Intent {
+		case IntentSelect, IntentAdd:
+			return svc.addItem(ctx, s, cmd)
+		case IntentRemove:
+			return svc.removeItem(s, cmd), nil
+		case IntentTooMany:
+			return svc.text(s, msgTooMany, maxQuantity), nil
+		case IntentCheckout, IntentPay:
+			return svc.checkout(s), nil
+		}
+	case StatePlace:
+		switch cmd.Intent {
+		case IntentInhouse:
+			s.Place = "inhouse"
+			return svc.confirm(s), nil
+		case IntentDelivery:
+			s.Place = "delivery"
+			s.State = StateAddress
+			return svc.text(s, msgAskAddress), nil
+		}
+		return svc.checkout(s), nil
+	case StateAddress:
+		if strings.TrimSpace(cmd.Text) == "" {
+			return svc.text(s, msgAskAddress), nil
+		}
+		s.Address = strings.TrimSpace(cmd.Text)
+		return svc.confirm(s), nil
+	case StateConfirming:
+		switch cmd.Intent {
+		case IntentYes:
+			return svc.placeOrder(ctx, s)
+		case IntentNo:
+			s.State = StateBrowsing
+			return svc.showMenu(ctx, s)
+		}
+		return svc.confirm(s), nil
+	case StatePaying:
+		switch cmd.Intent {
+		case IntentCash:
+			return svc.pay(ctx, s, PaymentCash)
+		case IntentMpesa:
+			return svc.pay(ctx, s, PaymentMpesa)
+		case IntentSelect, IntentAdd:
+			// A new order after the previous one was placed.
+			s.reset()
+			return svc.addItem(ctx, s, cmd)
+		case IntentTooMany:
+			return svc.text(s, msgTooMany, maxQuantity), nil
+		}
+		return svc.choosePayment(s), nil
+	}
+	return svc.text(s, msgNotUnderstood), nil
+}
+
+func (svc chatService) showMenu(ctx context.Context, s *Session) ([]Reply, error) {
+	pm := menu.PageMetadata{
+		Limit:         menuPageSize,
+		Vendor:        svc.config.Vendor,
+		OnlyAvailable: true,
+	}
+	page, err := svc.menu.ListItems(ctx, svc.config.Token, pm)
+	if err != nil {
+		return nil, err
+	}
+	if s.State == StateIdle {
+		s.State = StateBrowsing
+	}
+	s.Menu = page.Items
+	if len(page.Items) == 0 {
+		return svc.text(s, msgMenuEmpty), nil
+	}
+	var lines []string
+	var options []Option
+	for i, item := range page.Items {
+		lines = append(lines, fmt.Sprintf("%d. %s - KES %d", i+1, item.Name, item.Price))
+		options = append(options, Option{
+			ID:          strconv.Itoa(i + 1),
+			Title:       item.Name,
+			Description: fmt.Sprintf("KES %d", item.Price),
+		})
+	}
+	return []Reply{{
+		Text:    translate(s.Language, msgMenu) + "\n" + strings.Join(lines, "\n"),
+		Options: options,
+	}}, nil
+}
+
+func (svc chatService) addItem(ctx context.Context, s *Session, cmd Command) ([]Reply, error) {
+	if len(s.Menu) == 0 {
+		if _, err := svc.showMenu(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	item, ok := s.find(cmd)
+	if !ok {
+		return svc.text(s, msgNoSuchItem), nil
+	}
+	s.State = StateBrowsing
+	qty := cmd.Quantity
+	if qty == 0 {
+		qty = 1
+	}
+	// Adding to an item already in the cart must not take it above the cap
+	// either.
+	line := -1
+	for i := range s.Cart {
+		if s.Cart[i].ID == item.ID {
+			line = i
+		}
+	}
+	switch {
+	case line >= 0 && s.Cart[line].Quantity+qty > maxQuantity:
+		return svc.text(s, msgTooMany, maxQuantity), nil
+	case line >= 0:
+		s.Cart[line].Quantity += qty
+	default:
+		s.Cart = append(s.Cart, orders.Item{
+			ID:       item.ID,
+			Name:     item.Name,
+			Quantity: qty,
+			Price:    item.Price,
+		})
+	}
+	return []Reply{{
+		Text:    translate(s.Language, msgAdded, qty, item.Name, s.Total()),
+		Options: []Option{svc.option(s, "menu", msgOptionMenu), svc.option(s, "checkout", msgOptionCheckout)},
+	}}, nil
+}
+
+func (svc chatService) removeItem(s *Session, cmd Command) []Reply {
+	id := ""
+	if item, ok := s.find(cmd); ok {
+		id = item.ID
+	}
+	query := strings.ToLower(cmd.Query)
+	for i := range s.Cart {
+		line := s.Cart[i]
+		if line.ID != id && (query == "" || !strings.Contains(strings.ToLower(line.Name), query)) {
+			continue
+		}
+		switch {
+		case cmd.Quantity > 0 && cmd.Quantity < line.Quantity:
+			s.Cart[i].Quantity -= cmd.Quantity
+		default:
+			s.Cart = append(s.Cart[:i], s.Cart[i+1:]...)
+		}
+		return svc.text(s, msgRemoved, line.Name, s.Total())
+	}
+	return svc.text(s, msgNoSuchItem)
+}
+
+func (svc chatService) showCart(s *Session) []Reply {
+	if len(s.Cart) == 0 {
+		return svc.text(s, msgCartEmpty)
+	}
+	return svc.text(s, msgCart, cartLines(s.Cart), s.Total())
+}
+
+func (svc chatService) checkout(s *Session) []Reply {
+	if len(s.Cart) == 0 {
+		return svc.text(s, msgCartEmpty)
+	}
+	s.State = StatePlace
+	return []Reply{{
+		Text: translate(s.Language, msgChoosePlace),
+		Options: []Option{
+			svc.option(s, "inhouse", msgOptionInhouse),
+			svc.option(s, "delivery", msgOptionDelivery),
+		},
+	}}
+}
+
+func (svc chatService) confirm(s *Session) []Reply {
+	s.State = StateConfirming
+	place := translate(s.Language, msgOptionInhouse)
+	if s.Place == "delivery" {
+		place = fmt.Sprintf("%s: %s", translate(s.Language, msgOptionDelivery), s.Address)
+	}
+	return []Reply{{
+		Text: translate(s.Language, msgConfirm, cartLines(s.Cart), s.Total(), place),
+		Options: []Option{
+			svc.option(s, "yes", msgOptionYes),
+			svc.option(s, "no", msgOptionNo),
+		},
+	}}
+}
+
+func (svc chatService) placeOrder(ctx context.Context, s *Session) ([]Reply, error) {
+	metadata := orders.Metadata{
+		orders.ChannelKey:  s.Channel,
+		orders.CustomerKey: s.User,
+	}
+	if s.Address != "" {
+		metadata[addressKey] = s.Address
+	}
+	order := orders.Order{
+		Vendor:   svc.config.Vendor,
+		Place:    s.Place,
+		Status:   "ordered",
+		Items:    s.Cart,
+		Metadata: metadata,
+	}
+	id, err := svc.orders.CreateOrder(ctx, svc.config.Token, order)
+	if err != nil {
+		return nil, err
+	}
+	created, err := svc.orders.ViewOrder(ctx, svc.config.Token, id)
+	if err != nil {
+		return nil, err
+	}
+	s.OrderID = id
+	s.Cart = nil
+	s.State = StatePaying
+	return []Reply{
+		{
+			Text:  translate(s.Language, msgOrdered, id, created.Price),
+			Order: &created,
+		},
+		svc.choosePayment(s)[0],
+	}, nil
+}
+
+func (svc chatService) choosePayment(s *Session) []Reply {
+	return []Reply{{
+		Text: translate(s.Language, msgChoosePayment),
+		Options: []Option{
+			svc.option(s, PaymentCash, msgOptionCash),
+			svc.option(s, PaymentMpesa, msgOptionMpesa),
+		},
+	}}
+}
+
+func (svc chatService) pay(ctx context.Context, s *Session, method string) ([]Reply, error) {
+	order, err := svc.orders.ViewOrder(ctx, svc.config.Token, s.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Metadata == nil {
+		order.Metadata = orders.Metadata{}
+	}
+	order.Metadata[paymentMethodKey] = method
+	if _, err := svc.orders.UpdateOrder(ctx, svc.config.Token, orders.Order{ID: order.ID, Metadata: order.Metadata}); err != nil {
+		return nil, err
+	}
+	s.reset()
+	switch {
+	case method == PaymentMpesa && svc.config.Till != "":
+		return svc.text(s, msgPayMpesa, order.Price, svc.config.Till, order.ID), nil
+	case method == PaymentMpesa:
+		return svc.text(s, msgPayMpesaNoTill, order.Price), nil
+	default:
+		return svc.text(s, msgPayCash, order.Price), nil
+	}
+}
+
+func (svc chatService) text(s *Session, key messageKey, args ...interface{}) []Reply {
+	return []Reply{{Text: translate(s.Language, key, args...)}}
+}
+
+func (svc chatService) option(s *Session, id string, key messageKey) Option {
+	return Option{ID: id, Title: translate(s.Language, key)}
+}
+
+// reset clears everything but the language so the user can start over.
+func (s *Session) reset() {
+	s.State = StateIdle
+	s.Cart = nil
+	s.Place = ""
+	s.Address = ""
+	s.OrderID = ""
+	s.Menu = nil
+}
+
+// find resolves the menu item a command refers to, either by its position
+// in the last shown menu or by name.
+func (s Session) find(cmd Command) (menu.Item, bool) {
+	if cmd.Index > 0 && cmd.Index <= len(s.Menu) {
+		return s.Menu[cmd.Index-1], true
+	}
+	query := strings.ToLower(cmd.Query)
+	if query == "" {
+		return menu.Item{}, false
+	}
+	for _, item := range s.Menu {
+		if strings.ToLower(item.Name) == query {
+			return item, true
+		}
+	}
+	for _, item := range s.Menu {
+		name := strings.ToLower(item.Name)
+		if strings.Contains(name, query) || strings.Contains(query, name) {
+			return item, true
+		}
+	}
+	return menu.Item{}, false
+}
+
+func cartLines(cart []orders.Item) string {
+	lines := make([]string, 0, len(cart))
+	for _, item := range cart {
+		lines = append(lines, fmt.Sprintf("%d x %s - KES %d", item.Quantity, item.Name, item.Total()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package chatbot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+)
+
+const (
+	token    = "token"
+	vendor   = "mama-oliech"
+	customer = "254700000000"
+)
+
+// menuService lists a fixed menu.
+type menuService struct {
+	menu.Service
+	items []menu.Item
+}
+
+func (svc menuService) ListItems(ctx context.Context, token string, pm menu.PageMetadata) (menu.ItemsPage, error) {
+	return menu.ItemsPage{Items: svc.items}, nil
+}
+
+func TestHandleQuantities(t *testing.T) {
+	ctx := context.Background()
+	ordersSvc := orders.NewOrderService(memory.NewOrderRepo())
+	menuSvc := menuService{items: []menu.Item{
+		{ID: "chapati", Vendor: vendor, Name: "Chapati", Price: 30, Available: true},
+		{ID: "chai", Vendor: vendor, Name: "Chai", Price: 50, Available: true},
+	}}
+	svc := chatbot.NewService(chatbot.Config{Vendor: vendor, Token: token}, ordersSvc, menuSvc, chatbot.NewMemorySessionRepository(time.Hour))
+
+	cases := []struct {
+		desc  string
+		text  string
+		reply string
+	}{
+		{desc: "greet", text: "hi", reply: "Welcome to " + vendor},
+		{desc: "show the menu", text: "menu", reply: "1. Chapati - KES 30"},
+		{desc: "add two chapatis", text: "1 2", reply: "Added 2 x Chapati. Your order is now KES 60."},
+		{desc: "add too many chapatis", text: "1 100", reply: "at most 99 of an item"},
+		{desc: "add too many chapatis by name", text: "add 1000 chapati", reply: "at most 99 of an item"},
+		{desc: "add chapatis above the cap with those in the order", text: "1 98", reply: "at most 99 of an item"},
+		{desc: "add chapatis up to the cap", text: "1 97", reply: "Added 97 x Chapati. Your order is now KES 2970."},
+		{desc: "add a chai", text: "add chai", reply: "Added 1 x Chai. Your order is now KES 3020."},
+		{desc: "check out", text: "checkout", reply: "eat in"},
+		{desc: "eat in", text: "eat in", reply: "99 x Chapati - KES 2970\n1 x Chai - KES 50"},
+		{desc: "confirm", text: "yes", reply: "Total: KES 3020."},
+		{desc: "order too many after ordering", text: "2 5000", reply: "at most 99 of an item"},
+		{desc: "pay", text: "cash", reply: "pay KES 3020 in cash"},
+	}
+	for _, tc := range cases {
+		replies, err := svc.Handle(ctx, chatbot.Message{Channel: "simulator", From: customer, Text: tc.text})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.desc, err)
+		}
+		if len(replies) == 0 || !strings.Contains(replies[0].Text, tc.reply) {
+			t.Fatalf("%s: expected a reply containing %q, got %+v", tc.desc, tc.reply, replies)
+		}
+	}
+
+	page, err := ordersSvc.ListOrders(ctx, token, orders.PageMetadata{Limit: 10})
+	if err != nil {
+		t.Fatalf("listing orders: unexpected error: %s", err)
+	}
+	if len(page.Orders) != 1 {
+		t.Fatalf("expected a single order to be placed, got %d", len(page.Orders))
+	}
+	for _, item := range page.Orders[0].Items {
+		if item.Quantity > 99 {
+			t.Errorf("expected at most 99 of %s to be ordered, got %d", item.Name, item.Quantity)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package simulator provides a local HTTP chatbot channel used to walk
+// through whole conversations without a messaging provider.
+package simulator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+)
+
+// ChannelName is the name the simulator channel registers sessions under.
+const ChannelName = "simulator"
+
+var _ chatbot.Channel = (*Channel)(nil)
+
+// Channel keeps every reply sent to a user in an outbox so it can be read
+// back over HTTP.
+type Channel struct {
+	mu     sync.Mutex
+	outbox map[string][]chatbot.Reply
+}
+
+// NewChannel instantiates the simulator channel.
+func NewChannel() *Channel {
+	return &Channel{
+		outbox: make(map[string][]chatbot.Reply),
+	}
+}
+
+// Name returns the name of the simulator channel.
+func (ch *Channel) Name() string {
+	return ChannelName
+}
+
+// Send appends the reply to the user's outbox.
+func (ch *Channel) Send(_ context.Context, reply chatbot.Reply) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	ch.outbox[reply.To] = append(ch.outbox[reply.To], reply)
+	return nil
+}
+
+// Outbox returns and clears every reply sent to the user.
+func (ch *Channel) Outbox(user string) []chatbot.Reply {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	replies := ch.outbox[user]
+	delete(ch.outbox, user)
+	return replies
+}
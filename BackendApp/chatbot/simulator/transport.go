@@ -0,0 +1,144 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/go-kit/kit/endpoint"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const contentType = "application/json"
+
+type sendMessageReq struct {
+	From    string `json:"from"`
+	Name    string `json:"name,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Payload string `json:"payload,omitempty"`
+}
+
+func (req sendMessageReq) validate() error {
+	if req.From == "" {
+		return errors.ErrMalformedEntity
+	}
+	if req.Text == "" && req.Payload == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type outboxReq struct {
+	user string
+}
+
+type repliesRes struct {
+	Replies []chatbot.Reply `json:"replies"`
+}
+
+// MakeHandler registers the simulator HTTP endpoints on the router:
+//
+//	POST /chatbot/simulator/messages       sends a message as a user and returns the bot's replies
+//	GET  /chatbot/simulator/messages/{user} returns and clears the replies sent to the user
+func MakeHandler(svc chatbot.Service, ch *Channel, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/chatbot/simulator/messages").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint simulator_send_message")(sendMessageEndpoint(svc, ch)),
+		decodeSendMessage,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/chatbot/simulator/messages/{user}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint simulator_outbox")(outboxEndpoint(ch)),
+		decodeOutbox,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func sendMessageEndpoint(svc chatbot.Service, ch *Channel) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sendMessageReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		msg := chatbot.Message{
+			Channel: ChannelName,
+			From:    req.From,
+			Name:    req.Name,
+			Text:    req.Text,
+			Payload: req.Payload,
+			SentAt:  time.Now(),
+		}
+		replies, err := chatbot.Dispatch(ctx, svc, ch, msg)
+		if err != nil {
+			return nil, err
+		}
+		// The replies are returned inline so drain them from the outbox.
+		ch.Outbox(req.From)
+		return repliesRes{Replies: replies}, nil
+	}
+}
+
+func outboxEndpoint(ch *Channel) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(outboxReq)
+		replies := ch.Outbox(req.user)
+		if replies == nil {
+			replies = []chatbot.Reply{}
+		}
+		return repliesRes{Replies: replies}, nil
+	}
+}
+
+func decodeSendMessage(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var req sendMessageReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeOutbox(_ context.Context, r *http.Request) (interface{}, error) {
+	return outboxReq{user: mux.Vars(r)["user"]}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+var _ chatbot.Channel = (*Channel)(nil)
+
+// Channel sends chatbot replies through the Telegram Bot API.
+type Channel struct {
+	config Config
+	client *http.Client
+}
+
+// NewChannel instantiates the Telegram channel. If client is nil
+// http.DefaultClient is used.
+func NewChannel(config Config, client *http.Client) *Channel {
+	if config.URL == "" {
+		config.URL = DefaultURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Channel{
+		config: config,
+		client: client,
+	}
+}
+
+// Name returns the name of the Telegram channel.
+func (ch *Channel) Name() string {
+	return ChannelName
+}
+
+// Send delivers the reply as a message with an inline keyboard holding the
+// reply options.
+func (ch *Channel) Send(ctx context.Context, reply chatbot.Reply) error {
+	return ch.call(ctx, "sendMessage", BuildMessage(reply))
+}
+
+// AnswerCallback acknowledges an inline keyboard press so the client stops
+// showing the loading indicator.
+func (ch *Channel) AnswerCallback(ctx context.Context, id string) error {
+	return ch.call(ctx, "answerCallbackQuery", AnswerCallbackQueryReq{CallbackQueryID: id})
+}
+
+// SetWebhook registers url as the bot webhook. Telegram sends the configured
+// secret in every webhook request so the handler can authenticate it.
+func (ch *Channel) SetWebhook(ctx context.Context, url string) error {
+	return ch.call(ctx, "setWebhook", SetWebhookReq{
+		URL:            url,
+		SecretToken:    ch.config.Secret,
+		AllowedUpdates: []string{"message", "callback_query"},
+	})
+}
+
+func (ch *Channel) call(ctx context.Context, method string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	url := fmt.Sprintf("%s/bot%s/%s", strings.TrimSuffix(ch.config.URL, "/"), ch.config.Token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := ch.client.Do(req)
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	defer res.Body.Close()
+
+	var r Response
+	data, _ := io.ReadAll(res.Body)
+	if err := json.Unmarshal(data, &r); err != nil {
+		return errors.Wrap(chatbot.ErrSend, fmt.Errorf("telegram responded with %s", res.Status))
+	}
+	if !r.OK {
+		return errors.Wrap(chatbot.ErrSend, fmt.Errorf("telegram error %d: %s", r.ErrorCode, r.Description))
+	}
+	return nil
+}
+
+// BuildMessage converts a chatbot reply to a sendMessage request. Options
+// become inline keyboard buttons, three per row, and replies that confirm an
+// order carry the order receipt.
+func BuildMessage(reply chatbot.Reply) SendMessageReq {
+	text := reply.Text
+	if reply.Order != nil {
+		text = fmt.Sprintf("%s\n\n%s", text, chatbot.OrderConfirmation(reply.Language, *reply.Order))
+	}
+	msg := SendMessageReq{
+		ChatID: reply.To,
+		Text:   chatbot.Truncate(text, maxTextBody),
+	}
+	if len(reply.Options) == 0 {
+		return msg
+	}
+
+	var keyboard [][]InlineKeyboardButton
+	var row []InlineKeyboardButton
+	for _, opt := range reply.Options {
+		// Callback data is limited to 64 bytes, option IDs are ULIDs or
+		// short commands so they always fit.
+		row = append(row, InlineKeyboardButton{
+			Text:         opt.Title,
+			CallbackData: truncateBytes(opt.ID, maxCallbackData),
+		})
+		if len(row) == maxInlineRowLength {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+	msg.ReplyMarkup = &InlineKeyboardMarkup{InlineKeyboard: keyboard}
+	return msg
+}
+
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,100 @@
+// Package telegram contains the Telegram Bot API chatbot channel: a webhook
+// handler for inbound updates and a sender for replies.
+package telegram
+
+// ChannelName is the name the Telegram channel registers sessions under.
+const ChannelName = "telegram"
+
+// DefaultURL is the Bot API base URL the channel sends messages through.
+const DefaultURL = "https://api.telegram.org"
+
+const (
+	secretHeader       = "X-Telegram-Bot-Api-Secret-Token"
+	maxTextBody        = 4096
+	maxCallbackData    = 64
+	maxInlineRowLength = 3
+)
+
+// Config defines the options used to talk to the Telegram Bot API.
+type Config struct {
+	URL    string // The Bot API base URL. Defaults to DefaultURL.
+	Token  string // The bot token issued by BotFather.
+	Secret string // The secret Telegram echoes in every webhook request.
+}
+
+// Update is an incoming update posted to the webhook.
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// Message is a message sent to the bot.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Date      int64  `json:"date"`
+	Text      string `json:"text,omitempty"`
+}
+
+// User is a Telegram user or bot.
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+// Chat is the conversation a message belongs to.
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// CallbackQuery is sent when a user presses an inline keyboard button.
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// SendMessageReq is the body of a sendMessage request.
+type SendMessageReq struct {
+	ChatID      string                `json:"chat_id"`
+	Text        string                `json:"text"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// InlineKeyboardMarkup is a keyboard attached to a message.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton is a single keyboard button.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// AnswerCallbackQueryReq is the body of an answerCallbackQuery request.
+type AnswerCallbackQueryReq struct {
+	CallbackQueryID string `json:"callback_query_id"`
+}
+
+// SetWebhookReq is the body of a setWebhook request.
+type SetWebhookReq struct {
+	URL            string   `json:"url"`
+	SecretToken    string   `json:"secret_token,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// Response is the envelope of every Bot API response.
+type Response struct {
+	OK          bool        `json:"ok"`
+	Result      interface{} `json:"result,omitempty"`
+	ErrorCode   int         `json:"error_code,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
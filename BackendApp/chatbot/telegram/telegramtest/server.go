@@ -0,0 +1,169 @@
+// Package telegramtest provides a local fake of the Telegram Bot API so the
+// Telegram channel can be exercised without reaching Telegram.
+package telegramtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/telegram"
+)
+
+// Server mimics the sendMessage, answerCallbackQuery and setWebhook methods
+// of the Bot API and keeps every request it accepts.
+type Server struct {
+	*httptest.Server
+
+	token string
+
+	mu        sync.Mutex
+	sent      []telegram.SendMessageReq
+	callbacks []string
+	webhook   telegram.SetWebhookReq
+}
+
+// NewServer starts a fake Bot API for the bot token. Close it when done.
+func NewServer(token string) *Server {
+	s := &Server{token: token}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns a channel configuration pointing at the fake server.
+func (s *Server) Config(secret string) telegram.Config {
+	return telegram.Config{
+		URL:    s.URL,
+		Token:  s.token,
+		Secret: secret,
+	}
+}
+
+// Messages returns every message sent through the fake so far.
+func (s *Server) Messages() []telegram.SendMessageReq {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]telegram.SendMessageReq(nil), s.sent...)
+}
+
+// Callbacks returns the IDs of every answered callback query.
+func (s *Server) Callbacks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.callbacks...)
+}
+
+// Webhook returns the last webhook registration.
+func (s *Server) Webhook() telegram.SetWebhookReq {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.webhook
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/bot" + s.token + "/"
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, prefix) {
+		writeResponse(w, http.StatusUnauthorized, telegram.Response{ErrorCode: 401, Description: "Unauthorized"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.TrimPrefix(r.URL.Path, prefix) {
+	case "sendMessage":
+		var req telegram.SendMessageReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChatID == "" || req.Text == "" {
+			writeResponse(w, http.StatusBadRequest, telegram.Response{ErrorCode: 400, Description: "Bad Request: message text is empty"})
+			return
+		}
+		s.sent = append(s.sent, req)
+		writeResponse(w, http.StatusOK, telegram.Response{OK: true, Result: map[string]interface{}{
+			"message_id": len(s.sent),
+			"date":       time.Now().Unix(),
+			"text":       req.Text,
+		}})
+	case "answerCallbackQuery":
+		var req telegram.AnswerCallbackQueryReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CallbackQueryID == "" {
+			writeResponse(w, http.StatusBadRequest, telegram.Response{ErrorCode: 400, Description: "Bad Request: query is too old"})
+			return
+		}
+		s.callbacks = append(s.callbacks, req.CallbackQueryID)
+		writeResponse(w, http.StatusOK, telegram.Response{OK: true, Result: true})
+	case "setWebhook":
+		var req telegram.SetWebhookReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeResponse(w, http.StatusBadRequest, telegram.Response{ErrorCode: 400, Description: "Bad Request"})
+			return
+		}
+		s.webhook = req
+		writeResponse(w, http.StatusOK, telegram.Response{OK: true, Result: true, Description: "Webhook was set"})
+	default:
+		writeResponse(w, http.StatusNotFound, telegram.Response{ErrorCode: 404, Description: "Not Found"})
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, res telegram.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// TextUpdate builds the update Telegram posts when a user sends a text
+// message in a private chat.
+func TextUpdate(updateID, chatID int64, name, text string) telegram.Update {
+	return telegram.Update{
+		UpdateID: updateID,
+		Message: &telegram.Message{
+			MessageID: updateID,
+			From:      &telegram.User{ID: chatID, FirstName: name},
+			Chat:      telegram.Chat{ID: chatID, Type: "private"},
+			Date:      time.Now().Unix(),
+			Text:      text,
+		},
+	}
+}
+
+// CallbackUpdate builds the update Telegram posts when a user presses an
+// inline keyboard button.
+func CallbackUpdate(updateID, chatID int64, name, data string) telegram.Update {
+	return telegram.Update{
+		UpdateID: updateID,
+		CallbackQuery: &telegram.CallbackQuery{
+			ID:   "cb" + strconv.FormatInt(updateID, 10),
+			From: telegram.User{ID: chatID, FirstName: name},
+			Message: &telegram.Message{
+				MessageID: updateID,
+				Chat:      telegram.Chat{ID: chatID, Type: "private"},
+				Date:      time.Now().Unix(),
+			},
+			Data: data,
+		},
+	}
+}
+
+// Deliver posts the update to the webhook URL with the secret header, the
+// way Telegram does.
+func Deliver(ctx context.Context, webhookURL, secret string, u telegram.Update) (*http.Response, error) {
+	body, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,164 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/go-kit/kit/endpoint"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxBodySize     = 1 << 20
+	webhookPath     = "/chatbot/telegram/webhook"
+	jsonContentType = "application/json"
+)
+
+type webhookReq struct {
+	update Update
+}
+
+type webhookRes struct {
+	Received int `json:"received"`
+}
+
+// MakeHandler registers the Telegram webhook on the router. Updates are
+// authenticated with the configured secret, handled by svc and answered
+// over ch.
+func MakeHandler(svc chatbot.Service, ch *Channel, dedup chatbot.Deduplicator, config Config, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path(webhookPath).Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint telegram_webhook")(webhookEndpoint(svc, ch, dedup, logger)),
+		decodeWebhook(config),
+		encodeResponse,
+		opts...,
+	))
+}
+
+func webhookEndpoint(svc chatbot.Service, ch *Channel, dedup chatbot.Deduplicator, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(webhookReq)
+		msg, ok := toMessage(req.update)
+		if !ok {
+			return webhookRes{}, nil
+		}
+		if cq := req.update.CallbackQuery; cq != nil {
+			if err := ch.AnswerCallback(ctx, cq.ID); err != nil {
+				logger.Log("message", "failed to answer telegram callback", "id", cq.ID, "error", err)
+			}
+		}
+
+		first, err := dedup.Claim(ctx, msg.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			return webhookRes{}, nil
+		}
+		if _, err := chatbot.Dispatch(ctx, svc, ch, msg); err != nil {
+			// Let Telegram redeliver the update.
+			if rerr := dedup.Release(ctx, msg.ID); rerr != nil {
+				logger.Log("message", "failed to release telegram update", "id", msg.ID, "error", rerr)
+			}
+			return nil, err
+		}
+		return webhookRes{Received: 1}, nil
+	}
+}
+
+func decodeWebhook(config Config) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		if !Verify(config.Secret, r.Header.Get(secretHeader)) {
+			return nil, chatbot.ErrSignature
+		}
+		if !strings.Contains(r.Header.Get("Content-Type"), jsonContentType) {
+			return nil, errors.ErrUnsupportedContentType
+		}
+		var u Update
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&u); err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return webhookReq{update: u}, nil
+	}
+}
+
+// toMessage converts an update to a chatbot message. Replies are sent to the
+// chat the update came from. Updates without text or callback data are
+// skipped.
+func toMessage(u Update) (chatbot.Message, bool) {
+	msg := chatbot.Message{
+		ID:      ChannelName + ":" + strconv.FormatInt(u.UpdateID, 10),
+		Channel: ChannelName,
+	}
+	switch {
+	case u.Message != nil && u.Message.Text != "":
+		msg.From = strconv.FormatInt(u.Message.Chat.ID, 10)
+		msg.Text = u.Message.Text
+		msg.SentAt = time.Unix(u.Message.Date, 0)
+		if u.Message.From != nil {
+			msg.Name = fullName(*u.Message.From)
+		}
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		msg.From = strconv.FormatInt(u.CallbackQuery.Message.Chat.ID, 10)
+		msg.Payload = u.CallbackQuery.Data
+		msg.Name = fullName(u.CallbackQuery.From)
+		msg.SentAt = time.Now()
+	default:
+		return chatbot.Message{}, false
+	}
+	return msg, true
+}
+
+func fullName(u User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+// Verify reports whether the secret header matches the configured secret.
+func Verify(secret, header string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(header)) == 1
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", jsonContentType)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", jsonContentType)
+	switch {
+	case errors.Contains(err, chatbot.ErrSignature):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
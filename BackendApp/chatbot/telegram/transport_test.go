@@ -0,0 +1,152 @@
+package telegram_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/telegram"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/telegram/telegramtest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	token       = "123:token"
+	secret      = "webhook-secret"
+	webhookPath = "/chatbot/telegram/webhook"
+	chatID      = 4242
+)
+
+// echoService answers every message with its text. It fails the first
+// messages it is told to.
+type echoService struct {
+	mu      sync.Mutex
+	fail    int
+	handled []chatbot.Message
+}
+
+func (svc *echoService) Handle(ctx context.Context, msg chatbot.Message) ([]chatbot.Reply, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.fail > 0 {
+		svc.fail--
+		return nil, errors.ErrViewEntity
+	}
+	svc.handled = append(svc.handled, msg)
+	text := msg.Text
+	if text == "" {
+		text = msg.Payload
+	}
+	return []chatbot.Reply{{To: msg.From, Text: text}}, nil
+}
+
+func newWebhook(t *testing.T, svc chatbot.Service) (*telegramtest.Server, *httptest.Server) {
+	fake := telegramtest.NewServer(token)
+	t.Cleanup(fake.Close)
+	config := fake.Config(secret)
+	r := mux.NewRouter()
+	telegram.MakeHandler(svc, telegram.NewChannel(config, nil), chatbot.NewMemoryDeduplicator(time.Hour), config, r, kitlog.NewNopLogger())
+	webhook := httptest.NewServer(r)
+	t.Cleanup(webhook.Close)
+	return fake, webhook
+}
+
+func TestWebhookSecret(t *testing.T) {
+	cases := []struct {
+		desc   string
+		secret string
+		status int
+		sent   int
+	}{
+		{desc: "with the secret", secret: secret, status: http.StatusOK, sent: 1},
+		{desc: "with another secret", secret: "other-secret", status: http.StatusUnauthorized},
+		{desc: "without a secret", secret: "", status: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fake, webhook := newWebhook(t, &echoService{})
+			res, err := telegramtest.Deliver(context.Background(), webhook.URL+webhookPath, tc.secret, telegramtest.TextUpdate(1, chatID, "Wanjiku", "menu"))
+			if err != nil {
+				t.Fatalf("delivering: unexpected error: %s", err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, res.StatusCode)
+			}
+			if sent := len(fake.Messages()); sent != tc.sent {
+				t.Errorf("expected %d replies to be sent, got %d", tc.sent, sent)
+			}
+		})
+	}
+}
+
+func TestWebhookRedelivery(t *testing.T) {
+	svc := &echoService{fail: 1}
+	fake, webhook := newWebhook(t, svc)
+	deliver := func(u telegram.Update) int {
+		t.Helper()
+		res, err := telegramtest.Deliver(context.Background(), webhook.URL+webhookPath, secret, u)
+		if err != nil {
+			t.Fatalf("delivering update %d: unexpected error: %s", u.UpdateID, err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	// An update that failed to be handled is handled when redelivered.
+	if status := deliver(telegramtest.TextUpdate(1, chatID, "Wanjiku", "menu")); status != http.StatusInternalServerError {
+		t.Fatalf("expected the failed update to be answered with %d, got %d", http.StatusInternalServerError, status)
+	}
+	if status := deliver(telegramtest.TextUpdate(1, chatID, "Wanjiku", "menu")); status != http.StatusOK {
+		t.Fatalf("expected the redelivered update to be answered with %d, got %d", http.StatusOK, status)
+	}
+	// One that was handled is not handled again.
+	if status := deliver(telegramtest.TextUpdate(1, chatID, "Wanjiku", "menu")); status != http.StatusOK {
+		t.Fatalf("expected the repeated update to be answered with %d, got %d", http.StatusOK, status)
+	}
+	// Button presses are answered whether or not they were seen before.
+	for i := 0; i < 2; i++ {
+		if status := deliver(telegramtest.CallbackUpdate(2, chatID, "Wanjiku", "item:1")); status != http.StatusOK {
+			t.Fatalf("expected the button press to be answered with %d, got %d", http.StatusOK, status)
+		}
+	}
+
+	if len(svc.handled) != 2 {
+		t.Errorf("expected 2 updates to be handled, got %d", len(svc.handled))
+	}
+	sent := fake.Messages()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 replies to be sent, got %d", len(sent))
+	}
+	if sent[1].Text != "item:1" {
+		t.Errorf("expected the button press to be handled with its data, got %q", sent[1].Text)
+	}
+	if callbacks := fake.Callbacks(); len(callbacks) != 2 || callbacks[0] != "cb2" {
+		t.Errorf("expected the button presses to be answered twice, got %v", callbacks)
+	}
+}
+
+func TestSetWebhook(t *testing.T) {
+	fake := telegramtest.NewServer(token)
+	defer fake.Close()
+
+	if err := telegram.NewChannel(fake.Config(secret), nil).SetWebhook(context.Background(), "https://jikoni.example"+webhookPath); err != nil {
+		t.Fatalf("setting the webhook: unexpected error: %s", err)
+	}
+	if got := fake.Webhook(); got.URL != "https://jikoni.example"+webhookPath || got.SecretToken != secret {
+		t.Errorf("expected the webhook to be registered with its secret, got %+v", got)
+	}
+
+	// The fake refuses bots with another token.
+	config := fake.Config(secret)
+	config.Token = "456:other"
+	if err := telegram.NewChannel(config, nil).Send(context.Background(), chatbot.Reply{To: "4242", Text: "Karibu"}); !errors.Contains(err, chatbot.ErrSend) {
+		t.Errorf("expected error %v, got %v", chatbot.ErrSend, err)
+	}
+}
@@ -0,0 +1,136 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+var _ chatbot.Channel = (*Channel)(nil)
+
+// Channel sends chatbot replies through the WhatsApp Cloud API.
+type Channel struct {
+	config Config
+	client *http.Client
+}
+
+// NewChannel instantiates the WhatsApp channel. If client is nil
+// http.DefaultClient is used.
+func NewChannel(config Config, client *http.Client) *Channel {
+	if config.URL == "" {
+		config.URL = DefaultURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Channel{
+		config: config,
+		client: client,
+	}
+}
+
+// Name returns the name of the WhatsApp channel.
+func (ch *Channel) Name() string {
+	return ChannelName
+}
+
+// Send delivers the reply as a text, button or list message depending on
+// how many options it carries.
+func (ch *Channel) Send(ctx context.Context, reply chatbot.Reply) error {
+	return ch.send(ctx, BuildMessage(reply))
+}
+
+func (ch *Channel) send(ctx context.Context, msg OutboundMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	url := fmt.Sprintf("%s/%s/messages", strings.TrimSuffix(ch.config.URL, "/"), ch.config.PhoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ch.config.Token)
+
+	res, err := ch.client.Do(req)
+	if err != nil {
+		return errors.Wrap(chatbot.ErrSend, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		var er ErrorResponse
+		data, _ := io.ReadAll(res.Body)
+		if err := json.Unmarshal(data, &er); err != nil || er.Error.Message == "" {
+			return errors.Wrap(chatbot.ErrSend, fmt.Errorf("whatsapp responded with %s", res.Status))
+		}
+		return errors.Wrap(chatbot.ErrSend, fmt.Errorf("whatsapp error %d: %s", er.Error.Code, er.Error.Message))
+	}
+	return nil
+}
+
+// BuildMessage converts a chatbot reply to a Cloud API message. Replies with
+// up to three options become reply buttons, longer ones become a list and
+// replies that confirm an order carry the order receipt.
+func BuildMessage(reply chatbot.Reply) OutboundMessage {
+	text := reply.Text
+	if reply.Order != nil {
+		text = fmt.Sprintf("%s\n\n%s", text, chatbot.OrderConfirmation(reply.Language, *reply.Order))
+	}
+	msg := OutboundMessage{
+		MessagingProduct: messagingProduct,
+		RecipientType:    "individual",
+		To:               reply.To,
+	}
+
+	switch {
+	case len(reply.Options) == 0:
+		msg.Type = "text"
+		msg.Text = &Text{Body: chatbot.Truncate(text, maxTextBody)}
+	case len(reply.Options) <= maxButtons:
+		var buttons []Button
+		for _, opt := range reply.Options {
+			buttons = append(buttons, Button{
+				Type:  "reply",
+				Reply: Reply{ID: opt.ID, Title: chatbot.Truncate(opt.Title, maxButtonTitle)},
+			})
+		}
+		msg.Type = "interactive"
+		msg.Interactive = &Interactive{
+			Type:   "button",
+			Body:   Text{Body: chatbot.Truncate(text, maxInteractiveBody)},
+			Action: Action{Buttons: buttons},
+		}
+	default:
+		// Lists hold at most ten rows, the full menu is still numbered in the
+		// body so entries past the tenth can be typed.
+		var rows []Reply
+		for i, opt := range reply.Options {
+			if i == maxRows {
+				break
+			}
+			rows = append(rows, Reply{
+				ID:          opt.ID,
+				Title:       chatbot.Truncate(opt.Title, maxRowTitle),
+				Description: chatbot.Truncate(opt.Description, maxRowDescription),
+			})
+		}
+		msg.Type = "interactive"
+		msg.Interactive = &Interactive{
+			Type: "list",
+			Body: Text{Body: chatbot.Truncate(text, maxInteractiveBody)},
+			Action: Action{
+				Button:   listButtonTitle,
+				Sections: []Section{{Title: listSectionTitle, Rows: rows}},
+			},
+		}
+	}
+	return msg
+}
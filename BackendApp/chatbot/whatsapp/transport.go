@@ -0,0 +1,233 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/go-kit/kit/endpoint"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxBodySize     = 1 << 20
+	modeKey         = "hub.mode"
+	verifyTokenKey  = "hub.verify_token"
+	challengeKey    = "hub.challenge"
+	webhookPath     = "/chatbot/whatsapp/webhook"
+	jsonContentType = "application/json"
+)
+
+type challengeReq struct {
+	mode      string
+	token     string
+	challenge string
+}
+
+type challengeRes struct {
+	challenge string
+}
+
+type webhookReq struct {
+	messages []chatbot.Message
+}
+
+type webhookRes struct {
+	Received int `json:"received"`
+}
+
+// MakeHandler registers the WhatsApp webhook on the router. GET requests
+// answer the subscription challenge and POST requests carry signed
+// notifications whose messages are handled by svc and answered over ch.
+func MakeHandler(svc chatbot.Service, ch *Channel, dedup chatbot.Deduplicator, config Config, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("GET").Path(webhookPath).Handler(kithttp.NewServer(
+		challengeEndpoint(config),
+		decodeChallenge,
+		encodeChallenge,
+		opts...,
+	))
+
+	r.Methods("POST").Path(webhookPath).Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint whatsapp_webhook")(webhookEndpoint(svc, ch, dedup, logger)),
+		decodeWebhook(config),
+		encodeResponse,
+		opts...,
+	))
+}
+
+func challengeEndpoint(config Config) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(challengeReq)
+		if req.mode != subscribeMode || config.VerifyToken == "" ||
+			!hmac.Equal([]byte(req.token), []byte(config.VerifyToken)) {
+			return nil, errors.ErrAuthorization
+		}
+		return challengeRes{challenge: req.challenge}, nil
+	}
+}
+
+func webhookEndpoint(svc chatbot.Service, ch *Channel, dedup chatbot.Deduplicator, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(webhookReq)
+		received := 0
+		for _, msg := range req.messages {
+			first, err := dedup.Claim(ctx, msg.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !first {
+				continue
+			}
+			if _, err := chatbot.Dispatch(ctx, svc, ch, msg); err != nil {
+				// Let the provider redeliver the message.
+				if rerr := dedup.Release(ctx, msg.ID); rerr != nil {
+					logger.Log("message", "failed to release whatsapp message", "id", msg.ID, "error", rerr)
+				}
+				return nil, err
+			}
+			received++
+		}
+		return webhookRes{Received: received}, nil
+	}
+}
+
+func decodeChallenge(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	return challengeReq{
+		mode:      q.Get(modeKey),
+		token:     q.Get(verifyTokenKey),
+		challenge: q.Get(challengeKey),
+	}, nil
+}
+
+func decodeWebhook(config Config) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		if !Verify(config.AppSecret, body, r.Header.Get(signatureHeader)) {
+			return nil, chatbot.ErrSignature
+		}
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return webhookReq{messages: toMessages(n)}, nil
+	}
+}
+
+// toMessages flattens a notification into chatbot messages. Delivery status
+// updates and unsupported message types such as images are skipped.
+func toMessages(n Notification) []chatbot.Message {
+	var msgs []chatbot.Message
+	for _, entry := range n.Entry {
+		for _, change := range entry.Changes {
+			names := map[string]string{}
+			for _, contact := range change.Value.Contacts {
+				names[contact.WaID] = contact.Profile.Name
+			}
+			for _, in := range change.Value.Messages {
+				msg := chatbot.Message{
+					ID:      in.ID,
+					Channel: ChannelName,
+					From:    in.From,
+					Name:    names[in.From],
+					SentAt:  parseTimestamp(in.Timestamp),
+				}
+				switch {
+				case in.Text != nil:
+					msg.Text = in.Text.Body
+				case in.Interactive != nil && in.Interactive.ButtonReply != nil:
+					msg.Payload = in.Interactive.ButtonReply.ID
+					msg.Text = in.Interactive.ButtonReply.Title
+				case in.Interactive != nil && in.Interactive.ListReply != nil:
+					msg.Payload = in.Interactive.ListReply.ID
+					msg.Text = in.Interactive.ListReply.Title
+				case in.Button != nil:
+					msg.Payload = in.Button.Payload
+					msg.Text = in.Button.Text
+				default:
+					continue
+				}
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	return msgs
+}
+
+func parseTimestamp(ts string) time.Time {
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Unix(secs, 0)
+}
+
+// Sign returns the X-Hub-Signature-256 header value for the body.
+func Sign(appSecret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header matches the body.
+func Verify(appSecret string, body []byte, signature string) bool {
+	if appSecret == "" || !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(appSecret, body)), []byte(signature))
+}
+
+func encodeChallenge(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(challengeRes)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, err := io.WriteString(w, res.challenge)
+	return err
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", jsonContentType)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", jsonContentType)
+	switch {
+	case errors.Contains(err, chatbot.ErrSignature):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errors.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package whatsapp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/whatsapp"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/whatsapp/whatsapptest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	phoneNumberID = "1234"
+	token         = "token"
+	appSecret     = "app-secret"
+	verifyToken   = "verify-token"
+	webhookPath   = "/chatbot/whatsapp/webhook"
+	customer      = "254700000000"
+)
+
+// echoService answers every message with its text. It fails the first
+// messages it is told to.
+type echoService struct {
+	mu      sync.Mutex
+	fail    int
+	handled []chatbot.Message
+}
+
+func (svc *echoService) Handle(ctx context.Context, msg chatbot.Message) ([]chatbot.Reply, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.fail > 0 {
+		svc.fail--
+		return nil, errors.ErrViewEntity
+	}
+	svc.handled = append(svc.handled, msg)
+	return []chatbot.Reply{{To: msg.From, Text: msg.Text}}, nil
+}
+
+func newWebhook(t *testing.T, svc chatbot.Service) (*whatsapptest.Server, *httptest.Server) {
+	fake := whatsapptest.NewServer(phoneNumberID, token)
+	t.Cleanup(fake.Close)
+	config := fake.Config(appSecret, verifyToken)
+	r := mux.NewRouter()
+	whatsapp.MakeHandler(svc, whatsapp.NewChannel(config, nil), chatbot.NewMemoryDeduplicator(time.Hour), config, r, kitlog.NewNopLogger())
+	webhook := httptest.NewServer(r)
+	t.Cleanup(webhook.Close)
+	return fake, webhook
+}
+
+func TestWebhookSignature(t *testing.T) {
+	cases := []struct {
+		desc   string
+		secret string
+		status int
+		sent   int
+	}{
+		{desc: "signed with the app secret", secret: appSecret, status: http.StatusOK, sent: 1},
+		{desc: "signed with another secret", secret: "other-secret", status: http.StatusUnauthorized},
+		{desc: "signed without a secret", secret: "", status: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fake, webhook := newWebhook(t, &echoService{})
+			res, err := whatsapptest.Deliver(context.Background(), webhook.URL+webhookPath, tc.secret, whatsapptest.TextMessage("wamid.1", customer, "Wanjiku", "menu"))
+			if err != nil {
+				t.Fatalf("delivering: unexpected error: %s", err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, res.StatusCode)
+			}
+			if sent := len(fake.Messages()); sent != tc.sent {
+				t.Errorf("expected %d replies to be sent, got %d", tc.sent, sent)
+			}
+		})
+	}
+}
+
+func TestWebhookChallenge(t *testing.T) {
+	cases := []struct {
+		desc   string
+		mode   string
+		token  string
+		status int
+	}{
+		{desc: "with the verify token", mode: "subscribe", token: verifyToken, status: http.StatusOK},
+		{desc: "with another token", mode: "subscribe", token: "other-token", status: http.StatusForbidden},
+		{desc: "without a token", mode: "subscribe", status: http.StatusForbidden},
+		{desc: "with another mode", mode: "unsubscribe", token: verifyToken, status: http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, webhook := newWebhook(t, &echoService{})
+			q := url.Values{}
+			q.Set("hub.mode", tc.mode)
+			q.Set("hub.verify_token", tc.token)
+			q.Set("hub.challenge", "1158201444")
+			res, err := http.Get(webhook.URL + webhookPath + "?" + q.Encode())
+			if err != nil {
+				t.Fatalf("subscribing: unexpected error: %s", err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, res.StatusCode)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("reading the challenge: unexpected error: %s", err)
+			}
+			if string(body) != "1158201444" {
+				t.Errorf("expected the challenge to be echoed, got %q", body)
+			}
+		})
+	}
+}
+
+func TestWebhookRedelivery(t *testing.T) {
+	svc := &echoService{fail: 1}
+	fake, webhook := newWebhook(t, svc)
+	deliver := func(id, text string) int {
+		t.Helper()
+		res, err := whatsapptest.Deliver(context.Background(), webhook.URL+webhookPath, appSecret, whatsapptest.TextMessage(id, customer, "Wanjiku", text))
+		if err != nil {
+			t.Fatalf("delivering %s: unexpected error: %s", id, err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	// A message that failed to be handled is handled when redelivered.
+	if status := deliver("wamid.1", "menu"); status != http.StatusInternalServerError {
+		t.Fatalf("expected the failed message to be answered with %d, got %d", http.StatusInternalServerError, status)
+	}
+	if status := deliver("wamid.1", "menu"); status != http.StatusOK {
+		t.Fatalf("expected the redelivered message to be answered with %d, got %d", http.StatusOK, status)
+	}
+	// One that was handled is not handled again.
+	if status := deliver("wamid.1", "menu"); status != http.StatusOK {
+		t.Fatalf("expected the repeated message to be answered with %d, got %d", http.StatusOK, status)
+	}
+	if status := deliver("wamid.2", "1"); status != http.StatusOK {
+		t.Fatalf("expected the next message to be answered with %d, got %d", http.StatusOK, status)
+	}
+
+	if len(svc.handled) != 2 {
+		t.Errorf("expected 2 messages to be handled, got %d", len(svc.handled))
+	}
+	sent := fake.Messages()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 replies to be sent, got %d", len(sent))
+	}
+	if sent[0].To != customer {
+		t.Errorf("expected the reply to be sent to %s, got %s", customer, sent[0].To)
+	}
+}
+
+func TestSend(t *testing.T) {
+	fake := whatsapptest.NewServer(phoneNumberID, token)
+	defer fake.Close()
+
+	cases := []struct {
+		desc   string
+		config whatsapp.Config
+		err    error
+	}{
+		{desc: "with the access token", config: fake.Config(appSecret, verifyToken)},
+		{desc: "with another access token", config: whatsapp.Config{URL: fake.URL, Token: "other-token", PhoneNumberID: phoneNumberID}, err: chatbot.ErrSend},
+		{desc: "from another phone number", config: whatsapp.Config{URL: fake.URL, Token: token, PhoneNumberID: "5678"}, err: chatbot.ErrSend},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := whatsapp.NewChannel(tc.config, nil).Send(context.Background(), chatbot.Reply{To: customer, Text: "Karibu"})
+			if !errors.Contains(err, tc.err) {
+				t.Errorf("expected error %v, got %v", tc.err, err)
+			}
+		})
+	}
+	if sent := len(fake.Messages()); sent != 1 {
+		t.Errorf("expected 1 message to be sent, got %d", sent)
+	}
+}
@@ -0,0 +1,162 @@
+// Package whatsapp contains the WhatsApp Cloud API chatbot channel: a
+// webhook handler for inbound messages and a sender for replies.
+package whatsapp
+
+// ChannelName is the name the WhatsApp channel registers sessions under.
+const ChannelName = "whatsapp"
+
+// DefaultURL is the Graph API base URL the channel sends messages through.
+const DefaultURL = "https://graph.facebook.com/v17.0"
+
+// Limits imposed by the Cloud API on interactive messages.
+const (
+	maxButtons         = 3
+	maxButtonTitle     = 20
+	maxRows            = 10
+	maxRowTitle        = 24
+	maxRowDescription  = 72
+	maxInteractiveBody = 1024
+	maxTextBody        = 4096
+)
+
+const (
+	signatureHeader  = "X-Hub-Signature-256"
+	signaturePrefix  = "sha256="
+	messagingProduct = "whatsapp"
+	subscribeMode    = "subscribe"
+	listButtonTitle  = "Choose"
+	listSectionTitle = "Options"
+)
+
+// Config defines the options used to talk to the WhatsApp Cloud API.
+type Config struct {
+	URL           string // The Graph API base URL. Defaults to DefaultURL.
+	Token         string // The access token used to send messages.
+	PhoneNumberID string // The business phone number messages are sent from.
+	AppSecret     string // The app secret webhook payloads are signed with.
+	VerifyToken   string // The token echoed back during the webhook challenge.
+}
+
+// Notification is the webhook payload the Cloud API posts for inbound
+// messages and delivery statuses.
+type Notification struct {
+	Object string  `json:"object"`
+	Entry  []Entry `json:"entry"`
+}
+
+// Entry groups the changes for a single business account.
+type Entry struct {
+	ID      string   `json:"id"`
+	Changes []Change `json:"changes"`
+}
+
+// Change describes a single webhook event.
+type Change struct {
+	Field string `json:"field"`
+	Value Value  `json:"value"`
+}
+
+// Value holds the contacts and messages of a change.
+type Value struct {
+	MessagingProduct string            `json:"messaging_product"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	Contacts         []Contact         `json:"contacts,omitempty"`
+	Messages         []InboundMessage  `json:"messages,omitempty"`
+}
+
+// Contact is the profile of the user who sent a message.
+type Contact struct {
+	WaID    string `json:"wa_id"`
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+}
+
+// InboundMessage is a message a user sent to the business.
+type InboundMessage struct {
+	ID          string              `json:"id"`
+	From        string              `json:"from"`
+	Timestamp   string              `json:"timestamp"`
+	Type        string              `json:"type"`
+	Text        *Text               `json:"text,omitempty"`
+	Interactive *InboundInteractive `json:"interactive,omitempty"`
+	Button      *InboundButton      `json:"button,omitempty"`
+}
+
+// Text is the body of a text message.
+type Text struct {
+	Body string `json:"body"`
+}
+
+// InboundInteractive is the user's answer to an interactive message.
+type InboundInteractive struct {
+	Type        string `json:"type"`
+	ButtonReply *Reply `json:"button_reply,omitempty"`
+	ListReply   *Reply `json:"list_reply,omitempty"`
+}
+
+// Reply is the button or list row the user picked.
+type Reply struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// InboundButton is the user's answer to a template quick reply button.
+type InboundButton struct {
+	Payload string `json:"payload"`
+	Text    string `json:"text"`
+}
+
+// OutboundMessage is the body of a send message request.
+type OutboundMessage struct {
+	MessagingProduct string       `json:"messaging_product"`
+	RecipientType    string       `json:"recipient_type,omitempty"`
+	To               string       `json:"to"`
+	Type             string       `json:"type"`
+	Text             *Text        `json:"text,omitempty"`
+	Interactive      *Interactive `json:"interactive,omitempty"`
+}
+
+// Interactive is an outbound button or list message.
+type Interactive struct {
+	Type   string `json:"type"`
+	Body   Text   `json:"body"`
+	Action Action `json:"action"`
+}
+
+// Action holds the buttons or list sections of an interactive message.
+type Action struct {
+	Button   string    `json:"button,omitempty"`
+	Buttons  []Button  `json:"buttons,omitempty"`
+	Sections []Section `json:"sections,omitempty"`
+}
+
+// Button is a reply button.
+type Button struct {
+	Type  string `json:"type"`
+	Reply Reply  `json:"reply"`
+}
+
+// Section is a titled group of list rows.
+type Section struct {
+	Title string  `json:"title"`
+	Rows  []Reply `json:"rows"`
+}
+
+// SendResponse is the body of a successful send message response.
+type SendResponse struct {
+	MessagingProduct string `json:"messaging_product"`
+	Messages         []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+}
+
+// ErrorResponse is the body of a failed Graph API request.
+type ErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
@@ -0,0 +1,175 @@
+// Package whatsapptest provides a local fake of the WhatsApp Cloud API so
+// the WhatsApp channel can be exercised without reaching Meta.
+package whatsapptest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/whatsapp"
+)
+
+// Server mimics the send message endpoint of the Cloud API and keeps every
+// message it accepts.
+type Server struct {
+	*httptest.Server
+
+	phoneNumberID string
+	token         string
+
+	mu   sync.Mutex
+	sent []whatsapp.OutboundMessage
+}
+
+// NewServer starts a fake Cloud API that accepts messages for the phone
+// number ID authenticated with token. Close it when done.
+func NewServer(phoneNumberID, token string) *Server {
+	s := &Server{
+		phoneNumberID: phoneNumberID,
+		token:         token,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns a channel configuration pointing at the fake server.
+func (s *Server) Config(appSecret, verifyToken string) whatsapp.Config {
+	return whatsapp.Config{
+		URL:           s.URL,
+		Token:         s.token,
+		PhoneNumberID: s.phoneNumberID,
+		AppSecret:     appSecret,
+		VerifyToken:   verifyToken,
+	}
+}
+
+// Messages returns every message sent through the fake so far.
+func (s *Server) Messages() []whatsapp.OutboundMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]whatsapp.OutboundMessage(nil), s.sent...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != fmt.Sprintf("/%s/messages", s.phoneNumberID) {
+		writeError(w, http.StatusNotFound, 100, "Unsupported post request")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeError(w, http.StatusUnauthorized, 190, "Invalid OAuth access token")
+		return
+	}
+	var msg whatsapp.OutboundMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, 100, err.Error())
+		return
+	}
+	if msg.MessagingProduct != "whatsapp" || msg.To == "" {
+		writeError(w, http.StatusBadRequest, 100, "Invalid parameter")
+		return
+	}
+
+	s.mu.Lock()
+	s.sent = append(s.sent, msg)
+	id := fmt.Sprintf("wamid.%d", len(s.sent))
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"contacts":          []map[string]string{{"input": msg.To, "wa_id": msg.To}},
+		"messages":          []map[string]string{{"id": id}},
+	})
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	var er whatsapp.ErrorResponse
+	er.Error.Message = message
+	er.Error.Type = "OAuthException"
+	er.Error.Code = code
+	json.NewEncoder(w).Encode(er)
+}
+
+// TextMessage builds the notification the Cloud API posts when a user sends
+// a text message.
+func TextMessage(id, from, name, text string) whatsapp.Notification {
+	msg := inbound(id, from, "text")
+	msg.Text = &whatsapp.Text{Body: text}
+	return notification(from, name, msg)
+}
+
+// ButtonReply builds the notification the Cloud API posts when a user taps a
+// reply button.
+func ButtonReply(id, from, name, optionID, title string) whatsapp.Notification {
+	msg := inbound(id, from, "interactive")
+	msg.Interactive = &whatsapp.InboundInteractive{
+		Type:        "button_reply",
+		ButtonReply: &whatsapp.Reply{ID: optionID, Title: title},
+	}
+	return notification(from, name, msg)
+}
+
+// ListReply builds the notification the Cloud API posts when a user picks a
+// list row.
+func ListReply(id, from, name, optionID, title string) whatsapp.Notification {
+	msg := inbound(id, from, "interactive")
+	msg.Interactive = &whatsapp.InboundInteractive{
+		Type:      "list_reply",
+		ListReply: &whatsapp.Reply{ID: optionID, Title: title},
+	}
+	return notification(from, name, msg)
+}
+
+// Deliver posts the notification to the webhook URL signed with the app
+// secret, the way the Cloud API does.
+func Deliver(ctx context.Context, webhookURL, appSecret string, n whatsapp.Notification) (*http.Response, error) {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature-256", whatsapp.Sign(appSecret, body))
+	return http.DefaultClient.Do(req)
+}
+
+func inbound(id, from, typ string) whatsapp.InboundMessage {
+	return whatsapp.InboundMessage{
+		ID:        id,
+		From:      from,
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Type:      typ,
+	}
+}
+
+func notification(from, name string, msg whatsapp.InboundMessage) whatsapp.Notification {
+	contact := whatsapp.Contact{WaID: from}
+	contact.Profile.Name = name
+	return whatsapp.Notification{
+		Object: "whatsapp_business_account",
+		Entry: []whatsapp.Entry{{
+			ID: "fake-business-account",
+			Changes: []whatsapp.Change{{
+				Field: "messages",
+				Value: whatsapp.Value{
+					MessagingProduct: "whatsapp",
+					Contacts:         []whatsapp.Contact{contact},
+					Messages:         []whatsapp.InboundMessage{msg},
+				},
+			}},
+		}},
+	}
+}
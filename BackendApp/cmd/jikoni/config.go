@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	fama "github.com/0x6flab/jikoniApp/BackendApp"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+)
+
+const (
+	defProfile    = "default"
+	defConfigFile = ".jikoni/config" // In the home directory.
+	envProfile    = "JIKONI_PROFILE"
+	envConfigFile = "JIKONI_CONFIG"
+)
+
+// setting is a setting of the CLI. Its value is taken from its flag when
+// set, then from its environment variable, then from its key in the
+// profile, and is def otherwise.
+type setting struct {
+	key   string // The key in the profile, and the flag with dashes.
+	env   string
+	def   string
+	usage string
+}
+
+// The database settings are named as the service names them, so the CLI
+// runs with the environment of the service.
+var settings = []setting{
+	{"url", "JIKONI_URL", "http://localhost:9191", "address of the HTTP API of the service"},
+	{"grpc_url", "JIKONI_GRPC_URL", "localhost:9192", "address of the gRPC API of the service"},
+	{"grpc_ca", "JIKONI_GRPC_CA", "", "CA certificate of the gRPC API when it uses TLS"},
+	{"token", "JIKONI_TOKEN", "", "bearer token of the calls"},
+	{"pin", "JIKONI_STAFF_PIN", "", "PIN of the staff member the changes are attributed to"},
+	{"staff", "JIKONI_STAFF_ID", "", "ID of the staff member whose PIN is given"},
+	{"format", "JIKONI_FORMAT", string(tableFormat), "output format: table, json or csv"},
+	{"timeout", "JIKONI_TIMEOUT", "30s", "how long a call may take, retries included"},
+	{"db_engine", "JIKONI_DB_ENGINE", "postgres", "database engine: postgres or cockroach"},
+	{"db_host", "JIKONI_DB_HOST", "localhost", "database host"},
+	{"db_port", "JIKONI_DB_PORT", "5439", "database port"},
+	{"db_user", "JIKONI_DB_USER", "jikoniuser", "database user"},
+	{"db_pass", "JIKONI_DB_PASS", "jikonipass", "database password"},
+	{"db_name", "JIKONI_DB", "jikoni", "database name"},
+	{"db_ssl_mode", "JIKONI_DB_SSL_MODE", "disable", "database SSL mode"},
+	{"db_ssl_cert", "JIKONI_DB_SSL_CERT", "", "database SSL certificate"},
+	{"db_ssl_key", "JIKONI_DB_SSL_KEY", "", "database SSL key"},
+	{"db_ssl_root_cert", "JIKONI_DB_SSL_ROOT_CERT", "", "database SSL root certificate"},
+}
+
+type config struct {
+	url      string
+	grpcURL  string
+	grpcCA   string
+	token    string
+	pin      string
+	staff    string
+	format   format
+	timeout  time.Duration
+	dbConfig postgres.Config
+
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// flags are the global flags as parsed.
+type flags struct {
+	fs         *flag.FlagSet
+	profile    *string
+	configFile *string
+	values     map[string]*string
+}
+
+func bindFlags(fs *flag.FlagSet) flags {
+	f := flags{
+		fs:         fs,
+		profile:    fs.String("profile", "", fmt.Sprintf("profile of the profile file to use (env %s, default %q)", envProfile, defProfile)),
+		configFile: fs.String("config", "", fmt.Sprintf("profile file (env %s, default ~/%s)", envConfigFile, defConfigFile)),
+		values:     make(map[string]*string, len(settings)),
+	}
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s (env %s", s.usage, s.env)
+		if s.def != "" {
+			usage += fmt.Sprintf(", default %q", s.def)
+		}
+		f.values[s.key] = fs.String(flagName(s.key), "", usage+")")
+	}
+	return f
+}
+
+// loadConfig resolves the settings of the parsed flags.
+func loadConfig(f flags) (config, error) {
+	set := map[string]bool{}
+	f.fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+
+	name := *f.profile
+	if !set["profile"] {
+		name = fama.Env(envProfile, defProfile)
+	}
+	path := *f.configFile
+	if !set["config"] {
+		path = os.Getenv(envConfigFile)
+	}
+	profile, err := readProfile(path, name)
+	if err != nil {
+		return config{}, err
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, s := range settings {
+		v, ok := profile[s.key]
+		if !ok {
+			v = s.def
+		}
+		v = fama.Env(s.env, v)
+		if set[flagName(s.key)] {
+			v = *f.values[s.key]
+		}
+		values[s.key] = v
+	}
+
+	cfg := config{
+		url:     values["url"],
+		grpcURL: values["grpc_url"],
+		grpcCA:  values["grpc_ca"],
+		token:   values["token"],
+		pin:     values["pin"],
+		staff:   values["staff"],
+		format:  format(values["format"]),
+		dbConfig: postgres.Config{
+			Engine:      values["db_engine"],
+			Host:        values["db_host"],
+			Port:        values["db_port"],
+			User:        values["db_user"],
+			Pass:        values["db_pass"],
+			Name:        values["db_name"],
+			SSLMode:     values["db_ssl_mode"],
+			SSLCert:     values["db_ssl_cert"],
+			SSLKey:      values["db_ssl_key"],
+			SSLRootCert: values["db_ssl_root_cert"],
+		},
+	}
+	if err := cfg.format.validate(); err != nil {
+		return config{}, err
+	}
+	if cfg.timeout, err = time.ParseDuration(values["timeout"]); err != nil {
+		return config{}, fmt.Errorf("invalid timeout %q: %w", values["timeout"], err)
+	}
+	return cfg, nil
+}
+
+// readProfile reads the settings of the profile called name from the
+// profile file at path, or at ~/.jikoni/config if path is empty. Profiles
+// are sections of "key = value" lines:
+//
+//	[default]
+//	url = http://localhost:9191
+//	token = secret
+//
+//	[kampala]
+//	url = https://kampala.jikoni.example
+//
+// A missing default profile file is taken to be empty, a missing profile
+// other than the default one is an error.
+func readProfile(path, name string) (map[string]string, error) {
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return map[string]string{}, nil
+		}
+		path = filepath.Join(home, defConfigFile)
+	}
+	file, err := os.Open(path)
+	switch {
+	case os.IsNotExist(err) && !explicit && name == defProfile:
+		return map[string]string{}, nil
+	case err != nil:
+		return nil, err
+	}
+	defer file.Close()
+
+	profile := map[string]string{}
+	found := false
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "", strings.HasPrefix(text, "#"), strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			found = found || section == name
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, line)
+		}
+		key = strings.ReplaceAll(strings.TrimSpace(key), "-", "_")
+		if !known(key) {
+			return nil, fmt.Errorf("%s:%d: unknown setting %q", path, line, key)
+		}
+		if section == name {
+			profile[key] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found && name != defProfile {
+		return nil, fmt.Errorf("no profile %q in %s", name, path)
+	}
+	return profile, nil
+}
+
+func known(key string) bool {
+	for _, s := range settings {
+		if s.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	ordersgrpc "github.com/0x6flab/jikoniApp/BackendApp/orders/api/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var eventHeader = []string{"SEQ", "EVENT", "ID", "VENDOR", "NAME", "STATUS", "PRICE", "BALANCE"}
+
+func tailFeed(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "feed", "")
+	vendor := fs.String("vendor", "", "vendor whose orders are tailed")
+	since := fs.Uint64("since", 0, "change sequence to tail from, the start of the feed if 0")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *vendor == "" {
+		fs.Usage()
+		return fmt.Errorf("no vendor given")
+	}
+
+	conn, err := dialGRPC(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := ordersgrpc.NewOrderServiceClient(conn)
+	stream, err := client.WatchOrders(grpcContext(ctx, cfg), &ordersgrpc.WatchOrdersReq{
+		Vendor: *vendor,
+		Since:  *since,
+	})
+	if err != nil {
+		return err
+	}
+
+	p := newStreamPrinter(cfg, eventHeader...)
+	for {
+		event, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil && ctx.Err() != nil:
+			// Interrupted by the operator.
+			return nil
+		case err != nil:
+			return err
+		}
+		order := event.GetOrder()
+		row := []string{
+			strconv.FormatUint(event.GetSeq(), 10),
+			event.GetType().String(),
+			order.GetId(),
+			order.GetVendor(),
+			order.GetName(),
+			order.GetStatus(),
+			strconv.FormatUint(order.GetPrice(), 10),
+			strconv.FormatUint(order.GetBalance(), 10),
+		}
+		data, err := protojson.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := p.print(json.RawMessage(data), row...); err != nil {
+			return err
+		}
+		if err := p.flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func dialGRPC(ctx context.Context, cfg config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	creds := grpc.WithInsecure()
+	if cfg.grpcCA != "" {
+		tc, err := credentials.NewClientTLSFromFile(cfg.grpcCA, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the gRPC CA certificate: %w", err)
+		}
+		creds = grpc.WithTransportCredentials(tc)
+	}
+	return grpc.DialContext(ctx, cfg.grpcURL, append(opts, creds)...)
+}
+
+// grpcContext returns a copy of ctx carrying the token as the gRPC API
+// authenticates calls.
+func grpcContext(ctx context.Context, cfg config) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+cfg.token)
+}
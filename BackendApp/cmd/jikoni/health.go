@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"google.golang.org/grpc"
+)
+
+// check is the outcome of checking a part of the deployment.
+type check struct {
+	Name    string        `json:"name"`
+	Healthy bool          `json:"healthy"`
+	Detail  string        `json:"detail,omitempty"`
+	Latency time.Duration `json:"latency_ns"`
+}
+
+func checkHealth(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "health", "")
+	db := fs.Bool("db", false, "check the database and its migrations too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	checks := []func(context.Context, config) check{checkHTTP, checkGRPC}
+	if *db {
+		checks = append(checks, checkDB)
+	}
+	p := newPrinter(cfg, "CHECK", "STATUS", "LATENCY", "DETAIL")
+	failed := 0
+	for _, run := range checks {
+		start := time.Now()
+		c := run(ctx, cfg)
+		c.Latency = time.Since(start)
+		status := "ok"
+		if !c.Healthy {
+			status = "failed"
+			failed++
+		}
+		if err := p.print(c, c.Name, status, c.Latency.Round(time.Millisecond).String(), c.Detail); err != nil {
+			return err
+		}
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+func checkHTTP(ctx context.Context, cfg config) check {
+	c := check{Name: "http", Detail: cfg.url}
+	if err := newSDK(cfg).Health(ctx); err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	c.Healthy = true
+	return c
+}
+
+func checkGRPC(ctx context.Context, cfg config) check {
+	c := check{Name: "grpc", Detail: cfg.grpcURL}
+	ctx, cancel := withTimeout(ctx, cfg)
+	defer cancel()
+	conn, err := dialGRPC(ctx, cfg, grpc.WithBlock())
+	if err != nil {
+		c.Detail = fmt.Sprintf("%s: %s", cfg.grpcURL, err)
+		return c
+	}
+	conn.Close()
+	c.Healthy = true
+	return c
+}
+
+func checkDB(ctx context.Context, cfg config) check {
+	c := check{Name: "database", Detail: fmt.Sprintf("%s:%s/%s", cfg.dbConfig.Host, cfg.dbConfig.Port, cfg.dbConfig.Name)}
+	db, err := openDB(cfg)
+	if err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	defer db.Close()
+
+	ctx, cancel := withTimeout(ctx, cfg)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	migrations, err := postgres.Migrations(db, cfg.dbConfig.Engine)
+	if err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	pending := 0
+	for _, m := range migrations {
+		if m.AppliedAt.IsZero() {
+			pending++
+		}
+	}
+	if pending > 0 {
+		c.Detail = fmt.Sprintf("%d migrations pending", pending)
+		return c
+	}
+	c.Healthy = true
+	return c
+}
+
+// withTimeout bounds ctx by the timeout of the config, if any, as checks
+// that could otherwise wait forever need.
+func withTimeout(ctx context.Context, cfg config) (context.Context, context.CancelFunc) {
+	if cfg.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.timeout)
+}
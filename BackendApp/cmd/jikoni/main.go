@@ -0,0 +1,146 @@
+// Command jikoni runs a jikoni deployment for operators: it manages orders,
+// migrates the database, registers vendors and their API keys, tails the
+// live order feed and checks the health of the service.
+//
+//	jikoni [global flags] <command> <subcommand> [flags] [args]
+//
+// Settings are taken from the flags, then the JIKONI_* environment
+// variables, then the profile in the profile file, see config.go.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: jikoni [global flags] <command> <subcommand> [flags] [args]
+
+Commands:
+  orders list [filters]           list the orders matching the filters
+  orders show <id>...             show orders
+  orders status <id> <status>     move an order to ordered, preparing or paid
+  orders delete <id>...           delete orders
+  orders export [filters]         export the orders matching the filters
+  migrate up [-steps n]           apply migrations, all pending ones by default
+  migrate down [-steps n | -all]  roll back migrations, the last one by default
+  migrate redo                    roll back the last migration and apply it again
+  migrate status                  list migrations and when they were applied
+  vendors create -name <n> <id>   register a vendor
+  keys create <vendor>            create an API key of the vendor, shown once
+  feed -vendor <vendor>           tail the changes of the vendor's orders
+  health                          check the HTTP and gRPC APIs and the database
+
+Run "jikoni <command> <subcommand> -h" for the flags of a subcommand.
+
+Global flags:
+`
+
+// command runs a subcommand with its arguments.
+type command func(ctx context.Context, cfg config, args []string) error
+
+var commands = map[string]map[string]command{
+	"orders": {
+		"list":   listOrders,
+		"show":   showOrders,
+		"status": updateStatus,
+		"delete": deleteOrders,
+		"export": exportOrders,
+	},
+	"migrate": {
+		"up":     migrateUp,
+		"down":   migrateDown,
+		"redo":   migrateRedo,
+		"status": migrationStatus,
+	},
+	"vendors": {
+		"create": createVendor,
+	},
+	"keys": {
+		"create": createKey,
+	},
+	"feed": {
+		"": tailFeed,
+	},
+	"health": {
+		"": checkHealth,
+	},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "jikoni: %s\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("jikoni", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	flags := bindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("no command given")
+	}
+
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	name, args := "", args[1:]
+	if _, ok := subcommands[""]; !ok {
+		if len(args) == 0 {
+			fs.Usage()
+			return fmt.Errorf("no %s subcommand given", fs.Arg(0))
+		}
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := subcommands[name]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown %s subcommand %q", fs.Arg(0), name)
+	}
+
+	cfg, err := loadConfig(flags)
+	if err != nil {
+		return err
+	}
+	cfg.stdout = stdout
+	cfg.stderr = stderr
+	if err := cmd(ctx, cfg, args); err != nil && err != flag.ErrHelp {
+		return err
+	}
+	return nil
+}
+
+// newFlagSet returns the flag set of the subcommand called name, writing
+// its usage to the standard error of the config.
+func newFlagSet(cfg config, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(cfg.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(cfg.stderr, "Usage: jikoni %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
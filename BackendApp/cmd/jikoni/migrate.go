@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+func migrateUp(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "migrate up", "")
+	steps := fs.Int("steps", 0, "number of migrations to apply, all pending ones if 0")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return runMigrations(cfg, migrate.Up, *steps)
+}
+
+func migrateDown(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "migrate down", "")
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	all := fs.Bool("all", false, "roll back all the migrations, dropping all the data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *all {
+		return runMigrations(cfg, migrate.Down, 0)
+	}
+	// Zero would roll back everything, which is asked for with -all
+	// rather than by accident.
+	if *steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+	return runMigrations(cfg, migrate.Down, *steps)
+}
+
+func migrateRedo(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "migrate redo", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	id, err := postgres.Redo(db, cfg.dbConfig.Engine)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cfg.stderr, "redid migration %s\n", id)
+	return nil
+}
+
+func runMigrations(cfg config, dir migrate.MigrationDirection, steps int) error {
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	n, err := postgres.Migrate(db, cfg.dbConfig.Engine, dir, steps)
+	if err != nil {
+		return err
+	}
+	verb := "applied"
+	if dir == migrate.Down {
+		verb = "rolled back"
+	}
+	fmt.Fprintf(cfg.stderr, "%s %d migrations\n", verb, n)
+	return nil
+}
+
+func migrationStatus(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "migrate status", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrations, err := postgres.Migrations(db, cfg.dbConfig.Engine)
+	if err != nil {
+		return err
+	}
+	p := newPrinter(cfg, "MIGRATION", "APPLIED")
+	for _, m := range migrations {
+		applied := formatTime(m.AppliedAt)
+		if applied == "" {
+			applied = "pending"
+		}
+		if err := p.print(m, m.ID, applied); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+func openDB(cfg config) (*sqlx.DB, error) {
+	db, err := postgres.Open(cfg.dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	return db, nil
+}
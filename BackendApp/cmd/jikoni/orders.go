@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/sdk"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+)
+
+const dateLayout = "2006-01-02"
+
+func newSDK(cfg config) sdk.SDK {
+	return sdk.NewSDK(sdk.Config{
+		URL:     cfg.url,
+		Token:   cfg.token,
+		Timeout: cfg.timeout,
+	})
+}
+
+// staffContext attributes the changes made with the returned context to
+// the staff member whose PIN is configured.
+func staffContext(ctx context.Context, cfg config) context.Context {
+	if cfg.pin == "" {
+		return ctx
+	}
+	ctx = staff.WithPIN(ctx, cfg.pin)
+	if cfg.staff != "" {
+		ctx = staff.WithMemberID(ctx, cfg.staff)
+	}
+	return ctx
+}
+
+// filters are the flags filtering orders as listing them does.
+type filters struct {
+	vendor, name, place, status string
+	price                       uint64
+	outstanding                 bool
+	from, to                    timeFlag
+}
+
+func bindFilters(fs *flag.FlagSet) *filters {
+	f := &filters{}
+	fs.StringVar(&f.vendor, "vendor", "", "orders of the vendor")
+	fs.StringVar(&f.name, "name", "", "orders called name")
+	fs.StringVar(&f.place, "place", "", "orders served at the place: inhouse or delivery")
+	fs.StringVar(&f.status, "status", "", "orders in the status: ordered, preparing or paid")
+	fs.Uint64Var(&f.price, "price", 0, "orders of the price")
+	fs.BoolVar(&f.outstanding, "outstanding", false, "orders with a balance left to pay")
+	fs.Var(&f.from, "from", "orders created from, as 2006-01-02 or RFC 3339")
+	fs.Var(&f.to, "to", "orders created before, as 2006-01-02 or RFC 3339")
+	return f
+}
+
+func (f filters) pageMetadata() orders.PageMetadata {
+	return orders.PageMetadata{
+		Vendor:      f.vendor,
+		Name:        f.name,
+		Price:       f.price,
+		Place:       f.place,
+		Status:      f.status,
+		Outstanding: f.outstanding,
+		From:        time.Time(f.from),
+		To:          time.Time(f.to),
+	}
+}
+
+// timeFlag is a time given as a local date or an RFC 3339 time.
+type timeFlag time.Time
+
+func (t *timeFlag) String() string {
+	if t == nil || time.Time(*t).IsZero() {
+		return ""
+	}
+	return time.Time(*t).Format(time.RFC3339)
+}
+
+func (t *timeFlag) Set(s string) error {
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if v, err = time.ParseInLocation(dateLayout, s, time.Local); err != nil {
+			return fmt.Errorf("expected 2006-01-02 or RFC 3339 time")
+		}
+	}
+	*t = timeFlag(v)
+	return nil
+}
+
+func listOrders(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "orders list", "")
+	f := bindFilters(fs)
+	offset := fs.Uint64("offset", 0, "number of orders to skip")
+	limit := fs.Uint64("limit", 10, fmt.Sprintf("number of orders to list, at most %d", sdk.MaxLimit))
+	all := fs.Bool("all", false, "list all the orders from the offset, a page at a time")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pm := f.pageMetadata()
+	pm.Offset = *offset
+	pm.Limit = *limit
+	p := newPrinter(cfg, orderHeader...)
+	if *all {
+		it := newSDK(cfg).Orders(ctx, pm)
+		for it.Next() {
+			if err := p.print(it.Order(), orderRow(it.Order())...); err != nil {
+				return err
+			}
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return p.flush()
+	}
+
+	page, err := newSDK(cfg).ListOrders(ctx, pm)
+	if err != nil {
+		return err
+	}
+	for _, order := range page.Orders {
+		if err := p.print(order, orderRow(order)...); err != nil {
+			return err
+		}
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if cfg.format == tableFormat {
+		fmt.Fprintf(cfg.stderr, "%d-%d of %d orders\n", min(page.Offset+1, page.Total), page.Offset+uint64(len(page.Orders)), page.Total)
+	}
+	return nil
+}
+
+func showOrders(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "orders show", "<id>...")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no order given")
+	}
+
+	s := newSDK(cfg)
+	p := newPrinter(cfg, orderHeader...)
+	for i, id := range fs.Args() {
+		order, err := s.ViewOrder(ctx, id)
+		if err != nil {
+			return fmt.Errorf("order %s: %w", id, err)
+		}
+		if cfg.format != tableFormat {
+			if err := p.print(order, orderRow(order)...); err != nil {
+				return err
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(cfg.stdout)
+		}
+		if err := writeOrder(cfg.stdout, order); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+func updateStatus(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "orders status", "<id> <status>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected an order and a status")
+	}
+	id, status := fs.Arg(0), fs.Arg(1)
+	if !valid(status, orders.Statuses) {
+		return fmt.Errorf("unknown status %q, expected one of %s", status, strings.Join(orders.Statuses, ", "))
+	}
+
+	ctx = staffContext(ctx, cfg)
+	s := newSDK(cfg)
+	order, err := s.ViewOrder(ctx, id)
+	if err != nil {
+		return fmt.Errorf("order %s: %w", id, err)
+	}
+	if order.Status == status {
+		fmt.Fprintf(cfg.stderr, "order %s is already %s\n", id, status)
+		return nil
+	}
+	order.Status = status
+	if _, err := s.UpdateOrder(ctx, order); err != nil {
+		return fmt.Errorf("order %s: %w", id, err)
+	}
+	fmt.Fprintf(cfg.stderr, "order %s is now %s\n", id, status)
+	return nil
+}
+
+func deleteOrders(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "orders delete", "<id>...")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no order given")
+	}
+
+	ctx = staffContext(ctx, cfg)
+	s := newSDK(cfg)
+	for _, id := range fs.Args() {
+		if err := s.DeleteOrder(ctx, id); err != nil {
+			return fmt.Errorf("order %s: %w", id, err)
+		}
+		fmt.Fprintf(cfg.stderr, "order %s deleted\n", id)
+	}
+	return nil
+}
+
+func exportOrders(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "orders export", "")
+	f := bindFilters(fs)
+	typ := fs.String("type", string(export.CSV), "file type: csv or xlsx")
+	fields := fs.String("fields", "", "comma separated columns, the default ones if empty")
+	tz := fs.String("tz", "", "time zone the times are written in, the one of the service if empty")
+	out := fs.String("out", "", "file the export is written to, the standard output if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := export.Query{
+		PageMetadata: f.pageMetadata(),
+		Format:       export.Format(*typ),
+	}
+	if *fields != "" {
+		query.Fields = strings.Split(*fields, ",")
+	}
+	if *tz != "" {
+		location, err := time.LoadLocation(*tz)
+		if err != nil {
+			return err
+		}
+		query.Location = location
+	}
+
+	// Exports of long ranges take as long as they take to stream.
+	cfg.timeout = 0
+	if *out == "" {
+		return newSDK(cfg).ExportOrders(ctx, query, cfg.stdout)
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	err = newSDK(cfg).ExportOrders(ctx, query, file)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(*out)
+	}
+	return err
+}
+
+func valid(v string, values []string) bool {
+	for _, value := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func min(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
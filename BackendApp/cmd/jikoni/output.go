@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+const streamCellWidth = 12
+
+// format is how results are written out.
+type format string
+
+const (
+	tableFormat format = "table"
+	jsonFormat  format = "json"
+	csvFormat   format = "csv"
+)
+
+func (f format) validate() error {
+	switch f {
+	case tableFormat, jsonFormat, csvFormat:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or csv", f)
+}
+
+// printer writes results in the format of the config. Tables and CSV are
+// the rows under the header, JSON is the values themselves.
+type printer struct {
+	format format
+	header []string
+	tw     *tabwriter.Writer
+	csv    *csv.Writer
+	json   *json.Encoder
+	rows   int
+}
+
+func newPrinter(cfg config, header ...string) *printer {
+	p := &printer{
+		format: cfg.format,
+		header: header,
+	}
+	switch p.format {
+	case tableFormat:
+		p.tw = tabwriter.NewWriter(cfg.stdout, 0, 0, 2, ' ', 0)
+	case csvFormat:
+		p.csv = csv.NewWriter(cfg.stdout)
+	case jsonFormat:
+		p.json = json.NewEncoder(cfg.stdout)
+	}
+	return p
+}
+
+// newStreamPrinter returns a printer flushed after every row, padding the
+// cells of tables to a minimum width as the rows to come are not known.
+func newStreamPrinter(cfg config, header ...string) *printer {
+	p := newPrinter(cfg, header...)
+	if p.tw != nil {
+		p.tw = tabwriter.NewWriter(cfg.stdout, streamCellWidth, 0, 2, ' ', 0)
+	}
+	return p
+}
+
+// print writes v as JSON, or its row otherwise.
+func (p *printer) print(v interface{}, row ...string) error {
+	defer func() { p.rows++ }()
+	switch p.format {
+	case jsonFormat:
+		return p.json.Encode(v)
+	case csvFormat:
+		if p.rows == 0 {
+			if err := p.csv.Write(p.header); err != nil {
+				return err
+			}
+		}
+		return p.csv.Write(row)
+	default:
+		if p.rows == 0 {
+			fmt.Fprintln(p.tw, strings.Join(p.header, "\t"))
+		}
+		_, err := fmt.Fprintln(p.tw, strings.Join(row, "\t"))
+		return err
+	}
+}
+
+// flush writes out what has been printed so far. Tables are aligned on the
+// rows printed between flushes.
+func (p *printer) flush() error {
+	switch p.format {
+	case csvFormat:
+		p.csv.Flush()
+		return p.csv.Error()
+	case tableFormat:
+		return p.tw.Flush()
+	}
+	return nil
+}
+
+var orderHeader = []string{"ID", "VENDOR", "NAME", "PLACE", "STATUS", "PRICE", "BALANCE", "CREATED"}
+
+func orderRow(order orders.Order) []string {
+	return []string{
+		order.ID,
+		order.Vendor,
+		order.Name,
+		order.Place,
+		order.Status,
+		strconv.FormatUint(order.Price, 10),
+		strconv.FormatUint(order.Balance(), 10),
+		formatTime(order.CreatedAt),
+	}
+}
+
+// writeOrder writes the order field by field, its lines included.
+func writeOrder(w io.Writer, order orders.Order) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fields := [][2]string{
+		{"ID", order.ID},
+		{"Vendor", order.Vendor},
+		{"Name", order.Name},
+		{"Place", order.Place},
+		{"Status", order.Status},
+		{"Price", strconv.FormatUint(order.Price, 10)},
+		{"Discount", strconv.FormatUint(order.Discount(), 10)},
+		{"Tax", strconv.FormatUint(order.TaxTotal(), 10)},
+		{"Paid", strconv.FormatUint(order.Paid, 10)},
+		{"Balance", strconv.FormatUint(order.Balance(), 10)},
+		{"Tips", strconv.FormatUint(order.Tips, 10)},
+		{"Created by", order.CreatedBy},
+		{"Accepted by", order.AcceptedBy},
+		{"Paid by", order.PaidBy},
+		{"Created", formatTime(order.CreatedAt)},
+		{"Updated", formatTime(order.UpdatedAt)},
+	}
+	for _, f := range fields {
+		if f[1] != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", f[0], f[1])
+		}
+	}
+	if len(order.Metadata) > 0 {
+		metadata, err := json.Marshal(order.Metadata)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "Metadata:\t%s\n", metadata)
+	}
+	for i, item := range order.Items {
+		label := ""
+		if i == 0 {
+			label = "Items:"
+		}
+		fmt.Fprintf(tw, "%s\t%d x %s @ %d\n", label, item.Quantity, item.Name, item.Price)
+	}
+	return tw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
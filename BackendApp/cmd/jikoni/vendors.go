@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/vendors"
+	"github.com/0x6flab/jikoniApp/BackendApp/vendors/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+var keyHeader = []string{"ID", "VENDOR", "NAME", "KEY", "CREATED"}
+
+func newVendorService(db *sqlx.DB) vendors.Service {
+	return vendors.NewService(postgres.NewVendorRepo(db), postgres.NewKeyRepo(db))
+}
+
+func createVendor(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "vendors create", "<id>")
+	name := fs.String("name", "", "name of the vendor, i.e. Mama Oliech")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *name == "" {
+		fs.Usage()
+		return fmt.Errorf("expected the ID and the name of the vendor")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	id, err := newVendorService(db).CreateVendor(ctx, cfg.token, vendors.Vendor{ID: fs.Arg(0), Name: *name})
+	if err != nil {
+		return fmt.Errorf("vendor %s: %w", fs.Arg(0), err)
+	}
+	fmt.Fprintf(cfg.stderr, "created vendor %s\n", id)
+	return nil
+}
+
+func createKey(ctx context.Context, cfg config, args []string) error {
+	fs := newFlagSet(cfg, "keys create", "<vendor>")
+	name := fs.String("name", "", "what the key is used by, i.e. till")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a vendor")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	key, err := newVendorService(db).CreateKey(ctx, cfg.token, fs.Arg(0), *name)
+	if err != nil {
+		return fmt.Errorf("vendor %s: %w", fs.Arg(0), err)
+	}
+	p := newPrinter(cfg, keyHeader...)
+	if err := p.print(key, key.ID, key.Vendor, key.Name, key.Secret, formatTime(key.CreatedAt)); err != nil {
+		return err
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(cfg.stderr, "the key is not shown again, keep it somewhere safe")
+	return nil
+}
@@ -3,30 +3,106 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	fama "github.com/0x6flab/jikoniApp/BackendApp"
+	"github.com/0x6flab/jikoniApp/BackendApp/analytics"
+	analyticsapi "github.com/0x6flab/jikoniApp/BackendApp/analytics/api"
+	analyticspostgres "github.com/0x6flab/jikoniApp/BackendApp/analytics/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/batch"
+	batchapi "github.com/0x6flab/jikoniApp/BackendApp/batch/api"
+	batchpostgres "github.com/0x6flab/jikoniApp/BackendApp/batch/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/bills"
+	billsapi "github.com/0x6flab/jikoniApp/BackendApp/bills/api"
+	billspostgres "github.com/0x6flab/jikoniApp/BackendApp/bills/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot"
+	chatbotapi "github.com/0x6flab/jikoniApp/BackendApp/chatbot/api"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/simulator"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/telegram"
+	"github.com/0x6flab/jikoniApp/BackendApp/chatbot/whatsapp"
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	drawerapi "github.com/0x6flab/jikoniApp/BackendApp/drawer/api"
+	drawerpostgres "github.com/0x6flab/jikoniApp/BackendApp/drawer/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	exportapi "github.com/0x6flab/jikoniApp/BackendApp/export/api"
+	exportpostgres "github.com/0x6flab/jikoniApp/BackendApp/export/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	graphqlapi "github.com/0x6flab/jikoniApp/BackendApp/graphql/api"
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	guestapi "github.com/0x6flab/jikoniApp/BackendApp/guest/api"
+	guestpostgres "github.com/0x6flab/jikoniApp/BackendApp/guest/postgres"
 	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	inventoryapi "github.com/0x6flab/jikoniApp/BackendApp/inventory/api"
+	inventorypostgres "github.com/0x6flab/jikoniApp/BackendApp/inventory/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	loyaltyapi "github.com/0x6flab/jikoniApp/BackendApp/loyalty/api"
+	loyaltypostgres "github.com/0x6flab/jikoniApp/BackendApp/loyalty/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	menuapi "github.com/0x6flab/jikoniApp/BackendApp/menu/api"
+	menupostgres "github.com/0x6flab/jikoniApp/BackendApp/menu/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	offlineapi "github.com/0x6flab/jikoniApp/BackendApp/offline/api"
+	offlinepostgres "github.com/0x6flab/jikoniApp/BackendApp/offline/postgres"
 	"github.com/0x6flab/jikoniApp/BackendApp/orders"
 	ordersapi "github.com/0x6flab/jikoniApp/BackendApp/orders/api"
+	ordersgrpc "github.com/0x6flab/jikoniApp/BackendApp/orders/api/grpc"
+	ordersmemory "github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
 	"github.com/0x6flab/jikoniApp/BackendApp/orders/ocmux"
 	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	orderssqlite "github.com/0x6flab/jikoniApp/BackendApp/orders/sqlite"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	promotionsapi "github.com/0x6flab/jikoniApp/BackendApp/promotions/api"
+	promotionspostgres "github.com/0x6flab/jikoniApp/BackendApp/promotions/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	purchasingapi "github.com/0x6flab/jikoniApp/BackendApp/purchasing/api"
+	purchasingpostgres "github.com/0x6flab/jikoniApp/BackendApp/purchasing/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	staffapi "github.com/0x6flab/jikoniApp/BackendApp/staff/api"
+	staffpostgres "github.com/0x6flab/jikoniApp/BackendApp/staff/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	tablesapi "github.com/0x6flab/jikoniApp/BackendApp/tables/api"
+	tablespostgres "github.com/0x6flab/jikoniApp/BackendApp/tables/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	taxapi "github.com/0x6flab/jikoniApp/BackendApp/tax/api"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax/etims"
+	taxpostgres "github.com/0x6flab/jikoniApp/BackendApp/tax/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+	ussdapi "github.com/0x6flab/jikoniApp/BackendApp/ussd/api"
+	"github.com/0x6flab/jikoniApp/BackendApp/vendors"
+	vendorsapi "github.com/0x6flab/jikoniApp/BackendApp/vendors/api"
+	vendorspostgres "github.com/0x6flab/jikoniApp/BackendApp/vendors/postgres"
+	"github.com/go-kit/kit/metrics"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	kitlog "github.com/go-kit/log"
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"go.opencensus.io/plugin/ochttp"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	// Time zones are embedded so promotions run on local time in minimal
+	// images.
+	_ "time/tzdata"
 )
 
 const (
 	stopWaitTime     = 5 * time.Second
+	dedupTTL         = 24 * time.Hour
+	exportPurgeEvery = time.Hour
+	notifyEvery      = time.Second
 	svcName          = "jikoni-orders"
 	defLogLevel      = "error"
+	defDBEngine      = postgres.EnginePostgres
 	defDBHost        = "jikoni-db"
 	defDBPort        = "5439"
 	defDBUser        = "jikoniuser"
@@ -36,11 +112,47 @@ const (
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
+	defDBMigrate     = "true"
+	defOrdersStore   = storePostgres
+	defSQLitePath    = "jikoni.db"
 	defHTTPPort      = "8180"
+	defGRPCPort      = "8181"
 	defServerCert    = ""
 	defServerKey     = ""
 	defZipkinURL     = "http://jikoni-zipkin:9411/api/v2/spans"
+	defBotVendor     = "jikoni"
+	defBotToken      = "chatbot"
+	defBotTill       = ""
+	defBotSessionTTL = "30m"
+	defBotSimulator  = "false"
+	defWhatsAppURL   = whatsapp.DefaultURL
+	defWhatsAppToken = ""
+	defWhatsAppPhone = ""
+	defWhatsAppApp   = ""
+	defWhatsAppVrfy  = ""
+	defTelegramURL   = telegram.DefaultURL
+	defTelegramToken = ""
+	defTelegramSec   = ""
+	defUSSDVendor    = "jikoni"
+	defUSSDToken     = "ussd"
+	defUSSDTill      = ""
+	defUSSDTTL       = "10m"
+	defGuestSecret   = ""
+	defGuestURL      = "http://localhost:8180/guest"
+	defGuestToken    = "guest"
+	defGuestCartTTL  = "4h"
+	defTimezone      = "Africa/Nairobi"
+	defInvoicePrefix = tax.DefaultPrefix
+	defETIMSFake     = "false"
+	defExportDir     = "/tmp/jikoni-exports"
+	defExportTTL     = "24h"
+	defStaffPINKey   = ""
+	defStaffAttempts = "5"
+	defVendorTries   = "20"
+	defStaffLockout  = "15m"
+	defRequireKey    = "false"
 	envLogLevel      = "JIKONI_LOG_LEVEL"
+	envDBEngine      = "JIKONI_DB_ENGINE"
 	envDBHost        = "JIKONI_DB_HOST"
 	envDBPort        = "JIKONI_DB_PORT"
 	envDBUser        = "JIKONI_DB_USER"
@@ -50,19 +162,101 @@ const (
 	envDBSSLCert     = "JIKONI_DB_SSL_CERT"
 	envDBSSLKey      = "JIKONI_DB_SSL_KEY"
 	envDBSSLRootCert = "JIKONI_DB_SSL_ROOT_CERT"
+	envDBMigrate     = "JIKONI_DB_MIGRATE"
+	envOrdersStore   = "JIKONI_ORDERS_STORE"
+	envSQLitePath    = "JIKONI_SQLITE_PATH"
 	envHTTPPort      = "JIKONI_HTTP_PORT"
+	envGRPCPort      = "JIKONI_GRPC_PORT"
 	envServerCert    = "JIKONI_SERVER_CERT"
 	envServerKey     = "JIKONI_SERVER_KEY"
 	envZipkinURL     = "JIKONI_ZIPKIN_URL"
+	envBotVendor     = "JIKONI_CHATBOT_VENDOR"
+	envBotToken      = "JIKONI_CHATBOT_TOKEN"
+	envBotTill       = "JIKONI_CHATBOT_TILL"
+	envBotSessionTTL = "JIKONI_CHATBOT_SESSION_TTL"
+	envBotSimulator  = "JIKONI_CHATBOT_SIMULATOR"
+	envWhatsAppURL   = "JIKONI_WHATSAPP_URL"
+	envWhatsAppToken = "JIKONI_WHATSAPP_TOKEN"
+	envWhatsAppPhone = "JIKONI_WHATSAPP_PHONE_NUMBER_ID"
+	envWhatsAppApp   = "JIKONI_WHATSAPP_APP_SECRET"
+	envWhatsAppVrfy  = "JIKONI_WHATSAPP_VERIFY_TOKEN"
+	envTelegramURL   = "JIKONI_TELEGRAM_URL"
+	envTelegramToken = "JIKONI_TELEGRAM_TOKEN"
+	envTelegramSec   = "JIKONI_TELEGRAM_SECRET"
+	envUSSDVendor    = "JIKONI_USSD_VENDOR"
+	envUSSDToken     = "JIKONI_USSD_TOKEN"
+	envUSSDTill      = "JIKONI_USSD_TILL"
+	envUSSDTTL       = "JIKONI_USSD_SESSION_TTL"
+	envGuestSecret   = "JIKONI_GUEST_SECRET"
+	envGuestURL      = "JIKONI_GUEST_URL"
+	envGuestToken    = "JIKONI_GUEST_TOKEN"
+	envGuestCartTTL  = "JIKONI_GUEST_CART_TTL"
+	envTimezone      = "JIKONI_TIMEZONE"
+	envInvoicePrefix = "JIKONI_INVOICE_PREFIX"
+	envETIMSFake     = "JIKONI_ETIMS_FAKE"
+	envExportDir     = "JIKONI_EXPORT_DIR"
+	envExportTTL     = "JIKONI_EXPORT_TTL"
+	envStaffPINKey   = "JIKONI_STAFF_PIN_KEY"
+	envStaffAttempts = "JIKONI_STAFF_PIN_ATTEMPTS"
+	envVendorTries   = "JIKONI_STAFF_PIN_VENDOR_ATTEMPTS"
+	envStaffLockout  = "JIKONI_STAFF_PIN_LOCKOUT"
+	envRequireKey    = "JIKONI_REQUIRE_API_KEY"
+)
+
+// publicPaths are served without an API key when keys are required: the
+// health checks, metrics and docs, the guests at the tables, whose links
+// carry tokens of their own, and the USSD gateway and chatbot webhooks.
+var publicPaths = []string{
+	"/health",
+	"/metrics",
+	"/openapi.json",
+	"/docs",
+	"/guest",
+	"/ussd",
+	"/chatbot/whatsapp/webhook",
+	"/chatbot/telegram/webhook",
+}
+
+// Stores the orders can be kept in. The drawer reads the sales of its
+// Z-reports from the orders in postgres and locks the orders of closed days
+// in their writes, so it is only served when the orders are kept there.
+// The analytics are served whatever the store, but orders kept elsewhere
+// only show in them once the rollups are rebuilt.
+// All or nothing batches roll back the promotions and points they redeemed
+// along with their orders only when the orders are kept in postgres.
+const (
+	storePostgres = "postgres"
+	storeSQLite   = "sqlite"
+	storeMemory   = "memory"
 )
 
 type config struct {
-	logLevel   string
-	dbConfig   postgres.Config
-	httpPort   string
-	serverCert string
-	serverKey  string
-	zipkinURL  string
+	logLevel      string
+	dbConfig      postgres.Config
+	dbMigrate     bool
+	ordersStore   string
+	sqliteConfig  orderssqlite.Config
+	httpPort      string
+	grpcPort      string
+	serverCert    string
+	serverKey     string
+	zipkinURL     string
+	botConfig     chatbot.Config
+	botSessionTTL time.Duration
+	botSimulator  bool
+	whatsApp      whatsapp.Config
+	telegram      telegram.Config
+	ussdConfig    ussd.Config
+	ussdTTL       time.Duration
+	guestConfig   guest.Config
+	guestCartTTL  time.Duration
+	location      *time.Location
+	taxConfig     tax.Config
+	etimsFake     bool
+	exportDir     string
+	exportTTL     time.Duration
+	staffConfig   staff.Config
+	requireKey    bool
 }
 
 func main() {
@@ -82,13 +276,97 @@ func main() {
 	fmt.Println(3)
 	defer ocmux.InitOpenCensusWithZipkin(cfg.zipkinURL, svcName, fmt.Sprintf("%s:%s", svcName, cfg.httpPort)).Close()
 	fmt.Println(4)
-	db := connectToDB(cfg.dbConfig, logger)
+	db := connectToDB(cfg.dbConfig, cfg.dbMigrate, logger)
 	defer db.Close()
+	drawerSvc := newDrawerService(db, logger)
+	ordersRepo := newOrderRepo(cfg, db, logger)
 	fmt.Println(5)
-	svc := newService(db, logger)
+	menuSvc := newMenuService(db, logger)
+	promotionsSvc := newPromotionsService(cfg, db, logger)
+	taxSvc := newTaxService(cfg, db, ordersRepo, menuSvc, logger)
+	loyaltySvc := newLoyaltyService(db, logger)
+	inventorySvc := newInventoryService(db, menuSvc, logger)
+	purchasingSvc := newPurchasingService(db, inventorySvc, logger)
+	staffSvc := newStaffService(cfg, db, logger)
+	analyticsSvc := newAnalyticsService(cfg, db, ordersRepo, logger)
+	exportSvc := newExportService(cfg, db, ordersRepo, logger)
+	svc := newService(ordersRepo, promotionsSvc, taxSvc, logger, loyaltySvc, staffSvc)
+	// The hooks, loyalty, staff, inventory and tax, follow orders through
+	// the kitchen to being paid, whichever way the orders are written.
+	notifier := orders.NewNotifier(ordersRepo, loyaltySvc, staffSvc, inventorySvc, taxSvc)
+	batchSvc := newBatchService(db, ordersRepo, svc, logger)
+	syncSvc := newSyncService(db, svc, ordersRepo, staffSvc, logger)
+	graphqlSvc := newGraphQLService(db, ordersRepo, svc, loyaltySvc, logger)
+	botSvc := newChatbotService(cfg, svc, menuSvc, logger)
+	ussdSvc := newUSSDService(cfg, svc, menuSvc, logger)
+	tablesSvc := newTablesService(db, svc, logger)
+	billsSvc := newBillsService(db, svc, logger)
 	fmt.Println(6)
+
+	vendorsSvc := vendors.NewService(vendorspostgres.NewVendorRepo(db), vendorspostgres.NewKeyRepo(db))
+	router := mux.NewRouter()
+	if cfg.requireKey {
+		router.Use(vendorsapi.KeyMiddleware(vendorsSvc, publicPaths...))
+	}
+	router.Use(staffapi.PINMiddleware)
+	exportapi.MakeExportHandler(exportSvc, router, logger)
+	batchapi.MakeBatchHandler(batchSvc, router, logger)
+	offlineapi.MakeSyncHandler(syncSvc, router, logger)
+	graphqlapi.MakeGraphQLHandler(graphqlSvc, router, logger)
+	ordersapi.MakeOrdersHandler(svc, router, logger)
+	menuapi.MakeMenuHandler(menuSvc, router, logger)
+	ussdapi.MakeHandler(ussdSvc, router, logger)
+	tablesapi.MakeTablesHandler(tablesSvc, router, logger)
+	billsapi.MakeBillsHandler(billsSvc, router, logger)
+	promotionsapi.MakePromotionsHandler(promotionsSvc, router, logger)
+	taxapi.MakeTaxHandler(taxSvc, router, logger)
+	loyaltyapi.MakeLoyaltyHandler(loyaltySvc, router, logger)
+	inventoryapi.MakeInventoryHandler(inventorySvc, router, logger)
+	purchasingapi.MakePurchasingHandler(purchasingSvc, router, logger)
+	staffapi.MakeStaffHandler(staffSvc, router, logger)
+	if cfg.ordersStore == storePostgres {
+		drawerapi.MakeDrawerHandler(drawerSvc, router, logger)
+	}
+	analyticsapi.MakeAnalyticsHandler(analyticsSvc, router, logger)
+	// Table tokens cannot be verified without a secret.
+	if cfg.guestConfig.Secret != "" {
+		guestapi.MakeGuestHandler(newGuestService(cfg, db, tablesSvc, menuSvc, logger), router, logger)
+	}
+	if cfg.botSimulator {
+		simulator.MakeHandler(botSvc, simulator.NewChannel(), router, logger)
+	}
+	dedup := chatbot.NewMemoryDeduplicator(dedupTTL)
+	if cfg.whatsApp.Token != "" {
+		whatsapp.MakeHandler(botSvc, whatsapp.NewChannel(cfg.whatsApp, nil), dedup, cfg.whatsApp, router, logger)
+	}
+	if cfg.telegram.Token != "" {
+		telegram.MakeHandler(botSvc, telegram.NewChannel(cfg.telegram, nil), dedup, cfg.telegram, router, logger)
+	}
 	g.Go(func() error {
-		return startHTTPServer(ctx, svc, cfg, logger)
+		return startHTTPServer(ctx, router, cfg, logger)
+	})
+
+	ordersServer := ordersgrpc.NewServer(svc, ordersRepo, logger)
+	g.Go(func() error {
+		return startGRPCServer(ctx, ordersServer, vendorsSvc, cfg, logger)
+	})
+
+	g.Go(func() error {
+		return purgeExports(ctx, exportSvc, logger)
+	})
+
+	g.Go(func() error {
+		return notifyHooks(ctx, notifier, logger)
+	})
+
+	// Export jobs and imports running as the service shuts down are
+	// cancelled.
+	g.Go(func() error {
+		return exportSvc.Run(ctx, "")
+	})
+
+	g.Go(func() error {
+		return batchSvc.Run(ctx, "")
 	})
 
 	g.Go(func() error {
@@ -110,6 +388,7 @@ func main() {
 
 func loadConfig() config {
 	dbConfig := postgres.Config{
+		Engine:      fama.Env(envDBEngine, defDBEngine),
 		Host:        fama.Env(envDBHost, defDBHost),
 		Port:        fama.Env(envDBPort, defDBPort),
 		User:        fama.Env(envDBUser, defDBUser),
@@ -120,18 +399,133 @@ func loadConfig() config {
 		SSLKey:      fama.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: fama.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
+	botConfig := chatbot.Config{
+		Vendor: fama.Env(envBotVendor, defBotVendor),
+		Token:  fama.Env(envBotToken, defBotToken),
+		Till:   fama.Env(envBotTill, defBotTill),
+	}
+	botSessionTTL, err := time.ParseDuration(fama.Env(envBotSessionTTL, defBotSessionTTL))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envBotSessionTTL, err)
+	}
+	dbMigrate, err := strconv.ParseBool(fama.Env(envDBMigrate, defDBMigrate))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envDBMigrate, err)
+	}
+	ordersStore := fama.Env(envOrdersStore, defOrdersStore)
+	switch ordersStore {
+	case storePostgres, storeSQLite, storeMemory:
+	default:
+		log.Fatalf("invalid %s: %q is not one of %s, %s or %s", envOrdersStore, ordersStore, storePostgres, storeSQLite, storeMemory)
+	}
+	botSimulator, err := strconv.ParseBool(fama.Env(envBotSimulator, defBotSimulator))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envBotSimulator, err)
+	}
+	ussdTTL, err := time.ParseDuration(fama.Env(envUSSDTTL, defUSSDTTL))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envUSSDTTL, err)
+	}
+	guestCartTTL, err := time.ParseDuration(fama.Env(envGuestCartTTL, defGuestCartTTL))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envGuestCartTTL, err)
+	}
+	location, err := time.LoadLocation(fama.Env(envTimezone, defTimezone))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envTimezone, err)
+	}
+	etimsFake, err := strconv.ParseBool(fama.Env(envETIMSFake, defETIMSFake))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envETIMSFake, err)
+	}
+	exportTTL, err := time.ParseDuration(fama.Env(envExportTTL, defExportTTL))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envExportTTL, err)
+	}
+	// PINs are hashed with the key, staff can not be identified without it.
+	staffPINKey := fama.Env(envStaffPINKey, defStaffPINKey)
+	if staffPINKey == "" {
+		log.Fatalf("%s is required", envStaffPINKey)
+	}
+	staffAttempts, err := strconv.ParseUint(fama.Env(envStaffAttempts, defStaffAttempts), 10, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envStaffAttempts, err)
+	}
+	vendorTries, err := strconv.ParseUint(fama.Env(envVendorTries, defVendorTries), 10, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envVendorTries, err)
+	}
+	staffLockout, err := time.ParseDuration(fama.Env(envStaffLockout, defStaffLockout))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envStaffLockout, err)
+	}
+	requireKey, err := strconv.ParseBool(fama.Env(envRequireKey, defRequireKey))
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envRequireKey, err)
+	}
 	return config{
-		logLevel:   fama.Env(envLogLevel, defLogLevel),
-		dbConfig:   dbConfig,
-		httpPort:   fama.Env(envHTTPPort, defHTTPPort),
-		serverCert: fama.Env(envServerCert, defServerCert),
-		serverKey:  fama.Env(envServerKey, defServerKey),
-		zipkinURL:  fama.Env(envZipkinURL, defZipkinURL),
+		logLevel:      fama.Env(envLogLevel, defLogLevel),
+		dbConfig:      dbConfig,
+		dbMigrate:     dbMigrate,
+		ordersStore:   ordersStore,
+		sqliteConfig:  orderssqlite.Config{Path: fama.Env(envSQLitePath, defSQLitePath)},
+		httpPort:      fama.Env(envHTTPPort, defHTTPPort),
+		grpcPort:      fama.Env(envGRPCPort, defGRPCPort),
+		serverCert:    fama.Env(envServerCert, defServerCert),
+		serverKey:     fama.Env(envServerKey, defServerKey),
+		zipkinURL:     fama.Env(envZipkinURL, defZipkinURL),
+		botConfig:     botConfig,
+		botSessionTTL: botSessionTTL,
+		botSimulator:  botSimulator,
+		whatsApp: whatsapp.Config{
+			URL:           fama.Env(envWhatsAppURL, defWhatsAppURL),
+			Token:         fama.Env(envWhatsAppToken, defWhatsAppToken),
+			PhoneNumberID: fama.Env(envWhatsAppPhone, defWhatsAppPhone),
+			AppSecret:     fama.Env(envWhatsAppApp, defWhatsAppApp),
+			VerifyToken:   fama.Env(envWhatsAppVrfy, defWhatsAppVrfy),
+		},
+		telegram: telegram.Config{
+			URL:    fama.Env(envTelegramURL, defTelegramURL),
+			Token:  fama.Env(envTelegramToken, defTelegramToken),
+			Secret: fama.Env(envTelegramSec, defTelegramSec),
+		},
+		ussdConfig: ussd.Config{
+			Vendor: fama.Env(envUSSDVendor, defUSSDVendor),
+			Token:  fama.Env(envUSSDToken, defUSSDToken),
+			Till:   fama.Env(envUSSDTill, defUSSDTill),
+		},
+		ussdTTL: ussdTTL,
+		guestConfig: guest.Config{
+			Secret: fama.Env(envGuestSecret, defGuestSecret),
+			URL:    fama.Env(envGuestURL, defGuestURL),
+			Token:  fama.Env(envGuestToken, defGuestToken),
+		},
+		guestCartTTL: guestCartTTL,
+		location:     location,
+		taxConfig: tax.Config{
+			Prefix: fama.Env(envInvoicePrefix, defInvoicePrefix),
+		},
+		etimsFake: etimsFake,
+		exportDir: fama.Env(envExportDir, defExportDir),
+		exportTTL: exportTTL,
+		staffConfig: staff.Config{
+			PINKey:            []byte(staffPINKey),
+			MaxAttempts:       staffAttempts,
+			MaxVendorAttempts: vendorTries,
+			Lockout:           staffLockout,
+		},
+		requireKey: requireKey,
 	}
 }
 
-func connectToDB(dbConfig postgres.Config, logger kitlog.Logger) *sqlx.DB {
-	db, err := postgres.Connect(dbConfig)
+// connectToDB connects to the database, migrating it unless migrations are
+// left to operators running "jikoni migrate".
+func connectToDB(dbConfig postgres.Config, migrate bool, logger kitlog.Logger) *sqlx.DB {
+	connect := postgres.Connect
+	if !migrate {
+		connect = postgres.Open
+	}
+	db, err := connect(dbConfig)
 	if err != nil {
 		if err := logger.Log("service", svcName, "message", "Failed to connect to postgres", "error", err); err != nil {
 			return nil
@@ -141,33 +535,306 @@ func connectToDB(dbConfig postgres.Config, logger kitlog.Logger) *sqlx.DB {
 	return db
 }
 
-func newService(db *sqlx.DB, logger kitlog.Logger) orders.OrderService {
-	ordersRepo := postgres.NewOrderRepo(db)
+// newOrderRepo returns the repository of the store the orders are kept in.
+// Every service shares it, so orders kept in memory are seen by all of them.
+// The other services keep their data in postgres whatever the store. Orders
+// kept in postgres are rolled up for analytics in the transactions that
+// write them, orders kept elsewhere are only rolled up when the rollups are
+// rebuilt.
+func newOrderRepo(cfg config, db *sqlx.DB, logger kitlog.Logger) orders.OrderRepository {
+	switch cfg.ordersStore {
+	case storeSQLite:
+		sdb, err := orderssqlite.Connect(cfg.sqliteConfig)
+		if err != nil {
+			if err := logger.Log("service", svcName, "message", "Failed to open sqlite", "error", err); err != nil {
+				return nil
+			}
+			os.Exit(1)
+		}
+		return orderssqlite.NewOrderRepo(sdb)
+	case storeMemory:
+		return ordersmemory.NewOrderRepo()
+	default:
+		// Every writer shares the repository, the orders of closed days are
+		// locked whichever way they are written.
+		return postgres.NewOrderRepo(db, drawerpostgres.Lock, analyticspostgres.Record)
+	}
+}
+
+// newService prices orders with the promotions, loyalty and tax services so
+// every channel that creates orders gets the same discounts and taxes. What
+// staff do to orders is attributed to them. A create retried with its
+// idempotency key is answered before anything is redeemed again.
+func newService(ordersRepo orders.OrderRepository, promotionsSvc promotions.Service, taxSvc tax.Service, logger kitlog.Logger, loyaltySvc loyalty.Service, staffSvc staff.Service) orders.OrderService {
 	svc := orders.NewOrderService(ordersRepo)
+	svc = tax.InvoicingMiddleware(svc, taxSvc)
+	svc = loyalty.RedemptionMiddleware(svc, loyaltySvc)
+	svc = promotions.PricingMiddleware(svc, promotionsSvc)
+	svc = staff.AttributionMiddleware(svc, staffSvc)
+	svc = orders.IdempotencyMiddleware(svc, ordersRepo)
 	svc = ordersapi.LoggingMiddleware(svc, kitlog.With(logger, "component", svcName))
-	svc = ordersapi.MetricsMiddleware(
-		svc,
-		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
-			Namespace: strings.Replace(svcName, "-", "_", 1),
-			Subsystem: "api",
-			Name:      "request_count",
-			Help:      "Number of requests received.",
-		}, []string{"method"}),
-		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
-			Namespace: strings.Replace(svcName, "-", "_", 1),
-			Subsystem: "api",
-			Name:      "request_latency_microseconds",
-			Help:      "Total duration of requests in microseconds.",
-		}, []string{"method"}),
-	)
-	return svc
-}
-
-func startHTTPServer(ctx context.Context, svc orders.OrderService, config config, logger kitlog.Logger) error {
+	counter, latency := makeMetrics("api")
+	svc = ordersapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newMenuService(db *sqlx.DB, logger kitlog.Logger) menu.Service {
+	repo := menupostgres.NewMenuRepo(db)
+	svc := menu.NewService(repo)
+	svc = menuapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "menu"))
+	counter, latency := makeMetrics("menu")
+	svc = menuapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newPromotionsService(cfg config, db *sqlx.DB, logger kitlog.Logger) promotions.Service {
+	promosRepo := promotionspostgres.NewPromotionsRepo(db)
+	redemptionsRepo := promotionspostgres.NewRedemptionsRepo(db)
+	svc := promotions.NewService(promotions.Config{Location: cfg.location}, promosRepo, redemptionsRepo)
+	svc = promotionsapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "promotions"))
+	counter, latency := makeMetrics("promotions")
+	svc = promotionsapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newTaxService looks orders up straight from the repository, the orders
+// service in turn invoices through the tax service.
+func newTaxService(cfg config, db *sqlx.DB, ordersRepo orders.OrderRepository, menuSvc menu.Service, logger kitlog.Logger) tax.Service {
+	ratesRepo := taxpostgres.NewRatesRepo(db)
+	invoicesRepo := taxpostgres.NewInvoicesRepo(db)
+	ordersSvc := orders.NewOrderService(ordersRepo)
+	var submitter tax.Submitter
+	if cfg.etimsFake {
+		submitter = etims.NewFake("")
+	}
+	svc := tax.NewService(cfg.taxConfig, ratesRepo, invoicesRepo, ordersSvc, menuSvc, submitter)
+	svc = taxapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "tax"))
+	counter, latency := makeMetrics("tax")
+	svc = taxapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newLoyaltyService(db *sqlx.DB, logger kitlog.Logger) loyalty.Service {
+	programsRepo := loyaltypostgres.NewProgramsRepo(db)
+	cardsRepo := loyaltypostgres.NewCardsRepo(db)
+	ledgerRepo := loyaltypostgres.NewLedgerRepo(db)
+	svc := loyalty.NewService(programsRepo, cardsRepo, ledgerRepo)
+	svc = loyaltyapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "loyalty"))
+	counter, latency := makeMetrics("loyalty")
+	svc = loyaltyapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newInventoryService(db *sqlx.DB, menuSvc menu.Service, logger kitlog.Logger) inventory.Service {
+	ingredientsRepo := inventorypostgres.NewIngredientsRepo(db)
+	recipesRepo := inventorypostgres.NewRecipesRepo(db)
+	movementsRepo := inventorypostgres.NewMovementsRepo(db)
+	svc := inventory.NewService(ingredientsRepo, recipesRepo, movementsRepo, menuSvc)
+	svc = inventoryapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "inventory"))
+	counter, latency := makeMetrics("inventory")
+	svc = inventoryapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newPurchasingService(db *sqlx.DB, inventorySvc inventory.Service, logger kitlog.Logger) purchasing.Service {
+	suppliersRepo := purchasingpostgres.NewSuppliersRepo(db)
+	purchaseOrdersRepo := purchasingpostgres.NewPurchaseOrdersRepo(db)
+	goodsReceivedRepo := purchasingpostgres.NewGoodsReceivedRepo(db)
+	svc := purchasing.NewService(suppliersRepo, purchaseOrdersRepo, goodsReceivedRepo, inventorySvc)
+	svc = purchasingapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "purchasing"))
+	counter, latency := makeMetrics("purchasing")
+	svc = purchasingapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newStaffService(cfg config, db *sqlx.DB, logger kitlog.Logger) staff.Service {
+	membersRepo := staffpostgres.NewMembersRepo(db)
+	shiftsRepo := staffpostgres.NewShiftsRepo(db)
+	timecardsRepo := staffpostgres.NewTimecardsRepo(db)
+	actionsRepo := staffpostgres.NewActionsRepo(db)
+	attemptsRepo := staffpostgres.NewAttemptsRepo(db)
+	svc := staff.NewService(cfg.staffConfig, membersRepo, shiftsRepo, timecardsRepo, actionsRepo, attemptsRepo)
+	svc = staffapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "staff"))
+	counter, latency := makeMetrics("staff")
+	svc = staffapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newDrawerService(db *sqlx.DB, logger kitlog.Logger) drawer.Service {
+	sessionsRepo := drawerpostgres.NewSessionsRepo(db)
+	eventsRepo := drawerpostgres.NewEventsRepo(db)
+	salesRepo := drawerpostgres.NewSalesRepo(db)
+	daysRepo := drawerpostgres.NewDaysRepo(db)
+	svc := drawer.NewService(sessionsRepo, eventsRepo, salesRepo, daysRepo)
+	svc = drawerapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "drawer"))
+	counter, latency := makeMetrics("drawer")
+	svc = drawerapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newAnalyticsService reads orders straight from the repository to rebuild
+// the rollups, the orders service in turn rolls orders up through it.
+func newAnalyticsService(cfg config, db *sqlx.DB, ordersRepo orders.OrderRepository, logger kitlog.Logger) analytics.Service {
+	repo := analyticspostgres.NewRollupsRepo(db)
+	ordersSvc := orders.NewOrderService(ordersRepo)
+	svc := analytics.NewService(analytics.Config{Location: cfg.location}, repo, ordersSvc)
+	svc = analyticsapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "analytics"))
+	counter, latency := makeMetrics("analytics")
+	svc = analyticsapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newExportService reads orders straight from the repository, exports
+// cannot change orders.
+func newExportService(cfg config, db *sqlx.DB, ordersRepo orders.OrderRepository, logger kitlog.Logger) export.Service {
+	files, err := export.NewDiskStore(cfg.exportDir)
+	if err != nil {
+		log.Fatalf("invalid %s: %s", envExportDir, err)
+	}
+	jobsRepo := exportpostgres.NewJobsRepo(db)
+	ordersSvc := orders.NewOrderService(ordersRepo)
+	svc := export.NewService(export.Config{Location: cfg.location, TTL: cfg.exportTTL}, jobsRepo, files, ordersSvc)
+	svc = exportapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "export"))
+	counter, latency := makeMetrics("export")
+	svc = exportapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newBatchService runs batches through the orders service. Imports save
+// orders into the repository as they were taken, and pay them through the
+// service.
+func newBatchService(db *sqlx.DB, ordersRepo orders.OrderRepository, ordersSvc orders.OrderService, logger kitlog.Logger) batch.Service {
+	importsRepo := batchpostgres.NewImportsRepo(db)
+	svc := batch.NewService(ordersSvc, ordersRepo, importsRepo)
+	svc = batchapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "batch"))
+	counter, latency := makeMetrics("batch")
+	svc = batchapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newSyncService saves the orders devices sync into the repository, as they
+// were taken offline, and changes them through the orders service.
+func newSyncService(db *sqlx.DB, ordersSvc orders.OrderService, ordersRepo orders.OrderRepository, staffSvc staff.Service, logger kitlog.Logger) offline.Service {
+	clocksRepo := offlinepostgres.NewClocksRepo(db)
+	svc := offline.NewService(ordersSvc, ordersRepo, clocksRepo)
+	svc = staff.SyncMiddleware(svc, staffSvc)
+	svc = offlineapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "offline"))
+	counter, latency := makeMetrics("offline")
+	svc = offlineapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newGraphQLService serves GraphQL mutations through the orders service
+// and reads straight from the orders and menu repositories.
+func newGraphQLService(db *sqlx.DB, ordersRepo orders.OrderRepository, ordersSvc orders.OrderService, loyaltySvc loyalty.Service, logger kitlog.Logger) graphql.Service {
+	svc := graphql.NewService(ordersSvc, ordersRepo, menupostgres.NewMenuRepo(db), loyaltySvc)
+	svc = graphqlapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "graphql"))
+	counter, latency := makeMetrics("graphql")
+	svc = graphqlapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// notifyHooks notifies the hooks of the orders changing state until the
+// service shuts down. Changes the hooks failed on are notified again.
+func notifyHooks(ctx context.Context, notifier orders.Notifier, logger kitlog.Logger) error {
+	ticker := time.NewTicker(notifyEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := notifier.Notify(ctx, ""); err != nil {
+				logger.Log("service", svcName, "message", "failed to notify the order hooks", "error", err)
+			}
+		}
+	}
+}
+
+// purgeExports removes the export jobs and files that have expired until
+// the service shuts down.
+func purgeExports(ctx context.Context, svc export.Service, logger kitlog.Logger) error {
+	ticker := time.NewTicker(exportPurgeEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := svc.Purge(ctx, ""); err != nil {
+				logger.Log("service", svcName, "message", "failed to purge expired exports", "error", err)
+			}
+		}
+	}
+}
+
+func newTablesService(db *sqlx.DB, ordersSvc orders.OrderService, logger kitlog.Logger) tables.Service {
+	tablesRepo := tablespostgres.NewTablesRepo(db)
+	sessionsRepo := tablespostgres.NewSessionsRepo(db)
+	svc := tables.NewService(tablesRepo, sessionsRepo, ordersSvc)
+	svc = tablesapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "tables"))
+	counter, latency := makeMetrics("tables")
+	svc = tablesapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newBillsService(db *sqlx.DB, ordersSvc orders.OrderService, logger kitlog.Logger) bills.Service {
+	repo := billspostgres.NewSplitsRepo(db)
+	svc := bills.NewService(repo, ordersSvc)
+	svc = billsapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "bills"))
+	counter, latency := makeMetrics("bills")
+	svc = billsapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+// newGuestService keeps carts in postgres so guests at a table share theirs
+// whichever replica their phones reach.
+func newGuestService(cfg config, db *sqlx.DB, tablesSvc tables.Service, menuSvc menu.Service, logger kitlog.Logger) guest.Service {
+	carts := guestpostgres.NewCartRepo(db, cfg.guestCartTTL)
+	svc := guest.NewService(cfg.guestConfig, tablesSvc, menuSvc, carts)
+	svc = guestapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "guest"))
+	counter, latency := makeMetrics("guest")
+	svc = guestapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newChatbotService(cfg config, ordersSvc orders.OrderService, menuSvc menu.Service, logger kitlog.Logger) chatbot.Service {
+	sessions := chatbot.NewMemorySessionRepository(cfg.botSessionTTL)
+	svc := chatbot.NewService(cfg.botConfig, ordersSvc, menuSvc, sessions)
+	svc = chatbotapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "chatbot"))
+	counter, latency := makeMetrics("chatbot")
+	svc = chatbotapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func newUSSDService(cfg config, ordersSvc orders.OrderService, menuSvc menu.Service, logger kitlog.Logger) ussd.Service {
+	sessions := ussd.NewMemorySessionRepository(cfg.ussdTTL)
+	svc := ussd.NewService(cfg.ussdConfig, ordersSvc, menuSvc, sessions)
+	svc = ussdapi.LoggingMiddleware(svc, kitlog.With(logger, "component", "ussd"))
+	counter, latency := makeMetrics("ussd")
+	svc = ussdapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
+
+func makeMetrics(subsystem string) (metrics.Counter, metrics.Histogram) {
+	counter := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: strings.Replace(svcName, "-", "_", 1),
+		Subsystem: subsystem,
+		Name:      "request_count",
+		Help:      "Number of requests received.",
+	}, []string{"method"})
+	latency := kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+		Namespace: strings.Replace(svcName, "-", "_", 1),
+		Subsystem: subsystem,
+		Name:      "request_latency_microseconds",
+		Help:      "Total duration of requests in microseconds.",
+	}, []string{"method"})
+	return counter, latency
+}
+
+func startHTTPServer(ctx context.Context, router *mux.Router, config config, logger kitlog.Logger) error {
 	p := fmt.Sprintf(":%s", config.httpPort)
 	errCh := make(chan error)
-	router := mux.NewRouter()
-	ordersapi.MakeOrdersHandler(svc, router, logger)
 	handler := &ochttp.Handler{Handler: router}
 	server := &http.Server{Addr: p, Handler: handler}
 
@@ -206,3 +873,64 @@ func startHTTPServer(ctx context.Context, svc orders.OrderService, config config
 		return err
 	}
 }
+
+func startGRPCServer(ctx context.Context, ordersServer ordersgrpc.OrderServiceServer, vendorsSvc vendors.Service, config config, logger kitlog.Logger) error {
+	p := fmt.Sprintf(":%s", config.grpcPort)
+	errCh := make(chan error)
+	listener, err := net.Listen("tcp", p)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %w", config.grpcPort, err)
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(kitgrpc.Interceptor, staffapi.PINInterceptor),
+	}
+	if config.requireKey {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(vendorsapi.KeyInterceptor(vendorsSvc)),
+			grpc.ChainStreamInterceptor(vendorsapi.KeyStreamInterceptor(vendorsSvc)),
+		)
+	}
+
+	switch {
+	case config.serverCert != "" || config.serverKey != "":
+		creds, err := credentials.NewServerTLSFromFile(config.serverCert, config.serverKey)
+		if err != nil {
+			return fmt.Errorf("failed to load %s certificates: %w", svcName, err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		if err := logger.Log("transport", svcName, "message", fmt.Sprintf("%s service started using gRPC with TLS", svcName), "exposed_port", config.grpcPort, "cert", config.serverCert, "key", config.serverKey); err != nil {
+			return err
+		}
+	default:
+		if err := logger.Log("transport", svcName, "message", fmt.Sprintf("%s service started using gRPC", svcName), "exposed_port", config.grpcPort); err != nil {
+			return err
+		}
+	}
+	server := grpc.NewServer(opts...)
+	ordersgrpc.RegisterOrderServiceServer(server, ordersServer)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Streams watching orders never end on their own, they are cut once
+		// the wait is over.
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(stopWaitTime):
+			server.Stop()
+		}
+		if err := logger.Log("transport", svcName, "message", fmt.Sprintf("%s service shutdown of gRPC", svcName), "exposed_port", config.grpcPort); err != nil {
+			return err
+		}
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
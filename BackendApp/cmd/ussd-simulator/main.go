@@ -0,0 +1,98 @@
+// Command ussd-simulator walks through USSD sessions against a running
+// jikoni service the way a feature phone on a gateway would.
+//
+// Type the number of a choice and press enter. Two commands control the
+// simulated handset:
+//
+//	:drop  drops the current session, as a lost network would, and dials again
+//	:quit  hangs up
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defURL         = "http://localhost:8180/ussd"
+	defPhone       = "+254700000000"
+	defServiceCode = "*384*1#"
+	defNetworkCode = "63902"
+)
+
+func main() {
+	endpoint := flag.String("url", defURL, "USSD callback URL of the jikoni service")
+	phone := flag.String("phone", defPhone, "phone number of the simulated handset")
+	code := flag.String("code", defServiceCode, "USSD service code being dialled")
+	network := flag.String("network", defNetworkCode, "mobile network code of the simulated handset")
+	flag.Parse()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	stdin := bufio.NewScanner(os.Stdin)
+
+	for {
+		sessionID := fmt.Sprintf("sim-%d", time.Now().UnixNano())
+		fmt.Printf("Dialling %s from %s (session %s)\n", *code, *phone, sessionID)
+
+		var inputs []string
+		for {
+			form := url.Values{
+				"sessionId":   {sessionID},
+				"serviceCode": {*code},
+				"phoneNumber": {*phone},
+				"networkCode": {*network},
+				"text":        {strings.Join(inputs, "*")},
+			}
+			screen, err := post(client, *endpoint, form)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("\n%s\n\n", strings.TrimPrefix(strings.TrimPrefix(screen, "CON "), "END "))
+			if strings.HasPrefix(screen, "END") {
+				fmt.Println("Session ended.")
+				return
+			}
+
+			fmt.Print("> ")
+			if !stdin.Scan() {
+				return
+			}
+			input := strings.TrimSpace(stdin.Text())
+			switch input {
+			case ":quit":
+				return
+			case ":drop":
+				fmt.Println("Session dropped.")
+			default:
+				inputs = append(inputs, input)
+				continue
+			}
+			break
+		}
+	}
+}
+
+func post(client *http.Client, endpoint string, form url.Values) (string, error) {
+	res, err := client.PostForm(endpoint, form)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gateway callback failed with %s: %s", res.Status, body)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func openSessionEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(openSessionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		session := drawer.Session{
+			Vendor:   req.Vendor,
+			Float:    req.Float,
+			OpenedBy: req.OpenedBy,
+			Notes:    req.Notes,
+		}
+		session, err := svc.OpenSession(ctx, req.token, session)
+		if err != nil {
+			return nil, err
+		}
+		return sessionRes{Session: session, created: true}, nil
+	}
+}
+
+func viewSessionEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		session, err := svc.ViewSession(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return sessionRes{Session: session}, nil
+	}
+}
+
+func listSessionsEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := drawer.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Status: req.status,
+		}
+		page, err := svc.ListSessions(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := sessionsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Sessions: []drawer.Session{},
+		}
+		res.Sessions = append(res.Sessions, page.Sessions...)
+		return res, nil
+	}
+}
+
+func recordEventEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(recordEventReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		event, err := svc.RecordEvent(ctx, req.token, req.event)
+		if err != nil {
+			return nil, err
+		}
+		return eventRes{Event: event}, nil
+	}
+}
+
+func closeSessionEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(closeSessionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		session, err := svc.CloseSession(ctx, req.token, req.id, req.Counted, req.Member)
+		if err != nil {
+			return nil, err
+		}
+		return sessionRes{Session: session}, nil
+	}
+}
+
+func reportEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dayReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		report, err := svc.Report(ctx, req.token, req.Vendor, req.date)
+		if err != nil {
+			return nil, err
+		}
+		return reportRes{Report: report}, nil
+	}
+}
+
+func closeDayEndpoint(svc drawer.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dayReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		report, err := svc.CloseDay(ctx, req.token, req.Vendor, req.date, req.Member)
+		if err != nil {
+			return nil, err
+		}
+		return reportRes{Report: report}, nil
+	}
+}
@@ -0,0 +1,144 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/go-kit/log"
+)
+
+var _ drawer.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    drawer.Service
+}
+
+// LoggingMiddleware adds logging facilities to the cash drawer service.
+func LoggingMiddleware(svc drawer.Service, logger log.Logger) drawer.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) OpenSession(ctx context.Context, token string, session drawer.Session) (opened drawer.Session, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "open_session",
+			"token", token,
+			"vendor", session.Vendor,
+			"float", session.Float,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OpenSession(ctx, token, session)
+}
+
+func (lm *loggingMiddleware) ViewSession(ctx context.Context, token, id string) (session drawer.Session, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_session",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewSession(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSessions(ctx context.Context, token string, pm drawer.PageMetadata) (page drawer.SessionsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_sessions",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListSessions(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) RecordEvent(ctx context.Context, token string, event drawer.Event) (recorded drawer.Event, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "record_event",
+			"token", token,
+			"session", event.Session,
+			"type", event.Type,
+			"amount", event.Amount,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RecordEvent(ctx, token, event)
+}
+
+func (lm *loggingMiddleware) CloseSession(ctx context.Context, token, id string, counted uint64, member string) (session drawer.Session, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "close_session",
+			"token", token,
+			"id", id,
+			"counted", counted,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CloseSession(ctx, token, id, counted, member)
+}
+
+func (lm *loggingMiddleware) Report(ctx context.Context, token, vendor, date string) (report drawer.Report, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "z_report",
+			"token", token,
+			"vendor", vendor,
+			"date", date,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Report(ctx, token, vendor, date)
+}
+
+func (lm *loggingMiddleware) CloseDay(ctx context.Context, token, vendor, date, member string) (report drawer.Report, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "close_day",
+			"token", token,
+			"vendor", vendor,
+			"date", date,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CloseDay(ctx, token, vendor, date, member)
+}
+
+func (lm *loggingMiddleware) DayClosed(ctx context.Context, token, vendor string, at time.Time) (closed bool, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "day_closed",
+			"token", token,
+			"vendor", vendor,
+			"at", at,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.DayClosed(ctx, token, vendor, at)
+}
@@ -0,0 +1,101 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ drawer.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     drawer.Service
+}
+
+// MetricsMiddleware instruments the cash drawer service by tracking request count
+// and latency.
+func MetricsMiddleware(svc drawer.Service, counter metrics.Counter, latency metrics.Histogram) drawer.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) OpenSession(ctx context.Context, token string, session drawer.Session) (drawer.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "open_session").Add(1)
+		ms.latency.With("method", "open_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OpenSession(ctx, token, session)
+}
+
+func (ms *metricsMiddleware) ViewSession(ctx context.Context, token, id string) (drawer.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_session").Add(1)
+		ms.latency.With("method", "view_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSession(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListSessions(ctx context.Context, token string, pm drawer.PageMetadata) (drawer.SessionsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_sessions").Add(1)
+		ms.latency.With("method", "list_sessions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSessions(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) RecordEvent(ctx context.Context, token string, event drawer.Event) (drawer.Event, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_event").Add(1)
+		ms.latency.With("method", "record_event").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordEvent(ctx, token, event)
+}
+
+func (ms *metricsMiddleware) CloseSession(ctx context.Context, token, id string, counted uint64, member string) (drawer.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "close_session").Add(1)
+		ms.latency.With("method", "close_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CloseSession(ctx, token, id, counted, member)
+}
+
+func (ms *metricsMiddleware) Report(ctx context.Context, token, vendor, date string) (drawer.Report, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "z_report").Add(1)
+		ms.latency.With("method", "z_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Report(ctx, token, vendor, date)
+}
+
+func (ms *metricsMiddleware) CloseDay(ctx context.Context, token, vendor, date, member string) (drawer.Report, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "close_day").Add(1)
+		ms.latency.With("method", "close_day").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CloseDay(ctx, token, vendor, date, member)
+}
+
+func (ms *metricsMiddleware) DayClosed(ctx context.Context, token, vendor string, at time.Time) (bool, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "day_closed").Add(1)
+		ms.latency.With("method", "day_closed").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DayClosed(ctx, token, vendor, at)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+const maxLimitSize = 100
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type openSessionReq struct {
+	token    string
+	Vendor   string `json:"vendor"`
+	Float    uint64 `json:"float"`
+	OpenedBy string `json:"opened_by,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+func (req openSessionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.Vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type recordEventReq struct {
+	token string
+	event drawer.Event
+}
+
+func (req recordEventReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.event.Session == "" {
+		return errors.ErrMissingID
+	}
+	return req.event.Validate()
+}
+
+type closeSessionReq struct {
+	token   string
+	id      string
+	Counted uint64 `json:"counted"`
+	Member  string `json:"member,omitempty"`
+}
+
+func (req closeSessionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listReq struct {
+	token  string
+	vendor string
+	status string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type dayReq struct {
+	token  string
+	date   string
+	Vendor string `json:"vendor"`
+	Member string `json:"member,omitempty"`
+}
+
+func (req dayReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.Vendor == "" {
+		return errors.ErrInvalidQueryParams
+	}
+	if _, _, err := drawer.Period(req.date); err != nil {
+		return err
+	}
+	return nil
+}
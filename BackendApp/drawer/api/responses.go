@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*sessionRes)(nil)
+	_ Response = (*sessionsPageRes)(nil)
+	_ Response = (*eventRes)(nil)
+	_ Response = (*reportRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type sessionRes struct {
+	drawer.Session
+	created bool
+}
+
+func (res sessionRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res sessionRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/drawer/sessions/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res sessionRes) Empty() bool {
+	return false
+}
+
+type sessionsPageRes struct {
+	pageRes
+	Sessions []drawer.Session `json:"sessions"`
+}
+
+func (res sessionsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res sessionsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res sessionsPageRes) Empty() bool {
+	return false
+}
+
+type eventRes struct {
+	drawer.Event
+}
+
+func (res eventRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res eventRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res eventRes) Empty() bool {
+	return false
+}
+
+type reportRes struct {
+	drawer.Report
+}
+
+func (res reportRes) Code() int {
+	return http.StatusOK
+}
+
+func (res reportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res reportRes) Empty() bool {
+	return false
+}
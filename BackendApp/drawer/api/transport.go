@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	statusKey   = "status"
+)
+
+// MakeDrawerHandler returns a HTTP handler for cash drawer sessions and
+// Z-report API endpoints.
+func MakeDrawerHandler(svc drawer.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/drawer/sessions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint open_session")(openSessionEndpoint(svc)),
+		decodeOpenSession,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/drawer/sessions/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_session")(viewSessionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/drawer/sessions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_sessions")(listSessionsEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/drawer/sessions/{id}/events").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint record_event")(recordEventEndpoint(svc)),
+		decodeRecordEvent,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/drawer/sessions/{id}/close").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint close_session")(closeSessionEndpoint(svc)),
+		decodeCloseSession,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/drawer/days/{date}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint z_report")(reportEndpoint(svc)),
+		decodeReport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/drawer/days/{date}/close").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint close_day")(closeDayEndpoint(svc)),
+		decodeCloseDay,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeOpenSession(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := openSessionReq{token: decodeToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeRecordEvent(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var event drawer.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	event.Session = mux.Vars(r)["id"]
+	req := recordEventReq{
+		token: decodeToken(r),
+		event: event,
+	}
+	return req, nil
+}
+
+func decodeCloseSession(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := closeSessionReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+		status: r.URL.Query().Get(statusKey),
+	}
+	var err error
+	if req.offset, req.limit, err = decodePage(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeReport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := dayReq{
+		token:  decodeToken(r),
+		date:   mux.Vars(r)["date"],
+		Vendor: r.URL.Query().Get(vendorKey),
+	}
+	return req, nil
+}
+
+func decodeCloseDay(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := dayReq{
+		token: decodeToken(r),
+		date:  mux.Vars(r)["date"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodePage(r *http.Request) (uint64, uint64, error) {
+	var offset, limit uint64 = 0, maxLimitSize
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return offset, limit, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, drawer.ErrDrawerOpen),
+		errors.Contains(err, drawer.ErrDrawerClosed),
+		errors.Contains(err, drawer.ErrDayClosed):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package drawer keeps track of the cash in a vendor's till and closes the
+// business day. A drawer session runs from opening the till with a float to
+// counting it at close, the Z-report sums up a business day once it is
+// over.
+package drawer
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+// Cash is the payment method of payments that go into the drawer.
+const Cash = "cash"
+
+// DateLayout is the layout of business days i.e. 2022-03-14.
+const DateLayout = "2006-01-02"
+
+var (
+	// ErrDrawerOpen indicates opening a drawer that is already open, or
+	// closing a day while the drawer is still open.
+	ErrDrawerOpen = errors.New("cash drawer is open")
+
+	// ErrDrawerClosed indicates cash moved in or out of a drawer session
+	// that was closed.
+	ErrDrawerClosed = errors.New("cash drawer is closed")
+
+	// ErrDayClosed indicates closing a business day twice, or writing an
+	// order of a closed day.
+	ErrDayClosed = errors.New("business day is closed")
+)
+
+// Status is the lifecycle state of a drawer session.
+type Status string
+
+// Drawer session statuses.
+const (
+	Open   Status = "open"
+	Closed Status = "closed"
+)
+
+// EventType is how cash moved in or out of the drawer, other than through
+// payments.
+type EventType string
+
+// Drawer event types.
+const (
+	CashIn  EventType = "cash_in"  // Cash put in i.e. change from the bank.
+	CashOut EventType = "cash_out" // Cash taken out i.e. paying a supplier.
+	Refund  EventType = "refund"   // Cash given back to a customer.
+)
+
+// Event is cash moved in or out of the drawer.
+type Event struct {
+	ID        string    `json:"id"`
+	Session   string    `json:"session"`
+	Vendor    string    `json:"vendor"`
+	Type      EventType `json:"type"`
+	Amount    uint64    `json:"amount"`
+	Order     string    `json:"order_id,omitempty"` // The order refunded, if any.
+	Reason    string    `json:"reason,omitempty"`
+	Member    string    `json:"member,omitempty"` // The staff member who moved the cash.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate returns an error if the event representation is invalid.
+func (e Event) Validate() error {
+	if e.Session == "" || e.Amount == 0 {
+		return errors.ErrMalformedEntity
+	}
+	switch e.Type {
+	case CashIn, CashOut, Refund:
+		return nil
+	default:
+		return errors.ErrMalformedEntity
+	}
+}
+
+// Session is a drawer from opening with a float to being counted at close.
+// A vendor has one drawer open at most.
+type Session struct {
+	ID       string    `json:"id"`
+	Vendor   string    `json:"vendor"`
+	Status   Status    `json:"status"`
+	Float    uint64    `json:"float"`              // The cash the drawer was opened with.
+	Expected uint64    `json:"expected,omitempty"` // The cash that should be in the drawer at close.
+	Counted  uint64    `json:"counted,omitempty"`  // The cash counted at close.
+	Variance int64     `json:"variance,omitempty"` // Counted less expected, negative when cash is short.
+	OpenedBy string    `json:"opened_by,omitempty"`
+	ClosedBy string    `json:"closed_by,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+	OpenedAt time.Time `json:"opened_at"`
+	ClosedAt time.Time `json:"closed_at,omitempty"`
+	Events   []Event   `json:"events,omitempty"`
+}
+
+// Close closes the session with the cash counted, expected is the cash
+// that should be in the drawer.
+func (s Session) Close(expected, counted uint64, member string, at time.Time) Session {
+	s.Status = Closed
+	s.Expected = expected
+	s.Counted = counted
+	s.Variance = int64(counted) - int64(expected)
+	s.ClosedBy = member
+	s.ClosedAt = at
+	return s
+}
+
+// MethodTotal sums the payments made with a payment method.
+type MethodTotal struct {
+	Method   string `json:"method"`
+	Payments uint64 `json:"payments"`
+	Amount   uint64 `json:"amount"`
+	Tips     uint64 `json:"tips,omitempty"`
+}
+
+// Sales sums up the orders taken over a period.
+type Sales struct {
+	Orders      uint64 // The orders taken.
+	Paid        uint64 // The orders paid in full.
+	Sales       uint64 // What the orders were charged, net of discounts.
+	Discounts   uint64
+	Taxes       uint64
+	Outstanding uint64 // What is still to be paid on the orders.
+}
+
+// Report is the Z-report of a business day. Once the day is closed the
+// report is kept as it was at close.
+type Report struct {
+	Vendor      string        `json:"vendor"`
+	Date        string        `json:"date"`
+	Closed      bool          `json:"closed"`
+	Orders      uint64        `json:"orders"`
+	Paid        uint64        `json:"paid"`
+	Sales       uint64        `json:"sales"`
+	Discounts   uint64        `json:"discounts"`
+	Taxes       uint64        `json:"taxes"`
+	Outstanding uint64        `json:"outstanding"`
+	Payments    []MethodTotal `json:"payments"`
+	Tips        uint64        `json:"tips"`
+	Refunds     uint64        `json:"refunds"`
+	CashIn      uint64        `json:"cash_in"`
+	CashOut     uint64        `json:"cash_out"`
+	Sessions    uint64        `json:"sessions"` // The drawer sessions closed over the day.
+	Expected    uint64        `json:"expected"`
+	Counted     uint64        `json:"counted"`
+	Variance    int64         `json:"variance"`
+	ClosedBy    string        `json:"closed_by,omitempty"`
+	ClosedAt    time.Time     `json:"closed_at,omitempty"`
+}
+
+// Period returns when the business day of date starts and ends.
+func Period(date string) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation(DateLayout, date, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return from, from.AddDate(0, 0, 1), nil
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Vendor string
+	Status string
+	From   time.Time // Limits sessions to those closed from.
+	To     time.Time // Limits sessions to those closed before.
+}
+
+// SessionsPage contains a page of drawer sessions.
+type SessionsPage struct {
+	PageMetadata
+	Sessions []Session
+}
+
+// Service specifies the cash drawer API.
+type Service interface {
+	// OpenSession opens the vendor's drawer with a float.
+	OpenSession(ctx context.Context, token string, session Session) (Session, error)
+
+	// ViewSession retrieves a drawer session and its events by its unique
+	// identifier ID.
+	ViewSession(ctx context.Context, token, id string) (Session, error)
+
+	// ListSessions retrieves the drawer sessions for a given pageMetadata.
+	ListSessions(ctx context.Context, token string, pm PageMetadata) (SessionsPage, error)
+
+	// RecordEvent records cash moved in or out of an open drawer.
+	RecordEvent(ctx context.Context, token string, event Event) (Event, error)
+
+	// CloseSession closes a drawer with the cash counted. The cash expected
+	// is the float and the cash payments taken while it was open, plus the
+	// cash put in, less the cash taken out and refunded.
+	CloseSession(ctx context.Context, token, id string, counted uint64, member string) (Session, error)
+
+	// Report returns the Z-report of the vendor's business day, as it was
+	// at close once the day is closed.
+	Report(ctx context.Context, token, vendor, date string) (Report, error)
+
+	// CloseDay closes the vendor's business day, keeping its Z-report. The
+	// orders of a closed day can no longer be written.
+	CloseDay(ctx context.Context, token, vendor, date, member string) (Report, error)
+
+	// DayClosed reports whether the vendor's business day at is closed.
+	DayClosed(ctx context.Context, token, vendor string, at time.Time) (bool, error)
+}
+
+// SessionRepository specifies a drawer session persistence API.
+type SessionRepository interface {
+	// Save persists the session. A vendor has one open session at most.
+	Save(ctx context.Context, session Session) (string, error)
+
+	// RetrieveByID retrieves a session by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Session, error)
+
+	// RetrieveOpen retrieves the open session of the vendor.
+	RetrieveOpen(ctx context.Context, vendor string) (Session, error)
+
+	// RetrieveAll retrieves the sessions for a given pageMetadata, newest
+	// first.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (SessionsPage, error)
+
+	// Close closes the session if it is still open.
+	Close(ctx context.Context, session Session) error
+}
+
+// EventQuery selects the drawer events to total. Empty fields match every
+// event.
+type EventQuery struct {
+	Vendor  string
+	Session string
+	From    time.Time
+	To      time.Time
+}
+
+// EventRepository specifies a drawer event persistence API.
+type EventRepository interface {
+	// Save persists the event.
+	Save(ctx context.Context, event Event) error
+
+	// RetrieveAll retrieves the events of a session, oldest first.
+	RetrieveAll(ctx context.Context, session string) ([]Event, error)
+
+	// Totals sums the amounts of the events selected by the query by type.
+	Totals(ctx context.Context, query EventQuery) (map[EventType]uint64, error)
+}
+
+// SalesRepository specifies the API the orders and payments of a vendor are
+// summed up with.
+type SalesRepository interface {
+	// Sales sums up the orders taken between from and to.
+	Sales(ctx context.Context, vendor string, from, to time.Time) (Sales, error)
+
+	// Payments sums the payments made between from and to by method.
+	Payments(ctx context.Context, vendor string, from, to time.Time) ([]MethodTotal, error)
+}
+
+// DayRepository specifies a closed business day persistence API.
+type DayRepository interface {
+	// Close closes the vendor's business day with the Z-report report
+	// takes, persisting it. The report is taken once no more orders of the
+	// day can be written. A day is closed once.
+	Close(ctx context.Context, vendor, date string, report func(ctx context.Context) (Report, error)) (Report, error)
+
+	// Retrieve retrieves the Z-report of the vendor's closed day.
+	Retrieve(ctx context.Context, vendor, date string) (Report, error)
+}
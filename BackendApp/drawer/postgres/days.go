@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ drawer.DayRepository = (*daysRepo)(nil)
+
+type daysRepo struct {
+	db *sqlx.DB
+}
+
+// NewDaysRepo instantiates a PostgreSQL
+// implementation of business days repository.
+func NewDaysRepo(db *sqlx.DB) drawer.DayRepository {
+	return &daysRepo{
+		db: db,
+	}
+}
+
+func (repo daysRepo) Close(ctx context.Context, vendor, date string, report func(ctx context.Context) (drawer.Report, error)) (drawer.Report, error) {
+	q := `INSERT INTO business_days (vendor, date, report, closed_by, closed_at)
+		  VALUES (:vendor, :date, :report, :closed_by, :closed_at)`
+
+	var closed drawer.Report
+	err := pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		// Writes of the day's orders wait for the day to be closed, and
+		// the ones under way are committed before the report is taken.
+		if err := pgutil.Lock(ctx, tx, day{vendor: vendor, date: date}.key()); err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		r, err := report(ctx)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(r)
+		if err != nil {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		dbd := dbDay{
+			Vendor:   vendor,
+			Date:     date,
+			Report:   b,
+			ClosedBy: r.ClosedBy,
+			ClosedAt: r.ClosedAt,
+		}
+		if _, err := tx.NamedExecContext(ctx, q, dbd); err != nil {
+			return handleError(err, errors.ErrCreateEntity)
+		}
+		closed = r
+		return nil
+	})
+	if err != nil {
+		return drawer.Report{}, err
+	}
+	return closed, nil
+}
+
+func (repo daysRepo) Retrieve(ctx context.Context, vendor, date string) (drawer.Report, error) {
+	q := `SELECT report FROM business_days WHERE vendor = $1 AND date = $2`
+
+	var b []byte
+	if err := repo.db.QueryRowxContext(ctx, q, vendor, date).Scan(&b); err != nil {
+		if err == sql.ErrNoRows {
+			return drawer.Report{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return drawer.Report{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	var report drawer.Report
+	if err := json.Unmarshal(b, &report); err != nil {
+		return drawer.Report{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return report, nil
+}
+
+type dbDay struct {
+	Vendor   string    `db:"vendor"`
+	Date     string    `db:"date"`
+	Report   []byte    `db:"report"`
+	ClosedBy string    `db:"closed_by"`
+	ClosedAt time.Time `db:"closed_at"`
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+const eventColumns = `id, session, vendor, type, amount, order_id, reason, member, created_at`
+
+var _ drawer.EventRepository = (*eventsRepo)(nil)
+
+type eventsRepo struct {
+	db *sqlx.DB
+}
+
+// NewEventsRepo instantiates a PostgreSQL
+// implementation of drawer events repository.
+func NewEventsRepo(db *sqlx.DB) drawer.EventRepository {
+	return &eventsRepo{
+		db: db,
+	}
+}
+
+func (repo eventsRepo) Save(ctx context.Context, event drawer.Event) error {
+	q := `INSERT INTO drawer_events (` + eventColumns + `)
+		  VALUES (:id, :session, :vendor, :type, :amount, :order_id, :reason, :member, :created_at)`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, toDBEvent(event)); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo eventsRepo) RetrieveAll(ctx context.Context, session string) ([]drawer.Event, error) {
+	q := `SELECT ` + eventColumns + ` FROM drawer_events WHERE session = $1 ORDER BY created_at`
+
+	rows, err := repo.db.QueryxContext(ctx, q, session)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var events []drawer.Event
+	for rows.Next() {
+		dbe := dbEvent{}
+		if err := rows.StructScan(&dbe); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		events = append(events, toEvent(dbe))
+	}
+	return events, nil
+}
+
+func (repo eventsRepo) Totals(ctx context.Context, query drawer.EventQuery) (map[drawer.EventType]uint64, error) {
+	var where []string
+	var emq string
+	params := map[string]interface{}{
+		"vendor":  query.Vendor,
+		"session": query.Session,
+		"from":    query.From,
+		"to":      query.To,
+	}
+	if query.Vendor != "" {
+		where = append(where, "vendor = :vendor")
+	}
+	if query.Session != "" {
+		where = append(where, "session = :session")
+	}
+	if !query.From.IsZero() {
+		where = append(where, "created_at >= :from")
+	}
+	if !query.To.IsZero() {
+		where = append(where, "created_at < :to")
+	}
+	if len(where) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(where, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT type, COALESCE(SUM(amount), 0) AS amount FROM drawer_events %s GROUP BY type;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	totals := make(map[drawer.EventType]uint64)
+	for rows.Next() {
+		var typ string
+		var amount uint64
+		if err := rows.Scan(&typ, &amount); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		totals[drawer.EventType(typ)] = amount
+	}
+	return totals, nil
+}
+
+type dbEvent struct {
+	ID        string    `db:"id"`
+	Session   string    `db:"session"`
+	Vendor    string    `db:"vendor"`
+	Type      string    `db:"type"`
+	Amount    uint64    `db:"amount"`
+	Order     string    `db:"order_id"`
+	Reason    string    `db:"reason"`
+	Member    string    `db:"member"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBEvent(event drawer.Event) dbEvent {
+	return dbEvent{
+		ID:        event.ID,
+		Session:   event.Session,
+		Vendor:    event.Vendor,
+		Type:      string(event.Type),
+		Amount:    event.Amount,
+		Order:     event.Order,
+		Reason:    event.Reason,
+		Member:    event.Member,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func toEvent(dbe dbEvent) drawer.Event {
+	return drawer.Event{
+		ID:        dbe.ID,
+		Session:   dbe.Session,
+		Vendor:    dbe.Vendor,
+		Type:      drawer.EventType(dbe.Type),
+		Amount:    dbe.Amount,
+		Order:     dbe.Order,
+		Reason:    dbe.Reason,
+		Member:    dbe.Member,
+		CreatedAt: dbe.CreatedAt,
+	}
+}
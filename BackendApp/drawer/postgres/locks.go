@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+)
+
+// day is a vendor's business day.
+type day struct {
+	vendor string
+	date   string
+}
+
+// key is the lock closing the day and writing its orders take.
+func (d day) key() string {
+	return "business_days:" + d.vendor + "/" + d.date
+}
+
+// Lock keeps the orders of closed business days as they were when the
+// day's Z-report was taken: orders taken on a closed day can no longer be
+// saved, updated, paid or deleted. Orders belong to the business day they
+// were taken on. It is a hook of the PostgreSQL orders repository, run in
+// the transaction of every write, so orders created through the service,
+// imported or synced from offline clients are all locked alike. The day's
+// lock is held until the write commits, a day is never closed halfway
+// through one.
+func Lock(ctx context.Context, tx *sqlx.Tx, before, after []orders.Order) error {
+	q := `SELECT EXISTS (SELECT 1 FROM business_days WHERE vendor = $1 AND date = $2)`
+
+	var days []day
+	keys := []string{}
+	seen := map[day]bool{}
+	for _, os := range [][]orders.Order{before, after} {
+		for _, order := range os {
+			d := day{vendor: order.Vendor, date: order.CreatedAt.In(time.Local).Format(drawer.DateLayout)}
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			days = append(days, d)
+			keys = append(keys, d.key())
+		}
+	}
+	if err := pgutil.Lock(ctx, tx, keys...); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	for _, d := range days {
+		var closed bool
+		if err := tx.QueryRowxContext(ctx, q, d.vendor, d.date).Scan(&closed); err != nil {
+			return errors.Wrap(errors.ErrViewEntity, err)
+		}
+		if closed {
+			return errors.Wrap(drawer.ErrDayClosed, errors.ErrConflict)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	orderspostgres "github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/multierr"
+)
+
+const vendor = "mama-oliech"
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := orderspostgres.NewOrderRepo(db, postgres.Lock)
+	days := postgres.NewDaysRepo(db)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	date := yesterday.In(time.Local).Format(drawer.DateLayout)
+	order := orders.Order{ID: ulid.Make().String(), Vendor: vendor, Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered, CreatedAt: yesterday, UpdatedAt: yesterday}
+	if _, err := repo.Save(ctx, order); err != nil {
+		t.Fatalf("saving: %s", err)
+	}
+	today := order
+	today.ID, today.CreatedAt, today.UpdatedAt = ulid.Make().String(), time.Now(), time.Now()
+	if _, err := repo.Save(ctx, today); err != nil {
+		t.Fatalf("saving: %s", err)
+	}
+
+	report := func(ctx context.Context) (drawer.Report, error) {
+		return drawer.Report{Vendor: vendor, Date: date, Closed: true, ClosedBy: "wanjiku", ClosedAt: time.Now()}, nil
+	}
+	if _, err := days.Close(ctx, vendor, date, report); err != nil {
+		t.Fatalf("closing the day: %s", err)
+	}
+	if _, err := days.Close(ctx, vendor, date, report); !is(err, errors.ErrConflict) {
+		t.Errorf("closing the day twice returned %v, want %s", err, errors.ErrConflict)
+	}
+
+	// Every order of the closed day is locked, paid or not.
+	late := order
+	late.ID = ulid.Make().String()
+	if _, err := repo.Save(ctx, late); !is(err, drawer.ErrDayClosed) {
+		t.Errorf("saving an order into a closed day returned %v, want %s", err, drawer.ErrDayClosed)
+	}
+	if _, err := repo.Update(ctx, orders.Order{ID: order.ID, Status: orders.StatusPreparing, UpdatedAt: time.Now()}); !is(err, drawer.ErrDayClosed) {
+		t.Errorf("updating an order of a closed day returned %v, want %s", err, drawer.ErrDayClosed)
+	}
+	payment := orders.Payment{ID: ulid.Make().String(), Order: order.ID, Method: "cash", Amount: 100, CreatedAt: time.Now()}
+	if _, err := repo.AddPayment(ctx, payment); !is(err, drawer.ErrDayClosed) {
+		t.Errorf("paying an order of a closed day returned %v, want %s", err, drawer.ErrDayClosed)
+	}
+	if err := repo.Delete(ctx, order.ID); !is(err, drawer.ErrDayClosed) {
+		t.Errorf("deleting an order of a closed day returned %v, want %s", err, drawer.ErrDayClosed)
+	}
+	// Orders of the days still open are not.
+	if _, err := repo.Update(ctx, orders.Order{ID: today.ID, Status: orders.StatusPreparing, UpdatedAt: time.Now()}); err != nil {
+		t.Errorf("updating an order of an open day: %s", err)
+	}
+}
+
+func is(err, target error) bool {
+	for _, err := range multierr.Errors(err) {
+		if errors.Contains(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ drawer.SalesRepository = (*salesRepo)(nil)
+
+type salesRepo struct {
+	db *sqlx.DB
+}
+
+// NewSalesRepo instantiates a PostgreSQL implementation of sales
+// repository, reading the orders and order payments tables.
+func NewSalesRepo(db *sqlx.DB) drawer.SalesRepository {
+	return &salesRepo{
+		db: db,
+	}
+}
+
+func (repo salesRepo) Sales(ctx context.Context, vendor string, from, to time.Time) (drawer.Sales, error) {
+	q := `SELECT COUNT(*) AS orders,
+			COUNT(*) FILTER (WHERE status = 'paid') AS paid,
+			COALESCE(SUM(price), 0) AS sales,
+			COALESCE(SUM((SELECT SUM(CAST(a->>'amount' AS BIGINT)) FROM jsonb_array_elements(COALESCE(adjustments, '[]')) a)), 0) AS discounts,
+			COALESCE(SUM((SELECT SUM(CAST(t->>'amount' AS BIGINT)) FROM jsonb_array_elements(COALESCE(taxes, '[]')) t)), 0) AS taxes,
+			COALESCE(SUM(GREATEST(price - paid, 0)), 0) AS outstanding
+		  FROM orders WHERE vendor = :vendor AND created_at >= :from AND created_at < :to`
+
+	params := map[string]interface{}{
+		"vendor": vendor,
+		"from":   from,
+		"to":     to,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return drawer.Sales{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	dbs := dbSales{}
+	if rows.Next() {
+		if err := rows.StructScan(&dbs); err != nil {
+			return drawer.Sales{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+	}
+	return drawer.Sales(dbs), nil
+}
+
+func (repo salesRepo) Payments(ctx context.Context, vendor string, from, to time.Time) ([]drawer.MethodTotal, error) {
+	q := `SELECT method, COUNT(*) AS payments, COALESCE(SUM(amount), 0) AS amount, COALESCE(SUM(tip), 0) AS tips
+		  FROM order_payments WHERE vendor = :vendor AND created_at >= :from AND created_at < :to
+		  GROUP BY method ORDER BY method`
+
+	params := map[string]interface{}{
+		"vendor": vendor,
+		"from":   from,
+		"to":     to,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var totals []drawer.MethodTotal
+	for rows.Next() {
+		dbt := dbMethodTotal{}
+		if err := rows.StructScan(&dbt); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		totals = append(totals, drawer.MethodTotal(dbt))
+	}
+	return totals, nil
+}
+
+type dbSales struct {
+	Orders      uint64 `db:"orders"`
+	Paid        uint64 `db:"paid"`
+	Sales       uint64 `db:"sales"`
+	Discounts   uint64 `db:"discounts"`
+	Taxes       uint64 `db:"taxes"`
+	Outstanding uint64 `db:"outstanding"`
+}
+
+type dbMethodTotal struct {
+	Method   string `db:"method"`
+	Payments uint64 `db:"payments"`
+	Amount   uint64 `db:"amount"`
+	Tips     uint64 `db:"tips"`
+}
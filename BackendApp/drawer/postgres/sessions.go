@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/drawer"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+const sessionColumns = `id, vendor, status, opening, expected, counted, variance, opened_by, closed_by, notes, opened_at, closed_at`
+
+var _ drawer.SessionRepository = (*sessionsRepo)(nil)
+
+type sessionsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSessionsRepo instantiates a PostgreSQL
+// implementation of drawer sessions repository.
+func NewSessionsRepo(db *sqlx.DB) drawer.SessionRepository {
+	return &sessionsRepo{
+		db: db,
+	}
+}
+
+func (repo sessionsRepo) Save(ctx context.Context, session drawer.Session) (string, error) {
+	q := `INSERT INTO drawer_sessions (` + sessionColumns + `)
+		  VALUES (:id, :vendor, :status, :opening, :expected, :counted, :variance, :opened_by, :closed_by, :notes, :opened_at, :closed_at) RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBSession(session))
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo sessionsRepo) RetrieveByID(ctx context.Context, id string) (drawer.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM drawer_sessions WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo sessionsRepo) RetrieveOpen(ctx context.Context, vendor string) (drawer.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM drawer_sessions WHERE vendor = $1 AND status = 'open'`
+
+	return repo.retrieve(ctx, q, vendor)
+}
+
+func (repo sessionsRepo) retrieve(ctx context.Context, q string, arg string) (drawer.Session, error) {
+	dbs := dbSession{}
+	if err := repo.db.QueryRowxContext(ctx, q, arg).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return drawer.Session{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return drawer.Session{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toSession(dbs), nil
+}
+
+func (repo sessionsRepo) RetrieveAll(ctx context.Context, pm drawer.PageMetadata) (drawer.SessionsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"status": pm.Status,
+		"from":   pm.From,
+		"to":     pm.To,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Status != "" {
+		query = append(query, "status = :status")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "closed_at >= :from")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "closed_at < :to")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+sessionColumns+` FROM drawer_sessions %s ORDER BY opened_at DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return drawer.SessionsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []drawer.Session
+	for rows.Next() {
+		dbs := dbSession{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return drawer.SessionsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, toSession(dbs))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM drawer_sessions %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return drawer.SessionsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := drawer.SessionsPage{
+		Sessions: items,
+		PageMetadata: drawer.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo sessionsRepo) Close(ctx context.Context, session drawer.Session) error {
+	q := `UPDATE drawer_sessions SET status = :status, expected = :expected, counted = :counted, variance = :variance,
+		  closed_by = :closed_by, notes = :notes, closed_at = :closed_at
+		  WHERE id = :id AND status = 'open'`
+
+	res, err := repo.db.NamedExecContext(ctx, q, toDBSession(session))
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return drawer.ErrDrawerClosed
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbSession struct {
+	ID       string       `db:"id"`
+	Vendor   string       `db:"vendor"`
+	Status   string       `db:"status"`
+	Opening  uint64       `db:"opening"`
+	Expected uint64       `db:"expected"`
+	Counted  uint64       `db:"counted"`
+	Variance int64        `db:"variance"`
+	OpenedBy string       `db:"opened_by"`
+	ClosedBy string       `db:"closed_by"`
+	Notes    string       `db:"notes"`
+	OpenedAt time.Time    `db:"opened_at"`
+	ClosedAt sql.NullTime `db:"closed_at"`
+}
+
+func toDBSession(session drawer.Session) dbSession {
+	return dbSession{
+		ID:       session.ID,
+		Vendor:   session.Vendor,
+		Status:   string(session.Status),
+		Opening:  session.Float,
+		Expected: session.Expected,
+		Counted:  session.Counted,
+		Variance: session.Variance,
+		OpenedBy: session.OpenedBy,
+		ClosedBy: session.ClosedBy,
+		Notes:    session.Notes,
+		OpenedAt: session.OpenedAt,
+		ClosedAt: sql.NullTime{Time: session.ClosedAt, Valid: !session.ClosedAt.IsZero()},
+	}
+}
+
+func toSession(dbs dbSession) drawer.Session {
+	session := drawer.Session{
+		ID:       dbs.ID,
+		Vendor:   dbs.Vendor,
+		Status:   drawer.Status(dbs.Status),
+		Float:    dbs.Opening,
+		Expected: dbs.Expected,
+		Counted:  dbs.Counted,
+		Variance: dbs.Variance,
+		OpenedBy: dbs.OpenedBy,
+		ClosedBy: dbs.ClosedBy,
+		Notes:    dbs.Notes,
+		OpenedAt: dbs.OpenedAt,
+	}
+	if dbs.ClosedAt.Valid {
+		session.ClosedAt = dbs.ClosedAt.Time
+	}
+	return session
+}
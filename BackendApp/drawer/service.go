@@ -0,0 +1,250 @@
+package drawer
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/oklog/ulid/v2"
+)
+
+const pageSize = 100
+
+var _ Service = (*drawerService)(nil)
+
+type drawerService struct {
+	sessions SessionRepository
+	events   EventRepository
+	sales    SalesRepository
+	days     DayRepository
+}
+
+// NewService instantiates the cash drawer service implementation.
+func NewService(sessions SessionRepository, events EventRepository, sales SalesRepository, days DayRepository) Service {
+	return &drawerService{
+		sessions: sessions,
+		events:   events,
+		sales:    sales,
+		days:     days,
+	}
+}
+
+func (svc drawerService) OpenSession(ctx context.Context, token string, session Session) (Session, error) {
+	if session.Vendor == "" {
+		return Session{}, errors.ErrMalformedEntity
+	}
+	session.ID = ulid.Make().String()
+	session.Status = Open
+	session.Expected, session.Counted, session.Variance = 0, 0, 0
+	session.ClosedBy, session.ClosedAt = "", time.Time{}
+	session.OpenedAt = time.Now()
+	session.Events = nil
+	if _, err := svc.sessions.Save(ctx, session); err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return Session{}, ErrDrawerOpen
+		}
+		return Session{}, err
+	}
+	return session, nil
+}
+
+func (svc drawerService) ViewSession(ctx context.Context, token, id string) (Session, error) {
+	session, err := svc.sessions.RetrieveByID(ctx, id)
+	if err != nil {
+		return Session{}, err
+	}
+	if session.Events, err = svc.events.RetrieveAll(ctx, id); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+func (svc drawerService) ListSessions(ctx context.Context, token string, pm PageMetadata) (SessionsPage, error) {
+	return svc.sessions.RetrieveAll(ctx, pm)
+}
+
+func (svc drawerService) RecordEvent(ctx context.Context, token string, event Event) (Event, error) {
+	if err := event.Validate(); err != nil {
+		return Event{}, err
+	}
+	session, err := svc.sessions.RetrieveByID(ctx, event.Session)
+	if err != nil {
+		return Event{}, err
+	}
+	if session.Status != Open {
+		return Event{}, ErrDrawerClosed
+	}
+	event.ID = ulid.Make().String()
+	event.Vendor = session.Vendor
+	event.CreatedAt = time.Now()
+	if err := svc.events.Save(ctx, event); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+func (svc drawerService) CloseSession(ctx context.Context, token, id string, counted uint64, member string) (Session, error) {
+	session, err := svc.sessions.RetrieveByID(ctx, id)
+	if err != nil {
+		return Session{}, err
+	}
+	if session.Status != Open {
+		return Session{}, ErrDrawerClosed
+	}
+
+	now := time.Now()
+	expected, err := svc.expected(ctx, session, now)
+	if err != nil {
+		return Session{}, err
+	}
+	session = session.Close(expected, counted, member, now)
+	if err := svc.sessions.Close(ctx, session); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+// expected returns the cash that should be in the drawer of the session
+// at to.
+func (svc drawerService) expected(ctx context.Context, session Session, to time.Time) (uint64, error) {
+	payments, err := svc.sales.Payments(ctx, session.Vendor, session.OpenedAt, to)
+	if err != nil {
+		return 0, err
+	}
+	totals, err := svc.events.Totals(ctx, EventQuery{Session: session.ID})
+	if err != nil {
+		return 0, err
+	}
+	in := session.Float + totals[CashIn]
+	for _, p := range payments {
+		if p.Method == Cash {
+			in += p.Amount + p.Tips
+		}
+	}
+	out := totals[CashOut] + totals[Refund]
+	if out > in {
+		return 0, nil
+	}
+	return in - out, nil
+}
+
+func (svc drawerService) Report(ctx context.Context, token, vendor, date string) (Report, error) {
+	if vendor == "" {
+		return Report{}, errors.ErrMalformedEntity
+	}
+	report, err := svc.days.Retrieve(ctx, vendor, date)
+	if err == nil {
+		return report, nil
+	}
+	if !errors.Contains(err, errors.ErrNotFound) {
+		return Report{}, err
+	}
+	return svc.report(ctx, vendor, date)
+}
+
+func (svc drawerService) CloseDay(ctx context.Context, token, vendor, date, member string) (Report, error) {
+	if vendor == "" {
+		return Report{}, errors.ErrMalformedEntity
+	}
+	_, to, err := Period(date)
+	if err != nil {
+		return Report{}, err
+	}
+	// A day that has not ended yet can be closed early, but not while cash
+	// taken on it is still in an open drawer.
+	open, err := svc.sessions.RetrieveOpen(ctx, vendor)
+	switch {
+	case err == nil && open.OpenedAt.Before(to):
+		return Report{}, ErrDrawerOpen
+	case err != nil && !errors.Contains(err, errors.ErrNotFound):
+		return Report{}, err
+	}
+
+	report, err := svc.days.Close(ctx, vendor, date, func(ctx context.Context) (Report, error) {
+		report, err := svc.report(ctx, vendor, date)
+		if err != nil {
+			return Report{}, err
+		}
+		report.Closed = true
+		report.ClosedBy = member
+		report.ClosedAt = time.Now()
+		return report, nil
+	})
+	if err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return Report{}, ErrDayClosed
+		}
+		return Report{}, err
+	}
+	return report, nil
+}
+
+func (svc drawerService) DayClosed(ctx context.Context, token, vendor string, at time.Time) (bool, error) {
+	_, err := svc.days.Retrieve(ctx, vendor, at.In(time.Local).Format(DateLayout))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Contains(err, errors.ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// report sums up the vendor's business day of date as it is now.
+func (svc drawerService) report(ctx context.Context, vendor, date string) (Report, error) {
+	from, to, err := Period(date)
+	if err != nil {
+		return Report{}, err
+	}
+	sales, err := svc.sales.Sales(ctx, vendor, from, to)
+	if err != nil {
+		return Report{}, err
+	}
+	payments, err := svc.sales.Payments(ctx, vendor, from, to)
+	if err != nil {
+		return Report{}, err
+	}
+	totals, err := svc.events.Totals(ctx, EventQuery{Vendor: vendor, From: from, To: to})
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Vendor:      vendor,
+		Date:        from.Format(DateLayout),
+		Orders:      sales.Orders,
+		Paid:        sales.Paid,
+		Sales:       sales.Sales,
+		Discounts:   sales.Discounts,
+		Taxes:       sales.Taxes,
+		Outstanding: sales.Outstanding,
+		Payments:    []MethodTotal{},
+		Refunds:     totals[Refund],
+		CashIn:      totals[CashIn],
+		CashOut:     totals[CashOut],
+	}
+	for _, p := range payments {
+		report.Payments = append(report.Payments, p)
+		report.Tips += p.Tips
+	}
+
+	pm := PageMetadata{Vendor: vendor, Status: string(Closed), From: from, To: to, Limit: pageSize}
+	for {
+		page, err := svc.sessions.RetrieveAll(ctx, pm)
+		if err != nil {
+			return Report{}, err
+		}
+		for _, s := range page.Sessions {
+			report.Sessions++
+			report.Expected += s.Expected
+			report.Counted += s.Counted
+			report.Variance += s.Variance
+		}
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"io"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func exportEndpoint(svc export.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		// The export is written out as the response is encoded, errors
+		// met before the first row still get an error response.
+		res := exportRes{
+			format: req.query.Format,
+			export: func(w io.Writer) error {
+				_, err := svc.Export(ctx, req.token, req.query, w)
+				return err
+			},
+		}
+		return res, nil
+	}
+}
+
+func createJobEndpoint(svc export.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		job, err := svc.CreateJob(ctx, req.token, req.query)
+		if err != nil {
+			return nil, err
+		}
+		return newJobRes(job, true), nil
+	}
+}
+
+func viewJobEndpoint(svc export.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(jobReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		job, err := svc.ViewJob(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return newJobRes(job, false), nil
+	}
+}
+
+func downloadEndpoint(svc export.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(jobReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		job, file, err := svc.Download(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return fileRes{job: job, file: file}, nil
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/go-kit/log"
+)
+
+var _ export.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    export.Service
+}
+
+// LoggingMiddleware adds logging facilities to the orders export service.
+func LoggingMiddleware(svc export.Service, logger log.Logger) export.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Export(ctx context.Context, token string, q export.Query, w io.Writer) (rows uint64, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "export_orders",
+			"token", token,
+			"vendor", q.Vendor,
+			"format", q.Format,
+			"from", q.From,
+			"to", q.To,
+			"rows", rows,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Export(ctx, token, q, w)
+}
+
+func (lm *loggingMiddleware) CreateJob(ctx context.Context, token string, q export.Query) (job export.Job, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_export_job",
+			"token", token,
+			"vendor", q.Vendor,
+			"format", q.Format,
+			"from", q.From,
+			"to", q.To,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateJob(ctx, token, q)
+}
+
+func (lm *loggingMiddleware) ViewJob(ctx context.Context, token, id string) (job export.Job, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_export_job",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewJob(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Download(ctx context.Context, token, id string) (job export.Job, file io.ReadCloser, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "download_export",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Download(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Purge(ctx context.Context, token string) (removed uint64, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "purge_exports",
+			"removed", removed,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Purge(ctx, token)
+}
+
+func (lm *loggingMiddleware) Run(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "run_exports",
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Run(ctx, token)
+}
@@ -0,0 +1,84 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ export.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     export.Service
+}
+
+// MetricsMiddleware instruments the orders export service by tracking request count
+// and latency.
+func MetricsMiddleware(svc export.Service, counter metrics.Counter, latency metrics.Histogram) export.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Export(ctx context.Context, token string, q export.Query, w io.Writer) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_orders").Add(1)
+		ms.latency.With("method", "export_orders").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Export(ctx, token, q, w)
+}
+
+func (ms *metricsMiddleware) CreateJob(ctx context.Context, token string, q export.Query) (export.Job, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_export_job").Add(1)
+		ms.latency.With("method", "create_export_job").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateJob(ctx, token, q)
+}
+
+func (ms *metricsMiddleware) ViewJob(ctx context.Context, token, id string) (export.Job, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_export_job").Add(1)
+		ms.latency.With("method", "view_export_job").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewJob(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Download(ctx context.Context, token, id string) (export.Job, io.ReadCloser, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "download_export").Add(1)
+		ms.latency.With("method", "download_export").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Download(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Purge(ctx context.Context, token string) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "purge_exports").Add(1)
+		ms.latency.With("method", "purge_exports").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Purge(ctx, token)
+}
+
+func (ms *metricsMiddleware) Run(ctx context.Context, token string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "run_exports").Add(1)
+		ms.latency.With("method", "run_exports").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Run(ctx, token)
+}
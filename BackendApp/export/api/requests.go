@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+type exportReq struct {
+	token string
+	query export.Query
+}
+
+func (req exportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return nil
+}
+
+type jobReq struct {
+	token string
+	id    string
+}
+
+func (req jobReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var _ Response = (*jobRes)(nil)
+
+// exportRes streams an export as the response body.
+type exportRes struct {
+	format export.Format
+	export func(w io.Writer) error
+}
+
+// fileRes streams the file of an export job as the response body.
+type fileRes struct {
+	job  export.Job
+	file io.ReadCloser
+}
+
+type jobRes struct {
+	export.Job
+	Format   export.Format `json:"format"`
+	Download string        `json:"download,omitempty"` // Where the file can be downloaded from once the job is done.
+	created  bool
+}
+
+func newJobRes(job export.Job, created bool) jobRes {
+	res := jobRes{
+		Job:     job,
+		Format:  job.Query.Format,
+		created: created,
+	}
+	if job.Status == export.Done {
+		res.Download = fmt.Sprintf("/orders/export/jobs/%s/file", job.ID)
+	}
+	return res
+}
+
+func (res jobRes) Code() int {
+	if res.created {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func (res jobRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/orders/export/jobs/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res jobRes) Empty() bool {
+	return false
+}
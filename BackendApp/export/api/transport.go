@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType    = "application/json"
+	vendorKey      = "vendor"
+	nameKey        = "name"
+	priceKey       = "price"
+	placeKey       = "place"
+	statusKey      = "status"
+	outstandingKey = "outstanding"
+	fromKey        = "from"
+	toKey          = "to"
+	formatKey      = "format"
+	fieldsKey      = "fields"
+	tzKey          = "tz"
+	metadataKey    = "metadata"
+)
+
+// MakeExportHandler returns a HTTP handler for orders export API endpoints.
+// It has to be registered before the orders handler, whose /orders/{id}
+// would otherwise take /orders/export.
+func MakeExportHandler(svc export.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("GET").Path("/orders/export").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint export_orders")(exportEndpoint(svc)),
+		decodeExport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/orders/export/jobs").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_export_job")(createJobEndpoint(svc)),
+		decodeExport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/orders/export/jobs/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_export_job")(viewJobEndpoint(svc)),
+		decodeJob,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/orders/export/jobs/{id}/file").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint download_export")(downloadEndpoint(svc)),
+		decodeJob,
+		encodeResponse,
+		opts...,
+	))
+}
+
+// decodeExport reads the same filters as listing orders does, paging
+// aside.
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	req := exportReq{
+		token: decodeToken(r),
+		query: export.Query{
+			Format: export.Format(q.Get(formatKey)),
+		},
+	}
+	req.query.Vendor = q.Get(vendorKey)
+	req.query.Name = q.Get(nameKey)
+	req.query.Place = q.Get(placeKey)
+	req.query.Status = q.Get(statusKey)
+	var err error
+	if q.Has(priceKey) {
+		if req.query.Price, err = strconv.ParseUint(q.Get(priceKey), 10, 64); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(outstandingKey) {
+		if req.query.Outstanding, err = strconv.ParseBool(q.Get(outstandingKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(fromKey) {
+		if req.query.From, err = time.Parse(time.RFC3339, q.Get(fromKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(toKey) {
+		if req.query.To, err = time.Parse(time.RFC3339, q.Get(toKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(metadataKey) {
+		if err = json.Unmarshal([]byte(q.Get(metadataKey)), &req.query.Metadata); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if q.Has(fieldsKey) {
+		for _, f := range strings.Split(q.Get(fieldsKey), ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				req.query.Fields = append(req.query.Fields, f)
+			}
+		}
+	}
+	if q.Has(tzKey) {
+		if req.query.Location, err = time.LoadLocation(q.Get(tzKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeJob(_ context.Context, r *http.Request) (interface{}, error) {
+	req := jobReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	switch res := response.(type) {
+	case exportRes:
+		format := res.format
+		if format == "" {
+			format = export.CSV
+		}
+		aw := &attachmentWriter{
+			w:           w,
+			contentType: format.ContentType(),
+			filename:    fmt.Sprintf("orders.%s", format),
+		}
+		if err := res.export(aw); err != nil && !aw.started {
+			return err
+		}
+		return nil
+	case fileRes:
+		defer res.file.Close()
+		aw := &attachmentWriter{
+			w:           w,
+			contentType: res.job.Query.Format.ContentType(),
+			filename:    fmt.Sprintf("orders-%s.%s", res.job.ID, res.job.Query.Format),
+		}
+		_, err := io.Copy(aw, res.file)
+		return err
+	}
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+// attachmentWriter sends the headers of a file download with the first
+// bytes of the file, so errors met before then still get an error response.
+// Errors met after the download started cut the file short.
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (aw *attachmentWriter) Write(p []byte) (int, error) {
+	if !aw.started {
+		aw.started = true
+		aw.w.Header().Set("Content-Type", aw.contentType)
+		aw.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", aw.filename))
+		aw.w.WriteHeader(http.StatusOK)
+	}
+	return aw.w.Write(p)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, export.ErrNotReady):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, export.ErrExpired):
+		w.WriteHeader(http.StatusGone)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
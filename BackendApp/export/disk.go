@@ -0,0 +1,43 @@
+package export
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var _ FileStore = (*diskStore)(nil)
+
+type diskStore struct {
+	dir string
+}
+
+// NewDiskStore instantiates a file store keeping the files of export jobs
+// in dir, which is created if it does not exist.
+func NewDiskStore(dir string) (FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &diskStore{dir: dir}, nil
+}
+
+func (ds *diskStore) Create(id string) (io.WriteCloser, error) {
+	return os.OpenFile(ds.path(id), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+}
+
+func (ds *diskStore) Open(id string) (io.ReadCloser, error) {
+	return os.Open(ds.path(id))
+}
+
+func (ds *diskStore) Remove(id string) error {
+	if err := os.Remove(ds.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path returns the path of the file of a job. Identifiers are ULIDs so
+// they cannot name a file outside dir.
+func (ds *diskStore) path(id string) string {
+	return filepath.Join(ds.dir, filepath.Base(id))
+}
@@ -0,0 +1,158 @@
+// Package export writes orders out to spreadsheets. Exports are streamed a
+// page of orders at a time so a month of orders never sits in memory, and
+// large exports can run as jobs that leave a file to download until it
+// expires.
+package export
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var (
+	// ErrExpired indicates downloading the file of an export job that has
+	// expired.
+	ErrExpired = errors.New("export has expired")
+
+	// ErrNotReady indicates downloading the file of an export job that has
+	// not completed.
+	ErrNotReady = errors.New("export is not ready")
+
+	// ErrInterrupted indicates an export job that was still running when
+	// the service stopped.
+	ErrInterrupted = errors.New("export was interrupted")
+)
+
+// Format is the file format of an export.
+type Format string
+
+// Export formats.
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ContentType returns the media type of files of the format.
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// Query selects the orders of an export and how they are written out.
+type Query struct {
+	orders.PageMetadata                // Filters the orders as listing them does, paging is ignored.
+	Format              Format         // Defaults to CSV.
+	Fields              []string       // The columns in order, DefaultFields when empty.
+	Location            *time.Location // The time zone times are written in.
+}
+
+// Validate returns an error if the query representation is invalid.
+func (q Query) Validate() error {
+	if q.Location == nil || q.Location == time.Local {
+		return errors.ErrInvalidQueryParams
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return errors.ErrInvalidQueryParams
+	}
+	switch q.Format {
+	case CSV, XLSX:
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+	for _, f := range q.Fields {
+		if _, ok := fields[f]; !ok && f != Metadata && !strings.HasPrefix(f, metadataPrefix) {
+			return errors.ErrInvalidQueryParams
+		}
+	}
+	return nil
+}
+
+// Status is the lifecycle state of an export job.
+type Status string
+
+// Export job statuses.
+const (
+	Pending Status = "pending"
+	Running Status = "running"
+	Done    Status = "done"
+	Failed  Status = "failed"
+)
+
+// Job is an export run in the background that leaves a file to download.
+type Job struct {
+	ID          string    `json:"id"`
+	Status      Status    `json:"status"`
+	Query       Query     `json:"-"`
+	Rows        uint64    `json:"rows"` // The orders written out.
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"` // When the job and its file are removed.
+}
+
+// Service specifies the orders export API.
+type Service interface {
+	// Export writes the orders of the query out to w. It returns the
+	// number of orders written.
+	Export(ctx context.Context, token string, q Query, w io.Writer) (uint64, error)
+
+	// CreateJob starts exporting the orders of the query in the
+	// background.
+	CreateJob(ctx context.Context, token string, q Query) (Job, error)
+
+	// ViewJob retrieves an export job by its unique identifier ID.
+	ViewJob(ctx context.Context, token, id string) (Job, error)
+
+	// Download opens the file of a completed export job.
+	Download(ctx context.Context, token, id string) (Job, io.ReadCloser, error)
+
+	// Purge removes the export jobs and files that have expired. It
+	// returns the number of jobs removed.
+	Purge(ctx context.Context, token string) (uint64, error)
+
+	// Run runs the export jobs created until ctx is done. It first fails
+	// the jobs left unfinished when the service last stopped. Jobs still
+	// running when ctx is done are cancelled and fail, Run returns once
+	// they have.
+	Run(ctx context.Context, token string) error
+}
+
+// JobRepository specifies an export job persistence API.
+type JobRepository interface {
+	// Save persists an export job.
+	Save(ctx context.Context, job Job) error
+
+	// RetrieveByID retrieves an export job by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Job, error)
+
+	// Update updates the status, rows, error and completion of a job.
+	Update(ctx context.Context, job Job) error
+
+	// RemoveExpired removes the jobs that expired before at and returns
+	// their identifiers.
+	RemoveExpired(ctx context.Context, at time.Time) ([]string, error)
+
+	// FailUnfinished fails the jobs created before at that are still
+	// pending or running, with reason as their error. It returns the
+	// number of jobs failed.
+	FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error)
+}
+
+// FileStore specifies where the files of export jobs are kept.
+type FileStore interface {
+	// Create creates the file of the job with the unique identifier id.
+	Create(id string) (io.WriteCloser, error)
+
+	// Open opens the file of the job with the unique identifier id.
+	Open(id string) (io.ReadCloser, error)
+
+	// Remove removes the file of the job with the unique identifier id.
+	Remove(id string) error
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// Metadata selects a column for every metadata key the exported orders
+// have. A single key is selected by its path i.e. metadata.table.
+const Metadata = "metadata"
+
+const metadataPrefix = Metadata + "."
+
+// DefaultFields are the columns of an export that does not select any.
+var DefaultFields = []string{
+	"id", "vendor", "name", "place", "status", "items",
+	"price", "discount", "tax", "paid", "tips", "outstanding",
+	"created_by", "accepted_by", "paid_by", "created_at", "updated_at",
+}
+
+// Cell is a value of a row.
+type Cell struct {
+	Value  string
+	Number bool // Whether the value is written out as a number.
+}
+
+type field func(order orders.Order, loc *time.Location) Cell
+
+var fields = map[string]field{
+	"id":          text(func(o orders.Order) string { return o.ID }),
+	"vendor":      text(func(o orders.Order) string { return o.Vendor }),
+	"name":        text(func(o orders.Order) string { return o.Name }),
+	"place":       text(func(o orders.Order) string { return o.Place }),
+	"status":      text(func(o orders.Order) string { return o.Status }),
+	"items":       text(items),
+	"price":       number(func(o orders.Order) uint64 { return o.Price }),
+	"discount":    number(orders.Order.Discount),
+	"tax":         number(orders.Order.TaxTotal),
+	"paid":        number(func(o orders.Order) uint64 { return o.Paid }),
+	"tips":        number(func(o orders.Order) uint64 { return o.Tips }),
+	"outstanding": number(outstanding),
+	"created_by":  text(func(o orders.Order) string { return o.CreatedBy }),
+	"accepted_by": text(func(o orders.Order) string { return o.AcceptedBy }),
+	"paid_by":     text(func(o orders.Order) string { return o.PaidBy }),
+	"created_at":  timestamp(func(o orders.Order) time.Time { return o.CreatedAt }),
+	"updated_at":  timestamp(func(o orders.Order) time.Time { return o.UpdatedAt }),
+}
+
+func text(f func(orders.Order) string) field {
+	return func(order orders.Order, _ *time.Location) Cell {
+		return Cell{Value: f(order)}
+	}
+}
+
+func number(f func(orders.Order) uint64) field {
+	return func(order orders.Order, _ *time.Location) Cell {
+		return Cell{Value: strconv.FormatUint(f(order), 10), Number: true}
+	}
+}
+
+// timestamp writes times out in the export's time zone. Orders keep the
+// wall clock of the server that took them.
+func timestamp(f func(orders.Order) time.Time) field {
+	return func(order orders.Order, loc *time.Location) Cell {
+		t := f(order)
+		if t.IsZero() {
+			return Cell{}
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+		return Cell{Value: t.In(loc).Format(time.RFC3339)}
+	}
+}
+
+// items writes the item lines of an order out as i.e. "2 x Tea; 1 x Mandazi".
+func items(order orders.Order) string {
+	lines := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		lines = append(lines, fmt.Sprintf("%d x %s", item.Quantity, item.Name))
+	}
+	return strings.Join(lines, "; ")
+}
+
+func outstanding(order orders.Order) uint64 {
+	if order.Paid >= order.Price {
+		return 0
+	}
+	return order.Price - order.Paid
+}
+
+// header returns the header row of the columns.
+func header(columns []string) []Cell {
+	cells := make([]Cell, len(columns))
+	for i, c := range columns {
+		cells[i] = Cell{Value: c}
+	}
+	return cells
+}
+
+// row returns the row of an order for the columns.
+func row(order orders.Order, columns []string, loc *time.Location) []Cell {
+	var meta map[string]string
+	cells := make([]Cell, len(columns))
+	for i, c := range columns {
+		if f, ok := fields[c]; ok {
+			cells[i] = f(order, loc)
+			continue
+		}
+		if meta == nil {
+			meta = flatten(order.Metadata)
+		}
+		cells[i] = Cell{Value: meta[c]}
+	}
+	return cells
+}
+
+// flatten returns the metadata keyed by the path of each value i.e.
+// metadata.customer.phone. Lists are written out as JSON.
+func flatten(metadata orders.Metadata) map[string]string {
+	flat := map[string]string{}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for k, nested := range val {
+				walk(prefix+"."+k, nested)
+			}
+		case orders.Metadata:
+			walk(prefix, map[string]interface{}(val))
+		case nil:
+			flat[prefix] = ""
+		case string:
+			flat[prefix] = val
+		case float64:
+			flat[prefix] = strconv.FormatFloat(val, 'f', -1, 64)
+		case bool:
+			flat[prefix] = strconv.FormatBool(val)
+		default:
+			b, err := json.Marshal(val)
+			if err != nil {
+				flat[prefix] = fmt.Sprint(val)
+				return
+			}
+			flat[prefix] = string(b)
+		}
+	}
+	for k, v := range metadata {
+		walk(metadataPrefix+k, v)
+	}
+	return flat
+}
+
+// metadataKeys returns the sorted paths of the metadata keys.
+func metadataKeys(keys map[string]struct{}) []string {
+	paths := make([]string, 0, len(keys))
+	for k := range keys {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+)
+
+const jobColumns = `id, status, query, rows, error, created_at, completed_at, expires_at`
+
+var _ export.JobRepository = (*jobsRepo)(nil)
+
+type jobsRepo struct {
+	db *sqlx.DB
+}
+
+// NewJobsRepo instantiates a PostgreSQL
+// implementation of export jobs repository.
+func NewJobsRepo(db *sqlx.DB) export.JobRepository {
+	return &jobsRepo{
+		db: db,
+	}
+}
+
+func (repo jobsRepo) Save(ctx context.Context, job export.Job) error {
+	q := `INSERT INTO export_jobs (` + jobColumns + `)
+		  VALUES (:id, :status, :query, :rows, :error, :created_at, :completed_at, :expires_at)`
+
+	dbj, err := toDBJob(job)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, dbj); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo jobsRepo) RetrieveByID(ctx context.Context, id string) (export.Job, error) {
+	q := `SELECT ` + jobColumns + ` FROM export_jobs WHERE id = $1`
+
+	dbj := dbJob{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbj); err != nil {
+		if err == sql.ErrNoRows {
+			return export.Job{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return export.Job{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toJob(dbj)
+}
+
+func (repo jobsRepo) Update(ctx context.Context, job export.Job) error {
+	q := `UPDATE export_jobs SET status = :status, rows = :rows, error = :error, completed_at = :completed_at WHERE id = :id`
+
+	dbj, err := toDBJob(job)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, dbj)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (repo jobsRepo) RemoveExpired(ctx context.Context, at time.Time) ([]string, error) {
+	q := `DELETE FROM export_jobs WHERE expires_at < $1 RETURNING id`
+
+	rows, err := repo.db.QueryxContext(ctx, q, at)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(errors.ErrRemoveEntity, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (repo jobsRepo) FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error) {
+	q := `UPDATE export_jobs SET status = $1, error = $2, completed_at = $3
+		  WHERE status IN ($4, $5) AND created_at < $3`
+
+	res, err := repo.db.ExecContext(ctx, q, export.Failed, reason, at, export.Pending, export.Running)
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return uint64(cnt), nil
+}
+
+// dbQuery is how the query of a job is kept, time zones by name.
+type dbQuery struct {
+	Vendor      string        `json:"vendor,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Price       uint64        `json:"price,omitempty"`
+	Place       string        `json:"place,omitempty"`
+	Status      string        `json:"status,omitempty"`
+	Outstanding bool          `json:"outstanding,omitempty"`
+	From        time.Time     `json:"from,omitempty"`
+	To          time.Time     `json:"to,omitempty"`
+	Format      export.Format `json:"format"`
+	Fields      []string      `json:"fields,omitempty"`
+	Timezone    string        `json:"timezone"`
+}
+
+type dbJob struct {
+	ID          string       `db:"id"`
+	Status      string       `db:"status"`
+	Query       []byte       `db:"query"`
+	Rows        uint64       `db:"rows"`
+	Error       string       `db:"error"`
+	CreatedAt   time.Time    `db:"created_at"`
+	CompletedAt sql.NullTime `db:"completed_at"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+}
+
+func toDBJob(job export.Job) (dbJob, error) {
+	q := dbQuery{
+		Vendor:      job.Query.Vendor,
+		Name:        job.Query.Name,
+		Price:       job.Query.Price,
+		Place:       job.Query.Place,
+		Status:      job.Query.Status,
+		Outstanding: job.Query.Outstanding,
+		From:        job.Query.From,
+		To:          job.Query.To,
+		Format:      job.Query.Format,
+		Fields:      job.Query.Fields,
+	}
+	if job.Query.Location != nil {
+		q.Timezone = job.Query.Location.String()
+	}
+	query, err := json.Marshal(q)
+	if err != nil {
+		return dbJob{}, err
+	}
+	return dbJob{
+		ID:          job.ID,
+		Status:      string(job.Status),
+		Query:       query,
+		Rows:        job.Rows,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: sql.NullTime{Time: job.CompletedAt, Valid: !job.CompletedAt.IsZero()},
+		ExpiresAt:   job.ExpiresAt,
+	}, nil
+}
+
+func toJob(dbj dbJob) (export.Job, error) {
+	var q dbQuery
+	if err := json.Unmarshal(dbj.Query, &q); err != nil {
+		return export.Job{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return export.Job{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	job := export.Job{
+		ID:     dbj.ID,
+		Status: export.Status(dbj.Status),
+		Query: export.Query{
+			PageMetadata: orders.PageMetadata{
+				Vendor:      q.Vendor,
+				Name:        q.Name,
+				Price:       q.Price,
+				Place:       q.Place,
+				Status:      q.Status,
+				Outstanding: q.Outstanding,
+				From:        q.From,
+				To:          q.To,
+			},
+			Format:   q.Format,
+			Fields:   q.Fields,
+			Location: loc,
+		},
+		Rows:      dbj.Rows,
+		Error:     dbj.Error,
+		CreatedAt: dbj.CreatedAt,
+		ExpiresAt: dbj.ExpiresAt,
+	}
+	if dbj.CompletedAt.Valid {
+		job.CompletedAt = dbj.CompletedAt.Time
+	}
+	return job, nil
+}
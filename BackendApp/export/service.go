@@ -0,0 +1,279 @@
+package export
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	pageSize   = 500
+	defaultTTL = 24 * time.Hour
+	// updateTimeout is how long a job cancelled by the service stopping is
+	// given to be marked failed.
+	updateTimeout = 5 * time.Second
+)
+
+// Config defines the options the export service uses.
+type Config struct {
+	Location *time.Location // The time zone times are written in when a query has none. Defaults to UTC.
+	TTL      time.Duration  // How long the files of export jobs can be downloaded for. Defaults to a day.
+}
+
+var _ Service = (*exportService)(nil)
+
+type exportService struct {
+	config Config
+	jobs   JobRepository
+	files  FileStore
+	orders orders.OrderService
+	queue  chan queuedJob
+}
+
+// queuedJob is a job created and waiting for Run to run it.
+type queuedJob struct {
+	token string
+	job   Job
+}
+
+// NewService instantiates the orders export service implementation. Jobs
+// are only run while Run is.
+func NewService(config Config, jobs JobRepository, files FileStore, ordersSvc orders.OrderService) Service {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+	return &exportService{
+		config: config,
+		jobs:   jobs,
+		files:  files,
+		orders: ordersSvc,
+		queue:  make(chan queuedJob),
+	}
+}
+
+func (svc exportService) Export(ctx context.Context, token string, q Query, w io.Writer) (uint64, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return 0, err
+	}
+	columns, err := svc.columns(ctx, token, q)
+	if err != nil {
+		return 0, err
+	}
+	ew, err := NewWriter(q.Format, w)
+	if err != nil {
+		return 0, err
+	}
+	if err := ew.Write(header(columns)); err != nil {
+		return 0, err
+	}
+	var count uint64
+	err = svc.each(ctx, token, q.PageMetadata, func(order orders.Order) error {
+		count++
+		return ew.Write(row(order, columns, q.Location))
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, ew.Close()
+}
+
+func (svc exportService) CreateJob(ctx context.Context, token string, q Query) (Job, error) {
+	q = svc.query(q)
+	if err := q.Validate(); err != nil {
+		return Job{}, err
+	}
+	now := time.Now()
+	job := Job{
+		ID:        ulid.Make().String(),
+		Status:    Pending,
+		Query:     q,
+		CreatedAt: now,
+		ExpiresAt: now.Add(svc.config.TTL),
+	}
+	if err := svc.jobs.Save(ctx, job); err != nil {
+		return Job{}, err
+	}
+	// The job outlives the request that created it, Run runs it.
+	select {
+	case svc.queue <- queuedJob{token: token, job: job}:
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	}
+	return job, nil
+}
+
+func (svc exportService) ViewJob(ctx context.Context, token, id string) (Job, error) {
+	return svc.jobs.RetrieveByID(ctx, id)
+}
+
+func (svc exportService) Download(ctx context.Context, token, id string) (Job, io.ReadCloser, error) {
+	job, err := svc.jobs.RetrieveByID(ctx, id)
+	if err != nil {
+		return Job{}, nil, err
+	}
+	if time.Now().After(job.ExpiresAt) {
+		return Job{}, nil, ErrExpired
+	}
+	if job.Status != Done {
+		return Job{}, nil, ErrNotReady
+	}
+	file, err := svc.files.Open(id)
+	if err != nil {
+		return Job{}, nil, errors.Wrap(errors.ErrNotFound, err)
+	}
+	return job, file, nil
+}
+
+func (svc exportService) Purge(ctx context.Context, token string) (uint64, error) {
+	ids, err := svc.jobs.RemoveExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := svc.files.Remove(id); err != nil {
+			return 0, err
+		}
+	}
+	return uint64(len(ids)), nil
+}
+
+func (svc exportService) Run(ctx context.Context, token string) error {
+	// Jobs are only run while the service is, the jobs left unfinished
+	// when it last stopped never will be.
+	if _, err := svc.jobs.FailUnfinished(ctx, time.Now(), ErrInterrupted.Error()); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case q := <-svc.queue:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				svc.run(ctx, q.token, q.job)
+			}()
+		}
+	}
+}
+
+// run exports the orders of the job to its file and records how it went.
+// A job cancelled by the service stopping is recorded as interrupted.
+func (svc exportService) run(ctx context.Context, token string, job Job) {
+	job.Status = Running
+	if err := svc.jobs.Update(ctx, job); err != nil {
+		return
+	}
+	rows, err := svc.write(ctx, token, job)
+	job.Rows = rows
+	job.CompletedAt = time.Now()
+	job.Status = Done
+	if err != nil {
+		job.Status = Failed
+		job.Error = err.Error()
+	}
+	if ctx.Err() != nil {
+		job.Status = Failed
+		job.Error = ErrInterrupted.Error()
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), updateTimeout)
+		defer cancel()
+	}
+	svc.jobs.Update(ctx, job)
+}
+
+func (svc exportService) write(ctx context.Context, token string, job Job) (uint64, error) {
+	file, err := svc.files.Create(job.ID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := svc.Export(ctx, token, job.Query, file)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return rows, err
+}
+
+// columns returns the columns of the export. Selecting metadata selects
+// every metadata key of the exported orders, so the orders are read twice.
+func (svc exportService) columns(ctx context.Context, token string, q Query) ([]string, error) {
+	if len(q.Fields) == 0 {
+		return DefaultFields, nil
+	}
+	expand := false
+	selected := map[string]struct{}{}
+	for _, f := range q.Fields {
+		selected[f] = struct{}{}
+		if f == Metadata {
+			expand = true
+		}
+	}
+	if !expand {
+		return q.Fields, nil
+	}
+	keys := map[string]struct{}{}
+	err := svc.each(ctx, token, q.PageMetadata, func(order orders.Order) error {
+		for k := range flatten(order.Metadata) {
+			if _, ok := selected[k]; !ok {
+				keys[k] = struct{}{}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, f := range q.Fields {
+		if f == Metadata {
+			columns = append(columns, metadataKeys(keys)...)
+			continue
+		}
+		columns = append(columns, f)
+	}
+	return columns, nil
+}
+
+// each calls fn with the orders of the page metadata a page at a time.
+// Each page is read after the last order of the one before, so orders taken
+// or deleted while exporting neither skip nor repeat the others.
+func (svc exportService) each(ctx context.Context, token string, pm orders.PageMetadata, fn func(orders.Order) error) error {
+	pm.Offset, pm.Limit, pm.After = 0, pageSize, orders.Cursor{}
+	for {
+		page, err := svc.orders.ListOrders(ctx, token, pm)
+		if err != nil {
+			return err
+		}
+		for _, order := range page.Orders {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+		if len(page.Orders) < pageSize {
+			return nil
+		}
+		pm.After = orders.CursorOf(page.Orders[len(page.Orders)-1])
+	}
+}
+
+// query fills in the query's defaults.
+func (svc exportService) query(q Query) Query {
+	if q.Location == nil {
+		q.Location = svc.config.Location
+	}
+	if q.Format == "" {
+		q.Format = CSV
+	}
+	return q
+}
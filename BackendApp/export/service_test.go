@@ -0,0 +1,161 @@
+package export_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// deletingOrders deletes the first order listed once its page is read, as
+// a cashier would while the orders are exported.
+type deletingOrders struct {
+	orders.OrderService
+	deleted bool
+}
+
+func (svc *deletingOrders) ListOrders(ctx context.Context, token string, pm orders.PageMetadata) (orders.OrdersPage, error) {
+	page, err := svc.OrderService.ListOrders(ctx, token, pm)
+	if err != nil || svc.deleted || len(page.Orders) == 0 {
+		return page, err
+	}
+	svc.deleted = true
+	return page, svc.OrderService.DeleteOrder(ctx, token, page.Orders[0].ID)
+}
+
+// jobRepo keeps export jobs in memory.
+type jobRepo struct {
+	export.JobRepository
+	mu   sync.Mutex
+	jobs map[string]export.Job
+}
+
+func (repo *jobRepo) Save(ctx context.Context, job export.Job) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.jobs[job.ID] = job
+	return nil
+}
+
+func (repo *jobRepo) RetrieveByID(ctx context.Context, id string) (export.Job, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	job, ok := repo.jobs[id]
+	if !ok {
+		return export.Job{}, errors.ErrNotFound
+	}
+	return job, nil
+}
+
+func (repo *jobRepo) Update(ctx context.Context, job export.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return repo.Save(ctx, job)
+}
+
+func (repo *jobRepo) FailUnfinished(ctx context.Context, at time.Time, reason string) (uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var n uint64
+	for id, job := range repo.jobs {
+		if (job.Status == export.Pending || job.Status == export.Running) && job.CreatedAt.Before(at) {
+			job.Status, job.Error, job.CompletedAt = export.Failed, reason, at
+			repo.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+// blockingOrders lists no orders until the export is cancelled.
+type blockingOrders struct {
+	orders.OrderService
+	listing chan struct{}
+}
+
+func (svc blockingOrders) ListOrders(ctx context.Context, token string, pm orders.PageMetadata) (orders.OrdersPage, error) {
+	close(svc.listing)
+	<-ctx.Done()
+	return orders.OrdersPage{}, ctx.Err()
+}
+
+func TestRunJobs(t *testing.T) {
+	files, err := export.NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("creating file store: %s", err)
+	}
+	stale := export.Job{ID: ulid.Make().String(), Status: export.Running, CreatedAt: time.Now().Add(-time.Hour)}
+	jobs := &jobRepo{jobs: map[string]export.Job{stale.ID: stale}}
+	ordersSvc := blockingOrders{listing: make(chan struct{})}
+	svc := export.NewService(export.Config{}, jobs, files, ordersSvc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- svc.Run(ctx, token)
+	}()
+	job, err := svc.CreateJob(context.Background(), token, export.Query{PageMetadata: orders.PageMetadata{Vendor: vendor}})
+	if err != nil {
+		t.Fatalf("creating job: %s", err)
+	}
+
+	// The job left running when the service last stopped is failed.
+	if got, _ := jobs.RetrieveByID(ctx, stale.ID); got.Status != export.Failed {
+		t.Errorf("stale job is %s, want %s", got.Status, export.Failed)
+	}
+
+	// The job running as the service stops is cancelled.
+	<-ordersSvc.listing
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("running jobs: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("running jobs did not return once cancelled")
+	}
+	got, _ := jobs.RetrieveByID(context.Background(), job.ID)
+	if got.Status != export.Failed || got.Error != export.ErrInterrupted.Error() {
+		t.Errorf("cancelled job is %s with %q, want %s with %q", got.Status, got.Error, export.Failed, export.ErrInterrupted)
+	}
+}
+
+func TestExportWhileDeleting(t *testing.T) {
+	ctx := context.Background()
+	ordersSvc := orders.NewOrderService(memory.NewOrderRepo())
+	base := time.Now().Add(-time.Hour)
+	const n = 1001
+	for i := 0; i < n; i++ {
+		at := base.Add(time.Duration(i) * time.Millisecond)
+		order := orders.Order{ID: ulid.Make().String(), Vendor: vendor, Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered, CreatedAt: at}
+		if _, err := ordersSvc.CreateOrder(ctx, token, order); err != nil {
+			t.Fatalf("creating order: %s", err)
+		}
+	}
+
+	svc := export.NewService(export.Config{}, nil, nil, &deletingOrders{OrderService: ordersSvc})
+	q := export.Query{PageMetadata: orders.PageMetadata{Vendor: vendor}}
+	rows, err := svc.Export(ctx, token, q, io.Discard)
+	if err != nil {
+		t.Fatalf("exporting: %s", err)
+	}
+	// The order deleted was exported before it was, none after it is
+	// skipped.
+	if rows != n {
+		t.Errorf("exported %d orders, want %d", rows, n)
+	}
+}
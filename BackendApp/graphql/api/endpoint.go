@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func executeEndpoint(svc graphql.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(graphqlReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		return executeRes{svc.Execute(ctx, req.token, req.request)}, nil
+	}
+}
+
+func subscribeEndpoint(svc graphql.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(graphqlReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		responses, err := svc.Subscribe(ctx, req.token, req.request)
+		if err != nil {
+			return nil, err
+		}
+		return subscribeRes{responses: responses}, nil
+	}
+}
@@ -0,0 +1,51 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/go-kit/log"
+)
+
+var _ graphql.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    graphql.Service
+}
+
+// LoggingMiddleware adds logging facilities to the GraphQL service.
+func LoggingMiddleware(svc graphql.Service, logger log.Logger) graphql.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Execute(ctx context.Context, token string, req graphql.Request) (res *graphql.Response) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "execute_graphql",
+			"token", token,
+			"operation", req.OperationName,
+			"took", time.Since(begin),
+			"errors", len(res.Errors),
+		)
+	}(time.Now())
+
+	return lm.svc.Execute(ctx, token, req)
+}
+
+func (lm *loggingMiddleware) Subscribe(ctx context.Context, token string, req graphql.Request) (responses <-chan *graphql.Response, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "subscribe_graphql",
+			"token", token,
+			"operation", req.OperationName,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Subscribe(ctx, token, req)
+}
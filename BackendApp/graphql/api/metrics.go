@@ -0,0 +1,47 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ graphql.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     graphql.Service
+}
+
+// MetricsMiddleware instruments the GraphQL service by tracking request count
+// and latency.
+func MetricsMiddleware(svc graphql.Service, counter metrics.Counter, latency metrics.Histogram) graphql.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Execute(ctx context.Context, token string, req graphql.Request) *graphql.Response {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "execute_graphql").Add(1)
+		ms.latency.With("method", "execute_graphql").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Execute(ctx, token, req)
+}
+
+func (ms *metricsMiddleware) Subscribe(ctx context.Context, token string, req graphql.Request) (<-chan *graphql.Response, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "subscribe_graphql").Add(1)
+		ms.latency.With("method", "subscribe_graphql").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Subscribe(ctx, token, req)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+type graphqlReq struct {
+	token   string
+	request graphql.Request
+}
+
+func (req graphqlReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if strings.TrimSpace(req.request.Query) == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var _ Response = (*executeRes)(nil)
+
+type executeRes struct {
+	*graphql.Response
+}
+
+func (res executeRes) Code() int {
+	return http.StatusOK
+}
+
+func (res executeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res executeRes) Empty() bool {
+	return false
+}
+
+// subscribeRes is the stream of the responses of a subscription, sent as
+// server-sent events.
+type subscribeRes struct {
+	responses <-chan *graphql.Response
+}
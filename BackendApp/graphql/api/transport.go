@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	contentType     = "application/json"
+	eventStreamType = "text/event-stream"
+	nextEvent       = "next"
+	completeEvent   = "complete"
+)
+
+// MakeGraphQLHandler returns a HTTP handler for API endpoints. Operations
+// are posted to /graphql; subscriptions are posted accepting
+// text/event-stream and answered with an event per change.
+func MakeGraphQLHandler(svc graphql.Service, r *mux.Router, logger kitlog.Logger) {
+	// The HTTP trace wraps the response writer in one that cannot flush,
+	// so subscriptions are only traced at the endpoint.
+	streamOpts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+	}
+	opts := append(streamOpts, kitoc.HTTPServerTrace())
+
+	r.Methods("POST").Path("/graphql").HeadersRegexp("Accept", eventStreamType).Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint subscribe_graphql")(subscribeEndpoint(svc)),
+		decodeGraphQL,
+		encodeResponse,
+		streamOpts...,
+	))
+
+	r.Methods("POST").Path("/graphql").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint execute_graphql")(executeEndpoint(svc)),
+		decodeGraphQL,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeGraphQL(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	// Numbers are kept as written so variables of type Int are not read
+	// as floats.
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	var request graphql.Request
+	if err := dec.Decode(&request); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := graphqlReq{
+		token:   decodeToken(r),
+		request: request,
+	}
+	return req, nil
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if sr, ok := response.(subscribeRes); ok {
+		return encodeEvents(ctx, w, sr)
+	}
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeEvents writes a next event per response of the subscription as it
+// comes, and a complete event once the subscription ends.
+func encodeEvents(_ context.Context, w http.ResponseWriter, res subscribeRes) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming unsupported")
+	}
+	w.Header().Set("Content-Type", eventStreamType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for response := range res.responses {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", nextEvent, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata:\n\n", completeEvent); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	// Operations refused before they run are answered the GraphQL way.
+	if errs, ok := err.(gqlerror.List); ok {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(graphql.Response{Errors: errs}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, graphql.ErrInvalidCursor),
+		errors.Contains(err, graphql.ErrNotSubscription):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
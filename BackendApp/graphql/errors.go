@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes, in the extensions of errors.
+const (
+	codeBadInput        = "BAD_USER_INPUT"
+	codeUnauthenticated = "UNAUTHENTICATED"
+	codeForbidden       = "FORBIDDEN"
+	codeNotFound        = "NOT_FOUND"
+	codeConflict        = "CONFLICT"
+	codeInternal        = "INTERNAL_SERVER_ERROR"
+	codeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+)
+
+// presentError returns the GraphQL error of an error. The messages of
+// errors other than the service's own are not sent to clients.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	if gerr, ok := err.(*gqlerror.Error); ok {
+		// Refused by the schema, i.e. an argument of the wrong type.
+		if _, ok := gerr.Extensions["code"]; !ok {
+			errcode.Set(gerr, codeBadInput)
+		}
+		return gerr
+	}
+	gerr := graphql.DefaultErrorPresenter(ctx, err)
+	if e, ok := err.(errors.Error); ok {
+		gerr.Message = e.Msg()
+	} else {
+		gerr.Message = "internal server error"
+	}
+	errcode.Set(gerr, errorCode(err))
+	return gerr
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidStatus),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, ErrInvalidCursor):
+		return codeBadInput
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		return codeUnauthenticated
+	case errors.Contains(err, errors.ErrAuthorization):
+		return codeForbidden
+	case errors.Contains(err, errors.ErrNotFound):
+		return codeNotFound
+	case errors.Contains(err, errors.ErrConflict):
+		return codeConflict
+	}
+	return codeInternal
+}
@@ -0,0 +1,125 @@
+// Package graphql serves orders, the menu and customers over GraphQL, so a
+// dashboard can assemble a view in a single round trip. Orders come with
+// their line items, customer and payments, listed a page at a time through
+// cursors, changed through the orders service and watched through
+// subscriptions.
+//
+// Operations run on gqlgen, against the schema in schema.graphqls.
+// Introspection is not served. What the objects of a page need, i.e. the
+// payments of a page of orders, is loaded at once rather than once per
+// object. Operations nested too deep or too costly are refused before they
+// run.
+package graphql
+
+//go:generate go run github.com/99designs/gqlgen@v0.17.40 generate --config gqlgen.yml
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const (
+	// MaxDepth is how deep the selections of an operation nest at most.
+	MaxDepth = 10
+
+	// MaxComplexity is the cost of an operation at most, one for every
+	// field it would resolve.
+	MaxComplexity = 5000
+
+	// DefaultFirst is how many objects a page has when the query does not
+	// say.
+	DefaultFirst = 20
+
+	// MaxFirst is how many objects a page has at most.
+	MaxFirst = 100
+)
+
+var (
+	// ErrInvalidCursor indicates a cursor not given by the server.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrNotSubscription indicates a subscription requested for an
+	// operation other than a subscription, or the other way round.
+	ErrNotSubscription = errors.New("operation is not a subscription")
+)
+
+// Request is a GraphQL operation to run.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is what running an operation gave.
+type Response = graphql.Response
+
+// Service specifies the GraphQL API.
+type Service interface {
+	// Execute runs a query or a mutation.
+	Execute(ctx context.Context, token string, req Request) *Response
+
+	// Subscribe runs a subscription, sending a response for every event
+	// until ctx is done. The channel is closed once it is.
+	Subscribe(ctx context.Context, token string, req Request) (<-chan *Response, error)
+}
+
+// Customer is who an order was placed by, as told by the customer key of
+// its metadata.
+type Customer struct {
+	ID     string
+	Vendor string
+}
+
+// PageInfo tells where a page is among the others.
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+// OrderConnection is a page of orders listed through cursors.
+type OrderConnection struct {
+	TotalCount uint64
+	Edges      []OrderEdge
+	Nodes      []orders.Order
+	PageInfo   PageInfo
+}
+
+// OrderEdge is an order of a page and its cursor.
+type OrderEdge struct {
+	Cursor string
+	Node   orders.Order
+}
+
+// MenuItemConnection is a page of menu items listed through cursors.
+type MenuItemConnection struct {
+	TotalCount uint64
+	Edges      []MenuItemEdge
+	Nodes      []menu.Item
+	PageInfo   PageInfo
+}
+
+// MenuItemEdge is a menu item of a page and its cursor.
+type MenuItemEdge struct {
+	Cursor string
+	Node   menu.Item
+}
+
+// Order event types.
+const (
+	EventWritten = "WRITTEN"
+	EventDeleted = "DELETED"
+)
+
+// OrderEvent is a change of an order sent to subscriptions.
+type OrderEvent struct {
+	Type   string
+	Cursor string
+	ID     string
+	Order  *orders.Order
+}
@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = (*depthLimit)(nil)
+
+type depthLimit struct {
+	max int
+}
+
+// DepthLimit refuses operations whose selections nest deeper than max
+// before they run.
+func DepthLimit(max int) graphql.HandlerExtension {
+	return &depthLimit{max: max}
+}
+
+func (dl *depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (dl *depthLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (dl *depthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if d := depth(rc.Operation.SelectionSet); d > dl.max {
+		err := gqlerror.ErrorPosf(rc.Operation.Position, "the operation is nested %d deep, more than %d", d, dl.max)
+		errcode.Set(err, codeDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// depth returns how deep the selections nest. Fragments spreading
+// themselves are refused by validation before.
+func depth(sels ast.SelectionSet) int {
+	max := 0
+	for _, sel := range sels {
+		d := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			d = 1 + depth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = depth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = depth(sel.Definition.SelectionSet)
+			}
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return max
+}
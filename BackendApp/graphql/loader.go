@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loadWait is how long a loader waits for more keys once it is asked for
+// the first of a batch.
+const loadWait = 2 * time.Millisecond
+
+// BatchFunc loads the values of many keys at once. Keys without a value
+// are left out of the map.
+type BatchFunc func(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+// Loader batches the loads of the objects of a page, resolved side by side,
+// into a single call of its batch function, and caches what it loaded for
+// the rest of the operation. Loaders are made for a single operation.
+type Loader struct {
+	batch BatchFunc
+
+	mu    sync.Mutex
+	cache map[string]*load
+	queue []string
+}
+
+type load struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewLoader returns a loader loading with batch.
+func NewLoader(batch BatchFunc) *Loader {
+	return &Loader{
+		batch: batch,
+		cache: map[string]*load{},
+	}
+}
+
+// Load returns the value of the key. The keys asked for within loadWait of
+// the first of them are loaded together.
+func (l *Loader) Load(ctx context.Context, key string) (interface{}, error) {
+	l.mu.Lock()
+	ld, ok := l.cache[key]
+	if !ok {
+		ld = &load{done: make(chan struct{})}
+		l.cache[key] = ld
+		l.queue = append(l.queue, key)
+		if len(l.queue) == 1 {
+			time.AfterFunc(loadWait, func() { l.dispatch(ctx) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-ld.done:
+		return ld.value, ld.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch loads the queued keys.
+func (l *Loader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.queue
+	l.queue = nil
+	loads := make([]*load, len(keys))
+	for i, key := range keys {
+		loads[i] = l.cache[key]
+	}
+	l.mu.Unlock()
+
+	values, err := l.batch(ctx, keys)
+	for i, key := range keys {
+		loads[i].value, loads[i].err = values[key], err
+		close(loads[i].done)
+	}
+}
@@ -0,0 +1,38 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"time"
+)
+
+type ItemInput struct {
+	ID       *string `json:"id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Quantity uint64  `json:"quantity"`
+	Price    *uint64 `json:"price,omitempty"`
+}
+
+type OrderFilter struct {
+	Vendor      *string     `json:"vendor,omitempty"`
+	Name        *string     `json:"name,omitempty"`
+	Price       *uint64     `json:"price,omitempty"`
+	Place       *string     `json:"place,omitempty"`
+	Status      *string     `json:"status,omitempty"`
+	Customer    *string     `json:"customer,omitempty"`
+	Outstanding *bool       `json:"outstanding,omitempty"`
+	From        *time.Time  `json:"from,omitempty"`
+	To          *time.Time  `json:"to,omitempty"`
+	Metadata    interface{} `json:"metadata,omitempty"`
+}
+
+type OrderInput struct {
+	Vendor   *string     `json:"vendor,omitempty"`
+	Name     *string     `json:"name,omitempty"`
+	Price    *uint64     `json:"price,omitempty"`
+	Place    *string     `json:"place,omitempty"`
+	Status   *string     `json:"status,omitempty"`
+	Items    []ItemInput `json:"items,omitempty"`
+	Customer *string     `json:"customer,omitempty"`
+	Metadata interface{} `json:"metadata,omitempty"`
+}
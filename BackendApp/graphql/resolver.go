@@ -0,0 +1,491 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var _ ResolverRoot = (*resolver)(nil)
+
+// resolver resolves the fields of the schema through the service.
+type resolver struct {
+	svc *graphqlService
+}
+
+func (r *resolver) Query() QueryResolver               { return queryResolver{r} }
+func (r *resolver) Mutation() MutationResolver         { return mutationResolver{r} }
+func (r *resolver) Subscription() SubscriptionResolver { return subscriptionResolver{r} }
+func (r *resolver) Order() OrderResolver               { return orderResolver{r} }
+func (r *resolver) Item() ItemResolver                 { return itemResolver{r} }
+func (r *resolver) Adjustment() AdjustmentResolver     { return adjustmentResolver{r} }
+func (r *resolver) Tax() TaxResolver                   { return taxResolver{r} }
+func (r *resolver) Payment() PaymentResolver           { return paymentResolver{r} }
+func (r *resolver) MenuItem() MenuItemResolver         { return menuItemResolver{r} }
+func (r *resolver) Customer() CustomerResolver         { return customerResolver{r} }
+
+type queryResolver struct{ *resolver }
+
+func (r queryResolver) Order(ctx context.Context, id string) (*orders.Order, error) {
+	o, err := r.svc.orders.ViewOrder(ctx, stateOf(ctx).token, id)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r queryResolver) Orders(ctx context.Context, filter *OrderFilter, first *uint64, after *string) (*OrderConnection, error) {
+	pm, err := filter.pageMetadata()
+	if err != nil {
+		return nil, err
+	}
+	return r.svc.listOrders(ctx, pm, first, after)
+}
+
+func (r queryResolver) MenuItem(ctx context.Context, id string) (*menu.Item, error) {
+	v, err := stateOf(ctx).items.Load(ctx, id)
+	if v == nil || err != nil {
+		return nil, err
+	}
+	item := v.(menu.Item)
+	return &item, nil
+}
+
+func (r queryResolver) Menu(ctx context.Context, vendor string, name, category *string, available *bool, first *uint64, after *string) (*MenuItemConnection, error) {
+	pm := menu.PageMetadata{
+		Vendor:        vendor,
+		Name:          value(name),
+		Category:      value(category),
+		OnlyAvailable: available != nil && *available,
+	}
+	return r.svc.listMenu(ctx, pm, first, after)
+}
+
+func (r queryResolver) Customer(ctx context.Context, id, vendor string) (*Customer, error) {
+	return &Customer{ID: id, Vendor: vendor}, nil
+}
+
+type mutationResolver struct{ *resolver }
+
+func (r mutationResolver) CreateOrder(ctx context.Context, input OrderInput) (*orders.Order, error) {
+	o, err := input.order()
+	if err != nil {
+		return nil, err
+	}
+	token := stateOf(ctx).token
+	id, err := r.svc.orders.CreateOrder(ctx, token, o)
+	if err != nil {
+		return nil, err
+	}
+	o, err = r.svc.orders.ViewOrder(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r mutationResolver) UpdateOrder(ctx context.Context, id string, input OrderInput) (*orders.Order, error) {
+	o, err := input.order()
+	if err != nil {
+		return nil, err
+	}
+	o.ID = id
+	token := stateOf(ctx).token
+	if _, err := r.svc.orders.UpdateOrder(ctx, token, o); err != nil {
+		return nil, err
+	}
+	o, err = r.svc.orders.ViewOrder(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r mutationResolver) DeleteOrder(ctx context.Context, id string) (string, error) {
+	if err := r.svc.orders.DeleteOrder(ctx, stateOf(ctx).token, id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r mutationResolver) RecordPayment(ctx context.Context, id, method string, amount uint64, tip *uint64) (*orders.Order, error) {
+	var t uint64
+	if tip != nil {
+		t = *tip
+	}
+	o, err := r.svc.orders.RecordPayment(ctx, stateOf(ctx).token, id, method, amount, t)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+type subscriptionResolver struct{ *resolver }
+
+func (r subscriptionResolver) OrderChanged(ctx context.Context, vendor string, since *string) (<-chan *OrderEvent, error) {
+	var seq uint64
+	if since != nil {
+		s, err := parseCursor(seqPrefix, *since)
+		if err != nil {
+			return nil, err
+		}
+		seq = s
+	}
+	return r.svc.watch(ctx, vendor, seq), nil
+}
+
+type orderResolver struct{ *resolver }
+
+func (r orderResolver) Metadata(ctx context.Context, o *orders.Order) (interface{}, error) {
+	if o.Metadata == nil {
+		return nil, nil
+	}
+	return map[string]interface{}(o.Metadata), nil
+}
+
+func (r orderResolver) CreatedBy(ctx context.Context, o *orders.Order) (*string, error) {
+	return optional(o.CreatedBy), nil
+}
+
+func (r orderResolver) AcceptedBy(ctx context.Context, o *orders.Order) (*string, error) {
+	return optional(o.AcceptedBy), nil
+}
+
+func (r orderResolver) PaidBy(ctx context.Context, o *orders.Order) (*string, error) {
+	return optional(o.PaidBy), nil
+}
+
+func (r orderResolver) Customer(ctx context.Context, o *orders.Order) (*Customer, error) {
+	id, _ := o.Metadata[orders.CustomerKey].(string)
+	if id == "" {
+		return nil, nil
+	}
+	return &Customer{ID: id, Vendor: o.Vendor}, nil
+}
+
+func (r orderResolver) Payments(ctx context.Context, o *orders.Order) ([]orders.Payment, error) {
+	v, err := stateOf(ctx).payments.Load(ctx, o.ID)
+	if v == nil || err != nil {
+		return []orders.Payment{}, err
+	}
+	return v.([]orders.Payment), nil
+}
+
+type itemResolver struct{ *resolver }
+
+func (r itemResolver) ID(ctx context.Context, i *orders.Item) (*string, error) {
+	return optional(i.ID), nil
+}
+
+func (r itemResolver) MenuItem(ctx context.Context, i *orders.Item) (*menu.Item, error) {
+	if i.ID == "" {
+		return nil, nil
+	}
+	return queryResolver{r.resolver}.MenuItem(ctx, i.ID)
+}
+
+type adjustmentResolver struct{ *resolver }
+
+func (r adjustmentResolver) Promotion(ctx context.Context, a *orders.Adjustment) (*string, error) {
+	return optional(a.Promotion), nil
+}
+
+func (r adjustmentResolver) Code(ctx context.Context, a *orders.Adjustment) (*string, error) {
+	return optional(a.Code), nil
+}
+
+func (r adjustmentResolver) Name(ctx context.Context, a *orders.Adjustment) (*string, error) {
+	return optional(a.Name), nil
+}
+
+func (r adjustmentResolver) Loyalty(ctx context.Context, a *orders.Adjustment) (*string, error) {
+	return optional(a.Loyalty), nil
+}
+
+type taxResolver struct{ *resolver }
+
+func (r taxResolver) Name(ctx context.Context, t *orders.Tax) (*string, error) {
+	return optional(t.Name), nil
+}
+
+func (r taxResolver) Code(ctx context.Context, t *orders.Tax) (*string, error) {
+	return optional(t.Code), nil
+}
+
+type paymentResolver struct{ *resolver }
+
+func (r paymentResolver) Method(ctx context.Context, p *orders.Payment) (*string, error) {
+	return optional(p.Method), nil
+}
+
+func (r paymentResolver) PaidBy(ctx context.Context, p *orders.Payment) (*string, error) {
+	return optional(p.PaidBy), nil
+}
+
+type menuItemResolver struct{ *resolver }
+
+func (r menuItemResolver) Category(ctx context.Context, i *menu.Item) (*string, error) {
+	return optional(i.Category), nil
+}
+
+func (r menuItemResolver) Metadata(ctx context.Context, i *menu.Item) (interface{}, error) {
+	if i.Metadata == nil {
+		return nil, nil
+	}
+	return map[string]interface{}(i.Metadata), nil
+}
+
+type customerResolver struct{ *resolver }
+
+func (r customerResolver) Orders(ctx context.Context, c *Customer, status *string, first *uint64, after *string) (*OrderConnection, error) {
+	pm := orders.PageMetadata{
+		Vendor:   c.Vendor,
+		Status:   value(status),
+		Metadata: orders.Metadata{orders.CustomerKey: c.ID},
+	}
+	return r.svc.listOrders(ctx, pm, first, after)
+}
+
+func (r customerResolver) Loyalty(ctx context.Context, c *Customer) (*loyalty.Balance, error) {
+	if r.svc.balances == nil {
+		return nil, nil
+	}
+	v, err := stateOf(ctx).balances.Load(ctx, balanceKey(c.Vendor, c.ID))
+	if v == nil || err != nil {
+		return nil, err
+	}
+	b := v.(loyalty.Balance)
+	return &b, nil
+}
+
+// pageComplexity is the cost of a page: its selections once per object of
+// the page.
+func pageComplexity(childComplexity int, first *uint64) int {
+	n := uint64(DefaultFirst)
+	if first != nil {
+		n = *first
+	}
+	if n > MaxFirst {
+		n = MaxFirst
+	}
+	if n < 1 {
+		n = 1
+	}
+	return 1 + int(n)*childComplexity
+}
+
+// page returns the offset and the limit of the page the arguments ask for.
+func page(first *uint64, after *string) (uint64, uint64, error) {
+	limit := uint64(DefaultFirst)
+	if first != nil {
+		limit = *first
+	}
+	if limit > MaxFirst {
+		return 0, 0, errors.ErrLimitSize
+	}
+	var offset uint64
+	if after != nil {
+		o, err := parseCursor(offsetPrefix, *after)
+		if err != nil {
+			return 0, 0, err
+		}
+		offset = o + 1
+	}
+	return offset, limit, nil
+}
+
+func (svc graphqlService) listOrders(ctx context.Context, pm orders.PageMetadata, first *uint64, after *string) (*OrderConnection, error) {
+	offset, limit, err := page(first, after)
+	if err != nil {
+		return nil, err
+	}
+	pm.Offset, pm.Limit = offset, limit
+	op, err := svc.repo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return nil, err
+	}
+	c := &OrderConnection{
+		TotalCount: op.Total,
+		Edges:      make([]OrderEdge, len(op.Orders)),
+		Nodes:      op.Orders,
+		PageInfo:   pageInfo(offset, uint64(len(op.Orders)), op.Total),
+	}
+	for i, o := range op.Orders {
+		c.Edges[i] = OrderEdge{Cursor: offsetCursor(offset + uint64(i)), Node: o}
+	}
+	return c, nil
+}
+
+func (svc graphqlService) listMenu(ctx context.Context, pm menu.PageMetadata, first *uint64, after *string) (*MenuItemConnection, error) {
+	offset, limit, err := page(first, after)
+	if err != nil {
+		return nil, err
+	}
+	pm.Offset, pm.Limit = offset, limit
+	ip, err := svc.menu.RetrieveAll(ctx, pm)
+	if err != nil {
+		return nil, err
+	}
+	c := &MenuItemConnection{
+		TotalCount: ip.Total,
+		Edges:      make([]MenuItemEdge, len(ip.Items)),
+		Nodes:      ip.Items,
+		PageInfo:   pageInfo(offset, uint64(len(ip.Items)), ip.Total),
+	}
+	for i, item := range ip.Items {
+		c.Edges[i] = MenuItemEdge{Cursor: offsetCursor(offset + uint64(i)), Node: item}
+	}
+	return c, nil
+}
+
+// pageInfo tells where the page of n objects at offset is among the total.
+func pageInfo(offset, n, total uint64) PageInfo {
+	info := PageInfo{
+		HasNextPage:     offset+n < total,
+		HasPreviousPage: offset > 0,
+	}
+	if n > 0 {
+		start, end := offsetCursor(offset), offsetCursor(offset+n-1)
+		info.StartCursor, info.EndCursor = &start, &end
+	}
+	return info
+}
+
+// pageMetadata returns the page metadata of the orders the filter lets
+// through.
+func (filter *OrderFilter) pageMetadata() (orders.PageMetadata, error) {
+	pm := orders.PageMetadata{}
+	if filter == nil {
+		return pm, nil
+	}
+	pm.Vendor = value(filter.Vendor)
+	pm.Name = value(filter.Name)
+	pm.Place = value(filter.Place)
+	pm.Status = value(filter.Status)
+	pm.Outstanding = filter.Outstanding != nil && *filter.Outstanding
+	if filter.Price != nil {
+		pm.Price = *filter.Price
+	}
+	if filter.From != nil {
+		pm.From = *filter.From
+	}
+	if filter.To != nil {
+		pm.To = *filter.To
+	}
+	if filter.Metadata != nil {
+		m, ok := filter.Metadata.(map[string]interface{})
+		if !ok {
+			return pm, errors.ErrMalformedEntity
+		}
+		pm.Metadata = m
+	}
+	if filter.Customer != nil {
+		if pm.Metadata == nil {
+			pm.Metadata = orders.Metadata{}
+		}
+		pm.Metadata[orders.CustomerKey] = *filter.Customer
+	}
+	return pm, nil
+}
+
+// order returns the order the input describes.
+func (in OrderInput) order() (orders.Order, error) {
+	o := orders.Order{
+		Vendor: value(in.Vendor),
+		Name:   value(in.Name),
+		Place:  value(in.Place),
+		Status: value(in.Status),
+	}
+	if in.Price != nil {
+		o.Price = *in.Price
+	}
+	for _, i := range in.Items {
+		item := orders.Item{
+			ID:       value(i.ID),
+			Name:     value(i.Name),
+			Quantity: i.Quantity,
+		}
+		if i.Price != nil {
+			item.Price = *i.Price
+		}
+		o.Items = append(o.Items, item)
+	}
+	if in.Metadata != nil {
+		m, ok := in.Metadata.(map[string]interface{})
+		if !ok {
+			return o, errors.ErrMalformedEntity
+		}
+		o.Metadata = m
+	}
+	if in.Customer != nil {
+		if o.Metadata == nil {
+			o.Metadata = orders.Metadata{}
+		}
+		o.Metadata[orders.CustomerKey] = *in.Customer
+	}
+	return o, nil
+}
+
+// optional returns nil for empty strings, so they are null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// value returns the string s points to, empty if there is none.
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Cursor prefixes tell offsets in pages from change sequences.
+const (
+	offsetPrefix = "offset:"
+	seqPrefix    = "seq:"
+)
+
+func offsetCursor(offset uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offsetPrefix + strconv.FormatUint(offset, 10)))
+}
+
+func seqCursor(seq uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(seqPrefix + strconv.FormatUint(seq, 10)))
+}
+
+func parseCursor(prefix, cursor string) (uint64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.Wrap(ErrInvalidCursor, err)
+	}
+	s := string(b)
+	if !strings.HasPrefix(s, prefix) {
+		return 0, ErrInvalidCursor
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, prefix), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(ErrInvalidCursor, err)
+	}
+	return n, nil
+}
+
+func balanceKey(vendor, customer string) string {
+	return vendor + "\x00" + customer
+}
+
+func splitBalanceKey(key string) (string, string) {
+	i := strings.IndexByte(key, 0)
+	return key[:i], key[i+1:]
+}
@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Integers are not limited to 32 bits, amounts of money are whole shillings
+// and so are counts. Points, which can be owed, are signed.
+
+// MarshalInt writes a count or an amount.
+func MarshalInt(n uint64) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		io.WriteString(w, strconv.FormatUint(n, 10))
+	})
+}
+
+// UnmarshalInt reads a count or an amount, which is never negative.
+func UnmarshalInt(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return strconv.ParseUint(string(v), 10, 64)
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case int:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%v is not a count", v)
+}
+
+// MarshalTime writes a point in time in RFC 3339 format, null if it is not
+// set. Times stored by the server are its wall clock.
+func MarshalTime(t time.Time) graphql.Marshaler {
+	if t.IsZero() {
+		return graphql.Null
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	return graphql.MarshalString(t.Format(time.RFC3339Nano))
+}
+
+// UnmarshalTime reads a point in time in RFC 3339 format.
+func UnmarshalTime(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%v is not a time", v)
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// MarshalJSON writes any JSON value, i.e. the metadata of orders.
+func MarshalJSON(v interface{}) graphql.Marshaler {
+	return graphql.MarshalAny(v)
+}
+
+// UnmarshalJSON reads any JSON value.
+func UnmarshalJSON(v interface{}) (interface{}, error) {
+	return v, nil
+}
@@ -0,0 +1,248 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// watchEvery is how often subscriptions look for changes once they
+	// have sent all there were.
+	watchEvery = time.Second
+
+	// watchPage is how many changes subscriptions read at a time.
+	watchPage = 100
+)
+
+// Balances tells the loyalty balances of customers, i.e. the loyalty
+// service.
+type Balances interface {
+	Balance(ctx context.Context, token, vendor, customer string) (loyalty.Balance, error)
+}
+
+var _ Service = (*graphqlService)(nil)
+
+type graphqlService struct {
+	exec     *executor.Executor
+	orders   orders.OrderService
+	repo     orders.OrderRepository
+	menu     menu.Repository
+	balances Balances
+}
+
+// NewService instantiates the GraphQL service implementation. Mutations go
+// through the orders service, queries read the orders and menu
+// repositories. The balances are optional, customers have no loyalty
+// balance without them.
+func NewService(ordersSvc orders.OrderService, repo orders.OrderRepository, items menu.Repository, balances Balances) Service {
+	svc := &graphqlService{
+		orders:   ordersSvc,
+		repo:     repo,
+		menu:     items,
+		balances: balances,
+	}
+	cfg := Config{Resolvers: &resolver{svc: svc}}
+	cfg.Complexity.Query.Orders = func(childComplexity int, _ *OrderFilter, first *uint64, _ *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+	cfg.Complexity.Query.Menu = func(childComplexity int, _ string, _, _ *string, _ *bool, first *uint64, _ *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+	cfg.Complexity.Customer.Orders = func(childComplexity int, _ *string, first *uint64, _ *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+
+	svc.exec = executor.New(NewExecutableSchema(cfg))
+	svc.exec.Use(DepthLimit(MaxDepth))
+	svc.exec.Use(extension.FixedComplexityLimit(MaxComplexity))
+	svc.exec.SetErrorPresenter(presentError)
+	svc.exec.SetRecoverFunc(func(ctx context.Context, v interface{}) error {
+		return fmt.Errorf("resolver panicked: %v", v)
+	})
+	return svc
+}
+
+func (svc graphqlService) Execute(ctx context.Context, token string, req Request) *Response {
+	ctx = graphql.StartOperationTrace(svc.newState(ctx, token))
+	rc, errs := svc.exec.CreateOperationContext(ctx, params(req))
+	if errs != nil {
+		return svc.exec.DispatchError(graphql.WithOperationContext(ctx, rc), errs)
+	}
+	if rc.Operation.Operation == ast.Subscription {
+		err := gqlerror.ErrorPosf(rc.Operation.Position, "subscriptions are served as event streams")
+		return svc.exec.DispatchError(graphql.WithOperationContext(ctx, rc), gqlerror.List{err})
+	}
+	handler, ctx := svc.exec.DispatchOperation(ctx, rc)
+	return handler(ctx)
+}
+
+func (svc graphqlService) Subscribe(ctx context.Context, token string, req Request) (<-chan *Response, error) {
+	ctx = graphql.StartOperationTrace(ctx)
+	rc, errs := svc.exec.CreateOperationContext(ctx, params(req))
+	if errs != nil {
+		return nil, errs
+	}
+	if rc.Operation.Operation != ast.Subscription {
+		return nil, ErrNotSubscription
+	}
+	handler, ctx := svc.exec.DispatchOperation(svc.newState(ctx, token), rc)
+
+	responses := make(chan *Response)
+	go func() {
+		defer close(responses)
+		for {
+			// Every event is an operation of its own, loading afresh.
+			res := handler(svc.newState(ctx, token))
+			if res == nil {
+				return
+			}
+			// The data is written over by the next event.
+			res.Data = append(json.RawMessage(nil), res.Data...)
+			select {
+			case responses <- res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return responses, nil
+}
+
+func params(req Request) *graphql.RawParams {
+	return &graphql.RawParams{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		ReadTime: graphql.TraceTiming{
+			Start: graphql.Now(),
+			End:   graphql.Now(),
+		},
+	}
+}
+
+// watch sends the changes of the vendor's orders after since, looking for
+// new ones every watchEvery until ctx is done. The watch, and with it the
+// subscription, ends on an error.
+func (svc graphqlService) watch(ctx context.Context, vendor string, since uint64) <-chan *OrderEvent {
+	events := make(chan *OrderEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(watchEvery)
+		defer ticker.Stop()
+		for {
+			page, err := svc.repo.RetrieveChanges(ctx, vendor, since, watchPage)
+			if err != nil {
+				return
+			}
+			for _, e := range changeEvents(page) {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			since = page.Seq
+			if page.More {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
+
+// changeEvents returns the changes of the page in the order they happened.
+func changeEvents(page orders.ChangesPage) []*OrderEvent {
+	events := make([]*OrderEvent, 0, len(page.Orders)+len(page.Deleted))
+	i, j := 0, 0
+	for i < len(page.Orders) || j < len(page.Deleted) {
+		if j == len(page.Deleted) || (i < len(page.Orders) && page.Orders[i].Seq < page.Deleted[j].Seq) {
+			o := page.Orders[i]
+			events = append(events, &OrderEvent{Type: EventWritten, Cursor: seqCursor(o.Seq), ID: o.ID, Order: &o})
+			i++
+			continue
+		}
+		t := page.Deleted[j]
+		events = append(events, &OrderEvent{Type: EventDeleted, Cursor: seqCursor(t.Seq), ID: t.ID})
+		j++
+	}
+	return events
+}
+
+type stateKey struct{}
+
+// state is what the resolvers of an operation share: the token it runs
+// with and the loaders batching what its objects need.
+type state struct {
+	token    string
+	payments *Loader
+	items    *Loader
+	balances *Loader
+}
+
+func stateOf(ctx context.Context) *state {
+	return ctx.Value(stateKey{}).(*state)
+}
+
+// newState returns a copy of ctx carrying the state of a new operation.
+func (svc graphqlService) newState(ctx context.Context, token string) context.Context {
+	s := &state{
+		token: token,
+		payments: NewLoader(func(ctx context.Context, ids []string) (map[string]interface{}, error) {
+			payments, err := svc.repo.RetrievePayments(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byOrder := map[string][]orders.Payment{}
+			for _, p := range payments {
+				byOrder[p.Order] = append(byOrder[p.Order], p)
+			}
+			values := map[string]interface{}{}
+			for id, ps := range byOrder {
+				values[id] = ps
+			}
+			return values, nil
+		}),
+		items: NewLoader(func(ctx context.Context, ids []string) (map[string]interface{}, error) {
+			items, err := svc.menu.RetrieveByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			values := map[string]interface{}{}
+			for _, item := range items {
+				values[item.ID] = item
+			}
+			return values, nil
+		}),
+		// Balances are read one customer at a time, the loader keeps
+		// customers on many orders from being read more than once.
+		balances: NewLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			values := map[string]interface{}{}
+			for _, key := range keys {
+				vendor, customer := splitBalanceKey(key)
+				b, err := svc.balances.Balance(ctx, token, vendor, customer)
+				if err != nil {
+					return nil, err
+				}
+				values[key] = b
+			}
+			return values, nil
+		}),
+	}
+	return context.WithValue(ctx, stateKey{}, s)
+}
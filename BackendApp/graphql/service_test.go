@@ -0,0 +1,177 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/graphql"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// paymentsRepo counts the reads of payments.
+type paymentsRepo struct {
+	orders.OrderRepository
+	mu    sync.Mutex
+	reads int
+}
+
+func (repo *paymentsRepo) RetrievePayments(ctx context.Context, ids []string) ([]orders.Payment, error) {
+	repo.mu.Lock()
+	repo.reads++
+	repo.mu.Unlock()
+	return repo.OrderRepository.RetrievePayments(ctx, ids)
+}
+
+// menuRepo has no items.
+type menuRepo struct {
+	menu.Repository
+}
+
+func (menuRepo) RetrieveByIDs(ctx context.Context, ids []string) ([]menu.Item, error) {
+	return nil, nil
+}
+
+func newService(t *testing.T, n int) (graphql.Service, *paymentsRepo) {
+	ctx := context.Background()
+	repo := &paymentsRepo{OrderRepository: memory.NewOrderRepo()}
+	ordersSvc := orders.NewOrderService(repo)
+	for i := 0; i < n; i++ {
+		order := orders.Order{Vendor: vendor, Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered}
+		id, err := ordersSvc.CreateOrder(ctx, token, order)
+		if err != nil {
+			t.Fatalf("creating order: %s", err)
+		}
+		if _, err := ordersSvc.RecordPayment(ctx, token, id, "cash", 50, 0); err != nil {
+			t.Fatalf("paying order: %s", err)
+		}
+	}
+	return graphql.NewService(ordersSvc, repo, menuRepo{}, nil), repo
+}
+
+func TestExecute(t *testing.T) {
+	svc, repo := newService(t, 3)
+	q := `query($vendor: String) {
+		orders(filter: {vendor: $vendor}, first: 2) {
+			totalCount
+			nodes { name price paid createdBy payments { amount method } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+	res := svc.Execute(context.Background(), token, graphql.Request{Query: q, Variables: map[string]interface{}{"vendor": vendor}})
+	if len(res.Errors) > 0 {
+		t.Fatalf("running query: %v", res.Errors)
+	}
+	var data struct {
+		Orders struct {
+			TotalCount int
+			Nodes      []struct {
+				Name      string
+				Price     int
+				Paid      int
+				CreatedBy *string
+				Payments  []struct {
+					Amount int
+					Method string
+				}
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   string
+			}
+		}
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		t.Fatalf("decoding data: %s", err)
+	}
+	if data.Orders.TotalCount != 3 || len(data.Orders.Nodes) != 2 || !data.Orders.PageInfo.HasNextPage {
+		t.Errorf("listed %d of %d orders, want 2 of 3 with a next page", len(data.Orders.Nodes), data.Orders.TotalCount)
+	}
+	for _, o := range data.Orders.Nodes {
+		if o.CreatedBy != nil {
+			t.Errorf("unattributed order created by %q, want null", *o.CreatedBy)
+		}
+		if len(o.Payments) != 1 || o.Payments[0].Amount != 50 {
+			t.Errorf("order has payments %+v, want one of 50", o.Payments)
+		}
+	}
+	// The payments of the page are read at once.
+	if repo.reads != 1 {
+		t.Errorf("payments read %d times, want once", repo.reads)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	svc, _ := newService(t, 0)
+	cases := []struct {
+		desc  string
+		query string
+		err   string
+	}{
+		{
+			desc:  "nested too deep",
+			query: `{ orders { nodes { customer { orders { nodes { customer { orders { nodes { customer { orders { nodes { id } } } } } } } } } } } }`,
+			err:   "nested",
+		},
+		{
+			desc:  "too costly",
+			query: `{ orders(first: 100) { nodes { customer { orders(first: 100) { totalCount } } } } }`,
+			err:   "complexity",
+		},
+		{
+			desc:  "page too large",
+			query: `{ orders(first: 101) { totalCount } }`,
+			err:   "limit",
+		},
+		{
+			desc:  "introspection",
+			query: `{ __schema { types { name } } }`,
+			err:   "introspection",
+		},
+	}
+	for _, tc := range cases {
+		res := svc.Execute(context.Background(), token, graphql.Request{Query: tc.query})
+		if len(res.Errors) == 0 || !strings.Contains(strings.ToLower(res.Errors[0].Message), tc.err) {
+			t.Errorf("%s: got errors %v, want one about %s", tc.desc, res.Errors, tc.err)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	svc, _ := newService(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := `subscription { orderChanged(vendor: "` + vendor + `") { type order { name payments { amount } } } }`
+	responses, err := svc.Subscribe(ctx, token, graphql.Request{Query: q})
+	if err != nil {
+		t.Fatalf("subscribing: %s", err)
+	}
+	// The changes are the orders as they were last written.
+	for i := 0; i < 2; i++ {
+		select {
+		case res := <-responses:
+			if len(res.Errors) > 0 {
+				t.Fatalf("receiving event: %v", res.Errors)
+			}
+			if !strings.Contains(string(res.Data), `"type":"WRITTEN"`) || !strings.Contains(string(res.Data), `"amount":50`) {
+				t.Errorf("received %s, want a paid order written", res.Data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d events, want 2", i)
+		}
+	}
+
+	if _, err := svc.Subscribe(ctx, token, graphql.Request{Query: `{ orders { totalCount } }`}); err != graphql.ErrNotSubscription {
+		t.Errorf("subscribing to a query returned %v, want %s", err, graphql.ErrNotSubscription)
+	}
+}
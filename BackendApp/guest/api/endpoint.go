@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func viewTableEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(guestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		table, err := svc.Table(ctx, req.tableToken)
+		if err != nil {
+			return nil, err
+		}
+		cart, err := svc.ViewCart(ctx, req.tableToken)
+		if err != nil {
+			return nil, err
+		}
+		return toTableRes(table, cart), nil
+	}
+}
+
+func viewMenuEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(guestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		items, err := svc.Menu(ctx, req.tableToken)
+		if err != nil {
+			return nil, err
+		}
+		return menuRes{Items: items}, nil
+	}
+}
+
+func viewCartEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(guestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cart, err := svc.ViewCart(ctx, req.tableToken)
+		if err != nil {
+			return nil, err
+		}
+		return toCartRes(cart), nil
+	}
+}
+
+func addItemEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cart, err := svc.AddItem(ctx, req.tableToken, req.ItemID, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		return toCartRes(cart), nil
+	}
+}
+
+func updateItemEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cart, err := svc.UpdateItem(ctx, req.tableToken, req.itemID, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		return toCartRes(cart), nil
+	}
+}
+
+func removeItemEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(removeItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cart, err := svc.RemoveItem(ctx, req.tableToken, req.itemID)
+		if err != nil {
+			return nil, err
+		}
+		return toCartRes(cart), nil
+	}
+}
+
+func checkoutEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(checkoutReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.Checkout(ctx, req.tableToken, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		return checkoutRes{OrderID: id}, nil
+	}
+}
+
+func viewLinkEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(linkReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		url, err := svc.Link(ctx, req.token, req.tableID)
+		if err != nil {
+			return nil, err
+		}
+		return linkRes{URL: url}, nil
+	}
+}
+
+func rotateLinkEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(linkReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		url, err := svc.RotateLink(ctx, req.token, req.tableID)
+		if err != nil {
+			return nil, err
+		}
+		return linkRes{URL: url}, nil
+	}
+}
+
+func qrEndpoint(svc guest.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(qrReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		url, err := svc.Link(ctx, req.token, req.tableID)
+		if err != nil {
+			return nil, err
+		}
+		if req.format == svgFormat {
+			image, err := guest.SVG(url)
+			if err != nil {
+				return nil, err
+			}
+			return qrRes{contentType: svgContentType, image: image}, nil
+		}
+		image, err := guest.PNG(url, req.size)
+		if err != nil {
+			return nil, err
+		}
+		return qrRes{contentType: pngContentType, image: image}, nil
+	}
+}
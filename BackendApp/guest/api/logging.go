@@ -0,0 +1,145 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/go-kit/log"
+)
+
+var _ guest.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    guest.Service
+}
+
+// LoggingMiddleware adds logging facilities to the guest service. Table
+// tokens are not logged since they grant ordering at the table.
+func LoggingMiddleware(svc guest.Service, logger log.Logger) guest.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Table(ctx context.Context, tableToken string) (table tables.Table, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_view_table",
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Table(ctx, tableToken)
+}
+
+func (lm *loggingMiddleware) Menu(ctx context.Context, tableToken string) (items []menu.Item, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_view_menu",
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Menu(ctx, tableToken)
+}
+
+func (lm *loggingMiddleware) ViewCart(ctx context.Context, tableToken string) (cart guest.Cart, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_view_cart",
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewCart(ctx, tableToken)
+}
+
+func (lm *loggingMiddleware) AddItem(ctx context.Context, tableToken, itemID string, quantity uint64) (cart guest.Cart, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_add_item",
+			"item_id", itemID,
+			"quantity", quantity,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.AddItem(ctx, tableToken, itemID, quantity)
+}
+
+func (lm *loggingMiddleware) UpdateItem(ctx context.Context, tableToken, itemID string, quantity uint64) (cart guest.Cart, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_update_item",
+			"item_id", itemID,
+			"quantity", quantity,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateItem(ctx, tableToken, itemID, quantity)
+}
+
+func (lm *loggingMiddleware) RemoveItem(ctx context.Context, tableToken, itemID string) (cart guest.Cart, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_remove_item",
+			"item_id", itemID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveItem(ctx, tableToken, itemID)
+}
+
+func (lm *loggingMiddleware) Checkout(ctx context.Context, tableToken string, version uint64) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "guest_checkout",
+			"version", version,
+			"order_id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Checkout(ctx, tableToken, version)
+}
+
+func (lm *loggingMiddleware) Link(ctx context.Context, token, tableID string) (url string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_table_link",
+			"token", token,
+			"table_id", tableID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Link(ctx, token, tableID)
+}
+
+func (lm *loggingMiddleware) RotateLink(ctx context.Context, token, tableID string) (url string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "rotate_table_link",
+			"token", token,
+			"table_id", tableID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RotateLink(ctx, token, tableID)
+}
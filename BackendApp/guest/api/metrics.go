@@ -0,0 +1,112 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ guest.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     guest.Service
+}
+
+// MetricsMiddleware instruments the guest service by tracking request count
+// and latency.
+func MetricsMiddleware(svc guest.Service, counter metrics.Counter, latency metrics.Histogram) guest.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Table(ctx context.Context, tableToken string) (tables.Table, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_view_table").Add(1)
+		ms.latency.With("method", "guest_view_table").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Table(ctx, tableToken)
+}
+
+func (ms *metricsMiddleware) Menu(ctx context.Context, tableToken string) ([]menu.Item, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_view_menu").Add(1)
+		ms.latency.With("method", "guest_view_menu").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Menu(ctx, tableToken)
+}
+
+func (ms *metricsMiddleware) ViewCart(ctx context.Context, tableToken string) (guest.Cart, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_view_cart").Add(1)
+		ms.latency.With("method", "guest_view_cart").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewCart(ctx, tableToken)
+}
+
+func (ms *metricsMiddleware) AddItem(ctx context.Context, tableToken, itemID string, quantity uint64) (guest.Cart, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_add_item").Add(1)
+		ms.latency.With("method", "guest_add_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddItem(ctx, tableToken, itemID, quantity)
+}
+
+func (ms *metricsMiddleware) UpdateItem(ctx context.Context, tableToken, itemID string, quantity uint64) (guest.Cart, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_update_item").Add(1)
+		ms.latency.With("method", "guest_update_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateItem(ctx, tableToken, itemID, quantity)
+}
+
+func (ms *metricsMiddleware) RemoveItem(ctx context.Context, tableToken, itemID string) (guest.Cart, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_remove_item").Add(1)
+		ms.latency.With("method", "guest_remove_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveItem(ctx, tableToken, itemID)
+}
+
+func (ms *metricsMiddleware) Checkout(ctx context.Context, tableToken string, version uint64) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "guest_checkout").Add(1)
+		ms.latency.With("method", "guest_checkout").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Checkout(ctx, tableToken, version)
+}
+
+func (ms *metricsMiddleware) Link(ctx context.Context, token, tableID string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_table_link").Add(1)
+		ms.latency.With("method", "view_table_link").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Link(ctx, token, tableID)
+}
+
+func (ms *metricsMiddleware) RotateLink(ctx context.Context, token, tableID string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_table_link").Add(1)
+		ms.latency.With("method", "rotate_table_link").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateLink(ctx, token, tableID)
+}
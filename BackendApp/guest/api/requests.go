@@ -0,0 +1,114 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+type guestReq struct {
+	tableToken string
+}
+
+func (req guestReq) validate() error {
+	if req.tableToken == "" {
+		return guest.ErrInvalidToken
+	}
+	return nil
+}
+
+type addItemReq struct {
+	tableToken string
+	ItemID     string `json:"id"`
+	Quantity   uint64 `json:"quantity"`
+}
+
+func (req addItemReq) validate() error {
+	if req.tableToken == "" {
+		return guest.ErrInvalidToken
+	}
+	if req.ItemID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type updateItemReq struct {
+	tableToken string
+	itemID     string
+	Quantity   uint64 `json:"quantity"`
+}
+
+func (req updateItemReq) validate() error {
+	if req.tableToken == "" {
+		return guest.ErrInvalidToken
+	}
+	if req.itemID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type removeItemReq struct {
+	tableToken string
+	itemID     string
+}
+
+func (req removeItemReq) validate() error {
+	if req.tableToken == "" {
+		return guest.ErrInvalidToken
+	}
+	if req.itemID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type checkoutReq struct {
+	tableToken string
+	Version    uint64 `json:"version"`
+}
+
+func (req checkoutReq) validate() error {
+	if req.tableToken == "" {
+		return guest.ErrInvalidToken
+	}
+	if req.Version == 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type linkReq struct {
+	token   string
+	tableID string
+}
+
+func (req linkReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.tableID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type qrReq struct {
+	token   string
+	tableID string
+	format  string
+	size    int
+}
+
+func (req qrReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.tableID == "" {
+		return errors.ErrMissingID
+	}
+	if req.size < guest.MinSize || req.size > guest.MaxSize {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*tableRes)(nil)
+	_ Response = (*menuRes)(nil)
+	_ Response = (*cartRes)(nil)
+	_ Response = (*checkoutRes)(nil)
+	_ Response = (*linkRes)(nil)
+)
+
+// tableRes shows guests which table they are ordering at, without the
+// details staff use to manage it.
+type tableRes struct {
+	Vendor string  `json:"vendor"`
+	Number uint64  `json:"number"`
+	Area   string  `json:"area,omitempty"`
+	Cart   cartRes `json:"cart"`
+}
+
+func (res tableRes) Code() int {
+	return http.StatusOK
+}
+
+func (res tableRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res tableRes) Empty() bool {
+	return false
+}
+
+func toTableRes(table tables.Table, cart guest.Cart) tableRes {
+	return tableRes{
+		Vendor: table.Vendor,
+		Number: table.Number,
+		Area:   table.Area,
+		Cart:   toCartRes(cart),
+	}
+}
+
+type menuRes struct {
+	Items []menu.Item `json:"items"`
+}
+
+func (res menuRes) Code() int {
+	return http.StatusOK
+}
+
+func (res menuRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res menuRes) Empty() bool {
+	return false
+}
+
+// cartRes carries the cart version so each phone can tell whether another
+// guest changed the cart and which version it is checking out.
+type cartRes struct {
+	Items     []orders.Item `json:"items"`
+	Total     uint64        `json:"total"`
+	Version   uint64        `json:"version"`
+	UpdatedAt time.Time     `json:"updated_at,omitempty"`
+}
+
+func (res cartRes) Code() int {
+	return http.StatusOK
+}
+
+func (res cartRes) Headers() map[string]string {
+	return map[string]string{
+		"ETag": fmt.Sprintf(`"%d"`, res.Version),
+	}
+}
+
+func (res cartRes) Empty() bool {
+	return false
+}
+
+func toCartRes(cart guest.Cart) cartRes {
+	items := cart.Items
+	if items == nil {
+		items = []orders.Item{}
+	}
+	return cartRes{
+		Items:     items,
+		Total:     cart.Total(),
+		Version:   cart.Version,
+		UpdatedAt: cart.UpdatedAt,
+	}
+}
+
+type checkoutRes struct {
+	OrderID string `json:"order_id"`
+}
+
+func (res checkoutRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res checkoutRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orders/%s", res.OrderID),
+	}
+}
+
+func (res checkoutRes) Empty() bool {
+	return false
+}
+
+type linkRes struct {
+	URL string `json:"url"`
+}
+
+func (res linkRes) Code() int {
+	return http.StatusOK
+}
+
+func (res linkRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res linkRes) Empty() bool {
+	return false
+}
+
+// qrRes is a QR code image, it is written as is rather than as JSON.
+type qrRes struct {
+	contentType string
+	image       []byte
+}
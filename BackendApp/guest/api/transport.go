@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType    = "application/json"
+	pngContentType = "image/png"
+	svgContentType = "image/svg+xml"
+	svgFormat      = "svg"
+	sizeKey        = "size"
+)
+
+// MakeGuestHandler returns a HTTP handler for guest ordering API endpoints.
+// Guest endpoints are authorized by the table token in the path, the QR code
+// endpoints by a bearer token.
+func MakeGuestHandler(svc guest.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("GET").Path("/guest/{token}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_view_table")(viewTableEndpoint(svc)),
+		decodeGuest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/guest/{token}/menu").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_view_menu")(viewMenuEndpoint(svc)),
+		decodeGuest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/guest/{token}/cart").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_view_cart")(viewCartEndpoint(svc)),
+		decodeGuest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/guest/{token}/cart/items").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_add_item")(addItemEndpoint(svc)),
+		decodeAddItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/guest/{token}/cart/items/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_update_item")(updateItemEndpoint(svc)),
+		decodeUpdateItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/guest/{token}/cart/items/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_remove_item")(removeItemEndpoint(svc)),
+		decodeRemoveItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/guest/{token}/cart/checkout").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint guest_checkout")(checkoutEndpoint(svc)),
+		decodeCheckout,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tables/{id}/qr").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_table_link")(viewLinkEndpoint(svc)),
+		decodeLink,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/tables/{id}/qr/rotate").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint rotate_table_link")(rotateLinkEndpoint(svc)),
+		decodeLink,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tables/{id}/qr.{format:png|svg}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint table_qr_code")(qrEndpoint(svc)),
+		decodeQR,
+		encodeQR,
+		opts...,
+	))
+}
+
+func decodeGuest(_ context.Context, r *http.Request) (interface{}, error) {
+	return guestReq{tableToken: mux.Vars(r)["token"]}, nil
+}
+
+// decodeAddItem accepts the quantity as optional, one unit is added if it
+// is missing.
+func decodeAddItem(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := addItemReq{tableToken: mux.Vars(r)["token"]}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeUpdateItem(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := updateItemReq{
+		tableToken: mux.Vars(r)["token"],
+		itemID:     mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeRemoveItem(_ context.Context, r *http.Request) (interface{}, error) {
+	req := removeItemReq{
+		tableToken: mux.Vars(r)["token"],
+		itemID:     mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeCheckout(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := checkoutReq{tableToken: mux.Vars(r)["token"]}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeLink(_ context.Context, r *http.Request) (interface{}, error) {
+	req := linkReq{
+		token:   decodeToken(r),
+		tableID: mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeQR(_ context.Context, r *http.Request) (interface{}, error) {
+	req := qrReq{
+		token:   decodeToken(r),
+		tableID: mux.Vars(r)["id"],
+		format:  mux.Vars(r)["format"],
+		size:    guest.DefaultSize,
+	}
+	if r.URL.Query().Has(sizeKey) {
+		size, err := strconv.Atoi(r.URL.Query().Get(sizeKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		req.size = size
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeQR(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(qrRes)
+	w.Header().Set("Content-Type", res.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(res.image)))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(res.image)
+	return err
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, guest.ErrEmptyCart):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, guest.ErrInvalidToken):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, guest.ErrStaleCart):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, guest.ErrUnavailable):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package guest lets guests order from their phones by scanning the QR code
+// on their table, without an account. Everyone at a table shares one cart.
+package guest
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+// ChannelName is recorded in the metadata of orders placed from a table QR code.
+const ChannelName = "qr"
+
+var (
+	// ErrInvalidToken indicates a table token that is forged or was revoked
+	// by rotating the table's QR code.
+	ErrInvalidToken = errors.New("invalid or revoked table token")
+
+	// ErrStaleCart indicates a checkout of a cart that another guest changed
+	// after it was last viewed.
+	ErrStaleCart = errors.New("cart changed since it was last viewed")
+
+	// ErrEmptyCart indicates a checkout of a cart without items.
+	ErrEmptyCart = errors.New("cart is empty")
+
+	// ErrUnavailable indicates a menu item that cannot be ordered at the table.
+	ErrUnavailable = errors.New("menu item is not available")
+)
+
+// Cart is the shared cart of a table. Every change bumps the version so
+// phones can tell when someone else at the table changed it.
+type Cart struct {
+	TableID   string        `json:"table_id"`
+	Vendor    string        `json:"vendor"`
+	Items     []orders.Item `json:"items"`
+	Version   uint64        `json:"version"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Total returns the price of everything in the cart.
+func (cart Cart) Total() uint64 {
+	return orders.Order{Items: cart.Items}.ItemsTotal()
+}
+
+// Service specifies the guest ordering API. Guest methods are authorized
+// by the table token carried in the QR code, the rest by a staff token.
+type Service interface {
+	// Table returns the table the table token belongs to.
+	Table(ctx context.Context, tableToken string) (tables.Table, error)
+
+	// Menu returns the items that can be ordered at the table.
+	Menu(ctx context.Context, tableToken string) ([]menu.Item, error)
+
+	// ViewCart returns the table's cart.
+	ViewCart(ctx context.Context, tableToken string) (Cart, error)
+
+	// AddItem adds quantity units of a menu item to the table's cart.
+	AddItem(ctx context.Context, tableToken, itemID string, quantity uint64) (Cart, error)
+
+	// UpdateItem sets the quantity of a menu item in the table's cart. A
+	// zero quantity removes the item. The last update wins.
+	UpdateItem(ctx context.Context, tableToken, itemID string, quantity uint64) (Cart, error)
+
+	// RemoveItem removes a menu item from the table's cart.
+	RemoveItem(ctx context.Context, tableToken, itemID string) (Cart, error)
+
+	// Checkout places the cart as an order round on the table's session
+	// and empties it. Version is the cart version the guest reviewed, if
+	// the cart has changed since ErrStaleCart is returned.
+	Checkout(ctx context.Context, tableToken string, version uint64) (string, error)
+
+	// Link returns the URL encoded in the QR code of a table.
+	Link(ctx context.Context, token, tableID string) (string, error)
+
+	// RotateLink rotates the QR code of a table, revoking the old link, and
+	// returns the new one.
+	RotateLink(ctx context.Context, token, tableID string) (string, error)
+}
+
+// CartRepository specifies a cart persistence API. Saves are conditional on
+// the version so concurrent changes from different phones are not lost.
+type CartRepository interface {
+	// Retrieve retrieves the cart of a table. If the table has no cart
+	// errors.ErrNotFound is returned.
+	Retrieve(ctx context.Context, tableID string) (Cart, error)
+
+	// Save persists the cart if the stored cart is one version behind it,
+	// or absent for the first version. Otherwise errors.ErrConflict is
+	// returned.
+	Save(ctx context.Context, cart Cart) error
+
+	// Remove removes the cart of a table if it is still at the version.
+	// Otherwise errors.ErrConflict is returned.
+	Remove(ctx context.Context, tableID string, version uint64) error
+
+	// Restore puts a removed cart back at its version, unless guests at
+	// the table started another cart since. Then errors.ErrConflict is
+	// returned.
+	Restore(ctx context.Context, cart Cart) error
+}
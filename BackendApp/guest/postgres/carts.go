@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ guest.CartRepository = (*cartsRepo)(nil)
+
+type cartsRepo struct {
+	db  *sqlx.DB
+	ttl time.Duration
+}
+
+// NewCartRepo instantiates a PostgreSQL implementation of cart repository,
+// so phones served by different replicas share the cart of their table and
+// carts outlive restarts. Carts that have not been updated within ttl are
+// treated as abandoned.
+func NewCartRepo(db *sqlx.DB, ttl time.Duration) guest.CartRepository {
+	return &cartsRepo{
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+func (repo cartsRepo) Retrieve(ctx context.Context, tableID string) (guest.Cart, error) {
+	q := `SELECT table_id, vendor, items, version, updated_at FROM guest_carts WHERE table_id = $1 AND updated_at >= $2`
+
+	dbc := dbCart{}
+	if err := repo.db.QueryRowxContext(ctx, q, tableID, repo.abandoned()).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return guest.Cart{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return guest.Cart{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toCart(dbc)
+}
+
+func (repo cartsRepo) Save(ctx context.Context, cart guest.Cart) error {
+	// The first version replaces an abandoned cart, the next ones the
+	// version before them.
+	q := `UPDATE guest_carts SET vendor = :vendor, items = :items, version = :version, updated_at = :updated_at
+		  WHERE table_id = :table_id AND version = :version - 1 AND updated_at >= :abandoned`
+	if cart.Version == 1 {
+		q = `INSERT INTO guest_carts (table_id, vendor, items, version, updated_at)
+			 VALUES (:table_id, :vendor, :items, :version, :updated_at)
+			 ON CONFLICT (table_id) DO UPDATE SET vendor = EXCLUDED.vendor, items = EXCLUDED.items,
+			 version = EXCLUDED.version, updated_at = EXCLUDED.updated_at
+			 WHERE guest_carts.updated_at < :abandoned`
+	}
+
+	dbc, err := toDBCart(cart)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	dbc.Abandoned = repo.abandoned()
+	res, err := repo.db.NamedExecContext(ctx, q, dbc)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.ErrConflict
+	}
+	if cart.Version == 1 {
+		return repo.expire(ctx)
+	}
+	return nil
+}
+
+func (repo cartsRepo) Remove(ctx context.Context, tableID string, version uint64) error {
+	q := `DELETE FROM guest_carts WHERE table_id = $1 AND version = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, tableID, version)
+	if err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.ErrConflict
+	}
+	return nil
+}
+
+func (repo cartsRepo) Restore(ctx context.Context, cart guest.Cart) error {
+	// Only an abandoned cart started after the removal is replaced.
+	q := `INSERT INTO guest_carts (table_id, vendor, items, version, updated_at)
+		  VALUES (:table_id, :vendor, :items, :version, :updated_at)
+		  ON CONFLICT (table_id) DO UPDATE SET vendor = EXCLUDED.vendor, items = EXCLUDED.items,
+		  version = EXCLUDED.version, updated_at = EXCLUDED.updated_at
+		  WHERE guest_carts.updated_at < :abandoned`
+
+	dbc, err := toDBCart(cart)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	dbc.Abandoned = repo.abandoned()
+	res, err := repo.db.NamedExecContext(ctx, q, dbc)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.ErrConflict
+	}
+	return nil
+}
+
+// expire drops abandoned carts when a new one is started, so the table does
+// not grow without bound.
+func (repo cartsRepo) expire(ctx context.Context) error {
+	if repo.ttl == 0 {
+		return nil
+	}
+	q := `DELETE FROM guest_carts WHERE updated_at < $1`
+
+	if _, err := repo.db.ExecContext(ctx, q, repo.abandoned()); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+// abandoned returns when carts last updated before were abandoned, carts
+// are never abandoned without a ttl.
+func (repo cartsRepo) abandoned() time.Time {
+	if repo.ttl == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-repo.ttl).UTC()
+}
+
+type dbCart struct {
+	TableID   string    `db:"table_id"`
+	Vendor    string    `db:"vendor"`
+	Items     []byte    `db:"items"`
+	Version   uint64    `db:"version"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Abandoned time.Time `db:"abandoned"`
+}
+
+func toDBCart(cart guest.Cart) (dbCart, error) {
+	items := []byte("[]")
+	if len(cart.Items) > 0 {
+		b, err := json.Marshal(cart.Items)
+		if err != nil {
+			return dbCart{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		items = b
+	}
+	return dbCart{
+		TableID:   cart.TableID,
+		Vendor:    cart.Vendor,
+		Items:     items,
+		Version:   cart.Version,
+		UpdatedAt: cart.UpdatedAt.UTC(),
+	}, nil
+}
+
+func toCart(dbc dbCart) (guest.Cart, error) {
+	items := []orders.Item{}
+	if err := json.Unmarshal(dbc.Items, &items); err != nil {
+		return guest.Cart{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return guest.Cart{
+		TableID:   dbc.TableID,
+		Vendor:    dbc.Vendor,
+		Items:     items,
+		Version:   dbc.Version,
+		UpdatedAt: dbc.UpdatedAt,
+	}, nil
+}
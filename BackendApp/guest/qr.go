@@ -0,0 +1,50 @@
+package guest
+
+import (
+	"bytes"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Sizes in pixels of the PNG QR images.
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// PNG renders the content as a QR code PNG size pixels wide.
+func PNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// SVG renders the content as a scalable QR code, one unit per module, so it
+// prints sharply at any size.
+func SVG(content string) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := qr.Bitmap()
+	n := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, n, n)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, n, n)
+	for y, row := range bitmap {
+		// Runs of dark modules are drawn as a single rectangle.
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}
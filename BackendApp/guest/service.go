@@ -0,0 +1,307 @@
+package guest
+
+import (
+	"context"
+	"crypto/hmac"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+const (
+	menuLimit   = 100
+	maxQuantity = 99
+	maxRetries  = 5
+)
+
+// Config defines the options the guest service uses.
+type Config struct {
+	Secret string // The secret table tokens are signed with.
+	URL    string // The base URL guests open, the table token is appended to it.
+	Token  string // The token the service authenticates to the table, order and menu services with.
+}
+
+var _ Service = (*guestService)(nil)
+
+type guestService struct {
+	config Config
+	tables tables.Service
+	menu   menu.Service
+	carts  CartRepository
+}
+
+// NewService instantiates the guest service implementation.
+func NewService(config Config, tablesSvc tables.Service, menuSvc menu.Service, carts CartRepository) Service {
+	return &guestService{
+		config: config,
+		tables: tablesSvc,
+		menu:   menuSvc,
+		carts:  carts,
+	}
+}
+
+func (svc guestService) Table(ctx context.Context, tableToken string) (tables.Table, error) {
+	id, code, ok := parse(svc.config.Secret, tableToken)
+	if !ok {
+		return tables.Table{}, ErrInvalidToken
+	}
+	table, err := svc.tables.ViewTable(ctx, svc.config.Token, id)
+	if err != nil {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return tables.Table{}, ErrInvalidToken
+		}
+		return tables.Table{}, err
+	}
+	if !hmac.Equal([]byte(code), []byte(table.QRCode)) {
+		return tables.Table{}, ErrInvalidToken
+	}
+	return table, nil
+}
+
+func (svc guestService) Menu(ctx context.Context, tableToken string) ([]menu.Item, error) {
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return nil, err
+	}
+	page, err := svc.menu.ListItems(ctx, svc.config.Token, menu.PageMetadata{
+		Limit:         menuLimit,
+		Vendor:        table.Vendor,
+		OnlyAvailable: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (svc guestService) ViewCart(ctx context.Context, tableToken string) (Cart, error) {
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return Cart{}, err
+	}
+	return svc.cart(ctx, table)
+}
+
+func (svc guestService) AddItem(ctx context.Context, tableToken, itemID string, quantity uint64) (Cart, error) {
+	if quantity == 0 {
+		quantity = 1
+	}
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return Cart{}, err
+	}
+	item, err := svc.item(ctx, table, itemID)
+	if err != nil {
+		return Cart{}, err
+	}
+	return svc.change(ctx, table, func(cart *Cart) error {
+		for i := range cart.Items {
+			if cart.Items[i].ID == item.ID {
+				if cart.Items[i].Quantity+quantity > maxQuantity {
+					return errors.ErrMalformedEntity
+				}
+				cart.Items[i].Quantity += quantity
+				cart.Items[i].Price = item.Price
+				return nil
+			}
+		}
+		if quantity > maxQuantity {
+			return errors.ErrMalformedEntity
+		}
+		cart.Items = append(cart.Items, orders.Item{
+			ID:       item.ID,
+			Name:     item.Name,
+			Quantity: quantity,
+			Price:    item.Price,
+		})
+		return nil
+	})
+}
+
+func (svc guestService) UpdateItem(ctx context.Context, tableToken, itemID string, quantity uint64) (Cart, error) {
+	if quantity == 0 {
+		return svc.RemoveItem(ctx, tableToken, itemID)
+	}
+	if quantity > maxQuantity {
+		return Cart{}, errors.ErrMalformedEntity
+	}
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return Cart{}, err
+	}
+	return svc.change(ctx, table, func(cart *Cart) error {
+		for i := range cart.Items {
+			if cart.Items[i].ID == itemID {
+				cart.Items[i].Quantity = quantity
+				return nil
+			}
+		}
+		return errors.ErrNotFound
+	})
+}
+
+func (svc guestService) RemoveItem(ctx context.Context, tableToken, itemID string) (Cart, error) {
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return Cart{}, err
+	}
+	return svc.change(ctx, table, func(cart *Cart) error {
+		for i := range cart.Items {
+			if cart.Items[i].ID == itemID {
+				cart.Items = append(cart.Items[:i], cart.Items[i+1:]...)
+				return nil
+			}
+		}
+		return errors.ErrNotFound
+	})
+}
+
+func (svc guestService) Checkout(ctx context.Context, tableToken string, version uint64) (string, error) {
+	table, err := svc.Table(ctx, tableToken)
+	if err != nil {
+		return "", err
+	}
+	cart, err := svc.cart(ctx, table)
+	if err != nil {
+		return "", err
+	}
+	if cart.Version != version {
+		return "", ErrStaleCart
+	}
+	if len(cart.Items) == 0 {
+		return "", ErrEmptyCart
+	}
+	// Claim the cart first so two phones checking out together place it
+	// only once.
+	if err := svc.carts.Remove(ctx, table.ID, cart.Version); err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return "", ErrStaleCart
+		}
+		return "", err
+	}
+	id, err := svc.place(ctx, table, cart)
+	if err != nil {
+		// Give the cart back at the version the guests reviewed so they
+		// can retry without viewing it again.
+		cart.UpdatedAt = time.Now()
+		if serr := svc.carts.Restore(ctx, cart); serr != nil {
+			return "", errors.Wrap(err, serr)
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+func (svc guestService) Link(ctx context.Context, token, tableID string) (string, error) {
+	table, err := svc.tables.ViewTable(ctx, token, tableID)
+	if err != nil {
+		return "", err
+	}
+	return svc.link(table), nil
+}
+
+func (svc guestService) RotateLink(ctx context.Context, token, tableID string) (string, error) {
+	table, err := svc.tables.RotateQRCode(ctx, token, tableID)
+	if err != nil {
+		return "", err
+	}
+	// The next party at the table starts with an empty cart.
+	cart, err := svc.carts.Retrieve(ctx, table.ID)
+	switch {
+	case err == nil:
+		if err := svc.carts.Remove(ctx, table.ID, cart.Version); err != nil && !errors.Contains(err, errors.ErrConflict) {
+			return "", err
+		}
+	case !errors.Contains(err, errors.ErrNotFound):
+		return "", err
+	}
+	return svc.link(table), nil
+}
+
+func (svc guestService) link(table tables.Table) string {
+	return strings.TrimSuffix(svc.config.URL, "/") + "/" + Sign(svc.config.Secret, table)
+}
+
+// cart returns the cart of the table, an empty one if the guests have not
+// added anything yet.
+func (svc guestService) cart(ctx context.Context, table tables.Table) (Cart, error) {
+	cart, err := svc.carts.Retrieve(ctx, table.ID)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return Cart{TableID: table.ID, Vendor: table.Vendor, Items: []orders.Item{}}, nil
+	}
+	return cart, err
+}
+
+// change applies fn to the latest version of the cart and saves it. If a
+// guest at the same table saved the cart in between, fn is applied again to
+// their version so neither change is lost. Updates of the same item are
+// therefore decided by whoever saves last.
+func (svc guestService) change(ctx context.Context, table tables.Table, fn func(cart *Cart) error) (Cart, error) {
+	for i := 0; i < maxRetries; i++ {
+		cart, err := svc.cart(ctx, table)
+		if err != nil {
+			return Cart{}, err
+		}
+		cart.Items = append([]orders.Item{}, cart.Items...)
+		if err := fn(&cart); err != nil {
+			return Cart{}, err
+		}
+		cart.Version++
+		cart.UpdatedAt = time.Now()
+		err = svc.carts.Save(ctx, cart)
+		if errors.Contains(err, errors.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return Cart{}, err
+		}
+		return cart, nil
+	}
+	return Cart{}, errors.ErrConflict
+}
+
+func (svc guestService) item(ctx context.Context, table tables.Table, id string) (menu.Item, error) {
+	item, err := svc.menu.ViewItem(ctx, svc.config.Token, id)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return menu.Item{}, ErrUnavailable
+	}
+	if err != nil {
+		return menu.Item{}, err
+	}
+	if item.Vendor != table.Vendor || !item.Available {
+		return menu.Item{}, ErrUnavailable
+	}
+	return item, nil
+}
+
+// place adds the cart as an order round to the table's session, seating the
+// table first if nobody has opened one.
+func (svc guestService) place(ctx context.Context, table tables.Table, cart Cart) (string, error) {
+	session, err := svc.tables.ActiveSession(ctx, svc.config.Token, table.ID)
+	switch {
+	case errors.Contains(err, errors.ErrNotFound):
+		id, err := svc.tables.OpenSession(ctx, svc.config.Token, table.ID, 0)
+		switch {
+		case errors.Contains(err, tables.ErrTableOccupied):
+			// Staff seated the table in the meantime.
+			if session, err = svc.tables.ActiveSession(ctx, svc.config.Token, table.ID); err != nil {
+				return "", err
+			}
+		case err != nil:
+			return "", err
+		default:
+			session.ID = id
+		}
+	case err != nil:
+		return "", err
+	}
+	order := orders.Order{
+		Items:    cart.Items,
+		Metadata: orders.Metadata{orders.ChannelKey: ChannelName},
+	}
+	return svc.tables.AddOrder(ctx, svc.config.Token, session.ID, order)
+}
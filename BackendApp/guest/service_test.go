@@ -0,0 +1,248 @@
+package guest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/guest"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+const secret = "secret"
+
+var (
+	table   = tables.Table{ID: "table", Vendor: "vendor", Number: 1, QRCode: "code"}
+	chapati = menu.Item{ID: "chapati", Vendor: "vendor", Name: "Chapati", Price: 30, Available: true}
+	chai    = menu.Item{ID: "chai", Vendor: "vendor", Name: "Chai", Price: 50, Available: true}
+)
+
+// tablesService seats the table on the first round and records the rounds
+// placed, failing them with err when it is set.
+type tablesService struct {
+	tables.Service
+	session tables.Session
+	rounds  []orders.Order
+	err     error
+}
+
+func (svc *tablesService) ViewTable(_ context.Context, _, id string) (tables.Table, error) {
+	if id != table.ID {
+		return tables.Table{}, errors.ErrNotFound
+	}
+	return table, nil
+}
+
+func (svc *tablesService) ActiveSession(context.Context, string, string) (tables.Session, error) {
+	if svc.session.ID == "" {
+		return tables.Session{}, errors.ErrNotFound
+	}
+	return svc.session, nil
+}
+
+func (svc *tablesService) OpenSession(_ context.Context, _, tableID string, _ uint64) (string, error) {
+	svc.session = tables.Session{ID: "session", TableID: tableID, State: tables.StateOpen}
+	return svc.session.ID, nil
+}
+
+func (svc *tablesService) AddOrder(_ context.Context, _, _ string, order orders.Order) (string, error) {
+	if svc.err != nil {
+		return "", svc.err
+	}
+	svc.rounds = append(svc.rounds, order)
+	return "order", nil
+}
+
+type menuService struct {
+	menu.Service
+}
+
+func (menuService) ViewItem(_ context.Context, _, id string) (menu.Item, error) {
+	for _, item := range []menu.Item{chapati, chai} {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return menu.Item{}, errors.ErrNotFound
+}
+
+// cartRepo keeps carts in memory with the version checks of the postgres
+// repository. Before, if set, runs once ahead of the next save as if a
+// guest on another phone had got there first.
+type cartRepo struct {
+	mu     sync.Mutex
+	carts  map[string]guest.Cart
+	before func()
+}
+
+func newCartRepo() *cartRepo {
+	return &cartRepo{carts: map[string]guest.Cart{}}
+}
+
+func (repo *cartRepo) Retrieve(_ context.Context, tableID string) (guest.Cart, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	cart, ok := repo.carts[tableID]
+	if !ok {
+		return guest.Cart{}, errors.ErrNotFound
+	}
+	return cart, nil
+}
+
+func (repo *cartRepo) Save(_ context.Context, cart guest.Cart) error {
+	if before := repo.before; before != nil {
+		repo.before = nil
+		before()
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	stored, ok := repo.carts[cart.TableID]
+	switch {
+	case cart.Version == 1 && ok:
+		return errors.ErrConflict
+	case cart.Version > 1 && (!ok || stored.Version != cart.Version-1):
+		return errors.ErrConflict
+	}
+	repo.carts[cart.TableID] = cart
+	return nil
+}
+
+func (repo *cartRepo) Remove(_ context.Context, tableID string, version uint64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if cart, ok := repo.carts[tableID]; !ok || cart.Version != version {
+		return errors.ErrConflict
+	}
+	delete(repo.carts, tableID)
+	return nil
+}
+
+func (repo *cartRepo) Restore(_ context.Context, cart guest.Cart) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, ok := repo.carts[cart.TableID]; ok {
+		return errors.ErrConflict
+	}
+	repo.carts[cart.TableID] = cart
+	return nil
+}
+
+func newService(tablesSvc *tablesService, carts *cartRepo) guest.Service {
+	return guest.NewService(guest.Config{Secret: secret, Token: "token"}, tablesSvc, menuService{}, carts)
+}
+
+func TestChangeCart(t *testing.T) {
+	carts := newCartRepo()
+	svc := newService(&tablesService{}, carts)
+	token := guest.Sign(secret, table)
+	ctx := context.Background()
+
+	cart, err := svc.AddItem(ctx, token, chapati.ID, 2)
+	if err != nil {
+		t.Fatalf("adding an item: unexpected error: %s", err)
+	}
+	if cart.Version != 1 || len(cart.Items) != 1 || cart.Total() != 60 {
+		t.Fatalf("adding an item: got version %d with %d items for %d, want version 1 with 1 item for 60", cart.Version, len(cart.Items), cart.Total())
+	}
+
+	// Another phone adds chai between this phone reading the cart and
+	// saving it, the change is applied again on top of theirs.
+	carts.before = func() {
+		if _, err := svc.AddItem(ctx, token, chai.ID, 1); err != nil {
+			t.Errorf("adding an item from another phone: unexpected error: %s", err)
+		}
+	}
+	cart, err = svc.UpdateItem(ctx, token, chapati.ID, 3)
+	if err != nil {
+		t.Fatalf("updating an item: unexpected error: %s", err)
+	}
+	if cart.Version != 3 || len(cart.Items) != 2 || cart.Total() != 140 {
+		t.Errorf("updating an item: got version %d with %d items for %d, want version 3 with 2 items for 140", cart.Version, len(cart.Items), cart.Total())
+	}
+
+	cases := []struct {
+		desc string
+		fn   func() (guest.Cart, error)
+		err  error
+	}{
+		{desc: "add an unknown item", fn: func() (guest.Cart, error) { return svc.AddItem(ctx, token, "mandazi", 1) }, err: guest.ErrUnavailable},
+		{desc: "add too many", fn: func() (guest.Cart, error) { return svc.AddItem(ctx, token, chapati.ID, 97) }, err: errors.ErrMalformedEntity},
+		{desc: "update an item not in the cart", fn: func() (guest.Cart, error) { return svc.UpdateItem(ctx, token, "mandazi", 1) }, err: errors.ErrNotFound},
+		{desc: "remove an item not in the cart", fn: func() (guest.Cart, error) { return svc.RemoveItem(ctx, token, "mandazi") }, err: errors.ErrNotFound},
+		{desc: "change with a forged token", fn: func() (guest.Cart, error) { return svc.AddItem(ctx, token+"x", chai.ID, 1) }, err: guest.ErrInvalidToken},
+	}
+	for _, tc := range cases {
+		if _, err := tc.fn(); !errors.Contains(err, tc.err) {
+			t.Errorf("%s: got error %v, want %s", tc.desc, err, tc.err)
+		}
+	}
+	if cart, err := svc.ViewCart(ctx, token); err != nil || cart.Version != 3 {
+		t.Errorf("viewing the cart after failed changes: got version %d and error %v, want version 3", cart.Version, err)
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	tablesSvc := &tablesService{}
+	carts := newCartRepo()
+	svc := newService(tablesSvc, carts)
+	token := guest.Sign(secret, table)
+	ctx := context.Background()
+
+	if _, err := svc.Checkout(ctx, token, 0); !errors.Contains(err, guest.ErrEmptyCart) {
+		t.Errorf("checking out an empty cart: got error %v, want %s", err, guest.ErrEmptyCart)
+	}
+	if _, err := svc.AddItem(ctx, token, chapati.ID, 2); err != nil {
+		t.Fatalf("adding an item: unexpected error: %s", err)
+	}
+	reviewed, err := svc.AddItem(ctx, token, chai.ID, 1)
+	if err != nil {
+		t.Fatalf("adding an item: unexpected error: %s", err)
+	}
+
+	if _, err := svc.Checkout(ctx, token, reviewed.Version-1); !errors.Contains(err, guest.ErrStaleCart) {
+		t.Errorf("checking out a stale cart: got error %v, want %s", err, guest.ErrStaleCart)
+	}
+
+	// A failed round gives the cart back at the version the guests
+	// reviewed, so checking it out again succeeds.
+	tablesSvc.err = errors.ErrCreateEntity
+	if _, err := svc.Checkout(ctx, token, reviewed.Version); !errors.Contains(err, errors.ErrCreateEntity) {
+		t.Errorf("checking out with the round failing: got error %v, want %s", err, errors.ErrCreateEntity)
+	}
+	cart, err := svc.ViewCart(ctx, token)
+	if err != nil {
+		t.Fatalf("viewing the cart after a failed checkout: unexpected error: %s", err)
+	}
+	if cart.Version != reviewed.Version || cart.Total() != reviewed.Total() {
+		t.Errorf("viewing the cart after a failed checkout: got version %d for %d, want version %d for %d", cart.Version, cart.Total(), reviewed.Version, reviewed.Total())
+	}
+
+	tablesSvc.err = nil
+	if _, err := svc.Checkout(ctx, token, reviewed.Version); err != nil {
+		t.Fatalf("checking out: unexpected error: %s", err)
+	}
+	if len(tablesSvc.rounds) != 1 {
+		t.Fatalf("checking out: got %d rounds, want 1", len(tablesSvc.rounds))
+	}
+	round := tablesSvc.rounds[0]
+	if round.ItemsTotal() != reviewed.Total() || round.Metadata[orders.ChannelKey] != guest.ChannelName {
+		t.Errorf("checking out: got a round of %d from channel %v, want %d from %s", round.ItemsTotal(), round.Metadata[orders.ChannelKey], reviewed.Total(), guest.ChannelName)
+	}
+	if tablesSvc.session.ID == "" {
+		t.Errorf("checking out: the table was not seated")
+	}
+
+	// A second phone checking out the same cart places nothing.
+	if _, err := svc.Checkout(ctx, token, reviewed.Version); !errors.Contains(err, guest.ErrStaleCart) {
+		t.Errorf("checking out twice: got error %v, want %s", err, guest.ErrStaleCart)
+	}
+	if len(tablesSvc.rounds) != 1 {
+		t.Errorf("checking out twice: got %d rounds, want 1", len(tablesSvc.rounds))
+	}
+	if cart, err := svc.ViewCart(ctx, token); err != nil || len(cart.Items) != 0 || cart.Version != 0 {
+		t.Errorf("viewing the cart after checking out: got %d items at version %d and error %v, want an empty cart", len(cart.Items), cart.Version, err)
+	}
+}
@@ -0,0 +1,52 @@
+package guest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+// The table token is the table ID and its current QR code, signed with the
+// service secret. Rotating the QR code of a table revokes its old tokens.
+const (
+	tokenSeparator = "."
+	signatureSize  = 16
+)
+
+var encoding = base64.RawURLEncoding
+
+// Sign returns the table token for the table.
+func Sign(secret string, table tables.Table) string {
+	payload := table.ID + tokenSeparator + table.QRCode
+	return encoding.EncodeToString([]byte(payload)) + tokenSeparator + signature(secret, payload)
+}
+
+// parse returns the table ID and QR code carried by a correctly signed
+// token. It does not check whether the QR code is still current.
+func parse(secret, token string) (string, string, bool) {
+	enc, sig, ok := strings.Cut(token, tokenSeparator)
+	if !ok || secret == "" {
+		return "", "", false
+	}
+	payload, err := encoding.DecodeString(enc)
+	if err != nil {
+		return "", "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, string(payload)))) {
+		return "", "", false
+	}
+	id, code, ok := strings.Cut(string(payload), tokenSeparator)
+	if !ok || id == "" || code == "" {
+		return "", "", false
+	}
+	return id, code, true
+}
+
+func signature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return encoding.EncodeToString(mac.Sum(nil)[:signatureSize])
+}
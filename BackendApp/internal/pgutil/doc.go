@@ -0,0 +1,3 @@
+// Package pgutil contains the transaction and lock helpers shared by the
+// PostgreSQL repositories, which run on both PostgreSQL and CockroachDB.
+package pgutil
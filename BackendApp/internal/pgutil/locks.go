@@ -0,0 +1,45 @@
+package pgutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxKey is the size of the key column of the locks table.
+const maxKey = 254
+
+// Lock takes the locks of the keys until the transaction ends. A lock is a
+// row of the locks table, written the first time it is taken and locked
+// for update, which both PostgreSQL and CockroachDB do. Keys are locked in
+// order, so transactions taking several never wait on each other in a
+// circle. Keys longer than the column are locked by their hash.
+func Lock(ctx context.Context, tx *sqlx.Tx, keys ...string) error {
+	iq := `INSERT INTO locks (key) VALUES ($1) ON CONFLICT (key) DO NOTHING`
+	q := `SELECT key FROM locks WHERE key = $1 FOR UPDATE`
+
+	sorted := make([]string, len(keys))
+	for i, key := range keys {
+		if len(key) > maxKey {
+			sum := sha256.Sum256([]byte(key))
+			key = hex.EncodeToString(sum[:])
+		}
+		sorted[i] = key
+	}
+	sort.Strings(sorted)
+	for i, key := range sorted {
+		if i > 0 && key == sorted[i-1] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, iq, key); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, q, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package pgutil
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/multierr"
+)
+
+// errSerialize is the code of serialization failures:
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const errSerialize = "40001"
+
+const (
+	// txAttempts is how many times a transaction is run at most.
+	txAttempts = 5
+	// txBackoff is how long the first retry of a transaction waits, the
+	// next ones wait longer each.
+	txBackoff = 20 * time.Millisecond
+)
+
+// txKey keys the transaction a context runs in.
+type txKey struct{}
+
+// WithTx returns a copy of ctx running in the transaction tx. Transactions
+// run with it join tx, and DB queries in it.
+func WithTx(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// DB returns the transaction ctx runs in, or db when it runs in none.
+func DB(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTx runs fn in a transaction and commits it, running it all again when
+// the database aborts the transaction with a serialization failure.
+// CockroachDB runs every transaction serializably and leaves retrying the
+// ones it aborts to the client, PostgreSQL seldom aborts any at its default
+// isolation. Errors starting or committing the transaction are wrapped with
+// wrapper, the errors of fn are returned as they are.
+//
+// When ctx runs in a transaction already, fn runs in a savepoint of it:
+// what fn wrote is undone if it fails, and committed with the outer
+// transaction otherwise. The outer transaction is the one run again.
+func InTx(ctx context.Context, db *sqlx.DB, wrapper error, fn func(tx *sqlx.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return savepoint(ctx, tx, wrapper, fn)
+	}
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, wrapper, fn)
+		if err == nil || attempt == txAttempts || !Retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txBackoff):
+		}
+	}
+}
+
+func runTx(ctx context.Context, db *sqlx.DB, wrapper error, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(wrapper, err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(wrapper, err)
+	}
+	return nil
+}
+
+func savepoint(ctx context.Context, tx *sqlx.Tx, wrapper error, fn func(tx *sqlx.Tx) error) error {
+	// Savepoints of the same name nest, the innermost one is released or
+	// rolled back to.
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT pgutil_tx`); err != nil {
+		return errors.Wrap(wrapper, err)
+	}
+	if err := fn(tx); err != nil {
+		// Rolling back keeps the savepoint, it is released after.
+		if _, rerr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT pgutil_tx`); rerr == nil {
+			tx.ExecContext(ctx, `RELEASE SAVEPOINT pgutil_tx`)
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT pgutil_tx`); err != nil {
+		return errors.Wrap(wrapper, err)
+	}
+	return nil
+}
+
+// Retryable tells whether err aborted a transaction that succeeds when run
+// again, however it was combined with or wrapped in other errors. Wrapping
+// keeps only the message of the driver error, which ends with its code.
+func Retryable(err error) bool {
+	for _, e := range multierr.Errors(err) {
+		if pgErr, ok := e.(*pgconn.PgError); ok && pgErr.Code == errSerialize {
+			return true
+		}
+		if strings.Contains(e.Error(), "(SQLSTATE "+errSerialize+")") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package pgutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/jackc/pgconn"
+	"go.uber.org/multierr"
+)
+
+func TestRetryable(t *testing.T) {
+	serialize := &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "restart transaction"}
+	duplicate := &pgconn.PgError{Severity: "ERROR", Code: "23505", Message: "duplicate key"}
+
+	cases := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{desc: "no error", err: nil, want: false},
+		{desc: "serialization failure", err: serialize, want: true},
+		{desc: "combined serialization failure", err: multierr.Combine(errors.ErrUpdateEntity, serialize), want: true},
+		{desc: "wrapped serialization failure", err: errors.Wrap(errors.ErrCreateEntity, serialize), want: true},
+		{desc: "other driver error", err: errors.Wrap(errors.ErrCreateEntity, duplicate), want: false},
+		{desc: "other error", err: fmt.Errorf("connection refused"), want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := pgutil.Retryable(tc.err); got != tc.want {
+				t.Errorf("Retryable(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
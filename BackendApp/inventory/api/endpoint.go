@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func createIngredientEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createIngredientReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateIngredient(ctx, req.token, req.ing)
+		if err != nil {
+			return nil, err
+		}
+		return createIngredientRes{ID: id}, nil
+	}
+}
+
+func viewIngredientEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		ing, err := svc.ViewIngredient(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewIngredientRes{Ingredient: ing, Low: ing.Low()}, nil
+	}
+}
+
+func listIngredientsEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listIngredientsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := inventory.PageMetadata{
+			Offset:  req.offset,
+			Limit:   req.limit,
+			Vendor:  req.vendor,
+			OnlyLow: req.onlyLow,
+		}
+		page, err := svc.ListIngredients(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := ingredientsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Ingredients: []viewIngredientRes{},
+		}
+		for _, ing := range page.Ingredients {
+			res.Ingredients = append(res.Ingredients, viewIngredientRes{Ingredient: ing, Low: ing.Low()})
+		}
+		return res, nil
+	}
+}
+
+func updateIngredientEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateIngredientReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateIngredient(ctx, req.token, req.ing)
+		if err != nil {
+			return nil, err
+		}
+		return updateIngredientRes{ID: id}, nil
+	}
+}
+
+func removeIngredientEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveIngredient(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func saveRecipeEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveRecipeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.SaveRecipe(ctx, req.token, req.recipe); err != nil {
+			return nil, err
+		}
+		return saveRecipeRes{item: req.recipe.Item}, nil
+	}
+}
+
+func viewRecipeEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		recipe, err := svc.ViewRecipe(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewRecipeRes{Recipe: recipe}, nil
+	}
+}
+
+func removeRecipeEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveRecipe(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func adjustStockEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(moveStockReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		movement, err := svc.AdjustStock(ctx, req.token, req.id, req.Quantity, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return movementRes{Movement: movement}, nil
+	}
+}
+
+func logWasteEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(moveStockReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		movement, err := svc.LogWaste(ctx, req.token, req.id, req.Quantity, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return movementRes{Movement: movement}, nil
+	}
+}
+
+func listMovementsEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listMovementsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := inventory.PageMetadata{
+			Offset:     req.offset,
+			Limit:      req.limit,
+			Vendor:     req.vendor,
+			Ingredient: req.ingredient,
+			Type:       req.typ,
+			From:       req.from,
+			To:         req.to,
+		}
+		page, err := svc.ListMovements(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := movementsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Movements: page.Movements,
+		}
+		if res.Movements == nil {
+			res.Movements = []inventory.Movement{}
+		}
+		return res, nil
+	}
+}
+
+func reportEndpoint(svc inventory.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(reportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if req.to.IsZero() {
+			req.to = time.Now()
+		}
+		lines, err := svc.Report(ctx, req.token, req.vendor, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+		res := reportRes{
+			Vendor: req.vendor,
+			To:     req.to.Format(time.RFC3339),
+			Lines:  lines,
+		}
+		if !req.from.IsZero() {
+			res.From = req.from.Format(time.RFC3339)
+		}
+		if res.Lines == nil {
+			res.Lines = []inventory.ReportLine{}
+		}
+		return res, nil
+	}
+}
@@ -0,0 +1,245 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/go-kit/log"
+)
+
+var _ inventory.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    inventory.Service
+}
+
+// LoggingMiddleware adds logging facilities to the inventory service.
+func LoggingMiddleware(svc inventory.Service, logger log.Logger) inventory.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateIngredient(ctx context.Context, token string, ing inventory.Ingredient) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_ingredient",
+			"token", token,
+			"vendor", ing.Vendor,
+			"name", ing.Name,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateIngredient(ctx, token, ing)
+}
+
+func (lm *loggingMiddleware) ViewIngredient(ctx context.Context, token, id string) (ing inventory.Ingredient, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_ingredient",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewIngredient(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListIngredients(ctx context.Context, token string, pm inventory.PageMetadata) (page inventory.IngredientsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_ingredients",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListIngredients(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateIngredient(ctx context.Context, token string, ing inventory.Ingredient) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_ingredient",
+			"token", token,
+			"id", ing.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateIngredient(ctx, token, ing)
+}
+
+func (lm *loggingMiddleware) RemoveIngredient(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_ingredient",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveIngredient(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) SaveRecipe(ctx context.Context, token string, recipe inventory.Recipe) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "save_recipe",
+			"token", token,
+			"item", recipe.Item,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.SaveRecipe(ctx, token, recipe)
+}
+
+func (lm *loggingMiddleware) ViewRecipe(ctx context.Context, token, item string) (recipe inventory.Recipe, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_recipe",
+			"token", token,
+			"item", item,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewRecipe(ctx, token, item)
+}
+
+func (lm *loggingMiddleware) RemoveRecipe(ctx context.Context, token, item string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_recipe",
+			"token", token,
+			"item", item,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveRecipe(ctx, token, item)
+}
+
+func (lm *loggingMiddleware) AdjustStock(ctx context.Context, token, id string, quantity int64, reason string) (movement inventory.Movement, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "adjust_stock",
+			"token", token,
+			"id", id,
+			"quantity", quantity,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.AdjustStock(ctx, token, id, quantity, reason)
+}
+
+func (lm *loggingMiddleware) ReceiveStock(ctx context.Context, token, reference string, deliveries ...inventory.Delivery) (movements []inventory.Movement, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "receive_stock",
+			"token", token,
+			"reference", reference,
+			"deliveries", len(deliveries),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ReceiveStock(ctx, token, reference, deliveries...)
+}
+
+func (lm *loggingMiddleware) LogWaste(ctx context.Context, token, id string, quantity int64, reason string) (movement inventory.Movement, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "log_waste",
+			"token", token,
+			"id", id,
+			"quantity", quantity,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.LogWaste(ctx, token, id, quantity, reason)
+}
+
+func (lm *loggingMiddleware) ListMovements(ctx context.Context, token string, pm inventory.PageMetadata) (page inventory.MovementsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_stock_movements",
+			"token", token,
+			"vendor", pm.Vendor,
+			"ingredient", pm.Ingredient,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListMovements(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) Report(ctx context.Context, token, vendor string, from, to time.Time) (lines []inventory.ReportLine, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "stock_report",
+			"token", token,
+			"vendor", vendor,
+			"from", from,
+			"to", to,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Report(ctx, token, vendor, from, to)
+}
+
+func (lm *loggingMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "deplete_preparing_order",
+			"token", token,
+			"order", order.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPreparing(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "deplete_paid_order",
+			"token", token,
+			"order", order.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPaid(ctx, token, order)
+}
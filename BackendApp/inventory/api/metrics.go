@@ -0,0 +1,165 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ inventory.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     inventory.Service
+}
+
+// MetricsMiddleware instruments the inventory service by tracking request count
+// and latency.
+func MetricsMiddleware(svc inventory.Service, counter metrics.Counter, latency metrics.Histogram) inventory.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateIngredient(ctx context.Context, token string, ing inventory.Ingredient) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_ingredient").Add(1)
+		ms.latency.With("method", "create_ingredient").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateIngredient(ctx, token, ing)
+}
+
+func (ms *metricsMiddleware) ViewIngredient(ctx context.Context, token, id string) (inventory.Ingredient, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_ingredient").Add(1)
+		ms.latency.With("method", "view_ingredient").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewIngredient(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListIngredients(ctx context.Context, token string, pm inventory.PageMetadata) (inventory.IngredientsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_ingredients").Add(1)
+		ms.latency.With("method", "list_ingredients").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListIngredients(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateIngredient(ctx context.Context, token string, ing inventory.Ingredient) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_ingredient").Add(1)
+		ms.latency.With("method", "update_ingredient").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateIngredient(ctx, token, ing)
+}
+
+func (ms *metricsMiddleware) RemoveIngredient(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_ingredient").Add(1)
+		ms.latency.With("method", "remove_ingredient").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveIngredient(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) SaveRecipe(ctx context.Context, token string, recipe inventory.Recipe) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_recipe").Add(1)
+		ms.latency.With("method", "save_recipe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SaveRecipe(ctx, token, recipe)
+}
+
+func (ms *metricsMiddleware) ViewRecipe(ctx context.Context, token, item string) (inventory.Recipe, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_recipe").Add(1)
+		ms.latency.With("method", "view_recipe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRecipe(ctx, token, item)
+}
+
+func (ms *metricsMiddleware) RemoveRecipe(ctx context.Context, token, item string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_recipe").Add(1)
+		ms.latency.With("method", "remove_recipe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRecipe(ctx, token, item)
+}
+
+func (ms *metricsMiddleware) AdjustStock(ctx context.Context, token, id string, quantity int64, reason string) (inventory.Movement, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "adjust_stock").Add(1)
+		ms.latency.With("method", "adjust_stock").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AdjustStock(ctx, token, id, quantity, reason)
+}
+
+func (ms *metricsMiddleware) ReceiveStock(ctx context.Context, token, reference string, deliveries ...inventory.Delivery) ([]inventory.Movement, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "receive_stock").Add(1)
+		ms.latency.With("method", "receive_stock").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReceiveStock(ctx, token, reference, deliveries...)
+}
+
+func (ms *metricsMiddleware) LogWaste(ctx context.Context, token, id string, quantity int64, reason string) (inventory.Movement, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "log_waste").Add(1)
+		ms.latency.With("method", "log_waste").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.LogWaste(ctx, token, id, quantity, reason)
+}
+
+func (ms *metricsMiddleware) ListMovements(ctx context.Context, token string, pm inventory.PageMetadata) (inventory.MovementsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_stock_movements").Add(1)
+		ms.latency.With("method", "list_stock_movements").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListMovements(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) Report(ctx context.Context, token, vendor string, from, to time.Time) ([]inventory.ReportLine, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "stock_report").Add(1)
+		ms.latency.With("method", "stock_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Report(ctx, token, vendor, from, to)
+}
+
+func (ms *metricsMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "deplete_preparing_order").Add(1)
+		ms.latency.With("method", "deplete_preparing_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPreparing(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "deplete_paid_order").Add(1)
+		ms.latency.With("method", "deplete_paid_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPaid(ctx, token, order)
+}
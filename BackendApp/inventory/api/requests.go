@@ -0,0 +1,146 @@
+package api
+
+import (
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+)
+
+const (
+	maxLimitSize = 100
+)
+
+type createIngredientReq struct {
+	token string
+	ing   inventory.Ingredient
+}
+
+func (req createIngredientReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.ing.Validate()
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listIngredientsReq struct {
+	token   string
+	vendor  string
+	onlyLow bool
+	offset  uint64
+	limit   uint64
+}
+
+func (req listIngredientsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type updateIngredientReq struct {
+	token string
+	ing   inventory.Ingredient
+}
+
+func (req updateIngredientReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.ing.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type saveRecipeReq struct {
+	token  string
+	recipe inventory.Recipe
+}
+
+func (req saveRecipeReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.recipe.Item == "" {
+		return errors.ErrMissingID
+	}
+	return req.recipe.Validate()
+}
+
+// moveStockReq adjusts the stock of an ingredient or logs it as wasted.
+type moveStockReq struct {
+	token    string
+	id       string
+	Quantity int64  `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+func (req moveStockReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	if req.Quantity == 0 || req.Reason == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type listMovementsReq struct {
+	token      string
+	vendor     string
+	ingredient string
+	typ        string
+	from       time.Time
+	to         time.Time
+	offset     uint64
+	limit      uint64
+}
+
+func (req listMovementsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type reportReq struct {
+	token  string
+	vendor string
+	from   time.Time
+	to     time.Time
+}
+
+func (req reportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createIngredientRes)(nil)
+	_ Response = (*viewIngredientRes)(nil)
+	_ Response = (*ingredientsPageRes)(nil)
+	_ Response = (*updateIngredientRes)(nil)
+	_ Response = (*removeRes)(nil)
+	_ Response = (*saveRecipeRes)(nil)
+	_ Response = (*viewRecipeRes)(nil)
+	_ Response = (*movementRes)(nil)
+	_ Response = (*movementsPageRes)(nil)
+	_ Response = (*reportRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type createIngredientRes struct {
+	ID string
+}
+
+func (res createIngredientRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createIngredientRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/inventory/ingredients/%s", res.ID),
+	}
+}
+
+func (res createIngredientRes) Empty() bool {
+	return true
+}
+
+type viewIngredientRes struct {
+	inventory.Ingredient
+	Low bool `json:"low"`
+}
+
+func (res viewIngredientRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewIngredientRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewIngredientRes) Empty() bool {
+	return false
+}
+
+type ingredientsPageRes struct {
+	pageRes
+	Ingredients []viewIngredientRes `json:"ingredients"`
+}
+
+func (res ingredientsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ingredientsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ingredientsPageRes) Empty() bool {
+	return false
+}
+
+type updateIngredientRes struct {
+	ID string
+}
+
+func (res updateIngredientRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateIngredientRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/inventory/ingredients/%s", res.ID),
+	}
+}
+
+func (res updateIngredientRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type saveRecipeRes struct {
+	item string
+}
+
+func (res saveRecipeRes) Code() int {
+	return http.StatusOK
+}
+
+func (res saveRecipeRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/menu/%s/recipe", res.item),
+	}
+}
+
+func (res saveRecipeRes) Empty() bool {
+	return true
+}
+
+type viewRecipeRes struct {
+	inventory.Recipe
+}
+
+func (res viewRecipeRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRecipeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRecipeRes) Empty() bool {
+	return false
+}
+
+type movementRes struct {
+	inventory.Movement
+}
+
+func (res movementRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res movementRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res movementRes) Empty() bool {
+	return false
+}
+
+type movementsPageRes struct {
+	pageRes
+	Movements []inventory.Movement `json:"movements"`
+}
+
+func (res movementsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res movementsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res movementsPageRes) Empty() bool {
+	return false
+}
+
+type reportRes struct {
+	Vendor string                 `json:"vendor"`
+	From   string                 `json:"from,omitempty"`
+	To     string                 `json:"to"`
+	Lines  []inventory.ReportLine `json:"lines"`
+}
+
+func (res reportRes) Code() int {
+	return http.StatusOK
+}
+
+func (res reportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res reportRes) Empty() bool {
+	return false
+}
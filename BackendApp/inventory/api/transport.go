@@ -0,0 +1,328 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType   = "application/json"
+	offsetKey     = "offset"
+	limitKey      = "limit"
+	vendorKey     = "vendor"
+	lowKey        = "low"
+	ingredientKey = "ingredient"
+	typeKey       = "type"
+	fromKey       = "from"
+	toKey         = "to"
+)
+
+// MakeInventoryHandler returns a HTTP handler for ingredients, recipes and
+// stock movements API endpoints.
+func MakeInventoryHandler(svc inventory.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/inventory/ingredients").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_ingredient")(createIngredientEndpoint(svc)),
+		decodeCreateIngredient,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/inventory/ingredients/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_ingredient")(viewIngredientEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/inventory/ingredients").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_ingredients")(listIngredientsEndpoint(svc)),
+		decodeListIngredients,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/inventory/ingredients/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_ingredient")(updateIngredientEndpoint(svc)),
+		decodeUpdateIngredient,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/inventory/ingredients/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_ingredient")(removeIngredientEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/inventory/ingredients/{id}/adjustments").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint adjust_stock")(adjustStockEndpoint(svc)),
+		decodeMoveStock,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/inventory/ingredients/{id}/wastage").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint log_waste")(logWasteEndpoint(svc)),
+		decodeMoveStock,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/menu/{id}/recipe").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint save_recipe")(saveRecipeEndpoint(svc)),
+		decodeSaveRecipe,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/menu/{id}/recipe").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_recipe")(viewRecipeEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/menu/{id}/recipe").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_recipe")(removeRecipeEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/inventory/movements").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_stock_movements")(listMovementsEndpoint(svc)),
+		decodeListMovements,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/inventory/report").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint stock_report")(reportEndpoint(svc)),
+		decodeReport,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCreateIngredient(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var ing inventory.Ingredient
+	if err := json.NewDecoder(r.Body).Decode(&ing); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createIngredientReq{
+		token: decodeToken(r),
+		ing:   ing,
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeListIngredients(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listIngredientsReq{
+		token:  decodeToken(r),
+		limit:  maxLimitSize,
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	var err error
+	if r.URL.Query().Has(lowKey) {
+		req.onlyLow, err = strconv.ParseBool(r.URL.Query().Get(lowKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeUpdateIngredient(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var ing inventory.Ingredient
+	if err := json.NewDecoder(r.Body).Decode(&ing); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	ing.ID = mux.Vars(r)["id"]
+	req := updateIngredientReq{
+		token: decodeToken(r),
+		ing:   ing,
+	}
+	return req, nil
+}
+
+func decodeMoveStock(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := moveStockReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeSaveRecipe(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var recipe inventory.Recipe
+	if err := json.NewDecoder(r.Body).Decode(&recipe); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	recipe.Item = mux.Vars(r)["id"]
+	req := saveRecipeReq{
+		token:  decodeToken(r),
+		recipe: recipe,
+	}
+	return req, nil
+}
+
+func decodeListMovements(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listMovementsReq{
+		token:      decodeToken(r),
+		limit:      maxLimitSize,
+		vendor:     r.URL.Query().Get(vendorKey),
+		ingredient: r.URL.Query().Get(ingredientKey),
+		typ:        r.URL.Query().Get(typeKey),
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if req.from, req.to, err = decodePeriod(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeReport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := reportReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	var err error
+	if req.from, req.to, err = decodePeriod(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodePeriod(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if r.URL.Query().Has(fromKey) {
+		from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return from, to, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package inventory tracks the stock of the ingredients a vendor cooks with.
+// Orders deplete stock through the recipes of the menu items ordered and
+// every change to a stock level is recorded as a movement.
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// Units ingredients are counted in. Stock is kept in whole units so
+// ingredients are best counted in small units such as grams.
+var Units = []string{"g", "ml", "pcs"}
+
+// Type describes why the stock of an ingredient moved.
+type Type string
+
+// Types of stock movements.
+const (
+	Sale    Type = "sale"    // Used up by an order.
+	Receive Type = "receive" // Delivered by a supplier.
+	Adjust  Type = "adjust"  // Counted or corrected by hand.
+	Waste   Type = "waste"   // Spoilt, spilt or thrown away.
+)
+
+// Ingredient is a good a vendor keeps in stock i.e. milk.
+type Ingredient struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Unit      string    `json:"unit,omitempty"`       // The unit the ingredient is counted in i.e. ml.
+	Stock     int64     `json:"stock"`                // How much of the ingredient is in stock.
+	Threshold int64     `json:"threshold"`            // The stock level at or below which the ingredient is low.
+	Par       int64     `json:"par"`                  // The stock level to restock up to.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the ingredient was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the ingredient was created in the system.
+}
+
+// Validate returns an error if the ingredient representation is invalid.
+func (ing Ingredient) Validate() error {
+	if ing.Vendor == "" || ing.Name == "" || ing.Threshold < 0 || ing.Par < 0 {
+		return errors.ErrMalformedEntity
+	}
+	for _, unit := range Units {
+		if unit == ing.Unit {
+			return nil
+		}
+	}
+	return errors.ErrMalformedEntity
+}
+
+// Low reports whether the ingredient is running out.
+func (ing Ingredient) Low() bool {
+	return ing.Stock <= ing.Threshold
+}
+
+// Line is how much of an ingredient goes into one unit of a menu item.
+type Line struct {
+	Ingredient string `json:"ingredient"`
+	Quantity   int64  `json:"quantity"`
+}
+
+// Recipe is what a menu item is made of i.e. 200ml of milk and 2g of tea
+// leaves for a cup of tea.
+type Recipe struct {
+	Item      string    `json:"item,omitempty"` // The menu item identifier.
+	Vendor    string    `json:"vendor,omitempty"`
+	Lines     []Line    `json:"lines,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the recipe was updated.
+}
+
+// Validate returns an error if the recipe representation is invalid.
+func (r Recipe) Validate() error {
+	if r.Item == "" || len(r.Lines) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	for _, line := range r.Lines {
+		if line.Ingredient == "" || line.Quantity <= 0 {
+			return errors.ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+// Movement is a change to the stock of an ingredient.
+type Movement struct {
+	ID         string    `json:"id,omitempty"`
+	Vendor     string    `json:"vendor,omitempty"`
+	Ingredient string    `json:"ingredient,omitempty"`
+	Type       Type      `json:"type,omitempty"`       // Why the stock moved.
+	Quantity   int64     `json:"quantity"`             // How much the stock moved by, negative when it went down.
+	Balance    int64     `json:"balance"`              // The stock left after the movement.
+	OrderID    string    `json:"order_id,omitempty"`   // The order that used the stock up.
+	Reference  string    `json:"reference,omitempty"`  // The delivery the stock was received on.
+	Cost       uint64    `json:"cost,omitempty"`       // What a unit received cost.
+	Reason     string    `json:"reason,omitempty"`     // Why the stock was adjusted or wasted.
+	CreatedAt  time.Time `json:"created_at,omitempty"` // When the stock moved.
+}
+
+// Delivery is stock of an ingredient received from a supplier.
+type Delivery struct {
+	Ingredient string `json:"ingredient"`
+	Quantity   int64  `json:"quantity"`
+	Cost       uint64 `json:"cost"` // What a unit cost.
+}
+
+// ReportLine sums up the movements of an ingredient over a period.
+type ReportLine struct {
+	Ingredient string `json:"ingredient"`
+	Name       string `json:"name"`
+	Unit       string `json:"unit"`
+	Opening    int64  `json:"opening"`  // The stock at the start of the period.
+	Received   int64  `json:"received"` // What suppliers delivered.
+	Sold       int64  `json:"sold"`     // What orders used up.
+	Wasted     int64  `json:"wasted"`   // What was wasted.
+	Adjusted   int64  `json:"adjusted"` // The net of the adjustments.
+	Closing    int64  `json:"closing"`  // The stock at the end of the period.
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Vendor     string
+	Ingredient string
+	Type       string
+	From       time.Time
+	To         time.Time
+
+	// OnlyLow limits ingredients to those running out.
+	OnlyLow bool
+}
+
+// IngredientsPage contains a page of ingredients.
+type IngredientsPage struct {
+	PageMetadata
+	Ingredients []Ingredient
+}
+
+// MovementsPage contains a page of stock movements.
+type MovementsPage struct {
+	PageMetadata
+	Movements []Movement
+}
+
+// Service specifies the inventory API.
+type Service interface {
+	orders.Hook
+
+	// CreateIngredient adds an ingredient, its stock is recorded as the
+	// opening stock.
+	CreateIngredient(ctx context.Context, token string, ing Ingredient) (string, error)
+
+	// ViewIngredient retrieves an ingredient by its unique identifier ID.
+	ViewIngredient(ctx context.Context, token, id string) (Ingredient, error)
+
+	// ListIngredients retrieves the ingredients for a given pageMetadata.
+	ListIngredients(ctx context.Context, token string, pm PageMetadata) (IngredientsPage, error)
+
+	// UpdateIngredient updates the name, unit, threshold and par level of
+	// an ingredient. Stock only changes through movements.
+	UpdateIngredient(ctx context.Context, token string, ing Ingredient) (string, error)
+
+	// RemoveIngredient removes an ingredient.
+	RemoveIngredient(ctx context.Context, token, id string) error
+
+	// SaveRecipe creates or replaces the recipe of a menu item.
+	SaveRecipe(ctx context.Context, token string, recipe Recipe) error
+
+	// ViewRecipe retrieves the recipe of a menu item.
+	ViewRecipe(ctx context.Context, token, item string) (Recipe, error)
+
+	// RemoveRecipe removes the recipe of a menu item.
+	RemoveRecipe(ctx context.Context, token, item string) error
+
+	// AdjustStock moves the stock of an ingredient by quantity, i.e. after
+	// a delivery or a stock take. A reason is required.
+	AdjustStock(ctx context.Context, token, id string, quantity int64, reason string) (Movement, error)
+
+	// ReceiveStock adds the deliveries to the stock. The reference is the
+	// delivery note the goods came on, deliveries already received on it
+	// are skipped.
+	ReceiveStock(ctx context.Context, token, reference string, deliveries ...Delivery) ([]Movement, error)
+
+	// LogWaste takes quantity of an ingredient off the stock as wasted. A
+	// reason is required.
+	LogWaste(ctx context.Context, token, id string, quantity int64, reason string) (Movement, error)
+
+	// ListMovements retrieves the stock movements for a given pageMetadata.
+	ListMovements(ctx context.Context, token string, pm PageMetadata) (MovementsPage, error)
+
+	// Report sums up the stock movements of a vendor's ingredients between
+	// from and to.
+	Report(ctx context.Context, token, vendor string, from, to time.Time) ([]ReportLine, error)
+}
+
+// IngredientRepository specifies an ingredient persistence API.
+type IngredientRepository interface {
+	// Save persists the ingredient with no stock.
+	Save(ctx context.Context, ing Ingredient) (string, error)
+
+	// RetrieveByID retrieves an ingredient by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Ingredient, error)
+
+	// RetrieveAll retrieves the ingredients for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (IngredientsPage, error)
+
+	// Update updates the name, unit, threshold and par level of the
+	// ingredient.
+	Update(ctx context.Context, ing Ingredient) (string, error)
+
+	// Remove removes the ingredient.
+	Remove(ctx context.Context, id string) error
+}
+
+// RecipeRepository specifies a recipe persistence API.
+type RecipeRepository interface {
+	// Save creates or replaces the recipe of the menu item.
+	Save(ctx context.Context, recipe Recipe) error
+
+	// Retrieve retrieves the recipe of a menu item.
+	Retrieve(ctx context.Context, item string) (Recipe, error)
+
+	// RetrieveByItems retrieves the recipes of the menu items that have
+	// one.
+	RetrieveByItems(ctx context.Context, items []string) ([]Recipe, error)
+
+	// RetrieveByIngredients retrieves the recipes that use any of the
+	// ingredients.
+	RetrieveByIngredients(ctx context.Context, ingredients []string) ([]Recipe, error)
+
+	// Remove removes the recipe of a menu item.
+	Remove(ctx context.Context, item string) error
+}
+
+// MovementRepository specifies a stock movement persistence API.
+type MovementRepository interface {
+	// Record moves the stock of the ingredients and records the
+	// movements, all or none. An order uses each ingredient up, and a
+	// delivery brings it in, once so such movements that were already
+	// recorded are skipped. The ingredients moved are returned with their
+	// new stock.
+	Record(ctx context.Context, movements ...Movement) ([]Ingredient, error)
+
+	// RetrieveAll retrieves the movements for a given pageMetadata, newest
+	// first.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (MovementsPage, error)
+
+	// Report sums up the movements of a vendor's ingredients between from
+	// and to.
+	Report(ctx context.Context, vendor string, from, to time.Time) ([]ReportLine, error)
+}
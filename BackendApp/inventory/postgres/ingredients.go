@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/jmoiron/sqlx"
+)
+
+const ingredientColumns = `id, vendor, name, unit, stock, threshold, par, created_at, updated_at`
+
+var _ inventory.IngredientRepository = (*ingredientsRepo)(nil)
+
+type ingredientsRepo struct {
+	db *sqlx.DB
+}
+
+// NewIngredientsRepo instantiates a PostgreSQL
+// implementation of ingredients repository.
+func NewIngredientsRepo(db *sqlx.DB) inventory.IngredientRepository {
+	return &ingredientsRepo{
+		db: db,
+	}
+}
+
+func (repo ingredientsRepo) Save(ctx context.Context, ing inventory.Ingredient) (string, error) {
+	q := `INSERT INTO ingredients (` + ingredientColumns + `)
+		  VALUES (:id, :vendor, :name, :unit, 0, :threshold, :par, :created_at, :updated_at) RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBIngredient(ing))
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo ingredientsRepo) RetrieveByID(ctx context.Context, id string) (inventory.Ingredient, error) {
+	q := `SELECT ` + ingredientColumns + ` FROM ingredients WHERE id = $1`
+
+	dbi := dbIngredient{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbi); err != nil {
+		if err == sql.ErrNoRows {
+			return inventory.Ingredient{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return inventory.Ingredient{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toIngredient(dbi), nil
+}
+
+func (repo ingredientsRepo) RetrieveAll(ctx context.Context, pm inventory.PageMetadata) (inventory.IngredientsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.OnlyLow {
+		query = append(query, "stock <= threshold")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+ingredientColumns+` FROM ingredients %s ORDER BY name LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return inventory.IngredientsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []inventory.Ingredient
+	for rows.Next() {
+		dbi := dbIngredient{}
+		if err := rows.StructScan(&dbi); err != nil {
+			return inventory.IngredientsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, toIngredient(dbi))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM ingredients %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return inventory.IngredientsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := inventory.IngredientsPage{
+		Ingredients: items,
+		PageMetadata: inventory.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo ingredientsRepo) Update(ctx context.Context, ing inventory.Ingredient) (string, error) {
+	q := `UPDATE ingredients SET name = :name, unit = :unit, threshold = :threshold, par = :par, updated_at = :updated_at
+		  WHERE id = :id RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBIngredient(ing))
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo ingredientsRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM ingredients WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbIngredient{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbIngredient struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Name      string    `db:"name"`
+	Unit      string    `db:"unit"`
+	Stock     int64     `db:"stock"`
+	Threshold int64     `db:"threshold"`
+	Par       int64     `db:"par"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBIngredient(ing inventory.Ingredient) dbIngredient {
+	return dbIngredient{
+		ID:        ing.ID,
+		Vendor:    ing.Vendor,
+		Name:      ing.Name,
+		Unit:      ing.Unit,
+		Stock:     ing.Stock,
+		Threshold: ing.Threshold,
+		Par:       ing.Par,
+		CreatedAt: ing.CreatedAt,
+		UpdatedAt: ing.UpdatedAt,
+	}
+}
+
+func toIngredient(dbi dbIngredient) inventory.Ingredient {
+	return inventory.Ingredient{
+		ID:        dbi.ID,
+		Vendor:    dbi.Vendor,
+		Name:      dbi.Name,
+		Unit:      dbi.Unit,
+		Stock:     dbi.Stock,
+		Threshold: dbi.Threshold,
+		Par:       dbi.Par,
+		CreatedAt: dbi.CreatedAt,
+		UpdatedAt: dbi.UpdatedAt,
+	}
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/jmoiron/sqlx"
+)
+
+const movementColumns = `id, vendor, ingredient, type, quantity, balance, order_id, reference, cost, reason, created_at`
+
+var _ inventory.MovementRepository = (*movementsRepo)(nil)
+
+type movementsRepo struct {
+	db *sqlx.DB
+}
+
+// NewMovementsRepo instantiates a PostgreSQL
+// implementation of stock movements repository.
+func NewMovementsRepo(db *sqlx.DB) inventory.MovementRepository {
+	return &movementsRepo{
+		db: db,
+	}
+}
+
+func (repo movementsRepo) Record(ctx context.Context, movements ...inventory.Movement) ([]inventory.Ingredient, error) {
+	// The movement goes in first so a sale already recorded for the order,
+	// a delivery already received or a movement of an ingredient since
+	// removed is skipped before the stock moves.
+	mq := `INSERT INTO stock_movements (` + movementColumns + `)
+		   SELECT :id, :vendor, :ingredient, :type, :quantity, 0, :order_id, :reference, :cost, :reason, :created_at
+		   WHERE EXISTS (SELECT 1 FROM ingredients WHERE id = :ingredient)
+		   ON CONFLICT DO NOTHING RETURNING id`
+	sq := `UPDATE ingredients SET stock = stock + $1, updated_at = $2 WHERE id = $3 RETURNING ` + ingredientColumns
+	bq := `UPDATE stock_movements SET balance = $1 WHERE id = $2`
+
+	var moved []inventory.Ingredient
+	err := pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		moved = nil
+		for _, m := range movements {
+			rows, err := sqlx.NamedQueryContext(ctx, tx, mq, toDBMovement(m))
+			if err != nil {
+				return handleError(err, errors.ErrCreateEntity)
+			}
+			recorded := rows.Next()
+			rows.Close()
+			if !recorded {
+				continue
+			}
+
+			dbi := dbIngredient{}
+			if err := tx.QueryRowxContext(ctx, sq, m.Quantity, m.CreatedAt, m.Ingredient).StructScan(&dbi); err != nil {
+				return handleError(err, errors.ErrUpdateEntity)
+			}
+			if _, err := tx.ExecContext(ctx, bq, dbi.Stock, m.ID); err != nil {
+				return errors.Wrap(errors.ErrUpdateEntity, err)
+			}
+			moved = append(moved, toIngredient(dbi))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (repo movementsRepo) RetrieveAll(ctx context.Context, pm inventory.PageMetadata) (inventory.MovementsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":      pm.Limit,
+		"offset":     pm.Offset,
+		"vendor":     pm.Vendor,
+		"ingredient": pm.Ingredient,
+		"type":       pm.Type,
+		"from":       pm.From,
+		"to":         pm.To,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Ingredient != "" {
+		query = append(query, "ingredient = :ingredient")
+	}
+	if pm.Type != "" {
+		query = append(query, "type = :type")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "created_at >= :from")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "created_at <= :to")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+movementColumns+` FROM stock_movements %s ORDER BY created_at DESC, id DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return inventory.MovementsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []inventory.Movement
+	for rows.Next() {
+		dbm := dbMovement{}
+		if err := rows.StructScan(&dbm); err != nil {
+			return inventory.MovementsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, toMovement(dbm))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM stock_movements %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return inventory.MovementsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := inventory.MovementsPage{
+		Movements: items,
+		PageMetadata: inventory.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo movementsRepo) Report(ctx context.Context, vendor string, from, to time.Time) ([]inventory.ReportLine, error) {
+	q := `SELECT i.id AS ingredient, i.name, i.unit,
+			COALESCE(SUM(m.quantity) FILTER (WHERE m.created_at < :from), 0) AS opening,
+			COALESCE(SUM(m.quantity) FILTER (WHERE m.type = 'receive' AND m.created_at >= :from AND m.created_at <= :to), 0) AS received,
+			COALESCE(-SUM(m.quantity) FILTER (WHERE m.type = 'sale' AND m.created_at >= :from AND m.created_at <= :to), 0) AS sold,
+			COALESCE(-SUM(m.quantity) FILTER (WHERE m.type = 'waste' AND m.created_at >= :from AND m.created_at <= :to), 0) AS wasted,
+			COALESCE(SUM(m.quantity) FILTER (WHERE m.type = 'adjust' AND m.created_at >= :from AND m.created_at <= :to), 0) AS adjusted,
+			COALESCE(SUM(m.quantity) FILTER (WHERE m.created_at <= :to), 0) AS closing
+		  FROM ingredients i LEFT JOIN stock_movements m ON m.ingredient = i.id
+		  WHERE i.vendor = :vendor
+		  GROUP BY i.id, i.name, i.unit ORDER BY i.name`
+
+	params := map[string]interface{}{
+		"vendor": vendor,
+		"from":   from,
+		"to":     to,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var lines []inventory.ReportLine
+	for rows.Next() {
+		dbl := dbReportLine{}
+		if err := rows.StructScan(&dbl); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		lines = append(lines, inventory.ReportLine(dbl))
+	}
+	return lines, nil
+}
+
+type dbMovement struct {
+	ID         string    `db:"id"`
+	Vendor     string    `db:"vendor"`
+	Ingredient string    `db:"ingredient"`
+	Type       string    `db:"type"`
+	Quantity   int64     `db:"quantity"`
+	Balance    int64     `db:"balance"`
+	OrderID    string    `db:"order_id"`
+	Reference  string    `db:"reference"`
+	Cost       uint64    `db:"cost"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func toDBMovement(m inventory.Movement) dbMovement {
+	return dbMovement{
+		ID:         m.ID,
+		Vendor:     m.Vendor,
+		Ingredient: m.Ingredient,
+		Type:       string(m.Type),
+		Quantity:   m.Quantity,
+		Balance:    m.Balance,
+		OrderID:    m.OrderID,
+		Reference:  m.Reference,
+		Cost:       m.Cost,
+		Reason:     m.Reason,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func toMovement(dbm dbMovement) inventory.Movement {
+	return inventory.Movement{
+		ID:         dbm.ID,
+		Vendor:     dbm.Vendor,
+		Ingredient: dbm.Ingredient,
+		Type:       inventory.Type(dbm.Type),
+		Quantity:   dbm.Quantity,
+		Balance:    dbm.Balance,
+		OrderID:    dbm.OrderID,
+		Reference:  dbm.Reference,
+		Cost:       dbm.Cost,
+		Reason:     dbm.Reason,
+		CreatedAt:  dbm.CreatedAt,
+	}
+}
+
+type dbReportLine struct {
+	Ingredient string `db:"ingredient"`
+	Name       string `db:"name"`
+	Unit       string `db:"unit"`
+	Opening    int64  `db:"opening"`
+	Received   int64  `db:"received"`
+	Sold       int64  `db:"sold"`
+	Wasted     int64  `db:"wasted"`
+	Adjusted   int64  `db:"adjusted"`
+	Closing    int64  `db:"closing"`
+}
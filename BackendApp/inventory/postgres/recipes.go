@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ inventory.RecipeRepository = (*recipesRepo)(nil)
+
+type recipesRepo struct {
+	db *sqlx.DB
+}
+
+// NewRecipesRepo instantiates a PostgreSQL
+// implementation of recipes repository.
+func NewRecipesRepo(db *sqlx.DB) inventory.RecipeRepository {
+	return &recipesRepo{
+		db: db,
+	}
+}
+
+func (repo recipesRepo) Save(ctx context.Context, recipe inventory.Recipe) error {
+	q := `INSERT INTO recipes (item, vendor, lines, updated_at) VALUES (:item, :vendor, :lines, :updated_at)
+		  ON CONFLICT (item) DO UPDATE SET vendor = :vendor, lines = :lines, updated_at = :updated_at`
+
+	dbr, err := toDBRecipe(recipe)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, dbr); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo recipesRepo) Retrieve(ctx context.Context, item string) (inventory.Recipe, error) {
+	q := `SELECT item, vendor, lines, updated_at FROM recipes WHERE item = $1`
+
+	dbr := dbRecipe{}
+	if err := repo.db.QueryRowxContext(ctx, q, item).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return inventory.Recipe{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return inventory.Recipe{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toRecipe(dbr)
+}
+
+func (repo recipesRepo) RetrieveByItems(ctx context.Context, items []string) ([]inventory.Recipe, error) {
+	q := `SELECT item, vendor, lines, updated_at FROM recipes WHERE item = ANY($1)`
+
+	return repo.retrieveAll(ctx, q, items)
+}
+
+func (repo recipesRepo) RetrieveByIngredients(ctx context.Context, ingredients []string) ([]inventory.Recipe, error) {
+	q := `SELECT item, vendor, lines, updated_at FROM recipes
+		  WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(lines) AS line WHERE line->>'ingredient' = ANY($1))`
+
+	return repo.retrieveAll(ctx, q, ingredients)
+}
+
+func (repo recipesRepo) retrieveAll(ctx context.Context, q string, ids []string) ([]inventory.Recipe, error) {
+	rows, err := repo.db.QueryxContext(ctx, q, ids)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var recipes []inventory.Recipe
+	for rows.Next() {
+		dbr := dbRecipe{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		recipe, err := toRecipe(dbr)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+func (repo recipesRepo) Remove(ctx context.Context, item string) error {
+	q := `DELETE FROM recipes WHERE item = :item`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbRecipe{Item: item}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+type dbRecipe struct {
+	Item      string    `db:"item"`
+	Vendor    string    `db:"vendor"`
+	Lines     []byte    `db:"lines"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBRecipe(recipe inventory.Recipe) (dbRecipe, error) {
+	lines := []byte("[]")
+	if len(recipe.Lines) > 0 {
+		b, err := json.Marshal(recipe.Lines)
+		if err != nil {
+			return dbRecipe{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		lines = b
+	}
+	return dbRecipe{
+		Item:      recipe.Item,
+		Vendor:    recipe.Vendor,
+		Lines:     lines,
+		UpdatedAt: recipe.UpdatedAt,
+	}, nil
+}
+
+func toRecipe(dbr dbRecipe) (inventory.Recipe, error) {
+	var lines []inventory.Line
+	if dbr.Lines != nil {
+		if err := json.Unmarshal(dbr.Lines, &lines); err != nil {
+			return inventory.Recipe{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return inventory.Recipe{
+		Item:      dbr.Item,
+		Vendor:    dbr.Vendor,
+		Lines:     lines,
+		UpdatedAt: dbr.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,376 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+// SoldOutKey marks the menu items the inventory made unavailable, only
+// those are made available again once their ingredients are restocked.
+const SoldOutKey = "inventory_sold_out"
+
+const openingStock = "Opening stock"
+
+var _ Service = (*inventoryService)(nil)
+
+type inventoryService struct {
+	ingredients IngredientRepository
+	recipes     RecipeRepository
+	movements   MovementRepository
+	menu        menu.Service
+}
+
+// NewService instantiates the inventory service implementation.
+func NewService(ingredients IngredientRepository, recipes RecipeRepository, movements MovementRepository, menuSvc menu.Service) Service {
+	return &inventoryService{
+		ingredients: ingredients,
+		recipes:     recipes,
+		movements:   movements,
+		menu:        menuSvc,
+	}
+}
+
+func (svc inventoryService) CreateIngredient(ctx context.Context, token string, ing Ingredient) (string, error) {
+	if err := ing.Validate(); err != nil {
+		return "", err
+	}
+	stock := ing.Stock
+	ing.ID = ulid.Make().String()
+	ing.Stock = 0
+	ing.CreatedAt = time.Now()
+	ing.UpdatedAt = time.Now()
+	id, err := svc.ingredients.Save(ctx, ing)
+	if err != nil {
+		return "", err
+	}
+	if stock == 0 {
+		return id, nil
+	}
+	movement := Movement{
+		ID:         ulid.Make().String(),
+		Vendor:     ing.Vendor,
+		Ingredient: id,
+		Type:       Adjust,
+		Quantity:   stock,
+		Reason:     openingStock,
+		CreatedAt:  time.Now(),
+	}
+	if _, err := svc.movements.Record(ctx, movement); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (svc inventoryService) ViewIngredient(ctx context.Context, token, id string) (Ingredient, error) {
+	return svc.ingredients.RetrieveByID(ctx, id)
+}
+
+func (svc inventoryService) ListIngredients(ctx context.Context, token string, pm PageMetadata) (IngredientsPage, error) {
+	return svc.ingredients.RetrieveAll(ctx, pm)
+}
+
+func (svc inventoryService) UpdateIngredient(ctx context.Context, token string, ing Ingredient) (string, error) {
+	current, err := svc.ingredients.RetrieveByID(ctx, ing.ID)
+	if err != nil {
+		return "", err
+	}
+	ing.Vendor = current.Vendor
+	if err := ing.Validate(); err != nil {
+		return "", err
+	}
+	ing.Stock = current.Stock
+	ing.CreatedAt = current.CreatedAt
+	ing.UpdatedAt = time.Now()
+	id, err := svc.ingredients.Update(ctx, ing)
+	if err != nil {
+		return "", err
+	}
+	// A new threshold may make the ingredient low or no longer low.
+	if ing.Low() != current.Low() {
+		if err := svc.availability(ctx, token, []Ingredient{ing}); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+func (svc inventoryService) RemoveIngredient(ctx context.Context, token, id string) error {
+	return svc.ingredients.Remove(ctx, id)
+}
+
+func (svc inventoryService) SaveRecipe(ctx context.Context, token string, recipe Recipe) error {
+	if err := recipe.Validate(); err != nil {
+		return err
+	}
+	item, err := svc.menu.ViewItem(ctx, token, recipe.Item)
+	if err != nil {
+		return err
+	}
+	for _, line := range recipe.Lines {
+		ing, err := svc.ingredients.RetrieveByID(ctx, line.Ingredient)
+		if err != nil {
+			return err
+		}
+		if ing.Vendor != item.Vendor {
+			return errors.ErrMalformedEntity
+		}
+	}
+	recipe.Vendor = item.Vendor
+	recipe.UpdatedAt = time.Now()
+	return svc.recipes.Save(ctx, recipe)
+}
+
+func (svc inventoryService) ViewRecipe(ctx context.Context, token, item string) (Recipe, error) {
+	return svc.recipes.Retrieve(ctx, item)
+}
+
+func (svc inventoryService) RemoveRecipe(ctx context.Context, token, item string) error {
+	return svc.recipes.Remove(ctx, item)
+}
+
+func (svc inventoryService) AdjustStock(ctx context.Context, token, id string, quantity int64, reason string) (Movement, error) {
+	if quantity == 0 || reason == "" {
+		return Movement{}, errors.ErrMalformedEntity
+	}
+	return svc.move(ctx, token, id, Adjust, quantity, reason)
+}
+
+func (svc inventoryService) ReceiveStock(ctx context.Context, token, reference string, deliveries ...Delivery) ([]Movement, error) {
+	if reference == "" || len(deliveries) == 0 {
+		return nil, errors.ErrMalformedEntity
+	}
+	var movements []Movement
+	for _, d := range deliveries {
+		if d.Quantity <= 0 {
+			return nil, errors.ErrMalformedEntity
+		}
+		ing, err := svc.ingredients.RetrieveByID(ctx, d.Ingredient)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, Movement{
+			ID:         ulid.Make().String(),
+			Vendor:     ing.Vendor,
+			Ingredient: ing.ID,
+			Type:       Receive,
+			Quantity:   d.Quantity,
+			Reference:  reference,
+			Cost:       d.Cost,
+			CreatedAt:  time.Now(),
+		})
+	}
+	moved, err := svc.movements.Record(ctx, movements...)
+	if err != nil {
+		return nil, err
+	}
+	stock := make(map[string]int64)
+	for _, ing := range moved {
+		stock[ing.ID] = ing.Stock
+	}
+	var received []Movement
+	for _, m := range movements {
+		if balance, ok := stock[m.Ingredient]; ok {
+			m.Balance = balance
+			received = append(received, m)
+		}
+	}
+	if err := svc.availability(ctx, token, moved); err != nil {
+		return received, err
+	}
+	return received, nil
+}
+
+func (svc inventoryService) LogWaste(ctx context.Context, token, id string, quantity int64, reason string) (Movement, error) {
+	if quantity <= 0 || reason == "" {
+		return Movement{}, errors.ErrMalformedEntity
+	}
+	return svc.move(ctx, token, id, Waste, -quantity, reason)
+}
+
+func (svc inventoryService) ListMovements(ctx context.Context, token string, pm PageMetadata) (MovementsPage, error) {
+	return svc.movements.RetrieveAll(ctx, pm)
+}
+
+func (svc inventoryService) Report(ctx context.Context, token, vendor string, from, to time.Time) ([]ReportLine, error) {
+	if vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if to.Before(from) {
+		return nil, errors.ErrMalformedEntity
+	}
+	return svc.movements.Report(ctx, vendor, from, to)
+}
+
+// OrderPreparing uses up the ingredients of the order as the kitchen starts
+// on it.
+func (svc inventoryService) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	return svc.deplete(ctx, token, order)
+}
+
+// OrderPaid uses up the ingredients of orders that were paid without the
+// kitchen preparing them first, i.e. sales over the counter.
+func (svc inventoryService) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	return svc.deplete(ctx, token, order)
+}
+
+// deplete takes the ingredients of the order's items off the stock. An
+// order is only depleted once however many times it is notified.
+func (svc inventoryService) deplete(ctx context.Context, token string, order orders.Order) error {
+	quantities := make(map[string]uint64)
+	var items []string
+	for _, item := range order.Items {
+		if item.ID == "" {
+			continue
+		}
+		if _, ok := quantities[item.ID]; !ok {
+			items = append(items, item.ID)
+		}
+		quantities[item.ID] += item.Quantity
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	recipes, err := svc.recipes.RetrieveByItems(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]int64)
+	var ingredients []string
+	for _, recipe := range recipes {
+		for _, line := range recipe.Lines {
+			if _, ok := used[line.Ingredient]; !ok {
+				ingredients = append(ingredients, line.Ingredient)
+			}
+			used[line.Ingredient] += line.Quantity * int64(quantities[recipe.Item])
+		}
+	}
+	if len(ingredients) == 0 {
+		return nil
+	}
+	var movements []Movement
+	for _, ing := range ingredients {
+		movements = append(movements, Movement{
+			ID:         ulid.Make().String(),
+			Vendor:     order.Vendor,
+			Ingredient: ing,
+			Type:       Sale,
+			Quantity:   -used[ing],
+			OrderID:    order.ID,
+			CreatedAt:  time.Now(),
+		})
+	}
+	moved, err := svc.movements.Record(ctx, movements...)
+	if err != nil {
+		return err
+	}
+	return svc.availability(ctx, token, moved)
+}
+
+func (svc inventoryService) move(ctx context.Context, token, id string, typ Type, quantity int64, reason string) (Movement, error) {
+	ing, err := svc.ingredients.RetrieveByID(ctx, id)
+	if err != nil {
+		return Movement{}, err
+	}
+	movement := Movement{
+		ID:         ulid.Make().String(),
+		Vendor:     ing.Vendor,
+		Ingredient: id,
+		Type:       typ,
+		Quantity:   quantity,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+	moved, err := svc.movements.Record(ctx, movement)
+	if err != nil {
+		return Movement{}, err
+	}
+	for _, m := range moved {
+		movement.Balance = m.Stock
+	}
+	if err := svc.availability(ctx, token, moved); err != nil {
+		return movement, err
+	}
+	return movement, nil
+}
+
+// availability marks the menu items made with ingredients that are running
+// out sold out, and makes those it marked available again once all their
+// ingredients are back in stock.
+func (svc inventoryService) availability(ctx context.Context, token string, moved []Ingredient) error {
+	if len(moved) == 0 {
+		return nil
+	}
+	low := make(map[string]bool)
+	var ids []string
+	for _, ing := range moved {
+		low[ing.ID] = ing.Low()
+		ids = append(ids, ing.ID)
+	}
+	recipes, err := svc.recipes.RetrieveByIngredients(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		soldOut, err := svc.soldOut(ctx, recipe, low)
+		if err != nil {
+			return err
+		}
+		item, err := svc.menu.ViewItem(ctx, token, recipe.Item)
+		if errors.Contains(err, errors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		marked, _ := item.Metadata[SoldOutKey].(bool)
+		switch {
+		case soldOut && item.Available:
+			if item.Metadata == nil {
+				item.Metadata = menu.Metadata{}
+			}
+			item.Metadata[SoldOutKey] = true
+			item.Available = false
+		case !soldOut && !item.Available && marked:
+			delete(item.Metadata, SoldOutKey)
+			item.Available = true
+		default:
+			continue
+		}
+		if _, err := svc.menu.UpdateItem(ctx, token, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// soldOut reports whether any ingredient of the recipe is running out. The
+// ingredients known to be low or not are not looked up again.
+func (svc inventoryService) soldOut(ctx context.Context, recipe Recipe, low map[string]bool) (bool, error) {
+	for _, line := range recipe.Lines {
+		isLow, ok := low[line.Ingredient]
+		if !ok {
+			ing, err := svc.ingredients.RetrieveByID(ctx, line.Ingredient)
+			if errors.Contains(err, errors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			isLow = ing.Low()
+			low[line.Ingredient] = isLow
+		}
+		if isLow {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func saveProgramEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveProgramReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.SaveProgram(ctx, req.token, req.program); err != nil {
+			return nil, err
+		}
+		return saveProgramRes{vendor: req.program.Vendor}, nil
+	}
+}
+
+func viewProgramEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(vendorReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		program, err := svc.ViewProgram(ctx, req.token, req.vendor)
+		if err != nil {
+			return nil, err
+		}
+		return viewProgramRes{Program: program}, nil
+	}
+}
+
+func createCardEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createCardReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateCard(ctx, req.token, req.card)
+		if err != nil {
+			return nil, err
+		}
+		return createCardRes{ID: id}, nil
+	}
+}
+
+func viewCardEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		card, err := svc.ViewCard(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewCardRes{Card: card}, nil
+	}
+}
+
+func listCardsEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(vendorReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		cards, err := svc.ListCards(ctx, req.token, req.vendor)
+		if err != nil {
+			return nil, err
+		}
+		res := listCardsRes{Cards: []viewCardRes{}}
+		for _, card := range cards {
+			res.Cards = append(res.Cards, viewCardRes{Card: card})
+		}
+		return res, nil
+	}
+}
+
+func updateCardEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateCardReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateCard(ctx, req.token, req.card)
+		if err != nil {
+			return nil, err
+		}
+		return updateCardRes{ID: id}, nil
+	}
+}
+
+func removeCardEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveCard(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeCardRes{}, nil
+	}
+}
+
+func balanceEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(balanceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		balance, err := svc.Balance(ctx, req.token, req.vendor, req.customer)
+		if err != nil {
+			return nil, err
+		}
+		return balanceRes{Balance: balance}, nil
+	}
+}
+
+func historyEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(historyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := loyalty.PageMetadata{
+			Offset:   req.offset,
+			Limit:    req.limit,
+			Vendor:   req.vendor,
+			Customer: req.customer,
+			Card:     req.card,
+		}
+		page, err := svc.History(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := historyRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Entries: page.Entries,
+		}
+		if res.Entries == nil {
+			res.Entries = []loyalty.Entry{}
+		}
+		return res, nil
+	}
+}
+
+func adjustEndpoint(svc loyalty.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(adjustReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		entry := loyalty.Entry{
+			Vendor:   req.Vendor,
+			Customer: req.Customer,
+			Card:     req.Card,
+			Amount:   req.Amount,
+			Reason:   req.Reason,
+		}
+		id, err := svc.Adjust(ctx, req.token, entry)
+		if err != nil {
+			return nil, err
+		}
+		return adjustRes{ID: id}, nil
+	}
+}
@@ -0,0 +1,229 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/go-kit/log"
+)
+
+var _ loyalty.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    loyalty.Service
+}
+
+// LoggingMiddleware adds logging facilities to the loyalty service.
+func LoggingMiddleware(svc loyalty.Service, logger log.Logger) loyalty.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) SaveProgram(ctx context.Context, token string, program loyalty.Program) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "save_loyalty_program",
+			"token", token,
+			"vendor", program.Vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.SaveProgram(ctx, token, program)
+}
+
+func (lm *loggingMiddleware) ViewProgram(ctx context.Context, token, vendor string) (program loyalty.Program, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_loyalty_program",
+			"token", token,
+			"vendor", vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewProgram(ctx, token, vendor)
+}
+
+func (lm *loggingMiddleware) CreateCard(ctx context.Context, token string, card loyalty.Card) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_stamp_card",
+			"token", token,
+			"vendor", card.Vendor,
+			"name", card.Name,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateCard(ctx, token, card)
+}
+
+func (lm *loggingMiddleware) ViewCard(ctx context.Context, token, id string) (card loyalty.Card, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_stamp_card",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewCard(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListCards(ctx context.Context, token, vendor string) (cards []loyalty.Card, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_stamp_cards",
+			"token", token,
+			"vendor", vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListCards(ctx, token, vendor)
+}
+
+func (lm *loggingMiddleware) UpdateCard(ctx context.Context, token string, card loyalty.Card) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_stamp_card",
+			"token", token,
+			"id", card.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateCard(ctx, token, card)
+}
+
+func (lm *loggingMiddleware) RemoveCard(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_stamp_card",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveCard(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Balance(ctx context.Context, token, vendor, customer string) (balance loyalty.Balance, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "loyalty_balance",
+			"token", token,
+			"vendor", vendor,
+			"customer", customer,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Balance(ctx, token, vendor, customer)
+}
+
+func (lm *loggingMiddleware) History(ctx context.Context, token string, pm loyalty.PageMetadata) (page loyalty.EntriesPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "loyalty_history",
+			"token", token,
+			"vendor", pm.Vendor,
+			"customer", pm.Customer,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.History(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) Adjust(ctx context.Context, token string, entry loyalty.Entry) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "adjust_loyalty_balance",
+			"token", token,
+			"vendor", entry.Vendor,
+			"customer", entry.Customer,
+			"amount", entry.Amount,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Adjust(ctx, token, entry)
+}
+
+func (lm *loggingMiddleware) Redeem(ctx context.Context, token string, order orders.Order, points uint64) (adjustments []orders.Adjustment, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "redeem_loyalty",
+			"token", token,
+			"vendor", order.Vendor,
+			"points", points,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Redeem(ctx, token, order, points)
+}
+
+func (lm *loggingMiddleware) Refund(ctx context.Context, token string, adjustments []orders.Adjustment) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "refund_loyalty",
+			"token", token,
+			"adjustments", len(adjustments),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Refund(ctx, token, adjustments)
+}
+
+func (lm *loggingMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "order_preparing",
+			"token", token,
+			"order", order.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPreparing(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "earn_loyalty",
+			"token", token,
+			"order", order.ID,
+			"price", order.Price,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPaid(ctx, token, order)
+}
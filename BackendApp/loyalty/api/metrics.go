@@ -0,0 +1,156 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ loyalty.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     loyalty.Service
+}
+
+// MetricsMiddleware instruments the loyalty service by tracking request count
+// and latency.
+func MetricsMiddleware(svc loyalty.Service, counter metrics.Counter, latency metrics.Histogram) loyalty.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) SaveProgram(ctx context.Context, token string, program loyalty.Program) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_loyalty_program").Add(1)
+		ms.latency.With("method", "save_loyalty_program").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SaveProgram(ctx, token, program)
+}
+
+func (ms *metricsMiddleware) ViewProgram(ctx context.Context, token, vendor string) (loyalty.Program, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_loyalty_program").Add(1)
+		ms.latency.With("method", "view_loyalty_program").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewProgram(ctx, token, vendor)
+}
+
+func (ms *metricsMiddleware) CreateCard(ctx context.Context, token string, card loyalty.Card) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_stamp_card").Add(1)
+		ms.latency.With("method", "create_stamp_card").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateCard(ctx, token, card)
+}
+
+func (ms *metricsMiddleware) ViewCard(ctx context.Context, token, id string) (loyalty.Card, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_stamp_card").Add(1)
+		ms.latency.With("method", "view_stamp_card").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewCard(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListCards(ctx context.Context, token, vendor string) ([]loyalty.Card, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_stamp_cards").Add(1)
+		ms.latency.With("method", "list_stamp_cards").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListCards(ctx, token, vendor)
+}
+
+func (ms *metricsMiddleware) UpdateCard(ctx context.Context, token string, card loyalty.Card) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_stamp_card").Add(1)
+		ms.latency.With("method", "update_stamp_card").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateCard(ctx, token, card)
+}
+
+func (ms *metricsMiddleware) RemoveCard(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_stamp_card").Add(1)
+		ms.latency.With("method", "remove_stamp_card").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveCard(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Balance(ctx context.Context, token, vendor, customer string) (loyalty.Balance, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "loyalty_balance").Add(1)
+		ms.latency.With("method", "loyalty_balance").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Balance(ctx, token, vendor, customer)
+}
+
+func (ms *metricsMiddleware) History(ctx context.Context, token string, pm loyalty.PageMetadata) (loyalty.EntriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "loyalty_history").Add(1)
+		ms.latency.With("method", "loyalty_history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.History(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) Adjust(ctx context.Context, token string, entry loyalty.Entry) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "adjust_loyalty_balance").Add(1)
+		ms.latency.With("method", "adjust_loyalty_balance").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Adjust(ctx, token, entry)
+}
+
+func (ms *metricsMiddleware) Redeem(ctx context.Context, token string, order orders.Order, points uint64) ([]orders.Adjustment, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "redeem_loyalty").Add(1)
+		ms.latency.With("method", "redeem_loyalty").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Redeem(ctx, token, order, points)
+}
+
+func (ms *metricsMiddleware) Refund(ctx context.Context, token string, adjustments []orders.Adjustment) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "refund_loyalty").Add(1)
+		ms.latency.With("method", "refund_loyalty").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Refund(ctx, token, adjustments)
+}
+
+func (ms *metricsMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "order_preparing").Add(1)
+		ms.latency.With("method", "order_preparing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPreparing(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "earn_loyalty").Add(1)
+		ms.latency.With("method", "earn_loyalty").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPaid(ctx, token, order)
+}
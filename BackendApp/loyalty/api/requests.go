@@ -0,0 +1,136 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+)
+
+const (
+	maxLimitSize = 100
+)
+
+type saveProgramReq struct {
+	token   string
+	program loyalty.Program
+}
+
+func (req saveProgramReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.program.Validate()
+}
+
+type vendorReq struct {
+	token  string
+	vendor string
+}
+
+func (req vendorReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type createCardReq struct {
+	token string
+	card  loyalty.Card
+}
+
+func (req createCardReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.card.Validate()
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type updateCardReq struct {
+	token string
+	card  loyalty.Card
+}
+
+func (req updateCardReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.card.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type balanceReq struct {
+	token    string
+	vendor   string
+	customer string
+}
+
+func (req balanceReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.customer == "" {
+		return errors.ErrMissingID
+	}
+	if req.vendor == "" {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
+
+type historyReq struct {
+	token    string
+	vendor   string
+	customer string
+	card     string
+	offset   uint64
+	limit    uint64
+}
+
+func (req historyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type adjustReq struct {
+	token    string
+	Vendor   string `json:"vendor"`
+	Customer string `json:"customer"`
+	Card     string `json:"card,omitempty"`
+	Amount   int64  `json:"amount"`
+	Reason   string `json:"reason"`
+}
+
+func (req adjustReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.Vendor == "" || req.Customer == "" || req.Amount == 0 || req.Reason == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*saveProgramRes)(nil)
+	_ Response = (*viewProgramRes)(nil)
+	_ Response = (*createCardRes)(nil)
+	_ Response = (*viewCardRes)(nil)
+	_ Response = (*listCardsRes)(nil)
+	_ Response = (*updateCardRes)(nil)
+	_ Response = (*removeCardRes)(nil)
+	_ Response = (*balanceRes)(nil)
+	_ Response = (*historyRes)(nil)
+	_ Response = (*adjustRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type saveProgramRes struct {
+	vendor string
+}
+
+func (res saveProgramRes) Code() int {
+	return http.StatusOK
+}
+
+func (res saveProgramRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/loyalty/programs/%s", res.vendor),
+	}
+}
+
+func (res saveProgramRes) Empty() bool {
+	return true
+}
+
+type viewProgramRes struct {
+	loyalty.Program
+}
+
+func (res viewProgramRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewProgramRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewProgramRes) Empty() bool {
+	return false
+}
+
+type createCardRes struct {
+	ID string
+}
+
+func (res createCardRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createCardRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/loyalty/cards/%s", res.ID),
+	}
+}
+
+func (res createCardRes) Empty() bool {
+	return true
+}
+
+type viewCardRes struct {
+	loyalty.Card
+}
+
+func (res viewCardRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewCardRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewCardRes) Empty() bool {
+	return false
+}
+
+type listCardsRes struct {
+	Cards []viewCardRes `json:"cards"`
+}
+
+func (res listCardsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listCardsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listCardsRes) Empty() bool {
+	return false
+}
+
+type updateCardRes struct {
+	ID string
+}
+
+func (res updateCardRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateCardRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/loyalty/cards/%s", res.ID),
+	}
+}
+
+func (res updateCardRes) Empty() bool {
+	return true
+}
+
+type removeCardRes struct{}
+
+func (res removeCardRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeCardRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeCardRes) Empty() bool {
+	return true
+}
+
+type balanceRes struct {
+	loyalty.Balance
+}
+
+func (res balanceRes) Code() int {
+	return http.StatusOK
+}
+
+func (res balanceRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res balanceRes) Empty() bool {
+	return false
+}
+
+type historyRes struct {
+	pageRes
+	Entries []loyalty.Entry `json:"entries"`
+}
+
+func (res historyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res historyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res historyRes) Empty() bool {
+	return false
+}
+
+type adjustRes struct {
+	ID string `json:"id"`
+}
+
+func (res adjustRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res adjustRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res adjustRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	customerKey = "customer"
+	cardKey     = "card"
+)
+
+// MakeLoyaltyHandler returns a HTTP handler for loyalty programs, stamp
+// cards and balances API endpoints.
+func MakeLoyaltyHandler(svc loyalty.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("PUT").Path("/loyalty/programs/{vendor}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint save_loyalty_program")(saveProgramEndpoint(svc)),
+		decodeSaveProgram,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/loyalty/programs/{vendor}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_loyalty_program")(viewProgramEndpoint(svc)),
+		decodeVendor,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/loyalty/cards").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_stamp_card")(createCardEndpoint(svc)),
+		decodeCreateCard,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/loyalty/cards/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_stamp_card")(viewCardEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/loyalty/cards").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_stamp_cards")(listCardsEndpoint(svc)),
+		decodeVendor,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/loyalty/cards/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_stamp_card")(updateCardEndpoint(svc)),
+		decodeUpdateCard,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/loyalty/cards/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_stamp_card")(removeCardEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/loyalty/balances/{customer}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint loyalty_balance")(balanceEndpoint(svc)),
+		decodeBalance,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/loyalty/ledger").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint loyalty_history")(historyEndpoint(svc)),
+		decodeHistory,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/loyalty/adjustments").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint adjust_loyalty_balance")(adjustEndpoint(svc)),
+		decodeAdjust,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeSaveProgram(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var program loyalty.Program
+	if err := json.NewDecoder(r.Body).Decode(&program); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	program.Vendor = mux.Vars(r)[vendorKey]
+	req := saveProgramReq{
+		token:   decodeToken(r),
+		program: program,
+	}
+	return req, nil
+}
+
+// decodeVendor reads the vendor from the path or else from the query.
+func decodeVendor(_ context.Context, r *http.Request) (interface{}, error) {
+	vendor, ok := mux.Vars(r)[vendorKey]
+	if !ok {
+		vendor = r.URL.Query().Get(vendorKey)
+	}
+	req := vendorReq{
+		token:  decodeToken(r),
+		vendor: vendor,
+	}
+	return req, nil
+}
+
+func decodeCreateCard(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var card loyalty.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createCardReq{
+		token: decodeToken(r),
+		card:  card,
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeUpdateCard(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var card loyalty.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	card.ID = mux.Vars(r)["id"]
+	req := updateCardReq{
+		token: decodeToken(r),
+		card:  card,
+	}
+	return req, nil
+}
+
+func decodeBalance(_ context.Context, r *http.Request) (interface{}, error) {
+	req := balanceReq{
+		token:    decodeToken(r),
+		vendor:   r.URL.Query().Get(vendorKey),
+		customer: mux.Vars(r)[customerKey],
+	}
+	return req, nil
+}
+
+func decodeHistory(_ context.Context, r *http.Request) (interface{}, error) {
+	req := historyReq{
+		token:    decodeToken(r),
+		limit:    maxLimitSize,
+		vendor:   r.URL.Query().Get(vendorKey),
+		customer: r.URL.Query().Get(customerKey),
+		card:     r.URL.Query().Get(cardKey),
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeAdjust(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := adjustReq{
+		token: decodeToken(r),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, loyalty.ErrInsufficientBalance):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, loyalty.ErrRedemption):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package loyalty
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var _ orders.OrderService = (*redemptionMiddleware)(nil)
+
+type redemptionMiddleware struct {
+	svc     orders.OrderService
+	loyalty Service
+}
+
+// RedemptionMiddleware spends the customer's points and full stamp cards on
+// orders as they are created. The points to spend are read from the
+// orders.RedeemPointsKey metadata and the customer from orders.CustomerKey.
+// What was spent is given back if the order is not placed.
+func RedemptionMiddleware(svc orders.OrderService, loyalty Service) orders.OrderService {
+	return &redemptionMiddleware{
+		svc:     svc,
+		loyalty: loyalty,
+	}
+}
+
+func (rm *redemptionMiddleware) CreateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	points, err := redeemPoints(order.Metadata)
+	if err != nil {
+		return "", err
+	}
+	if order.Price == 0 {
+		order.Price = order.ItemsTotal()
+	}
+	adjustments, err := rm.loyalty.Redeem(ctx, token, order, points)
+	if err != nil {
+		return "", err
+	}
+	order.Adjustments = append(order.Adjustments, adjustments...)
+
+	id, err := rm.svc.CreateOrder(ctx, token, order)
+	if err != nil && id == "" {
+		if rerr := rm.loyalty.Refund(ctx, token, adjustments); rerr != nil {
+			return "", errors.Wrap(err, rerr)
+		}
+		return "", err
+	}
+	return id, err
+}
+
+func (rm *redemptionMiddleware) ViewOrder(ctx context.Context, token, id string) (orders.Order, error) {
+	return rm.svc.ViewOrder(ctx, token, id)
+}
+
+func (rm *redemptionMiddleware) ListOrders(ctx context.Context, token string, page orders.PageMetadata) (orders.OrdersPage, error) {
+	return rm.svc.ListOrders(ctx, token, page)
+}
+
+func (rm *redemptionMiddleware) UpdateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	return rm.svc.UpdateOrder(ctx, token, order)
+}
+
+func (rm *redemptionMiddleware) DeleteOrder(ctx context.Context, token, id string) error {
+	return rm.svc.DeleteOrder(ctx, token, id)
+}
+
+func (rm *redemptionMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	return rm.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
+
+// redeemPoints reads the points to redeem, sent as a number by the HTTP API
+// and as text by the chat channels.
+func redeemPoints(md orders.Metadata) (uint64, error) {
+	switch points := md[orders.RedeemPointsKey].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		if points < 0 {
+			return 0, errors.ErrMalformedEntity
+		}
+		return uint64(points), nil
+	case string:
+		if points == "" {
+			return 0, nil
+		}
+		value, err := strconv.ParseUint(points, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return value, nil
+	default:
+		return 0, errors.ErrMalformedEntity
+	}
+}
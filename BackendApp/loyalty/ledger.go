@@ -0,0 +1,117 @@
+package loyalty
+
+import (
+	"sort"
+	"time"
+)
+
+// lot is a credit of points and what is left of it. Debits spend the oldest
+// lots first so points are spent before they can expire.
+type lot struct {
+	entry Entry
+	left  int64
+}
+
+// lots replays the points entries of a ledger and returns the lots that
+// still have points left, oldest first.
+func lots(entries []Entry) []*lot {
+	sorted := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Card == "" {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var open []*lot
+	byID := make(map[string]*lot)
+	for _, entry := range sorted {
+		if entry.Amount > 0 {
+			l := &lot{entry: entry, left: entry.Amount}
+			open = append(open, l)
+			byID[entry.ID] = l
+			continue
+		}
+		debit := -entry.Amount
+		// Expired points come off the lot that expired.
+		if l, ok := byID[entry.Reference]; ok && entry.Type == Expire {
+			spent := min(debit, l.left)
+			l.left -= spent
+			debit -= spent
+		}
+		for _, l := range open {
+			if debit == 0 {
+				break
+			}
+			spent := min(debit, l.left)
+			l.left -= spent
+			debit -= spent
+		}
+	}
+
+	var left []*lot
+	for _, l := range open {
+		if l.left > 0 {
+			left = append(left, l)
+		}
+	}
+	return left
+}
+
+// expired returns the entries that expire the points left of the lots that
+// expired by now.
+func expired(entries []Entry, now time.Time) []Entry {
+	var expiries []Entry
+	for _, l := range lots(entries) {
+		if l.entry.ExpiresAt.IsZero() || l.entry.ExpiresAt.After(now) {
+			continue
+		}
+		expiries = append(expiries, Entry{
+			Vendor:    l.entry.Vendor,
+			Customer:  l.entry.Customer,
+			Type:      Expire,
+			Amount:    -l.left,
+			Reference: l.entry.ID,
+			CreatedAt: now,
+		})
+	}
+	return expiries
+}
+
+// expiring returns the points of the lots that expire next and when they do.
+func expiring(entries []Entry) (int64, time.Time) {
+	var points int64
+	var at time.Time
+	for _, l := range lots(entries) {
+		expiresAt := l.entry.ExpiresAt
+		switch {
+		case expiresAt.IsZero():
+		case at.IsZero() || expiresAt.Before(at):
+			points, at = l.left, expiresAt
+		case expiresAt.Equal(at):
+			points += l.left
+		}
+	}
+	return points, at
+}
+
+// sum returns the balance of the entries made on card, points if card is
+// empty.
+func sum(entries []Entry, card string) int64 {
+	var total int64
+	for _, entry := range entries {
+		if entry.Card == card {
+			total += entry.Amount
+		}
+	}
+	return total
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
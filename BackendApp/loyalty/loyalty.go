@@ -0,0 +1,228 @@
+// Package loyalty rewards repeat customers with points earned on paid orders
+// and stamp cards such as "10th tea free". Every change to a balance is an
+// entry appended to a ledger, balances are the sum of the entries.
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var (
+	// ErrInsufficientBalance indicates a debit larger than the balance left.
+	ErrInsufficientBalance = errors.New("insufficient loyalty balance")
+
+	// ErrRedemption indicates points that cannot be redeemed on the order.
+	ErrRedemption = errors.New("points cannot be redeemed")
+)
+
+// Type describes why a ledger entry was made.
+type Type string
+
+// Types of ledger entries.
+const (
+	Earn    Type = "earn"    // Earned on a paid order.
+	Redeem  Type = "redeem"  // Spent as a discount at checkout.
+	Expire  Type = "expire"  // Points that were not spent in time.
+	Adjust  Type = "adjust"  // Added or taken off by an admin.
+	Reverse Type = "reverse" // Given back after a checkout failed.
+)
+
+// Program is how a vendor's customers earn and spend points.
+type Program struct {
+	Vendor     string    `json:"vendor,omitempty"`
+	Spend      uint64    `json:"spend"`                // The amount to spend to earn Points i.e. 100.
+	Points     uint64    `json:"points"`               // The points earned for every Spend.
+	Value      uint64    `json:"value"`                // What a point is worth at checkout.
+	MinRedeem  uint64    `json:"min_redeem,omitempty"` // The fewest points that can be redeemed at once.
+	ExpiryDays uint64    `json:"expiry_days"`          // How many days points last after they are earned, forever if zero.
+	Active     bool      `json:"active"`               // Whether points are earned and redeemed.
+	UpdatedAt  time.Time `json:"updated_at,omitempty"` // When the program was updated.
+}
+
+// Validate returns an error if the program representation is invalid.
+func (p Program) Validate() error {
+	if p.Vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	if p.Active && (p.Spend == 0 || p.Points == 0 || p.Value == 0) {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Earned returns the points earned by spending amount.
+func (p Program) Earned(amount uint64) uint64 {
+	if !p.Active || p.Spend == 0 {
+		return 0
+	}
+	return amount / p.Spend * p.Points
+}
+
+// Card is a stamp card. Customers get a stamp for every unit of the card's
+// items on a paid order, and one unit free once the card is full.
+type Card struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Name      string    `json:"name,omitempty"`       // The name of the card i.e. "10th tea free".
+	Items     []string  `json:"items,omitempty"`      // The menu items that earn stamps and can be had free.
+	Required  uint64    `json:"required"`             // The stamps that fill the card.
+	Active    bool      `json:"active"`               // Whether stamps are earned and redeemed.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the card was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the card was created in the system.
+}
+
+// Validate returns an error if the card representation is invalid.
+func (c Card) Validate() error {
+	if c.Vendor == "" || c.Name == "" || len(c.Items) == 0 || c.Required == 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Entry is a change to the points or the stamps of a customer.
+type Entry struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Customer  string    `json:"customer,omitempty"`
+	Card      string    `json:"card,omitempty"`       // The stamp card the entry counts stamps on, points if empty.
+	Type      Type      `json:"type,omitempty"`       // Why the entry was made.
+	Amount    int64     `json:"amount"`               // The points or stamps, negative for debits.
+	OrderID   string    `json:"order_id,omitempty"`   // The order the entry was made for.
+	Reference string    `json:"reference,omitempty"`  // The entry this one expires or reverses.
+	Reason    string    `json:"reason,omitempty"`     // Why an admin adjusted the balance.
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // When earned points expire.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the entry was made.
+}
+
+// CardBalance is the progress of a customer on a stamp card.
+type CardBalance struct {
+	Card     string `json:"card"`
+	Name     string `json:"name"`
+	Stamps   int64  `json:"stamps"`
+	Required uint64 `json:"required"`
+}
+
+// Balance is what a customer has to spend with a vendor.
+type Balance struct {
+	Vendor    string        `json:"vendor"`
+	Customer  string        `json:"customer"`
+	Points    int64         `json:"points"`
+	Value     uint64        `json:"value"`                // What the points are worth at checkout.
+	Expiring  int64         `json:"expiring,omitempty"`   // The points that expire next.
+	ExpiresAt time.Time     `json:"expires_at,omitempty"` // When the points expiring next expire.
+	Cards     []CardBalance `json:"cards,omitempty"`
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Vendor   string
+	Customer string
+	Card     string
+}
+
+// EntriesPage contains a page of ledger entries.
+type EntriesPage struct {
+	PageMetadata
+	Entries []Entry
+}
+
+// Service specifies the loyalty API.
+type Service interface {
+	orders.Hook
+
+	// SaveProgram creates or replaces the vendor's points program.
+	SaveProgram(ctx context.Context, token string, program Program) error
+
+	// ViewProgram retrieves the points program of a vendor.
+	ViewProgram(ctx context.Context, token, vendor string) (Program, error)
+
+	// CreateCard adds a stamp card.
+	CreateCard(ctx context.Context, token string, card Card) (string, error)
+
+	// ViewCard retrieves a stamp card by its unique identifier ID.
+	ViewCard(ctx context.Context, token, id string) (Card, error)
+
+	// ListCards retrieves the stamp cards of a vendor.
+	ListCards(ctx context.Context, token, vendor string) ([]Card, error)
+
+	// UpdateCard updates a stamp card. Stamps already collected are kept.
+	UpdateCard(ctx context.Context, token string, card Card) (string, error)
+
+	// RemoveCard removes a stamp card.
+	RemoveCard(ctx context.Context, token, id string) error
+
+	// Balance returns the points and stamps of a customer.
+	Balance(ctx context.Context, token, vendor, customer string) (Balance, error)
+
+	// History retrieves the ledger entries for a given pageMetadata.
+	History(ctx context.Context, token string, pm PageMetadata) (EntriesPage, error)
+
+	// Adjust credits or debits a customer's balance. A reason is required.
+	Adjust(ctx context.Context, token string, entry Entry) (string, error)
+
+	// Redeem spends points and full stamp cards on the order as
+	// discounts. The discounts returned must be refunded if the order is
+	// not placed.
+	Redeem(ctx context.Context, token string, order orders.Order, points uint64) ([]orders.Adjustment, error)
+
+	// Refund gives back what was spent on discounts of an order that was
+	// not placed.
+	Refund(ctx context.Context, token string, adjustments []orders.Adjustment) error
+}
+
+// ProgramRepository specifies a points program persistence API.
+type ProgramRepository interface {
+	// Save creates or replaces the program of the vendor.
+	Save(ctx context.Context, program Program) error
+
+	// Retrieve retrieves the program of a vendor.
+	Retrieve(ctx context.Context, vendor string) (Program, error)
+}
+
+// CardRepository specifies a stamp card persistence API.
+type CardRepository interface {
+	// Save persists the card.
+	Save(ctx context.Context, card Card) (string, error)
+
+	// RetrieveByID retrieves a card by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Card, error)
+
+	// RetrieveAll retrieves the cards of a vendor.
+	RetrieveAll(ctx context.Context, vendor string) ([]Card, error)
+
+	// Update updates the card.
+	Update(ctx context.Context, card Card) (string, error)
+
+	// Remove removes the card.
+	Remove(ctx context.Context, id string) error
+}
+
+// LedgerRepository specifies an append-only ledger persistence API.
+type LedgerRepository interface {
+	// Append appends the entries. An order earns, and a lot of points
+	// expires or a redemption is reversed, only once so such entries that
+	// were already appended are skipped.
+	Append(ctx context.Context, entries ...Entry) error
+
+	// Debit appends the debit entry unless it would take the balance it
+	// is made on below zero, in which case ErrInsufficientBalance is
+	// returned. Debits of the same customer are made one at a time.
+	Debit(ctx context.Context, entry Entry) error
+
+	// RetrieveByID retrieves a ledger entry by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Entry, error)
+
+	// RetrieveLedger retrieves every entry of a customer, oldest first.
+	RetrieveLedger(ctx context.Context, vendor, customer string) ([]Entry, error)
+
+	// RetrieveAll retrieves the entries for a given pageMetadata, newest
+	// first.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (EntriesPage, error)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ loyalty.CardRepository = (*cardsRepo)(nil)
+
+type cardsRepo struct {
+	db *sqlx.DB
+}
+
+// NewCardsRepo instantiates a PostgreSQL
+// implementation of stamp cards repository.
+func NewCardsRepo(db *sqlx.DB) loyalty.CardRepository {
+	return &cardsRepo{
+		db: db,
+	}
+}
+
+func (repo cardsRepo) Save(ctx context.Context, card loyalty.Card) (string, error) {
+	q := `INSERT INTO stamp_cards (id, vendor, name, items, required, active, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :items, :required, :active, :created_at, :updated_at) RETURNING id`
+
+	dbc, err := toDBCard(card)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, dbc)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo cardsRepo) RetrieveByID(ctx context.Context, id string) (loyalty.Card, error) {
+	q := `SELECT id, vendor, name, items, required, active, created_at, updated_at FROM stamp_cards WHERE id = $1`
+
+	dbc := dbCard{}
+	if err := pgutil.DB(ctx, repo.db).QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return loyalty.Card{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return loyalty.Card{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toCard(dbc)
+}
+
+func (repo cardsRepo) RetrieveAll(ctx context.Context, vendor string) ([]loyalty.Card, error) {
+	q := `SELECT id, vendor, name, items, required, active, created_at, updated_at FROM stamp_cards
+		  WHERE vendor = :vendor ORDER BY created_at`
+
+	rows, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, map[string]interface{}{"vendor": vendor})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var cards []loyalty.Card
+	for rows.Next() {
+		dbc := dbCard{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		card, err := toCard(dbc)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func (repo cardsRepo) Update(ctx context.Context, card loyalty.Card) (string, error) {
+	q := `UPDATE stamp_cards SET name = :name, items = :items, required = :required, active = :active, updated_at = :updated_at
+		  WHERE id = :id RETURNING id`
+
+	dbc, err := toDBCard(card)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, dbc)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo cardsRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM stamp_cards WHERE id = :id`
+
+	if _, err := sqlx.NamedExecContext(ctx, pgutil.DB(ctx, repo.db), q, dbCard{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+type dbCard struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Name      string    `db:"name"`
+	Items     []byte    `db:"items"`
+	Required  uint64    `db:"required"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBCard(card loyalty.Card) (dbCard, error) {
+	items := []byte("[]")
+	if len(card.Items) > 0 {
+		b, err := json.Marshal(card.Items)
+		if err != nil {
+			return dbCard{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		items = b
+	}
+	return dbCard{
+		ID:        card.ID,
+		Vendor:    card.Vendor,
+		Name:      card.Name,
+		Items:     items,
+		Required:  card.Required,
+		Active:    card.Active,
+		CreatedAt: card.CreatedAt,
+		UpdatedAt: card.UpdatedAt,
+	}, nil
+}
+
+func toCard(dbc dbCard) (loyalty.Card, error) {
+	var items []string
+	if dbc.Items != nil {
+		if err := json.Unmarshal(dbc.Items, &items); err != nil {
+			return loyalty.Card{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return loyalty.Card{
+		ID:        dbc.ID,
+		Vendor:    dbc.Vendor,
+		Name:      dbc.Name,
+		Items:     items,
+		Required:  dbc.Required,
+		Active:    dbc.Active,
+		CreatedAt: dbc.CreatedAt,
+		UpdatedAt: dbc.UpdatedAt,
+	}, nil
+}
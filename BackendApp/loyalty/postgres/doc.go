@@ -0,0 +1,3 @@
+// Package postgres contains repository implementations using postgres as the
+// underlying database.
+package postgres
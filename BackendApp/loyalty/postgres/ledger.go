@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/jmoiron/sqlx"
+)
+
+const entryColumns = `id, vendor, customer, card, type, amount, order_id, reference, reason, expires_at, created_at`
+
+// insertEntry skips entries already appended, the unique indexes of the
+// ledger only allow an order to earn and an entry to be expired or
+// reversed once.
+const insertEntry = `INSERT INTO loyalty_ledger (` + entryColumns + `)
+	VALUES (:id, :vendor, :customer, :card, :type, :amount, :order_id, :reference, :reason, :expires_at, :created_at)
+	ON CONFLICT DO NOTHING`
+
+var _ loyalty.LedgerRepository = (*ledgerRepo)(nil)
+
+type ledgerRepo struct {
+	db *sqlx.DB
+}
+
+// NewLedgerRepo instantiates a PostgreSQL
+// implementation of loyalty ledger repository.
+func NewLedgerRepo(db *sqlx.DB) loyalty.LedgerRepository {
+	return &ledgerRepo{
+		db: db,
+	}
+}
+
+func (repo ledgerRepo) Append(ctx context.Context, entries ...loyalty.Entry) error {
+	return pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		for _, entry := range entries {
+			if _, err := tx.NamedExecContext(ctx, insertEntry, toDBEntry(entry)); err != nil {
+				return handleError(err, errors.ErrCreateEntity)
+			}
+		}
+		return nil
+	})
+}
+
+func (repo ledgerRepo) Debit(ctx context.Context, entry loyalty.Entry) error {
+	return pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		// The lock is held until the debit is committed so two debits can
+		// not both spend the same balance.
+		if err := pgutil.Lock(ctx, tx, "loyalty_ledger:"+entry.Vendor+"/"+entry.Customer); err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		var balance int64
+		bq := `SELECT COALESCE(SUM(amount), 0) FROM loyalty_ledger WHERE vendor = $1 AND customer = $2 AND card = $3`
+		if err := tx.QueryRowxContext(ctx, bq, entry.Vendor, entry.Customer, entry.Card).Scan(&balance); err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		if balance+entry.Amount < 0 {
+			return loyalty.ErrInsufficientBalance
+		}
+		if _, err := tx.NamedExecContext(ctx, insertEntry, toDBEntry(entry)); err != nil {
+			return handleError(err, errors.ErrCreateEntity)
+		}
+		return nil
+	})
+}
+
+func (repo ledgerRepo) RetrieveByID(ctx context.Context, id string) (loyalty.Entry, error) {
+	q := `SELECT ` + entryColumns + ` FROM loyalty_ledger WHERE id = $1`
+
+	dbe := dbEntry{}
+	if err := pgutil.DB(ctx, repo.db).QueryRowxContext(ctx, q, id).StructScan(&dbe); err != nil {
+		if err == sql.ErrNoRows {
+			return loyalty.Entry{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return loyalty.Entry{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toEntry(dbe), nil
+}
+
+func (repo ledgerRepo) RetrieveLedger(ctx context.Context, vendor, customer string) ([]loyalty.Entry, error) {
+	q := `SELECT ` + entryColumns + ` FROM loyalty_ledger WHERE vendor = :vendor AND customer = :customer ORDER BY created_at, id`
+
+	params := map[string]interface{}{
+		"vendor":   vendor,
+		"customer": customer,
+	}
+	rows, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	return scanEntries(rows)
+}
+
+func (repo ledgerRepo) RetrieveAll(ctx context.Context, pm loyalty.PageMetadata) (loyalty.EntriesPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+		"vendor":   pm.Vendor,
+		"customer": pm.Customer,
+		"card":     pm.Card,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Customer != "" {
+		query = append(query, "customer = :customer")
+	}
+	if pm.Card != "" {
+		query = append(query, "card = :card")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+entryColumns+` FROM loyalty_ledger %s ORDER BY created_at DESC, id DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, params)
+	if err != nil {
+		return loyalty.EntriesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return loyalty.EntriesPage{}, err
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM loyalty_ledger %s;`, emq)
+	total, err := total(ctx, pgutil.DB(ctx, repo.db), cq, params)
+	if err != nil {
+		return loyalty.EntriesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := loyalty.EntriesPage{
+		Entries: entries,
+		PageMetadata: loyalty.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func scanEntries(rows *sqlx.Rows) ([]loyalty.Entry, error) {
+	var entries []loyalty.Entry
+	for rows.Next() {
+		dbe := dbEntry{}
+		if err := rows.StructScan(&dbe); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		entries = append(entries, toEntry(dbe))
+	}
+	return entries, nil
+}
+
+func total(ctx context.Context, db sqlx.ExtContext, query string, params interface{}) (uint64, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbEntry struct {
+	ID        string       `db:"id"`
+	Vendor    string       `db:"vendor"`
+	Customer  string       `db:"customer"`
+	Card      string       `db:"card"`
+	Type      string       `db:"type"`
+	Amount    int64        `db:"amount"`
+	OrderID   string       `db:"order_id"`
+	Reference string       `db:"reference"`
+	Reason    string       `db:"reason"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+func toDBEntry(entry loyalty.Entry) dbEntry {
+	return dbEntry{
+		ID:        entry.ID,
+		Vendor:    entry.Vendor,
+		Customer:  entry.Customer,
+		Card:      entry.Card,
+		Type:      string(entry.Type),
+		Amount:    entry.Amount,
+		OrderID:   entry.OrderID,
+		Reference: entry.Reference,
+		Reason:    entry.Reason,
+		ExpiresAt: sql.NullTime{Time: entry.ExpiresAt, Valid: !entry.ExpiresAt.IsZero()},
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func toEntry(dbe dbEntry) loyalty.Entry {
+	entry := loyalty.Entry{
+		ID:        dbe.ID,
+		Vendor:    dbe.Vendor,
+		Customer:  dbe.Customer,
+		Card:      dbe.Card,
+		Type:      loyalty.Type(dbe.Type),
+		Amount:    dbe.Amount,
+		OrderID:   dbe.OrderID,
+		Reference: dbe.Reference,
+		Reason:    dbe.Reason,
+		CreatedAt: dbe.CreatedAt,
+	}
+	if dbe.ExpiresAt.Valid {
+		entry.ExpiresAt = dbe.ExpiresAt.Time
+	}
+	return entry
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ loyalty.ProgramRepository = (*programsRepo)(nil)
+
+type programsRepo struct {
+	db *sqlx.DB
+}
+
+// NewProgramsRepo instantiates a PostgreSQL
+// implementation of loyalty programs repository.
+func NewProgramsRepo(db *sqlx.DB) loyalty.ProgramRepository {
+	return &programsRepo{
+		db: db,
+	}
+}
+
+func (repo programsRepo) Save(ctx context.Context, program loyalty.Program) error {
+	q := `INSERT INTO loyalty_programs (vendor, spend, points, value, min_redeem, expiry_days, active, updated_at)
+		  VALUES (:vendor, :spend, :points, :value, :min_redeem, :expiry_days, :active, :updated_at)
+		  ON CONFLICT (vendor) DO UPDATE SET spend = :spend, points = :points, value = :value, min_redeem = :min_redeem,
+		  expiry_days = :expiry_days, active = :active, updated_at = :updated_at`
+
+	dbp := dbProgram{
+		Vendor:     program.Vendor,
+		Spend:      program.Spend,
+		Points:     program.Points,
+		Value:      program.Value,
+		MinRedeem:  program.MinRedeem,
+		ExpiryDays: program.ExpiryDays,
+		Active:     program.Active,
+		UpdatedAt:  program.UpdatedAt,
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, dbp); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo programsRepo) Retrieve(ctx context.Context, vendor string) (loyalty.Program, error) {
+	q := `SELECT vendor, spend, points, value, min_redeem, expiry_days, active, updated_at FROM loyalty_programs WHERE vendor = $1`
+
+	dbp := dbProgram{}
+	if err := repo.db.QueryRowxContext(ctx, q, vendor).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return loyalty.Program{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return loyalty.Program{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return loyalty.Program{
+		Vendor:     dbp.Vendor,
+		Spend:      dbp.Spend,
+		Points:     dbp.Points,
+		Value:      dbp.Value,
+		MinRedeem:  dbp.MinRedeem,
+		ExpiryDays: dbp.ExpiryDays,
+		Active:     dbp.Active,
+		UpdatedAt:  dbp.UpdatedAt,
+	}, nil
+}
+
+type dbProgram struct {
+	Vendor     string    `db:"vendor"`
+	Spend      uint64    `db:"spend"`
+	Points     uint64    `db:"points"`
+	Value      uint64    `db:"value"`
+	MinRedeem  uint64    `db:"min_redeem"`
+	ExpiryDays uint64    `db:"expiry_days"`
+	Active     bool      `db:"active"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
@@ -0,0 +1,462 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+const day = 24 * time.Hour
+
+var _ Service = (*loyaltyService)(nil)
+
+type loyaltyService struct {
+	programs ProgramRepository
+	cards    CardRepository
+	ledger   LedgerRepository
+}
+
+// NewService instantiates the loyalty service implementation.
+func NewService(programs ProgramRepository, cards CardRepository, ledger LedgerRepository) Service {
+	return &loyaltyService{
+		programs: programs,
+		cards:    cards,
+		ledger:   ledger,
+	}
+}
+
+func (svc loyaltyService) SaveProgram(ctx context.Context, token string, program Program) error {
+	if err := program.Validate(); err != nil {
+		return err
+	}
+	program.UpdatedAt = time.Now()
+	return svc.programs.Save(ctx, program)
+}
+
+func (svc loyaltyService) ViewProgram(ctx context.Context, token, vendor string) (Program, error) {
+	return svc.programs.Retrieve(ctx, vendor)
+}
+
+func (svc loyaltyService) CreateCard(ctx context.Context, token string, card Card) (string, error) {
+	if err := card.Validate(); err != nil {
+		return "", err
+	}
+	card.ID = ulid.Make().String()
+	card.CreatedAt = time.Now()
+	card.UpdatedAt = time.Now()
+	return svc.cards.Save(ctx, card)
+}
+
+func (svc loyaltyService) ViewCard(ctx context.Context, token, id string) (Card, error) {
+	return svc.cards.RetrieveByID(ctx, id)
+}
+
+func (svc loyaltyService) ListCards(ctx context.Context, token, vendor string) ([]Card, error) {
+	return svc.cards.RetrieveAll(ctx, vendor)
+}
+
+func (svc loyaltyService) UpdateCard(ctx context.Context, token string, card Card) (string, error) {
+	current, err := svc.cards.RetrieveByID(ctx, card.ID)
+	if err != nil {
+		return "", err
+	}
+	card.Vendor = current.Vendor
+	if err := card.Validate(); err != nil {
+		return "", err
+	}
+	card.CreatedAt = current.CreatedAt
+	card.UpdatedAt = time.Now()
+	return svc.cards.Update(ctx, card)
+}
+
+func (svc loyaltyService) RemoveCard(ctx context.Context, token, id string) error {
+	return svc.cards.Remove(ctx, id)
+}
+
+func (svc loyaltyService) Balance(ctx context.Context, token, vendor, customer string) (Balance, error) {
+	if vendor == "" || customer == "" {
+		return Balance{}, errors.ErrMalformedEntity
+	}
+	entries, err := svc.sync(ctx, vendor, customer)
+	if err != nil {
+		return Balance{}, err
+	}
+	program, err := svc.program(ctx, vendor)
+	if err != nil {
+		return Balance{}, err
+	}
+	cards, err := svc.cards.RetrieveAll(ctx, vendor)
+	if err != nil {
+		return Balance{}, err
+	}
+
+	balance := Balance{
+		Vendor:   vendor,
+		Customer: customer,
+		Points:   sum(entries, ""),
+	}
+	if balance.Points > 0 {
+		balance.Value = uint64(balance.Points) * program.Value
+	}
+	balance.Expiring, balance.ExpiresAt = expiring(entries)
+	for _, card := range cards {
+		stamps := sum(entries, card.ID)
+		if !card.Active && stamps == 0 {
+			continue
+		}
+		balance.Cards = append(balance.Cards, CardBalance{
+			Card:     card.ID,
+			Name:     card.Name,
+			Stamps:   stamps,
+			Required: card.Required,
+		})
+	}
+	return balance, nil
+}
+
+func (svc loyaltyService) History(ctx context.Context, token string, pm PageMetadata) (EntriesPage, error) {
+	if pm.Vendor != "" && pm.Customer != "" {
+		if _, err := svc.sync(ctx, pm.Vendor, pm.Customer); err != nil {
+			return EntriesPage{}, err
+		}
+	}
+	return svc.ledger.RetrieveAll(ctx, pm)
+}
+
+func (svc loyaltyService) Adjust(ctx context.Context, token string, entry Entry) (string, error) {
+	if entry.Vendor == "" || entry.Customer == "" || entry.Reason == "" || entry.Amount == 0 {
+		return "", errors.ErrMalformedEntity
+	}
+	if entry.Card != "" {
+		card, err := svc.cards.RetrieveByID(ctx, entry.Card)
+		if err != nil {
+			return "", err
+		}
+		if card.Vendor != entry.Vendor {
+			return "", errors.ErrMalformedEntity
+		}
+	}
+	entry.ID = ulid.Make().String()
+	entry.Type = Adjust
+	entry.OrderID = ""
+	entry.Reference = ""
+	entry.ExpiresAt = time.Time{}
+	entry.CreatedAt = time.Now()
+
+	if entry.Amount > 0 {
+		if entry.Card == "" {
+			program, err := svc.program(ctx, entry.Vendor)
+			if err != nil {
+				return "", err
+			}
+			entry.ExpiresAt = program.expiry(entry.CreatedAt)
+		}
+		if err := svc.ledger.Append(ctx, entry); err != nil {
+			return "", err
+		}
+		return entry.ID, nil
+	}
+	if entry.Card == "" {
+		if _, err := svc.sync(ctx, entry.Vendor, entry.Customer); err != nil {
+			return "", err
+		}
+	}
+	if err := svc.ledger.Debit(ctx, entry); err != nil {
+		return "", err
+	}
+	return entry.ID, nil
+}
+
+func (svc loyaltyService) Redeem(ctx context.Context, token string, order orders.Order, points uint64) ([]orders.Adjustment, error) {
+	customer, _ := order.Metadata[orders.CustomerKey].(string)
+	if customer == "" {
+		if points > 0 {
+			return nil, ErrRedemption
+		}
+		return nil, nil
+	}
+	gross := order.Price
+	if gross == 0 {
+		gross = order.ItemsTotal()
+	}
+	var net uint64
+	if discount := order.Discount(); discount < gross {
+		net = gross - discount
+	}
+
+	adjustments, err := svc.redeemStamps(ctx, order, customer, net)
+	if err == nil && points > 0 {
+		for _, adj := range adjustments {
+			net -= adj.Amount
+		}
+		var adj orders.Adjustment
+		adj, err = svc.redeemPoints(ctx, order.Vendor, customer, points, net)
+		adjustments = append(adjustments, adj)
+	}
+	if err != nil {
+		if rerr := svc.Refund(ctx, token, adjustments); rerr != nil {
+			return nil, errors.Wrap(err, rerr)
+		}
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (svc loyaltyService) Refund(ctx context.Context, token string, adjustments []orders.Adjustment) error {
+	var reversals []Entry
+	for _, adj := range adjustments {
+		if adj.Loyalty == "" {
+			continue
+		}
+		entry, err := svc.ledger.RetrieveByID(ctx, adj.Loyalty)
+		if err != nil {
+			return err
+		}
+		reversal := Entry{
+			ID:        ulid.Make().String(),
+			Vendor:    entry.Vendor,
+			Customer:  entry.Customer,
+			Card:      entry.Card,
+			Type:      Reverse,
+			Amount:    -entry.Amount,
+			Reference: entry.ID,
+			CreatedAt: time.Now(),
+		}
+		if entry.Card == "" {
+			program, err := svc.program(ctx, entry.Vendor)
+			if err != nil {
+				return err
+			}
+			reversal.ExpiresAt = program.expiry(reversal.CreatedAt)
+		}
+		reversals = append(reversals, reversal)
+	}
+	if len(reversals) == 0 {
+		return nil
+	}
+	return svc.ledger.Append(ctx, reversals...)
+}
+
+// OrderPreparing does nothing, orders earn once they are paid.
+func (svc loyaltyService) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	return nil
+}
+
+// OrderPaid earns the customer of the order points on what was spent and a
+// stamp for every unit of a stamp card's items that was not had free.
+func (svc loyaltyService) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	customer, _ := order.Metadata[orders.CustomerKey].(string)
+	if customer == "" {
+		return nil
+	}
+	now := time.Now()
+	var entries []Entry
+
+	program, err := svc.program(ctx, order.Vendor)
+	if err != nil {
+		return err
+	}
+	if points := program.Earned(order.Price); points > 0 {
+		entries = append(entries, Entry{
+			ID:        ulid.Make().String(),
+			Vendor:    order.Vendor,
+			Customer:  customer,
+			Type:      Earn,
+			Amount:    int64(points),
+			OrderID:   order.ID,
+			ExpiresAt: program.expiry(now),
+			CreatedAt: now,
+		})
+	}
+
+	cards, err := svc.cards.RetrieveAll(ctx, order.Vendor)
+	if err != nil {
+		return err
+	}
+	free := make(map[string]int64)
+	for _, adj := range order.Adjustments {
+		if adj.Loyalty == "" {
+			continue
+		}
+		entry, err := svc.ledger.RetrieveByID(ctx, adj.Loyalty)
+		if err != nil {
+			return err
+		}
+		if entry.Card != "" {
+			free[entry.Card]++
+		}
+	}
+	for _, card := range cards {
+		if !card.Active {
+			continue
+		}
+		stamps := int64(0)
+		for _, item := range order.Items {
+			if card.qualifies(item.ID) {
+				stamps += int64(item.Quantity)
+			}
+		}
+		stamps -= free[card.ID]
+		if stamps <= 0 {
+			continue
+		}
+		entries = append(entries, Entry{
+			ID:        ulid.Make().String(),
+			Vendor:    order.Vendor,
+			Customer:  customer,
+			Card:      card.ID,
+			Type:      Earn,
+			Amount:    stamps,
+			OrderID:   order.ID,
+			CreatedAt: now,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return svc.ledger.Append(ctx, entries...)
+}
+
+// redeemStamps takes the cheapest unit of a full stamp card's items off the
+// order, once for every full card. The discounts granted before an error are
+// returned with it.
+func (svc loyaltyService) redeemStamps(ctx context.Context, order orders.Order, customer string, net uint64) ([]orders.Adjustment, error) {
+	cards, err := svc.cards.RetrieveAll(ctx, order.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	var adjustments []orders.Adjustment
+	for _, card := range cards {
+		if !card.Active || net == 0 {
+			continue
+		}
+		var price uint64
+		for _, item := range order.Items {
+			if card.qualifies(item.ID) && item.Quantity > 0 && (price == 0 || item.Price < price) {
+				price = item.Price
+			}
+		}
+		if price == 0 {
+			continue
+		}
+		if price > net {
+			price = net
+		}
+		entry := Entry{
+			ID:        ulid.Make().String(),
+			Vendor:    order.Vendor,
+			Customer:  customer,
+			Card:      card.ID,
+			Type:      Redeem,
+			Amount:    -int64(card.Required),
+			CreatedAt: time.Now(),
+		}
+		err := svc.ledger.Debit(ctx, entry)
+		if errors.Contains(err, ErrInsufficientBalance) {
+			continue
+		}
+		if err != nil {
+			return adjustments, err
+		}
+		adjustments = append(adjustments, orders.Adjustment{
+			Name:    card.Name,
+			Amount:  price,
+			Loyalty: entry.ID,
+		})
+		net -= price
+	}
+	return adjustments, nil
+}
+
+// redeemPoints spends points on the order, no more than the net price of the
+// order is worth.
+func (svc loyaltyService) redeemPoints(ctx context.Context, vendor, customer string, points, net uint64) (orders.Adjustment, error) {
+	program, err := svc.program(ctx, vendor)
+	if err != nil {
+		return orders.Adjustment{}, err
+	}
+	if !program.Active || program.Value == 0 {
+		return orders.Adjustment{}, ErrRedemption
+	}
+	if most := net / program.Value; points > most {
+		points = most
+	}
+	if points == 0 || points < program.MinRedeem {
+		return orders.Adjustment{}, ErrRedemption
+	}
+	if _, err := svc.sync(ctx, vendor, customer); err != nil {
+		return orders.Adjustment{}, err
+	}
+	entry := Entry{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		Customer:  customer,
+		Type:      Redeem,
+		Amount:    -int64(points),
+		CreatedAt: time.Now(),
+	}
+	if err := svc.ledger.Debit(ctx, entry); err != nil {
+		return orders.Adjustment{}, err
+	}
+	return orders.Adjustment{
+		Name:    "Loyalty points",
+		Amount:  points * program.Value,
+		Loyalty: entry.ID,
+	}, nil
+}
+
+// sync appends the expiry of points that expired since the ledger of the
+// customer was last read and returns the ledger.
+func (svc loyaltyService) sync(ctx context.Context, vendor, customer string) ([]Entry, error) {
+	entries, err := svc.ledger.RetrieveLedger(ctx, vendor, customer)
+	if err != nil {
+		return nil, err
+	}
+	expiries := expired(entries, time.Now())
+	if len(expiries) == 0 {
+		return entries, nil
+	}
+	for _, expiry := range expiries {
+		expiry.ID = ulid.Make().String()
+		// A debit made since the ledger was read may have spent the
+		// points, those are left for the next read to expire.
+		err := svc.ledger.Debit(ctx, expiry)
+		if err != nil && !errors.Contains(err, ErrInsufficientBalance) {
+			return nil, err
+		}
+	}
+	return svc.ledger.RetrieveLedger(ctx, vendor, customer)
+}
+
+// program returns the points program of the vendor, an inactive one if the
+// vendor has none.
+func (svc loyaltyService) program(ctx context.Context, vendor string) (Program, error) {
+	program, err := svc.programs.Retrieve(ctx, vendor)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return Program{Vendor: vendor}, nil
+	}
+	return program, err
+}
+
+// expiry returns when points earned at t expire, never if the zero time.
+func (p Program) expiry(t time.Time) time.Time {
+	if p.ExpiryDays == 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(p.ExpiryDays) * day)
+}
+
+func (c Card) qualifies(item string) bool {
+	if item == "" {
+		return false
+	}
+	for _, id := range c.Items {
+		if id == item {
+			return true
+		}
+	}
+	return false
+}
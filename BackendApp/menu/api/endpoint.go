@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func createItemEndpoint(svc menu.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createItemReq)
+		if err := req.validate(); err != nil {
+			return createItemRes{}, err
+		}
+		id, err := svc.CreateItem(ctx, req.token, req.item)
+		if err != nil {
+			return createItemRes{}, err
+		}
+		return createItemRes{ID: id, created: true}, nil
+	}
+}
+
+func viewItemEndpoint(svc menu.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		item, err := svc.ViewItem(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return toViewItemRes(item), nil
+	}
+}
+
+func listItemsEndpoint(svc menu.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listItemsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := menu.PageMetadata{
+			Offset:        req.offset,
+			Limit:         req.limit,
+			Vendor:        req.vendor,
+			Name:          req.name,
+			Category:      req.category,
+			OnlyAvailable: req.available,
+		}
+		page, err := svc.ListItems(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := itemsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Items: []viewItemRes{},
+		}
+		for _, item := range page.Items {
+			res.Items = append(res.Items, toViewItemRes(item))
+		}
+		return res, nil
+	}
+}
+
+func updateItemEndpoint(svc menu.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		item := menu.Item{
+			ID:       req.id,
+			Name:     req.Name,
+			Category: req.Category,
+			Price:    req.Price,
+			Metadata: req.Metadata,
+		}
+		switch req.Available {
+		case nil:
+			current, err := svc.ViewItem(ctx, req.token, req.id)
+			if err != nil {
+				return nil, err
+			}
+			item.Available = current.Available
+		default:
+			item.Available = *req.Available
+		}
+		id, err := svc.UpdateItem(ctx, req.token, item)
+		if err != nil {
+			return nil, err
+		}
+		return updateItemRes{ID: id, updated: true}, nil
+	}
+}
+
+func removeItemEndpoint(svc menu.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(removeItemReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveItem(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeItemRes{}, nil
+	}
+}
+
+func toViewItemRes(item menu.Item) viewItemRes {
+	return viewItemRes{
+		ID:        item.ID,
+		Vendor:    item.Vendor,
+		Name:      item.Name,
+		Category:  item.Category,
+		Price:     item.Price,
+		Available: item.Available,
+		Metadata:  item.Metadata,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/go-kit/log"
+)
+
+var _ menu.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    menu.Service
+}
+
+// LoggingMiddleware adds logging facilities to the menu service.
+func LoggingMiddleware(svc menu.Service, logger log.Logger) menu.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateItem(ctx context.Context, token string, item menu.Item) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_menu_item",
+			"token", token,
+			"vendor", item.Vendor,
+			"name", item.Name,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateItem(ctx, token, item)
+}
+
+func (lm *loggingMiddleware) ViewItem(ctx context.Context, token, id string) (item menu.Item, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_menu_item",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewItem(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListItems(ctx context.Context, token string, pm menu.PageMetadata) (page menu.ItemsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_menu_items",
+			"token", token,
+			"vendor", pm.Vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListItems(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateItem(ctx context.Context, token string, item menu.Item) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_menu_item",
+			"token", token,
+			"id", item.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateItem(ctx, token, item)
+}
+
+func (lm *loggingMiddleware) RemoveItem(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_menu_item",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveItem(ctx, token, id)
+}
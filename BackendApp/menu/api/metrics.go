@@ -0,0 +1,73 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ menu.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     menu.Service
+}
+
+// MetricsMiddleware instruments the menu service by tracking request count and latency.
+func MetricsMiddleware(svc menu.Service, counter metrics.Counter, latency metrics.Histogram) menu.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateItem(ctx context.Context, token string, item menu.Item) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_menu_item").Add(1)
+		ms.latency.With("method", "create_menu_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateItem(ctx, token, item)
+}
+
+func (ms *metricsMiddleware) ViewItem(ctx context.Context, token, id string) (menu.Item, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_menu_item").Add(1)
+		ms.latency.With("method", "view_menu_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewItem(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListItems(ctx context.Context, token string, pm menu.PageMetadata) (menu.ItemsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_menu_items").Add(1)
+		ms.latency.With("method", "list_menu_items").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListItems(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateItem(ctx context.Context, token string, item menu.Item) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_menu_item").Add(1)
+		ms.latency.With("method", "update_menu_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateItem(ctx, token, item)
+}
+
+func (ms *metricsMiddleware) RemoveItem(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_menu_item").Add(1)
+		ms.latency.With("method", "remove_menu_item").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveItem(ctx, token, id)
+}
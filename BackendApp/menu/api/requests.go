@@ -0,0 +1,92 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+)
+
+const (
+	maxLimitSize = 100
+)
+
+type createItemReq struct {
+	item  menu.Item
+	token string
+}
+
+func (req createItemReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.item.Validate()
+}
+
+type viewItemReq struct {
+	token string
+	id    string
+}
+
+func (req viewItemReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listItemsReq struct {
+	token     string
+	vendor    string
+	name      string
+	category  string
+	available bool
+	offset    uint64
+	limit     uint64
+}
+
+func (req listItemsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type updateItemReq struct {
+	token     string
+	id        string
+	Name      string        `json:"name,omitempty"`
+	Category  string        `json:"category,omitempty"`
+	Price     uint64        `json:"price,omitempty"`
+	Available *bool         `json:"available,omitempty"`
+	Metadata  menu.Metadata `json:"metadata,omitempty"`
+}
+
+func (req updateItemReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type removeItemReq struct {
+	token string
+	id    string
+}
+
+func (req removeItemReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createItemRes)(nil)
+	_ Response = (*viewItemRes)(nil)
+	_ Response = (*itemsPageRes)(nil)
+	_ Response = (*updateItemRes)(nil)
+	_ Response = (*removeItemRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type createItemRes struct {
+	ID      string
+	created bool
+}
+
+func (res createItemRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res createItemRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/menu/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res createItemRes) Empty() bool {
+	return true
+}
+
+type viewItemRes struct {
+	ID        string        `json:"id"`
+	Vendor    string        `json:"vendor"`
+	Name      string        `json:"name"`
+	Category  string        `json:"category,omitempty"`
+	Price     uint64        `json:"price"`
+	Available bool          `json:"available"`
+	Metadata  menu.Metadata `json:"metadata,omitempty"`
+	UpdatedAt time.Time     `json:"updated_at,omitempty"`
+	CreatedAt time.Time     `json:"created_at,omitempty"`
+}
+
+func (res viewItemRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewItemRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewItemRes) Empty() bool {
+	return false
+}
+
+type itemsPageRes struct {
+	pageRes
+	Items []viewItemRes `json:"items"`
+}
+
+func (res itemsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res itemsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res itemsPageRes) Empty() bool {
+	return false
+}
+
+type updateItemRes struct {
+	ID      string
+	updated bool
+}
+
+func (res updateItemRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateItemRes) Headers() map[string]string {
+	if res.updated {
+		return map[string]string{
+			"Location": fmt.Sprintf("/menu/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res updateItemRes) Empty() bool {
+	return true
+}
+
+type removeItemRes struct{}
+
+func (res removeItemRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeItemRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeItemRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType  = "application/json"
+	offsetKey    = "offset"
+	limitKey     = "limit"
+	vendorKey    = "vendor"
+	nameKey      = "name"
+	categoryKey  = "category"
+	availableKey = "available"
+)
+
+// MakeMenuHandler returns a HTTP handler for menu API endpoints.
+func MakeMenuHandler(svc menu.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/menu").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_menu_item")(createItemEndpoint(svc)),
+		decodeCreateItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/menu/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_menu_item")(viewItemEndpoint(svc)),
+		decodeViewItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/menu").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_menu_items")(listItemsEndpoint(svc)),
+		decodeListItems,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/menu/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_menu_item")(updateItemEndpoint(svc)),
+		decodeUpdateItem,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/menu/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_menu_item")(removeItemEndpoint(svc)),
+		decodeRemoveItem,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCreateItem(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	item := menu.Item{Available: true}
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createItemReq{
+		item:  item,
+		token: decodeToken(r),
+	}
+	return req, nil
+}
+
+func decodeViewItem(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewItemReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeListItems(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listItemsReq{
+		token:    decodeToken(r),
+		limit:    maxLimitSize,
+		vendor:   r.URL.Query().Get(vendorKey),
+		name:     r.URL.Query().Get(nameKey),
+		category: r.URL.Query().Get(categoryKey),
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(availableKey) {
+		req.available, err = strconv.ParseBool(r.URL.Query().Get(availableKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeUpdateItem(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := updateItemReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeRemoveItem(_ context.Context, r *http.Request) (interface{}, error) {
+	req := removeItemReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package menu
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+// Metadata to be used for customized
+// describing of particular menu Item.
+type Metadata map[string]interface{}
+
+// Item this represents a good a vendor sells i.e. chapati.
+type Item struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`     // The name of the vendor selling the item i.e shop.
+	Name      string    `json:"name,omitempty"`       // The name of the item.
+	Category  string    `json:"category,omitempty"`   // The category the item is listed under i.e drinks.
+	Price     uint64    `json:"price,omitempty"`      // The price of a single unit of the item.
+	Available bool      `json:"available"`            // Whether the item can currently be ordered.
+	Metadata  Metadata  `json:"metadata,omitempty"`   // Metadata contains extra information about the item.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the item was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the item was created in the system.
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total         uint64
+	Offset        uint64
+	Limit         uint64
+	Vendor        string
+	Name          string
+	Category      string
+	OnlyAvailable bool
+	Metadata      Metadata
+}
+
+// ItemsPage contains a page of menu items.
+type ItemsPage struct {
+	PageMetadata
+	Items []Item
+}
+
+// Service describes the methods a vendor's menu undergoes.
+type Service interface {
+	// CreateItem adds an item to the menu. Requires a token and the item object.
+	CreateItem(ctx context.Context, token string, item Item) (string, error)
+
+	// ViewItem retrieves an Item by its unique identifier ID.
+	ViewItem(ctx context.Context, token string, id string) (Item, error)
+
+	// ListItems retrieves all items for a given pageMetadata.
+	ListItems(ctx context.Context, token string, pm PageMetadata) (ItemsPage, error)
+
+	// UpdateItem updates the name, category, price, availability and
+	// metadata for a given item by its unique identifier ID.
+	UpdateItem(ctx context.Context, token string, item Item) (string, error)
+
+	// RemoveItem removes the item from the menu.
+	RemoveItem(ctx context.Context, token string, id string) error
+}
+
+// Repository specifies a menu persistence API.
+type Repository interface {
+	// Save persists the Item. A non-nil error is returned to indicate
+	// operation failure.
+	Save(ctx context.Context, item Item) (string, error)
+
+	// RetrieveByID retrieves Item by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Item, error)
+
+	// RetrieveByIDs retrieves the items with the unique identifiers ids,
+	// leaving out the ones that do not exist.
+	RetrieveByIDs(ctx context.Context, ids []string) ([]Item, error)
+
+	// RetrieveAll retrieves all items for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (ItemsPage, error)
+
+	// Update replaces the stored item with the given one.
+	Update(ctx context.Context, item Item) (string, error)
+
+	// Delete deletes the item.
+	Delete(ctx context.Context, id string) error
+}
+
+// Validate returns an error if item representation is invalid.
+func (item Item) Validate() error {
+	if item.Vendor == "" || item.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ menu.Repository = (*menuRepo)(nil)
+
+type menuRepo struct {
+	db *sqlx.DB
+}
+
+// NewMenuRepo instantiates a PostgreSQL
+// implementation of menu repository.
+func NewMenuRepo(db *sqlx.DB) menu.Repository {
+	return &menuRepo{
+		db: db,
+	}
+}
+
+func (repo menuRepo) Save(ctx context.Context, item menu.Item) (string, error) {
+	q := `INSERT INTO menu_items (id, vendor, name, category, price, available, metadata, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :category, :price, :available, :metadata, :created_at, :updated_at) RETURNING id`
+
+	dbi, err := toDBItem(item)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbi)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo menuRepo) RetrieveByID(ctx context.Context, id string) (menu.Item, error) {
+	q := `SELECT id, vendor, name, category, price, available, metadata, created_at, updated_at FROM menu_items WHERE id = $1`
+
+	dbi := dbItem{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbi); err != nil {
+		if err == sql.ErrNoRows {
+			return menu.Item{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return menu.Item{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toItem(dbi)
+}
+
+func (repo menuRepo) RetrieveByIDs(ctx context.Context, ids []string) ([]menu.Item, error) {
+	q := `SELECT id, vendor, name, category, price, available, metadata, created_at, updated_at FROM menu_items WHERE id = ANY($1)`
+
+	rows, err := repo.db.QueryxContext(ctx, q, ids)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []menu.Item
+	for rows.Next() {
+		dbi := dbItem{}
+		if err := rows.StructScan(&dbi); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		item, err := toItem(dbi)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (repo menuRepo) RetrieveAll(ctx context.Context, pm menu.PageMetadata) (menu.ItemsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+		"vendor":   pm.Vendor,
+		"name":     pm.Name,
+		"category": pm.Category,
+	}
+	if len(pm.Metadata) > 0 {
+		mp, err := json.Marshal(pm.Metadata)
+		if err != nil {
+			return menu.ItemsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		params["metadata"] = mp
+		query = append(query, "metadata @> :metadata")
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Name != "" {
+		query = append(query, "name = :name")
+	}
+	if pm.Category != "" {
+		query = append(query, "category = :category")
+	}
+	if pm.OnlyAvailable {
+		query = append(query, "available = TRUE")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT id, vendor, name, category, price, available, metadata, created_at, updated_at FROM menu_items %s ORDER BY category, name LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return menu.ItemsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []menu.Item
+	for rows.Next() {
+		dbi := dbItem{}
+		if err := rows.StructScan(&dbi); err != nil {
+			return menu.ItemsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		item, err := toItem(dbi)
+		if err != nil {
+			return menu.ItemsPage{}, err
+		}
+		items = append(items, item)
+	}
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM menu_items %s;`, emq)
+
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return menu.ItemsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := menu.ItemsPage{
+		Items: items,
+		PageMetadata: menu.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo menuRepo) Update(ctx context.Context, item menu.Item) (string, error) {
+	q := `UPDATE menu_items SET name = :name, category = :category, price = :price, available = :available,
+		  metadata = :metadata, updated_at = :updated_at WHERE id = :id RETURNING id`
+
+	dbi, err := toDBItem(item)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbi)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo menuRepo) Delete(ctx context.Context, id string) error {
+	q := `DELETE FROM menu_items WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbItem{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbItem struct {
+	ID        string         `db:"id"`
+	Vendor    string         `db:"vendor"`
+	Name      string         `db:"name"`
+	Category  sql.NullString `db:"category"`
+	Price     uint64         `db:"price"`
+	Available bool           `db:"available"`
+	Metadata  []byte         `db:"metadata"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func toDBItem(item menu.Item) (dbItem, error) {
+	data := []byte("{}")
+	if len(item.Metadata) > 0 {
+		b, err := json.Marshal(item.Metadata)
+		if err != nil {
+			return dbItem{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		data = b
+	}
+	return dbItem{
+		ID:        item.ID,
+		Vendor:    item.Vendor,
+		Name:      item.Name,
+		Category:  sql.NullString{String: item.Category, Valid: item.Category != ""},
+		Price:     item.Price,
+		Available: item.Available,
+		Metadata:  data,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}, nil
+}
+
+func toItem(item dbItem) (menu.Item, error) {
+	var metadata map[string]interface{}
+	if item.Metadata != nil {
+		if err := json.Unmarshal(item.Metadata, &metadata); err != nil {
+			return menu.Item{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return menu.Item{
+		ID:        item.ID,
+		Vendor:    item.Vendor,
+		Name:      item.Name,
+		Category:  item.Category.String,
+		Price:     item.Price,
+		Available: item.Available,
+		Metadata:  metadata,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package menu
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var _ Service = (*menuService)(nil)
+
+type menuService struct {
+	items Repository
+}
+
+// NewService instantiates the menu service implementation.
+func NewService(items Repository) Service {
+	return &menuService{
+		items: items,
+	}
+}
+
+func (svc menuService) CreateItem(ctx context.Context, token string, item Item) (string, error) {
+	if err := item.Validate(); err != nil {
+		return "", err
+	}
+	item.ID = ulid.Make().String()
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = time.Now()
+	return svc.items.Save(ctx, item)
+}
+
+func (svc menuService) ViewItem(ctx context.Context, token, id string) (Item, error) {
+	return svc.items.RetrieveByID(ctx, id)
+}
+
+func (svc menuService) ListItems(ctx context.Context, token string, pm PageMetadata) (ItemsPage, error) {
+	return svc.items.RetrieveAll(ctx, pm)
+}
+
+func (svc menuService) UpdateItem(ctx context.Context, token string, item Item) (string, error) {
+	current, err := svc.items.RetrieveByID(ctx, item.ID)
+	if err != nil {
+		return "", err
+	}
+	if item.Name != "" {
+		current.Name = item.Name
+	}
+	if item.Category != "" {
+		current.Category = item.Category
+	}
+	if item.Price != 0 {
+		current.Price = item.Price
+	}
+	if item.Metadata != nil {
+		current.Metadata = item.Metadata
+	}
+	current.Available = item.Available
+	current.UpdatedAt = time.Now()
+	return svc.items.Update(ctx, current)
+}
+
+func (svc menuService) RemoveItem(ctx context.Context, token, id string) error {
+	return svc.items.Delete(ctx, id)
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func syncEndpoint(svc offline.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(syncReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		sync, err := svc.Sync(ctx, req.token, req.changeSet)
+		if err != nil {
+			return nil, err
+		}
+		return syncRes{Sync: sync}, nil
+	}
+}
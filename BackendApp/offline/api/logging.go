@@ -0,0 +1,40 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/go-kit/log"
+)
+
+var _ offline.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    offline.Service
+}
+
+// LoggingMiddleware adds logging facilities to the offline sync service.
+func LoggingMiddleware(svc offline.Service, logger log.Logger) offline.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Sync(ctx context.Context, token string, cs offline.ChangeSet) (sync offline.Sync, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "sync_orders",
+			"token", token,
+			"vendor", cs.Vendor,
+			"device", cs.Device,
+			"changes", len(cs.Changes),
+			"since", cs.Token,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Sync(ctx, token, cs)
+}
@@ -0,0 +1,38 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ offline.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     offline.Service
+}
+
+// MetricsMiddleware instruments the offline sync service by tracking request count
+// and latency.
+func MetricsMiddleware(svc offline.Service, counter metrics.Counter, latency metrics.Histogram) offline.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Sync(ctx context.Context, token string, cs offline.ChangeSet) (offline.Sync, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "sync_orders").Add(1)
+		ms.latency.With("method", "sync_orders").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Sync(ctx, token, cs)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+)
+
+type syncReq struct {
+	token     string
+	changeSet offline.ChangeSet
+}
+
+func (req syncReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.changeSet.Validate()
+}
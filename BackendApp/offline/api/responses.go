@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var _ Response = (*syncRes)(nil)
+
+type syncRes struct {
+	offline.Sync
+}
+
+func (res syncRes) Code() int {
+	return http.StatusOK
+}
+
+func (res syncRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res syncRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,176 @@
+// Package offline syncs orders with devices that keep taking them while
+// offline. Devices send the orders they created, edited or deleted since
+// they last synced, the server applies them, resolving conflicts the same
+// way whatever order the devices sync in, and sends back the orders changed
+// on the server since.
+//
+// Devices keep a Lamport clock: it moves past every clock they receive from
+// the server, and every change made on the device takes its next value.
+// Conflicting writes go to the later clock, the device identifier breaking
+// ties. Writes made outside of syncing count as made at the server's clock
+// when they are met, and win ties. On top of that:
+//
+//   - statuses only move forward, from ordered through preparing to paid,
+//   - metadata is resolved key by key rather than as a whole,
+//   - paid orders keep their price and items,
+//   - deleting an order wins over editing it.
+package offline
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+const (
+	// DefaultLimit is how many server changes a sync returns when the
+	// change set does not say.
+	DefaultLimit = 100
+
+	// MaxLimit is how many server changes a sync returns at most.
+	MaxLimit = 1000
+
+	// MaxChanges is how many changes a change set has at most.
+	MaxChanges = 500
+)
+
+var (
+	// ErrInvalidToken indicates a sync token not given by the server.
+	ErrInvalidToken = errors.New("invalid sync token")
+
+	// ErrDeleted indicates a change to an order deleted on the server.
+	ErrDeleted = errors.New("order deleted")
+)
+
+// Token is where a device is in the changes of the server. It is opaque to
+// devices, which send back the last one they received.
+type Token string
+
+// NewToken returns the token of the change sequence.
+func NewToken(seq uint64) Token {
+	return Token(strconv.FormatUint(seq, 36))
+}
+
+// Seq returns the change sequence of the token. The empty token is before
+// any change.
+func (t Token) Seq() (uint64, error) {
+	if t == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseUint(string(t), 36, 64)
+	if err != nil {
+		return 0, errors.Wrap(ErrInvalidToken, err)
+	}
+	return seq, nil
+}
+
+// Change is an order created, edited or deleted on a device. Created
+// orders carry an identifier the device generated, a ULID. Metadata keys
+// set to null are removed.
+type Change struct {
+	Order   orders.Order      `json:"order"`
+	Deleted bool              `json:"deleted,omitempty"`
+	Version uint64            `json:"version"`          // The device's clock when the change was made.
+	Fields  map[string]uint64 `json:"fields,omitempty"` // The device's clock when each metadata key was last set, the change's version when missing.
+}
+
+// ChangeSet is what a device sends when it syncs.
+type ChangeSet struct {
+	Device  string   `json:"device"`
+	Vendor  string   `json:"vendor"`
+	Token   Token    `json:"token,omitempty"` // The token of the device's last sync, empty on the first one.
+	Limit   uint64   `json:"limit,omitempty"` // How many server changes to send back at most.
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Validate returns an error if the change set representation is invalid.
+func (cs ChangeSet) Validate() error {
+	if cs.Device == "" || cs.Vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	if cs.Limit > MaxLimit || len(cs.Changes) > MaxChanges {
+		return errors.ErrMalformedEntity
+	}
+	_, err := cs.Token.Seq()
+	return err
+}
+
+// Outcome is what became of a change.
+type Outcome string
+
+// Change outcomes.
+const (
+	Applied  Outcome = "applied"  // The change was taken as it was.
+	Merged   Outcome = "merged"   // Some of the change lost to the server's order.
+	Stale    Outcome = "stale"    // All of the change lost to the server's order.
+	Rejected Outcome = "rejected" // The change could not be applied.
+)
+
+// Result is what became of a change of a change set.
+type Result struct {
+	ID      string  `json:"id"`
+	Outcome Outcome `json:"outcome"`
+	Error   string  `json:"error,omitempty"` // Why the change was rejected.
+}
+
+// Sync is what the server sends back to a syncing device: what became of
+// its changes and the orders changed on the server since its last sync,
+// its own changes included. Devices sync again with the token until there
+// are no more changes.
+type Sync struct {
+	Results []Result       `json:"results"`
+	Orders  []orders.Order `json:"orders"`
+	Deleted []string       `json:"deleted"`
+	Token   Token          `json:"token"`
+	More    bool           `json:"more"`
+	Clock   uint64         `json:"clock"` // The server's clock, devices move theirs past it.
+}
+
+// Stamp is the clock of a write and the device that made it.
+type Stamp struct {
+	Version uint64 `json:"version"`
+	Device  string `json:"device"`
+}
+
+// After reports whether the stamp wins over other. Writes made outside of
+// syncing have no device and win ties.
+func (s Stamp) After(other Stamp) bool {
+	if s.Version != other.Version {
+		return s.Version > other.Version
+	}
+	if s.Device == "" || other.Device == "" {
+		return s.Device == ""
+	}
+	return s.Device > other.Device
+}
+
+// Clock is how the last synced write to an order was stamped.
+type Clock struct {
+	ID     string
+	Vendor string
+	Stamp
+	Seq    uint64           // The change sequence of the order once written.
+	Fields map[string]Stamp // How each metadata key was last set.
+}
+
+// Service specifies the offline sync API.
+type Service interface {
+	// Sync applies the changes of the change set and returns the server's
+	// changes since the change set's token.
+	Sync(ctx context.Context, token string, cs ChangeSet) (Sync, error)
+}
+
+// ClockRepository specifies a clock persistence API.
+type ClockRepository interface {
+	// Save persists the clock of an order, replacing the one it had.
+	Save(ctx context.Context, clock Clock) error
+
+	// RetrieveByID retrieves the clock of an order by its unique
+	// identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Clock, error)
+
+	// Latest returns the latest version synced to the vendor's orders.
+	Latest(ctx context.Context, vendor string) (uint64, error)
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ offline.ClockRepository = (*clocksRepo)(nil)
+
+type clocksRepo struct {
+	db *sqlx.DB
+}
+
+// NewClocksRepo instantiates a PostgreSQL
+// implementation of order clocks repository.
+func NewClocksRepo(db *sqlx.DB) offline.ClockRepository {
+	return &clocksRepo{
+		db: db,
+	}
+}
+
+func (repo clocksRepo) Save(ctx context.Context, clock offline.Clock) error {
+	q := `INSERT INTO order_clocks (id, vendor, version, device, seq, fields)
+		  VALUES (:id, :vendor, :version, :device, :seq, :fields)
+		  ON CONFLICT (id) DO UPDATE SET vendor = :vendor, version = :version, device = :device, seq = :seq, fields = :fields`
+
+	dbc, err := toDBClock(clock)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, dbc); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo clocksRepo) RetrieveByID(ctx context.Context, id string) (offline.Clock, error) {
+	q := `SELECT id, vendor, version, device, seq, fields FROM order_clocks WHERE id = $1`
+
+	dbc := dbClock{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return offline.Clock{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return offline.Clock{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toClock(dbc)
+}
+
+func (repo clocksRepo) Latest(ctx context.Context, vendor string) (uint64, error) {
+	q := `SELECT COALESCE(MAX(version), 0) FROM order_clocks WHERE vendor = $1`
+
+	var latest uint64
+	if err := repo.db.QueryRowxContext(ctx, q, vendor).Scan(&latest); err != nil {
+		return 0, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return latest, nil
+}
+
+type dbClock struct {
+	ID      string `db:"id"`
+	Vendor  string `db:"vendor"`
+	Version uint64 `db:"version"`
+	Device  string `db:"device"`
+	Seq     uint64 `db:"seq"`
+	Fields  []byte `db:"fields"`
+}
+
+func toDBClock(clock offline.Clock) (dbClock, error) {
+	fields := clock.Fields
+	if fields == nil {
+		fields = map[string]offline.Stamp{}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return dbClock{}, err
+	}
+	return dbClock{
+		ID:      clock.ID,
+		Vendor:  clock.Vendor,
+		Version: clock.Version,
+		Device:  clock.Device,
+		Seq:     clock.Seq,
+		Fields:  data,
+	}, nil
+}
+
+func toClock(dbc dbClock) (offline.Clock, error) {
+	var fields map[string]offline.Stamp
+	if err := json.Unmarshal(dbc.Fields, &fields); err != nil {
+		return offline.Clock{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return offline.Clock{
+		ID:     dbc.ID,
+		Vendor: dbc.Vendor,
+		Stamp: offline.Stamp{
+			Version: dbc.Version,
+			Device:  dbc.Device,
+		},
+		Seq:    dbc.Seq,
+		Fields: fields,
+	}, nil
+}
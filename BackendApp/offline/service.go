@@ -0,0 +1,381 @@
+package offline
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+// statusRanks orders the statuses an order moves through.
+var statusRanks = map[string]int{
+	orders.StatusOrdered:   1,
+	orders.StatusPreparing: 2,
+	orders.StatusPaid:      3,
+}
+
+// offlineMethod is the payment method orders paid offline are settled with.
+const offlineMethod = "offline"
+
+var _ Service = (*offlineService)(nil)
+
+type offlineService struct {
+	ordersSvc orders.OrderService
+	orders    orders.OrderRepository
+	clocks    ClockRepository
+}
+
+// NewService instantiates the offline sync service implementation. Orders
+// taken offline are saved into the orders repository as they were taken,
+// under their own identifiers, and changed through the orders service like
+// any other.
+func NewService(ordersSvc orders.OrderService, repo orders.OrderRepository, clocks ClockRepository) Service {
+	return &offlineService{
+		ordersSvc: ordersSvc,
+		orders:    repo,
+		clocks:    clocks,
+	}
+}
+
+func (svc offlineService) Sync(ctx context.Context, token string, cs ChangeSet) (Sync, error) {
+	if err := cs.Validate(); err != nil {
+		return Sync{}, err
+	}
+	since, _ := cs.Token.Seq()
+	if cs.Limit == 0 {
+		cs.Limit = DefaultLimit
+	}
+	latest, err := svc.clocks.Latest(ctx, cs.Vendor)
+	if err != nil {
+		return Sync{}, err
+	}
+
+	// Writes met outside of syncing count as made at the clock the sync
+	// started with, whatever the changes before them moved it to.
+	sync := Sync{
+		Results: make([]Result, len(cs.Changes)),
+		Orders:  []orders.Order{},
+		Deleted: []string{},
+		Clock:   latest,
+	}
+	for i, c := range cs.Changes {
+		outcome, err := svc.apply(ctx, token, cs, latest, c)
+		if err != nil && !rejected(err) {
+			return Sync{}, err
+		}
+		sync.Results[i] = Result{ID: c.Order.ID, Outcome: outcome}
+		if err != nil {
+			sync.Results[i].Outcome = Rejected
+			sync.Results[i].Error = err.Error()
+			continue
+		}
+		if c.Version > sync.Clock {
+			sync.Clock = c.Version
+		}
+	}
+
+	page, err := svc.orders.RetrieveChanges(ctx, cs.Vendor, since, cs.Limit)
+	if err != nil {
+		return Sync{}, err
+	}
+	sync.Orders = append(sync.Orders, page.Orders...)
+	for _, t := range page.Deleted {
+		sync.Deleted = append(sync.Deleted, t.ID)
+	}
+	sync.Token = NewToken(page.Seq)
+	sync.More = page.More
+	return sync, nil
+}
+
+// apply applies a change of the change set, latest being the server's
+// clock.
+func (svc offlineService) apply(ctx context.Context, token string, cs ChangeSet, latest uint64, c Change) (Outcome, error) {
+	if c.Order.ID == "" {
+		return Rejected, errors.ErrMissingID
+	}
+	if _, err := ulid.Parse(c.Order.ID); err != nil {
+		return Rejected, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if c.Order.Vendor != "" && c.Order.Vendor != cs.Vendor {
+		return Rejected, errors.ErrMalformedEntity
+	}
+	stamp := Stamp{Version: c.Version, Device: cs.Device}
+
+	current, err := svc.orders.RetrieveByID(ctx, c.Order.ID)
+	switch {
+	case errors.Contains(err, errors.ErrNotFound):
+		_, err := svc.orders.RetrieveTombstone(ctx, c.Order.ID)
+		switch {
+		case err == nil && c.Deleted:
+			return Applied, nil
+		case err == nil:
+			return Rejected, ErrDeleted
+		case !errors.Contains(err, errors.ErrNotFound):
+			return "", err
+		case c.Deleted:
+			// Orders created and deleted while offline never reach the
+			// server.
+			return Applied, nil
+		}
+		return svc.create(ctx, token, cs.Vendor, stamp, c)
+	case err != nil:
+		return "", err
+	case current.Vendor != cs.Vendor:
+		return Rejected, errors.ErrNotFound
+	}
+
+	last, err := svc.clock(ctx, current, latest)
+	if err != nil {
+		return "", err
+	}
+	if c.Deleted {
+		if !stamp.After(last.Stamp) {
+			return Stale, nil
+		}
+		if err := svc.ordersSvc.DeleteOrder(ctx, token, current.ID); err != nil {
+			return "", err
+		}
+		// The clock of the deletion is kept, the server's clock is never
+		// behind a change it took.
+		clock := Clock{ID: current.ID, Vendor: current.Vendor, Stamp: stamp, Fields: last.Fields}
+		if err := svc.clocks.Save(ctx, clock); err != nil {
+			return "", err
+		}
+		return Applied, nil
+	}
+	return svc.update(ctx, token, current, last, stamp, c)
+}
+
+func (svc offlineService) create(ctx context.Context, token, vendor string, stamp Stamp, c Change) (Outcome, error) {
+	order := c.Order
+	order.Vendor = vendor
+	if err := order.Validate(); err != nil {
+		return Rejected, err
+	}
+	if len(order.Items) > 0 {
+		if order.Price == 0 {
+			order.Price = order.ItemsTotal()
+		}
+		if order.Name == "" {
+			order.Name = order.ItemsName()
+		}
+	}
+	// Money is taken through payments, discounts and taxes through their
+	// services, once the device is back online.
+	order.Adjustments, order.Taxes = nil, nil
+	order.Paid, order.Tips = 0, 0
+	// Orders are taken by the staff member syncing them and move on from
+	// ordered through the orders service, as orders taken online do.
+	status := order.Status
+	order.Status = orders.StatusOrdered
+	order.CreatedBy, order.AcceptedBy, order.PaidBy = orders.Staff(ctx), "", ""
+	for k, v := range order.Metadata {
+		if v == nil {
+			delete(order.Metadata, k)
+		}
+	}
+	// Orders keep the wall clock of the server that took them. Devices are
+	// trusted with when the order was taken, not with taking it later.
+	now := time.Now()
+	if order.CreatedAt.IsZero() || order.CreatedAt.After(now) {
+		order.CreatedAt = now
+	}
+	order.CreatedAt = order.CreatedAt.In(time.Local)
+	order.UpdatedAt = now
+
+	if _, err := svc.orders.Save(ctx, order); err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return Rejected, err
+		}
+		return "", err
+	}
+	if status != orders.StatusOrdered {
+		if err := svc.advance(ctx, token, order, status); err != nil {
+			return "", err
+		}
+	}
+	created, err := svc.orders.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		return "", err
+	}
+	clock := Clock{
+		ID:     created.ID,
+		Vendor: vendor,
+		Stamp:  stamp,
+		Seq:    created.Seq,
+		Fields: map[string]Stamp{},
+	}
+	for k := range created.Metadata {
+		clock.Fields[k] = field(c, k, stamp)
+	}
+	if err := svc.clocks.Save(ctx, clock); err != nil {
+		return "", err
+	}
+	return Applied, nil
+}
+
+// update applies the edit of a device to the current order, field by
+// field.
+func (svc offlineService) update(ctx context.Context, token string, current orders.Order, last Clock, stamp Stamp, c Change) (Outcome, error) {
+	edit := c.Order
+	if edit.Status != "" && !orders.ValidateStatus(edit.Status) {
+		return Rejected, errors.ErrInvalidStatus
+	}
+	if edit.Place != "" && !orders.ValidatePlaces(edit.Place) {
+		return Rejected, errors.ErrMalformedEntity
+	}
+
+	wins := stamp.After(last.Stamp)
+	settled := current.Status == orders.StatusPaid
+	uOrder := orders.Order{ID: current.ID}
+	took, lost := false, false
+	take := func(differs, allowed bool, set func()) {
+		if !differs {
+			return
+		}
+		if allowed {
+			set()
+			took = true
+			return
+		}
+		lost = true
+	}
+	take(edit.Name != "" && edit.Name != current.Name, wins, func() { uOrder.Name = edit.Name })
+	take(edit.Place != "" && edit.Place != current.Place, wins, func() { uOrder.Place = edit.Place })
+	take(edit.Price != 0 && edit.Price != current.Price, wins && !settled, func() { uOrder.Price = edit.Price })
+	take(edit.Items != nil && !reflect.DeepEqual(edit.Items, current.Items), wins && !settled, func() { uOrder.Items = edit.Items })
+	take(edit.Status != "" && edit.Status != current.Status, statusRanks[edit.Status] > statusRanks[current.Status], func() { uOrder.Status = edit.Status })
+
+	fields := map[string]Stamp{}
+	for k, s := range last.Fields {
+		fields[k] = s
+	}
+	for k := range current.Metadata {
+		fields[k] = lastField(last, k)
+	}
+	metadata := orders.Metadata{}
+	for k, v := range current.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range edit.Metadata {
+		fs := field(c, k, stamp)
+		take(!reflect.DeepEqual(v, current.Metadata[k]), fs.After(lastField(last, k)), func() {
+			// Removed keys keep their stamp, so older sets of them lose.
+			if v == nil {
+				delete(metadata, k)
+			} else {
+				metadata[k] = v
+			}
+			fields[k] = fs
+			uOrder.Metadata = metadata
+		})
+	}
+
+	if !took {
+		if lost {
+			return Stale, nil
+		}
+		return Applied, nil
+	}
+	status := uOrder.Status
+	if status == orders.StatusPaid {
+		uOrder.Status = ""
+	}
+	if _, err := svc.ordersSvc.UpdateOrder(ctx, token, uOrder); err != nil {
+		return "", err
+	}
+	updated, err := svc.orders.RetrieveByID(ctx, current.ID)
+	if err != nil {
+		return "", err
+	}
+	if status == orders.StatusPaid {
+		if err := svc.advance(ctx, token, updated, status); err != nil {
+			return "", err
+		}
+		if updated, err = svc.orders.RetrieveByID(ctx, current.ID); err != nil {
+			return "", err
+		}
+	}
+	clock := Clock{
+		ID:     current.ID,
+		Vendor: current.Vendor,
+		Stamp:  last.Stamp,
+		Seq:    updated.Seq,
+		Fields: fields,
+	}
+	if wins {
+		clock.Stamp = stamp
+	}
+	if err := svc.clocks.Save(ctx, clock); err != nil {
+		return "", err
+	}
+	outcome := Applied
+	if lost {
+		outcome = Merged
+	}
+	return outcome, nil
+}
+
+// advance moves the order on to the status it was synced with. Orders paid
+// offline are settled by a payment of their balance, recorded once however
+// often the change is synced.
+func (svc offlineService) advance(ctx context.Context, token string, order orders.Order, status string) error {
+	if status == orders.StatusPaid && order.Balance() > 0 {
+		ctx = orders.WithPaymentKey(ctx, "offline:"+order.ID)
+		_, err := svc.ordersSvc.RecordPayment(ctx, token, order.ID, offlineMethod, order.Balance(), 0)
+		return err
+	}
+	_, err := svc.ordersSvc.UpdateOrder(ctx, token, orders.Order{ID: order.ID, Status: status})
+	return err
+}
+
+// clock returns how the order was last written. Orders never synced, or
+// written outside of syncing since they last were, count as written at the
+// server's clock.
+func (svc offlineService) clock(ctx context.Context, order orders.Order, latest uint64) (Clock, error) {
+	clock, err := svc.clocks.RetrieveByID(ctx, order.ID)
+	if err != nil && !errors.Contains(err, errors.ErrNotFound) {
+		return Clock{}, err
+	}
+	if err != nil || clock.Seq != order.Seq {
+		return Clock{ID: order.ID, Vendor: order.Vendor, Stamp: Stamp{Version: latest}}, nil
+	}
+	return clock, nil
+}
+
+// field returns the stamp the change set the metadata key with.
+func field(c Change, key string, stamp Stamp) Stamp {
+	if v, ok := c.Fields[key]; ok {
+		stamp.Version = v
+	}
+	return stamp
+}
+
+// lastField returns the stamp the metadata key was last set with.
+func lastField(last Clock, key string) Stamp {
+	if s, ok := last.Fields[key]; ok {
+		return s
+	}
+	return last.Stamp
+}
+
+// rejected reports whether the error is the change's fault rather than the
+// server's, the sync goes on without the change.
+func rejected(err error) bool {
+	for _, e := range []error{
+		errors.ErrMalformedEntity,
+		errors.ErrMissingID,
+		errors.ErrInvalidStatus,
+		errors.ErrNotFound,
+		errors.ErrConflict,
+		ErrDeleted,
+	} {
+		if errors.Contains(err, e) {
+			return true
+		}
+	}
+	return false
+}
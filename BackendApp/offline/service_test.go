@@ -0,0 +1,127 @@
+package offline_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	token   = "token"
+	vendor  = "mama-oliech"
+	cashier = "amina"
+)
+
+// clockRepo keeps clocks in memory.
+type clockRepo struct {
+	mu     sync.Mutex
+	clocks map[string]offline.Clock
+}
+
+func (repo *clockRepo) Save(ctx context.Context, clock offline.Clock) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.clocks[clock.ID] = clock
+	return nil
+}
+
+func (repo *clockRepo) RetrieveByID(ctx context.Context, id string) (offline.Clock, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	clock, ok := repo.clocks[id]
+	if !ok {
+		return offline.Clock{}, errors.ErrNotFound
+	}
+	return clock, nil
+}
+
+func (repo *clockRepo) Latest(ctx context.Context, vendor string) (uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var latest uint64
+	for _, clock := range repo.clocks {
+		if clock.Vendor == vendor && clock.Stamp.Version > latest {
+			latest = clock.Stamp.Version
+		}
+	}
+	return latest, nil
+}
+
+// paidHook keeps the orders it is told were paid.
+type paidHook struct {
+	paid []orders.Order
+}
+
+func (hook *paidHook) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	return nil
+}
+
+func (hook *paidHook) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	hook.paid = append(hook.paid, order)
+	return nil
+}
+
+func TestSyncAttribution(t *testing.T) {
+	ctx := orders.WithStaff(context.Background(), cashier)
+	repo := memory.NewOrderRepo()
+	hook := &paidHook{}
+	notifier := orders.NewNotifier(repo, hook)
+	svc := offline.NewService(orders.NewOrderService(repo), repo, &clockRepo{clocks: map[string]offline.Clock{}})
+
+	// An order taken and paid offline is attributed to the staff member
+	// syncing it, whoever the device says took it.
+	order := orders.Order{ID: ulid.Make().String(), Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusPaid, CreatedBy: "mallory", PaidBy: "mallory", CreatedAt: time.Now().Add(-time.Minute)}
+	cs := offline.ChangeSet{Device: "till", Vendor: vendor, Changes: []offline.Change{{Order: order, Version: 1}}}
+	sync, err := svc.Sync(ctx, token, cs)
+	if err != nil {
+		t.Fatalf("syncing: %s", err)
+	}
+	if got := sync.Results[0].Outcome; got != offline.Applied {
+		t.Fatalf("created order was %s, want %s", got, offline.Applied)
+	}
+	created, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("retrieving created order: %s", err)
+	}
+	if created.CreatedBy != cashier || created.PaidBy != cashier {
+		t.Errorf("created order was taken by %q and paid by %q, want %q", created.CreatedBy, created.PaidBy, cashier)
+	}
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying the hooks: %s", err)
+	}
+	if len(hook.paid) != 1 {
+		t.Errorf("created order was paid %d times, want once", len(hook.paid))
+	}
+
+	// So is an order moved on offline.
+	order = orders.Order{ID: ulid.Make().String(), Name: "Pilau", Price: 100, Place: "inhouse", Status: orders.StatusOrdered}
+	cs.Changes = []offline.Change{{Order: order, Version: 2}}
+	if _, err := svc.Sync(ctx, token, cs); err != nil {
+		t.Fatalf("syncing: %s", err)
+	}
+	order.Status, order.PaidBy = orders.StatusPaid, "mallory"
+	cs.Changes = []offline.Change{{Order: order, Version: 3}}
+	if _, err := svc.Sync(ctx, token, cs); err != nil {
+		t.Fatalf("syncing: %s", err)
+	}
+	updated, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("retrieving updated order: %s", err)
+	}
+	if updated.Status != orders.StatusPaid || updated.PaidBy != cashier {
+		t.Errorf("updated order is %s and paid by %q, want %s and %q", updated.Status, updated.PaidBy, orders.StatusPaid, cashier)
+	}
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying the hooks: %s", err)
+	}
+	if len(hook.paid) != 2 {
+		t.Errorf("orders were paid %d times, want twice", len(hook.paid))
+	}
+}
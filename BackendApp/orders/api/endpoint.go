@@ -7,131 +7,93 @@ import (
 	"github.com/go-kit/kit/endpoint"
 )
 
-func createOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
+// The endpoints below are served by the HTTP handler and the gRPC server
+// alike, each transport decoding its requests into the requests of the
+// endpoints and encoding their responses.
+
+// CreateOrderEndpoint creates the order of a CreateOrderReq and responds
+// with a CreateOrderRes.
+func CreateOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(createOrderReq)
+		req := request.(CreateOrderReq)
 		if err := req.validate(); err != nil {
-			return createOrderRes{}, err
+			return nil, err
 		}
-		uid, err := svc.CreateOrder(ctx, req.token, req.order)
+		id, err := svc.CreateOrder(ctx, req.Token, req.Order)
 		if err != nil {
-			return createOrderRes{}, err
-		}
-		ucr := createOrderRes{
-			ID:      uid,
-			created: true,
+			return nil, err
 		}
-
-		return ucr, nil
+		return CreateOrderRes{ID: id}, nil
 	}
 }
 
-func viewOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
+// ViewOrderEndpoint responds to a ViewOrderReq with an OrderRes.
+func ViewOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(viewOrderReq)
+		req := request.(ViewOrderReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
-		order, err := svc.ViewOrder(ctx, req.token, req.id)
+		order, err := svc.ViewOrder(ctx, req.Token, req.ID)
 		if err != nil {
 			return nil, err
 		}
-		return viewOrderRes{
-			ID:        order.ID,
-			Vendor:    order.Vendor,
-			Name:      order.Name,
-			Price:     order.Price,
-			Place:     order.Place,
-			Metadata:  order.Metadata,
-			Status:    order.Status,
-			CreatedAt: order.CreatedAt,
-			UpdatedAt: order.UpdatedAt,
-		}, nil
+		return OrderRes{Order: order}, nil
 	}
 }
 
-func listOrdersEndpoint(svc orders.OrderService) endpoint.Endpoint {
+// ListOrdersEndpoint responds to a ListOrdersReq with an OrdersPageRes.
+func ListOrdersEndpoint(svc orders.OrderService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(listOrdersReq)
+		req := request.(ListOrdersReq)
 		if err := req.validate(); err != nil {
-			return orders.OrdersPage{}, err
-		}
-		pm := orders.PageMetadata{
-			Offset: req.offset,
-			Limit:  req.limit,
-			Total:  req.total,
-			Vendor: req.vendor,
-			Name:   req.name,
-			Price:  req.price,
-			Place:  req.place,
-			Status: req.status,
+			return nil, err
 		}
-		up, err := svc.ListOrders(ctx, req.token, pm)
+		page, err := svc.ListOrders(ctx, req.Token, req.PageMetadata)
 		if err != nil {
-			return orders.OrdersPage{}, err
+			return nil, err
 		}
-		return buildOrdersResponse(up), nil
+		return OrdersPageRes{Page: page}, nil
 	}
 }
 
-func updateOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
+// UpdateOrderEndpoint updates the order of an UpdateOrderReq and responds
+// with an UpdateOrderRes.
+func UpdateOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(updateOrderReq)
+		req := request.(UpdateOrderReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 		order := orders.Order{
-			ID:       req.id,
-			Vendor:   req.Vendor,
-			Name:     req.Name,
-			Price:    req.Price,
-			Place:    req.Place,
-			Status:   req.Status,
-			Metadata: req.Metadata,
+			ID:       req.Order.ID,
+			Vendor:   req.Order.Vendor,
+			Name:     req.Order.Name,
+			Price:    req.Order.Price,
+			Place:    req.Order.Place,
+			Status:   req.Order.Status,
+			Items:    req.Order.Items,
+			Metadata: req.Order.Metadata,
 		}
-		oid, err := svc.UpdateOrder(ctx, req.token, order)
+		id, err := svc.UpdateOrder(ctx, req.Token, order)
 		if err != nil {
 			return nil, err
 		}
-		return updateOrderRes{ID: oid, updated: true}, nil
+		return UpdateOrderRes{ID: id}, nil
 	}
 }
 
-func deleteOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
+// DeleteOrderEndpoint deletes the order of a DeleteOrderReq and responds
+// with a DeleteOrderRes.
+func DeleteOrderEndpoint(svc orders.OrderService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(deleteOrderReq)
+		req := request.(DeleteOrderReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
-		if err := svc.DeleteOrder(ctx, req.token, req.id); err != nil {
+		if err := svc.DeleteOrder(ctx, req.Token, req.ID); err != nil {
 			return nil, err
 		}
-		return deleteOrderRes{ID: req.id, deleted: true}, nil
-	}
-}
-
-func buildOrdersResponse(op orders.OrdersPage) ordersPageRes {
-	res := ordersPageRes{
-		pageRes: pageRes{
-			Total:  op.Total,
-			Offset: op.Offset,
-			Limit:  op.Limit,
-		},
-		Orders: []viewOrderRes{},
-	}
-	for _, order := range op.Orders {
-		view := viewOrderRes{
-			ID:        order.ID,
-			Vendor:    order.Vendor,
-			Name:      order.Name,
-			Price:     order.Price,
-			Place:     order.Place,
-			Status:    order.Status,
-			Metadata:  order.Metadata,
-			CreatedAt: order.CreatedAt,
-			UpdatedAt: order.UpdatedAt,
-		}
-		res.Orders = append(res.Orders, view)
+		return DeleteOrderRes{ID: req.ID}, nil
 	}
-	return res
 }
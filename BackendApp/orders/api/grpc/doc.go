@@ -0,0 +1,6 @@
+// Package grpc contains the gRPC transport of the orders service, next to
+// the HTTP one of package api: the protobuf definition and its generated
+// code and the server, which serves the endpoints of package api.
+package grpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative orders.proto
@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/api"
+	"github.com/go-kit/kit/endpoint"
+)
+
+// The other endpoints are the ones the HTTP handler serves, payments are
+// only taken over gRPC.
+
+func recordPaymentEndpoint(svc orders.OrderService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(recordPaymentReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		order, err := svc.RecordPayment(ctx, req.token, req.id, req.method, req.amount, req.tip)
+		if err != nil {
+			return nil, err
+		}
+		return api.OrderRes{Order: order}, nil
+	}
+}
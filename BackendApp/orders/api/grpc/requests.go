@@ -0,0 +1,37 @@
+package grpc
+
+import "github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+
+type recordPaymentReq struct {
+	token  string
+	id     string
+	method string
+	amount uint64
+	tip    uint64
+}
+
+func (req recordPaymentReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type watchOrdersReq struct {
+	token  string
+	vendor string
+	since  uint64
+}
+
+func (req watchOrdersReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
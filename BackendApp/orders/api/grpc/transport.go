@@ -0,0 +1,461 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/api"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	kitlog "github.com/go-kit/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	authKey = "authorization"
+
+	// watchEvery is how often a watch looks for changes once it has sent
+	// all there were.
+	watchEvery = time.Second
+
+	// watchPage is how many changes a watch reads at a time.
+	watchPage = 100
+)
+
+// Feed is where watches read the changes of orders from, i.e. the orders
+// repository.
+type Feed interface {
+	RetrieveChanges(ctx context.Context, vendor string, since, limit uint64) (orders.ChangesPage, error)
+}
+
+var _ OrderServiceServer = (*grpcServer)(nil)
+
+type grpcServer struct {
+	UnimplementedOrderServiceServer
+	createOrder   kitgrpc.Handler
+	viewOrder     kitgrpc.Handler
+	listOrders    kitgrpc.Handler
+	updateOrder   kitgrpc.Handler
+	deleteOrder   kitgrpc.Handler
+	recordPayment kitgrpc.Handler
+	feed          Feed
+}
+
+// NewServer returns a gRPC server of the orders service, on the same
+// service the HTTP handler serves. Watches stream from the feed, go-kit
+// endpoints do not stream.
+func NewServer(svc orders.OrderService, feed Feed, logger kitlog.Logger) OrderServiceServer {
+	opts := []kitgrpc.ServerOption{
+		kitgrpc.ServerErrorLogger(logger),
+		kitoc.GRPCServerTrace(),
+	}
+	return &grpcServer{
+		createOrder: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint create_order")(api.CreateOrderEndpoint(svc)),
+			decodeCreateOrder,
+			encodeCreateOrder,
+			opts...,
+		),
+		viewOrder: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint view_order")(api.ViewOrderEndpoint(svc)),
+			decodeViewOrder,
+			encodeOrder,
+			opts...,
+		),
+		listOrders: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint list_orders")(api.ListOrdersEndpoint(svc)),
+			decodeListOrders,
+			encodeOrdersPage,
+			opts...,
+		),
+		updateOrder: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint update_order")(api.UpdateOrderEndpoint(svc)),
+			decodeUpdateOrder,
+			encodeUpdateOrder,
+			opts...,
+		),
+		deleteOrder: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint delete_order")(api.DeleteOrderEndpoint(svc)),
+			decodeDeleteOrder,
+			encodeDeleteOrder,
+			opts...,
+		),
+		recordPayment: kitgrpc.NewServer(
+			kitoc.TraceEndpoint("gokit:endpoint record_payment")(recordPaymentEndpoint(svc)),
+			decodeRecordPayment,
+			encodeOrder,
+			opts...,
+		),
+		feed: feed,
+	}
+}
+
+func (s *grpcServer) CreateOrder(ctx context.Context, req *Order) (*CreateOrderRes, error) {
+	_, res, err := s.createOrder.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*CreateOrderRes), nil
+}
+
+func (s *grpcServer) ViewOrder(ctx context.Context, req *ViewOrderReq) (*Order, error) {
+	_, res, err := s.viewOrder.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*Order), nil
+}
+
+func (s *grpcServer) ListOrders(ctx context.Context, req *ListOrdersReq) (*OrdersPage, error) {
+	_, res, err := s.listOrders.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*OrdersPage), nil
+}
+
+func (s *grpcServer) UpdateOrder(ctx context.Context, req *UpdateOrderReq) (*UpdateOrderRes, error) {
+	_, res, err := s.updateOrder.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*UpdateOrderRes), nil
+}
+
+func (s *grpcServer) DeleteOrder(ctx context.Context, req *DeleteOrderReq) (*emptypb.Empty, error) {
+	_, res, err := s.deleteOrder.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*emptypb.Empty), nil
+}
+
+func (s *grpcServer) RecordPayment(ctx context.Context, req *RecordPaymentReq) (*Order, error) {
+	_, res, err := s.recordPayment.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*Order), nil
+}
+
+// WatchOrders sends the changes there are, then looks for new ones every
+// watchEvery until the client goes away.
+func (s *grpcServer) WatchOrders(req *WatchOrdersReq, stream OrderService_WatchOrdersServer) error {
+	ctx := stream.Context()
+	wr := watchOrdersReq{
+		token:  decodeToken(ctx),
+		vendor: req.GetVendor(),
+		since:  req.GetSince(),
+	}
+	if err := wr.validate(); err != nil {
+		return encodeError(err)
+	}
+	ticker := time.NewTicker(watchEvery)
+	defer ticker.Stop()
+	for {
+		page, err := s.feed.RetrieveChanges(ctx, wr.vendor, wr.since, watchPage)
+		if err != nil {
+			return encodeError(err)
+		}
+		events, err := changeEvents(page)
+		if err != nil {
+			return encodeError(err)
+		}
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		wr.since = page.Seq
+		if page.More {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// changeEvents returns the changes of the page in the order they happened.
+func changeEvents(page orders.ChangesPage) ([]*OrderEvent, error) {
+	events := make([]*OrderEvent, 0, len(page.Orders)+len(page.Deleted))
+	i, j := 0, 0
+	for i < len(page.Orders) || j < len(page.Deleted) {
+		if j == len(page.Deleted) || (i < len(page.Orders) && page.Orders[i].Seq < page.Deleted[j].Seq) {
+			order, err := toPBOrder(page.Orders[i])
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, &OrderEvent{Type: OrderEvent_WRITTEN, Seq: order.Seq, Order: order})
+			i++
+			continue
+		}
+		t := page.Deleted[j]
+		events = append(events, &OrderEvent{
+			Type:  OrderEvent_DELETED,
+			Seq:   t.Seq,
+			Order: &Order{Id: t.ID, Vendor: t.Vendor, Seq: t.Seq},
+		})
+		j++
+	}
+	return events, nil
+}
+
+func decodeCreateOrder(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*Order)
+	order, err := toOrder(req)
+	if err != nil {
+		return nil, err
+	}
+	// Discounts come from promotions and taxes from the vendor's tax rates,
+	// not from the client.
+	order.Adjustments = nil
+	order.Taxes = nil
+	// What was paid and who acted on the order are recorded by the server.
+	order.Paid, order.Tips = 0, 0
+	order.CreatedBy, order.AcceptedBy, order.PaidBy = "", "", ""
+	return api.CreateOrderReq{Token: decodeToken(ctx), Order: order}, nil
+}
+
+func decodeViewOrder(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*ViewOrderReq)
+	return api.ViewOrderReq{Token: decodeToken(ctx), ID: req.GetId()}, nil
+}
+
+func decodeListOrders(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*ListOrdersReq)
+	pm := orders.PageMetadata{
+		Offset:      req.GetOffset(),
+		Limit:       req.GetLimit(),
+		Vendor:      req.GetVendor(),
+		Name:        req.GetName(),
+		Price:       req.GetPrice(),
+		Place:       req.GetPlace(),
+		Status:      req.GetStatus(),
+		Outstanding: req.GetOutstanding(),
+	}
+	if req.From != nil {
+		pm.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		pm.To = req.To.AsTime()
+	}
+	if req.Metadata != nil {
+		pm.Metadata = req.Metadata.AsMap()
+	}
+	return api.ListOrdersReq{Token: decodeToken(ctx), PageMetadata: pm}, nil
+}
+
+func decodeUpdateOrder(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*UpdateOrderReq)
+	order := orders.Order{
+		ID:     req.GetId(),
+		Vendor: req.GetVendor(),
+		Name:   req.GetName(),
+		Price:  req.GetPrice(),
+		Place:  req.GetPlace(),
+		Status: req.GetStatus(),
+		Items:  toItems(req.GetItems()),
+	}
+	if req.Metadata != nil {
+		order.Metadata = req.Metadata.AsMap()
+	}
+	return api.UpdateOrderReq{Token: decodeToken(ctx), Order: order}, nil
+}
+
+func decodeDeleteOrder(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*DeleteOrderReq)
+	return api.DeleteOrderReq{Token: decodeToken(ctx), ID: req.GetId()}, nil
+}
+
+func decodeRecordPayment(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*RecordPaymentReq)
+	return recordPaymentReq{
+		token:  decodeToken(ctx),
+		id:     req.GetId(),
+		method: req.GetMethod(),
+		amount: req.GetAmount(),
+		tip:    req.GetTip(),
+	}, nil
+}
+
+func encodeCreateOrder(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(api.CreateOrderRes)
+	return &CreateOrderRes{Id: res.ID}, nil
+}
+
+func encodeOrder(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(api.OrderRes)
+	return toPBOrder(res.Order)
+}
+
+func encodeOrdersPage(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(api.OrdersPageRes)
+	page := &OrdersPage{
+		Total:  res.Page.Total,
+		Offset: res.Page.Offset,
+		Limit:  res.Page.Limit,
+		Orders: make([]*Order, 0, len(res.Page.Orders)),
+	}
+	for _, order := range res.Page.Orders {
+		o, err := toPBOrder(order)
+		if err != nil {
+			return nil, err
+		}
+		page.Orders = append(page.Orders, o)
+	}
+	return page, nil
+}
+
+func encodeUpdateOrder(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(api.UpdateOrderRes)
+	return &UpdateOrderRes{Id: res.ID}, nil
+}
+
+func encodeDeleteOrder(_ context.Context, _ interface{}) (interface{}, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// decodeToken reads the token from the authorization metadata, as the HTTP
+// transport reads it from the Authorization header.
+func decodeToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(authKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.Replace(values[0], "Bearer ", "", 1)
+}
+
+// encodeError maps errors to status codes as the HTTP transport maps them
+// to status codes of its own.
+func encodeError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidStatus),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		code = codes.InvalidArgument
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		code = codes.Unauthenticated
+	case errors.Contains(err, errors.ErrAuthorization):
+		code = codes.PermissionDenied
+	case errors.Contains(err, errors.ErrNotFound):
+		code = codes.NotFound
+	case errors.Contains(err, errors.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Contains(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Contains(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	msg := err.Error()
+	if errorVal, ok := err.(errors.Error); ok {
+		msg = errorVal.Msg()
+	}
+	return status.Error(code, msg)
+}
+
+func toOrder(o *Order) (orders.Order, error) {
+	order := orders.Order{
+		ID:         o.GetId(),
+		Vendor:     o.GetVendor(),
+		Name:       o.GetName(),
+		Price:      o.GetPrice(),
+		Place:      o.GetPlace(),
+		Status:     o.GetStatus(),
+		Items:      toItems(o.GetItems()),
+		Paid:       o.GetPaid(),
+		Tips:       o.GetTips(),
+		CreatedBy:  o.GetCreatedBy(),
+		AcceptedBy: o.GetAcceptedBy(),
+		PaidBy:     o.GetPaidBy(),
+	}
+	if o.Metadata != nil {
+		order.Metadata = o.Metadata.AsMap()
+	}
+	return order, nil
+}
+
+func toItems(items []*Item) []orders.Item {
+	if len(items) == 0 {
+		return nil
+	}
+	res := make([]orders.Item, 0, len(items))
+	for _, item := range items {
+		res = append(res, orders.Item{
+			ID:       item.GetId(),
+			Name:     item.GetName(),
+			Quantity: item.GetQuantity(),
+			Price:    item.GetPrice(),
+		})
+	}
+	return res
+}
+
+func toPBOrder(order orders.Order) (*Order, error) {
+	o := &Order{
+		Id:         order.ID,
+		Vendor:     order.Vendor,
+		Name:       order.Name,
+		Price:      order.Price,
+		Place:      order.Place,
+		Status:     order.Status,
+		Paid:       order.Paid,
+		Tips:       order.Tips,
+		CreatedBy:  order.CreatedBy,
+		AcceptedBy: order.AcceptedBy,
+		PaidBy:     order.PaidBy,
+		CreatedAt:  toTimestamp(order.CreatedAt),
+		UpdatedAt:  toTimestamp(order.UpdatedAt),
+		Seq:        order.Seq,
+		Gross:      order.Gross(),
+		Discount:   order.Discount(),
+		Tax:        order.TaxTotal(),
+		Balance:    order.Balance(),
+	}
+	for _, item := range order.Items {
+		o.Items = append(o.Items, &Item{Id: item.ID, Name: item.Name, Quantity: item.Quantity, Price: item.Price})
+	}
+	for _, adj := range order.Adjustments {
+		o.Adjustments = append(o.Adjustments, &Adjustment{Promotion: adj.Promotion, Code: adj.Code, Name: adj.Name, Amount: adj.Amount, Loyalty: adj.Loyalty})
+	}
+	for _, tax := range order.Taxes {
+		o.Taxes = append(o.Taxes, &Tax{Name: tax.Name, Code: tax.Code, Rate: tax.Rate, Base: tax.Base, Amount: tax.Amount, Inclusive: tax.Inclusive})
+	}
+	if len(order.Metadata) > 0 {
+		md, err := structpb.NewStruct(order.Metadata)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		o.Metadata = md
+	}
+	return o, nil
+}
+
+// toTimestamp returns the time of an order as a timestamp. Orders keep the
+// wall clock of the server that took them.
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	return timestamppb.New(t)
+}
@@ -0,0 +1,197 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	ordersgrpc "github.com/0x6flab/jikoniApp/BackendApp/orders/api/grpc"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	kitlog "github.com/go-kit/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// failingService fails to view every order with its error.
+type failingService struct {
+	orders.OrderService
+	err error
+}
+
+func (svc failingService) ViewOrder(ctx context.Context, token, id string) (orders.Order, error) {
+	return orders.Order{}, svc.err
+}
+
+func withToken(ctx context.Context) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestErrorStatus(t *testing.T) {
+	cases := []struct {
+		desc string
+		ctx  context.Context
+		id   string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{desc: "without a token", ctx: context.Background(), id: "order", code: codes.Unauthenticated, msg: errors.ErrBearerToken.Msg()},
+		{desc: "without an id", ctx: withToken(context.Background()), code: codes.InvalidArgument, msg: errors.ErrMissingID.Msg()},
+		{desc: "malformed", err: errors.ErrMalformedEntity, code: codes.InvalidArgument},
+		{desc: "invalid status", err: errors.ErrInvalidStatus, code: codes.InvalidArgument},
+		{desc: "not authenticated", err: errors.Wrap(errors.ErrAuthentication, errors.ErrNotFound), code: codes.Unauthenticated, msg: errors.ErrAuthentication.Msg()},
+		{desc: "not authorized", err: errors.ErrAuthorization, code: codes.PermissionDenied},
+		{desc: "not found", err: errors.Wrap(errors.ErrNotFound, errors.New("no rows")), code: codes.NotFound, msg: errors.ErrNotFound.Msg()},
+		{desc: "in conflict", err: errors.Wrap(orders.ErrOutstanding, errors.ErrConflict), code: codes.AlreadyExists},
+		{desc: "cancelled", err: context.Canceled, code: codes.Canceled},
+		{desc: "past its deadline", err: context.DeadlineExceeded, code: codes.DeadlineExceeded},
+		{desc: "failing otherwise", err: errors.ErrViewEntity, code: codes.Internal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.ctx == nil {
+				tc.ctx = withToken(context.Background())
+				tc.id = "order"
+			}
+			server := ordersgrpc.NewServer(failingService{err: tc.err}, nil, kitlog.NewNopLogger())
+			_, err := server.ViewOrder(tc.ctx, &ordersgrpc.ViewOrderReq{Id: tc.id})
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected a status error, got %v", err)
+			}
+			if st.Code() != tc.code {
+				t.Errorf("expected code %s, got %s", tc.code, st.Code())
+			}
+			if tc.msg != "" && st.Message() != tc.msg {
+				t.Errorf("expected message %q, got %q", tc.msg, st.Message())
+			}
+		})
+	}
+}
+
+// watchStream hands the events sent on it to the test.
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *ordersgrpc.OrderEvent
+}
+
+func (s watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s watchStream) Send(event *ordersgrpc.OrderEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestWatchOrders(t *testing.T) {
+	repo := memory.NewOrderRepo()
+	svc := orders.NewOrderService(repo)
+	server := ordersgrpc.NewServer(svc, repo, kitlog.NewNopLogger())
+
+	create := func(name string) string {
+		t.Helper()
+		id, err := svc.CreateOrder(context.Background(), token, orders.Order{Vendor: vendor, Name: name, Price: 100, Place: "inhouse", Status: orders.StatusOrdered})
+		if err != nil {
+			t.Fatalf("creating %s: unexpected error: %s", name, err)
+		}
+		return id
+	}
+	chapati := create("Chapati")
+	create("Chai")
+	if _, err := svc.CreateOrder(context.Background(), token, orders.Order{Vendor: "other", Name: "Mandazi", Price: 20, Place: "inhouse", Status: orders.StatusOrdered}); err != nil {
+		t.Fatalf("creating an order of another vendor: unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(withToken(context.Background()))
+	stream := watchStream{ctx: ctx, events: make(chan *ordersgrpc.OrderEvent, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchOrders(&ordersgrpc.WatchOrdersReq{Vendor: vendor}, stream)
+	}()
+	next := func() *ordersgrpc.OrderEvent {
+		t.Helper()
+		select {
+		case event := <-stream.events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return nil
+		}
+	}
+
+	// The changes there are come first, in the order they happened.
+	var seq uint64
+	for _, name := range []string{"Chapati", "Chai"} {
+		event := next()
+		if event.GetType() != ordersgrpc.OrderEvent_WRITTEN || event.GetOrder().GetName() != name {
+			t.Fatalf("expected %s to be written, got %v", name, event)
+		}
+		if event.GetSeq() <= seq {
+			t.Errorf("expected the sequence to grow past %d, got %d", seq, event.GetSeq())
+		}
+		seq = event.GetSeq()
+	}
+
+	// Then the changes as they happen.
+	create("Pilau")
+	if event := next(); event.GetType() != ordersgrpc.OrderEvent_WRITTEN || event.GetOrder().GetName() != "Pilau" {
+		t.Fatalf("expected Pilau to be written, got %v", event)
+	}
+	if err := svc.DeleteOrder(context.Background(), token, chapati); err != nil {
+		t.Fatalf("deleting: unexpected error: %s", err)
+	}
+	if event := next(); event.GetType() != ordersgrpc.OrderEvent_DELETED || event.GetOrder().GetId() != chapati {
+		t.Fatalf("expected Chapati to be deleted, got %v", event)
+	}
+
+	// The watch ends when the client goes away.
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected the watch to end without an error, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watch to end")
+	}
+	select {
+	case event := <-stream.events:
+		t.Errorf("expected no more events, got %v", event)
+	default:
+	}
+}
+
+func TestWatchOrdersValidation(t *testing.T) {
+	repo := memory.NewOrderRepo()
+	server := ordersgrpc.NewServer(orders.NewOrderService(repo), repo, kitlog.NewNopLogger())
+
+	cases := []struct {
+		desc   string
+		ctx    context.Context
+		vendor string
+		code   codes.Code
+	}{
+		{desc: "without a token", ctx: context.Background(), vendor: vendor, code: codes.Unauthenticated},
+		{desc: "without a vendor", ctx: withToken(context.Background()), code: codes.InvalidArgument},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			stream := watchStream{ctx: tc.ctx, events: make(chan *ordersgrpc.OrderEvent, 1)}
+			err := server.WatchOrders(&ordersgrpc.WatchOrdersReq{Vendor: tc.vendor}, stream)
+			if code := status.Code(err); code != tc.code {
+				t.Errorf("expected code %s, got %s", tc.code, code)
+			}
+		})
+	}
+}
@@ -94,3 +94,20 @@ func (lm *loggingMiddleware) DeleteOrder(ctx context.Context, token, id string)
 	return lm.svc.DeleteOrder(ctx, token, id)
 
 }
+
+func (lm *loggingMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (order orders.Order, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "record_payment",
+			"token", token,
+			"id", id,
+			"paid_with", method,
+			"amount", amount,
+			"tip", tip,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
@@ -67,3 +67,12 @@ func (ms *metricsMiddleware) DeleteOrder(ctx context.Context, token, id string)
 
 	return ms.svc.DeleteOrder(ctx, token, id)
 }
+
+func (ms *metricsMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_payment").Add(1)
+		ms.latency.With("method", "record_payment").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+const (
+	htmlContentType = "text/html; charset=utf-8"
+	openAPIPath     = "/openapi.json"
+	docsPath        = "/docs"
+	docsFilePath    = "/docs/{file}"
+)
+
+// openAPI is the OpenAPI document of the routes of MakeOrdersHandler.
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// swaggerUI holds the page browsing openAPI and the Swagger UI files it
+// loads, so the docs are served without reaching out to a CDN. The files
+// are Swagger UI 5.29.1, as distributed by github.com/swaggest/swgui
+// v1.8.5, under the license next to them.
+//
+//go:embed swagger
+var swaggerUI embed.FS
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPI)
+}
+
+func serveDocs(w http.ResponseWriter, _ *http.Request) {
+	page, err := swaggerUI.ReadFile("swagger/index.html")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", htmlContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(page)
+}
+
+func docsFiles() http.Handler {
+	files, err := fs.Sub(swaggerUI, "swagger")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix(docsPath+"/", http.FileServer(http.FS(files)))
+}
@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/api"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+// TestOpenAPI keeps the OpenAPI document from falling behind the routes
+// registered by MakeOrdersHandler. Routes without methods are taken to be
+// GET routes.
+func TestOpenAPI(t *testing.T) {
+	data, err := os.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatalf("reading the OpenAPI document: %s", err)
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %s", err)
+	}
+
+	r := mux.NewRouter()
+	api.MakeOrdersHandler(nil, r, kitlog.NewNopLogger())
+
+	err = r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet}
+		}
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+				t.Errorf("%s %s is missing from the OpenAPI document", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking the routes: %s", err)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	r := mux.NewRouter()
+	api.MakeOrdersHandler(nil, r, kitlog.NewNopLogger())
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{path: "/docs", status: http.StatusOK},
+		{path: "/docs/swagger-ui.css", status: http.StatusOK},
+		{path: "/docs/swagger-ui-bundle.js", status: http.StatusOK},
+		{path: "/docs/missing.js", status: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.status {
+			t.Errorf("GET %s answered %d, want %d", tc.path, w.Code, tc.status)
+		}
+		// The docs are served without reaching out to a CDN.
+		if strings.Contains(w.Body.String(), "unpkg.com") {
+			t.Errorf("GET %s loads files from unpkg.com", tc.path)
+		}
+	}
+}
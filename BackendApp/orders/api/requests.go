@@ -9,86 +9,79 @@ const (
 	maxLimitSize = 100
 )
 
-type createOrderReq struct {
-	order orders.Order
-	token string
+// CreateOrderReq is the request of CreateOrderEndpoint.
+type CreateOrderReq struct {
+	Token string
+	Order orders.Order
 }
 
-func (req createOrderReq) validate() error {
-	if req.token == "" {
+func (req CreateOrderReq) validate() error {
+	if req.Token == "" {
 		return errors.ErrBearerToken
 	}
-	return req.order.Validate()
+	return req.Order.Validate()
 }
 
-type viewOrderReq struct {
-	token string
-	id    string
+// ViewOrderReq is the request of ViewOrderEndpoint.
+type ViewOrderReq struct {
+	Token string
+	ID    string
 }
 
-func (req viewOrderReq) validate() error {
-	if req.token == "" {
+func (req ViewOrderReq) validate() error {
+	if req.Token == "" {
 		return errors.ErrBearerToken
 	}
-	if req.id == "" {
+	if req.ID == "" {
 		return errors.ErrMissingID
 	}
 	return nil
 }
 
-type listOrdersReq struct {
-	token  string
-	vendor string
-	name   string
-	price  uint64
-	place  string
-	status string
-	offset uint64
-	limit  uint64
-	total  uint64
+// ListOrdersReq is the request of ListOrdersEndpoint.
+type ListOrdersReq struct {
+	Token        string
+	PageMetadata orders.PageMetadata
 }
 
-func (req listOrdersReq) validate() error {
-	if req.token == "" {
+func (req ListOrdersReq) validate() error {
+	if req.Token == "" {
 		return errors.ErrBearerToken
 	}
-	if req.limit > maxLimitSize || req.limit < 1 {
+	if req.PageMetadata.Limit > maxLimitSize || req.PageMetadata.Limit < 1 {
 		return errors.ErrLimitSize
 	}
 	return nil
 }
 
-type updateOrderReq struct {
-	token    string
-	id       string
-	Vendor   string          `json:"vendor,omitempty"`
-	Name     string          `json:"name,omitempty"`
-	Price    uint64          `json:"price,omitempty"`
-	Place    string          `json:"place,omitempty"`
-	Status   string          `json:"status,omitempty"`
-	Metadata orders.Metadata `json:"metadata,omitempty"`
+// UpdateOrderReq is the request of UpdateOrderEndpoint. Only the fields
+// clients may change are taken from the order.
+type UpdateOrderReq struct {
+	Token string
+	Order orders.Order
 }
 
-func (req updateOrderReq) validate() error {
-	if req.token == "" {
+func (req UpdateOrderReq) validate() error {
+	if req.Token == "" {
 		return errors.ErrBearerToken
 	}
-	if req.id == "" {
+	if req.Order.ID == "" {
 		return errors.ErrMissingID
 	}
 	return nil
 }
 
-type deleteOrderReq struct {
-	token string
-	id    string
+// DeleteOrderReq is the request of DeleteOrderEndpoint.
+type DeleteOrderReq struct {
+	Token string
+	ID    string
 }
 
-func (req deleteOrderReq) validate() error {
-	if req.token == "" {
+func (req DeleteOrderReq) validate() error {
+	if req.Token == "" {
 		return errors.ErrBearerToken
 	}
-	if req.id == "" {
+	if req.ID == "" {
 		return errors.ErrMissingID
 	}
 	return nil
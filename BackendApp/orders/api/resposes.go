@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,11 +23,11 @@ type Response interface {
 
 var (
 	_ Response = (*tokenRes)(nil)
-	_ Response = (*createOrderRes)(nil)
-	_ Response = (*viewOrderRes)(nil)
-	_ Response = (*ordersPageRes)(nil)
-	_ Response = (*updateOrderRes)(nil)
-	_ Response = (*deleteOrderRes)(nil)
+	_ Response = (*CreateOrderRes)(nil)
+	_ Response = (*OrderRes)(nil)
+	_ Response = (*OrdersPageRes)(nil)
+	_ Response = (*UpdateOrderRes)(nil)
+	_ Response = (*DeleteOrderRes)(nil)
 )
 
 type pageRes struct {
@@ -51,112 +52,170 @@ func (res tokenRes) Empty() bool {
 	return res.Token == ""
 }
 
-type createOrderRes struct {
-	ID      string
-	created bool
+// CreateOrderRes is the response of CreateOrderEndpoint.
+type CreateOrderRes struct {
+	ID string
 }
 
-func (res createOrderRes) Code() int {
-	if res.created {
-		return http.StatusCreated
-	}
-	return http.StatusOK
+func (res CreateOrderRes) Code() int {
+	return http.StatusCreated
 }
 
-func (res createOrderRes) Headers() map[string]string {
-	if res.created {
-		return map[string]string{
-			"Location": fmt.Sprintf("/orders/%s", res.ID),
-		}
+func (res CreateOrderRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orders/%s", res.ID),
 	}
-	return map[string]string{}
 }
 
-func (res createOrderRes) Empty() bool {
+func (res CreateOrderRes) Empty() bool {
 	return true
 }
 
-type viewOrderRes struct {
-	ID        string          `json:"id"`
-	Vendor    string          `json:"vendor"`
-	Name      string          `json:"name"`
-	Price     uint64          `json:"price,omitempty"`
-	Place     string          `json:"place,omitempty"`
-	Status    string          `json:"status,omitempty"`
-	Metadata  orders.Metadata `json:"metadata,omitempty"`
-	UpdatedAt time.Time       `json:"updated_at,omitempty"`
-	CreatedAt time.Time       `json:"created_at,omitempty"`
-}
-
-func (res viewOrderRes) Code() int {
+// OrderRes is the response of ViewOrderEndpoint. It is written to HTTP
+// clients as a viewOrderRes.
+type OrderRes struct {
+	Order orders.Order
+}
+
+func (res OrderRes) Code() int {
 	return http.StatusOK
 }
 
-func (res viewOrderRes) Headers() map[string]string {
+func (res OrderRes) Headers() map[string]string {
 	return map[string]string{}
 }
 
-func (res viewOrderRes) Empty() bool {
+func (res OrderRes) Empty() bool {
 	return false
 }
 
-type ordersPageRes struct {
-	pageRes
-	Orders []viewOrderRes `json:"orders"`
+// MarshalJSON encodes the order with the totals HTTP clients are sent.
+func (res OrderRes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newViewOrderRes(res.Order))
 }
 
-func (res ordersPageRes) Code() int {
+type viewOrderRes struct {
+	ID          string              `json:"id"`
+	Vendor      string              `json:"vendor"`
+	Name        string              `json:"name"`
+	Price       uint64              `json:"price,omitempty"`
+	Place       string              `json:"place,omitempty"`
+	Status      string              `json:"status,omitempty"`
+	Items       []orders.Item       `json:"items,omitempty"`
+	Adjustments []orders.Adjustment `json:"adjustments,omitempty"`
+	Gross       uint64              `json:"gross"`
+	Discount    uint64              `json:"discount,omitempty"`
+	Taxes       []orders.Tax        `json:"taxes,omitempty"`
+	Tax         uint64              `json:"tax,omitempty"`
+	Paid        uint64              `json:"paid"`
+	Tips        uint64              `json:"tips,omitempty"`
+	Balance     uint64              `json:"balance"`
+	Metadata    orders.Metadata     `json:"metadata,omitempty"`
+	UpdatedAt   time.Time           `json:"updated_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at,omitempty"`
+}
+
+func newViewOrderRes(order orders.Order) viewOrderRes {
+	return viewOrderRes{
+		ID:          order.ID,
+		Vendor:      order.Vendor,
+		Name:        order.Name,
+		Price:       order.Price,
+		Place:       order.Place,
+		Status:      order.Status,
+		Items:       order.Items,
+		Adjustments: order.Adjustments,
+		Gross:       order.Gross(),
+		Discount:    order.Discount(),
+		Taxes:       order.Taxes,
+		Tax:         order.TaxTotal(),
+		Paid:        order.Paid,
+		Tips:        order.Tips,
+		Balance:     order.Balance(),
+		Metadata:    order.Metadata,
+		CreatedAt:   order.CreatedAt,
+		UpdatedAt:   order.UpdatedAt,
+	}
+}
+
+// OrdersPageRes is the response of ListOrdersEndpoint. It is written to
+// HTTP clients as an ordersPageRes.
+type OrdersPageRes struct {
+	Page orders.OrdersPage
+}
+
+func (res OrdersPageRes) Code() int {
 	return http.StatusOK
 }
 
-func (res ordersPageRes) Headers() map[string]string {
+func (res OrdersPageRes) Headers() map[string]string {
 	return map[string]string{}
 }
 
-func (res ordersPageRes) Empty() bool {
+func (res OrdersPageRes) Empty() bool {
 	return false
 }
 
-type updateOrderRes struct {
-	ID      string
-	updated bool
+// MarshalJSON encodes the page with the totals of its orders.
+func (res OrdersPageRes) MarshalJSON() ([]byte, error) {
+	page := ordersPageRes{
+		pageRes: pageRes{
+			Total:  res.Page.Total,
+			Offset: res.Page.Offset,
+			Limit:  res.Page.Limit,
+		},
+		Orders: []viewOrderRes{},
+	}
+	for _, order := range res.Page.Orders {
+		page.Orders = append(page.Orders, newViewOrderRes(order))
+	}
+	return json.Marshal(page)
+}
+
+type ordersPageRes struct {
+	pageRes
+	Orders []viewOrderRes `json:"orders"`
+}
+
+// UpdateOrderRes is the response of UpdateOrderEndpoint.
+type UpdateOrderRes struct {
+	ID string
 }
 
-func (res updateOrderRes) Code() int {
+func (res UpdateOrderRes) Code() int {
 	return http.StatusOK
 }
 
-func (res updateOrderRes) Headers() map[string]string {
-	if res.updated {
-		return map[string]string{
-			"Location": fmt.Sprintf("/orders/%s", res.ID),
-		}
+func (res UpdateOrderRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orders/%s", res.ID),
 	}
-	return map[string]string{}
 }
 
-func (res updateOrderRes) Empty() bool {
+func (res UpdateOrderRes) Empty() bool {
 	return true
 }
 
-type deleteOrderRes struct {
-	ID      string
-	deleted bool
+// DeleteOrderRes is the response of DeleteOrderEndpoint.
+type DeleteOrderRes struct {
+	ID string
 }
 
-func (res deleteOrderRes) Code() int {
+func (res DeleteOrderRes) Code() int {
 	return http.StatusNoContent
 }
 
-func (res deleteOrderRes) Headers() map[string]string {
-	if res.deleted {
-		return map[string]string{
-			"Location": fmt.Sprintf("/orders/%s", res.ID),
-		}
+func (res DeleteOrderRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orders/%s", res.ID),
 	}
-	return map[string]string{}
 }
 
-func (res deleteOrderRes) Empty() bool {
+func (res DeleteOrderRes) Empty() bool {
 	return true
 }
+
+type healthRes struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
 	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
@@ -19,15 +20,24 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	offsetKey   = "offset"
-	limitKey    = "limit"
-	totalKey    = "total"
-	vendorKey   = "vendor"
-	nameKey     = "name"
-	priceKey    = "price"
-	placeKey    = "place"
-	statusKey   = "status"
+	contentType    = "application/json"
+	offsetKey      = "offset"
+	limitKey       = "limit"
+	totalKey       = "total"
+	vendorKey      = "vendor"
+	nameKey        = "name"
+	priceKey       = "price"
+	placeKey       = "place"
+	statusKey      = "status"
+	outstandingKey = "outstanding"
+	fromKey        = "from"
+	toKey          = "to"
+	metadataKey    = "metadata"
+
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	healthPath  = "/health"
+	serviceName = "jikoni-orders"
 )
 
 // MakeOrdersHandler returns a HTTP handler for API endpoints.
@@ -40,41 +50,45 @@ func MakeOrdersHandler(svc orders.OrderService, r *mux.Router, logger kitlog.Log
 	}
 
 	r.Methods("POST").Path("/orders").Handler(kithttp.NewServer(
-		kitoc.TraceEndpoint("gokit:endpoint create_order")(createOrderEndpoint(svc)),
+		kitoc.TraceEndpoint("gokit:endpoint create_order")(CreateOrderEndpoint(svc)),
 		decodeCreateOrder,
 		encodeResponse,
-		opts...,
+		append(opts, kithttp.ServerBefore(idempotencyKeyToContext))...,
 	))
 
 	r.Methods("GET").Path("/orders/{id}").Handler(kithttp.NewServer(
-		kitoc.TraceEndpoint("gokit:endpoint view_order")(viewOrderEndpoint(svc)),
+		kitoc.TraceEndpoint("gokit:endpoint view_order")(ViewOrderEndpoint(svc)),
 		decodeViewOrder,
 		encodeResponse,
 		opts...,
 	))
 
 	r.Methods("GET").Path("/orders").Handler(kithttp.NewServer(
-		kitoc.TraceEndpoint("gokit:endpoint list_orders")(listOrdersEndpoint(svc)),
+		kitoc.TraceEndpoint("gokit:endpoint list_orders")(ListOrdersEndpoint(svc)),
 		decodeListOrders,
 		encodeResponse,
 		opts...,
 	))
 
 	r.Methods("PUT").Path("/orders/{id}").Handler(kithttp.NewServer(
-		kitoc.TraceEndpoint("gokit:endpoint update_order")(updateOrderEndpoint(svc)),
+		kitoc.TraceEndpoint("gokit:endpoint update_order")(UpdateOrderEndpoint(svc)),
 		decodeUpdateOrder,
 		encodeResponse,
 		opts...,
 	))
 
 	r.Methods("DELETE").Path("/orders/{id}").Handler(kithttp.NewServer(
-		kitoc.TraceEndpoint("gokit:endpoint delete_order")(deleteOrderEndpoint(svc)),
+		kitoc.TraceEndpoint("gokit:endpoint delete_order")(DeleteOrderEndpoint(svc)),
 		decodeDeleteOrder,
 		encodeResponse,
 		opts...,
 	))
 
+	r.Methods("GET").Path(healthPath).HandlerFunc(serveHealth)
 	r.Path("/metrics").Handler(promhttp.Handler())
+	r.Methods("GET").Path(openAPIPath).HandlerFunc(serveOpenAPI)
+	r.Methods("GET").Path(docsPath).HandlerFunc(serveDocs)
+	r.Methods("GET").Path(docsFilePath).Handler(docsFiles())
 }
 
 func decodeCreateOrder(_ context.Context, r *http.Request) (interface{}, error) {
@@ -85,17 +99,33 @@ func decodeCreateOrder(_ context.Context, r *http.Request) (interface{}, error)
 	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
 		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
 	}
-	req := createOrderReq{
-		order: order,
-		token: decodeToken(r),
+	// Discounts come from promotions and taxes from the vendor's tax rates,
+	// not from the client.
+	order.Adjustments = nil
+	order.Taxes = nil
+	// What was paid and who acted on the order are recorded by the server.
+	order.Paid, order.Tips = 0, 0
+	order.CreatedBy, order.AcceptedBy, order.PaidBy = "", "", ""
+	req := CreateOrderReq{
+		Token: decodeToken(r),
+		Order: order,
 	}
 	return req, nil
 }
 
+// idempotencyKeyToContext carries the idempotency key of the request, so a
+// retried create gets the order created the first time.
+func idempotencyKeyToContext(ctx context.Context, r *http.Request) context.Context {
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		return orders.WithIdempotencyKey(ctx, key)
+	}
+	return ctx
+}
+
 func decodeViewOrder(_ context.Context, r *http.Request) (interface{}, error) {
-	req := viewOrderReq{
-		token: decodeToken(r),
-		id:    mux.Vars(r)["id"],
+	req := ViewOrderReq{
+		Token: decodeToken(r),
+		ID:    mux.Vars(r)["id"],
 	}
 	return req, nil
 }
@@ -109,6 +139,9 @@ func decodeListOrders(_ context.Context, r *http.Request) (interface{}, error) {
 	var name = ""
 	var place = ""
 	var status = ""
+	var outstanding = false
+	var from, to time.Time
+	var metadata orders.Metadata
 	var err error
 
 	if r.URL.Query().Has(offsetKey) {
@@ -147,39 +180,77 @@ func decodeListOrders(_ context.Context, r *http.Request) (interface{}, error) {
 	if r.URL.Query().Has(statusKey) {
 		status = r.URL.Query().Get(statusKey)
 	}
-	req := listOrdersReq{
-		token:  decodeToken(r),
-		offset: offset,
-		limit:  limit,
-		total:  total,
-		vendor: vendor,
-		name:   name,
-		price:  price,
-		place:  place,
-		status: status,
+	if r.URL.Query().Has(outstandingKey) {
+		outstanding, err = strconv.ParseBool(r.URL.Query().Get(outstandingKey))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.URL.Query().Has(fromKey) {
+		from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(metadataKey) {
+		if err := json.Unmarshal([]byte(r.URL.Query().Get(metadataKey)), &metadata); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	req := ListOrdersReq{
+		Token: decodeToken(r),
+		PageMetadata: orders.PageMetadata{
+			Offset:      offset,
+			Limit:       limit,
+			Total:       total,
+			Vendor:      vendor,
+			Name:        name,
+			Price:       price,
+			Place:       place,
+			Status:      status,
+			Outstanding: outstanding,
+			From:        from,
+			To:          to,
+			Metadata:    metadata,
+		},
 	}
 	return req, nil
 }
 
 func decodeUpdateOrder(_ context.Context, r *http.Request) (interface{}, error) {
-	req := updateOrderReq{
-		token: decodeToken(r),
-		id:    mux.Vars(r)["id"],
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	var order orders.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
 		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
 	}
+	order.ID = mux.Vars(r)["id"]
+	req := UpdateOrderReq{
+		Token: decodeToken(r),
+		Order: order,
+	}
 	return req, nil
 }
 
 func decodeDeleteOrder(_ context.Context, r *http.Request) (interface{}, error) {
-	req := deleteOrderReq{
-		token: decodeToken(r),
-		id:    mux.Vars(r)["id"],
+	req := DeleteOrderReq{
+		Token: decodeToken(r),
+		ID:    mux.Vars(r)["id"],
 	}
 	return req, nil
 }
 
+// serveHealth tells load balancers and operators that the service is up.
+func serveHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(healthRes{Status: "pass", Service: serviceName})
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	if ar, ok := response.(Response); ok {
 		for k, v := range ar.Headers() {
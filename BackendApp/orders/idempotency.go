@@ -0,0 +1,69 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+var _ OrderService = (*idempotencyMiddleware)(nil)
+
+type idempotencyMiddleware struct {
+	svc    OrderService
+	orders OrderRepository
+}
+
+// IdempotencyMiddleware answers a create retried with the idempotency key of
+// an order created before with that order. It is meant to wrap the
+// middlewares pricing orders, so a retry is answered before promotions and
+// loyalty points are redeemed again.
+func IdempotencyMiddleware(svc OrderService, orders OrderRepository) OrderService {
+	return &idempotencyMiddleware{
+		svc:    svc,
+		orders: orders,
+	}
+}
+
+func (im *idempotencyMiddleware) CreateOrder(ctx context.Context, token string, order Order) (string, error) {
+	key := IdempotencyKey(ctx)
+	if key == "" {
+		return im.svc.CreateOrder(ctx, token, order)
+	}
+	id, err := im.orders.RetrieveByKey(ctx, key)
+	switch {
+	case err == nil:
+		return id, nil
+	case !errors.Contains(err, errors.ErrNotFound):
+		return "", err
+	}
+
+	id, err = im.svc.CreateOrder(ctx, token, order)
+	// A request racing another with the same key gets the other's order,
+	// what it redeemed was given back as its order was not saved.
+	if id == "" && errors.Contains(err, errors.ErrConflict) {
+		if oid, rerr := im.orders.RetrieveByKey(ctx, key); rerr == nil {
+			return oid, nil
+		}
+	}
+	return id, err
+}
+
+func (im *idempotencyMiddleware) ViewOrder(ctx context.Context, token, id string) (Order, error) {
+	return im.svc.ViewOrder(ctx, token, id)
+}
+
+func (im *idempotencyMiddleware) ListOrders(ctx context.Context, token string, page PageMetadata) (OrdersPage, error) {
+	return im.svc.ListOrders(ctx, token, page)
+}
+
+func (im *idempotencyMiddleware) UpdateOrder(ctx context.Context, token string, order Order) (string, error) {
+	return im.svc.UpdateOrder(ctx, token, order)
+}
+
+func (im *idempotencyMiddleware) DeleteOrder(ctx context.Context, token, id string) error {
+	return im.svc.DeleteOrder(ctx, token, id)
+}
+
+func (im *idempotencyMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (Order, error) {
+	return im.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
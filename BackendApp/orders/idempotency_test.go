@@ -0,0 +1,159 @@
+package orders_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/loyalty"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// loyaltySvc pays for discounts with points, counting what is spent.
+type loyaltySvc struct {
+	loyalty.Service
+	mu    sync.Mutex
+	spent uint64
+}
+
+func (svc *loyaltySvc) Redeem(ctx context.Context, token string, order orders.Order, points uint64) ([]orders.Adjustment, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.spent += points
+	return []orders.Adjustment{{Name: "Points", Amount: points, Loyalty: "entry"}}, nil
+}
+
+func (svc *loyaltySvc) Refund(ctx context.Context, token string, adjustments []orders.Adjustment) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	for _, adj := range adjustments {
+		svc.spent -= adj.Amount
+	}
+	return nil
+}
+
+// promoSvc takes 20 off orders with a promo code, counting its uses.
+type promoSvc struct {
+	promotions.Service
+	mu   sync.Mutex
+	uses int
+}
+
+func (svc *promoSvc) Quote(ctx context.Context, token string, basket promotions.Basket) ([]orders.Adjustment, error) {
+	return []orders.Adjustment{{Promotion: "promo", Code: basket.Code, Name: "Opening week", Amount: 20}}, nil
+}
+
+func (svc *promoSvc) Redeem(ctx context.Context, token string, basket promotions.Basket, adjustments []orders.Adjustment) (string, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.uses++
+	return "ref", nil
+}
+
+func (svc *promoSvc) Assign(ctx context.Context, token, ref, orderID string) error {
+	return nil
+}
+
+func (svc *promoSvc) Release(ctx context.Context, token, ref string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.uses--
+	return nil
+}
+
+func newCheckout() (orders.OrderService, *loyaltySvc, *promoSvc) {
+	repo := memory.NewOrderRepo()
+	points, promos := &loyaltySvc{}, &promoSvc{}
+	svc := orders.NewOrderService(repo)
+	svc = loyalty.RedemptionMiddleware(svc, points)
+	svc = promotions.PricingMiddleware(svc, promos)
+	svc = orders.IdempotencyMiddleware(svc, repo)
+	return svc, points, promos
+}
+
+func newOrder() orders.Order {
+	return orders.Order{
+		Vendor: vendor,
+		Name:   "Chapati",
+		Price:  100,
+		Place:  "inhouse",
+		Status: orders.StatusOrdered,
+		Metadata: orders.Metadata{
+			orders.CustomerKey:     "254700000000",
+			orders.PromoCodeKey:    "KARIBU",
+			orders.RedeemPointsKey: float64(30),
+		},
+	}
+}
+
+func TestIdempotentCheckout(t *testing.T) {
+	svc, points, promos := newCheckout()
+	ctx := orders.WithIdempotencyKey(context.Background(), "checkout-1")
+
+	id, err := svc.CreateOrder(ctx, token, newOrder())
+	if err != nil {
+		t.Fatalf("creating order: %s", err)
+	}
+	retried, err := svc.CreateOrder(ctx, token, newOrder())
+	if err != nil {
+		t.Fatalf("retrying order: %s", err)
+	}
+	if retried != id {
+		t.Errorf("retry created order %s, want %s", retried, id)
+	}
+	if points.spent != 30 {
+		t.Errorf("spent %d points, want 30", points.spent)
+	}
+	if promos.uses != 1 {
+		t.Errorf("promo code used %d times, want once", promos.uses)
+	}
+
+	order, err := svc.ViewOrder(ctx, token, id)
+	if err != nil {
+		t.Fatalf("viewing order: %s", err)
+	}
+	if order.Price != 50 {
+		t.Errorf("order priced %d, want 50", order.Price)
+	}
+}
+
+func TestIdempotentCheckoutRace(t *testing.T) {
+	svc, points, promos := newCheckout()
+	ctx := orders.WithIdempotencyKey(context.Background(), "checkout-1")
+
+	// Retries racing the first request get its order, what they redeemed is
+	// given back.
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	errs := make([]error, len(ids))
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i], errs[i] = svc.CreateOrder(ctx, token, newOrder())
+		}(i)
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Fatalf("creating order: %s", errs[i])
+		}
+		if id != ids[0] {
+			t.Errorf("created orders %s and %s with the same key", ids[0], id)
+		}
+	}
+	if points.spent != 30 {
+		t.Errorf("spent %d points, want 30", points.spent)
+	}
+	if promos.uses != 1 {
+		t.Errorf("promo code used %d times, want once", promos.uses)
+	}
+}
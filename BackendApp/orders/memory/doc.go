@@ -0,0 +1,4 @@
+// Package memory contains repository implementations keeping their data in
+// memory, for tests and deployments that can afford to lose their orders on
+// restart.
+package memory
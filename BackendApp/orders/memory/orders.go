@@ -0,0 +1,543 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"go.uber.org/multierr"
+)
+
+var _ orders.OrderRepository = (*orderRepo)(nil)
+
+type orderRepo struct {
+	mu         sync.RWMutex
+	seq        uint64 // The change sequence of the last write.
+	orders     map[string]orders.Order
+	keys       map[string]string // Idempotency keys by the orders they created.
+	payments   map[string][]orders.Payment
+	paymentIDs map[string]bool
+	tombstones map[string]orders.Tombstone
+	// Transitions left to notify, oldest first.
+	transitions    []orders.Transition
+	lastTransition uint64
+}
+
+// NewOrderRepo instantiates an in-memory implementation of the orders
+// repository. It is safe for concurrent use.
+func NewOrderRepo() orders.OrderRepository {
+	return &orderRepo{
+		orders:     make(map[string]orders.Order),
+		keys:       make(map[string]string),
+		payments:   make(map[string][]orders.Payment),
+		paymentIDs: make(map[string]bool),
+		tombstones: make(map[string]orders.Tombstone),
+	}
+}
+
+func (repo *orderRepo) Save(ctx context.Context, order orders.Order) (string, error) {
+	order, err := clone(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	// Payments are only ever added to a saved order.
+	order.Paid, order.Tips = 0, 0
+
+	defer repo.lock(ctx)()
+
+	if _, ok := repo.orders[order.ID]; ok {
+		return "", errors.ErrConflict
+	}
+	repo.insert(order)
+	return order.ID, nil
+}
+
+func (repo *orderRepo) SaveWithKey(ctx context.Context, order orders.Order, key string) (string, error) {
+	order, err := clone(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	order.Paid, order.Tips = 0, 0
+
+	defer repo.lock(ctx)()
+
+	if _, ok := repo.orders[order.ID]; ok {
+		return "", errors.ErrConflict
+	}
+	if _, ok := repo.keys[key]; ok {
+		return "", errors.Wrap(errors.ErrConflict, errors.New("idempotency key already used"))
+	}
+	repo.insert(order)
+	repo.keys[key] = order.ID
+	return order.ID, nil
+}
+
+func (repo *orderRepo) RetrieveByKey(ctx context.Context, key string) (string, error) {
+	defer repo.rlock(ctx)()
+
+	id, ok := repo.keys[key]
+	if !ok {
+		return "", errors.Wrap(errors.ErrNotFound, errors.New("idempotency key not found"))
+	}
+	return id, nil
+}
+
+func (repo *orderRepo) SaveMany(ctx context.Context, os []orders.Order) ([]string, error) {
+	saved := make([]orders.Order, 0, len(os))
+	ids := make([]string, 0, len(os))
+	for _, order := range os {
+		order, err := clone(order)
+		if err != nil {
+			return nil, multierr.Combine(errors.ErrCreateEntity, err)
+		}
+		saved = append(saved, order)
+		ids = append(ids, order.ID)
+	}
+
+	defer repo.lock(ctx)()
+
+	// Nothing is saved unless every order can be.
+	seen := make(map[string]bool, len(saved))
+	for _, order := range saved {
+		if _, ok := repo.orders[order.ID]; ok || seen[order.ID] {
+			return nil, errors.ErrConflict
+		}
+		seen[order.ID] = true
+	}
+	for _, order := range saved {
+		repo.insert(order)
+	}
+	return ids, nil
+}
+
+func (repo *orderRepo) RetrieveByID(ctx context.Context, id string) (orders.Order, error) {
+	defer repo.rlock(ctx)()
+
+	order, ok := repo.orders[id]
+	if !ok {
+		return orders.Order{}, errors.ErrNotFound
+	}
+	return clone(order)
+}
+
+func (repo *orderRepo) RetrieveAll(ctx context.Context, pm orders.PageMetadata) (orders.OrdersPage, error) {
+	defer repo.rlock(ctx)()
+
+	var matched []orders.Order
+	for _, order := range repo.orders {
+		ok, err := matches(order, pm)
+		if err != nil {
+			return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		if ok {
+			matched = append(matched, order)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	total := uint64(len(matched))
+	start, end := pm.Offset, pm.Offset+pm.Limit
+	if start > total {
+		start = total
+	}
+	if end > total || end < start {
+		end = total
+	}
+	var items []orders.Order
+	for _, order := range matched[start:end] {
+		order, err := clone(order)
+		if err != nil {
+			return orders.OrdersPage{}, err
+		}
+		items = append(items, order)
+	}
+	page := orders.OrdersPage{
+		Orders: items,
+		PageMetadata: orders.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo *orderRepo) Update(ctx context.Context, order orders.Order) (string, error) {
+	update, err := clone(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+
+	defer repo.lock(ctx)()
+
+	current, ok := repo.orders[order.ID]
+	if !ok {
+		return "", errors.ErrNotFound
+	}
+	before := current
+	// Orders stay with the vendor they were taken for, their changes are
+	// read by vendor.
+	if order.Name != "" {
+		current.Name = order.Name
+	}
+	if order.Price != 0 {
+		current.Price = order.Price
+	}
+	if order.Place != "" {
+		current.Place = order.Place
+	}
+	if order.Status != "" {
+		current.Status = order.Status
+	}
+	if order.Items != nil {
+		current.Items = update.Items
+	}
+	if order.Metadata != nil {
+		current.Metadata = update.Metadata
+	}
+	// The first staff member to accept or settle the order keeps it.
+	if current.AcceptedBy == "" {
+		current.AcceptedBy = order.AcceptedBy
+	}
+	if current.PaidBy == "" {
+		current.PaidBy = order.PaidBy
+	}
+	if err := orders.ValidatePaid(before, current); err != nil {
+		return "", err
+	}
+	current.UpdatedAt = order.UpdatedAt
+	current.Seq = repo.next()
+	repo.orders[order.ID] = current
+	repo.transition(before, current)
+	return order.ID, nil
+}
+
+func (repo *orderRepo) Delete(ctx context.Context, id string) error {
+	defer repo.lock(ctx)()
+
+	order, ok := repo.orders[id]
+	if !ok {
+		return nil
+	}
+	delete(repo.orders, id)
+	for key, keyed := range repo.keys {
+		if keyed == id {
+			delete(repo.keys, key)
+		}
+	}
+	repo.tombstones[id] = orders.Tombstone{
+		ID:        id,
+		Vendor:    order.Vendor,
+		Seq:       repo.next(),
+		DeletedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+func (repo *orderRepo) AddPayment(ctx context.Context, payment orders.Payment) (orders.Order, error) {
+	defer repo.lock(ctx)()
+
+	order, ok := repo.orders[payment.Order]
+	if !ok {
+		return orders.Order{}, errors.ErrNotFound
+	}
+	before := order
+	if repo.paymentIDs[payment.ID] {
+		return orders.Order{}, errors.Wrap(errors.ErrConflict, errors.New("payment already added"))
+	}
+	if order.Paid < order.Price && order.Paid+payment.Amount >= order.Price {
+		order.PaidBy = payment.PaidBy
+	}
+	if order.Paid+payment.Amount >= order.Price {
+		order.Status = orders.StatusPaid
+	}
+	order.Paid += payment.Amount
+	order.Tips += payment.Tip
+	order.UpdatedAt = payment.CreatedAt
+	order.Seq = repo.next()
+	repo.orders[order.ID] = order
+	repo.transition(before, order)
+
+	payment.Vendor = order.Vendor
+	repo.payments[order.ID] = append(repo.payments[order.ID], payment)
+	repo.paymentIDs[payment.ID] = true
+	return clone(order)
+}
+
+func (repo *orderRepo) RetrieveChanges(ctx context.Context, vendor string, since, limit uint64) (orders.ChangesPage, error) {
+	defer repo.rlock(ctx)()
+
+	var written []orders.Order
+	for _, order := range repo.orders {
+		if order.Vendor == vendor && order.Seq > since {
+			written = append(written, order)
+		}
+	}
+	sort.Slice(written, func(i, j int) bool { return written[i].Seq < written[j].Seq })
+	var deleted []orders.Tombstone
+	for _, t := range repo.tombstones {
+		if t.Vendor == vendor && t.Seq > since {
+			deleted = append(deleted, t)
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].Seq < deleted[j].Seq })
+
+	// Both lists are in the order of their changes, the page takes the
+	// first changes of either.
+	page := orders.ChangesPage{Seq: since}
+	i, j := 0, 0
+	for n := uint64(0); n < limit && (i < len(written) || j < len(deleted)); n++ {
+		if j == len(deleted) || (i < len(written) && written[i].Seq < deleted[j].Seq) {
+			order, err := clone(written[i])
+			if err != nil {
+				return orders.ChangesPage{}, err
+			}
+			page.Orders = append(page.Orders, order)
+			page.Seq = order.Seq
+			i++
+			continue
+		}
+		page.Deleted = append(page.Deleted, deleted[j])
+		page.Seq = deleted[j].Seq
+		j++
+	}
+	page.More = i < len(written) || j < len(deleted)
+	return page, nil
+}
+
+func (repo *orderRepo) RetrieveTombstone(ctx context.Context, id string) (orders.Tombstone, error) {
+	defer repo.rlock(ctx)()
+
+	t, ok := repo.tombstones[id]
+	if !ok {
+		return orders.Tombstone{}, errors.ErrNotFound
+	}
+	return t, nil
+}
+
+func (repo *orderRepo) RetrievePayments(ctx context.Context, ids []string) ([]orders.Payment, error) {
+	defer repo.rlock(ctx)()
+
+	var payments []orders.Payment
+	for _, id := range ids {
+		payments = append(payments, repo.payments[id]...)
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].CreatedAt.Equal(payments[j].CreatedAt) {
+			return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+		}
+		return payments[i].ID < payments[j].ID
+	})
+	return payments, nil
+}
+
+func (repo *orderRepo) RetrieveTransitions(ctx context.Context, after, limit uint64) ([]orders.Transition, error) {
+	defer repo.rlock(ctx)()
+
+	var transitions []orders.Transition
+	for _, t := range repo.transitions {
+		if uint64(len(transitions)) == limit {
+			break
+		}
+		if t.ID > after {
+			transitions = append(transitions, t)
+		}
+	}
+	return transitions, nil
+}
+
+func (repo *orderRepo) RemoveTransition(ctx context.Context, id uint64) error {
+	defer repo.lock(ctx)()
+
+	for i, t := range repo.transitions {
+		if t.ID == id {
+			repo.transitions = append(repo.transitions[:i], repo.transitions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (repo *orderRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Transactions run within one keep the lock it holds, and only undo
+	// what they wrote.
+	if ctx.Value(txKey{}) != repo {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, repo)
+	}
+	restore := repo.snapshot()
+	if err := fn(ctx); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// snapshot returns how to bring the repository back to how it is now.
+// Orders and payments are never changed in place, they are replaced, so it
+// keeps copies of the maps holding them. The lock must be held.
+func (repo *orderRepo) snapshot() func() {
+	seq, last := repo.seq, repo.lastTransition
+	saved := make(map[string]orders.Order, len(repo.orders))
+	for k, v := range repo.orders {
+		saved[k] = v
+	}
+	keys := make(map[string]string, len(repo.keys))
+	for k, v := range repo.keys {
+		keys[k] = v
+	}
+	payments := make(map[string][]orders.Payment, len(repo.payments))
+	for k, v := range repo.payments {
+		payments[k] = v
+	}
+	paymentIDs := make(map[string]bool, len(repo.paymentIDs))
+	for k, v := range repo.paymentIDs {
+		paymentIDs[k] = v
+	}
+	tombstones := make(map[string]orders.Tombstone, len(repo.tombstones))
+	for k, v := range repo.tombstones {
+		tombstones[k] = v
+	}
+	transitions := append([]orders.Transition(nil), repo.transitions...)
+	return func() {
+		repo.seq, repo.lastTransition = seq, last
+		repo.orders, repo.keys, repo.tombstones = saved, keys, tombstones
+		repo.payments, repo.paymentIDs = payments, paymentIDs
+		repo.transitions = transitions
+	}
+}
+
+// txKey keys the repository whose transaction the context runs in.
+type txKey struct{}
+
+// lock takes the write lock and returns how to release it. Writes run in a
+// transaction already hold it.
+func (repo *orderRepo) lock(ctx context.Context) func() {
+	if ctx.Value(txKey{}) == repo {
+		return func() {}
+	}
+	repo.mu.Lock()
+	return repo.mu.Unlock
+}
+
+// rlock takes the read lock and returns how to release it. Reads run in a
+// transaction already hold the write lock.
+func (repo *orderRepo) rlock(ctx context.Context) func() {
+	if ctx.Value(txKey{}) == repo {
+		return func() {}
+	}
+	repo.mu.RLock()
+	return repo.mu.RUnlock
+}
+
+// insert saves the order as its latest change. Saving an order clears the
+// tombstone of a deleted order with the same id. The lock must be held.
+func (repo *orderRepo) insert(order orders.Order) {
+	order.Seq = repo.next()
+	repo.orders[order.ID] = order
+	delete(repo.tombstones, order.ID)
+	repo.transition(orders.Order{}, order)
+}
+
+// transition records the order moving to preparing or paid, if the write
+// from before to after moved it there. The lock must be held.
+func (repo *orderRepo) transition(before, after orders.Order) {
+	if after.Status == before.Status || (after.Status != orders.StatusPreparing && after.Status != orders.StatusPaid) {
+		return
+	}
+	repo.lastTransition++
+	repo.transitions = append(repo.transitions, orders.Transition{
+		ID:     repo.lastTransition,
+		Order:  after.ID,
+		Status: after.Status,
+	})
+}
+
+// next returns the change sequence of a new write. The lock must be held.
+func (repo *orderRepo) next() uint64 {
+	repo.seq++
+	return repo.seq
+}
+
+func matches(order orders.Order, pm orders.PageMetadata) (bool, error) {
+	switch {
+	case pm.Vendor != "" && order.Vendor != pm.Vendor,
+		pm.Name != "" && order.Name != pm.Name,
+		pm.Price != 0 && order.Price != pm.Price,
+		pm.Place != "" && order.Place != pm.Place,
+		pm.Status != "" && order.Status != pm.Status,
+		pm.Outstanding && order.Paid >= order.Price,
+		!pm.From.IsZero() && order.CreatedAt.Before(pm.From),
+		!pm.To.IsZero() && !order.CreatedAt.Before(pm.To),
+		pm.After.ID != "" && !listedAfter(order, pm.After):
+		return false, nil
+	}
+	if len(pm.Metadata) == 0 {
+		return true, nil
+	}
+	return order.Metadata.Contains(pm.Metadata)
+}
+
+// listedAfter reports whether the order is listed after the cursor.
+func listedAfter(order orders.Order, after orders.Cursor) bool {
+	if !order.CreatedAt.Equal(after.CreatedAt) {
+		return order.CreatedAt.After(after.CreatedAt)
+	}
+	return order.ID > after.ID
+}
+
+// clone returns a copy of the order sharing nothing with it. The lines and
+// metadata are copied through JSON, as the postgres repository stores them,
+// so orders read back the same whichever repository kept them.
+func clone(order orders.Order) (orders.Order, error) {
+	items := []orders.Item{}
+	if len(order.Items) > 0 {
+		if err := roundTrip(order.Items, &items); err != nil {
+			return orders.Order{}, err
+		}
+	}
+	adjustments := []orders.Adjustment{}
+	if len(order.Adjustments) > 0 {
+		if err := roundTrip(order.Adjustments, &adjustments); err != nil {
+			return orders.Order{}, err
+		}
+	}
+	taxes := []orders.Tax{}
+	if len(order.Taxes) > 0 {
+		if err := roundTrip(order.Taxes, &taxes); err != nil {
+			return orders.Order{}, err
+		}
+	}
+	metadata := orders.Metadata{}
+	if len(order.Metadata) > 0 {
+		if err := roundTrip(order.Metadata, &metadata); err != nil {
+			return orders.Order{}, err
+		}
+	}
+	order.Items = items
+	order.Adjustments = adjustments
+	order.Taxes = taxes
+	order.Metadata = metadata
+	return order, nil
+}
+
+func roundTrip(v interface{}, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/repotest"
+)
+
+func TestOrderRepo(t *testing.T) {
+	repotest.Run(t, memory.NewOrderRepo())
+}
@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"go.uber.org/multierr"
+)
+
+// notifyBatch is how many transitions are read at a time.
+const notifyBatch = 100
+
+// Notifier notifies hooks of the transitions recorded by the orders
+// repository. A transition is removed once every hook has been notified of
+// it, one a hook failed on is left to be notified again, every hook
+// included.
+type Notifier struct {
+	orders OrderRepository
+	hooks  []Hook
+}
+
+// NewNotifier returns a notifier of the transitions of the repository to
+// the hooks.
+func NewNotifier(orders OrderRepository, hooks ...Hook) Notifier {
+	return Notifier{
+		orders: orders,
+		hooks:  hooks,
+	}
+}
+
+// Notify notifies the hooks of the transitions recorded so far, oldest
+// first, and returns the errors of those left, combined. The hooks are
+// given the order as it is when they are notified. Transitions of orders
+// since deleted are dropped.
+func (n Notifier) Notify(ctx context.Context, token string) error {
+	var errs error
+	var after uint64
+	for {
+		transitions, err := n.orders.RetrieveTransitions(ctx, after, notifyBatch)
+		if err != nil {
+			return multierr.Append(errs, err)
+		}
+		for _, t := range transitions {
+			after = t.ID
+			if err := n.notify(ctx, token, t); err != nil {
+				errs = multierr.Append(errs, err)
+				continue
+			}
+			if err := n.orders.RemoveTransition(ctx, t.ID); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+		if len(transitions) < notifyBatch {
+			return errs
+		}
+	}
+}
+
+func (n Notifier) notify(ctx context.Context, token string, t Transition) error {
+	order, err := n.orders.RetrieveByID(ctx, t.Order)
+	if err != nil {
+		if notFound(err) {
+			return nil
+		}
+		return err
+	}
+	for _, hook := range n.hooks {
+		var err error
+		switch t.Status {
+		case StatusPreparing:
+			err = hook.OrderPreparing(ctx, token, order)
+		case StatusPaid:
+			err = hook.OrderPaid(ctx, token, order)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notFound reports whether err is ErrNotFound, alone or combined with the
+// error of the database.
+func notFound(err error) bool {
+	for _, err := range multierr.Errors(err) {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return true
+		}
+	}
+	return false
+}
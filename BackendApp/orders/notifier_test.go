@@ -0,0 +1,109 @@
+package orders_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+)
+
+var errHook = errors.New("hook failed")
+
+// recordingHook keeps what it is notified of, failing while fail is set.
+type recordingHook struct {
+	fail      bool
+	preparing []string
+	paid      []string
+}
+
+func (hook *recordingHook) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	if hook.fail {
+		return errHook
+	}
+	hook.preparing = append(hook.preparing, order.ID)
+	return nil
+}
+
+func (hook *recordingHook) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	if hook.fail {
+		return errHook
+	}
+	hook.paid = append(hook.paid, order.ID)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOrderRepo()
+	svc := orders.NewOrderService(repo)
+	hook := &recordingHook{}
+	notifier := orders.NewNotifier(repo, hook)
+
+	order := orders.Order{Vendor: vendor, Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered}
+	id, err := svc.CreateOrder(ctx, token, order)
+	if err != nil {
+		t.Fatalf("creating: %s", err)
+	}
+	if _, err := svc.UpdateOrder(ctx, token, orders.Order{ID: id, Status: orders.StatusPreparing}); err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	// Notifying is left to the notifier, the write only records it.
+	if len(hook.preparing) != 0 {
+		t.Fatalf("hook notified by the write")
+	}
+
+	// A transition the hook failed on is kept until it is notified.
+	hook.fail = true
+	if err := notifier.Notify(ctx, token); !errors.Contains(err, errHook) {
+		t.Fatalf("notifying a failing hook returned %v, want %s", err, errHook)
+	}
+	hook.fail = false
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying: %s", err)
+	}
+	if len(hook.preparing) != 1 || hook.preparing[0] != id {
+		t.Errorf("hook notified of preparing %v, want %v", hook.preparing, []string{id})
+	}
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying again: %s", err)
+	}
+	if len(hook.preparing) != 1 {
+		t.Errorf("hook notified of preparing %d times, want once", len(hook.preparing))
+	}
+
+	// Only the payment settling the order moves it to paid.
+	for _, amount := range []uint64{60, 40} {
+		if _, err := svc.RecordPayment(ctx, token, id, "cash", amount, 0); err != nil {
+			t.Fatalf("recording payment: %s", err)
+		}
+	}
+	if _, err := svc.RecordPayment(ctx, token, id, "cash", 0, 10); err != nil {
+		t.Fatalf("recording tip: %s", err)
+	}
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying: %s", err)
+	}
+	if len(hook.paid) != 1 || hook.paid[0] != id {
+		t.Errorf("hook notified of paid %v, want %v", hook.paid, []string{id})
+	}
+
+	// Orders deleted before the hooks were notified of them are dropped.
+	gone, err := svc.CreateOrder(ctx, token, orders.Order{Vendor: vendor, Name: "Pilau", Price: 100, Place: "inhouse", Status: orders.StatusPreparing})
+	if err != nil {
+		t.Fatalf("creating: %s", err)
+	}
+	if err := svc.DeleteOrder(ctx, token, gone); err != nil {
+		t.Fatalf("deleting: %s", err)
+	}
+	if err := notifier.Notify(ctx, token); err != nil {
+		t.Fatalf("notifying: %s", err)
+	}
+	if len(hook.preparing) != 1 {
+		t.Errorf("hook notified of a deleted order")
+	}
+	if left, err := repo.RetrieveTransitions(ctx, 0, 10); err != nil || len(left) != 0 {
+		t.Errorf("transitions %v left after notifying them (%v)", left, err)
+	}
+}
@@ -2,32 +2,196 @@ package orders
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
 )
 
+// ErrOutstanding indicates marking an order paid while some of its price
+// is left to pay. Orders are settled by recording payments against them.
+var ErrOutstanding = errors.New("order has a balance outstanding")
+
 // Places describes where the order was placed or is being taken.
 var Places = []string{"inhouse", "delivery"}
 
-// Statuses describe the payment status of the order.
-var Statuses = []string{"ordered", "paid"}
+// Statuses of an order.
+const (
+	StatusOrdered   = "ordered"
+	StatusPreparing = "preparing"
+	StatusPaid      = "paid"
+)
+
+// Statuses describe how far along the order is, from being ordered through
+// the kitchen preparing it to being paid.
+var Statuses = []string{StatusOrdered, StatusPreparing, StatusPaid}
+
+// Metadata keys that channels other than the HTTP API use to describe who
+// placed the order and from where.
+const (
+	CustomerKey     = "customer"
+	ChannelKey      = "channel"
+	PromoCodeKey    = "promo_code"
+	RedeemPointsKey = "redeem_points"
+)
 
 // Metadata to be used for customized
 // describing of particular Order.
 type Metadata map[string]interface{}
 
+// Item is a single line of an order i.e. 2 cups of tea.
+type Item struct {
+	ID       string `json:"id,omitempty"`       // The menu item identifier if the line was ordered from a menu.
+	Name     string `json:"name,omitempty"`     // The name of the ordered good.
+	Quantity uint64 `json:"quantity,omitempty"` // How many units of the good were ordered.
+	Price    uint64 `json:"price,omitempty"`    // The price of a single unit of the good.
+}
+
+// Total returns the price of the item line.
+func (item Item) Total() uint64 {
+	return item.Quantity * item.Price
+}
+
+// Adjustment is a discount taken off the price of an order. Discounts are
+// kept as lines of their own so the gross price can always be told apart
+// from what was charged.
+type Adjustment struct {
+	Promotion string `json:"promotion,omitempty"` // The promotion that granted the discount.
+	Code      string `json:"code,omitempty"`      // The promo code redeemed, if any.
+	Name      string `json:"name,omitempty"`      // The name shown on the receipt i.e. "Happy hour".
+	Amount    uint64 `json:"amount"`              // How much was taken off the price.
+	Loyalty   string `json:"loyalty,omitempty"`   // The loyalty ledger entry that paid for the discount, if any.
+}
+
+// Tax is a tax or charge levied on an order, one per rate applied. Rates
+// are in hundredths of a percent so 16% VAT is 1600.
+type Tax struct {
+	Name      string `json:"name,omitempty"`      // The name printed on the invoice i.e. "VAT 16%".
+	Code      string `json:"code,omitempty"`      // The tax band of the rate i.e. "B" for standard rated VAT.
+	Rate      uint64 `json:"rate"`                // The rate in hundredths of a percent.
+	Base      uint64 `json:"base"`                // The taxable amount, excluding the tax.
+	Amount    uint64 `json:"amount"`              // The tax charged.
+	Inclusive bool   `json:"inclusive,omitempty"` // Whether the tax is contained in the item prices.
+}
+
 // Order this represents the order to be made by a person to the shop.
 type Order struct {
-	ID        string    `json:"id,omitempty"`
-	Vendor    string    `json:"vendor,omitempty"`     // The name of the vendor os the product i.e shop.
-	Name      string    `json:"name,omitempty"`       // The name of the order good.
-	Price     uint64    `json:"price,omitempty"`      // This is the price of the order.
-	Place     string    `json:"place,omitempty"`      // This is the place where the order was served. It is either inhouse or delivery.
-	Status    string    `json:"status,omitempty"`     // This is the payment status. It is either paid or ordered.
-	Metadata  Metadata  `json:"metadata,omitempty"`   // Metadata contains extra information about the order.
-	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the order was updated.
-	CreatedAt time.Time `json:"created_at,omitempty"` // When the order was created in the system.
+	ID          string       `json:"id,omitempty"`
+	Vendor      string       `json:"vendor,omitempty"`      // The name of the vendor os the product i.e shop.
+	Name        string       `json:"name,omitempty"`        // The name of the order good.
+	Price       uint64       `json:"price,omitempty"`       // This is the price of the order.
+	Place       string       `json:"place,omitempty"`       // This is the place where the order was served. It is either inhouse or delivery.
+	Status      string       `json:"status,omitempty"`      // This is the status of the order. It is ordered, preparing or paid.
+	Items       []Item       `json:"items,omitempty"`       // Items are the lines of the order when more than one good was ordered.
+	Adjustments []Adjustment `json:"adjustments,omitempty"` // Discounts taken off the gross price.
+	Taxes       []Tax        `json:"taxes,omitempty"`       // Taxes and charges levied on the net price.
+	Paid        uint64       `json:"paid,omitempty"`        // How much of the price has been paid so far.
+	Tips        uint64       `json:"tips,omitempty"`        // Tips left on top of the price.
+	Metadata    Metadata     `json:"metadata,omitempty"`    // Metadata contains extra information about the order.
+	CreatedBy   string       `json:"created_by,omitempty"`  // The staff member who took the order.
+	AcceptedBy  string       `json:"accepted_by,omitempty"` // The staff member who sent the order to the kitchen.
+	PaidBy      string       `json:"paid_by,omitempty"`     // The staff member who took the payment that settled the order.
+	UpdatedAt   time.Time    `json:"updated_at,omitempty"`  // When the order was updated.
+	CreatedAt   time.Time    `json:"created_at,omitempty"`  // When the order was created in the system.
+	Seq         uint64       `json:"seq,omitempty"`         // The change sequence of the last write to the order, set by the repository.
+}
+
+// Payment is money taken against an order.
+type Payment struct {
+	ID        string    `json:"id"`
+	Order     string    `json:"order_id"`
+	Vendor    string    `json:"vendor"`
+	Method    string    `json:"method,omitempty"` // How the payment was made i.e. cash or mpesa.
+	Amount    uint64    `json:"amount"`
+	Tip       uint64    `json:"tip,omitempty"`
+	PaidBy    string    `json:"paid_by,omitempty"` // The staff member who took the payment.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tombstone is what is left of a deleted order, so the deletion can be
+// told apart from an order that never was.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	Vendor    string    `json:"vendor"`
+	Seq       uint64    `json:"seq"` // The change sequence of the deletion.
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Transition is an order moving to a status the hooks are notified of,
+// recorded by the repository in the write that moved it so the hooks are
+// notified of it however the write was made.
+type Transition struct {
+	ID     uint64 // Increases with every transition recorded.
+	Order  string
+	Status string
+}
+
+// ChangesPage contains the orders written and deleted after a change
+// sequence, in the order of their changes.
+type ChangesPage struct {
+	Orders  []Order
+	Deleted []Tombstone
+	Seq     uint64 // The change sequence of the last change of the page.
+	More    bool   // Whether changes were left out of the page.
+}
+
+type staffKey struct{}
+
+// WithStaff returns a copy of ctx carrying the staff member acting on
+// orders, the order service attributes what is done to the orders to them.
+func WithStaff(ctx context.Context, member string) context.Context {
+	return context.WithValue(ctx, staffKey{}, member)
+}
+
+// Staff returns the staff member acting on orders in ctx, if any.
+func Staff(ctx context.Context) string {
+	member, _ := ctx.Value(staffKey{}).(string)
+	return member
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying the idempotency key of a
+// request creating an order. Creating an order again with the same key
+// through IdempotencyMiddleware returns the order created the first time
+// instead of a new one.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKey returns the idempotency key in ctx, if any.
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+type paymentKey struct{}
+
+// WithPaymentKey returns a copy of ctx carrying the key of a payment.
+// Recording a payment again with the same key records nothing and returns
+// the order as it is, so a payment that may have been recorded can be
+// retried safely.
+func WithPaymentKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, paymentKey{}, key)
+}
+
+// PaymentKey returns the payment key in ctx, if any.
+func PaymentKey(ctx context.Context) string {
+	key, _ := ctx.Value(paymentKey{}).(string)
+	return key
+}
+
+// Hook is notified when an order changes state, by a Notifier once the
+// change is committed. A hook may be notified of a change more than once and
+// must only act on it once.
+type Hook interface {
+	// OrderPreparing is called once the kitchen starts preparing an order.
+	OrderPreparing(ctx context.Context, token string, order Order) error
+
+	// OrderPaid is called once an order has been paid in full.
+	OrderPaid(ctx context.Context, token string, order Order) error
 }
 
 // OrderService. This describes the methods an Order undergo.
@@ -47,19 +211,40 @@ type OrderService interface {
 	ListOrders(ctx context.Context, token string, pm PageMetadata) (OrdersPage, error)
 
 	// UpdateOrder updates the name, prices, metadata, place and status
-	// for a given order by its unique identifier ID.
+	// for a given order by its unique identifier ID. Only the first staff
+	// member to accept or settle the order is kept.
 	UpdateOrder(ctx context.Context, token string, p Order) (string, error)
 
 	// DeleteOrder deletes the order for a give unique identifier ID.
 	DeleteOrder(ctx context.Context, token string, id string) error
+
+	// RecordPayment settles amount of the order's balance and adds tip to
+	// its tips, paid with method i.e. cash or mpesa. The order is marked
+	// paid once nothing is outstanding.
+	RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (Order, error)
 }
 
-// OrderRepository specifies an account persistence API.
+// OrderRepository specifies an account persistence API. Every write that
+// moves an order to preparing or paid, saving an order in either status
+// included, records a Transition along with the order.
 type OrderRepository interface {
 	// Save persists the Order. A non-nil error is returned to indicate
 	// operation failure.
 	Save(ctx context.Context, order Order) (string, error)
 
+	// SaveWithKey persists the Order along with the idempotency key it was
+	// created with, in a single transaction. Saving a key that was saved
+	// before returns ErrConflict and saves nothing.
+	SaveWithKey(ctx context.Context, order Order, key string) (string, error)
+
+	// RetrieveByKey retrieves the unique identifier of the order created
+	// with the idempotency key.
+	RetrieveByKey(ctx context.Context, key string) (string, error)
+
+	// SaveMany persists the orders as they are, payments included, in a
+	// single transaction. Either all of the orders are saved or none is.
+	SaveMany(ctx context.Context, orders []Order) ([]string, error)
+
 	// RetrieveByID retrieves Order by its unique identifier ID.
 	RetrieveByID(ctx context.Context, id string) (Order, error)
 
@@ -67,11 +252,46 @@ type OrderRepository interface {
 	RetrieveAll(ctx context.Context, pm PageMetadata) (OrdersPage, error)
 
 	// Update updates the name, prices, metadata, place and status
-	// for a given order by its unique identifier ID.
+	// for a given order by its unique identifier ID. Orders stay with the
+	// vendor they were taken for.
 	Update(ctx context.Context, p Order) (string, error)
 
 	// Delete deletes the order
 	Delete(ctx context.Context, id string) error
+
+	// AddPayment atomically adds the payment amount to the paid total and
+	// its tip to the tips of the order, marking it paid by the staff member
+	// who took the payment once the price is covered. The payment is kept
+	// with the vendor of the order. Adding a payment whose ID was added
+	// before returns ErrConflict and adds nothing.
+	AddPayment(ctx context.Context, payment Payment) (Order, error)
+
+	// RetrieveChanges retrieves up to limit changes of the vendor's orders
+	// with a change sequence greater than since. An order changed many
+	// times appears once, with its last change.
+	RetrieveChanges(ctx context.Context, vendor string, since, limit uint64) (ChangesPage, error)
+
+	// RetrieveTombstone retrieves what is left of a deleted order by its
+	// unique identifier ID.
+	RetrieveTombstone(ctx context.Context, id string) (Tombstone, error)
+
+	// RetrievePayments retrieves the payments taken against the orders with
+	// the unique identifiers ids, oldest first.
+	RetrievePayments(ctx context.Context, ids []string) ([]Payment, error)
+
+	// RetrieveTransitions retrieves up to limit of the transitions left
+	// with an ID greater than after, oldest first.
+	RetrieveTransitions(ctx context.Context, after, limit uint64) ([]Transition, error)
+
+	// RemoveTransition removes the transition with the ID once the hooks
+	// have been notified of it.
+	RemoveTransition(ctx context.Context, id uint64) error
+
+	// InTransaction runs fn in a single transaction, which is committed if
+	// fn returns nil and rolled back otherwise. What is written with the
+	// context fn is given is only seen by others once it is committed.
+	// Transactions run within fn's context join the outer one.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // Validate returns an error if order representation is invalid.
@@ -85,6 +305,80 @@ func (order Order) Validate() error {
 	return nil
 }
 
+// ItemsTotal returns the sum of all the item lines of the order.
+func (order Order) ItemsTotal() uint64 {
+	var total uint64
+	for _, item := range order.Items {
+		total += item.Total()
+	}
+	return total
+}
+
+// Discount returns the sum of the adjustments of the order.
+func (order Order) Discount() uint64 {
+	var discount uint64
+	for _, adj := range order.Adjustments {
+		discount += adj.Amount
+	}
+	return discount
+}
+
+// Gross returns the price of the order before discounts.
+func (order Order) Gross() uint64 {
+	return order.Price + order.Discount()
+}
+
+// TaxTotal returns the sum of the taxes of the order.
+func (order Order) TaxTotal() uint64 {
+	var total uint64
+	for _, tax := range order.Taxes {
+		total += tax.Amount
+	}
+	return total
+}
+
+// ExclusiveTax returns the taxes charged on top of the item prices.
+func (order Order) ExclusiveTax() uint64 {
+	var total uint64
+	for _, tax := range order.Taxes {
+		if !tax.Inclusive {
+			total += tax.Amount
+		}
+	}
+	return total
+}
+
+// Balance returns how much of the price is still outstanding.
+func (order Order) Balance() uint64 {
+	if order.Paid >= order.Price {
+		return 0
+	}
+	return order.Price - order.Paid
+}
+
+// ValidatePaid returns ErrOutstanding, as a conflict, if writing the order
+// as after over before marks it paid, or changes the price of a paid order,
+// while some of the price is left to pay.
+func ValidatePaid(before, after Order) error {
+	if after.Status != StatusPaid || after.Balance() == 0 {
+		return nil
+	}
+	if before.Status != StatusPaid || before.Price != after.Price {
+		return errors.Wrap(ErrOutstanding, errors.ErrConflict)
+	}
+	return nil
+}
+
+// ItemsName returns a human readable summary of the item lines
+// i.e. "2 x Chapati, 1 x Tea".
+func (order Order) ItemsName() string {
+	names := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		names = append(names, fmt.Sprintf("%d x %s", item.Quantity, item.Name))
+	}
+	return strings.Join(names, ", ")
+}
+
 // ValidatePlaces check if the order place is acceptable
 func ValidatePlaces(order string) bool {
 	for _, place := range Places {
@@ -104,3 +398,64 @@ func ValidateStatus(order string) bool {
 	}
 	return false
 }
+
+// Contains reports whether the metadata contains other the way listing
+// orders filters them: objects contain the keys of other with values
+// containing theirs, arrays contain every element of other's array, and
+// other values are equal.
+func (metadata Metadata) Contains(other Metadata) (bool, error) {
+	var m, o interface{}
+	// Both sides are compared as they are stored, as JSON.
+	if err := roundTrip(metadata, &m); err != nil {
+		return false, err
+	}
+	if err := roundTrip(other, &o); err != nil {
+		return false, err
+	}
+	return contains(m, o), nil
+}
+
+func contains(v, other interface{}) bool {
+	switch other := other.(type) {
+	case map[string]interface{}:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, ov := range other {
+			mv, ok := m[k]
+			if !ok || !contains(mv, ov) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, ov := range other {
+			found := false
+			for _, av := range a {
+				if contains(av, ov) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return v == other
+	}
+}
+
+func roundTrip(v interface{}, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
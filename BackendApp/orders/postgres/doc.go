@@ -1,3 +1,11 @@
 // Package postgres contains repository implementations using postgres as the
 // underlying database.
+//
+// The package also runs on CockroachDB, picked with the engine of the
+// config. Its migrations replace the statements CockroachDB does not
+// support, and hash the keys of the orders and their payments: the keys are
+// ULIDs, which sort by time and would have every new row written to the same
+// range. CockroachDB runs every transaction serializably and aborts the ones
+// that conflict, so the repository runs its transactions again when they
+// fail to serialize.
 package postgres
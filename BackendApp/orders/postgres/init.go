@@ -3,15 +3,27 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 	_ "github.com/jackc/pgx/v4/stdlib" // required for SQL access
 	"github.com/jmoiron/sqlx"
-	migrate "github.com/rubenv/sql-migrate"
 )
 
+// Engines the package connects to. CockroachDB speaks the PostgreSQL wire
+// protocol, so both are reached through the same driver; the engine picks
+// the migrations and how they are locked.
+const (
+	EnginePostgres  = "postgres"
+	EngineCockroach = "cockroach"
+)
+
+// dsnQuoter escapes a value of a connection string to be quoted.
+var dsnQuoter = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
 // Config defines the options that are used when connecting to a PostgreSQL instance
 type Config struct {
+	Engine      string // EnginePostgres if empty.
 	Host        string
 	Port        string
 	User        string
@@ -27,7 +39,26 @@ type Config struct {
 // unappeased database migrations. A non-nil error is returned to indicate
 // failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateDB(db, cfg.Engine); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Open creates a connection to the PostgreSQL instance, leaving its
+// migrations as they are.
+func Open(cfg Config) (*sqlx.DB, error) {
+	if err := validateEngine(cfg.Engine); err != nil {
+		return nil, err
+	}
+	// Values are quoted so empty ones, such as the password of the root user
+	// of an insecure CockroachDB, do not swallow the next key.
+	q := dsnQuoter.Replace
+	url := fmt.Sprintf("host='%s' port='%s' user='%s' dbname='%s' password='%s' sslmode='%s' sslcert='%s' sslkey='%s' sslrootcert='%s'", q(cfg.Host), q(cfg.Port), q(cfg.User), q(cfg.Name), q(cfg.Pass), q(cfg.SSLMode), q(cfg.SSLCert), q(cfg.SSLKey), q(cfg.SSLRootCert))
 
 	// Register default views.
 	ocsql.RegisterAllViews()
@@ -43,39 +74,16 @@ func Connect(cfg Config) (*sqlx.DB, error) {
 		return nil, err
 	}
 	// Wrap our *sql.DB with sqlx. use the original db driver name!!!
-	dbx := sqlx.NewDb(db, "pgx")
-	if err := migrateDB(dbx); err != nil {
-		return nil, err
-	}
-
-	return dbx, nil
+	return sqlx.NewDb(db, "pgx"), nil
 }
 
-func migrateDB(db *sqlx.DB) error {
-	migrations := &migrate.MemoryMigrationSource{
-		Migrations: []*migrate.Migration{
-			{
-				Id: "jikoni_1",
-				Up: []string{
-					`CREATE TABLE IF NOT EXISTS orders (
-						id 			VARCHAR(254) NOT NULL PRIMARY KEY,
-						vendor 		VARCHAR(254) NOT NULL,
-						name        VARCHAR(254) NOT NULL,
-						price		SMALLINT NOT NULL,
-						place	    VARCHAR(20),
-						status      VARCHAR(20),
-						metadata    JSONB,
-						created_at  TIMESTAMP DEFAULT now(),
-						updated_at  TIMESTAMP DEFAULT now()
-					)`,
-				},
-				Down: []string{
-					`DROP TABLE IF EXISTS orders`,
-				},
-			},
-		},
+// validateEngine returns an error if engine is not one the package connects
+// to. The empty engine is PostgreSQL.
+func validateEngine(engine string) error {
+	switch engine {
+	case "", EnginePostgres, EngineCockroach:
+		return nil
+	default:
+		return fmt.Errorf("unknown database engine %q, want %s or %s", engine, EnginePostgres, EngineCockroach)
 	}
-
-	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
-	return err
 }
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/multierr"
+)
+
+// lockChanges takes the change locks of the vendors. Transactions writing
+// orders take the locks of their vendors before numbering their changes,
+// so the changes of a vendor commit in the order they are numbered and
+// readers paging through them by number never skip a change committed
+// late.
+func lockChanges(ctx context.Context, tx *sqlx.Tx, vendors ...string) error {
+	keys := make([]string, len(vendors))
+	for i, vendor := range vendors {
+		keys[i] = "order_changes:" + vendor
+	}
+	if err := pgutil.Lock(ctx, tx, keys...); err != nil {
+		return multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	return nil
+}
+
+// lockChangesOf takes the change lock of the vendor of the order with the
+// unique identifier id. Orders stay with the vendor they were taken for.
+func lockChangesOf(ctx context.Context, tx *sqlx.Tx, id string) error {
+	q := `SELECT vendor FROM orders WHERE id = $1`
+
+	var vendor string
+	if err := tx.QueryRowxContext(ctx, q, id).Scan(&vendor); err != nil {
+		if err == sql.ErrNoRows {
+			return multierr.Combine(errors.ErrNotFound, err)
+		}
+		return multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return lockChanges(ctx, tx, vendor)
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// migrationLock is the key of the advisory lock held while migrating, so
+// replicas started together migrate one after the other. On CockroachDB,
+// which has no advisory locks, it is the key of the row of the lock table
+// locked instead.
+const migrationLock int64 = 0x6a696b6f6e69 // "jikoni"
+
+// migrationFiles are the migrations of the database, a file each named
+// after its version. Migrations are applied in the order of their versions
+// and rolled back in reverse. Each file has its up and down statements, see
+// https://github.com/rubenv/sql-migrate#writing-migrations.
+//
+// The files of migrations/cockroach replace the migrations of the same
+// version on CockroachDB, where their statements are not supported or
+// their tables are better laid out otherwise.
+//
+//go:embed migrations/*.sql migrations/cockroach/*.sql
+var migrationFiles embed.FS
+
+// legacyMigrations maps the ids migrations were recorded under when they
+// were kept in code to their files, so databases migrated back then are
+// recognised as migrated. Only the first migration was ever kept in code.
+var legacyMigrations = map[string]string{
+	"jikoni_1": "0001_orders.sql",
+}
+
+// Migration is a migration of the database and when it was applied.
+type Migration struct {
+	ID        string    `json:"id"`
+	AppliedAt time.Time `json:"applied_at"` // Zero if the migration has not been applied.
+}
+
+// Migrate applies up to max migrations of the engine in the direction, all
+// of them if max is zero, and returns how many it applied. Migrating down
+// rolls back the last applied migrations first.
+func Migrate(db *sqlx.DB, engine string, dir migrate.MigrationDirection, max int) (int, error) {
+	var n int
+	err := withMigrationLock(db, engine, func() error {
+		var err error
+		n, err = migrate.ExecMax(db.DB, "postgres", migrations(engine), dir, max)
+		return err
+	})
+	return n, err
+}
+
+// Redo rolls back the last applied migration of the engine and applies it
+// again, and returns its id.
+func Redo(db *sqlx.DB, engine string) (string, error) {
+	var id string
+	err := withMigrationLock(db, engine, func() error {
+		ms, err := Migrations(db, engine)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			if !m.AppliedAt.IsZero() {
+				id = m.ID
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("no migration has been applied")
+		}
+		if _, err := migrate.ExecMax(db.DB, "postgres", migrations(engine), migrate.Down, 1); err != nil {
+			return err
+		}
+		_, err = migrate.ExecMax(db.DB, "postgres", migrations(engine), migrate.Up, 1)
+		return err
+	})
+	return id, err
+}
+
+// Migrations returns the migrations of the database of the engine in the
+// order they are applied, with when they were.
+func Migrations(db *sqlx.DB, engine string) ([]Migration, error) {
+	ms, err := migrations(engine).FindMigrations()
+	if err != nil {
+		return nil, err
+	}
+	records, err := migrate.GetMigrationRecords(db.DB, "postgres")
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]time.Time, len(records))
+	for _, r := range records {
+		id := r.Id
+		if legacy, ok := legacyMigrations[id]; ok {
+			id = legacy
+		}
+		applied[id] = r.AppliedAt
+	}
+	status := make([]Migration, 0, len(ms))
+	for _, m := range ms {
+		status = append(status, Migration{ID: m.Id, AppliedAt: applied[m.Id]})
+	}
+	return status, nil
+}
+
+func migrateDB(db *sqlx.DB, engine string) error {
+	_, err := Migrate(db, engine, migrate.Up, 0)
+	return err
+}
+
+func migrations(engine string) migrate.MigrationSource {
+	if engine == EngineCockroach {
+		return cockroachMigrations{}
+	}
+	return migrationDir("migrations")
+}
+
+func migrationDir(name string) migrate.MigrationSource {
+	dir, err := fs.Sub(migrationFiles, name)
+	if err != nil {
+		// The directory is embedded, it cannot be missing.
+		panic(err)
+	}
+	return migrate.HttpFileSystemMigrationSource{FileSystem: http.FS(dir)}
+}
+
+// cockroachMigrations are the migrations with the CockroachDB files in place
+// of theirs. CockroachDB does not support schema changes mixed with the
+// statements using them in a transaction, so each statement runs on its
+// own; the statements are written to be run again if one fails.
+type cockroachMigrations struct{}
+
+func (cockroachMigrations) FindMigrations() ([]*migrate.Migration, error) {
+	ms, err := migrationDir("migrations").FindMigrations()
+	if err != nil {
+		return nil, err
+	}
+	variants, err := migrationDir("migrations/cockroach").FindMigrations()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(ms))
+	for i, m := range ms {
+		index[m.Id] = i
+	}
+	for _, v := range variants {
+		i, ok := index[v.Id]
+		if !ok {
+			return nil, fmt.Errorf("cockroach migration %s replaces no migration", v.Id)
+		}
+		ms[i] = v
+	}
+	for _, m := range ms {
+		m.DisableTransactionUp = true
+		m.DisableTransactionDown = true
+	}
+	return ms, nil
+}
+
+// withMigrationLock runs fn holding the migration lock of the engine, once
+// the legacy migration records have been renamed after their files.
+func withMigrationLock(db *sqlx.DB, engine string, fn func() error) error {
+	if engine == EngineCockroach {
+		// The database was never migrated by the legacy migrations.
+		return withCockroachLock(db, fn)
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The lock is held by the session of the connection, and waited for
+	// until the replica holding it is done.
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLock); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLock)
+
+	if err := renameLegacyMigrations(ctx, db); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// withCockroachLock runs fn holding the row of the lock table, written and
+// left locked by a transaction open until fn is done. The transaction of a
+// replica that goes away is aborted with its connection, which releases the
+// lock.
+func withCockroachLock(db *sqlx.DB, fn func() error) error {
+	ctx := context.Background()
+	q := `CREATE TABLE IF NOT EXISTS jikoni_migration_lock (id BIGINT NOT NULL PRIMARY KEY)`
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Writing the row waits for the replica holding it to be done.
+	if _, err := tx.ExecContext(ctx, "UPSERT INTO jikoni_migration_lock (id) VALUES ($1)", migrationLock); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	return fn()
+}
+
+func renameLegacyMigrations(ctx context.Context, db *sqlx.DB) error {
+	var migrated bool
+	if err := db.GetContext(ctx, &migrated, "SELECT to_regclass('gorp_migrations') IS NOT NULL"); err != nil {
+		return err
+	}
+	if !migrated {
+		return nil
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for old, id := range legacyMigrations {
+		if _, err := tx.ExecContext(ctx, "UPDATE gorp_migrations SET id = $1 WHERE id = $2", id, old); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS orders (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name        VARCHAR(254) NOT NULL,
+	price		SMALLINT NOT NULL,
+	place	    VARCHAR(20),
+	status      VARCHAR(20),
+	metadata    JSONB,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,19 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS items JSONB;
+
+CREATE TABLE IF NOT EXISTS menu_items (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name        VARCHAR(254) NOT NULL,
+	category    VARCHAR(254),
+	price		BIGINT NOT NULL,
+	available   BOOLEAN NOT NULL DEFAULT TRUE,
+	metadata    JSONB,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS menu_items;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS items;
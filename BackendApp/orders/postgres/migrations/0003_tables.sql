@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS dining_tables (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	number      BIGINT NOT NULL,
+	area        VARCHAR(254),
+	seats       BIGINT NOT NULL DEFAULT 0,
+	qr_code     VARCHAR(254) NOT NULL UNIQUE,
+	metadata    JSONB,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now(),
+	UNIQUE (vendor, number)
+);
+
+CREATE TABLE IF NOT EXISTS table_sessions (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	table_id    VARCHAR(254) NOT NULL REFERENCES dining_tables (id) ON DELETE CASCADE,
+	guests      BIGINT NOT NULL DEFAULT 0,
+	state       VARCHAR(20) NOT NULL,
+	order_ids   JSONB NOT NULL DEFAULT '[]',
+	merged_into VARCHAR(254),
+	opened_at   TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now(),
+	closed_at   TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS table_sessions_active ON table_sessions (table_id) WHERE state IN ('open', 'billing');
+
+-- +migrate Down
+DROP TABLE IF EXISTS table_sessions;
+
+DROP TABLE IF EXISTS dining_tables;
@@ -0,0 +1,43 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS paid BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tips BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS bill_splits (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	order_ids   JSONB NOT NULL DEFAULT '[]',
+	method      VARCHAR(20) NOT NULL,
+	state       VARCHAR(20) NOT NULL,
+	total       BIGINT NOT NULL,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS bill_shares (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	split_id    VARCHAR(254) NOT NULL REFERENCES bill_splits (id) ON DELETE CASCADE,
+	vendor 		VARCHAR(254) NOT NULL,
+	position    INTEGER NOT NULL,
+	label       VARCHAR(254) NOT NULL DEFAULT '',
+	lines       JSONB NOT NULL DEFAULT '[]',
+	amount      BIGINT NOT NULL,
+	allocations JSONB NOT NULL DEFAULT '{}',
+	tip         BIGINT NOT NULL DEFAULT 0,
+	staff       VARCHAR(254) NOT NULL DEFAULT '',
+	status      VARCHAR(20) NOT NULL,
+	paid_with   VARCHAR(254) NOT NULL DEFAULT '',
+	reference   VARCHAR(254) NOT NULL DEFAULT '',
+	paid_at     TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bill_shares_tips ON bill_shares (vendor, staff, paid_at) WHERE status = 'paid';
+
+-- +migrate Down
+DROP TABLE IF EXISTS bill_shares;
+
+DROP TABLE IF EXISTS bill_splits;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tips;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS paid;
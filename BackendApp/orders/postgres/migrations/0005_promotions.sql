@@ -0,0 +1,46 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS adjustments JSONB;
+
+CREATE TABLE IF NOT EXISTS promotions (
+	id 			          VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		          VARCHAR(254) NOT NULL,
+	name 		          VARCHAR(254) NOT NULL,
+	code 		          VARCHAR(254) NOT NULL DEFAULT '',
+	kind 		          VARCHAR(20) NOT NULL,
+	percent 	          BIGINT NOT NULL DEFAULT 0,
+	amount 		          BIGINT NOT NULL DEFAULT 0,
+	buy 		          BIGINT NOT NULL DEFAULT 0,
+	get 		          BIGINT NOT NULL DEFAULT 0,
+	items 		          JSONB NOT NULL DEFAULT '[]',
+	min_spend 	          BIGINT NOT NULL DEFAULT 0,
+	days 		          JSONB NOT NULL DEFAULT '[]',
+	window_from           VARCHAR(5) NOT NULL DEFAULT '',
+	window_to             VARCHAR(5) NOT NULL DEFAULT '',
+	starts_at             TIMESTAMP,
+	ends_at               TIMESTAMP,
+	max_uses              BIGINT NOT NULL DEFAULT 0,
+	max_uses_per_customer BIGINT NOT NULL DEFAULT 0,
+	stackable             BOOLEAN NOT NULL DEFAULT FALSE,
+	active                BOOLEAN NOT NULL DEFAULT TRUE,
+	metadata              JSONB,
+	created_at            TIMESTAMP DEFAULT now(),
+	updated_at            TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS promotions_code ON promotions (vendor, code) WHERE code <> '';
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+	promotion_id VARCHAR(254) NOT NULL REFERENCES promotions (id) ON DELETE CASCADE,
+	order_id     VARCHAR(254) NOT NULL,
+	customer     VARCHAR(254) NOT NULL DEFAULT '',
+	amount       BIGINT NOT NULL,
+	redeemed_at  TIMESTAMP DEFAULT now(),
+	PRIMARY KEY (promotion_id, order_id)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS promotion_redemptions;
+
+DROP TABLE IF EXISTS promotions;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS adjustments;
@@ -0,0 +1,52 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS taxes JSONB;
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL,
+	kind 		VARCHAR(20) NOT NULL,
+	code 		VARCHAR(20) NOT NULL DEFAULT '',
+	category 	VARCHAR(254) NOT NULL DEFAULT '',
+	rate 		BIGINT NOT NULL,
+	inclusive 	BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_kind ON tax_rates (vendor, kind, category);
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+	vendor 		VARCHAR(254) NOT NULL PRIMARY KEY,
+	last 		BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+	id 			   VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		   VARCHAR(254) NOT NULL,
+	prefix 		   VARCHAR(20) NOT NULL,
+	sequence 	   BIGINT NOT NULL,
+	order_id 	   VARCHAR(254) NOT NULL UNIQUE,
+	customer 	   VARCHAR(254) NOT NULL DEFAULT '',
+	gross 		   BIGINT NOT NULL,
+	discount 	   BIGINT NOT NULL,
+	net 		   BIGINT NOT NULL,
+	tax 		   BIGINT NOT NULL,
+	total 		   BIGINT NOT NULL,
+	taxes 		   JSONB NOT NULL DEFAULT '[]',
+	status 		   VARCHAR(20) NOT NULL,
+	control_number VARCHAR(254) NOT NULL DEFAULT '',
+	error 		   TEXT NOT NULL DEFAULT '',
+	issued_at 	   TIMESTAMP NOT NULL,
+	submitted_at   TIMESTAMP,
+	UNIQUE (vendor, sequence)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS invoices;
+
+DROP TABLE IF EXISTS invoice_sequences;
+
+DROP TABLE IF EXISTS tax_rates;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS taxes;
@@ -0,0 +1,49 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS loyalty_programs (
+	vendor 		VARCHAR(254) NOT NULL PRIMARY KEY,
+	spend 		BIGINT NOT NULL,
+	points 		BIGINT NOT NULL,
+	value 		BIGINT NOT NULL,
+	min_redeem 	BIGINT NOT NULL DEFAULT 0,
+	expiry_days BIGINT NOT NULL DEFAULT 0,
+	active 		BOOLEAN NOT NULL DEFAULT TRUE,
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS stamp_cards (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL,
+	items 		JSONB NOT NULL DEFAULT '[]',
+	required 	BIGINT NOT NULL,
+	active 		BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	customer 	VARCHAR(254) NOT NULL,
+	card 		VARCHAR(254) NOT NULL DEFAULT '',
+	type 		VARCHAR(20) NOT NULL,
+	amount 		BIGINT NOT NULL,
+	order_id 	VARCHAR(254) NOT NULL DEFAULT '',
+	reference 	VARCHAR(254) NOT NULL DEFAULT '',
+	reason 		TEXT NOT NULL DEFAULT '',
+	expires_at 	TIMESTAMP,
+	created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS loyalty_ledger_customer ON loyalty_ledger (vendor, customer, card);
+
+CREATE UNIQUE INDEX IF NOT EXISTS loyalty_ledger_earn ON loyalty_ledger (order_id, card) WHERE type = 'earn';
+
+CREATE UNIQUE INDEX IF NOT EXISTS loyalty_ledger_reference ON loyalty_ledger (type, reference) WHERE reference <> '';
+
+-- +migrate Down
+DROP TABLE IF EXISTS loyalty_ledger;
+
+DROP TABLE IF EXISTS stamp_cards;
+
+DROP TABLE IF EXISTS loyalty_programs;
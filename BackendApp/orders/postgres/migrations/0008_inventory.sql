@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS ingredients (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL,
+	unit 		VARCHAR(20) NOT NULL,
+	stock 		BIGINT NOT NULL DEFAULT 0,
+	threshold 	BIGINT NOT NULL DEFAULT 0,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS recipes (
+	item 		VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	lines 		JSONB NOT NULL DEFAULT '[]',
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	ingredient 	VARCHAR(254) NOT NULL,
+	type 		VARCHAR(20) NOT NULL,
+	quantity 	BIGINT NOT NULL,
+	balance 	BIGINT NOT NULL,
+	order_id 	VARCHAR(254) NOT NULL DEFAULT '',
+	reason 		TEXT NOT NULL DEFAULT '',
+	created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_ingredient ON stock_movements (ingredient, created_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS stock_movements_sale ON stock_movements (order_id, ingredient) WHERE type = 'sale';
+
+-- +migrate Down
+DROP TABLE IF EXISTS stock_movements;
+
+DROP TABLE IF EXISTS recipes;
+
+DROP TABLE IF EXISTS ingredients;
@@ -0,0 +1,59 @@
+-- +migrate Up
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS par BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS reference VARCHAR(254) NOT NULL DEFAULT '';
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS cost BIGINT NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS stock_movements_receive ON stock_movements (reference, ingredient) WHERE type = 'receive';
+
+CREATE TABLE IF NOT EXISTS suppliers (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL,
+	phone 		VARCHAR(254) NOT NULL DEFAULT '',
+	email 		VARCHAR(254) NOT NULL DEFAULT '',
+	ingredients JSONB NOT NULL DEFAULT '[]',
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	supplier 	VARCHAR(254) NOT NULL,
+	status 		VARCHAR(20) NOT NULL,
+	lines 		JSONB NOT NULL DEFAULT '[]',
+	expected_at TIMESTAMP,
+	notes 		TEXT NOT NULL DEFAULT '',
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS purchase_orders_vendor ON purchase_orders (vendor, status);
+
+CREATE TABLE IF NOT EXISTS goods_received (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	purchase_order 	VARCHAR(254) NOT NULL,
+	reference 		VARCHAR(254) NOT NULL DEFAULT '',
+	lines 			JSONB NOT NULL DEFAULT '[]',
+	received_at 	TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS goods_received_purchase_order ON goods_received (purchase_order, received_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS goods_received;
+
+DROP TABLE IF EXISTS purchase_orders;
+
+DROP TABLE IF EXISTS suppliers;
+
+DROP INDEX IF EXISTS stock_movements_receive;
+
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS cost;
+
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS reference;
+
+ALTER TABLE ingredients DROP COLUMN IF EXISTS par;
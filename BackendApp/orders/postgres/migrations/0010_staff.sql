@@ -0,0 +1,72 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS created_by VARCHAR(254) NOT NULL DEFAULT '';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS accepted_by VARCHAR(254) NOT NULL DEFAULT '';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS paid_by VARCHAR(254) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS staff_members (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL,
+	role 		VARCHAR(20) NOT NULL,
+	pin 		VARCHAR(64) NOT NULL,
+	phone 		VARCHAR(254) NOT NULL DEFAULT '',
+	active 		BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS staff_members_pin ON staff_members (vendor, pin);
+
+CREATE TABLE IF NOT EXISTS shifts (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	member 		VARCHAR(254) NOT NULL,
+	starts_at 	TIMESTAMP NOT NULL,
+	ends_at 	TIMESTAMP NOT NULL,
+	notes 		TEXT NOT NULL DEFAULT '',
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS shifts_vendor ON shifts (vendor, starts_at);
+
+CREATE TABLE IF NOT EXISTS timecards (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	member 		VARCHAR(254) NOT NULL,
+	clock_in 	TIMESTAMP NOT NULL,
+	clock_out 	TIMESTAMP,
+	breaks 		JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS timecards_vendor ON timecards (vendor, clock_in);
+
+CREATE UNIQUE INDEX IF NOT EXISTS timecards_open ON timecards (member) WHERE clock_out IS NULL;
+
+CREATE TABLE IF NOT EXISTS staff_actions (
+	order_id 	VARCHAR(254) NOT NULL,
+	type 		VARCHAR(20) NOT NULL,
+	vendor 		VARCHAR(254) NOT NULL,
+	member 		VARCHAR(254) NOT NULL,
+	created_at  TIMESTAMP NOT NULL,
+	PRIMARY KEY (order_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS staff_actions_vendor ON staff_actions (vendor, created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS staff_actions;
+
+DROP TABLE IF EXISTS timecards;
+
+DROP TABLE IF EXISTS shifts;
+
+DROP TABLE IF EXISTS staff_members;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_by;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS accepted_by;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS created_by;
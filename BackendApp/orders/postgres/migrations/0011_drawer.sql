@@ -0,0 +1,66 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS order_payments (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	order_id 	VARCHAR(254) NOT NULL,
+	vendor 		VARCHAR(254) NOT NULL,
+	method 		VARCHAR(50) NOT NULL DEFAULT '',
+	amount 		BIGINT NOT NULL,
+	tip 		BIGINT NOT NULL DEFAULT 0,
+	paid_by 	VARCHAR(254) NOT NULL DEFAULT '',
+	created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_payments_vendor ON order_payments (vendor, created_at);
+
+CREATE TABLE IF NOT EXISTS drawer_sessions (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 		VARCHAR(254) NOT NULL,
+	status 		VARCHAR(20) NOT NULL,
+	opening 	BIGINT NOT NULL DEFAULT 0,
+	expected 	BIGINT NOT NULL DEFAULT 0,
+	counted 	BIGINT NOT NULL DEFAULT 0,
+	variance 	BIGINT NOT NULL DEFAULT 0,
+	opened_by 	VARCHAR(254) NOT NULL DEFAULT '',
+	closed_by 	VARCHAR(254) NOT NULL DEFAULT '',
+	notes 		TEXT NOT NULL DEFAULT '',
+	opened_at 	TIMESTAMP NOT NULL,
+	closed_at 	TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS drawer_sessions_vendor ON drawer_sessions (vendor, opened_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS drawer_sessions_open ON drawer_sessions (vendor) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS drawer_events (
+	id 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	session 	VARCHAR(254) NOT NULL REFERENCES drawer_sessions (id) ON DELETE CASCADE,
+	vendor 		VARCHAR(254) NOT NULL,
+	type 		VARCHAR(20) NOT NULL,
+	amount 		BIGINT NOT NULL,
+	order_id 	VARCHAR(254) NOT NULL DEFAULT '',
+	reason 		TEXT NOT NULL DEFAULT '',
+	member 		VARCHAR(254) NOT NULL DEFAULT '',
+	created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS drawer_events_session ON drawer_events (session);
+
+CREATE INDEX IF NOT EXISTS drawer_events_vendor ON drawer_events (vendor, created_at);
+
+CREATE TABLE IF NOT EXISTS business_days (
+	vendor 		VARCHAR(254) NOT NULL,
+	date 		DATE NOT NULL,
+	report 		JSONB NOT NULL,
+	closed_by 	VARCHAR(254) NOT NULL DEFAULT '',
+	closed_at 	TIMESTAMP NOT NULL,
+	PRIMARY KEY (vendor, date)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS business_days;
+
+DROP TABLE IF EXISTS drawer_events;
+
+DROP TABLE IF EXISTS drawer_sessions;
+
+DROP TABLE IF EXISTS order_payments;
@@ -0,0 +1,31 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sales_rollups (
+	vendor 		VARCHAR(254) NOT NULL,
+	hour 		TIMESTAMP NOT NULL,
+	place 		VARCHAR(254) NOT NULL DEFAULT '',
+	status 		VARCHAR(254) NOT NULL DEFAULT '',
+	orders 		BIGINT NOT NULL DEFAULT 0,
+	revenue 	BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (vendor, hour, place, status)
+);
+
+CREATE INDEX IF NOT EXISTS sales_rollups_hour ON sales_rollups (hour);
+
+CREATE TABLE IF NOT EXISTS item_rollups (
+	vendor 		VARCHAR(254) NOT NULL,
+	hour 		TIMESTAMP NOT NULL,
+	place 		VARCHAR(254) NOT NULL DEFAULT '',
+	status 		VARCHAR(254) NOT NULL DEFAULT '',
+	item 		VARCHAR(254) NOT NULL,
+	name 		VARCHAR(254) NOT NULL DEFAULT '',
+	quantity 	BIGINT NOT NULL DEFAULT 0,
+	revenue 	BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (vendor, hour, place, status, item)
+);
+
+CREATE INDEX IF NOT EXISTS item_rollups_hour ON item_rollups (hour);
+
+-- +migrate Down
+DROP TABLE IF EXISTS item_rollups;
+
+DROP TABLE IF EXISTS sales_rollups;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS export_jobs (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	status 			VARCHAR(20) NOT NULL,
+	query 			JSONB NOT NULL DEFAULT '{}',
+	rows 			BIGINT NOT NULL DEFAULT 0,
+	error 			TEXT NOT NULL DEFAULT '',
+	created_at 		TIMESTAMP NOT NULL,
+	completed_at 	TIMESTAMP,
+	expires_at 		TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS export_jobs_expires_at ON export_jobs (expires_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS export_jobs;
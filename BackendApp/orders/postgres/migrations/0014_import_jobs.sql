@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS import_jobs (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	status 			VARCHAR(20) NOT NULL,
+	dry_run 		BOOLEAN NOT NULL DEFAULT FALSE,
+	rows 			BIGINT NOT NULL DEFAULT 0,
+	imported 		BIGINT NOT NULL DEFAULT 0,
+	errors 			JSONB NOT NULL DEFAULT '[]',
+	error 			TEXT NOT NULL DEFAULT '',
+	created_at 		TIMESTAMP NOT NULL,
+	completed_at 	TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS import_jobs;
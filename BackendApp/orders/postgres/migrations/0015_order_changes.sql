@@ -0,0 +1,63 @@
+-- +migrate Up
+CREATE SEQUENCE IF NOT EXISTS order_changes;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS seq BIGINT NOT NULL DEFAULT nextval('order_changes');
+
+CREATE INDEX IF NOT EXISTS orders_vendor_seq ON orders (vendor, seq);
+
+CREATE TABLE IF NOT EXISTS order_tombstones (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	seq 			BIGINT NOT NULL,
+	deleted_at 		TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_tombstones_vendor_seq ON order_tombstones (vendor, seq);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION order_changed() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO order_tombstones (id, vendor, seq, deleted_at)
+		VALUES (OLD.id, OLD.vendor, nextval('order_changes'), now() AT TIME ZONE 'UTC')
+		ON CONFLICT (id) DO UPDATE SET vendor = EXCLUDED.vendor, seq = EXCLUDED.seq, deleted_at = EXCLUDED.deleted_at;
+		RETURN OLD;
+	END IF;
+	IF TG_OP = 'INSERT' THEN
+		DELETE FROM order_tombstones WHERE id = NEW.id;
+	ELSE
+		NEW.seq := nextval('order_changes');
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS orders_changed ON orders;
+
+CREATE TRIGGER orders_changed BEFORE INSERT OR UPDATE OR DELETE ON orders
+FOR EACH ROW EXECUTE FUNCTION order_changed();
+
+CREATE TABLE IF NOT EXISTS order_clocks (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	version 		BIGINT NOT NULL,
+	device 			VARCHAR(254) NOT NULL,
+	seq 			BIGINT NOT NULL,
+	fields 			JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS order_clocks_vendor_version ON order_clocks (vendor, version);
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_clocks;
+
+DROP TRIGGER IF EXISTS orders_changed ON orders;
+
+DROP FUNCTION IF EXISTS order_changed;
+
+DROP TABLE IF EXISTS order_tombstones;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS seq;
+
+DROP SEQUENCE IF EXISTS order_changes;
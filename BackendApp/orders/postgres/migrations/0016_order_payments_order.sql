@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS order_payments_order ON order_payments (order_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS order_payments_order;
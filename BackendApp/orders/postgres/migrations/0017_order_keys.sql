@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS order_keys (
+	key 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	order_id 		VARCHAR(254) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+	created_at 		TIMESTAMP NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_keys;
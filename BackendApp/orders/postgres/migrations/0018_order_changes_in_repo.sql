@@ -0,0 +1,31 @@
+-- +migrate Up
+-- The repository numbers the changes of the orders and keeps their
+-- tombstones itself, as it does on CockroachDB, which runs no triggers.
+DROP TRIGGER IF EXISTS orders_changed ON orders;
+
+DROP FUNCTION IF EXISTS order_changed;
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION order_changed() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO order_tombstones (id, vendor, seq, deleted_at)
+		VALUES (OLD.id, OLD.vendor, nextval('order_changes'), now() AT TIME ZONE 'UTC')
+		ON CONFLICT (id) DO UPDATE SET vendor = EXCLUDED.vendor, seq = EXCLUDED.seq, deleted_at = EXCLUDED.deleted_at;
+		RETURN OLD;
+	END IF;
+	IF TG_OP = 'INSERT' THEN
+		DELETE FROM order_tombstones WHERE id = NEW.id;
+	ELSE
+		NEW.seq := nextval('order_changes');
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS orders_changed ON orders;
+
+CREATE TRIGGER orders_changed BEFORE INSERT OR UPDATE OR DELETE ON orders
+FOR EACH ROW EXECUTE FUNCTION order_changed();
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS guest_carts (
+	table_id 		VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	items 			JSONB NOT NULL DEFAULT '[]',
+	version 		BIGINT NOT NULL,
+	updated_at 		TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS guest_carts_updated_at ON guest_carts (updated_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS guest_carts;
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS staff_pin_attempts (
+	key 			VARCHAR(254) NOT NULL PRIMARY KEY,
+	failures 		BIGINT NOT NULL,
+	since 			TIMESTAMP NOT NULL,
+	locked_until 	TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS staff_pin_attempts;
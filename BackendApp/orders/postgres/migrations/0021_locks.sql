@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Rows locked for update by transactions that must not run at the same
+-- time, keyed by what they lock.
+CREATE TABLE IF NOT EXISTS locks (
+	key 	VARCHAR(254) NOT NULL PRIMARY KEY
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS locks;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS vendors (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	name 			VARCHAR(254) NOT NULL,
+	created_at 		TIMESTAMP NOT NULL
+);
+
+-- API keys are kept as the SHA-256 of the key, the key itself is only
+-- shown when it is created.
+CREATE TABLE IF NOT EXISTS api_keys (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL REFERENCES vendors (id) ON DELETE CASCADE,
+	name 			VARCHAR(254) NOT NULL DEFAULT '',
+	hash 			CHAR(64) NOT NULL UNIQUE,
+	created_at 		TIMESTAMP NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS vendors;
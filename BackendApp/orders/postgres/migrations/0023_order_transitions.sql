@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Orders moving to a status the hooks are notified of, recorded by the
+-- orders repository in the transactions that move them.
+CREATE TABLE IF NOT EXISTS order_transitions (
+	id 				BIGSERIAL PRIMARY KEY,
+	order_id 		VARCHAR(254) NOT NULL,
+	status 			VARCHAR(254) NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_transitions;
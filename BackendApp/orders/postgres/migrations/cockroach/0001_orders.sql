@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Order ids are ULIDs, which sort by when they were made, so a plain primary
+-- key would send every new order to the same range. Hashing the key spreads
+-- them over the cluster.
+CREATE TABLE IF NOT EXISTS orders (
+	id 			VARCHAR(254) NOT NULL,
+	vendor 		VARCHAR(254) NOT NULL,
+	name        VARCHAR(254) NOT NULL,
+	price		SMALLINT NOT NULL,
+	place	    VARCHAR(20),
+	status      VARCHAR(20),
+	metadata    JSONB,
+	created_at  TIMESTAMP DEFAULT now(),
+	updated_at  TIMESTAMP DEFAULT now(),
+	PRIMARY KEY (id) USING HASH
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS orders;
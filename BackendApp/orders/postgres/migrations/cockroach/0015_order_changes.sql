@@ -0,0 +1,39 @@
+-- +migrate Up
+CREATE SEQUENCE IF NOT EXISTS order_changes;
+
+-- CockroachDB cannot fill a new column from a sequence, nor run the trigger
+-- numbering the changes; the repository numbers every change it writes.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS seq BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS orders_vendor_seq ON orders (vendor, seq);
+
+CREATE TABLE IF NOT EXISTS order_tombstones (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	seq 			BIGINT NOT NULL,
+	deleted_at 		TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_tombstones_vendor_seq ON order_tombstones (vendor, seq);
+
+CREATE TABLE IF NOT EXISTS order_clocks (
+	id 				VARCHAR(254) NOT NULL PRIMARY KEY,
+	vendor 			VARCHAR(254) NOT NULL,
+	version 		BIGINT NOT NULL,
+	device 			VARCHAR(254) NOT NULL,
+	seq 			BIGINT NOT NULL,
+	fields 			JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS order_clocks_vendor_version ON order_clocks (vendor, version);
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_clocks;
+
+DROP TABLE IF EXISTS order_tombstones;
+
+DROP INDEX IF EXISTS orders_vendor_seq;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS seq;
+
+DROP SEQUENCE IF EXISTS order_changes;
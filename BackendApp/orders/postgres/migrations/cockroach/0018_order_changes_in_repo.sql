@@ -0,0 +1,5 @@
+-- +migrate Up
+-- The trigger dropped on PostgreSQL was never created on CockroachDB.
+
+-- +migrate Down
+-- Nothing to bring back.
@@ -0,0 +1,72 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// TestMigrations applies every migration, rolls every one back and applies
+// them again, so the down statements undo all the up statements do.
+func TestMigrations(t *testing.T) {
+	db := openTestDB(t)
+
+	ms, err := postgres.Migrations(db, testEngine)
+	if err != nil {
+		t.Fatalf("listing migrations: %s", err)
+	}
+	for _, m := range ms {
+		if !m.AppliedAt.IsZero() {
+			t.Fatalf("migration %s is applied, the test database must be empty", m.ID)
+		}
+	}
+
+	steps := []struct {
+		desc string
+		dir  migrate.MigrationDirection
+	}{
+		{"applying", migrate.Up},
+		{"rolling back", migrate.Down},
+		{"applying again", migrate.Up},
+	}
+	for _, s := range steps {
+		n, err := postgres.Migrate(db, testEngine, s.dir, 0)
+		if err != nil {
+			t.Fatalf("%s migrations: %s", s.desc, err)
+		}
+		if n != len(ms) {
+			t.Fatalf("%s migrations: %d of %d migrated", s.desc, n, len(ms))
+		}
+		if s.dir == migrate.Down {
+			checkLeftovers(t, db)
+		}
+	}
+
+	// The database is left empty for the next run.
+	if _, err := postgres.Migrate(db, testEngine, migrate.Down, 0); err != nil {
+		t.Fatalf("rolling back migrations: %s", err)
+	}
+}
+
+// checkLeftovers fails the test if rolling back every migration left
+// relations behind, other than the tables recording and locking the
+// migrations.
+func checkLeftovers(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+	q := `SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE n.nspname = 'public' AND c.relname NOT LIKE 'gorp_migrations%'`
+	if testEngine == postgres.EngineCockroach {
+		q = `SELECT table_name FROM information_schema.tables
+			 WHERE table_schema = 'public' AND table_name NOT IN ('gorp_migrations', 'jikoni_migration_lock')`
+	}
+
+	var names []string
+	if err := db.Select(&names, q); err != nil {
+		t.Fatalf("listing relations: %s", err)
+	}
+	if len(names) > 0 {
+		t.Fatalf("rolling back all the migrations left %v behind", names)
+	}
+}
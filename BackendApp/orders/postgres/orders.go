@@ -9,53 +9,166 @@ import (
 	"time"
 
 	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
 	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jackc/pgconn"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/multierr"
 )
 
+// saveManyRows is how many orders a multi-row insert saves at most, it keeps
+// the bind parameters of a statement under the limit of 65535.
+const saveManyRows = 1000
+
 var _ orders.OrderRepository = (*orderRepo)(nil)
 
+// Hook is run in the transaction of every write with what the orders written
+// were before and what they are after it, so what the hook writes commits or
+// rolls back with the orders. Orders saved have nothing before and orders
+// deleted nothing after.
+type Hook func(ctx context.Context, tx *sqlx.Tx, before, after []orders.Order) error
+
 type orderRepo struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	hooks []Hook
 }
 
 // NewOrderRepo instantiates a PostgreSQL
 // implementation of Users repository.
-func NewOrderRepo(db *sqlx.DB) orders.OrderRepository {
+func NewOrderRepo(db *sqlx.DB, hooks ...Hook) orders.OrderRepository {
 	return &orderRepo{
-		db: db,
+		db:    db,
+		hooks: hooks,
 	}
 }
 
 func (repo orderRepo) Save(ctx context.Context, order orders.Order) (string, error) {
-	q := `INSERT INTO orders (id, vendor, name, price, place, status, metadata, created_at, updated_at)
-		  VALUES (:id, :vendor, :name, :price, :place, :status, :metadata, :created_at, :updated_at) RETURNING id`
+	dbo, err := toDBOrder(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	err = pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		if err := insertOrder(ctx, tx, dbo); err != nil {
+			return err
+		}
+		return repo.run(ctx, tx, nil, []orders.Order{order})
+	})
+	if err != nil {
+		return "", err
+	}
+	return order.ID, nil
+}
+
+func (repo orderRepo) SaveWithKey(ctx context.Context, order orders.Order, key string) (string, error) {
+	kq := `INSERT INTO order_keys (key, order_id, created_at) VALUES ($1, $2, $3)`
 
 	dbo, err := toDBOrder(order)
 	if err != nil {
 		return "", multierr.Combine(errors.ErrCreateEntity, err)
 	}
-	row, err := repo.db.NamedQueryContext(ctx, q, dbo)
+	err = pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		if err := insertOrder(ctx, tx, dbo); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, kq, key, order.ID, order.CreatedAt); err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errDuplicate {
+				return errors.Wrap(errors.ErrConflict, err)
+			}
+			return multierr.Combine(errors.ErrCreateEntity, err)
+		}
+		return repo.run(ctx, tx, nil, []orders.Order{order})
+	})
 	if err != nil {
-		return "", handleError(err, errors.ErrCreateEntity)
+		return "", err
+	}
+	return order.ID, nil
+}
+
+// insertOrder inserts the order as the latest change of its vendor, and
+// drops the tombstone of an order it brings back. The repository keeps the
+// changes itself rather than in triggers, which CockroachDB does not run.
+func insertOrder(ctx context.Context, tx *sqlx.Tx, dbo dbOrder) error {
+	q := `INSERT INTO orders (id, vendor, name, price, place, status, items, adjustments, taxes, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq)
+		  VALUES (:id, :vendor, :name, :price, :place, :status, :items, :adjustments, :taxes, :metadata, :created_by, :accepted_by, :paid_by, :created_at, :updated_at, nextval('order_changes'))`
+	tq := `DELETE FROM order_tombstones WHERE id = $1`
+
+	if err := lockChanges(ctx, tx, dbo.Vendor); err != nil {
+		return err
+	}
+	if _, err := tx.NamedExecContext(ctx, q, dbo); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	if _, err := tx.ExecContext(ctx, tq, dbo.ID); err != nil {
+		return multierr.Combine(errors.ErrCreateEntity, err)
 	}
-	defer row.Close()
-	row.Next()
+	return nil
+}
+
+func (repo orderRepo) RetrieveByKey(ctx context.Context, key string) (string, error) {
+	q := `SELECT order_id FROM order_keys WHERE key = $1`
+
 	var id string
-	if err := row.Scan(&id); err != nil {
-		return "", err
+	if err := pgutil.DB(ctx, repo.db).QueryRowxContext(ctx, q, key).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.Wrap(errors.ErrNotFound, err)
+		}
+		return "", multierr.Combine(errors.ErrViewEntity, err)
 	}
 	return id, nil
 }
 
+func (repo orderRepo) SaveMany(ctx context.Context, os []orders.Order) ([]string, error) {
+	q := `INSERT INTO orders (id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq)
+		  VALUES (:id, :vendor, :name, :price, :place, :status, :items, :adjustments, :taxes, :paid, :tips, :metadata, :created_by, :accepted_by, :paid_by, :created_at, :updated_at, nextval('order_changes'))`
+	tq := `DELETE FROM order_tombstones WHERE id = ANY($1)`
+
+	ids := make([]string, 0, len(os))
+	vendors := make([]string, 0, len(os))
+	batches := make([][]dbOrder, 0, len(os)/saveManyRows+1)
+	for start := 0; start < len(os); start += saveManyRows {
+		end := start + saveManyRows
+		if end > len(os) {
+			end = len(os)
+		}
+		dbos := make([]dbOrder, 0, end-start)
+		for _, order := range os[start:end] {
+			dbo, err := toDBOrder(order)
+			if err != nil {
+				return nil, multierr.Combine(errors.ErrCreateEntity, err)
+			}
+			dbos = append(dbos, dbo)
+			ids = append(ids, order.ID)
+			vendors = append(vendors, order.Vendor)
+		}
+		batches = append(batches, dbos)
+	}
+	err := pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		if err := lockChanges(ctx, tx, vendors...); err != nil {
+			return err
+		}
+		for _, dbos := range batches {
+			if _, err := tx.NamedExecContext(ctx, q, dbos); err != nil {
+				return handleError(err, errors.ErrCreateEntity)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, tq, ids); err != nil {
+			return multierr.Combine(errors.ErrCreateEntity, err)
+		}
+		return repo.run(ctx, tx, nil, os)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (repo orderRepo) RetrieveByID(ctx context.Context, id string) (orders.Order, error) {
-	q := `SELECT id, vendor, name, price, place, status, metadata, created_at, updated_at FROM orders WHERE id = $1`
+	q := `SELECT id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq FROM orders WHERE id = $1`
 
 	dbc := dbOrder{
 		ID: id,
 	}
-	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+	if err := pgutil.DB(ctx, repo.db).QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
 		if err == sql.ErrNoRows {
 			return orders.Order{}, multierr.Combine(errors.ErrNotFound, err)
 		}
@@ -75,31 +188,54 @@ func (repo orderRepo) RetrieveAll(ctx context.Context, pm orders.PageMetadata) (
 		query = append(query, mq)
 	}
 	if pm.Vendor != "" {
-		query = append(query, fmt.Sprintf("vendor = '%s'", pm.Vendor))
+		query = append(query, "vendor = :vendor")
 	}
 	if pm.Name != "" {
-		query = append(query, fmt.Sprintf("name = '%s'", pm.Name))
+		query = append(query, "name = :name")
 	}
 	if pm.Price != 0 {
-		query = append(query, fmt.Sprintf("price = '%d'", pm.Price))
+		query = append(query, "price = :price")
 	}
 	if pm.Place != "" {
-		query = append(query, fmt.Sprintf("place = '%s'", pm.Place))
+		query = append(query, "place = :place")
 	}
 	if pm.Status != "" {
-		query = append(query, fmt.Sprintf("status = '%s'", pm.Status))
+		query = append(query, "status = :status")
+	}
+	if pm.Outstanding {
+		query = append(query, "paid < price")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "created_at >= :from")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "created_at < :to")
+	}
+	if pm.After.ID != "" {
+		query = append(query, "(created_at, id) > (:after_created_at, :after_id)")
 	}
 	if len(query) > 0 {
 		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 	}
 
-	q := fmt.Sprintf(`SELECT id, vendor, name, price, place, status, metadata, created_at, updated_at FROM orders %s ORDER BY created_at LIMIT :limit OFFSET :offset;`, emq)
+	q := fmt.Sprintf(`SELECT id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq FROM orders %s ORDER BY created_at, id LIMIT :limit OFFSET :offset;`, emq)
 	params := map[string]interface{}{
 		"limit":    pm.Limit,
 		"offset":   pm.Offset,
 		"metadata": mp,
-	}
-	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+		"vendor":   pm.Vendor,
+		"name":     pm.Name,
+		"price":    pm.Price,
+		"place":    pm.Place,
+		"status":   pm.Status,
+		// Orders keep the wall clock of the server that took them.
+		"from": pm.From.In(time.Local),
+		"to":   pm.To.In(time.Local),
+		// Cursors carry the wall clock as it was read back.
+		"after_created_at": pm.After.CreatedAt,
+		"after_id":         pm.After.ID,
+	}
+	rows, err := sqlx.NamedQueryContext(ctx, pgutil.DB(ctx, repo.db), q, params)
 	if err != nil {
 		return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
 	}
@@ -118,7 +254,7 @@ func (repo orderRepo) RetrieveAll(ctx context.Context, pm orders.PageMetadata) (
 	}
 	cq := fmt.Sprintf(`SELECT COUNT(*) FROM orders %s;`, emq)
 
-	total, err := total(ctx, repo.db, cq, params)
+	total, err := total(ctx, pgutil.DB(ctx, repo.db), cq, params)
 	if err != nil {
 		return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
 	}
@@ -134,11 +270,10 @@ func (repo orderRepo) RetrieveAll(ctx context.Context, pm orders.PageMetadata) (
 }
 
 func (repo orderRepo) Update(ctx context.Context, order orders.Order) (string, error) {
+	// Orders stay with the vendor they were taken for, their changes are
+	// read by vendor.
 	var query []string
 	var upq string
-	if order.Vendor != "" {
-		query = append(query, "vendor = :vendor,")
-	}
 	if order.Name != "" {
 		query = append(query, "name = :name,")
 	}
@@ -151,45 +286,335 @@ func (repo orderRepo) Update(ctx context.Context, order orders.Order) (string, e
 	if order.Status != "" {
 		query = append(query, "status = :status,")
 	}
+	if order.Items != nil {
+		query = append(query, "items = :items,")
+	}
 	if order.Metadata != nil {
 		query = append(query, "metadata = :metadata,")
 	}
+	// The first staff member to accept or settle the order keeps it.
+	if order.AcceptedBy != "" {
+		query = append(query, "accepted_by = COALESCE(NULLIF(accepted_by, ''), :accepted_by),")
+	}
+	if order.PaidBy != "" {
+		query = append(query, "paid_by = COALESCE(NULLIF(paid_by, ''), :paid_by),")
+	}
 	if len(query) > 0 {
 		upq = strings.Join(query, " ")
 	}
-	q := fmt.Sprintf(`UPDATE orders SET %s updated_at = :updated_at WHERE id = :id RETURNING id`, upq)
+	q := fmt.Sprintf(`UPDATE orders SET %s updated_at = :updated_at, seq = nextval('order_changes') WHERE id = :id
+		  RETURNING id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq`, upq)
 
 	dbu, err := toDBOrder(order)
 	if err != nil {
 		return "", multierr.Combine(errors.ErrUpdateEntity, err)
 	}
-	row, err := repo.db.NamedQueryContext(ctx, q, dbu)
+	err = pgutil.InTx(ctx, repo.db, errors.ErrUpdateEntity, func(tx *sqlx.Tx) error {
+		if err := lockChangesOf(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		before, err := lockOrder(ctx, tx, order.ID)
+		if err != nil {
+			return err
+		}
+		after, err := updateOrder(ctx, tx, q, dbu)
+		if err != nil {
+			return err
+		}
+		if err := orders.ValidatePaid(before, after); err != nil {
+			return err
+		}
+		return repo.run(ctx, tx, []orders.Order{before}, []orders.Order{after})
+	})
 	if err != nil {
-		return "", multierr.Combine(errors.ErrUpdateEntity, err)
-	}
-	defer row.Close()
-	row.Next()
-	var id string
-	if err := row.Scan(&id); err != nil {
-		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+		return "", err
 	}
-	return id, nil
+	return order.ID, nil
 }
 
 func (repo orderRepo) Delete(ctx context.Context, id string) error {
-	q := `DELETE FROM orders WHERE id = :id`
+	q := `DELETE FROM orders WHERE id = $1
+		  RETURNING id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq`
+	tq := `INSERT INTO order_tombstones (id, vendor, seq, deleted_at) VALUES ($1, $2, nextval('order_changes'), $3)
+		   ON CONFLICT (id) DO UPDATE SET vendor = EXCLUDED.vendor, seq = EXCLUDED.seq, deleted_at = EXCLUDED.deleted_at`
 
-	dbu := dbOrder{
-		ID: id,
+	vq := `SELECT vendor FROM orders WHERE id = $1`
+
+	return pgutil.InTx(ctx, repo.db, errors.ErrRemoveEntity, func(tx *sqlx.Tx) error {
+		var vendor string
+		switch err := tx.QueryRowxContext(ctx, vq, id).Scan(&vendor); err {
+		case nil:
+		case sql.ErrNoRows:
+			return nil
+		default:
+			return multierr.Combine(errors.ErrRemoveEntity, err)
+		}
+		if err := lockChanges(ctx, tx, vendor); err != nil {
+			return err
+		}
+		dbo := dbOrder{}
+		switch err := tx.QueryRowxContext(ctx, q, id).StructScan(&dbo); err {
+		case nil:
+		case sql.ErrNoRows:
+			return nil
+		default:
+			return multierr.Combine(errors.ErrRemoveEntity, err)
+		}
+		// Clients syncing the vendor learn of the deletion from its tombstone.
+		if _, err := tx.ExecContext(ctx, tq, id, dbo.Vendor, time.Now().UTC()); err != nil {
+			return multierr.Combine(errors.ErrRemoveEntity, err)
+		}
+		before, err := toOrder(dbo)
+		if err != nil {
+			return err
+		}
+		return repo.run(ctx, tx, []orders.Order{before}, nil)
+	})
+}
+
+func (repo orderRepo) AddPayment(ctx context.Context, payment orders.Payment) (orders.Order, error) {
+	q := `UPDATE orders SET paid = paid + :amount, tips = tips + :tip,
+			status = CASE WHEN paid + :amount >= price THEN 'paid' ELSE status END,
+			paid_by = CASE WHEN paid < price AND paid + :amount >= price THEN :paid_by ELSE paid_by END, updated_at = :created_at,
+			seq = nextval('order_changes')
+		  WHERE id = :order_id
+		  RETURNING id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq`
+	pq := `INSERT INTO order_payments (id, order_id, vendor, method, amount, tip, paid_by, created_at)
+		   VALUES (:id, :order_id, :vendor, :method, :amount, :tip, :paid_by, :created_at)`
+
+	var order orders.Order
+	err := pgutil.InTx(ctx, repo.db, errors.ErrUpdateEntity, func(tx *sqlx.Tx) error {
+		if err := lockChangesOf(ctx, tx, payment.Order); err != nil {
+			return err
+		}
+		before, err := lockOrder(ctx, tx, payment.Order)
+		if err != nil {
+			return err
+		}
+		if order, err = updateOrder(ctx, tx, q, toDBPayment(payment)); err != nil {
+			return err
+		}
+
+		p := payment
+		p.Vendor = order.Vendor
+		if _, err := tx.NamedExecContext(ctx, pq, toDBPayment(p)); err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errDuplicate {
+				return errors.Wrap(errors.ErrConflict, err)
+			}
+			return multierr.Combine(errors.ErrCreateEntity, err)
+		}
+		return repo.run(ctx, tx, []orders.Order{before}, []orders.Order{order})
+	})
+	if err != nil {
+		return orders.Order{}, err
+	}
+	return order, nil
+}
+
+// lockOrder returns the order with the unique identifier id as it is before
+// the transaction writes it, locking it until the transaction ends so no
+// other write comes in between.
+func lockOrder(ctx context.Context, tx *sqlx.Tx, id string) (orders.Order, error) {
+	q := `SELECT id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq
+		  FROM orders WHERE id = $1 FOR UPDATE`
+
+	dbo := dbOrder{}
+	if err := tx.QueryRowxContext(ctx, q, id).StructScan(&dbo); err != nil {
+		if err == sql.ErrNoRows {
+			return orders.Order{}, multierr.Combine(errors.ErrNotFound, err)
+		}
+		return orders.Order{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return toOrder(dbo)
+}
+
+// updateOrder runs the named update query and returns the order it
+// returns.
+func updateOrder(ctx context.Context, tx *sqlx.Tx, q string, arg interface{}) (orders.Order, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, tx, q, arg)
+	if err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
 	}
-	if _, err := repo.db.NamedExecContext(ctx, q, dbu); err != nil {
+	defer rows.Close()
+	if !rows.Next() {
+		return orders.Order{}, errors.ErrNotFound
+	}
+	dbo := dbOrder{}
+	if err := rows.StructScan(&dbo); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	return toOrder(dbo)
+}
+
+func (repo orderRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return pgutil.InTx(ctx, repo.db, errors.ErrUpdateEntity, func(tx *sqlx.Tx) error {
+		return fn(pgutil.WithTx(ctx, tx))
+	})
+}
+
+// run records the transitions of the orders written and runs the hooks in
+// the transaction that wrote them.
+func (repo orderRepo) run(ctx context.Context, tx *sqlx.Tx, before, after []orders.Order) error {
+	if err := recordTransitions(ctx, tx, before, after); err != nil {
+		return err
+	}
+	for _, hook := range repo.hooks {
+		if err := hook(ctx, tx, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordTransitions records the orders the write moved to preparing or
+// paid.
+func recordTransitions(ctx context.Context, tx *sqlx.Tx, before, after []orders.Order) error {
+	q := `INSERT INTO order_transitions (order_id, status) VALUES ($1, $2)`
+
+	statuses := make(map[string]string, len(before))
+	for _, order := range before {
+		statuses[order.ID] = order.Status
+	}
+	for _, order := range after {
+		if order.Status == statuses[order.ID] || (order.Status != orders.StatusPreparing && order.Status != orders.StatusPaid) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, q, order.ID, order.Status); err != nil {
+			return multierr.Combine(errors.ErrCreateEntity, err)
+		}
+	}
+	return nil
+}
+
+func (repo orderRepo) RetrieveTransitions(ctx context.Context, after, limit uint64) ([]orders.Transition, error) {
+	q := `SELECT id, order_id, status FROM order_transitions WHERE id > $1 ORDER BY id LIMIT $2`
+
+	rows, err := pgutil.DB(ctx, repo.db).QueryxContext(ctx, q, after, limit)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var transitions []orders.Transition
+	for rows.Next() {
+		var t orders.Transition
+		if err := rows.Scan(&t.ID, &t.Order, &t.Status); err != nil {
+			return nil, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, nil
+}
+
+func (repo orderRepo) RemoveTransition(ctx context.Context, id uint64) error {
+	q := `DELETE FROM order_transitions WHERE id = $1`
+
+	if _, err := pgutil.DB(ctx, repo.db).ExecContext(ctx, q, id); err != nil {
 		return multierr.Combine(errors.ErrRemoveEntity, err)
 	}
 	return nil
 }
 
-func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
-	rows, err := db.NamedQueryContext(ctx, query, params)
+func (repo orderRepo) RetrieveChanges(ctx context.Context, vendor string, since, limit uint64) (orders.ChangesPage, error) {
+	// Reading a change past the limit tells whether changes were left out.
+	q := `SELECT id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq
+		  FROM orders WHERE vendor = $1 AND seq > $2 ORDER BY seq LIMIT $3`
+	tq := `SELECT id, vendor, seq, deleted_at FROM order_tombstones WHERE vendor = $1 AND seq > $2 ORDER BY seq LIMIT $3`
+
+	// Orders and tombstones are read from the same snapshot, a change
+	// committed in between would be skipped by the next page.
+	tx, err := repo.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryxContext(ctx, q, vendor, since, limit+1)
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var written []orders.Order
+	for rows.Next() {
+		dbo := dbOrder{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		order, err := toOrder(dbo)
+		if err != nil {
+			return orders.ChangesPage{}, err
+		}
+		written = append(written, order)
+	}
+	rows.Close()
+
+	trows, err := tx.QueryxContext(ctx, tq, vendor, since, limit+1)
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer trows.Close()
+	var deleted []orders.Tombstone
+	for trows.Next() {
+		dbt := dbTombstone{}
+		if err := trows.StructScan(&dbt); err != nil {
+			return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		deleted = append(deleted, toTombstone(dbt))
+	}
+
+	// Both lists are in the order of their changes, the page takes the
+	// first changes of either.
+	page := orders.ChangesPage{Seq: since}
+	i, j := 0, 0
+	for n := uint64(0); n < limit && (i < len(written) || j < len(deleted)); n++ {
+		if j == len(deleted) || (i < len(written) && written[i].Seq < deleted[j].Seq) {
+			page.Orders = append(page.Orders, written[i])
+			page.Seq = written[i].Seq
+			i++
+			continue
+		}
+		page.Deleted = append(page.Deleted, deleted[j])
+		page.Seq = deleted[j].Seq
+		j++
+	}
+	page.More = i < len(written) || j < len(deleted)
+	return page, nil
+}
+
+func (repo orderRepo) RetrieveTombstone(ctx context.Context, id string) (orders.Tombstone, error) {
+	q := `SELECT id, vendor, seq, deleted_at FROM order_tombstones WHERE id = $1`
+
+	dbt := dbTombstone{}
+	if err := pgutil.DB(ctx, repo.db).QueryRowxContext(ctx, q, id).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return orders.Tombstone{}, multierr.Combine(errors.ErrNotFound, err)
+		}
+		return orders.Tombstone{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return toTombstone(dbt), nil
+}
+
+func (repo orderRepo) RetrievePayments(ctx context.Context, ids []string) ([]orders.Payment, error) {
+	q := `SELECT id, order_id, vendor, method, amount, tip, paid_by, created_at FROM order_payments
+		  WHERE order_id = ANY($1) ORDER BY created_at, id`
+
+	rows, err := pgutil.DB(ctx, repo.db).QueryxContext(ctx, q, ids)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var payments []orders.Payment
+	for rows.Next() {
+		dbp := dbPayment{}
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		payments = append(payments, toPayment(dbp))
+	}
+	return payments, nil
+}
+
+func total(ctx context.Context, db sqlx.ExtContext, query string, params interface{}) (uint64, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, params)
 	if err != nil {
 		return 0, err
 	}
@@ -204,15 +629,24 @@ func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (
 }
 
 type dbOrder struct {
-	ID        string    `db:"id,omitempty"`
-	Vendor    string    `db:"vendor,omitempty"`
-	Name      string    `db:"name,omitempty"`
-	Price     uint64    `db:"price,omitempty"`
-	Place     string    `db:"place,omitempty"`
-	Metadata  []byte    `db:"metadata,omitempty"`
-	Status    string    `db:"status,omitempty"`
-	CreatedAt time.Time `db:"created_at,omitempty"`
-	UpdatedAt time.Time `db:"updated_at,omitempty"`
+	ID          string    `db:"id,omitempty"`
+	Vendor      string    `db:"vendor,omitempty"`
+	Name        string    `db:"name,omitempty"`
+	Price       uint64    `db:"price,omitempty"`
+	Place       string    `db:"place,omitempty"`
+	Items       []byte    `db:"items,omitempty"`
+	Adjustments []byte    `db:"adjustments,omitempty"`
+	Taxes       []byte    `db:"taxes,omitempty"`
+	Paid        uint64    `db:"paid"`
+	Tips        uint64    `db:"tips"`
+	Metadata    []byte    `db:"metadata,omitempty"`
+	CreatedBy   string    `db:"created_by"`
+	AcceptedBy  string    `db:"accepted_by"`
+	PaidBy      string    `db:"paid_by"`
+	Status      string    `db:"status,omitempty"`
+	CreatedAt   time.Time `db:"created_at,omitempty"`
+	UpdatedAt   time.Time `db:"updated_at,omitempty"`
+	Seq         uint64    `db:"seq"`
 }
 
 func toDBOrder(order orders.Order) (dbOrder, error) {
@@ -224,16 +658,48 @@ func toDBOrder(order orders.Order) (dbOrder, error) {
 		}
 		data = b
 	}
+	items := []byte("[]")
+	if len(order.Items) > 0 {
+		b, err := json.Marshal(order.Items)
+		if err != nil {
+			return dbOrder{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+		items = b
+	}
+	adjustments := []byte("[]")
+	if len(order.Adjustments) > 0 {
+		b, err := json.Marshal(order.Adjustments)
+		if err != nil {
+			return dbOrder{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+		adjustments = b
+	}
+	taxes := []byte("[]")
+	if len(order.Taxes) > 0 {
+		b, err := json.Marshal(order.Taxes)
+		if err != nil {
+			return dbOrder{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+		taxes = b
+	}
 	return dbOrder{
-		ID:        order.ID,
-		Vendor:    order.Vendor,
-		Name:      order.Name,
-		Price:     order.Price,
-		Place:     order.Place,
-		Metadata:  data,
-		Status:    order.Status,
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		ID:          order.ID,
+		Vendor:      order.Vendor,
+		Name:        order.Name,
+		Price:       order.Price,
+		Place:       order.Place,
+		Items:       items,
+		Adjustments: adjustments,
+		Taxes:       taxes,
+		Paid:        order.Paid,
+		Tips:        order.Tips,
+		Metadata:    data,
+		CreatedBy:   order.CreatedBy,
+		AcceptedBy:  order.AcceptedBy,
+		PaidBy:      order.PaidBy,
+		Status:      order.Status,
+		CreatedAt:   order.CreatedAt,
+		UpdatedAt:   order.UpdatedAt,
 	}, nil
 }
 
@@ -244,16 +710,43 @@ func toOrder(order dbOrder) (orders.Order, error) {
 			return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
 		}
 	}
+	var items []orders.Item
+	if order.Items != nil {
+		if err := json.Unmarshal(order.Items, &items); err != nil {
+			return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+	}
+	var adjustments []orders.Adjustment
+	if order.Adjustments != nil {
+		if err := json.Unmarshal(order.Adjustments, &adjustments); err != nil {
+			return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+	}
+	var taxes []orders.Tax
+	if order.Taxes != nil {
+		if err := json.Unmarshal(order.Taxes, &taxes); err != nil {
+			return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+	}
 	return orders.Order{
-		ID:        order.ID,
-		Vendor:    order.Vendor,
-		Name:      order.Name,
-		Price:     order.Price,
-		Place:     order.Place,
-		Metadata:  metadata,
-		Status:    order.Status,
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		ID:          order.ID,
+		Vendor:      order.Vendor,
+		Name:        order.Name,
+		Price:       order.Price,
+		Place:       order.Place,
+		Items:       items,
+		Adjustments: adjustments,
+		Taxes:       taxes,
+		Paid:        order.Paid,
+		Tips:        order.Tips,
+		Metadata:    metadata,
+		CreatedBy:   order.CreatedBy,
+		AcceptedBy:  order.AcceptedBy,
+		PaidBy:      order.PaidBy,
+		Status:      order.Status,
+		CreatedAt:   order.CreatedAt,
+		UpdatedAt:   order.UpdatedAt,
+		Seq:         order.Seq,
 	}, nil
 }
 
@@ -268,3 +761,56 @@ func createMetadataQuery(entity string, um orders.Metadata) (string, []byte, err
 	query := fmt.Sprintf("%smetadata @> :metadata", entity)
 	return query, param, nil
 }
+
+type dbPayment struct {
+	ID        string    `db:"id"`
+	Order     string    `db:"order_id"`
+	Vendor    string    `db:"vendor"`
+	Method    string    `db:"method"`
+	Amount    uint64    `db:"amount"`
+	Tip       uint64    `db:"tip"`
+	PaidBy    string    `db:"paid_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBPayment(payment orders.Payment) dbPayment {
+	return dbPayment{
+		ID:        payment.ID,
+		Order:     payment.Order,
+		Vendor:    payment.Vendor,
+		Method:    payment.Method,
+		Amount:    payment.Amount,
+		Tip:       payment.Tip,
+		PaidBy:    payment.PaidBy,
+		CreatedAt: payment.CreatedAt,
+	}
+}
+
+func toPayment(dbp dbPayment) orders.Payment {
+	return orders.Payment{
+		ID:        dbp.ID,
+		Order:     dbp.Order,
+		Vendor:    dbp.Vendor,
+		Method:    dbp.Method,
+		Amount:    dbp.Amount,
+		Tip:       dbp.Tip,
+		PaidBy:    dbp.PaidBy,
+		CreatedAt: dbp.CreatedAt,
+	}
+}
+
+type dbTombstone struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Seq       uint64    `db:"seq"`
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+func toTombstone(dbt dbTombstone) orders.Tombstone {
+	return orders.Tombstone{
+		ID:        dbt.ID,
+		Vendor:    dbt.Vendor,
+		Seq:       dbt.Seq,
+		DeletedAt: dbt.DeletedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/repotest"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+func TestOrderRepo(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := postgres.Migrate(db, testEngine, migrate.Up, 0); err != nil {
+		t.Fatalf("applying migrations: %s", err)
+	}
+	// The database is left empty for the next run.
+	defer func() {
+		if _, err := postgres.Migrate(db, testEngine, migrate.Down, 0); err != nil {
+			t.Errorf("rolling back migrations: %s", err)
+		}
+	}()
+
+	repotest.Run(t, postgres.NewOrderRepo(db))
+}
@@ -0,0 +1,41 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+
+	fama "github.com/0x6flab/jikoniApp/BackendApp"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// envTestDBHost names the host of a throwaway database the tests of the
+// package run against. The tests are skipped when it is not set, and they
+// drop every table of the database when it is.
+const envTestDBHost = "JIKONI_TEST_DB_HOST"
+
+// testEngine is the engine of the test database.
+var testEngine = fama.Env("JIKONI_TEST_DB_ENGINE", postgres.EnginePostgres)
+
+// openTestDB opens the test database without migrating it.
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	host := os.Getenv(envTestDBHost)
+	if host == "" {
+		t.Skipf("%s is not set", envTestDBHost)
+	}
+	db, err := postgres.Open(postgres.Config{
+		Engine:  testEngine,
+		Host:    host,
+		Port:    fama.Env("JIKONI_TEST_DB_PORT", "5432"),
+		User:    fama.Env("JIKONI_TEST_DB_USER", "postgres"),
+		Pass:    fama.Env("JIKONI_TEST_DB_PASS", ""),
+		Name:    fama.Env("JIKONI_TEST_DB", "jikoni_test"),
+		SSLMode: fama.Env("JIKONI_TEST_DB_SSL_MODE", "disable"),
+	})
+	if err != nil {
+		t.Fatalf("opening the test database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
@@ -0,0 +1,818 @@
+// Package repotest checks that orders repositories behave the same, so the
+// order service can be run on any of them. The checks only touch orders of
+// vendors they make up, they can be run against a database in use.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/multierr"
+)
+
+// Case is a behaviour every orders repository must have.
+type Case struct {
+	Name string
+	Run  func(ctx context.Context, repo orders.OrderRepository) error
+}
+
+// Cases are the behaviours checked by Run.
+var Cases = []Case{
+	{"save", checkSave},
+	{"save with key", checkSaveWithKey},
+	{"save many", checkSaveMany},
+	{"list filters", checkFilters},
+	{"list metadata", checkMetadata},
+	{"list pages", checkPages},
+	{"update", checkUpdate},
+	{"payments", checkPayments},
+	{"paid", checkPaid},
+	{"delete", checkDelete},
+	{"transitions", checkTransitions},
+	{"transactions", checkTransactions},
+	{"changes", checkChanges},
+}
+
+// Run checks the repository against each case, each in a subtest of t
+// named after it.
+func Run(t *testing.T, repo orders.OrderRepository) {
+	for _, c := range Cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if err := c.Run(context.Background(), repo); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// base is when the orders of the checks were taken, in the past so they
+// stay out of the way of real orders listed by date.
+var base = time.Date(2001, 1, 1, 12, 0, 0, 0, time.Local)
+
+func newOrder(vendor string, minutes int) orders.Order {
+	at := base.Add(time.Duration(minutes) * time.Minute)
+	return orders.Order{
+		ID:        ulid.Make().String(),
+		Vendor:    vendor,
+		Name:      "Chapati",
+		Price:     100,
+		Place:     "inhouse",
+		Status:    orders.StatusOrdered,
+		CreatedBy: "wanjiku",
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+func newVendor() string {
+	return "repotest-" + ulid.Make().String()
+}
+
+func checkSave(ctx context.Context, repo orders.OrderRepository) error {
+	order := newOrder(newVendor(), 0)
+	order.Items = []orders.Item{{ID: "chapati", Name: "Chapati", Quantity: 2, Price: 50}}
+	order.Adjustments = []orders.Adjustment{{Name: "Happy hour", Amount: 10}}
+	order.Taxes = []orders.Tax{{Name: "VAT 16%", Rate: 1600, Base: 78, Amount: 12, Inclusive: true}}
+	order.Metadata = orders.Metadata{"table": "4", "guests": 2.0}
+	order.Paid, order.Tips = 100, 10
+
+	id, err := repo.Save(ctx, order)
+	if err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	if id != order.ID {
+		return fmt.Errorf("saving returned id %q, want %q", id, order.ID)
+	}
+	saved, err := repo.RetrieveByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	// Payments are only added to saved orders.
+	want := order
+	want.Paid, want.Tips = 0, 0
+	if err := sameOrder(saved, want); err != nil {
+		return err
+	}
+	if saved.Seq == 0 {
+		return fmt.Errorf("saved order has no change sequence")
+	}
+	if _, err := repo.Save(ctx, order); !is(err, errors.ErrConflict) {
+		return fmt.Errorf("saving an order twice returned %v, want %s", err, errors.ErrConflict)
+	}
+	if _, err := repo.RetrieveByID(ctx, ulid.Make().String()); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving a missing order returned %v, want %s", err, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func checkSaveWithKey(ctx context.Context, repo orders.OrderRepository) error {
+	vendor, key := newVendor(), ulid.Make().String()
+	order := newOrder(vendor, 0)
+	if _, err := repo.SaveWithKey(ctx, order, key); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	id, err := repo.RetrieveByKey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("retrieving by key: %w", err)
+	}
+	if id != order.ID {
+		return fmt.Errorf("key retrieved order %q, want %q", id, order.ID)
+	}
+
+	other := newOrder(vendor, 1)
+	if _, err := repo.SaveWithKey(ctx, other, key); !is(err, errors.ErrConflict) {
+		return fmt.Errorf("saving with a used key returned %v, want %s", err, errors.ErrConflict)
+	}
+	if _, err := repo.RetrieveByID(ctx, other.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("saving with a used key saved the order")
+	}
+	if _, err := repo.RetrieveByKey(ctx, ulid.Make().String()); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving a missing key returned %v, want %s", err, errors.ErrNotFound)
+	}
+
+	// The key goes with the order.
+	if err := repo.Delete(ctx, order.ID); err != nil {
+		return fmt.Errorf("deleting: %w", err)
+	}
+	if _, err := repo.RetrieveByKey(ctx, key); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving the key of a deleted order returned %v, want %s", err, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func checkSaveMany(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	first, second := newOrder(vendor, 0), newOrder(vendor, 1)
+	second.Paid, second.Tips, second.Status = 100, 20, orders.StatusPaid
+	ids, err := repo.SaveMany(ctx, []orders.Order{first, second})
+	if err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	if !reflect.DeepEqual(ids, []string{first.ID, second.ID}) {
+		return fmt.Errorf("saving returned ids %v, want %v", ids, []string{first.ID, second.ID})
+	}
+	saved, err := repo.RetrieveByID(ctx, second.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	if err := sameOrder(saved, second); err != nil {
+		return err
+	}
+
+	// A single order already saved fails the whole batch.
+	third := newOrder(vendor, 2)
+	if _, err := repo.SaveMany(ctx, []orders.Order{third, first}); !is(err, errors.ErrConflict) {
+		return fmt.Errorf("saving a saved order returned %v, want %s", err, errors.ErrConflict)
+	}
+	if _, err := repo.RetrieveByID(ctx, third.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("a failed batch saved some of its orders")
+	}
+	return nil
+}
+
+func checkFilters(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	ordered := newOrder(vendor, 0)
+	delivery := newOrder(vendor, 10)
+	delivery.Place = "delivery"
+	// Filters are bound rather than written into the query.
+	delivery.Name = "Mama's pilau"
+	delivery.Price = 350
+	preparing := newOrder(vendor, 20)
+	preparing.Status = orders.StatusPreparing
+	paid := newOrder(vendor, 30)
+	paid.Status, paid.Paid = orders.StatusPaid, paid.Price
+	if _, err := repo.SaveMany(ctx, []orders.Order{paid, preparing, delivery, ordered}); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+
+	cases := []struct {
+		name string
+		pm   orders.PageMetadata
+		want []orders.Order
+	}{
+		{"vendor", orders.PageMetadata{}, []orders.Order{ordered, delivery, preparing, paid}},
+		{"name", orders.PageMetadata{Name: "Mama's pilau"}, []orders.Order{delivery}},
+		{"price", orders.PageMetadata{Price: 350}, []orders.Order{delivery}},
+		{"place", orders.PageMetadata{Place: "delivery"}, []orders.Order{delivery}},
+		{"status", orders.PageMetadata{Status: orders.StatusPreparing}, []orders.Order{preparing}},
+		{"outstanding", orders.PageMetadata{Outstanding: true}, []orders.Order{ordered, delivery, preparing}},
+		{"from", orders.PageMetadata{From: base.Add(10 * time.Minute)}, []orders.Order{delivery, preparing, paid}},
+		{"to", orders.PageMetadata{To: base.Add(20 * time.Minute)}, []orders.Order{ordered, delivery}},
+		{"range", orders.PageMetadata{From: base.Add(5 * time.Minute), To: base.Add(25 * time.Minute)}, []orders.Order{delivery, preparing}},
+	}
+	for _, c := range cases {
+		c.pm.Vendor = vendor
+		c.pm.Limit = 10
+		if err := list(ctx, repo, c.pm, c.want); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+func checkMetadata(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	whatsapp := newOrder(vendor, 0)
+	whatsapp.Metadata = orders.Metadata{
+		orders.ChannelKey:  "whatsapp",
+		orders.CustomerKey: map[string]interface{}{"phone": "+254700000000", "tags": []interface{}{"regular", "vip"}},
+	}
+	ussd := newOrder(vendor, 1)
+	ussd.Metadata = orders.Metadata{orders.ChannelKey: "ussd", "guests": 2}
+	plain := newOrder(vendor, 2)
+	if _, err := repo.SaveMany(ctx, []orders.Order{whatsapp, ussd, plain}); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+
+	cases := []struct {
+		name     string
+		metadata orders.Metadata
+		want     []orders.Order
+	}{
+		{"key", orders.Metadata{orders.ChannelKey: "ussd"}, []orders.Order{ussd}},
+		{"number", orders.Metadata{"guests": 2}, []orders.Order{ussd}},
+		{"nested", orders.Metadata{orders.CustomerKey: map[string]interface{}{"phone": "+254700000000"}}, []orders.Order{whatsapp}},
+		{"array", orders.Metadata{orders.CustomerKey: map[string]interface{}{"tags": []interface{}{"vip"}}}, []orders.Order{whatsapp}},
+		{"missing", orders.Metadata{orders.ChannelKey: "telegram"}, nil},
+		{"all of", orders.Metadata{orders.ChannelKey: "ussd", "guests": 3}, nil},
+	}
+	for _, c := range cases {
+		pm := orders.PageMetadata{Vendor: vendor, Metadata: c.metadata, Limit: 10}
+		if err := list(ctx, repo, pm, c.want); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+func checkPages(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	var saved []orders.Order
+	for i := 0; i < 5; i++ {
+		saved = append(saved, newOrder(vendor, i))
+	}
+	// Orders taken at the same time are listed by id.
+	tie := newOrder(vendor, 4)
+	saved = append(saved, tie)
+	if _, err := repo.SaveMany(ctx, saved); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	last := saved[4:]
+	if last[1].ID < last[0].ID {
+		last[0], last[1] = last[1], last[0]
+	}
+
+	pages := []struct {
+		offset, limit uint64
+		want          []orders.Order
+	}{
+		{0, 2, saved[:2]},
+		{2, 2, saved[2:4]},
+		{4, 2, last},
+		{5, 10, last[1:]},
+		{6, 10, nil},
+	}
+	for _, p := range pages {
+		pm := orders.PageMetadata{Vendor: vendor, Offset: p.offset, Limit: p.limit}
+		if err := list(ctx, repo, pm, p.want); err != nil {
+			return fmt.Errorf("offset %d limit %d: %w", p.offset, p.limit, err)
+		}
+	}
+
+	// Pages read after the last order of the page before are read from
+	// where it left off, even once orders before it are deleted.
+	pm := orders.PageMetadata{Vendor: vendor, Limit: 2}
+	page, err := repo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return fmt.Errorf("listing the first page: %w", err)
+	}
+	if err := repo.Delete(ctx, saved[0].ID); err != nil {
+		return fmt.Errorf("deleting: %w", err)
+	}
+	var read []orders.Order
+	for len(page.Orders) > 0 {
+		read = append(read, page.Orders...)
+		pm.After = orders.CursorOf(page.Orders[len(page.Orders)-1])
+		if page, err = repo.RetrieveAll(ctx, pm); err != nil {
+			return fmt.Errorf("listing after %s: %w", pm.After.ID, err)
+		}
+	}
+	want := append(saved[:4:4], last...)
+	if len(read) != len(want) {
+		return fmt.Errorf("paging after cursors read %d orders, want %d", len(read), len(want))
+	}
+	for i := range want {
+		if read[i].ID != want[i].ID {
+			return fmt.Errorf("paging after cursors read %s at %d, want %s", read[i].ID, i, want[i].ID)
+		}
+	}
+	return nil
+}
+
+func checkUpdate(ctx context.Context, repo orders.OrderRepository) error {
+	order := newOrder(newVendor(), 0)
+	order.Metadata = orders.Metadata{"table": "4"}
+	if _, err := repo.Save(ctx, order); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	saved, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+
+	// Only the fields set are updated.
+	update := orders.Order{
+		ID:         order.ID,
+		Status:     orders.StatusPreparing,
+		AcceptedBy: "otieno",
+		UpdatedAt:  base.Add(time.Minute),
+	}
+	if _, err := repo.Update(ctx, update); err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+	update = orders.Order{
+		ID:         order.ID,
+		Items:      []orders.Item{{Name: "Chapati", Quantity: 2, Price: 50}},
+		AcceptedBy: "akinyi",
+		UpdatedAt:  base.Add(2 * time.Minute),
+	}
+	if _, err := repo.Update(ctx, update); err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+	updated, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	want := order
+	want.Status = orders.StatusPreparing
+	want.Items = update.Items
+	// The first staff member to accept the order keeps it.
+	want.AcceptedBy = "otieno"
+	if err := sameOrder(updated, want); err != nil {
+		return err
+	}
+	if updated.Seq <= saved.Seq {
+		return fmt.Errorf("updating kept the change sequence at %d", updated.Seq)
+	}
+	if !updated.UpdatedAt.After(saved.UpdatedAt) {
+		return fmt.Errorf("updating kept the update time")
+	}
+	return nil
+}
+
+func checkPayments(ctx context.Context, repo orders.OrderRepository) error {
+	order := newOrder(newVendor(), 0)
+	if _, err := repo.Save(ctx, order); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+
+	payments := []orders.Payment{
+		{ID: ulid.Make().String(), Order: order.ID, Method: "cash", Amount: 60, PaidBy: "otieno", CreatedAt: base.Add(time.Minute)},
+		{ID: ulid.Make().String(), Order: order.ID, Method: "mpesa", Amount: 40, Tip: 10, PaidBy: "akinyi", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: ulid.Make().String(), Order: order.ID, Method: "cash", Tip: 5, PaidBy: "baraka", CreatedAt: base.Add(3 * time.Minute)},
+	}
+	statuses := []string{orders.StatusOrdered, orders.StatusPaid, orders.StatusPaid}
+	paid := []uint64{60, 100, 100}
+	var seq uint64
+	for i, payment := range payments {
+		updated, err := repo.AddPayment(ctx, payment)
+		if err != nil {
+			return fmt.Errorf("adding payment %d: %w", i+1, err)
+		}
+		if updated.Status != statuses[i] || updated.Paid != paid[i] {
+			return fmt.Errorf("payment %d left the order %s with %d paid, want %s with %d", i+1, updated.Status, updated.Paid, statuses[i], paid[i])
+		}
+		if updated.Seq <= seq {
+			return fmt.Errorf("payment %d kept the change sequence at %d", i+1, updated.Seq)
+		}
+		seq = updated.Seq
+	}
+	// A payment added again is refused, it must not count twice.
+	if _, err := repo.AddPayment(ctx, payments[0]); !is(err, errors.ErrConflict) {
+		return fmt.Errorf("adding a payment again returned %v, want %s", err, errors.ErrConflict)
+	}
+
+	settled, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	// The order is settled by the payment covering its price.
+	if settled.PaidBy != "akinyi" || settled.Tips != 15 {
+		return fmt.Errorf("order paid by %q with %d tips, want %q with %d", settled.PaidBy, settled.Tips, "akinyi", 15)
+	}
+
+	kept, err := repo.RetrievePayments(ctx, []string{order.ID, ulid.Make().String()})
+	if err != nil {
+		return fmt.Errorf("retrieving payments: %w", err)
+	}
+	if len(kept) != len(payments) {
+		return fmt.Errorf("retrieved %d payments, want %d", len(kept), len(payments))
+	}
+	for i, payment := range kept {
+		if payment.ID != payments[i].ID || payment.Vendor != order.Vendor || payment.Amount != payments[i].Amount || payment.Tip != payments[i].Tip {
+			return fmt.Errorf("payment %d is %+v, want %+v of vendor %s", i+1, payment, payments[i], order.Vendor)
+		}
+	}
+
+	missing := orders.Payment{ID: ulid.Make().String(), Order: ulid.Make().String(), Amount: 10, CreatedAt: base}
+	if _, err := repo.AddPayment(ctx, missing); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("paying a missing order returned %v, want %s", err, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func checkPaid(ctx context.Context, repo orders.OrderRepository) error {
+	order := newOrder(newVendor(), 0)
+	if _, err := repo.Save(ctx, order); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	// Orders with a balance are settled by payments, not marked paid.
+	if _, err := repo.Update(ctx, orders.Order{ID: order.ID, Status: orders.StatusPaid, UpdatedAt: base}); !is(err, orders.ErrOutstanding) || !is(err, errors.ErrConflict) {
+		return fmt.Errorf("marking an order with a balance paid returned %v, want %s", err, orders.ErrOutstanding)
+	}
+	kept, err := repo.RetrieveByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	if kept.Status != orders.StatusOrdered {
+		return fmt.Errorf("refused update left the order %s", kept.Status)
+	}
+
+	payment := orders.Payment{ID: ulid.Make().String(), Order: order.ID, Method: "cash", Amount: order.Price, PaidBy: "akinyi", CreatedAt: base.Add(time.Minute)}
+	if _, err := repo.AddPayment(ctx, payment); err != nil {
+		return fmt.Errorf("adding payment: %w", err)
+	}
+	// A settled order can still be written, as long as it stays settled.
+	if _, err := repo.Update(ctx, orders.Order{ID: order.ID, Name: "Chapati x2", UpdatedAt: base.Add(2 * time.Minute)}); err != nil {
+		return fmt.Errorf("updating a settled order: %w", err)
+	}
+	if _, err := repo.Update(ctx, orders.Order{ID: order.ID, Price: order.Price + 50, UpdatedAt: base.Add(3 * time.Minute)}); !is(err, orders.ErrOutstanding) {
+		return fmt.Errorf("raising the price of a settled order returned %v, want %s", err, orders.ErrOutstanding)
+	}
+	return nil
+}
+
+func checkChanges(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	first, second, third := newOrder(vendor, 0), newOrder(vendor, 1), newOrder(vendor, 2)
+	for _, order := range []orders.Order{first, second, third} {
+		if _, err := repo.Save(ctx, order); err != nil {
+			return fmt.Errorf("saving: %w", err)
+		}
+	}
+	// Changing the first order moves it after the others.
+	if _, err := repo.Update(ctx, orders.Order{ID: first.ID, Status: orders.StatusPreparing, UpdatedAt: base}); err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+	if err := repo.Delete(ctx, second.ID); err != nil {
+		return fmt.Errorf("deleting: %w", err)
+	}
+	tombstone, err := repo.RetrieveTombstone(ctx, second.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving the tombstone: %w", err)
+	}
+
+	page, err := repo.RetrieveChanges(ctx, vendor, 0, 10)
+	if err != nil {
+		return fmt.Errorf("retrieving changes: %w", err)
+	}
+	if ids := orderIDs(page.Orders); !reflect.DeepEqual(ids, []string{third.ID, first.ID}) {
+		return fmt.Errorf("changed orders %v, want %v", ids, []string{third.ID, first.ID})
+	}
+	if len(page.Deleted) != 1 || page.Deleted[0].ID != second.ID || page.Deleted[0].Seq != tombstone.Seq {
+		return fmt.Errorf("deleted orders %+v, want %+v", page.Deleted, tombstone)
+	}
+	if page.More || page.Seq != tombstone.Seq || page.Orders[1].Seq >= tombstone.Seq {
+		return fmt.Errorf("changes page ends at %d with more %t, want %d without more", page.Seq, page.More, tombstone.Seq)
+	}
+
+	// Pages pick up after the last change they saw.
+	page, err = repo.RetrieveChanges(ctx, vendor, 0, 1)
+	if err != nil {
+		return fmt.Errorf("retrieving changes: %w", err)
+	}
+	if ids := orderIDs(page.Orders); !reflect.DeepEqual(ids, []string{third.ID}) || !page.More {
+		return fmt.Errorf("first page of changes %v with more %t, want %v with more", ids, page.More, []string{third.ID})
+	}
+	page, err = repo.RetrieveChanges(ctx, vendor, page.Seq, 10)
+	if err != nil {
+		return fmt.Errorf("retrieving changes: %w", err)
+	}
+	if ids := orderIDs(page.Orders); !reflect.DeepEqual(ids, []string{first.ID}) || len(page.Deleted) != 1 {
+		return fmt.Errorf("second page of changes %v and %d deleted, want %v and 1 deleted", ids, len(page.Deleted), []string{first.ID})
+	}
+	return nil
+}
+
+func checkDelete(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	kept, deleted := newOrder(vendor, 0), newOrder(vendor, 1)
+	if _, err := repo.Save(ctx, kept); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	key := "repotest-" + ulid.Make().String()
+	if _, err := repo.SaveWithKey(ctx, deleted, key); err != nil {
+		return fmt.Errorf("saving with key: %w", err)
+	}
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		return fmt.Errorf("deleting: %w", err)
+	}
+	if _, err := repo.RetrieveByID(ctx, deleted.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving a deleted order returned %v, want %s", err, errors.ErrNotFound)
+	}
+	if _, err := repo.RetrieveByKey(ctx, key); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving a deleted order by its key returned %v, want %s", err, errors.ErrNotFound)
+	}
+	if err := list(ctx, repo, orders.PageMetadata{Vendor: vendor, Limit: 10}, []orders.Order{kept}); err != nil {
+		return fmt.Errorf("listing after deleting: %w", err)
+	}
+
+	tombstone, err := repo.RetrieveTombstone(ctx, deleted.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving the tombstone: %w", err)
+	}
+	if tombstone.ID != deleted.ID || tombstone.Vendor != vendor || tombstone.Seq == 0 || tombstone.DeletedAt.IsZero() {
+		return fmt.Errorf("tombstone %+v, want one of order %s of vendor %q", tombstone, deleted.ID, vendor)
+	}
+	if _, err := repo.RetrieveTombstone(ctx, kept.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving the tombstone of a live order returned %v, want %s", err, errors.ErrNotFound)
+	}
+
+	// Deleting what is not there leaves no tombstone and the one left
+	// before as it was.
+	missing := ulid.Make().String()
+	if err := repo.Delete(ctx, missing); err != nil {
+		return fmt.Errorf("deleting a missing order: %w", err)
+	}
+	if _, err := repo.RetrieveTombstone(ctx, missing); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("deleting a missing order left a tombstone")
+	}
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		return fmt.Errorf("deleting a deleted order: %w", err)
+	}
+	if again, err := repo.RetrieveTombstone(ctx, deleted.ID); err != nil || again.Seq != tombstone.Seq {
+		return fmt.Errorf("deleting a deleted order moved its tombstone from %d to %+v (%v)", tombstone.Seq, again, err)
+	}
+
+	// Saving a deleted order again brings it back.
+	if _, err := repo.Save(ctx, deleted); err != nil {
+		return fmt.Errorf("saving a deleted order: %w", err)
+	}
+	if _, err := repo.RetrieveTombstone(ctx, deleted.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("saving a deleted order kept its tombstone")
+	}
+	if _, err := repo.RetrieveByID(ctx, deleted.ID); err != nil {
+		return fmt.Errorf("retrieving an order saved again: %w", err)
+	}
+	return nil
+}
+
+func checkTransitions(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	ordered, preparing, paid := newOrder(vendor, 0), newOrder(vendor, 1), newOrder(vendor, 2)
+	preparing.Status = orders.StatusPreparing
+	paid.Status = orders.StatusPaid
+	if _, err := repo.Save(ctx, ordered); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	if _, err := repo.SaveWithKey(ctx, preparing, "repotest-"+ulid.Make().String()); err != nil {
+		return fmt.Errorf("saving with key: %w", err)
+	}
+	if _, err := repo.SaveMany(ctx, []orders.Order{paid}); err != nil {
+		return fmt.Errorf("saving many: %w", err)
+	}
+	// Writes leaving the status as it was record nothing.
+	if _, err := repo.Update(ctx, orders.Order{ID: preparing.ID, Status: orders.StatusPreparing, UpdatedAt: base}); err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+	if _, err := repo.Update(ctx, orders.Order{ID: ordered.ID, Status: orders.StatusPreparing, UpdatedAt: base}); err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+	payment := orders.Payment{ID: ulid.Make().String(), Order: ordered.ID, Method: "cash", Amount: ordered.Price, CreatedAt: base}
+	if _, err := repo.AddPayment(ctx, payment); err != nil {
+		return fmt.Errorf("adding a payment: %w", err)
+	}
+
+	want := []orders.Transition{
+		{Order: preparing.ID, Status: orders.StatusPreparing},
+		{Order: paid.ID, Status: orders.StatusPaid},
+		{Order: ordered.ID, Status: orders.StatusPreparing},
+		{Order: ordered.ID, Status: orders.StatusPaid},
+	}
+	got, err := transitionsOf(ctx, repo, ordered.ID, preparing.ID, paid.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving transitions: %w", err)
+	}
+	if len(got) != len(want) {
+		return fmt.Errorf("recorded transitions %+v, want %+v", got, want)
+	}
+	for i, t := range got {
+		if t.Order != want[i].Order || t.Status != want[i].Status || (i > 0 && t.ID <= got[i-1].ID) {
+			return fmt.Errorf("recorded transitions %+v, want %+v in order", got, want)
+		}
+	}
+
+	for _, t := range got {
+		if err := repo.RemoveTransition(ctx, t.ID); err != nil {
+			return fmt.Errorf("removing a transition: %w", err)
+		}
+	}
+	got, err = transitionsOf(ctx, repo, ordered.ID, preparing.ID, paid.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving transitions: %w", err)
+	}
+	if len(got) != 0 {
+		return fmt.Errorf("transitions %+v left after removing them", got)
+	}
+	return nil
+}
+
+// transitionsOf reads every transition left, a page at a time, and returns
+// those of the orders with the unique identifiers ids.
+func checkTransactions(ctx context.Context, repo orders.OrderRepository) error {
+	vendor := newVendor()
+	kept, undone, inner := newOrder(vendor, 0), newOrder(vendor, 1), newOrder(vendor, 2)
+	if _, err := repo.Save(ctx, kept); err != nil {
+		return fmt.Errorf("saving: %w", err)
+	}
+	errUndo := errors.New("undo")
+
+	// A failed transaction leaves nothing of what it wrote.
+	err := repo.InTransaction(ctx, func(ctx context.Context) error {
+		undone.Status = orders.StatusPreparing
+		if _, err := repo.Save(ctx, undone); err != nil {
+			return fmt.Errorf("saving: %w", err)
+		}
+		if _, err := repo.Update(ctx, orders.Order{ID: kept.ID, Name: "Mandazi", UpdatedAt: base}); err != nil {
+			return fmt.Errorf("updating: %w", err)
+		}
+		// Writes are read back within the transaction.
+		updated, err := repo.RetrieveByID(ctx, kept.ID)
+		if err != nil {
+			return fmt.Errorf("retrieving: %w", err)
+		}
+		if updated.Name != "Mandazi" {
+			return fmt.Errorf("order read back as %q within the transaction, want %q", updated.Name, "Mandazi")
+		}
+		if err := repo.Delete(ctx, kept.ID); err != nil {
+			return fmt.Errorf("deleting: %w", err)
+		}
+		return errUndo
+	})
+	if err != errUndo {
+		return fmt.Errorf("failed transaction returned %v, want %v", err, errUndo)
+	}
+	if _, err := repo.RetrieveByID(ctx, undone.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving an order saved in a failed transaction returned %v, want %s", err, errors.ErrNotFound)
+	}
+	if _, err := repo.RetrieveTombstone(ctx, kept.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving the tombstone of a delete undone returned %v, want %s", err, errors.ErrNotFound)
+	}
+	got, err := repo.RetrieveByID(ctx, kept.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	if err := sameOrder(got, kept); err != nil {
+		return fmt.Errorf("order updated in a failed transaction: %w", err)
+	}
+	transitions, err := transitionsOf(ctx, repo, undone.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving transitions: %w", err)
+	}
+	if len(transitions) != 0 {
+		return fmt.Errorf("failed transaction left transitions %+v", transitions)
+	}
+
+	// A transaction failing within one only undoes what it wrote.
+	err = repo.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := repo.Update(ctx, orders.Order{ID: kept.ID, Name: "Mandazi", UpdatedAt: base}); err != nil {
+			return fmt.Errorf("updating: %w", err)
+		}
+		err := repo.InTransaction(ctx, func(ctx context.Context) error {
+			if _, err := repo.Save(ctx, inner); err != nil {
+				return fmt.Errorf("saving: %w", err)
+			}
+			return errUndo
+		})
+		if err != errUndo {
+			return fmt.Errorf("failed inner transaction returned %v, want %v", err, errUndo)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("running a transaction: %w", err)
+	}
+	if _, err := repo.RetrieveByID(ctx, inner.ID); !is(err, errors.ErrNotFound) {
+		return fmt.Errorf("retrieving an order saved in a failed inner transaction returned %v, want %s", err, errors.ErrNotFound)
+	}
+	got, err = repo.RetrieveByID(ctx, kept.ID)
+	if err != nil {
+		return fmt.Errorf("retrieving: %w", err)
+	}
+	if got.Name != "Mandazi" {
+		return fmt.Errorf("order committed as %q, want %q", got.Name, "Mandazi")
+	}
+	return nil
+}
+
+func transitionsOf(ctx context.Context, repo orders.OrderRepository, ids ...string) ([]orders.Transition, error) {
+	const limit = 2
+	var transitions []orders.Transition
+	var after uint64
+	for {
+		page, err := repo.RetrieveTransitions(ctx, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) > limit {
+			return nil, fmt.Errorf("retrieved %d transitions, want at most %d", len(page), limit)
+		}
+		for _, t := range page {
+			if t.ID <= after {
+				return nil, fmt.Errorf("transition %d retrieved after %d", t.ID, after)
+			}
+			after = t.ID
+			for _, id := range ids {
+				if t.Order == id {
+					transitions = append(transitions, t)
+				}
+			}
+		}
+		if len(page) < limit {
+			return transitions, nil
+		}
+	}
+}
+
+// list checks that listing the page returns the orders wanted, in order,
+// and counts them all.
+func list(ctx context.Context, repo orders.OrderRepository, pm orders.PageMetadata, want []orders.Order) error {
+	page, err := repo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return err
+	}
+	if got, want := orderIDs(page.Orders), orderIDs(want); !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("listed %v, want %v", got, want)
+	}
+	if page.Offset != pm.Offset || page.Limit != pm.Limit {
+		return fmt.Errorf("page at offset %d limit %d, want offset %d limit %d", page.Offset, page.Limit, pm.Offset, pm.Limit)
+	}
+	if page.Total < uint64(len(want)) || (pm.Offset == 0 && len(want) < int(pm.Limit) && page.Total != uint64(len(want))) {
+		return fmt.Errorf("page counts %d orders for %d listed", page.Total, len(want))
+	}
+	return nil
+}
+
+// sameOrder checks that the order read back is the one saved, leaving out
+// what the repository sets and the times it may store at another precision.
+func sameOrder(got, want orders.Order) error {
+	got.Seq, want.Seq = 0, 0
+	got.CreatedAt, want.CreatedAt = time.Time{}, time.Time{}
+	got.UpdatedAt, want.UpdatedAt = time.Time{}, time.Time{}
+	// Repositories read empty lines and metadata back empty rather than nil.
+	for _, o := range []*orders.Order{&got, &want} {
+		if len(o.Items) == 0 {
+			o.Items = nil
+		}
+		if len(o.Adjustments) == 0 {
+			o.Adjustments = nil
+		}
+		if len(o.Taxes) == 0 {
+			o.Taxes = nil
+		}
+		if len(o.Metadata) == 0 {
+			o.Metadata = nil
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("order read back as %+v, want %+v", got, want)
+	}
+	return nil
+}
+
+// is reports whether err is target, alone or combined with the error of the
+// database as the postgres repository does.
+func is(err, target error) bool {
+	for _, err := range multierr.Errors(err) {
+		if errors.Contains(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func orderIDs(os []orders.Order) []string {
+	var ids []string
+	for _, o := range os {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
 	"github.com/oklog/ulid/v2"
 )
 
@@ -18,6 +19,30 @@ type PageMetadata struct {
 	Place    string
 	Metadata Metadata
 	Status   string
+
+	// Outstanding limits the page to orders with a balance left to pay.
+	Outstanding bool
+
+	From time.Time // Limits the page to orders created from.
+	To   time.Time // Limits the page to orders created before.
+
+	// After limits the page to orders listed after the cursor, when it is
+	// set. Pages read after the last order of the page before stay whole
+	// while orders are taken and deleted, unlike pages read at an offset.
+	After Cursor
+}
+
+// Cursor is the place of an order in the orders listed, by creation time
+// then identifier. Its time is the order's as read back from the
+// repository.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorOf returns the place of the order in the orders listed.
+func CursorOf(order Order) Cursor {
+	return Cursor{CreatedAt: order.CreatedAt, ID: order.ID}
 }
 
 // OrdersPage contains a page of orders.
@@ -32,7 +57,9 @@ type orderService struct {
 	orders OrderRepository
 }
 
-// NewOrderService instantiates the users service implementation
+// NewOrderService instantiates the users service implementation. Hooks are
+// notified of the orders changing state by a Notifier on the same
+// repository.
 func NewOrderService(orders OrderRepository) OrderService {
 	return &orderService{
 		orders: orders,
@@ -43,14 +70,46 @@ func (svc orderService) CreateOrder(ctx context.Context, token string, order Ord
 	if err := order.Validate(); err != nil {
 		return "", err
 	}
+	if len(order.Items) > 0 {
+		if order.Price == 0 {
+			order.Price = order.ItemsTotal()
+		}
+		if order.Name == "" {
+			order.Name = order.ItemsName()
+		}
+	}
+	// The price is charged net of discounts, adjustments keep what was
+	// taken off. Taxes not included in the item prices are added on top.
+	discount := order.Discount()
+	if discount > order.Price {
+		return "", errors.ErrMalformedEntity
+	}
+	order.Price -= discount
+	order.Price += order.ExclusiveTax()
+	// Orders are saved with nothing paid, payments settle them after.
+	if err := ValidatePaid(Order{}, order); err != nil {
+		return "", err
+	}
+	order.CreatedBy, order.AcceptedBy, order.PaidBy = Staff(ctx), "", ""
+	switch order.Status {
+	case StatusPreparing:
+		order.AcceptedBy = order.CreatedBy
+	case StatusPaid:
+		order.PaidBy = order.CreatedBy
+	}
 	order.ID = ulid.Make().String()
 	order.CreatedAt = time.Now()
 	order.UpdatedAt = time.Now()
-	uid, err := svc.orders.Save(ctx, order)
-	if err != nil {
-		return "", err
+	return svc.save(ctx, order)
+}
+
+// save saves the order with the idempotency key in ctx, if any. Saving an
+// order with a key another order was saved with fails with ErrConflict.
+func (svc orderService) save(ctx context.Context, order Order) (string, error) {
+	if key := IdempotencyKey(ctx); key != "" {
+		return svc.orders.SaveWithKey(ctx, order, key)
 	}
-	return uid, nil
+	return svc.orders.Save(ctx, order)
 }
 
 func (svc orderService) ViewOrder(ctx context.Context, token, id string) (Order, error) {
@@ -68,12 +127,62 @@ func (svc orderService) UpdateOrder(ctx context.Context, token string, order Ord
 		Price:     order.Price,
 		Place:     order.Place,
 		Status:    order.Status,
+		Items:     order.Items,
 		Metadata:  order.Metadata,
 		UpdatedAt: time.Now(),
 	}
+	switch order.Status {
+	case StatusPreparing:
+		uOrder.AcceptedBy = Staff(ctx)
+	case StatusPaid:
+		uOrder.PaidBy = Staff(ctx)
+	}
 	return svc.orders.Update(ctx, uOrder)
 }
 
 func (svc orderService) DeleteOrder(ctx context.Context, token string, id string) error {
 	return svc.orders.Delete(ctx, id)
 }
+
+func (svc orderService) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (Order, error) {
+	if amount == 0 && tip == 0 {
+		return Order{}, errors.ErrMalformedEntity
+	}
+	payment := Payment{
+		ID:        ulid.Make().String(),
+		Order:     id,
+		Method:    method,
+		Amount:    amount,
+		Tip:       tip,
+		PaidBy:    Staff(ctx),
+		CreatedAt: time.Now(),
+	}
+	// A payment retried with the same key was recorded the first time.
+	key := PaymentKey(ctx)
+	if key != "" {
+		payment.ID = key
+	}
+	order, err := svc.orders.AddPayment(ctx, payment)
+	if key != "" && errors.Contains(err, errors.ErrConflict) && svc.recorded(ctx, id, key) {
+		return svc.orders.RetrieveByID(ctx, id)
+	}
+	if err != nil {
+		return Order{}, err
+	}
+	return order, nil
+}
+
+// recorded reports whether the payment with the unique identifier
+// paymentID was recorded against the order with the unique identifier id.
+func (svc orderService) recorded(ctx context.Context, id, paymentID string) bool {
+	payments, err := svc.orders.RetrievePayments(ctx, []string{id})
+	if err != nil {
+		return false
+	}
+	for _, p := range payments {
+		if p.ID == paymentID {
+			return true
+		}
+	}
+	return false
+}
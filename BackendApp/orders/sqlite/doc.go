@@ -0,0 +1,3 @@
+// Package sqlite contains repository implementations using SQLite as the
+// underlying database, for deployments too small to run PostgreSQL.
+package sqlite
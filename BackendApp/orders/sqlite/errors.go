@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"go.uber.org/multierr"
+	"modernc.org/sqlite"
+)
+
+// SQLite primary result codes, extended codes keep them in their low byte:
+// https://www.sqlite.org/rescode.html
+const (
+	errConstraint = 19 // SQLITE_CONSTRAINT
+	errTooBig     = 18 // SQLITE_TOOBIG
+)
+
+func handleError(err, wrapper error) error {
+	if sqliteErr, ok := err.(*sqlite.Error); ok {
+		switch sqliteErr.Code() & 0xff {
+		case errConstraint:
+			return multierr.Combine(errors.ErrConflict, err)
+		case errTooBig:
+			return multierr.Combine(errors.ErrMalformedEntity, err)
+		}
+	}
+	return multierr.Combine(wrapper, err)
+}
+
+func isConflict(err error) bool {
+	sqliteErr, ok := err.(*sqlite.Error)
+	return ok && sqliteErr.Code()&0xff == errConstraint
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+	"modernc.org/sqlite"
+)
+
+// MemoryPath is the path of a database kept in memory, lost once it is
+// closed.
+const MemoryPath = ":memory:"
+
+// Config defines the options that are used when opening a SQLite database.
+type Config struct {
+	Path string // The database file, created if missing.
+}
+
+// migrationFiles are the migrations of the database, see the postgres
+// package for how they are written.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+func init() {
+	// Listing orders filters their metadata by containment, as PostgreSQL
+	// does with @> on JSONB.
+	sqlite.MustRegisterDeterministicScalarFunction("metadata_contains", 2, metadataContains)
+}
+
+// Connect opens the SQLite database and applies any unapplied database
+// migrations. A non-nil error is returned to indicate failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)", cfg.Path)
+	db, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Writes are serialised by SQLite anyway, a single connection spares
+	// transactions waiting on the file lock and keeps a database in memory
+	// from being opened once per connection.
+	db.SetMaxOpenConns(1)
+	if err := migrateDB(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return err
+	}
+	migrations := migrate.HttpFileSystemMigrationSource{FileSystem: http.FS(dir)}
+	_, err = migrate.Exec(db.DB, "sqlite3", migrations, migrate.Up)
+	return err
+}
+
+func metadataContains(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var metadata, other orders.Metadata
+	for i, v := range []*orders.Metadata{&metadata, &other} {
+		var data []byte
+		switch arg := args[i].(type) {
+		case string:
+			data = []byte(arg)
+		case []byte:
+			data = arg
+		case nil:
+			return int64(0), nil
+		default:
+			return nil, fmt.Errorf("metadata_contains: unexpected %T argument", arg)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+	}
+	ok, err := metadata.Contains(other)
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	return int64(1), nil
+}
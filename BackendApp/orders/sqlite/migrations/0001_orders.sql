@@ -0,0 +1,107 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS orders (
+	id 				TEXT NOT NULL PRIMARY KEY,
+	vendor 			TEXT NOT NULL,
+	name 			TEXT NOT NULL,
+	price 			INTEGER NOT NULL,
+	place 			TEXT NOT NULL DEFAULT '',
+	status 			TEXT NOT NULL DEFAULT '',
+	items 			TEXT NOT NULL DEFAULT '[]',
+	adjustments 	TEXT NOT NULL DEFAULT '[]',
+	taxes 			TEXT NOT NULL DEFAULT '[]',
+	paid 			INTEGER NOT NULL DEFAULT 0,
+	tips 			INTEGER NOT NULL DEFAULT 0,
+	metadata 		TEXT NOT NULL DEFAULT '{}',
+	created_by 		TEXT NOT NULL DEFAULT '',
+	accepted_by 	TEXT NOT NULL DEFAULT '',
+	paid_by 		TEXT NOT NULL DEFAULT '',
+	created_at 		TEXT NOT NULL,
+	updated_at 		TEXT NOT NULL,
+	seq 			INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS orders_created_at ON orders (created_at, id);
+
+CREATE INDEX IF NOT EXISTS orders_vendor_seq ON orders (vendor, seq);
+
+CREATE TABLE IF NOT EXISTS order_keys (
+	key 			TEXT NOT NULL PRIMARY KEY,
+	order_id 		TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+	created_at 		TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS order_payments (
+	id 				TEXT NOT NULL PRIMARY KEY,
+	order_id 		TEXT NOT NULL,
+	vendor 			TEXT NOT NULL,
+	method 			TEXT NOT NULL DEFAULT '',
+	amount 			INTEGER NOT NULL,
+	tip 			INTEGER NOT NULL DEFAULT 0,
+	paid_by 		TEXT NOT NULL DEFAULT '',
+	created_at 		TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_payments_order ON order_payments (order_id);
+
+CREATE TABLE IF NOT EXISTS order_tombstones (
+	id 				TEXT NOT NULL PRIMARY KEY,
+	vendor 			TEXT NOT NULL,
+	seq 			INTEGER NOT NULL,
+	deleted_at 		TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_tombstones_vendor_seq ON order_tombstones (vendor, seq);
+
+-- SQLite has no sequences, the change sequence is kept in a single row.
+CREATE TABLE IF NOT EXISTS order_changes (
+	seq 			INTEGER NOT NULL
+);
+
+INSERT INTO order_changes (seq) SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM order_changes);
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS orders_inserted AFTER INSERT ON orders
+BEGIN
+	UPDATE order_changes SET seq = seq + 1;
+	UPDATE orders SET seq = (SELECT seq FROM order_changes) WHERE id = NEW.id;
+	DELETE FROM order_tombstones WHERE id = NEW.id;
+END;
+-- +migrate StatementEnd
+
+-- Setting the sequence of an order does not count as another change of it.
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS orders_updated
+AFTER UPDATE OF vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at ON orders
+BEGIN
+	UPDATE order_changes SET seq = seq + 1;
+	UPDATE orders SET seq = (SELECT seq FROM order_changes) WHERE id = NEW.id;
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS orders_deleted AFTER DELETE ON orders
+BEGIN
+	UPDATE order_changes SET seq = seq + 1;
+	INSERT INTO order_tombstones (id, vendor, seq, deleted_at)
+	VALUES (OLD.id, OLD.vendor, (SELECT seq FROM order_changes), strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+	ON CONFLICT (id) DO UPDATE SET vendor = excluded.vendor, seq = excluded.seq, deleted_at = excluded.deleted_at;
+	DELETE FROM order_keys WHERE order_id = OLD.id;
+END;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS orders_deleted;
+
+DROP TRIGGER IF EXISTS orders_updated;
+
+DROP TRIGGER IF EXISTS orders_inserted;
+
+DROP TABLE IF EXISTS order_changes;
+
+DROP TABLE IF EXISTS order_tombstones;
+
+DROP TABLE IF EXISTS order_payments;
+
+DROP TABLE IF EXISTS order_keys;
+
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,31 @@
+-- +migrate Up
+-- Orders moving to a status the hooks are notified of, recorded by triggers
+-- in the writes that move them.
+CREATE TABLE IF NOT EXISTS order_transitions (
+	id 				INTEGER PRIMARY KEY AUTOINCREMENT,
+	order_id 		TEXT NOT NULL,
+	status 			TEXT NOT NULL
+);
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS orders_inserted_transition AFTER INSERT ON orders
+WHEN NEW.status IN ('preparing', 'paid')
+BEGIN
+	INSERT INTO order_transitions (order_id, status) VALUES (NEW.id, NEW.status);
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS orders_updated_transition AFTER UPDATE OF status ON orders
+WHEN NEW.status <> OLD.status AND NEW.status IN ('preparing', 'paid')
+BEGIN
+	INSERT INTO order_transitions (order_id, status) VALUES (NEW.id, NEW.status);
+END;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS orders_updated_transition;
+
+DROP TRIGGER IF EXISTS orders_inserted_transition;
+
+DROP TABLE IF EXISTS order_transitions;
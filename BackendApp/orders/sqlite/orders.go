@@ -0,0 +1,686 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/multierr"
+)
+
+// timeLayout is how times are stored, in UTC. Its fixed width keeps times
+// in order when compared as text.
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+const orderColumns = `id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at, seq`
+
+var _ orders.OrderRepository = (*orderRepo)(nil)
+
+type orderRepo struct {
+	db *sqlx.DB
+}
+
+// NewOrderRepo instantiates a SQLite
+// implementation of the orders repository.
+func NewOrderRepo(db *sqlx.DB) orders.OrderRepository {
+	return &orderRepo{
+		db: db,
+	}
+}
+
+func (repo orderRepo) Save(ctx context.Context, order orders.Order) (string, error) {
+	q := `INSERT INTO orders (id, vendor, name, price, place, status, items, adjustments, taxes, metadata, created_by, accepted_by, paid_by, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :price, :place, :status, :items, :adjustments, :taxes, :metadata, :created_by, :accepted_by, :paid_by, :created_at, :updated_at)`
+
+	dbo, err := toDBOrder(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	if _, err := sqlx.NamedExecContext(ctx, repo.querier(ctx), q, dbo); err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	return order.ID, nil
+}
+
+func (repo orderRepo) SaveWithKey(ctx context.Context, order orders.Order, key string) (string, error) {
+	q := `INSERT INTO orders (id, vendor, name, price, place, status, items, adjustments, taxes, metadata, created_by, accepted_by, paid_by, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :price, :place, :status, :items, :adjustments, :taxes, :metadata, :created_by, :accepted_by, :paid_by, :created_at, :updated_at)`
+	kq := `INSERT INTO order_keys (key, order_id, created_at) VALUES (?, ?, ?)`
+
+	dbo, err := toDBOrder(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.NamedExecContext(ctx, q, dbo); err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	if _, err := tx.ExecContext(ctx, kq, key, order.ID, dbo.CreatedAt); err != nil {
+		if isConflict(err) {
+			return "", errors.Wrap(errors.ErrConflict, err)
+		}
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	return order.ID, nil
+}
+
+func (repo orderRepo) RetrieveByKey(ctx context.Context, key string) (string, error) {
+	q := `SELECT order_id FROM order_keys WHERE key = ?`
+
+	var id string
+	if err := repo.querier(ctx).QueryRowxContext(ctx, q, key).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.Wrap(errors.ErrNotFound, err)
+		}
+		return "", multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return id, nil
+}
+
+func (repo orderRepo) SaveMany(ctx context.Context, orders []orders.Order) ([]string, error) {
+	q := `INSERT INTO orders (id, vendor, name, price, place, status, items, adjustments, taxes, paid, tips, metadata, created_by, accepted_by, paid_by, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :price, :place, :status, :items, :adjustments, :taxes, :paid, :tips, :metadata, :created_by, :accepted_by, :paid_by, :created_at, :updated_at)`
+
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	defer tx.Rollback()
+
+	// Statements are cheap in SQLite, the orders are inserted one by one
+	// so the sequence triggers number them in order.
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		dbo, err := toDBOrder(order)
+		if err != nil {
+			return nil, multierr.Combine(errors.ErrCreateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, q, dbo); err != nil {
+			return nil, handleError(err, errors.ErrCreateEntity)
+		}
+		ids = append(ids, order.ID)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	return ids, nil
+}
+
+func (repo orderRepo) RetrieveByID(ctx context.Context, id string) (orders.Order, error) {
+	return retrieve(ctx, repo.querier(ctx), id)
+}
+
+// retrieve reads the order with the unique identifier id, in or out of a
+// transaction.
+func retrieve(ctx context.Context, q sqlx.QueryerContext, id string) (orders.Order, error) {
+	sq := fmt.Sprintf(`SELECT %s FROM orders WHERE id = ?`, orderColumns)
+
+	dbo := dbOrder{}
+	if err := q.QueryRowxContext(ctx, sq, id).StructScan(&dbo); err != nil {
+		if err == sql.ErrNoRows {
+			return orders.Order{}, multierr.Combine(errors.ErrNotFound, err)
+		}
+		return orders.Order{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return toOrder(dbo)
+}
+
+func (repo orderRepo) RetrieveAll(ctx context.Context, pm orders.PageMetadata) (orders.OrdersPage, error) {
+	var query []string
+	var args []interface{}
+	if len(pm.Metadata) > 0 {
+		metadata, err := json.Marshal(pm.Metadata)
+		if err != nil {
+			return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		query = append(query, "metadata_contains(metadata, ?)")
+		args = append(args, string(metadata))
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = ?")
+		args = append(args, pm.Vendor)
+	}
+	if pm.Name != "" {
+		query = append(query, "name = ?")
+		args = append(args, pm.Name)
+	}
+	if pm.Price != 0 {
+		query = append(query, "price = ?")
+		args = append(args, pm.Price)
+	}
+	if pm.Place != "" {
+		query = append(query, "place = ?")
+		args = append(args, pm.Place)
+	}
+	if pm.Status != "" {
+		query = append(query, "status = ?")
+		args = append(args, pm.Status)
+	}
+	if pm.Outstanding {
+		query = append(query, "paid < price")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "created_at >= ?")
+		args = append(args, formatTime(pm.From))
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "created_at < ?")
+		args = append(args, formatTime(pm.To))
+	}
+	if pm.After.ID != "" {
+		query = append(query, "(created_at, id) > (?, ?)")
+		args = append(args, formatTime(pm.After.CreatedAt), pm.After.ID)
+	}
+	var emq string
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM orders %s ORDER BY created_at, id LIMIT ? OFFSET ?`, orderColumns, emq)
+	rows, err := repo.querier(ctx).QueryxContext(ctx, q, append(args, pm.Limit, pm.Offset)...)
+	if err != nil {
+		return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []orders.Order
+	for rows.Next() {
+		dbo := dbOrder{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		order, err := toOrder(dbo)
+		if err != nil {
+			return orders.OrdersPage{}, err
+		}
+		items = append(items, order)
+	}
+	rows.Close()
+
+	var total uint64
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM orders %s`, emq)
+	if err := sqlx.GetContext(ctx, repo.querier(ctx), &total, cq, args...); err != nil {
+		return orders.OrdersPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	page := orders.OrdersPage{
+		Orders: items,
+		PageMetadata: orders.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo orderRepo) Update(ctx context.Context, order orders.Order) (string, error) {
+	// Orders stay with the vendor they were taken for, their changes are
+	// read by vendor.
+	var query []string
+	if order.Name != "" {
+		query = append(query, "name = :name,")
+	}
+	if order.Price != 0 {
+		query = append(query, "price = :price,")
+	}
+	if order.Place != "" {
+		query = append(query, "place = :place,")
+	}
+	if order.Status != "" {
+		query = append(query, "status = :status,")
+	}
+	if order.Items != nil {
+		query = append(query, "items = :items,")
+	}
+	if order.Metadata != nil {
+		query = append(query, "metadata = :metadata,")
+	}
+	// The first staff member to accept or settle the order keeps it.
+	if order.AcceptedBy != "" {
+		query = append(query, "accepted_by = COALESCE(NULLIF(accepted_by, ''), :accepted_by),")
+	}
+	if order.PaidBy != "" {
+		query = append(query, "paid_by = COALESCE(NULLIF(paid_by, ''), :paid_by),")
+	}
+	q := fmt.Sprintf(`UPDATE orders SET %s updated_at = :updated_at WHERE id = :id`, strings.Join(query, " "))
+
+	dbu, err := toDBOrder(order)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	defer tx.Rollback()
+
+	before, err := retrieve(ctx, tx, order.ID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.NamedExecContext(ctx, q, dbu); err != nil {
+		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	after, err := retrieve(ctx, tx, order.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := orders.ValidatePaid(before, after); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	return order.ID, nil
+}
+
+func (repo orderRepo) Delete(ctx context.Context, id string) error {
+	q := `DELETE FROM orders WHERE id = ?`
+
+	if _, err := repo.querier(ctx).ExecContext(ctx, q, id); err != nil {
+		return multierr.Combine(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func (repo orderRepo) AddPayment(ctx context.Context, payment orders.Payment) (orders.Order, error) {
+	q := `UPDATE orders SET paid = paid + :amount, tips = tips + :tip,
+			status = CASE WHEN paid + :amount >= price THEN 'paid' ELSE status END,
+			paid_by = CASE WHEN paid < price AND paid + :amount >= price THEN :paid_by ELSE paid_by END, updated_at = :created_at
+		  WHERE id = :order_id`
+	sq := fmt.Sprintf(`SELECT %s FROM orders WHERE id = ?`, orderColumns)
+	pq := `INSERT INTO order_payments (id, order_id, vendor, method, amount, tip, paid_by, created_at)
+		   VALUES (:id, :order_id, :vendor, :method, :amount, :tip, :paid_by, :created_at)`
+
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExecContext(ctx, q, toDBPayment(payment))
+	if err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return orders.Order{}, multierr.Combine(errors.ErrNotFound, err)
+	}
+	// The sequence is set by a trigger after the update, the order is read
+	// back once it is.
+	dbo := dbOrder{}
+	if err := tx.QueryRowxContext(ctx, sq, payment.Order).StructScan(&dbo); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+
+	payment.Vendor = dbo.Vendor
+	if _, err := tx.NamedExecContext(ctx, pq, toDBPayment(payment)); err != nil {
+		if isConflict(err) {
+			return orders.Order{}, errors.Wrap(errors.ErrConflict, err)
+		}
+		return orders.Order{}, multierr.Combine(errors.ErrCreateEntity, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	return toOrder(dbo)
+}
+
+func (repo orderRepo) RetrieveChanges(ctx context.Context, vendor string, since, limit uint64) (orders.ChangesPage, error) {
+	// Reading a change past the limit tells whether changes were left out.
+	q := fmt.Sprintf(`SELECT %s FROM orders WHERE vendor = ? AND seq > ? ORDER BY seq LIMIT ?`, orderColumns)
+	tq := `SELECT id, vendor, seq, deleted_at FROM order_tombstones WHERE vendor = ? AND seq > ? ORDER BY seq LIMIT ?`
+
+	// Orders and tombstones are read in the same transaction, a change
+	// committed in between would be skipped by the next page.
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryxContext(ctx, q, vendor, since, limit+1)
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var written []orders.Order
+	for rows.Next() {
+		dbo := dbOrder{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		order, err := toOrder(dbo)
+		if err != nil {
+			return orders.ChangesPage{}, err
+		}
+		written = append(written, order)
+	}
+	rows.Close()
+
+	trows, err := tx.QueryxContext(ctx, tq, vendor, since, limit+1)
+	if err != nil {
+		return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer trows.Close()
+	var deleted []orders.Tombstone
+	for trows.Next() {
+		dbt := dbTombstone{}
+		if err := trows.StructScan(&dbt); err != nil {
+			return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		t, err := toTombstone(dbt)
+		if err != nil {
+			return orders.ChangesPage{}, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		deleted = append(deleted, t)
+	}
+
+	// Both lists are in the order of their changes, the page takes the
+	// first changes of either.
+	page := orders.ChangesPage{Seq: since}
+	i, j := 0, 0
+	for n := uint64(0); n < limit && (i < len(written) || j < len(deleted)); n++ {
+		if j == len(deleted) || (i < len(written) && written[i].Seq < deleted[j].Seq) {
+			page.Orders = append(page.Orders, written[i])
+			page.Seq = written[i].Seq
+			i++
+			continue
+		}
+		page.Deleted = append(page.Deleted, deleted[j])
+		page.Seq = deleted[j].Seq
+		j++
+	}
+	page.More = i < len(written) || j < len(deleted)
+	return page, nil
+}
+
+func (repo orderRepo) RetrieveTombstone(ctx context.Context, id string) (orders.Tombstone, error) {
+	q := `SELECT id, vendor, seq, deleted_at FROM order_tombstones WHERE id = ?`
+
+	dbt := dbTombstone{}
+	if err := repo.querier(ctx).QueryRowxContext(ctx, q, id).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return orders.Tombstone{}, multierr.Combine(errors.ErrNotFound, err)
+		}
+		return orders.Tombstone{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	t, err := toTombstone(dbt)
+	if err != nil {
+		return orders.Tombstone{}, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	return t, nil
+}
+
+func (repo orderRepo) RetrievePayments(ctx context.Context, ids []string) ([]orders.Payment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	q, args, err := sqlx.In(`SELECT id, order_id, vendor, method, amount, tip, paid_by, created_at FROM order_payments
+		  WHERE order_id IN (?) ORDER BY created_at, id`, ids)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrViewEntity, err)
+	}
+
+	rows, err := repo.querier(ctx).QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var payments []orders.Payment
+	for rows.Next() {
+		dbp := dbPayment{}
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		payment, err := toPayment(dbp)
+		if err != nil {
+			return nil, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
+func (repo orderRepo) RetrieveTransitions(ctx context.Context, after, limit uint64) ([]orders.Transition, error) {
+	q := `SELECT id, order_id, status FROM order_transitions WHERE id > ? ORDER BY id LIMIT ?`
+
+	rows, err := repo.querier(ctx).QueryxContext(ctx, q, after, limit)
+	if err != nil {
+		return nil, multierr.Combine(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var transitions []orders.Transition
+	for rows.Next() {
+		var t orders.Transition
+		if err := rows.Scan(&t.ID, &t.Order, &t.Status); err != nil {
+			return nil, multierr.Combine(errors.ErrViewEntity, err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, nil
+}
+
+func (repo orderRepo) RemoveTransition(ctx context.Context, id uint64) error {
+	q := `DELETE FROM order_transitions WHERE id = ?`
+
+	if _, err := repo.querier(ctx).ExecContext(ctx, q, id); err != nil {
+		return multierr.Combine(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func (repo orderRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := repo.begin(ctx)
+	if err != nil {
+		return multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx.Tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return multierr.Combine(errors.ErrUpdateEntity, err)
+	}
+	return nil
+}
+
+type dbOrder struct {
+	ID          string `db:"id"`
+	Vendor      string `db:"vendor"`
+	Name        string `db:"name"`
+	Price       uint64 `db:"price"`
+	Place       string `db:"place"`
+	Items       string `db:"items"`
+	Adjustments string `db:"adjustments"`
+	Taxes       string `db:"taxes"`
+	Paid        uint64 `db:"paid"`
+	Tips        uint64 `db:"tips"`
+	Metadata    string `db:"metadata"`
+	CreatedBy   string `db:"created_by"`
+	AcceptedBy  string `db:"accepted_by"`
+	PaidBy      string `db:"paid_by"`
+	Status      string `db:"status"`
+	CreatedAt   string `db:"created_at"`
+	UpdatedAt   string `db:"updated_at"`
+	Seq         uint64 `db:"seq"`
+}
+
+func toDBOrder(order orders.Order) (dbOrder, error) {
+	metadata, err := marshal(order.Metadata, len(order.Metadata), "{}")
+	if err != nil {
+		return dbOrder{}, err
+	}
+	items, err := marshal(order.Items, len(order.Items), "[]")
+	if err != nil {
+		return dbOrder{}, err
+	}
+	adjustments, err := marshal(order.Adjustments, len(order.Adjustments), "[]")
+	if err != nil {
+		return dbOrder{}, err
+	}
+	taxes, err := marshal(order.Taxes, len(order.Taxes), "[]")
+	if err != nil {
+		return dbOrder{}, err
+	}
+	return dbOrder{
+		ID:          order.ID,
+		Vendor:      order.Vendor,
+		Name:        order.Name,
+		Price:       order.Price,
+		Place:       order.Place,
+		Items:       items,
+		Adjustments: adjustments,
+		Taxes:       taxes,
+		Paid:        order.Paid,
+		Tips:        order.Tips,
+		Metadata:    metadata,
+		CreatedBy:   order.CreatedBy,
+		AcceptedBy:  order.AcceptedBy,
+		PaidBy:      order.PaidBy,
+		Status:      order.Status,
+		CreatedAt:   formatTime(order.CreatedAt),
+		UpdatedAt:   formatTime(order.UpdatedAt),
+	}, nil
+}
+
+func toOrder(dbo dbOrder) (orders.Order, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(dbo.Metadata), &metadata); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	var items []orders.Item
+	if err := json.Unmarshal([]byte(dbo.Items), &items); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	var adjustments []orders.Adjustment
+	if err := json.Unmarshal([]byte(dbo.Adjustments), &adjustments); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	var taxes []orders.Tax
+	if err := json.Unmarshal([]byte(dbo.Taxes), &taxes); err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	createdAt, err := parseTime(dbo.CreatedAt)
+	if err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	updatedAt, err := parseTime(dbo.UpdatedAt)
+	if err != nil {
+		return orders.Order{}, multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	return orders.Order{
+		ID:          dbo.ID,
+		Vendor:      dbo.Vendor,
+		Name:        dbo.Name,
+		Price:       dbo.Price,
+		Place:       dbo.Place,
+		Items:       items,
+		Adjustments: adjustments,
+		Taxes:       taxes,
+		Paid:        dbo.Paid,
+		Tips:        dbo.Tips,
+		Metadata:    metadata,
+		CreatedBy:   dbo.CreatedBy,
+		AcceptedBy:  dbo.AcceptedBy,
+		PaidBy:      dbo.PaidBy,
+		Status:      dbo.Status,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+		Seq:         dbo.Seq,
+	}, nil
+}
+
+// marshal returns v as JSON, or empty if v has no elements.
+func marshal(v interface{}, n int, empty string) (string, error) {
+	if n == 0 {
+		return empty, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", multierr.Combine(errors.ErrMalformedEntity, err)
+	}
+	return string(b), nil
+}
+
+type dbPayment struct {
+	ID        string `db:"id"`
+	Order     string `db:"order_id"`
+	Vendor    string `db:"vendor"`
+	Method    string `db:"method"`
+	Amount    uint64 `db:"amount"`
+	Tip       uint64 `db:"tip"`
+	PaidBy    string `db:"paid_by"`
+	CreatedAt string `db:"created_at"`
+}
+
+func toDBPayment(payment orders.Payment) dbPayment {
+	return dbPayment{
+		ID:        payment.ID,
+		Order:     payment.Order,
+		Vendor:    payment.Vendor,
+		Method:    payment.Method,
+		Amount:    payment.Amount,
+		Tip:       payment.Tip,
+		PaidBy:    payment.PaidBy,
+		CreatedAt: formatTime(payment.CreatedAt),
+	}
+}
+
+func toPayment(dbp dbPayment) (orders.Payment, error) {
+	createdAt, err := parseTime(dbp.CreatedAt)
+	if err != nil {
+		return orders.Payment{}, err
+	}
+	return orders.Payment{
+		ID:        dbp.ID,
+		Order:     dbp.Order,
+		Vendor:    dbp.Vendor,
+		Method:    dbp.Method,
+		Amount:    dbp.Amount,
+		Tip:       dbp.Tip,
+		PaidBy:    dbp.PaidBy,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+type dbTombstone struct {
+	ID        string `db:"id"`
+	Vendor    string `db:"vendor"`
+	Seq       uint64 `db:"seq"`
+	DeletedAt string `db:"deleted_at"`
+}
+
+func toTombstone(dbt dbTombstone) (orders.Tombstone, error) {
+	deletedAt, err := parseTime(dbt.DeletedAt)
+	if err != nil {
+		return orders.Tombstone{}, err
+	}
+	return orders.Tombstone{
+		ID:        dbt.ID,
+		Vendor:    dbt.Vendor,
+		Seq:       dbt.Seq,
+		DeletedAt: deletedAt,
+	}, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.ParseInLocation(timeLayout, s, time.UTC)
+}
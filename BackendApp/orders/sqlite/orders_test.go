@@ -0,0 +1,19 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/repotest"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/sqlite"
+)
+
+func TestOrderRepo(t *testing.T) {
+	db, err := sqlite.Connect(sqlite.Config{Path: filepath.Join(t.TempDir(), "o.db")})
+	if err != nil {
+		t.Fatalf("opening the database: %s", err)
+	}
+	defer db.Close()
+
+	repotest.Run(t, sqlite.NewOrderRepo(db))
+}
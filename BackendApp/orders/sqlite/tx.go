@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey keys the transaction a context runs in.
+type txKey struct{}
+
+// txn is a transaction of the repository, or a savepoint of the transaction
+// the context runs in. Committing a savepoint leaves what it wrote to the
+// outer transaction, rolling it back undoes only that.
+type txn struct {
+	*sqlx.Tx
+	nested bool
+	done   bool
+}
+
+// begin starts a transaction, or a savepoint when ctx runs in one. The
+// database has a single connection, writes and reads of a context running
+// in a transaction must go through it.
+func (repo orderRepo) begin(ctx context.Context) (*txn, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT nested`); err != nil {
+			return nil, err
+		}
+		return &txn{Tx: tx, nested: true}, nil
+	}
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+// querier returns the transaction ctx runs in, or the database when it runs
+// in none.
+func (repo orderRepo) querier(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return repo.db
+}
+
+func (t *txn) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.nested {
+		_, err := t.Exec(`RELEASE SAVEPOINT nested`)
+		return err
+	}
+	return t.Tx.Commit()
+}
+
+func (t *txn) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.nested {
+		// Rolling back keeps the savepoint, it is released after.
+		if _, err := t.Exec(`ROLLBACK TO SAVEPOINT nested`); err != nil {
+			return err
+		}
+		_, err := t.Exec(`RELEASE SAVEPOINT nested`)
+		return err
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func createPromotionEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createPromotionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreatePromotion(ctx, req.token, req.promo)
+		if err != nil {
+			return nil, err
+		}
+		return createPromotionRes{ID: id}, nil
+	}
+}
+
+func viewPromotionEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		promo, err := svc.ViewPromotion(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewPromotionRes{Promotion: promo}, nil
+	}
+}
+
+func listPromotionsEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listPromotionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := promotions.PageMetadata{
+			Offset:     req.offset,
+			Limit:      req.limit,
+			Vendor:     req.vendor,
+			Code:       req.code,
+			OnlyActive: req.onlyActive,
+		}
+		page, err := svc.ListPromotions(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := promotionsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Promotions: []viewPromotionRes{},
+		}
+		for _, promo := range page.Promotions {
+			res.Promotions = append(res.Promotions, viewPromotionRes{Promotion: promo})
+		}
+		return res, nil
+	}
+}
+
+func updatePromotionEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updatePromotionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdatePromotion(ctx, req.token, req.promo)
+		if err != nil {
+			return nil, err
+		}
+		return updatePromotionRes{ID: id}, nil
+	}
+}
+
+func removePromotionEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemovePromotion(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removePromotionRes{}, nil
+	}
+}
+
+func quoteEndpoint(svc promotions.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quoteReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		basket := promotions.Basket{
+			Vendor:   req.Vendor,
+			Customer: req.Customer,
+			Code:     req.Code,
+			Items:    req.Items,
+			Total:    req.Total,
+		}
+		if basket.Total == 0 {
+			basket.Total = orders.Order{Items: basket.Items}.ItemsTotal()
+		}
+		adjustments, err := svc.Quote(ctx, req.token, basket)
+		if err != nil {
+			return nil, err
+		}
+		order := orders.Order{Price: basket.Total, Adjustments: adjustments}
+		res := quoteRes{
+			Adjustments: []orders.Adjustment{},
+			Gross:       basket.Total,
+			Discount:    order.Discount(),
+			Net:         basket.Total - order.Discount(),
+		}
+		res.Adjustments = append(res.Adjustments, adjustments...)
+		return res, nil
+	}
+}
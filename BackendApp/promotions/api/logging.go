@@ -0,0 +1,159 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	"github.com/go-kit/log"
+)
+
+var _ promotions.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    promotions.Service
+}
+
+// LoggingMiddleware adds logging facilities to the promotions service.
+func LoggingMiddleware(svc promotions.Service, logger log.Logger) promotions.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreatePromotion(ctx context.Context, token string, promo promotions.Promotion) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_promotion",
+			"token", token,
+			"vendor", promo.Vendor,
+			"name", promo.Name,
+			"kind", promo.Kind,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreatePromotion(ctx, token, promo)
+}
+
+func (lm *loggingMiddleware) ViewPromotion(ctx context.Context, token, id string) (promo promotions.Promotion, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_promotion",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewPromotion(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListPromotions(ctx context.Context, token string, pm promotions.PageMetadata) (page promotions.PromotionsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_promotions",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListPromotions(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdatePromotion(ctx context.Context, token string, promo promotions.Promotion) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_promotion",
+			"token", token,
+			"id", promo.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdatePromotion(ctx, token, promo)
+}
+
+func (lm *loggingMiddleware) RemovePromotion(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_promotion",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemovePromotion(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Quote(ctx context.Context, token string, basket promotions.Basket) (adjustments []orders.Adjustment, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "quote",
+			"token", token,
+			"vendor", basket.Vendor,
+			"code", basket.Code,
+			"total", basket.Total,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Quote(ctx, token, basket)
+}
+
+func (lm *loggingMiddleware) Redeem(ctx context.Context, token string, basket promotions.Basket, adjustments []orders.Adjustment) (ref string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "redeem",
+			"token", token,
+			"ref", ref,
+			"vendor", basket.Vendor,
+			"code", basket.Code,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Redeem(ctx, token, basket, adjustments)
+}
+
+func (lm *loggingMiddleware) Assign(ctx context.Context, token, ref, orderID string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "assign",
+			"token", token,
+			"ref", ref,
+			"order", orderID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Assign(ctx, token, ref, orderID)
+}
+
+func (lm *loggingMiddleware) Release(ctx context.Context, token, ref string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "release",
+			"token", token,
+			"ref", ref,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Release(ctx, token, ref)
+}
@@ -0,0 +1,111 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ promotions.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     promotions.Service
+}
+
+// MetricsMiddleware instruments the promotions service by tracking request count
+// and latency.
+func MetricsMiddleware(svc promotions.Service, counter metrics.Counter, latency metrics.Histogram) promotions.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreatePromotion(ctx context.Context, token string, promo promotions.Promotion) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_promotion").Add(1)
+		ms.latency.With("method", "create_promotion").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreatePromotion(ctx, token, promo)
+}
+
+func (ms *metricsMiddleware) ViewPromotion(ctx context.Context, token, id string) (promotions.Promotion, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_promotion").Add(1)
+		ms.latency.With("method", "view_promotion").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewPromotion(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListPromotions(ctx context.Context, token string, pm promotions.PageMetadata) (promotions.PromotionsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_promotions").Add(1)
+		ms.latency.With("method", "list_promotions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListPromotions(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdatePromotion(ctx context.Context, token string, promo promotions.Promotion) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_promotion").Add(1)
+		ms.latency.With("method", "update_promotion").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdatePromotion(ctx, token, promo)
+}
+
+func (ms *metricsMiddleware) RemovePromotion(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_promotion").Add(1)
+		ms.latency.With("method", "remove_promotion").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemovePromotion(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Quote(ctx context.Context, token string, basket promotions.Basket) ([]orders.Adjustment, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "quote").Add(1)
+		ms.latency.With("method", "quote").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Quote(ctx, token, basket)
+}
+
+func (ms *metricsMiddleware) Redeem(ctx context.Context, token string, basket promotions.Basket, adjustments []orders.Adjustment) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "redeem").Add(1)
+		ms.latency.With("method", "redeem").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Redeem(ctx, token, basket, adjustments)
+}
+
+func (ms *metricsMiddleware) Assign(ctx context.Context, token, ref, orderID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign").Add(1)
+		ms.latency.With("method", "assign").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Assign(ctx, token, ref, orderID)
+}
+
+func (ms *metricsMiddleware) Release(ctx context.Context, token, ref string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "release").Add(1)
+		ms.latency.With("method", "release").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Release(ctx, token, ref)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+)
+
+const (
+	maxLimitSize = 100
+)
+
+type createPromotionReq struct {
+	promo promotions.Promotion
+	token string
+}
+
+func (req createPromotionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.promo.Validate()
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listPromotionsReq struct {
+	token      string
+	vendor     string
+	code       string
+	onlyActive bool
+	offset     uint64
+	limit      uint64
+}
+
+func (req listPromotionsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type updatePromotionReq struct {
+	token string
+	promo promotions.Promotion
+}
+
+func (req updatePromotionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.promo.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type quoteReq struct {
+	token    string
+	Vendor   string        `json:"vendor"`
+	Customer string        `json:"customer,omitempty"`
+	Code     string        `json:"code,omitempty"`
+	Items    []orders.Item `json:"items,omitempty"`
+	Total    uint64        `json:"total,omitempty"`
+}
+
+func (req quoteReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.Vendor == "" || (len(req.Items) == 0 && req.Total == 0) {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
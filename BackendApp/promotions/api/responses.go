@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createPromotionRes)(nil)
+	_ Response = (*viewPromotionRes)(nil)
+	_ Response = (*promotionsPageRes)(nil)
+	_ Response = (*updatePromotionRes)(nil)
+	_ Response = (*removePromotionRes)(nil)
+	_ Response = (*quoteRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type createPromotionRes struct {
+	ID string
+}
+
+func (res createPromotionRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createPromotionRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/promotions/%s", res.ID),
+	}
+}
+
+func (res createPromotionRes) Empty() bool {
+	return true
+}
+
+type viewPromotionRes struct {
+	promotions.Promotion
+}
+
+func (res viewPromotionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewPromotionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewPromotionRes) Empty() bool {
+	return false
+}
+
+type promotionsPageRes struct {
+	pageRes
+	Promotions []viewPromotionRes `json:"promotions"`
+}
+
+func (res promotionsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res promotionsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res promotionsPageRes) Empty() bool {
+	return false
+}
+
+type updatePromotionRes struct {
+	ID string
+}
+
+func (res updatePromotionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updatePromotionRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/promotions/%s", res.ID),
+	}
+}
+
+func (res updatePromotionRes) Empty() bool {
+	return true
+}
+
+type removePromotionRes struct{}
+
+func (res removePromotionRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removePromotionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removePromotionRes) Empty() bool {
+	return true
+}
+
+// quoteRes prices a basket the way it would be charged if ordered now.
+type quoteRes struct {
+	Adjustments []orders.Adjustment `json:"adjustments"`
+	Gross       uint64              `json:"gross"`
+	Discount    uint64              `json:"discount"`
+	Net         uint64              `json:"net"`
+}
+
+func (res quoteRes) Code() int {
+	return http.StatusOK
+}
+
+func (res quoteRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res quoteRes) Empty() bool {
+	return false
+}
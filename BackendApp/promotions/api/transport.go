@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	codeKey     = "code"
+	activeKey   = "active"
+)
+
+// MakePromotionsHandler returns a HTTP handler for promotions API endpoints.
+func MakePromotionsHandler(svc promotions.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/promotions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_promotion")(createPromotionEndpoint(svc)),
+		decodeCreatePromotion,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/promotions/quote").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint quote_promotions")(quoteEndpoint(svc)),
+		decodeQuote,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/promotions/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_promotion")(viewPromotionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/promotions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_promotions")(listPromotionsEndpoint(svc)),
+		decodeListPromotions,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/promotions/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_promotion")(updatePromotionEndpoint(svc)),
+		decodeUpdatePromotion,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/promotions/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_promotion")(removePromotionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCreatePromotion(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var promo promotions.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createPromotionReq{
+		promo: promo,
+		token: decodeToken(r),
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeListPromotions(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listPromotionsReq{
+		token:  decodeToken(r),
+		limit:  maxLimitSize,
+		vendor: r.URL.Query().Get(vendorKey),
+		code:   r.URL.Query().Get(codeKey),
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(activeKey) {
+		req.onlyActive, err = strconv.ParseBool(r.URL.Query().Get(activeKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+// decodeUpdatePromotion reads the full promotion, updates replace every rule.
+func decodeUpdatePromotion(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var promo promotions.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	promo.ID = mux.Vars(r)["id"]
+	req := updatePromotionReq{
+		token: decodeToken(r),
+		promo: promo,
+	}
+	return req, nil
+}
+
+func decodeQuote(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := quoteReq{
+		token: decodeToken(r),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, promotions.ErrInvalidCode):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
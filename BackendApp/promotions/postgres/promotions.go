@@ -0,0 +1,327 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	"github.com/jmoiron/sqlx"
+)
+
+const promotionColumns = `id, vendor, name, code, kind, percent, amount, buy, get, items, min_spend, days, window_from, window_to,
+	starts_at, ends_at, max_uses, max_uses_per_customer, stackable, active, metadata, created_at, updated_at`
+
+var _ promotions.Repository = (*promotionsRepo)(nil)
+
+type promotionsRepo struct {
+	db *sqlx.DB
+}
+
+// NewPromotionsRepo instantiates a PostgreSQL
+// implementation of promotions repository.
+func NewPromotionsRepo(db *sqlx.DB) promotions.Repository {
+	return &promotionsRepo{
+		db: db,
+	}
+}
+
+func (repo promotionsRepo) Save(ctx context.Context, promo promotions.Promotion) (string, error) {
+	q := `INSERT INTO promotions (` + promotionColumns + `)
+		  VALUES (:id, :vendor, :name, :code, :kind, :percent, :amount, :buy, :get, :items, :min_spend, :days, :window_from, :window_to,
+		  :starts_at, :ends_at, :max_uses, :max_uses_per_customer, :stackable, :active, :metadata, :created_at, :updated_at) RETURNING id`
+
+	dbp, err := toDBPromotion(promo)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbp)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo promotionsRepo) RetrieveByID(ctx context.Context, id string) (promotions.Promotion, error) {
+	q := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo promotionsRepo) RetrieveByCode(ctx context.Context, vendor, code string) (promotions.Promotion, error) {
+	q := `SELECT ` + promotionColumns + ` FROM promotions WHERE vendor = $1 AND code = $2`
+
+	return repo.retrieve(ctx, q, vendor, code)
+}
+
+func (repo promotionsRepo) retrieve(ctx context.Context, q string, args ...interface{}) (promotions.Promotion, error) {
+	dbp := dbPromotion{}
+	if err := repo.db.QueryRowxContext(ctx, q, args...).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return promotions.Promotion{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return promotions.Promotion{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toPromotion(dbp)
+}
+
+func (repo promotionsRepo) RetrieveAutomatic(ctx context.Context, vendor string) ([]promotions.Promotion, error) {
+	q := `SELECT ` + promotionColumns + ` FROM promotions WHERE vendor = :vendor AND code = '' AND active = TRUE ORDER BY created_at`
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, map[string]interface{}{"vendor": vendor})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	return scan(rows)
+}
+
+func (repo promotionsRepo) RetrieveAll(ctx context.Context, pm promotions.PageMetadata) (promotions.PromotionsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"code":   pm.Code,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Code != "" {
+		query = append(query, "code = :code")
+	}
+	if pm.OnlyActive {
+		query = append(query, "active = TRUE")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+promotionColumns+` FROM promotions %s ORDER BY created_at LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return promotions.PromotionsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	promos, err := scan(rows)
+	if err != nil {
+		return promotions.PromotionsPage{}, err
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM promotions %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return promotions.PromotionsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := promotions.PromotionsPage{
+		Promotions: promos,
+		PageMetadata: promotions.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo promotionsRepo) Update(ctx context.Context, promo promotions.Promotion) (string, error) {
+	q := `UPDATE promotions SET name = :name, code = :code, kind = :kind, percent = :percent, amount = :amount, buy = :buy,
+		  get = :get, items = :items, min_spend = :min_spend, days = :days, window_from = :window_from, window_to = :window_to,
+		  starts_at = :starts_at, ends_at = :ends_at, max_uses = :max_uses, max_uses_per_customer = :max_uses_per_customer,
+		  stackable = :stackable, active = :active, metadata = :metadata, updated_at = :updated_at WHERE id = :id RETURNING id`
+
+	dbp, err := toDBPromotion(promo)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbp)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo promotionsRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM promotions WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbPromotion{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func scan(rows *sqlx.Rows) ([]promotions.Promotion, error) {
+	var promos []promotions.Promotion
+	for rows.Next() {
+		dbp := dbPromotion{}
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		promo, err := toPromotion(dbp)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, promo)
+	}
+	return promos, nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbPromotion struct {
+	ID                 string       `db:"id"`
+	Vendor             string       `db:"vendor"`
+	Name               string       `db:"name"`
+	Code               string       `db:"code"`
+	Kind               string       `db:"kind"`
+	Percent            uint64       `db:"percent"`
+	Amount             uint64       `db:"amount"`
+	Buy                uint64       `db:"buy"`
+	Get                uint64       `db:"get"`
+	Items              []byte       `db:"items"`
+	MinSpend           uint64       `db:"min_spend"`
+	Days               []byte       `db:"days"`
+	WindowFrom         string       `db:"window_from"`
+	WindowTo           string       `db:"window_to"`
+	StartsAt           sql.NullTime `db:"starts_at"`
+	EndsAt             sql.NullTime `db:"ends_at"`
+	MaxUses            uint64       `db:"max_uses"`
+	MaxUsesPerCustomer uint64       `db:"max_uses_per_customer"`
+	Stackable          bool         `db:"stackable"`
+	Active             bool         `db:"active"`
+	Metadata           []byte       `db:"metadata"`
+	CreatedAt          time.Time    `db:"created_at"`
+	UpdatedAt          time.Time    `db:"updated_at"`
+}
+
+func toDBPromotion(promo promotions.Promotion) (dbPromotion, error) {
+	data := []byte("{}")
+	if len(promo.Metadata) > 0 {
+		b, err := json.Marshal(promo.Metadata)
+		if err != nil {
+			return dbPromotion{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		data = b
+	}
+	items, err := marshalList(promo.Items)
+	if err != nil {
+		return dbPromotion{}, err
+	}
+	days, err := marshalList(promo.Days)
+	if err != nil {
+		return dbPromotion{}, err
+	}
+	return dbPromotion{
+		ID:                 promo.ID,
+		Vendor:             promo.Vendor,
+		Name:               promo.Name,
+		Code:               promo.Code,
+		Kind:               string(promo.Kind),
+		Percent:            promo.Percent,
+		Amount:             promo.Amount,
+		Buy:                promo.Buy,
+		Get:                promo.Get,
+		Items:              items,
+		MinSpend:           promo.MinSpend,
+		Days:               days,
+		WindowFrom:         promo.From,
+		WindowTo:           promo.To,
+		StartsAt:           sql.NullTime{Time: promo.StartsAt, Valid: !promo.StartsAt.IsZero()},
+		EndsAt:             sql.NullTime{Time: promo.EndsAt, Valid: !promo.EndsAt.IsZero()},
+		MaxUses:            promo.MaxUses,
+		MaxUsesPerCustomer: promo.MaxUsesPerCustomer,
+		Stackable:          promo.Stackable,
+		Active:             promo.Active,
+		Metadata:           data,
+		CreatedAt:          promo.CreatedAt,
+		UpdatedAt:          promo.UpdatedAt,
+	}, nil
+}
+
+func toPromotion(promo dbPromotion) (promotions.Promotion, error) {
+	var metadata map[string]interface{}
+	if promo.Metadata != nil {
+		if err := json.Unmarshal(promo.Metadata, &metadata); err != nil {
+			return promotions.Promotion{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	var items, days []string
+	if promo.Items != nil {
+		if err := json.Unmarshal(promo.Items, &items); err != nil {
+			return promotions.Promotion{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	if promo.Days != nil {
+		if err := json.Unmarshal(promo.Days, &days); err != nil {
+			return promotions.Promotion{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return promotions.Promotion{
+		ID:                 promo.ID,
+		Vendor:             promo.Vendor,
+		Name:               promo.Name,
+		Code:               promo.Code,
+		Kind:               promotions.Kind(promo.Kind),
+		Percent:            promo.Percent,
+		Amount:             promo.Amount,
+		Buy:                promo.Buy,
+		Get:                promo.Get,
+		Items:              items,
+		MinSpend:           promo.MinSpend,
+		Days:               days,
+		From:               promo.WindowFrom,
+		To:                 promo.WindowTo,
+		StartsAt:           promo.StartsAt.Time,
+		EndsAt:             promo.EndsAt.Time,
+		MaxUses:            promo.MaxUses,
+		MaxUsesPerCustomer: promo.MaxUsesPerCustomer,
+		Stackable:          promo.Stackable,
+		Active:             promo.Active,
+		Metadata:           metadata,
+		CreatedAt:          promo.CreatedAt,
+		UpdatedAt:          promo.UpdatedAt,
+	}, nil
+}
+
+func marshalList(list []string) ([]byte, error) {
+	if list == nil {
+		list = []string{}
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ promotions.RedemptionRepository = (*redemptionsRepo)(nil)
+
+type redemptionsRepo struct {
+	db *sqlx.DB
+}
+
+// NewRedemptionsRepo instantiates a PostgreSQL
+// implementation of promotion redemptions repository.
+func NewRedemptionsRepo(db *sqlx.DB) promotions.RedemptionRepository {
+	return &redemptionsRepo{
+		db: db,
+	}
+}
+
+func (repo redemptionsRepo) Reserve(ctx context.Context, redemptions ...promotions.Redemption) error {
+	lq := `SELECT max_uses, max_uses_per_customer FROM promotions WHERE id = $1 FOR UPDATE`
+	q := `INSERT INTO promotion_redemptions (promotion_id, order_id, customer, amount, redeemed_at)
+		  VALUES (:promotion_id, :order_id, :customer, :amount, :redeemed_at)`
+
+	// The promotions are locked in the order of their IDs, so reservations
+	// of the same promotions can not wait on each other.
+	sorted := append([]promotions.Redemption(nil), redemptions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PromotionID < sorted[j].PromotionID })
+
+	return pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		for _, r := range sorted {
+			// The lock on the promotion is held until the redemption is
+			// committed, so the uses counted can not change in between.
+			var maxUses, maxUsesPerCustomer uint64
+			switch err := tx.QueryRowxContext(ctx, lq, r.PromotionID).Scan(&maxUses, &maxUsesPerCustomer); {
+			case err == sql.ErrNoRows:
+				return errors.ErrNotFound
+			case err != nil:
+				return errors.Wrap(errors.ErrCreateEntity, err)
+			}
+			if maxUses > 0 || maxUsesPerCustomer > 0 {
+				uses, customerUses, err := count(ctx, tx, r.PromotionID, r.Customer)
+				if err != nil {
+					return err
+				}
+				if maxUses > 0 && uses >= maxUses {
+					return promotions.ErrUsedUp
+				}
+				if maxUsesPerCustomer > 0 && (r.Customer == "" || customerUses >= maxUsesPerCustomer) {
+					return promotions.ErrUsedUp
+				}
+			}
+			dbr := dbRedemption{
+				PromotionID: r.PromotionID,
+				OrderID:     r.OrderID,
+				Customer:    r.Customer,
+				Amount:      r.Amount,
+				RedeemedAt:  r.RedeemedAt,
+			}
+			if _, err := tx.NamedExecContext(ctx, q, dbr); err != nil {
+				return handleError(err, errors.ErrCreateEntity)
+			}
+		}
+		return nil
+	})
+}
+
+func (repo redemptionsRepo) Assign(ctx context.Context, ref, orderID string) error {
+	q := `UPDATE promotion_redemptions SET order_id = $2 WHERE order_id = $1`
+
+	if _, err := pgutil.DB(ctx, repo.db).ExecContext(ctx, q, ref, orderID); err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	return nil
+}
+
+func (repo redemptionsRepo) Release(ctx context.Context, ref string) error {
+	q := `DELETE FROM promotion_redemptions WHERE order_id = $1`
+
+	if _, err := pgutil.DB(ctx, repo.db).ExecContext(ctx, q, ref); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func (repo redemptionsRepo) Count(ctx context.Context, promotionID, customer string) (uint64, uint64, error) {
+	return count(ctx, pgutil.DB(ctx, repo.db), promotionID, customer)
+}
+
+func count(ctx context.Context, db sqlx.QueryerContext, promotionID, customer string) (uint64, uint64, error) {
+	q := `SELECT COUNT(*), COUNT(*) FILTER (WHERE customer = $2 AND customer <> '') FROM promotion_redemptions WHERE promotion_id = $1`
+
+	var uses, customerUses uint64
+	if err := db.QueryRowxContext(ctx, q, promotionID, customer).Scan(&uses, &customerUses); err != nil {
+		return 0, 0, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return uses, customerUses, nil
+}
+
+type dbRedemption struct {
+	PromotionID string    `db:"promotion_id"`
+	OrderID     string    `db:"order_id"`
+	Customer    string    `db:"customer"`
+	Amount      uint64    `db:"amount"`
+	RedeemedAt  time.Time `db:"redeemed_at"`
+}
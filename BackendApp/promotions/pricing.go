@@ -0,0 +1,110 @@
+package promotions
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// quoteAttempts is how many times an order is quoted at most when its
+// promotions get used up before they are redeemed.
+const quoteAttempts = 3
+
+var _ orders.OrderService = (*pricingMiddleware)(nil)
+
+type pricingMiddleware struct {
+	svc    orders.OrderService
+	promos Service
+}
+
+// PricingMiddleware applies promotions to orders as they are created. The
+// promo code is read from the orders.PromoCodeKey metadata and the customer
+// from orders.CustomerKey. Every channel that creates orders through the
+// returned service gets the same discounts. The uses of the promotions are
+// counted before the order is placed and given back if it is not.
+func PricingMiddleware(svc orders.OrderService, promos Service) orders.OrderService {
+	return &pricingMiddleware{
+		svc:    svc,
+		promos: promos,
+	}
+}
+
+func (pm *pricingMiddleware) CreateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	basket := Basket{
+		Vendor:   order.Vendor,
+		Customer: metadata(order.Metadata, orders.CustomerKey),
+		Code:     metadata(order.Metadata, orders.PromoCodeKey),
+		Items:    order.Items,
+		Total:    order.Price,
+		At:       time.Now(),
+	}
+	if basket.Total == 0 {
+		basket.Total = order.ItemsTotal()
+	}
+	adjustments, ref, err := pm.redeem(ctx, token, basket)
+	if err != nil {
+		return "", err
+	}
+	order.Adjustments = adjustments
+	if order.Price == 0 {
+		order.Price = basket.Total
+	}
+
+	id, err := pm.svc.CreateOrder(ctx, token, order)
+	if err != nil && id == "" {
+		if rerr := pm.promos.Release(ctx, token, ref); rerr != nil {
+			return "", errors.Wrap(err, rerr)
+		}
+		return "", err
+	}
+	if aerr := pm.promos.Assign(ctx, token, ref, id); aerr != nil && err == nil {
+		return id, aerr
+	}
+	return id, err
+}
+
+// redeem quotes the basket and counts the discounts against the usage
+// limits of their promotions. A promotion used up by an order placed in the
+// meantime is left out of a new quote.
+func (pm *pricingMiddleware) redeem(ctx context.Context, token string, basket Basket) ([]orders.Adjustment, string, error) {
+	for attempt := 1; ; attempt++ {
+		adjustments, err := pm.promos.Quote(ctx, token, basket)
+		if err != nil {
+			return nil, "", err
+		}
+		ref, err := pm.promos.Redeem(ctx, token, basket, adjustments)
+		switch {
+		case err == nil:
+			return adjustments, ref, nil
+		case !errors.Contains(err, ErrUsedUp) || attempt == quoteAttempts:
+			return nil, "", err
+		}
+	}
+}
+
+func (pm *pricingMiddleware) ViewOrder(ctx context.Context, token, id string) (orders.Order, error) {
+	return pm.svc.ViewOrder(ctx, token, id)
+}
+
+func (pm *pricingMiddleware) ListOrders(ctx context.Context, token string, page orders.PageMetadata) (orders.OrdersPage, error) {
+	return pm.svc.ListOrders(ctx, token, page)
+}
+
+func (pm *pricingMiddleware) UpdateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	return pm.svc.UpdateOrder(ctx, token, order)
+}
+
+func (pm *pricingMiddleware) DeleteOrder(ctx context.Context, token, id string) error {
+	return pm.svc.DeleteOrder(ctx, token, id)
+}
+
+func (pm *pricingMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	return pm.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
+
+func metadata(md orders.Metadata, key string) string {
+	value, _ := md[key].(string)
+	return value
+}
@@ -0,0 +1,153 @@
+package promotions_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/promotions"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// promoRepo serves a single automatic promotion.
+type promoRepo struct {
+	promotions.Repository
+	promo promotions.Promotion
+}
+
+func (repo promoRepo) RetrieveAutomatic(ctx context.Context, vendor string) ([]promotions.Promotion, error) {
+	return []promotions.Promotion{repo.promo}, nil
+}
+
+// redemptionRepo keeps redemptions in memory, checking the limits of the
+// promotion as they are reserved.
+type redemptionRepo struct {
+	mu          sync.Mutex
+	promo       promotions.Promotion
+	redemptions []promotions.Redemption
+}
+
+func (repo *redemptionRepo) Reserve(ctx context.Context, redemptions ...promotions.Redemption) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if uint64(len(repo.redemptions)+len(redemptions)) > repo.promo.MaxUses {
+		return promotions.ErrUsedUp
+	}
+	repo.redemptions = append(repo.redemptions, redemptions...)
+	return nil
+}
+
+func (repo *redemptionRepo) Assign(ctx context.Context, ref, orderID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.redemptions {
+		if repo.redemptions[i].OrderID == ref {
+			repo.redemptions[i].OrderID = orderID
+		}
+	}
+	return nil
+}
+
+func (repo *redemptionRepo) Release(ctx context.Context, ref string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var kept []promotions.Redemption
+	for _, r := range repo.redemptions {
+		if r.OrderID != ref {
+			kept = append(kept, r)
+		}
+	}
+	repo.redemptions = kept
+	return nil
+}
+
+func (repo *redemptionRepo) Count(ctx context.Context, promotionID, customer string) (uint64, uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return uint64(len(repo.redemptions)), 0, nil
+}
+
+// failingOrders fails every order it is asked to create.
+type failingOrders struct {
+	orders.OrderService
+}
+
+func (svc failingOrders) CreateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	return "", errors.ErrCreateEntity
+}
+
+func newPricing(maxUses uint64, ordersSvc orders.OrderService) (orders.OrderService, *redemptionRepo) {
+	promo := promotions.Promotion{ID: "promo", Vendor: vendor, Name: "Opening week", Kind: promotions.Fixed, Amount: 20, MaxUses: maxUses, Active: true}
+	redemptions := &redemptionRepo{promo: promo}
+	svc := promotions.NewService(promotions.Config{}, promoRepo{promo: promo}, redemptions)
+	return promotions.PricingMiddleware(ordersSvc, svc), redemptions
+}
+
+func newOrder() orders.Order {
+	return orders.Order{Vendor: vendor, Name: "Chapati", Price: 100, Place: "inhouse", Status: orders.StatusOrdered}
+}
+
+func TestPricingUsageLimit(t *testing.T) {
+	ctx := context.Background()
+	ordersSvc := orders.NewOrderService(memory.NewOrderRepo())
+	svc, redemptions := newPricing(3, ordersSvc)
+
+	// Every checkout is quoted before any is redeemed, only as many as the
+	// promotion allows get the discount.
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	errs := make([]error, len(ids))
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i], errs[i] = svc.CreateOrder(ctx, token, newOrder())
+		}(i)
+	}
+	wg.Wait()
+
+	var discounted int
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Fatalf("creating order: %s", errs[i])
+		}
+		order, err := ordersSvc.ViewOrder(ctx, token, id)
+		if err != nil {
+			t.Fatalf("viewing order: %s", err)
+		}
+		if order.Discount() > 0 {
+			discounted++
+		}
+	}
+	if discounted != 3 {
+		t.Errorf("%d orders got the discount, want %d", discounted, 3)
+	}
+	placed := map[string]bool{}
+	for _, id := range ids {
+		placed[id] = true
+	}
+	for _, r := range redemptions.redemptions {
+		if !placed[r.OrderID] {
+			t.Errorf("redemption kept with %s, want the order it was for", r.OrderID)
+		}
+	}
+}
+
+func TestPricingFailedOrder(t *testing.T) {
+	ctx := context.Background()
+	svc, redemptions := newPricing(1, failingOrders{})
+
+	if _, err := svc.CreateOrder(ctx, token, newOrder()); !errors.Contains(err, errors.ErrCreateEntity) {
+		t.Fatalf("creating order returned %v, want %s", err, errors.ErrCreateEntity)
+	}
+	if n := len(redemptions.redemptions); n != 0 {
+		t.Errorf("%d redemptions kept for an order that was not placed, want none", n)
+	}
+}
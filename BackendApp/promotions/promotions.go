@@ -0,0 +1,241 @@
+// Package promotions prices orders with the discounts a vendor runs: percentage
+// and fixed discounts, buy-N-get-M offers, happy hours and promo codes.
+package promotions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// ErrInvalidCode indicates a promo code that does not exist or cannot be
+// redeemed on the order.
+var ErrInvalidCode = errors.New("promo code is not valid for this order")
+
+// ErrUsedUp indicates a promotion that reached its usage limits since the
+// order was quoted.
+var ErrUsedUp = errors.New("promotion has reached its usage limit")
+
+// Kind describes how a promotion discounts an order.
+type Kind string
+
+// Kinds of promotions.
+const (
+	Percentage Kind = "percentage" // A percentage off the eligible items.
+	Fixed      Kind = "fixed"      // A fixed amount off the eligible items.
+	BOGO       Kind = "bogo"       // Buy a number of units, get more free.
+)
+
+const clockLayout = "15:04"
+
+// Days are the names days of the week are written as, starting on Sunday.
+var Days = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Metadata to be used for customized
+// describing of particular Promotion.
+type Metadata map[string]interface{}
+
+// Promotion is a pricing rule. Promotions with a code only apply when the
+// customer enters it, the rest apply automatically whenever their
+// conditions are met.
+type Promotion struct {
+	ID                 string    `json:"id,omitempty"`
+	Vendor             string    `json:"vendor,omitempty"`                // The vendor running the promotion.
+	Name               string    `json:"name,omitempty"`                  // The name shown on the order i.e. "Happy hour".
+	Code               string    `json:"code,omitempty"`                  // The code customers enter i.e. WELCOME100.
+	Kind               Kind      `json:"kind,omitempty"`                  // How the promotion discounts the order.
+	Percent            uint64    `json:"percent,omitempty"`               // The percentage off for percentage promotions.
+	Amount             uint64    `json:"amount,omitempty"`                // The amount off for fixed promotions.
+	Buy                uint64    `json:"buy,omitempty"`                   // The units to buy for BOGO promotions.
+	Get                uint64    `json:"get,omitempty"`                   // The units given free for every units bought.
+	Items              []string  `json:"items,omitempty"`                 // The menu items the promotion is limited to, all items if empty.
+	MinSpend           uint64    `json:"min_spend,omitempty"`             // The gross order total required.
+	Days               []string  `json:"days,omitempty"`                  // The days of the week it runs i.e. "mon", every day if empty.
+	From               string    `json:"from,omitempty"`                  // When it starts every day i.e. "07:00".
+	To                 string    `json:"to,omitempty"`                    // When it ends every day i.e. "12:00".
+	StartsAt           time.Time `json:"starts_at,omitempty"`             // When the promotion starts.
+	EndsAt             time.Time `json:"ends_at,omitempty"`               // When the promotion ends.
+	MaxUses            uint64    `json:"max_uses,omitempty"`              // How many orders can use it in total.
+	MaxUsesPerCustomer uint64    `json:"max_uses_per_customer,omitempty"` // How many orders each customer can use it on.
+	Stackable          bool      `json:"stackable"`                       // Whether it combines with other promotions.
+	Active             bool      `json:"active"`                          // Whether it is currently offered.
+	Metadata           Metadata  `json:"metadata,omitempty"`              // Metadata contains extra information about the promotion.
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`            // When the promotion was updated.
+	CreatedAt          time.Time `json:"created_at,omitempty"`            // When the promotion was created in the system.
+}
+
+// Validate returns an error if the promotion representation is invalid.
+func (promo Promotion) Validate() error {
+	if promo.Vendor == "" || promo.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	switch promo.Kind {
+	case Percentage:
+		if promo.Percent == 0 || promo.Percent > 100 {
+			return errors.ErrMalformedEntity
+		}
+	case Fixed:
+		if promo.Amount == 0 {
+			return errors.ErrMalformedEntity
+		}
+	case BOGO:
+		if promo.Buy == 0 || promo.Get == 0 {
+			return errors.ErrMalformedEntity
+		}
+	default:
+		return errors.ErrMalformedEntity
+	}
+	for _, day := range promo.Days {
+		if weekday(day) < 0 {
+			return errors.ErrMalformedEntity
+		}
+	}
+	if (promo.From == "") != (promo.To == "") {
+		return errors.ErrMalformedEntity
+	}
+	if promo.From != "" {
+		if _, err := time.Parse(clockLayout, promo.From); err != nil {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		if _, err := time.Parse(clockLayout, promo.To); err != nil {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	if !promo.StartsAt.IsZero() && !promo.EndsAt.IsZero() && !promo.StartsAt.Before(promo.EndsAt) {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// NormalizeCode returns the form promo codes are stored and compared in.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Vendor     string
+	Code       string
+	OnlyActive bool
+}
+
+// PromotionsPage contains a page of promotions.
+type PromotionsPage struct {
+	PageMetadata
+	Promotions []Promotion
+}
+
+// Basket is what a customer is about to order, used to work out which
+// promotions apply.
+type Basket struct {
+	Vendor   string
+	Customer string        // Who is ordering, required by promotions with a per customer limit.
+	Code     string        // The promo code entered, if any.
+	Items    []orders.Item // The item lines of the order.
+	Total    uint64        // The gross total, the items total if zero.
+	At       time.Time     // When the order is placed, now if zero.
+}
+
+// Redemption records a promotion used on an order.
+type Redemption struct {
+	PromotionID string
+	OrderID     string // The order, or the reference of the redemption until the order is placed.
+	Customer    string
+	Amount      uint64
+	RedeemedAt  time.Time
+}
+
+// Service specifies the promotions API.
+type Service interface {
+	// CreatePromotion adds a promotion.
+	CreatePromotion(ctx context.Context, token string, promo Promotion) (string, error)
+
+	// ViewPromotion retrieves a promotion by its unique identifier ID.
+	ViewPromotion(ctx context.Context, token, id string) (Promotion, error)
+
+	// ListPromotions retrieves all promotions for a given pageMetadata.
+	ListPromotions(ctx context.Context, token string, pm PageMetadata) (PromotionsPage, error)
+
+	// UpdatePromotion updates the rules of a promotion.
+	UpdatePromotion(ctx context.Context, token string, promo Promotion) (string, error)
+
+	// RemovePromotion removes a promotion.
+	RemovePromotion(ctx context.Context, token, id string) error
+
+	// Quote returns the discounts the basket gets. If the basket carries a
+	// code that cannot be redeemed ErrInvalidCode is returned.
+	Quote(ctx context.Context, token string, basket Basket) ([]orders.Adjustment, error)
+
+	// Redeem counts the discounts quoted for a basket against the usage
+	// limits of their promotions before the order is placed, and returns the
+	// reference of the redemption. If a limit was reached since the quote
+	// ErrUsedUp is returned and nothing is counted.
+	Redeem(ctx context.Context, token string, basket Basket, adjustments []orders.Adjustment) (string, error)
+
+	// Assign records the order placed with the redemption.
+	Assign(ctx context.Context, token, ref, orderID string) error
+
+	// Release gives back the uses of a redemption whose order was not
+	// placed.
+	Release(ctx context.Context, token, ref string) error
+}
+
+// Repository specifies a promotion persistence API.
+type Repository interface {
+	// Save persists the promotion. If the vendor already has a promotion
+	// with the same code errors.ErrConflict is returned.
+	Save(ctx context.Context, promo Promotion) (string, error)
+
+	// RetrieveByID retrieves a promotion by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Promotion, error)
+
+	// RetrieveByCode retrieves the promotion of a vendor with the code.
+	RetrieveByCode(ctx context.Context, vendor, code string) (Promotion, error)
+
+	// RetrieveAutomatic retrieves the active promotions of a vendor that
+	// apply without a code.
+	RetrieveAutomatic(ctx context.Context, vendor string) ([]Promotion, error)
+
+	// RetrieveAll retrieves all promotions for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (PromotionsPage, error)
+
+	// Update updates the promotion.
+	Update(ctx context.Context, promo Promotion) (string, error)
+
+	// Remove removes the promotion.
+	Remove(ctx context.Context, id string) error
+}
+
+// RedemptionRepository specifies a redemption persistence API.
+type RedemptionRepository interface {
+	// Reserve persists the redemptions unless one would take its promotion
+	// past its usage limits, in which case ErrUsedUp is returned and none is
+	// persisted. Redemptions of the same promotion are reserved one at a
+	// time.
+	Reserve(ctx context.Context, redemptions ...Redemption) error
+
+	// Assign moves the redemptions saved with the reference to the order.
+	Assign(ctx context.Context, ref, orderID string) error
+
+	// Release removes the redemptions saved with the reference.
+	Release(ctx context.Context, ref string) error
+
+	// Count returns how many times the promotion was redeemed in total and
+	// by the customer.
+	Count(ctx context.Context, promotionID, customer string) (uint64, uint64, error)
+}
+
+func weekday(day string) int {
+	for i, d := range Days {
+		if strings.EqualFold(d, day) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,185 @@
+package promotions
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// runs reports whether the promotion is on at the time, in the vendor's
+// time zone.
+func (promo Promotion) runs(at time.Time) bool {
+	if !promo.Active {
+		return false
+	}
+	if !promo.StartsAt.IsZero() && at.Before(promo.StartsAt) {
+		return false
+	}
+	if !promo.EndsAt.IsZero() && !at.Before(promo.EndsAt) {
+		return false
+	}
+	if len(promo.Days) > 0 {
+		today := false
+		for _, day := range promo.Days {
+			if weekday(day) == int(at.Weekday()) {
+				today = true
+			}
+		}
+		if !today {
+			return false
+		}
+	}
+	if promo.From != "" {
+		from, _ := time.Parse(clockLayout, promo.From)
+		to, _ := time.Parse(clockLayout, promo.To)
+		now := at.Hour()*60 + at.Minute()
+		start := from.Hour()*60 + from.Minute()
+		end := to.Hour()*60 + to.Minute()
+		// Windows that end before they start run past midnight.
+		if start <= end && (now < start || now >= end) {
+			return false
+		}
+		if start > end && now < start && now >= end {
+			return false
+		}
+	}
+	return true
+}
+
+// eligible returns the item lines the promotion applies to.
+func (promo Promotion) eligible(items []orders.Item) []orders.Item {
+	if len(promo.Items) == 0 {
+		return items
+	}
+	var lines []orders.Item
+	for _, item := range items {
+		for _, id := range promo.Items {
+			if item.ID == id {
+				lines = append(lines, item)
+				break
+			}
+		}
+	}
+	return lines
+}
+
+// discount returns how much the promotion takes off the basket.
+func (promo Promotion) discount(basket Basket) uint64 {
+	lines := promo.eligible(basket.Items)
+	base := orders.Order{Items: lines}.ItemsTotal()
+	// Orders priced without item lines are discounted as a whole.
+	if len(basket.Items) == 0 && len(promo.Items) == 0 {
+		base = basket.Total
+	}
+
+	switch promo.Kind {
+	case Percentage:
+		return base * promo.Percent / 100
+	case Fixed:
+		if promo.Amount > base {
+			return base
+		}
+		return promo.Amount
+	case BOGO:
+		// Every Buy+Get units of the same item, the cheapest Get are free.
+		units := map[string][]uint64{}
+		var keys []string
+		for _, line := range lines {
+			key := line.ID
+			if key == "" {
+				key = line.Name
+			}
+			if _, ok := units[key]; !ok {
+				keys = append(keys, key)
+			}
+			for i := uint64(0); i < line.Quantity; i++ {
+				units[key] = append(units[key], line.Price)
+			}
+		}
+		var off uint64
+		for _, key := range keys {
+			prices := units[key]
+			free := uint64(len(prices)) / (promo.Buy + promo.Get) * promo.Get
+			for _, price := range cheapest(prices, free) {
+				off += price
+			}
+		}
+		return off
+	}
+	return 0
+}
+
+// combine picks the promotions a basket gets out of those that apply.
+// Stackable promotions add up, one that does not stack is used on its own,
+// whichever saves the customer more. A promotion the customer entered the
+// code of is always used.
+func combine(entered *Promotion, automatic []Promotion, basket Basket) []orders.Adjustment {
+	if entered != nil {
+		chosen := []Promotion{*entered}
+		if entered.Stackable {
+			for _, promo := range automatic {
+				if promo.Stackable {
+					chosen = append(chosen, promo)
+				}
+			}
+		}
+		return adjustments(chosen, basket)
+	}
+
+	var stacked []Promotion
+	for _, promo := range automatic {
+		if promo.Stackable {
+			stacked = append(stacked, promo)
+		}
+	}
+	best := adjustments(stacked, basket)
+	for _, promo := range automatic {
+		if promo.Stackable {
+			continue
+		}
+		alone := adjustments([]Promotion{promo}, basket)
+		if total(alone) > total(best) {
+			best = alone
+		}
+	}
+	return best
+}
+
+// adjustments applies the promotions in order, none of them can take the
+// basket below zero.
+func adjustments(promos []Promotion, basket Basket) []orders.Adjustment {
+	left := basket.Total
+	var adjs []orders.Adjustment
+	for _, promo := range promos {
+		off := promo.discount(basket)
+		if off > left {
+			off = left
+		}
+		if off == 0 {
+			continue
+		}
+		left -= off
+		adjs = append(adjs, orders.Adjustment{
+			Promotion: promo.ID,
+			Code:      promo.Code,
+			Name:      promo.Name,
+			Amount:    off,
+		})
+	}
+	return adjs
+}
+
+func total(adjs []orders.Adjustment) uint64 {
+	return orders.Order{Adjustments: adjs}.Discount()
+}
+
+// cheapest returns the n lowest prices.
+func cheapest(prices []uint64, n uint64) []uint64 {
+	sorted := append([]uint64(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if n > uint64(len(sorted)) {
+		n = uint64(len(sorted))
+	}
+	return sorted[:n]
+}
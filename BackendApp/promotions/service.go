@@ -0,0 +1,190 @@
+package promotions
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+// Config defines the options the promotions service uses.
+type Config struct {
+	Location *time.Location // The time zone daily windows and days of the week are read in. Defaults to UTC.
+}
+
+var _ Service = (*promotionsService)(nil)
+
+type promotionsService struct {
+	config      Config
+	promos      Repository
+	redemptions RedemptionRepository
+}
+
+// NewService instantiates the promotions service implementation.
+func NewService(config Config, promos Repository, redemptions RedemptionRepository) Service {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	return &promotionsService{
+		config:      config,
+		promos:      promos,
+		redemptions: redemptions,
+	}
+}
+
+func (svc promotionsService) CreatePromotion(ctx context.Context, token string, promo Promotion) (string, error) {
+	if err := promo.Validate(); err != nil {
+		return "", err
+	}
+	promo.Code = NormalizeCode(promo.Code)
+	promo.ID = ulid.Make().String()
+	promo.CreatedAt = time.Now()
+	promo.UpdatedAt = time.Now()
+	return svc.promos.Save(ctx, promo)
+}
+
+func (svc promotionsService) ViewPromotion(ctx context.Context, token, id string) (Promotion, error) {
+	return svc.promos.RetrieveByID(ctx, id)
+}
+
+func (svc promotionsService) ListPromotions(ctx context.Context, token string, pm PageMetadata) (PromotionsPage, error) {
+	pm.Code = NormalizeCode(pm.Code)
+	return svc.promos.RetrieveAll(ctx, pm)
+}
+
+func (svc promotionsService) UpdatePromotion(ctx context.Context, token string, promo Promotion) (string, error) {
+	current, err := svc.promos.RetrieveByID(ctx, promo.ID)
+	if err != nil {
+		return "", err
+	}
+	promo.Vendor = current.Vendor
+	promo.Code = NormalizeCode(promo.Code)
+	if err := promo.Validate(); err != nil {
+		return "", err
+	}
+	promo.CreatedAt = current.CreatedAt
+	promo.UpdatedAt = time.Now()
+	return svc.promos.Update(ctx, promo)
+}
+
+func (svc promotionsService) RemovePromotion(ctx context.Context, token, id string) error {
+	return svc.promos.Remove(ctx, id)
+}
+
+func (svc promotionsService) Quote(ctx context.Context, token string, basket Basket) ([]orders.Adjustment, error) {
+	if basket.Vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	if basket.Total == 0 {
+		basket.Total = orders.Order{Items: basket.Items}.ItemsTotal()
+	}
+	if basket.At.IsZero() {
+		basket.At = time.Now()
+	}
+
+	var entered *Promotion
+	if code := NormalizeCode(basket.Code); code != "" {
+		promo, err := svc.promos.RetrieveByCode(ctx, basket.Vendor, code)
+		if errors.Contains(err, errors.ErrNotFound) {
+			return nil, ErrInvalidCode
+		}
+		if err != nil {
+			return nil, err
+		}
+		ok, err := svc.applies(ctx, promo, basket)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || promo.discount(basket) == 0 {
+			return nil, ErrInvalidCode
+		}
+		entered = &promo
+	}
+
+	candidates, err := svc.promos.RetrieveAutomatic(ctx, basket.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	var automatic []Promotion
+	for _, promo := range candidates {
+		ok, err := svc.applies(ctx, promo, basket)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			automatic = append(automatic, promo)
+		}
+	}
+	return combine(entered, automatic, basket), nil
+}
+
+func (svc promotionsService) Redeem(ctx context.Context, token string, basket Basket, adjustments []orders.Adjustment) (string, error) {
+	// The redemptions are kept under a reference until the order they are
+	// for is placed.
+	ref := ulid.Make().String()
+	var redemptions []Redemption
+	for _, adj := range adjustments {
+		if adj.Promotion == "" {
+			continue
+		}
+		redemptions = append(redemptions, Redemption{
+			PromotionID: adj.Promotion,
+			OrderID:     ref,
+			Customer:    basket.Customer,
+			Amount:      adj.Amount,
+			RedeemedAt:  time.Now(),
+		})
+	}
+	if len(redemptions) == 0 {
+		return "", nil
+	}
+	if err := svc.redemptions.Reserve(ctx, redemptions...); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func (svc promotionsService) Assign(ctx context.Context, token, ref, orderID string) error {
+	if ref == "" {
+		return nil
+	}
+	return svc.redemptions.Assign(ctx, ref, orderID)
+}
+
+func (svc promotionsService) Release(ctx context.Context, token, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	return svc.redemptions.Release(ctx, ref)
+}
+
+// applies reports whether the promotion can be used on the basket right
+// now. Usage limits are checked against the redemptions so far, Redeem
+// checks them again when the uses are counted.
+func (svc promotionsService) applies(ctx context.Context, promo Promotion, basket Basket) (bool, error) {
+	if promo.Vendor != basket.Vendor || !promo.runs(basket.At.In(svc.config.Location)) {
+		return false, nil
+	}
+	if basket.Total < promo.MinSpend {
+		return false, nil
+	}
+	if promo.MaxUses == 0 && promo.MaxUsesPerCustomer == 0 {
+		return true, nil
+	}
+	if promo.MaxUsesPerCustomer > 0 && basket.Customer == "" {
+		return false, nil
+	}
+	uses, customerUses, err := svc.redemptions.Count(ctx, promo.ID, basket.Customer)
+	if err != nil {
+		return false, err
+	}
+	if promo.MaxUses > 0 && uses >= promo.MaxUses {
+		return false, nil
+	}
+	if promo.MaxUsesPerCustomer > 0 && customerUses >= promo.MaxUsesPerCustomer {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,3 @@
+for text in "habari" "menyu" "ongeza chapati 2" "3" "maliza" "peleka" "Kilimani" "ndio" "mpesa"; do
+	curl --location --request POST 'http://localhost:9191/chatbot/simulator/messages' --header 'Content-Type: application/json' --data-raw "{\"from\":\"+254700000000\", \"text\":\"$text\"}"
+done
@@ -0,0 +1,56 @@
+#!/bin/sh
+# Applies all the migrations to a throwaway single-node CockroachDB, rolls
+# them all back and applies them again, failing if a rollback leaves anything
+# behind. The tests of orders/postgres do so and check the orders
+# repository, then the CLI does so.
+# Run "make cli" first.
+set -e
+
+JIKONI=${JIKONI:-./build/jikoni}
+CONTAINER=jikoni-cockroach-test
+export JIKONI_DB_ENGINE=cockroach
+export JIKONI_DB_HOST=localhost
+export JIKONI_DB_PORT=${JIKONI_DB_PORT:-26299}
+export JIKONI_DB_USER=root
+export JIKONI_DB=jikoni
+export JIKONI_DB_SSL_MODE=disable
+
+docker run -d --rm --name $CONTAINER -p $JIKONI_DB_PORT:26257 \
+	cockroachdb/cockroach:v23.1.11 start-single-node --insecure >/dev/null
+trap 'docker stop $CONTAINER >/dev/null' EXIT
+
+sql() {
+	docker exec $CONTAINER ./cockroach sql --insecure --format=tsv -e "$1"
+}
+
+until sql "SELECT 1" >/dev/null 2>&1; do
+	sleep 1
+done
+sql "CREATE DATABASE IF NOT EXISTS $JIKONI_DB" >/dev/null
+
+leftovers() {
+	sql "SELECT count(*) FROM $JIKONI_DB.information_schema.tables
+		 WHERE table_schema = 'public'
+		 AND table_name NOT IN ('gorp_migrations', 'jikoni_migration_lock')" | tail -n 1
+}
+
+# The insecure node lets root in without a password, which is only left
+# empty with the flag.
+jikoni() {
+	$JIKONI -db-pass '' "$@"
+}
+
+JIKONI_TEST_DB_ENGINE=cockroach JIKONI_TEST_DB_HOST=$JIKONI_DB_HOST JIKONI_TEST_DB_PORT=$JIKONI_DB_PORT \
+	JIKONI_TEST_DB_USER=$JIKONI_DB_USER JIKONI_TEST_DB=$JIKONI_DB \
+	go test -mod=vendor -count=1 ./orders/postgres/
+
+jikoni migrate up
+jikoni migrate redo
+jikoni migrate down -all
+if [ "$(leftovers)" != "0" ]; then
+	echo "rolling back all the migrations left relations behind" >&2
+	exit 1
+fi
+jikoni migrate up
+jikoni -format csv migrate status
+echo "migrations are reversible"
@@ -0,0 +1,3 @@
+curl --location --request POST 'http://localhost:9191/menu' --header 'Authorization: Bearer token' --header 'Content-Type: application/json' --data-raw '{"name":"Chapati", "vendor":"jikoni", "category": "food", "price": 20}'
+curl --location --request POST 'http://localhost:9191/menu' --header 'Authorization: Bearer token' --header 'Content-Type: application/json' --data-raw '{"name":"Beans", "vendor":"jikoni", "category": "food", "price": 70}'
+curl --location --request POST 'http://localhost:9191/menu' --header 'Authorization: Bearer token' --header 'Content-Type: application/json' --data-raw '{"name":"Tea", "vendor":"jikoni", "category": "drinks", "price": 30}'
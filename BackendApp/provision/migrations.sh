@@ -0,0 +1,47 @@
+#!/bin/sh
+# Applies all the migrations to a throwaway Postgres, rolls them all back and
+# applies them again, failing if a rollback leaves anything behind or an up
+# does not apply over its own rollback. The tests of orders/postgres do so
+# and check the orders repository, then the CLI does so. Run "make cli"
+# first.
+set -e
+
+JIKONI=${JIKONI:-./build/jikoni}
+CONTAINER=jikoni-migrations-test
+export JIKONI_DB_HOST=localhost
+export JIKONI_DB_PORT=${JIKONI_DB_PORT:-5499}
+export JIKONI_DB_USER=jikoniuser
+export JIKONI_DB_PASS=jikonipass
+export JIKONI_DB=jikoni
+
+docker run -d --rm --name $CONTAINER -p $JIKONI_DB_PORT:5432 \
+	-e POSTGRES_USER=$JIKONI_DB_USER -e POSTGRES_PASSWORD=$JIKONI_DB_PASS -e POSTGRES_DB=$JIKONI_DB \
+	postgres:13.3-alpine >/dev/null
+trap 'docker stop $CONTAINER >/dev/null' EXIT
+
+until docker exec $CONTAINER pg_isready -U $JIKONI_DB_USER -d $JIKONI_DB >/dev/null 2>&1; do
+	sleep 1
+done
+# The server restarts once initialised.
+sleep 2
+
+leftovers() {
+	docker exec $CONTAINER psql -U $JIKONI_DB_USER -d $JIKONI_DB -tAc \
+		"SELECT count(*) FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		 WHERE n.nspname = 'public' AND c.relname NOT LIKE 'gorp_migrations%'"
+}
+
+JIKONI_TEST_DB_HOST=$JIKONI_DB_HOST JIKONI_TEST_DB_PORT=$JIKONI_DB_PORT \
+	JIKONI_TEST_DB_USER=$JIKONI_DB_USER JIKONI_TEST_DB_PASS=$JIKONI_DB_PASS JIKONI_TEST_DB=$JIKONI_DB \
+	go test -mod=vendor -count=1 ./orders/postgres/
+
+$JIKONI migrate up
+$JIKONI migrate redo
+$JIKONI migrate down -all
+if [ "$(leftovers)" != "0" ]; then
+	echo "rolling back all the migrations left relations behind" >&2
+	exit 1
+fi
+$JIKONI migrate up
+$JIKONI -format csv migrate status
+echo "migrations are reversible"
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/go-kit/kit/endpoint"
+)
+
+const (
+	suppliersPath      = "suppliers"
+	purchaseOrdersPath = "purchase-orders"
+)
+
+func createSupplierEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createSupplierReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateSupplier(ctx, req.token, req.supplier)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{path: suppliersPath, ID: id}, nil
+	}
+}
+
+func viewSupplierEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		supplier, err := svc.ViewSupplier(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewSupplierRes{Supplier: supplier}, nil
+	}
+}
+
+func listSuppliersEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := purchasing.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Name:   req.name,
+		}
+		page, err := svc.ListSuppliers(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := suppliersPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Suppliers: []purchasing.Supplier{},
+		}
+		res.Suppliers = append(res.Suppliers, page.Suppliers...)
+		return res, nil
+	}
+}
+
+func updateSupplierEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateSupplierReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateSupplier(ctx, req.token, req.supplier)
+		if err != nil {
+			return nil, err
+		}
+		return updateRes{path: suppliersPath, ID: id}, nil
+	}
+}
+
+func removeSupplierEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveSupplier(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func createPurchaseOrderEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createPurchaseOrderReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreatePurchaseOrder(ctx, req.token, req.po)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{path: purchaseOrdersPath, ID: id}, nil
+	}
+}
+
+func viewPurchaseOrderEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		po, err := svc.ViewPurchaseOrder(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewPurchaseOrderRes{PurchaseOrder: po}, nil
+	}
+}
+
+func listPurchaseOrdersEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := purchasing.PageMetadata{
+			Offset:   req.offset,
+			Limit:    req.limit,
+			Vendor:   req.vendor,
+			Supplier: req.supplier,
+			Status:   req.status,
+		}
+		page, err := svc.ListPurchaseOrders(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := purchaseOrdersPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			PurchaseOrders: []purchasing.PurchaseOrder{},
+		}
+		res.PurchaseOrders = append(res.PurchaseOrders, page.PurchaseOrders...)
+		return res, nil
+	}
+}
+
+func updatePurchaseOrderEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updatePurchaseOrderReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdatePurchaseOrder(ctx, req.token, req.po)
+		if err != nil {
+			return nil, err
+		}
+		return updateRes{path: purchaseOrdersPath, ID: id}, nil
+	}
+}
+
+func sendPurchaseOrderEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		po, err := svc.SendPurchaseOrder(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewPurchaseOrderRes{PurchaseOrder: po}, nil
+	}
+}
+
+func cancelPurchaseOrderEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		po, err := svc.CancelPurchaseOrder(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewPurchaseOrderRes{PurchaseOrder: po}, nil
+	}
+}
+
+func receiveGoodsEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(receiveGoodsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		grn, err := svc.ReceiveGoods(ctx, req.token, req.grn)
+		if err != nil {
+			return nil, err
+		}
+		return goodsReceivedRes{GoodsReceived: grn, created: true}, nil
+	}
+}
+
+func viewGoodsReceivedEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		grn, err := svc.ViewGoodsReceived(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return goodsReceivedRes{GoodsReceived: grn}, nil
+	}
+}
+
+func listGoodsReceivedEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := purchasing.PageMetadata{
+			Offset:        req.offset,
+			Limit:         req.limit,
+			Vendor:        req.vendor,
+			PurchaseOrder: req.purchaseOrder,
+		}
+		page, err := svc.ListGoodsReceived(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := goodsReceivedPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			GoodsReceived: []purchasing.GoodsReceived{},
+		}
+		res.GoodsReceived = append(res.GoodsReceived, page.GoodsReceived...)
+		return res, nil
+	}
+}
+
+func suggestionsEndpoint(svc purchasing.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(suggestionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		suggestions, err := svc.Suggestions(ctx, req.token, req.vendor, req.days)
+		if err != nil {
+			return nil, err
+		}
+		res := suggestionsRes{
+			Vendor:      req.vendor,
+			Days:        req.days,
+			Suggestions: []purchasing.Suggestion{},
+		}
+		res.Suggestions = append(res.Suggestions, suggestions...)
+		return res, nil
+	}
+}
@@ -0,0 +1,247 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/go-kit/log"
+)
+
+var _ purchasing.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    purchasing.Service
+}
+
+// LoggingMiddleware adds logging facilities to the purchasing service.
+func LoggingMiddleware(svc purchasing.Service, logger log.Logger) purchasing.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateSupplier(ctx context.Context, token string, supplier purchasing.Supplier) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_supplier",
+			"token", token,
+			"vendor", supplier.Vendor,
+			"name", supplier.Name,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateSupplier(ctx, token, supplier)
+}
+
+func (lm *loggingMiddleware) ViewSupplier(ctx context.Context, token, id string) (supplier purchasing.Supplier, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_supplier",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewSupplier(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSuppliers(ctx context.Context, token string, pm purchasing.PageMetadata) (page purchasing.SuppliersPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_suppliers",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListSuppliers(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateSupplier(ctx context.Context, token string, supplier purchasing.Supplier) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_supplier",
+			"token", token,
+			"id", supplier.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateSupplier(ctx, token, supplier)
+}
+
+func (lm *loggingMiddleware) RemoveSupplier(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_supplier",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveSupplier(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CreatePurchaseOrder(ctx context.Context, token string, po purchasing.PurchaseOrder) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_purchase_order",
+			"token", token,
+			"vendor", po.Vendor,
+			"supplier", po.Supplier,
+			"lines", len(po.Lines),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreatePurchaseOrder(ctx, token, po)
+}
+
+func (lm *loggingMiddleware) ViewPurchaseOrder(ctx context.Context, token, id string) (po purchasing.PurchaseOrder, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_purchase_order",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewPurchaseOrder(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListPurchaseOrders(ctx context.Context, token string, pm purchasing.PageMetadata) (page purchasing.PurchaseOrdersPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_purchase_orders",
+			"token", token,
+			"vendor", pm.Vendor,
+			"supplier", pm.Supplier,
+			"status", pm.Status,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListPurchaseOrders(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdatePurchaseOrder(ctx context.Context, token string, po purchasing.PurchaseOrder) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_purchase_order",
+			"token", token,
+			"id", po.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdatePurchaseOrder(ctx, token, po)
+}
+
+func (lm *loggingMiddleware) SendPurchaseOrder(ctx context.Context, token, id string) (po purchasing.PurchaseOrder, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "send_purchase_order",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.SendPurchaseOrder(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CancelPurchaseOrder(ctx context.Context, token, id string) (po purchasing.PurchaseOrder, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "cancel_purchase_order",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CancelPurchaseOrder(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ReceiveGoods(ctx context.Context, token string, grn purchasing.GoodsReceived) (received purchasing.GoodsReceived, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "receive_goods",
+			"token", token,
+			"purchase_order", grn.PurchaseOrder,
+			"reference", grn.Reference,
+			"lines", len(grn.Lines),
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ReceiveGoods(ctx, token, grn)
+}
+
+func (lm *loggingMiddleware) ViewGoodsReceived(ctx context.Context, token, id string) (grn purchasing.GoodsReceived, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_goods_received",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewGoodsReceived(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListGoodsReceived(ctx context.Context, token string, pm purchasing.PageMetadata) (page purchasing.GoodsReceivedPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_goods_received",
+			"token", token,
+			"purchase_order", pm.PurchaseOrder,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListGoodsReceived(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) Suggestions(ctx context.Context, token, vendor string, days uint64) (suggestions []purchasing.Suggestion, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "reorder_suggestions",
+			"token", token,
+			"vendor", vendor,
+			"days", days,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Suggestions(ctx, token, vendor, days)
+}
@@ -0,0 +1,164 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ purchasing.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     purchasing.Service
+}
+
+// MetricsMiddleware instruments the purchasing service by tracking request count
+// and latency.
+func MetricsMiddleware(svc purchasing.Service, counter metrics.Counter, latency metrics.Histogram) purchasing.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateSupplier(ctx context.Context, token string, supplier purchasing.Supplier) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_supplier").Add(1)
+		ms.latency.With("method", "create_supplier").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateSupplier(ctx, token, supplier)
+}
+
+func (ms *metricsMiddleware) ViewSupplier(ctx context.Context, token, id string) (purchasing.Supplier, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_supplier").Add(1)
+		ms.latency.With("method", "view_supplier").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSupplier(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListSuppliers(ctx context.Context, token string, pm purchasing.PageMetadata) (purchasing.SuppliersPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_suppliers").Add(1)
+		ms.latency.With("method", "list_suppliers").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSuppliers(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateSupplier(ctx context.Context, token string, supplier purchasing.Supplier) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_supplier").Add(1)
+		ms.latency.With("method", "update_supplier").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateSupplier(ctx, token, supplier)
+}
+
+func (ms *metricsMiddleware) RemoveSupplier(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_supplier").Add(1)
+		ms.latency.With("method", "remove_supplier").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSupplier(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) CreatePurchaseOrder(ctx context.Context, token string, po purchasing.PurchaseOrder) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_purchase_order").Add(1)
+		ms.latency.With("method", "create_purchase_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreatePurchaseOrder(ctx, token, po)
+}
+
+func (ms *metricsMiddleware) ViewPurchaseOrder(ctx context.Context, token, id string) (purchasing.PurchaseOrder, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_purchase_order").Add(1)
+		ms.latency.With("method", "view_purchase_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewPurchaseOrder(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListPurchaseOrders(ctx context.Context, token string, pm purchasing.PageMetadata) (purchasing.PurchaseOrdersPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_purchase_orders").Add(1)
+		ms.latency.With("method", "list_purchase_orders").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListPurchaseOrders(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdatePurchaseOrder(ctx context.Context, token string, po purchasing.PurchaseOrder) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_purchase_order").Add(1)
+		ms.latency.With("method", "update_purchase_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdatePurchaseOrder(ctx, token, po)
+}
+
+func (ms *metricsMiddleware) SendPurchaseOrder(ctx context.Context, token, id string) (purchasing.PurchaseOrder, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "send_purchase_order").Add(1)
+		ms.latency.With("method", "send_purchase_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SendPurchaseOrder(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) CancelPurchaseOrder(ctx context.Context, token, id string) (purchasing.PurchaseOrder, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "cancel_purchase_order").Add(1)
+		ms.latency.With("method", "cancel_purchase_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CancelPurchaseOrder(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ReceiveGoods(ctx context.Context, token string, grn purchasing.GoodsReceived) (purchasing.GoodsReceived, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "receive_goods").Add(1)
+		ms.latency.With("method", "receive_goods").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReceiveGoods(ctx, token, grn)
+}
+
+func (ms *metricsMiddleware) ViewGoodsReceived(ctx context.Context, token, id string) (purchasing.GoodsReceived, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_goods_received").Add(1)
+		ms.latency.With("method", "view_goods_received").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewGoodsReceived(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListGoodsReceived(ctx context.Context, token string, pm purchasing.PageMetadata) (purchasing.GoodsReceivedPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_goods_received").Add(1)
+		ms.latency.With("method", "list_goods_received").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListGoodsReceived(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) Suggestions(ctx context.Context, token, vendor string, days uint64) ([]purchasing.Suggestion, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reorder_suggestions").Add(1)
+		ms.latency.With("method", "reorder_suggestions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Suggestions(ctx, token, vendor, days)
+}
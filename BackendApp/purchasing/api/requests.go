@@ -0,0 +1,132 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+)
+
+const (
+	maxLimitSize = 100
+	maxDays      = 90
+)
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type createSupplierReq struct {
+	token    string
+	supplier purchasing.Supplier
+}
+
+func (req createSupplierReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.supplier.Validate()
+}
+
+type updateSupplierReq struct {
+	token    string
+	supplier purchasing.Supplier
+}
+
+func (req updateSupplierReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.supplier.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type createPurchaseOrderReq struct {
+	token string
+	po    purchasing.PurchaseOrder
+}
+
+func (req createPurchaseOrderReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.po.Validate()
+}
+
+type updatePurchaseOrderReq struct {
+	token string
+	po    purchasing.PurchaseOrder
+}
+
+func (req updatePurchaseOrderReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.po.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type receiveGoodsReq struct {
+	token string
+	grn   purchasing.GoodsReceived
+}
+
+func (req receiveGoodsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.grn.PurchaseOrder == "" {
+		return errors.ErrMissingID
+	}
+	return req.grn.Validate()
+}
+
+type listReq struct {
+	token         string
+	vendor        string
+	name          string
+	supplier      string
+	status        string
+	purchaseOrder string
+	offset        uint64
+	limit         uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type suggestionsReq struct {
+	token  string
+	vendor string
+	days   uint64
+}
+
+func (req suggestionsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" || req.days > maxDays {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createRes)(nil)
+	_ Response = (*updateRes)(nil)
+	_ Response = (*removeRes)(nil)
+	_ Response = (*viewSupplierRes)(nil)
+	_ Response = (*suppliersPageRes)(nil)
+	_ Response = (*viewPurchaseOrderRes)(nil)
+	_ Response = (*purchaseOrdersPageRes)(nil)
+	_ Response = (*goodsReceivedRes)(nil)
+	_ Response = (*goodsReceivedPageRes)(nil)
+	_ Response = (*suggestionsRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+// createRes is the response to a created supplier or purchase order, path
+// is where it can be found.
+type createRes struct {
+	path string
+	ID   string
+}
+
+func (res createRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/%s/%s", res.path, res.ID),
+	}
+}
+
+func (res createRes) Empty() bool {
+	return true
+}
+
+type updateRes struct {
+	path string
+	ID   string
+}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/%s/%s", res.path, res.ID),
+	}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type viewSupplierRes struct {
+	purchasing.Supplier
+}
+
+func (res viewSupplierRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSupplierRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSupplierRes) Empty() bool {
+	return false
+}
+
+type suppliersPageRes struct {
+	pageRes
+	Suppliers []purchasing.Supplier `json:"suppliers"`
+}
+
+func (res suppliersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res suppliersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res suppliersPageRes) Empty() bool {
+	return false
+}
+
+type viewPurchaseOrderRes struct {
+	purchasing.PurchaseOrder
+}
+
+func (res viewPurchaseOrderRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewPurchaseOrderRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewPurchaseOrderRes) Empty() bool {
+	return false
+}
+
+type purchaseOrdersPageRes struct {
+	pageRes
+	PurchaseOrders []purchasing.PurchaseOrder `json:"purchase_orders"`
+}
+
+func (res purchaseOrdersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res purchaseOrdersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res purchaseOrdersPageRes) Empty() bool {
+	return false
+}
+
+type goodsReceivedRes struct {
+	purchasing.GoodsReceived
+	created bool
+}
+
+func (res goodsReceivedRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res goodsReceivedRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/receipts/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res goodsReceivedRes) Empty() bool {
+	return false
+}
+
+type goodsReceivedPageRes struct {
+	pageRes
+	GoodsReceived []purchasing.GoodsReceived `json:"receipts"`
+}
+
+func (res goodsReceivedPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res goodsReceivedPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res goodsReceivedPageRes) Empty() bool {
+	return false
+}
+
+type suggestionsRes struct {
+	Vendor      string                  `json:"vendor"`
+	Days        uint64                  `json:"days,omitempty"`
+	Suggestions []purchasing.Suggestion `json:"suggestions"`
+}
+
+func (res suggestionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res suggestionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res suggestionsRes) Empty() bool {
+	return false
+}
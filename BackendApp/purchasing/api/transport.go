@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	nameKey     = "name"
+	supplierKey = "supplier"
+	statusKey   = "status"
+	daysKey     = "days"
+)
+
+// MakePurchasingHandler returns a HTTP handler for suppliers, purchase
+// orders and goods received API endpoints.
+func MakePurchasingHandler(svc purchasing.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/suppliers").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_supplier")(createSupplierEndpoint(svc)),
+		decodeCreateSupplier,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/suppliers/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_supplier")(viewSupplierEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/suppliers").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_suppliers")(listSuppliersEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/suppliers/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_supplier")(updateSupplierEndpoint(svc)),
+		decodeUpdateSupplier,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/suppliers/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_supplier")(removeSupplierEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/purchase-orders").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_purchase_order")(createPurchaseOrderEndpoint(svc)),
+		decodeCreatePurchaseOrder,
+		encodeResponse,
+		opts...,
+	))
+
+	// Registered before /purchase-orders/{id} so it is not taken for an
+	// identifier.
+	r.Methods("GET").Path("/purchase-orders/suggestions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint reorder_suggestions")(suggestionsEndpoint(svc)),
+		decodeSuggestions,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/purchase-orders/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_purchase_order")(viewPurchaseOrderEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/purchase-orders").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_purchase_orders")(listPurchaseOrdersEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/purchase-orders/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_purchase_order")(updatePurchaseOrderEndpoint(svc)),
+		decodeUpdatePurchaseOrder,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/purchase-orders/{id}/send").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint send_purchase_order")(sendPurchaseOrderEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/purchase-orders/{id}/cancel").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint cancel_purchase_order")(cancelPurchaseOrderEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/purchase-orders/{id}/receipts").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint receive_goods")(receiveGoodsEndpoint(svc)),
+		decodeReceiveGoods,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/purchase-orders/{id}/receipts").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_goods_received")(listGoodsReceivedEndpoint(svc)),
+		decodeListGoodsReceived,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/receipts/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_goods_received")(viewGoodsReceivedEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeCreateSupplier(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var supplier purchasing.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createSupplierReq{
+		token:    decodeToken(r),
+		supplier: supplier,
+	}
+	return req, nil
+}
+
+func decodeUpdateSupplier(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var supplier purchasing.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	supplier.ID = mux.Vars(r)["id"]
+	req := updateSupplierReq{
+		token:    decodeToken(r),
+		supplier: supplier,
+	}
+	return req, nil
+}
+
+func decodeCreatePurchaseOrder(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var po purchasing.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createPurchaseOrderReq{
+		token: decodeToken(r),
+		po:    po,
+	}
+	return req, nil
+}
+
+func decodeUpdatePurchaseOrder(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var po purchasing.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	po.ID = mux.Vars(r)["id"]
+	req := updatePurchaseOrderReq{
+		token: decodeToken(r),
+		po:    po,
+	}
+	return req, nil
+}
+
+func decodeReceiveGoods(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var grn purchasing.GoodsReceived
+	if err := json.NewDecoder(r.Body).Decode(&grn); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	grn.PurchaseOrder = mux.Vars(r)["id"]
+	req := receiveGoodsReq{
+		token: decodeToken(r),
+		grn:   grn,
+	}
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{
+		token:    decodeToken(r),
+		vendor:   r.URL.Query().Get(vendorKey),
+		name:     r.URL.Query().Get(nameKey),
+		supplier: r.URL.Query().Get(supplierKey),
+		status:   r.URL.Query().Get(statusKey),
+	}
+	var err error
+	if req.offset, req.limit, err = decodePage(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeListGoodsReceived(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{
+		token:         decodeToken(r),
+		purchaseOrder: mux.Vars(r)["id"],
+	}
+	var err error
+	if req.offset, req.limit, err = decodePage(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodePage(r *http.Request) (uint64, uint64, error) {
+	var offset, limit uint64 = 0, maxLimitSize
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return offset, limit, nil
+}
+
+func decodeSuggestions(_ context.Context, r *http.Request) (interface{}, error) {
+	req := suggestionsReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	if r.URL.Query().Has(daysKey) {
+		days, err := strconv.ParseUint(r.URL.Query().Get(daysKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		req.days = days
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, errors.ErrInvalidStatus):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, purchasing.ErrOverDelivery):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
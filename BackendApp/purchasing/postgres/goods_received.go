@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/jmoiron/sqlx"
+)
+
+const goodsReceivedColumns = `id, vendor, purchase_order, reference, lines, received_at`
+
+var _ purchasing.GoodsReceivedRepository = (*goodsReceivedRepo)(nil)
+
+type goodsReceivedRepo struct {
+	db *sqlx.DB
+}
+
+// NewGoodsReceivedRepo instantiates a PostgreSQL
+// implementation of goods received notes repository.
+func NewGoodsReceivedRepo(db *sqlx.DB) purchasing.GoodsReceivedRepository {
+	return &goodsReceivedRepo{
+		db: db,
+	}
+}
+
+func (repo goodsReceivedRepo) RetrieveByID(ctx context.Context, id string) (purchasing.GoodsReceived, error) {
+	q := `SELECT ` + goodsReceivedColumns + ` FROM goods_received WHERE id = $1`
+
+	dbg := dbGoodsReceived{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbg); err != nil {
+		if err == sql.ErrNoRows {
+			return purchasing.GoodsReceived{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return purchasing.GoodsReceived{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toGoodsReceived(dbg)
+}
+
+func (repo goodsReceivedRepo) RetrieveAll(ctx context.Context, pm purchasing.PageMetadata) (purchasing.GoodsReceivedPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":          pm.Limit,
+		"offset":         pm.Offset,
+		"vendor":         pm.Vendor,
+		"purchase_order": pm.PurchaseOrder,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.PurchaseOrder != "" {
+		query = append(query, "purchase_order = :purchase_order")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+goodsReceivedColumns+` FROM goods_received %s ORDER BY received_at DESC, id DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return purchasing.GoodsReceivedPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []purchasing.GoodsReceived
+	for rows.Next() {
+		dbg := dbGoodsReceived{}
+		if err := rows.StructScan(&dbg); err != nil {
+			return purchasing.GoodsReceivedPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		grn, err := toGoodsReceived(dbg)
+		if err != nil {
+			return purchasing.GoodsReceivedPage{}, err
+		}
+		items = append(items, grn)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM goods_received %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return purchasing.GoodsReceivedPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := purchasing.GoodsReceivedPage{
+		GoodsReceived: items,
+		PageMetadata: purchasing.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+type dbGoodsReceived struct {
+	ID            string    `db:"id"`
+	Vendor        string    `db:"vendor"`
+	PurchaseOrder string    `db:"purchase_order"`
+	Reference     string    `db:"reference"`
+	Lines         []byte    `db:"lines"`
+	ReceivedAt    time.Time `db:"received_at"`
+}
+
+func toDBGoodsReceived(grn purchasing.GoodsReceived) (dbGoodsReceived, error) {
+	lines := []byte("[]")
+	if len(grn.Lines) > 0 {
+		b, err := json.Marshal(grn.Lines)
+		if err != nil {
+			return dbGoodsReceived{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		lines = b
+	}
+	return dbGoodsReceived{
+		ID:            grn.ID,
+		Vendor:        grn.Vendor,
+		PurchaseOrder: grn.PurchaseOrder,
+		Reference:     grn.Reference,
+		Lines:         lines,
+		ReceivedAt:    grn.ReceivedAt,
+	}, nil
+}
+
+func toGoodsReceived(dbg dbGoodsReceived) (purchasing.GoodsReceived, error) {
+	var lines []purchasing.ReceivedLine
+	if dbg.Lines != nil {
+		if err := json.Unmarshal(dbg.Lines, &lines); err != nil {
+			return purchasing.GoodsReceived{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return purchasing.GoodsReceived{
+		ID:            dbg.ID,
+		Vendor:        dbg.Vendor,
+		PurchaseOrder: dbg.PurchaseOrder,
+		Reference:     dbg.Reference,
+		Lines:         lines,
+		ReceivedAt:    dbg.ReceivedAt,
+	}, nil
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/jmoiron/sqlx"
+)
+
+const purchaseOrderColumns = `id, vendor, supplier, status, lines, expected_at, notes, created_at, updated_at`
+
+var _ purchasing.PurchaseOrderRepository = (*purchaseOrdersRepo)(nil)
+
+type purchaseOrdersRepo struct {
+	db *sqlx.DB
+}
+
+// NewPurchaseOrdersRepo instantiates a PostgreSQL
+// implementation of purchase orders repository.
+func NewPurchaseOrdersRepo(db *sqlx.DB) purchasing.PurchaseOrderRepository {
+	return &purchaseOrdersRepo{
+		db: db,
+	}
+}
+
+func (repo purchaseOrdersRepo) Save(ctx context.Context, po purchasing.PurchaseOrder) (string, error) {
+	q := `INSERT INTO purchase_orders (` + purchaseOrderColumns + `)
+		  VALUES (:id, :vendor, :supplier, :status, :lines, :expected_at, :notes, :created_at, :updated_at) RETURNING id`
+
+	dbpo, err := toDBPurchaseOrder(po)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbpo)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo purchaseOrdersRepo) RetrieveByID(ctx context.Context, id string) (purchasing.PurchaseOrder, error) {
+	q := `SELECT ` + purchaseOrderColumns + ` FROM purchase_orders WHERE id = $1`
+
+	dbpo := dbPurchaseOrder{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbpo); err != nil {
+		if err == sql.ErrNoRows {
+			return purchasing.PurchaseOrder{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return purchasing.PurchaseOrder{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toPurchaseOrder(dbpo)
+}
+
+func (repo purchaseOrdersRepo) RetrieveAll(ctx context.Context, pm purchasing.PageMetadata) (purchasing.PurchaseOrdersPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+		"vendor":   pm.Vendor,
+		"supplier": pm.Supplier,
+		"status":   pm.Status,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Supplier != "" {
+		query = append(query, "supplier = :supplier")
+	}
+	if pm.Status != "" {
+		query = append(query, "status = :status")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+purchaseOrderColumns+` FROM purchase_orders %s ORDER BY created_at DESC, id DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return purchasing.PurchaseOrdersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []purchasing.PurchaseOrder
+	for rows.Next() {
+		dbpo := dbPurchaseOrder{}
+		if err := rows.StructScan(&dbpo); err != nil {
+			return purchasing.PurchaseOrdersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		po, err := toPurchaseOrder(dbpo)
+		if err != nil {
+			return purchasing.PurchaseOrdersPage{}, err
+		}
+		items = append(items, po)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM purchase_orders %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return purchasing.PurchaseOrdersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := purchasing.PurchaseOrdersPage{
+		PurchaseOrders: items,
+		PageMetadata: purchasing.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo purchaseOrdersRepo) Update(ctx context.Context, po purchasing.PurchaseOrder) (string, error) {
+	q := `UPDATE purchase_orders SET status = :status, lines = :lines, expected_at = :expected_at, notes = :notes, updated_at = :updated_at
+		  WHERE id = :id RETURNING id`
+
+	dbpo, err := toDBPurchaseOrder(po)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbpo)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo purchaseOrdersRepo) Receive(ctx context.Context, grn purchasing.GoodsReceived) (purchasing.PurchaseOrder, error) {
+	var po purchasing.PurchaseOrder
+	err := pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		// The purchase order is locked so deliveries received at the same
+		// time are not both counted against what is outstanding.
+		pq := `SELECT ` + purchaseOrderColumns + ` FROM purchase_orders WHERE id = $1 FOR UPDATE`
+		dbpo := dbPurchaseOrder{}
+		if err := tx.QueryRowxContext(ctx, pq, grn.PurchaseOrder).StructScan(&dbpo); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.Wrap(errors.ErrNotFound, err)
+			}
+			return errors.Wrap(errors.ErrViewEntity, err)
+		}
+		current, err := toPurchaseOrder(dbpo)
+		if err != nil {
+			return err
+		}
+		if po, err = current.Receive(grn); err != nil {
+			return err
+		}
+
+		uq := `UPDATE purchase_orders SET status = :status, lines = :lines, updated_at = :updated_at WHERE id = :id`
+		if dbpo, err = toDBPurchaseOrder(po); err != nil {
+			return errors.Wrap(errors.ErrUpdateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, uq, dbpo); err != nil {
+			return handleError(err, errors.ErrUpdateEntity)
+		}
+
+		gq := `INSERT INTO goods_received (` + goodsReceivedColumns + `)
+			   VALUES (:id, :vendor, :purchase_order, :reference, :lines, :received_at)`
+		dbg, err := toDBGoodsReceived(grn)
+		if err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, gq, dbg); err != nil {
+			return handleError(err, errors.ErrCreateEntity)
+		}
+		return nil
+	})
+	if err != nil {
+		return purchasing.PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+type dbPurchaseOrder struct {
+	ID         string       `db:"id"`
+	Vendor     string       `db:"vendor"`
+	Supplier   string       `db:"supplier"`
+	Status     string       `db:"status"`
+	Lines      []byte       `db:"lines"`
+	ExpectedAt sql.NullTime `db:"expected_at"`
+	Notes      string       `db:"notes"`
+	CreatedAt  time.Time    `db:"created_at"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+}
+
+func toDBPurchaseOrder(po purchasing.PurchaseOrder) (dbPurchaseOrder, error) {
+	lines := []byte("[]")
+	if len(po.Lines) > 0 {
+		b, err := json.Marshal(po.Lines)
+		if err != nil {
+			return dbPurchaseOrder{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		lines = b
+	}
+	return dbPurchaseOrder{
+		ID:         po.ID,
+		Vendor:     po.Vendor,
+		Supplier:   po.Supplier,
+		Status:     string(po.Status),
+		Lines:      lines,
+		ExpectedAt: sql.NullTime{Time: po.ExpectedAt, Valid: !po.ExpectedAt.IsZero()},
+		Notes:      po.Notes,
+		CreatedAt:  po.CreatedAt,
+		UpdatedAt:  po.UpdatedAt,
+	}, nil
+}
+
+func toPurchaseOrder(dbpo dbPurchaseOrder) (purchasing.PurchaseOrder, error) {
+	var lines []purchasing.Line
+	if dbpo.Lines != nil {
+		if err := json.Unmarshal(dbpo.Lines, &lines); err != nil {
+			return purchasing.PurchaseOrder{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	po := purchasing.PurchaseOrder{
+		ID:        dbpo.ID,
+		Vendor:    dbpo.Vendor,
+		Supplier:  dbpo.Supplier,
+		Status:    purchasing.Status(dbpo.Status),
+		Lines:     lines,
+		Notes:     dbpo.Notes,
+		CreatedAt: dbpo.CreatedAt,
+		UpdatedAt: dbpo.UpdatedAt,
+	}
+	if dbpo.ExpectedAt.Valid {
+		po.ExpectedAt = dbpo.ExpectedAt.Time
+	}
+	return po, nil
+}
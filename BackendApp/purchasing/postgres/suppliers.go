@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+	"github.com/jmoiron/sqlx"
+)
+
+const supplierColumns = `id, vendor, name, phone, email, ingredients, created_at, updated_at`
+
+var _ purchasing.SupplierRepository = (*suppliersRepo)(nil)
+
+type suppliersRepo struct {
+	db *sqlx.DB
+}
+
+// NewSuppliersRepo instantiates a PostgreSQL
+// implementation of suppliers repository.
+func NewSuppliersRepo(db *sqlx.DB) purchasing.SupplierRepository {
+	return &suppliersRepo{
+		db: db,
+	}
+}
+
+func (repo suppliersRepo) Save(ctx context.Context, supplier purchasing.Supplier) (string, error) {
+	q := `INSERT INTO suppliers (` + supplierColumns + `)
+		  VALUES (:id, :vendor, :name, :phone, :email, :ingredients, :created_at, :updated_at) RETURNING id`
+
+	dbs, err := toDBSupplier(supplier)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbs)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo suppliersRepo) RetrieveByID(ctx context.Context, id string) (purchasing.Supplier, error) {
+	q := `SELECT ` + supplierColumns + ` FROM suppliers WHERE id = $1`
+
+	dbs := dbSupplier{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return purchasing.Supplier{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return purchasing.Supplier{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toSupplier(dbs)
+}
+
+func (repo suppliersRepo) RetrieveAll(ctx context.Context, pm purchasing.PageMetadata) (purchasing.SuppliersPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"name":   fmt.Sprintf("%%%s%%", pm.Name),
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Name != "" {
+		query = append(query, "name ILIKE :name")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+supplierColumns+` FROM suppliers %s ORDER BY name LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return purchasing.SuppliersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []purchasing.Supplier
+	for rows.Next() {
+		dbs := dbSupplier{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return purchasing.SuppliersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		supplier, err := toSupplier(dbs)
+		if err != nil {
+			return purchasing.SuppliersPage{}, err
+		}
+		items = append(items, supplier)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM suppliers %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return purchasing.SuppliersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := purchasing.SuppliersPage{
+		Suppliers: items,
+		PageMetadata: purchasing.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo suppliersRepo) Update(ctx context.Context, supplier purchasing.Supplier) (string, error) {
+	q := `UPDATE suppliers SET name = :name, phone = :phone, email = :email, ingredients = :ingredients, updated_at = :updated_at
+		  WHERE id = :id RETURNING id`
+
+	dbs, err := toDBSupplier(supplier)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbs)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo suppliersRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM suppliers WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbSupplier{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbSupplier struct {
+	ID          string    `db:"id"`
+	Vendor      string    `db:"vendor"`
+	Name        string    `db:"name"`
+	Phone       string    `db:"phone"`
+	Email       string    `db:"email"`
+	Ingredients []byte    `db:"ingredients"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func toDBSupplier(supplier purchasing.Supplier) (dbSupplier, error) {
+	ingredients := []byte("[]")
+	if len(supplier.Ingredients) > 0 {
+		b, err := json.Marshal(supplier.Ingredients)
+		if err != nil {
+			return dbSupplier{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		ingredients = b
+	}
+	return dbSupplier{
+		ID:          supplier.ID,
+		Vendor:      supplier.Vendor,
+		Name:        supplier.Name,
+		Phone:       supplier.Phone,
+		Email:       supplier.Email,
+		Ingredients: ingredients,
+		CreatedAt:   supplier.CreatedAt,
+		UpdatedAt:   supplier.UpdatedAt,
+	}, nil
+}
+
+func toSupplier(dbs dbSupplier) (purchasing.Supplier, error) {
+	var ingredients []string
+	if dbs.Ingredients != nil {
+		if err := json.Unmarshal(dbs.Ingredients, &ingredients); err != nil {
+			return purchasing.Supplier{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return purchasing.Supplier{
+		ID:          dbs.ID,
+		Vendor:      dbs.Vendor,
+		Name:        dbs.Name,
+		Phone:       dbs.Phone,
+		Email:       dbs.Email,
+		Ingredients: ingredients,
+		CreatedAt:   dbs.CreatedAt,
+		UpdatedAt:   dbs.UpdatedAt,
+	}, nil
+}
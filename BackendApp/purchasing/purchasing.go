@@ -0,0 +1,332 @@
+// Package purchasing restocks a vendor's ingredients from suppliers. Purchase
+// orders are sent to suppliers and the goods received against them, in one
+// delivery or several, go into the inventory at what they actually cost.
+package purchasing
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+// ErrOverDelivery indicates more of an ingredient received than is still
+// outstanding on the purchase order.
+var ErrOverDelivery = errors.New("received more than was ordered")
+
+// Status is where a purchase order is in its life cycle.
+type Status string
+
+// Purchase order statuses.
+const (
+	Draft     Status = "draft"     // Being written, it can still be changed.
+	Ordered   Status = "ordered"   // Sent to the supplier.
+	Partial   Status = "partial"   // Some of it was delivered.
+	Received  Status = "received"  // All of it was delivered.
+	Cancelled Status = "cancelled" // Called off before all of it was delivered.
+)
+
+// Supplier is who a vendor buys ingredients from.
+type Supplier struct {
+	ID          string    `json:"id,omitempty"`
+	Vendor      string    `json:"vendor,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Phone       string    `json:"phone,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Ingredients []string  `json:"ingredients,omitempty"` // The ingredients the supplier delivers.
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`  // When the supplier was updated.
+	CreatedAt   time.Time `json:"created_at,omitempty"`  // When the supplier was created in the system.
+}
+
+// Validate returns an error if the supplier representation is invalid.
+func (s Supplier) Validate() error {
+	if s.Vendor == "" || s.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Supplies reports whether the supplier delivers the ingredient.
+func (s Supplier) Supplies(ingredient string) bool {
+	for _, ing := range s.Ingredients {
+		if ing == ingredient {
+			return true
+		}
+	}
+	return false
+}
+
+// Line is how much of an ingredient is ordered and how much of it came.
+type Line struct {
+	Ingredient string `json:"ingredient"`
+	Quantity   int64  `json:"quantity"`       // How much is ordered.
+	Received   int64  `json:"received"`       // How much was delivered so far.
+	Cost       uint64 `json:"cost,omitempty"` // What a unit is expected to cost.
+}
+
+// Outstanding returns how much of the line is still to be delivered.
+func (l Line) Outstanding() int64 {
+	if l.Received >= l.Quantity {
+		return 0
+	}
+	return l.Quantity - l.Received
+}
+
+// PurchaseOrder is a vendor's order of ingredients from a supplier.
+type PurchaseOrder struct {
+	ID         string    `json:"id,omitempty"`
+	Vendor     string    `json:"vendor,omitempty"`
+	Supplier   string    `json:"supplier,omitempty"`
+	Status     Status    `json:"status,omitempty"`
+	Lines      []Line    `json:"lines,omitempty"`
+	ExpectedAt time.Time `json:"expected_at,omitempty"` // When the supplier is expected to deliver.
+	Notes      string    `json:"notes,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"` // When the purchase order was updated.
+	CreatedAt  time.Time `json:"created_at,omitempty"` // When the purchase order was created in the system.
+}
+
+// Validate returns an error if the purchase order representation is
+// invalid.
+func (po PurchaseOrder) Validate() error {
+	if po.Vendor == "" || po.Supplier == "" || len(po.Lines) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	seen := make(map[string]bool)
+	for _, line := range po.Lines {
+		if line.Ingredient == "" || line.Quantity <= 0 || seen[line.Ingredient] {
+			return errors.ErrMalformedEntity
+		}
+		seen[line.Ingredient] = true
+	}
+	return nil
+}
+
+// Open reports whether goods can still be received against the purchase
+// order.
+func (po PurchaseOrder) Open() bool {
+	return po.Status == Ordered || po.Status == Partial
+}
+
+// Receive adds the goods received to the lines of the purchase order, it
+// is received once nothing is outstanding.
+func (po PurchaseOrder) Receive(grn GoodsReceived) (PurchaseOrder, error) {
+	if !po.Open() {
+		return PurchaseOrder{}, errors.ErrInvalidStatus
+	}
+	lines := make([]Line, len(po.Lines))
+	copy(lines, po.Lines)
+	for _, received := range grn.Lines {
+		i := indexOf(lines, received.Ingredient)
+		if i < 0 {
+			return PurchaseOrder{}, errors.ErrMalformedEntity
+		}
+		if received.Quantity > lines[i].Outstanding() {
+			return PurchaseOrder{}, ErrOverDelivery
+		}
+		lines[i].Received += received.Quantity
+	}
+	po.Lines = lines
+	po.Status = Received
+	for _, line := range lines {
+		if line.Outstanding() > 0 {
+			po.Status = Partial
+			break
+		}
+	}
+	po.UpdatedAt = grn.ReceivedAt
+	return po, nil
+}
+
+func indexOf(lines []Line, ingredient string) int {
+	for i, line := range lines {
+		if line.Ingredient == ingredient {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReceivedLine is how much of an ingredient was delivered and what a unit
+// actually cost.
+type ReceivedLine struct {
+	Ingredient string `json:"ingredient"`
+	Quantity   int64  `json:"quantity"`
+	Cost       uint64 `json:"cost"`
+}
+
+// GoodsReceived is a note of the goods delivered against a purchase order.
+type GoodsReceived struct {
+	ID            string         `json:"id,omitempty"`
+	Vendor        string         `json:"vendor,omitempty"`
+	PurchaseOrder string         `json:"purchase_order,omitempty"`
+	Reference     string         `json:"reference,omitempty"` // The supplier's delivery note.
+	Lines         []ReceivedLine `json:"lines,omitempty"`
+	ReceivedAt    time.Time      `json:"received_at,omitempty"` // When the goods were delivered.
+}
+
+// Validate returns an error if the goods received representation is
+// invalid.
+func (grn GoodsReceived) Validate() error {
+	if grn.PurchaseOrder == "" || len(grn.Lines) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	seen := make(map[string]bool)
+	for _, line := range grn.Lines {
+		if line.Ingredient == "" || line.Quantity <= 0 || seen[line.Ingredient] {
+			return errors.ErrMalformedEntity
+		}
+		seen[line.Ingredient] = true
+	}
+	return nil
+}
+
+// Suggestion is how much of an ingredient to reorder and who from.
+type Suggestion struct {
+	Ingredient string  `json:"ingredient"`
+	Name       string  `json:"name"`
+	Unit       string  `json:"unit"`
+	Stock      int64   `json:"stock"`              // How much is in stock.
+	Par        int64   `json:"par"`                // The stock level to restock up to.
+	Usage      float64 `json:"usage"`              // How much is used up a day.
+	OnOrder    int64   `json:"on_order"`           // How much is ordered but not yet delivered.
+	Quantity   int64   `json:"quantity"`           // How much to reorder.
+	Supplier   string  `json:"supplier,omitempty"` // Who delivers the ingredient.
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total         uint64
+	Offset        uint64
+	Limit         uint64
+	Vendor        string
+	Name          string
+	Supplier      string
+	Status        string
+	PurchaseOrder string
+}
+
+// SuppliersPage contains a page of suppliers.
+type SuppliersPage struct {
+	PageMetadata
+	Suppliers []Supplier
+}
+
+// PurchaseOrdersPage contains a page of purchase orders.
+type PurchaseOrdersPage struct {
+	PageMetadata
+	PurchaseOrders []PurchaseOrder
+}
+
+// GoodsReceivedPage contains a page of goods received notes.
+type GoodsReceivedPage struct {
+	PageMetadata
+	GoodsReceived []GoodsReceived
+}
+
+// Service specifies the purchasing API.
+type Service interface {
+	// CreateSupplier adds a supplier.
+	CreateSupplier(ctx context.Context, token string, supplier Supplier) (string, error)
+
+	// ViewSupplier retrieves a supplier by its unique identifier ID.
+	ViewSupplier(ctx context.Context, token, id string) (Supplier, error)
+
+	// ListSuppliers retrieves the suppliers for a given pageMetadata.
+	ListSuppliers(ctx context.Context, token string, pm PageMetadata) (SuppliersPage, error)
+
+	// UpdateSupplier updates a supplier.
+	UpdateSupplier(ctx context.Context, token string, supplier Supplier) (string, error)
+
+	// RemoveSupplier removes a supplier.
+	RemoveSupplier(ctx context.Context, token, id string) error
+
+	// CreatePurchaseOrder adds a draft purchase order.
+	CreatePurchaseOrder(ctx context.Context, token string, po PurchaseOrder) (string, error)
+
+	// ViewPurchaseOrder retrieves a purchase order by its unique identifier
+	// ID.
+	ViewPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error)
+
+	// ListPurchaseOrders retrieves the purchase orders for a given
+	// pageMetadata.
+	ListPurchaseOrders(ctx context.Context, token string, pm PageMetadata) (PurchaseOrdersPage, error)
+
+	// UpdatePurchaseOrder updates the lines, expected delivery date and
+	// notes of a draft purchase order.
+	UpdatePurchaseOrder(ctx context.Context, token string, po PurchaseOrder) (string, error)
+
+	// SendPurchaseOrder marks a draft purchase order as ordered from the
+	// supplier.
+	SendPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error)
+
+	// CancelPurchaseOrder calls off what is still to be delivered on a
+	// purchase order.
+	CancelPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error)
+
+	// ReceiveGoods records the goods delivered against a purchase order and
+	// adds them to the stock at their actual cost.
+	ReceiveGoods(ctx context.Context, token string, grn GoodsReceived) (GoodsReceived, error)
+
+	// ViewGoodsReceived retrieves a goods received note by its unique
+	// identifier ID.
+	ViewGoodsReceived(ctx context.Context, token, id string) (GoodsReceived, error)
+
+	// ListGoodsReceived retrieves the goods received notes for a given
+	// pageMetadata.
+	ListGoodsReceived(ctx context.Context, token string, pm PageMetadata) (GoodsReceivedPage, error)
+
+	// Suggestions suggests what a vendor should reorder to bring its
+	// ingredients back to their par levels, given what was used up over the
+	// last days.
+	Suggestions(ctx context.Context, token, vendor string, days uint64) ([]Suggestion, error)
+}
+
+// SupplierRepository specifies a supplier persistence API.
+type SupplierRepository interface {
+	// Save persists the supplier.
+	Save(ctx context.Context, supplier Supplier) (string, error)
+
+	// RetrieveByID retrieves a supplier by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Supplier, error)
+
+	// RetrieveAll retrieves the suppliers for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (SuppliersPage, error)
+
+	// Update updates the supplier.
+	Update(ctx context.Context, supplier Supplier) (string, error)
+
+	// Remove removes the supplier.
+	Remove(ctx context.Context, id string) error
+}
+
+// PurchaseOrderRepository specifies a purchase order persistence API.
+type PurchaseOrderRepository interface {
+	// Save persists the purchase order.
+	Save(ctx context.Context, po PurchaseOrder) (string, error)
+
+	// RetrieveByID retrieves a purchase order by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (PurchaseOrder, error)
+
+	// RetrieveAll retrieves the purchase orders for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (PurchaseOrdersPage, error)
+
+	// Update updates the status, lines, expected delivery date and notes of
+	// the purchase order.
+	Update(ctx context.Context, po PurchaseOrder) (string, error)
+
+	// Receive adds the goods received to the lines of the purchase order
+	// and saves the note, all or none. The purchase order is returned as
+	// it is after the delivery.
+	Receive(ctx context.Context, grn GoodsReceived) (PurchaseOrder, error)
+}
+
+// GoodsReceivedRepository specifies a goods received note persistence API.
+type GoodsReceivedRepository interface {
+	// RetrieveByID retrieves a goods received note by its unique identifier
+	// ID.
+	RetrieveByID(ctx context.Context, id string) (GoodsReceived, error)
+
+	// RetrieveAll retrieves the goods received notes for a given
+	// pageMetadata, newest first.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (GoodsReceivedPage, error)
+}
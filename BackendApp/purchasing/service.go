@@ -0,0 +1,341 @@
+package purchasing
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// defaultDays is how many days of usage suggestions look back on.
+	defaultDays = 7
+
+	pageSize = 100
+)
+
+var _ Service = (*purchasingService)(nil)
+
+type purchasingService struct {
+	suppliers SupplierRepository
+	orders    PurchaseOrderRepository
+	received  GoodsReceivedRepository
+	inventory inventory.Service
+}
+
+// NewService instantiates the purchasing service implementation.
+func NewService(suppliers SupplierRepository, orders PurchaseOrderRepository, received GoodsReceivedRepository, inventorySvc inventory.Service) Service {
+	return &purchasingService{
+		suppliers: suppliers,
+		orders:    orders,
+		received:  received,
+		inventory: inventorySvc,
+	}
+}
+
+func (svc purchasingService) CreateSupplier(ctx context.Context, token string, supplier Supplier) (string, error) {
+	if err := supplier.Validate(); err != nil {
+		return "", err
+	}
+	supplier.ID = ulid.Make().String()
+	supplier.CreatedAt = time.Now()
+	supplier.UpdatedAt = time.Now()
+	return svc.suppliers.Save(ctx, supplier)
+}
+
+func (svc purchasingService) ViewSupplier(ctx context.Context, token, id string) (Supplier, error) {
+	return svc.suppliers.RetrieveByID(ctx, id)
+}
+
+func (svc purchasingService) ListSuppliers(ctx context.Context, token string, pm PageMetadata) (SuppliersPage, error) {
+	return svc.suppliers.RetrieveAll(ctx, pm)
+}
+
+func (svc purchasingService) UpdateSupplier(ctx context.Context, token string, supplier Supplier) (string, error) {
+	current, err := svc.suppliers.RetrieveByID(ctx, supplier.ID)
+	if err != nil {
+		return "", err
+	}
+	supplier.Vendor = current.Vendor
+	if err := supplier.Validate(); err != nil {
+		return "", err
+	}
+	supplier.UpdatedAt = time.Now()
+	return svc.suppliers.Update(ctx, supplier)
+}
+
+func (svc purchasingService) RemoveSupplier(ctx context.Context, token, id string) error {
+	return svc.suppliers.Remove(ctx, id)
+}
+
+func (svc purchasingService) CreatePurchaseOrder(ctx context.Context, token string, po PurchaseOrder) (string, error) {
+	if err := po.Validate(); err != nil {
+		return "", err
+	}
+	if err := svc.validateLines(ctx, token, po); err != nil {
+		return "", err
+	}
+	po.ID = ulid.Make().String()
+	po.Status = Draft
+	po.CreatedAt = time.Now()
+	po.UpdatedAt = time.Now()
+	return svc.orders.Save(ctx, po)
+}
+
+func (svc purchasingService) ViewPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error) {
+	return svc.orders.RetrieveByID(ctx, id)
+}
+
+func (svc purchasingService) ListPurchaseOrders(ctx context.Context, token string, pm PageMetadata) (PurchaseOrdersPage, error) {
+	return svc.orders.RetrieveAll(ctx, pm)
+}
+
+func (svc purchasingService) UpdatePurchaseOrder(ctx context.Context, token string, po PurchaseOrder) (string, error) {
+	current, err := svc.orders.RetrieveByID(ctx, po.ID)
+	if err != nil {
+		return "", err
+	}
+	if current.Status != Draft {
+		return "", errors.ErrInvalidStatus
+	}
+	po.Vendor = current.Vendor
+	po.Supplier = current.Supplier
+	if err := po.Validate(); err != nil {
+		return "", err
+	}
+	if err := svc.validateLines(ctx, token, po); err != nil {
+		return "", err
+	}
+	po.Status = Draft
+	po.UpdatedAt = time.Now()
+	return svc.orders.Update(ctx, po)
+}
+
+func (svc purchasingService) SendPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error) {
+	po, err := svc.orders.RetrieveByID(ctx, id)
+	if err != nil {
+		return PurchaseOrder{}, err
+	}
+	if po.Status != Draft {
+		return PurchaseOrder{}, errors.ErrInvalidStatus
+	}
+	po.Status = Ordered
+	po.UpdatedAt = time.Now()
+	if _, err := svc.orders.Update(ctx, po); err != nil {
+		return PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+func (svc purchasingService) CancelPurchaseOrder(ctx context.Context, token, id string) (PurchaseOrder, error) {
+	po, err := svc.orders.RetrieveByID(ctx, id)
+	if err != nil {
+		return PurchaseOrder{}, err
+	}
+	if po.Status != Draft && !po.Open() {
+		return PurchaseOrder{}, errors.ErrInvalidStatus
+	}
+	po.Status = Cancelled
+	po.UpdatedAt = time.Now()
+	if _, err := svc.orders.Update(ctx, po); err != nil {
+		return PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+func (svc purchasingService) ReceiveGoods(ctx context.Context, token string, grn GoodsReceived) (GoodsReceived, error) {
+	if err := grn.Validate(); err != nil {
+		return GoodsReceived{}, err
+	}
+	po, err := svc.orders.RetrieveByID(ctx, grn.PurchaseOrder)
+	if err != nil {
+		return GoodsReceived{}, err
+	}
+	// Checked here as well so a delivery that cannot be received fails
+	// before anything is saved, the repository checks again under lock.
+	if _, err := po.Receive(grn); err != nil {
+		return GoodsReceived{}, err
+	}
+	// Goods delivered without a cost cost what was expected.
+	for i, line := range grn.Lines {
+		if line.Cost == 0 {
+			grn.Lines[i].Cost = po.Lines[indexOf(po.Lines, line.Ingredient)].Cost
+		}
+	}
+	grn.ID = ulid.Make().String()
+	grn.Vendor = po.Vendor
+	if grn.ReceivedAt.IsZero() {
+		grn.ReceivedAt = time.Now()
+	}
+	if _, err := svc.orders.Receive(ctx, grn); err != nil {
+		return GoodsReceived{}, err
+	}
+
+	var deliveries []inventory.Delivery
+	for _, line := range grn.Lines {
+		deliveries = append(deliveries, inventory.Delivery{
+			Ingredient: line.Ingredient,
+			Quantity:   line.Quantity,
+			Cost:       line.Cost,
+		})
+	}
+	// The note is the reference so stock is only received once for it.
+	if _, err := svc.inventory.ReceiveStock(ctx, token, grn.ID, deliveries...); err != nil {
+		return grn, err
+	}
+	return grn, nil
+}
+
+func (svc purchasingService) ViewGoodsReceived(ctx context.Context, token, id string) (GoodsReceived, error) {
+	return svc.received.RetrieveByID(ctx, id)
+}
+
+func (svc purchasingService) ListGoodsReceived(ctx context.Context, token string, pm PageMetadata) (GoodsReceivedPage, error) {
+	return svc.received.RetrieveAll(ctx, pm)
+}
+
+func (svc purchasingService) Suggestions(ctx context.Context, token, vendor string, days uint64) ([]Suggestion, error) {
+	if vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	if days == 0 {
+		days = defaultDays
+	}
+	ingredients, err := svc.ingredients(ctx, token, vendor)
+	if err != nil {
+		return nil, err
+	}
+	to := time.Now()
+	from := to.AddDate(0, 0, -int(days))
+	report, err := svc.inventory.Report(ctx, token, vendor, from, to)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]int64)
+	for _, line := range report {
+		used[line.Ingredient] = line.Sold + line.Wasted
+	}
+	onOrder, err := svc.onOrder(ctx, vendor)
+	if err != nil {
+		return nil, err
+	}
+	suppliers, err := svc.vendorSuppliers(ctx, vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	for _, ing := range ingredients {
+		usage := float64(used[ing.ID]) / float64(days)
+		// Restock up to the par level or to what will be used up over as
+		// many days again, whichever is more.
+		target := ing.Par
+		if expected := int64(math.Ceil(usage * float64(days))); expected > target {
+			target = expected
+		}
+		quantity := target - ing.Stock - onOrder[ing.ID]
+		if quantity <= 0 {
+			continue
+		}
+		suggestion := Suggestion{
+			Ingredient: ing.ID,
+			Name:       ing.Name,
+			Unit:       ing.Unit,
+			Stock:      ing.Stock,
+			Par:        ing.Par,
+			Usage:      usage,
+			OnOrder:    onOrder[ing.ID],
+			Quantity:   quantity,
+		}
+		for _, supplier := range suppliers {
+			if supplier.Supplies(ing.ID) {
+				suggestion.Supplier = supplier.ID
+				break
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// validateLines checks that the supplier and the ingredients ordered belong
+// to the vendor ordering them.
+func (svc purchasingService) validateLines(ctx context.Context, token string, po PurchaseOrder) error {
+	supplier, err := svc.suppliers.RetrieveByID(ctx, po.Supplier)
+	if err != nil {
+		return err
+	}
+	if supplier.Vendor != po.Vendor {
+		return errors.ErrMalformedEntity
+	}
+	for _, line := range po.Lines {
+		ing, err := svc.inventory.ViewIngredient(ctx, token, line.Ingredient)
+		if err != nil {
+			return err
+		}
+		if ing.Vendor != po.Vendor {
+			return errors.ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+func (svc purchasingService) ingredients(ctx context.Context, token, vendor string) ([]inventory.Ingredient, error) {
+	var ingredients []inventory.Ingredient
+	pm := inventory.PageMetadata{Vendor: vendor, Limit: pageSize}
+	for {
+		page, err := svc.inventory.ListIngredients(ctx, token, pm)
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, page.Ingredients...)
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			return ingredients, nil
+		}
+	}
+}
+
+// onOrder returns how much of each ingredient is ordered from suppliers but
+// not yet delivered.
+func (svc purchasingService) onOrder(ctx context.Context, vendor string) (map[string]int64, error) {
+	onOrder := make(map[string]int64)
+	for _, status := range []Status{Ordered, Partial} {
+		pm := PageMetadata{Vendor: vendor, Status: string(status), Limit: pageSize}
+		for {
+			page, err := svc.orders.RetrieveAll(ctx, pm)
+			if err != nil {
+				return nil, err
+			}
+			for _, po := range page.PurchaseOrders {
+				for _, line := range po.Lines {
+					onOrder[line.Ingredient] += line.Outstanding()
+				}
+			}
+			pm.Offset += pm.Limit
+			if pm.Offset >= page.Total {
+				break
+			}
+		}
+	}
+	return onOrder, nil
+}
+
+func (svc purchasingService) vendorSuppliers(ctx context.Context, vendor string) ([]Supplier, error) {
+	var suppliers []Supplier
+	pm := PageMetadata{Vendor: vendor, Limit: pageSize}
+	for {
+		page, err := svc.suppliers.RetrieveAll(ctx, pm)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, page.Suppliers...)
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			return suppliers, nil
+		}
+	}
+}
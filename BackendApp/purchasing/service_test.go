@@ -0,0 +1,334 @@
+package purchasing_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/inventory"
+	"github.com/0x6flab/jikoniApp/BackendApp/purchasing"
+)
+
+const (
+	token  = "token"
+	vendor = "vendor"
+)
+
+var (
+	flour = inventory.Ingredient{ID: "flour", Vendor: vendor, Name: "Flour", Unit: "g", Par: 20}
+	oil   = inventory.Ingredient{ID: "oil", Vendor: vendor, Name: "Oil", Unit: "ml"}
+)
+
+type supplierRepo struct {
+	purchasing.SupplierRepository
+	suppliers map[string]purchasing.Supplier
+}
+
+func (repo *supplierRepo) Save(_ context.Context, supplier purchasing.Supplier) (string, error) {
+	repo.suppliers[supplier.ID] = supplier
+	return supplier.ID, nil
+}
+
+func (repo *supplierRepo) RetrieveByID(_ context.Context, id string) (purchasing.Supplier, error) {
+	supplier, ok := repo.suppliers[id]
+	if !ok {
+		return purchasing.Supplier{}, errors.ErrNotFound
+	}
+	return supplier, nil
+}
+
+func (repo *supplierRepo) RetrieveAll(_ context.Context, pm purchasing.PageMetadata) (purchasing.SuppliersPage, error) {
+	page := purchasing.SuppliersPage{PageMetadata: pm}
+	for _, supplier := range repo.suppliers {
+		if supplier.Vendor == pm.Vendor {
+			page.Suppliers = append(page.Suppliers, supplier)
+		}
+	}
+	page.Total = uint64(len(page.Suppliers))
+	return page, nil
+}
+
+// orderRepo keeps purchase orders and their goods received notes in memory,
+// receiving deliveries under a lock as the postgres repository does.
+type orderRepo struct {
+	mu       sync.Mutex
+	orders   map[string]purchasing.PurchaseOrder
+	received []purchasing.GoodsReceived
+}
+
+func (repo *orderRepo) Save(_ context.Context, po purchasing.PurchaseOrder) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.orders[po.ID] = po
+	return po.ID, nil
+}
+
+func (repo *orderRepo) RetrieveByID(_ context.Context, id string) (purchasing.PurchaseOrder, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	po, ok := repo.orders[id]
+	if !ok {
+		return purchasing.PurchaseOrder{}, errors.ErrNotFound
+	}
+	return po, nil
+}
+
+func (repo *orderRepo) RetrieveAll(_ context.Context, pm purchasing.PageMetadata) (purchasing.PurchaseOrdersPage, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	page := purchasing.PurchaseOrdersPage{PageMetadata: pm}
+	for _, po := range repo.orders {
+		if po.Vendor == pm.Vendor && (pm.Status == "" || string(po.Status) == pm.Status) {
+			page.PurchaseOrders = append(page.PurchaseOrders, po)
+		}
+	}
+	page.Total = uint64(len(page.PurchaseOrders))
+	return page, nil
+}
+
+func (repo *orderRepo) Update(_ context.Context, po purchasing.PurchaseOrder) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, ok := repo.orders[po.ID]; !ok {
+		return "", errors.ErrNotFound
+	}
+	repo.orders[po.ID] = po
+	return po.ID, nil
+}
+
+func (repo *orderRepo) Receive(_ context.Context, grn purchasing.GoodsReceived) (purchasing.PurchaseOrder, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	current, ok := repo.orders[grn.PurchaseOrder]
+	if !ok {
+		return purchasing.PurchaseOrder{}, errors.ErrNotFound
+	}
+	po, err := current.Receive(grn)
+	if err != nil {
+		return purchasing.PurchaseOrder{}, err
+	}
+	repo.orders[po.ID] = po
+	repo.received = append(repo.received, grn)
+	return po, nil
+}
+
+type receivedRepo struct {
+	purchasing.GoodsReceivedRepository
+	orders *orderRepo
+}
+
+func (repo receivedRepo) RetrieveAll(_ context.Context, pm purchasing.PageMetadata) (purchasing.GoodsReceivedPage, error) {
+	repo.orders.mu.Lock()
+	defer repo.orders.mu.Unlock()
+	page := purchasing.GoodsReceivedPage{PageMetadata: pm}
+	for _, grn := range repo.orders.received {
+		if pm.PurchaseOrder == "" || grn.PurchaseOrder == pm.PurchaseOrder {
+			page.GoodsReceived = append(page.GoodsReceived, grn)
+		}
+	}
+	page.Total = uint64(len(page.GoodsReceived))
+	return page, nil
+}
+
+// inventoryService records the stock received by reference.
+type inventoryService struct {
+	inventory.Service
+	mu       sync.Mutex
+	received map[string][]inventory.Delivery
+}
+
+func (svc *inventoryService) ViewIngredient(_ context.Context, _, id string) (inventory.Ingredient, error) {
+	for _, ing := range []inventory.Ingredient{flour, oil} {
+		if ing.ID == id {
+			return ing, nil
+		}
+	}
+	return inventory.Ingredient{}, errors.ErrNotFound
+}
+
+func (svc *inventoryService) ListIngredients(context.Context, string, inventory.PageMetadata) (inventory.IngredientsPage, error) {
+	return inventory.IngredientsPage{PageMetadata: inventory.PageMetadata{Total: 2}, Ingredients: []inventory.Ingredient{flour, oil}}, nil
+}
+
+func (svc *inventoryService) Report(context.Context, string, string, time.Time, time.Time) ([]inventory.ReportLine, error) {
+	return nil, nil
+}
+
+func (svc *inventoryService) ReceiveStock(_ context.Context, _, reference string, deliveries ...inventory.Delivery) ([]inventory.Movement, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if _, ok := svc.received[reference]; ok {
+		return nil, errors.ErrConflict
+	}
+	svc.received[reference] = deliveries
+	return nil, nil
+}
+
+func newService() (purchasing.Service, *inventoryService) {
+	orders := &orderRepo{orders: map[string]purchasing.PurchaseOrder{}}
+	inv := &inventoryService{received: map[string][]inventory.Delivery{}}
+	svc := purchasing.NewService(&supplierRepo{suppliers: map[string]purchasing.Supplier{}}, orders, receivedRepo{orders: orders}, inv)
+	return svc, inv
+}
+
+// order creates and sends a purchase order of 10 g of flour at 100 and 5 ml
+// of oil at 300.
+func order(t *testing.T, svc purchasing.Service) string {
+	ctx := context.Background()
+	supplier, err := svc.CreateSupplier(ctx, token, purchasing.Supplier{Vendor: vendor, Name: "Unga Ltd", Ingredients: []string{flour.ID, oil.ID}})
+	if err != nil {
+		t.Fatalf("creating a supplier: unexpected error: %s", err)
+	}
+	id, err := svc.CreatePurchaseOrder(ctx, token, purchasing.PurchaseOrder{
+		Vendor:   vendor,
+		Supplier: supplier,
+		Lines: []purchasing.Line{
+			{Ingredient: flour.ID, Quantity: 10, Cost: 100},
+			{Ingredient: oil.ID, Quantity: 5, Cost: 300},
+		},
+	})
+	if err != nil {
+		t.Fatalf("creating a purchase order: unexpected error: %s", err)
+	}
+	if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 1}}}); !errors.Contains(err, errors.ErrInvalidStatus) {
+		t.Errorf("receiving goods against a draft: got error %v, want %s", err, errors.ErrInvalidStatus)
+	}
+	if _, err := svc.SendPurchaseOrder(ctx, token, id); err != nil {
+		t.Fatalf("sending a purchase order: unexpected error: %s", err)
+	}
+	return id
+}
+
+func TestReceiveGoods(t *testing.T) {
+	svc, inv := newService()
+	ctx := context.Background()
+	id := order(t, svc)
+
+	// The first delivery brings part of the flour and all of the oil, the
+	// flour costs what was expected and the oil more.
+	first, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{
+		PurchaseOrder: id,
+		Reference:     "DN-1",
+		Lines: []purchasing.ReceivedLine{
+			{Ingredient: flour.ID, Quantity: 4},
+			{Ingredient: oil.ID, Quantity: 5, Cost: 320},
+		},
+	})
+	if err != nil {
+		t.Fatalf("receiving a partial delivery: unexpected error: %s", err)
+	}
+	po, err := svc.ViewPurchaseOrder(ctx, token, id)
+	if err != nil {
+		t.Fatalf("viewing the purchase order: unexpected error: %s", err)
+	}
+	if po.Status != purchasing.Partial || po.Lines[0].Outstanding() != 6 || po.Lines[1].Outstanding() != 0 {
+		t.Errorf("receiving a partial delivery: got %s with %d g of flour and %d ml of oil outstanding, want %s with 6 and 0", po.Status, po.Lines[0].Outstanding(), po.Lines[1].Outstanding(), purchasing.Partial)
+	}
+	want := []inventory.Delivery{{Ingredient: flour.ID, Quantity: 4, Cost: 100}, {Ingredient: oil.ID, Quantity: 5, Cost: 320}}
+	got := inv.received[first.ID]
+	if len(got) != len(want) {
+		t.Fatalf("receiving a partial delivery: got %d deliveries into stock, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("receiving a partial delivery: got %+v into stock, want %+v", got[i], want[i])
+		}
+	}
+
+	// What is still to come counts as on order.
+	suggestions, err := svc.Suggestions(ctx, token, vendor, 0)
+	if err != nil {
+		t.Fatalf("suggesting reorders: unexpected error: %s", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].OnOrder != 6 || suggestions[0].Quantity != 14 {
+		t.Errorf("suggesting reorders: got %+v, want 14 g of flour with 6 on order", suggestions)
+	}
+
+	cases := []struct {
+		desc  string
+		lines []purchasing.ReceivedLine
+		err   error
+	}{
+		{desc: "receive more than is outstanding", lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 7}}, err: purchasing.ErrOverDelivery},
+		{desc: "receive a line that was delivered", lines: []purchasing.ReceivedLine{{Ingredient: oil.ID, Quantity: 1}}, err: purchasing.ErrOverDelivery},
+		{desc: "receive an ingredient not ordered", lines: []purchasing.ReceivedLine{{Ingredient: "sugar", Quantity: 1}}, err: errors.ErrMalformedEntity},
+		{desc: "receive nothing", err: errors.ErrMalformedEntity},
+	}
+	for _, tc := range cases {
+		if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: tc.lines}); !errors.Contains(err, tc.err) {
+			t.Errorf("%s: got error %v, want %s", tc.desc, err, tc.err)
+		}
+	}
+	if len(inv.received) != 1 {
+		t.Errorf("receiving failed deliveries: got %d deliveries into stock, want 1", len(inv.received))
+	}
+
+	if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Reference: "DN-2", Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 6, Cost: 90}}}); err != nil {
+		t.Fatalf("receiving the rest: unexpected error: %s", err)
+	}
+	if po, err = svc.ViewPurchaseOrder(ctx, token, id); err != nil || po.Status != purchasing.Received {
+		t.Errorf("receiving the rest: got status %s and error %v, want %s", po.Status, err, purchasing.Received)
+	}
+	if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 1}}}); !errors.Contains(err, errors.ErrInvalidStatus) {
+		t.Errorf("receiving against a received purchase order: got error %v, want %s", err, errors.ErrInvalidStatus)
+	}
+	page, err := svc.ListGoodsReceived(ctx, token, purchasing.PageMetadata{PurchaseOrder: id, Limit: 10})
+	if err != nil || len(page.GoodsReceived) != 2 {
+		t.Errorf("listing goods received: got %d notes and error %v, want 2", len(page.GoodsReceived), err)
+	}
+}
+
+func TestReceiveGoodsConcurrently(t *testing.T) {
+	svc, inv := newService()
+	ctx := context.Background()
+	id := order(t, svc)
+
+	// Two deliveries of the whole flour received at the same time, only
+	// one of them fits.
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 10}}})
+		}(i)
+	}
+	wg.Wait()
+	var received, over int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			received++
+		case errors.Contains(err, purchasing.ErrOverDelivery):
+			over++
+		default:
+			t.Errorf("receiving concurrently: unexpected error: %s", err)
+		}
+	}
+	if received != 1 || over != 1 || len(inv.received) != 1 {
+		t.Errorf("receiving concurrently: got %d received, %d over delivered and %d into stock, want 1 of each", received, over, len(inv.received))
+	}
+}
+
+func TestCancelPartial(t *testing.T) {
+	svc, _ := newService()
+	ctx := context.Background()
+	id := order(t, svc)
+
+	if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 4}}}); err != nil {
+		t.Fatalf("receiving a partial delivery: unexpected error: %s", err)
+	}
+	po, err := svc.CancelPurchaseOrder(ctx, token, id)
+	if err != nil {
+		t.Fatalf("cancelling a partly delivered purchase order: unexpected error: %s", err)
+	}
+	if po.Status != purchasing.Cancelled || po.Lines[0].Received != 4 {
+		t.Errorf("cancelling a partly delivered purchase order: got %s with %d g of flour received, want %s with 4", po.Status, po.Lines[0].Received, purchasing.Cancelled)
+	}
+	if _, err := svc.ReceiveGoods(ctx, token, purchasing.GoodsReceived{PurchaseOrder: id, Lines: []purchasing.ReceivedLine{{Ingredient: flour.ID, Quantity: 6}}}); !errors.Contains(err, errors.ErrInvalidStatus) {
+		t.Errorf("receiving against a cancelled purchase order: got error %v, want %s", err, errors.ErrInvalidStatus)
+	}
+}
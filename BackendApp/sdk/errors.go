@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+// Errors the service responds with, for services outside of this module
+// to tell them apart with errors.Is.
+var (
+	ErrMalformedEntity        = errors.ErrMalformedEntity
+	ErrInvalidStatus          = errors.ErrInvalidStatus
+	ErrInvalidPlace           = errors.ErrInvalidPlace
+	ErrInvalidQueryParams     = errors.ErrInvalidQueryParams
+	ErrLimitSize              = errors.ErrLimitSize
+	ErrOffsetSize             = errors.ErrOffsetSize
+	ErrBearerToken            = errors.ErrBearerToken
+	ErrAuthentication         = errors.ErrAuthentication
+	ErrAuthorization          = errors.ErrAuthorization
+	ErrNotFound               = errors.ErrNotFound
+	ErrConflict               = errors.ErrConflict
+	ErrUnsupportedContentType = errors.ErrUnsupportedContentType
+	ErrCreateEntity           = errors.ErrCreateEntity
+	ErrViewEntity             = errors.ErrViewEntity
+	ErrUpdateEntity           = errors.ErrUpdateEntity
+	ErrRemoveEntity           = errors.ErrRemoveEntity
+)
+
+// serviceErrors are the errors above by the message the service responds
+// with.
+var serviceErrors = map[string]errors.Error{}
+
+func init() {
+	for _, err := range []errors.Error{
+		ErrMalformedEntity,
+		ErrInvalidStatus,
+		ErrInvalidPlace,
+		ErrInvalidQueryParams,
+		ErrLimitSize,
+		ErrOffsetSize,
+		ErrBearerToken,
+		ErrAuthentication,
+		ErrAuthorization,
+		ErrNotFound,
+		ErrConflict,
+		ErrUnsupportedContentType,
+		ErrCreateEntity,
+		ErrViewEntity,
+		ErrUpdateEntity,
+		ErrRemoveEntity,
+	} {
+		serviceErrors[err.Msg()] = err
+	}
+}
+
+var _ errors.Error = (*Error)(nil)
+
+// Error is an error the service responded with. It wraps the error of the
+// message the service sent, or the error of the status code of the
+// response when the message is not one of the errors above.
+type Error struct {
+	StatusCode int
+	msg        string
+	err        errors.Error
+}
+
+func (e *Error) Error() string {
+	if e.err == nil || e.err.Msg() == e.msg {
+		return e.msg
+	}
+	return e.msg + " : " + e.err.Error()
+}
+
+// Msg returns the message the service responded with.
+func (e *Error) Msg() string {
+	return e.msg
+}
+
+// Err returns the error the service responded with, if known.
+func (e *Error) Err() errors.Error {
+	return e.err
+}
+
+// Is reports whether the service responded with target.
+func (e *Error) Is(target error) bool {
+	return errors.Contains(e, target)
+}
+
+// decodeError returns the error of a response failed by the service.
+func decodeError(res *http.Response) error {
+	e := &Error{
+		StatusCode: res.StatusCode,
+		msg:        res.Status,
+	}
+	var er apiutil.ErrorRes
+	if data, err := io.ReadAll(res.Body); err == nil && json.Unmarshal(data, &er) == nil && er.Err != "" {
+		e.msg = er.Err
+		e.err = serviceErrors[er.Err]
+	}
+	if e.err == nil {
+		e.err = statusError(res.StatusCode)
+	}
+	return e
+}
+
+func statusError(code int) errors.Error {
+	switch code {
+	case http.StatusBadRequest:
+		return errors.ErrMalformedEntity
+	case http.StatusUnauthorized:
+		return errors.ErrAuthentication
+	case http.StatusNotFound:
+		return errors.ErrNotFound
+	case http.StatusConflict:
+		return errors.ErrConflict
+	case http.StatusUnsupportedMediaType:
+		return errors.ErrUnsupportedContentType
+	}
+	return nil
+}
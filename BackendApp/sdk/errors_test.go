@@ -0,0 +1,68 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/sdk"
+)
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		msg    string
+		err    errors.Error
+	}{
+		{desc: "malformed entity", status: http.StatusBadRequest, msg: sdk.ErrMalformedEntity.Msg(), err: sdk.ErrMalformedEntity},
+		{desc: "invalid status", status: http.StatusBadRequest, msg: sdk.ErrInvalidStatus.Msg(), err: sdk.ErrInvalidStatus},
+		{desc: "invalid place", status: http.StatusBadRequest, msg: sdk.ErrInvalidPlace.Msg(), err: sdk.ErrInvalidPlace},
+		{desc: "invalid query params", status: http.StatusBadRequest, msg: sdk.ErrInvalidQueryParams.Msg(), err: sdk.ErrInvalidQueryParams},
+		{desc: "limit size", status: http.StatusBadRequest, msg: sdk.ErrLimitSize.Msg(), err: sdk.ErrLimitSize},
+		{desc: "offset size", status: http.StatusBadRequest, msg: sdk.ErrOffsetSize.Msg(), err: sdk.ErrOffsetSize},
+		{desc: "bearer token", status: http.StatusUnauthorized, msg: sdk.ErrBearerToken.Msg(), err: sdk.ErrBearerToken},
+		{desc: "authentication", status: http.StatusUnauthorized, msg: sdk.ErrAuthentication.Msg(), err: sdk.ErrAuthentication},
+		{desc: "authorization", status: http.StatusForbidden, msg: sdk.ErrAuthorization.Msg(), err: sdk.ErrAuthorization},
+		{desc: "not found", status: http.StatusNotFound, msg: sdk.ErrNotFound.Msg(), err: sdk.ErrNotFound},
+		{desc: "conflict", status: http.StatusConflict, msg: sdk.ErrConflict.Msg(), err: sdk.ErrConflict},
+		{desc: "unsupported content type", status: http.StatusUnsupportedMediaType, msg: sdk.ErrUnsupportedContentType.Msg(), err: sdk.ErrUnsupportedContentType},
+		{desc: "create entity", status: http.StatusInternalServerError, msg: sdk.ErrCreateEntity.Msg(), err: sdk.ErrCreateEntity},
+		{desc: "view entity", status: http.StatusInternalServerError, msg: sdk.ErrViewEntity.Msg(), err: sdk.ErrViewEntity},
+		{desc: "update entity", status: http.StatusInternalServerError, msg: sdk.ErrUpdateEntity.Msg(), err: sdk.ErrUpdateEntity},
+		{desc: "remove entity", status: http.StatusInternalServerError, msg: sdk.ErrRemoveEntity.Msg(), err: sdk.ErrRemoveEntity},
+		{desc: "unknown message", status: http.StatusNotFound, msg: "no such table", err: sdk.ErrNotFound},
+		{desc: "no message", status: http.StatusConflict, err: sdk.ErrConflict},
+		{desc: "unknown status", status: http.StatusTeapot},
+	}
+	for _, tc := range cases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			if tc.msg != "" {
+				json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: tc.msg})
+			}
+		}))
+		svc := sdk.NewSDK(sdk.Config{URL: ts.URL, Retries: -1})
+		_, err := svc.ViewOrder(context.Background(), "id")
+		ts.Close()
+
+		e, ok := err.(*sdk.Error)
+		if !ok {
+			t.Errorf("%s: got %v, want an *sdk.Error", tc.desc, err)
+			continue
+		}
+		if e.StatusCode != tc.status {
+			t.Errorf("%s: got status %d, want %d", tc.desc, e.StatusCode, tc.status)
+		}
+		if e.Err() != tc.err {
+			t.Errorf("%s: got error %v, want %v", tc.desc, e.Err(), tc.err)
+		}
+		if tc.err != nil && !errors.Contains(err, tc.err) {
+			t.Errorf("%s: %v does not contain %v", tc.desc, err, tc.err)
+		}
+	}
+}
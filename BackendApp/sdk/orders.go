@@ -0,0 +1,306 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/oklog/ulid/v2"
+)
+
+const ordersEndpoint = "/orders"
+
+type orderReq struct {
+	Vendor   string          `json:"vendor,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Price    uint64          `json:"price,omitempty"`
+	Place    string          `json:"place,omitempty"`
+	Status   string          `json:"status,omitempty"`
+	Items    []orders.Item   `json:"items,omitempty"`
+	Metadata orders.Metadata `json:"metadata,omitempty"`
+}
+
+type ordersPageRes struct {
+	Total  uint64         `json:"total"`
+	Offset uint64         `json:"offset"`
+	Limit  uint64         `json:"limit"`
+	Orders []orders.Order `json:"orders"`
+}
+
+// CreateOrder creates the order under the idempotency key in ctx, set with
+// orders.WithIdempotencyKey, or a key of its own. Retries reuse the key so
+// the order is created once; callers retrying themselves set the key.
+func (s sdk) CreateOrder(ctx context.Context, order orders.Order) (string, error) {
+	key := orders.IdempotencyKey(ctx)
+	if key == "" {
+		key = ulid.Make().String()
+	}
+	header := s.header(ctx)
+	header.Set(idempotencyKeyHeader, key)
+	req := request{
+		method:     http.MethodPost,
+		path:       ordersEndpoint,
+		body:       toOrderReq(order),
+		header:     header,
+		idempotent: true,
+	}
+	res, err := s.do(ctx, req, nil)
+	if err != nil {
+		return "", err
+	}
+	return idFromLocation(res)
+}
+
+func (s sdk) ViewOrder(ctx context.Context, id string) (orders.Order, error) {
+	if id == "" {
+		return orders.Order{}, errors.ErrMissingID
+	}
+	req := request{
+		method:     http.MethodGet,
+		path:       path.Join(ordersEndpoint, url.PathEscape(id)),
+		header:     s.header(ctx),
+		idempotent: true,
+	}
+	var order orders.Order
+	if _, err := s.do(ctx, req, &order); err != nil {
+		return orders.Order{}, err
+	}
+	return order, nil
+}
+
+func (s sdk) ListOrders(ctx context.Context, pm orders.PageMetadata) (orders.OrdersPage, error) {
+	q, err := listQuery(pm)
+	if err != nil {
+		return orders.OrdersPage{}, err
+	}
+	req := request{
+		method:     http.MethodGet,
+		path:       ordersEndpoint,
+		query:      q.Encode(),
+		header:     s.header(ctx),
+		idempotent: true,
+	}
+	var page ordersPageRes
+	if _, err := s.do(ctx, req, &page); err != nil {
+		return orders.OrdersPage{}, err
+	}
+	pm.Total, pm.Offset, pm.Limit = page.Total, page.Offset, page.Limit
+	return orders.OrdersPage{
+		PageMetadata: pm,
+		Orders:       page.Orders,
+	}, nil
+}
+
+func (s sdk) UpdateOrder(ctx context.Context, order orders.Order) (string, error) {
+	if order.ID == "" {
+		return "", errors.ErrMissingID
+	}
+	req := request{
+		method:     http.MethodPut,
+		path:       path.Join(ordersEndpoint, url.PathEscape(order.ID)),
+		body:       toOrderReq(order),
+		header:     s.header(ctx),
+		idempotent: true,
+	}
+	if _, err := s.do(ctx, req, nil); err != nil {
+		return "", err
+	}
+	return order.ID, nil
+}
+
+func (s sdk) DeleteOrder(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.ErrMissingID
+	}
+	req := request{
+		method:     http.MethodDelete,
+		path:       path.Join(ordersEndpoint, url.PathEscape(id)),
+		header:     s.header(ctx),
+		idempotent: true,
+	}
+	_, err := s.do(ctx, req, nil)
+	return err
+}
+
+func (s sdk) Orders(ctx context.Context, pm orders.PageMetadata) *OrdersIterator {
+	if pm.Limit == 0 {
+		pm.Limit = MaxLimit
+	}
+	return &OrdersIterator{
+		sdk: s,
+		ctx: ctx,
+		pm:  pm,
+	}
+}
+
+func (s sdk) ExportOrders(ctx context.Context, query export.Query, w io.Writer) error {
+	q, err := listQuery(query.PageMetadata)
+	if err != nil {
+		return err
+	}
+	q.Del("offset")
+	q.Del("limit")
+	if query.Format != "" {
+		q.Set("format", string(query.Format))
+	}
+	if len(query.Fields) > 0 {
+		q.Set("fields", strings.Join(query.Fields, ","))
+	}
+	if query.Location != nil {
+		q.Set("tz", query.Location.String())
+	}
+	req := request{
+		method: http.MethodGet,
+		path:   path.Join(ordersEndpoint, "export"),
+		query:  q.Encode(),
+		header: s.header(ctx),
+		// Nothing is written to w before the service answers, so a failed
+		// request can still be made again.
+		idempotent: true,
+	}
+	_, err = s.do(ctx, req, w)
+	return err
+}
+
+// header returns the headers of a request made with ctx: the PIN of the
+// staff member acting, set with staff.WithPIN, and the member, set with
+// staff.WithMemberID, if any.
+func (s sdk) header(ctx context.Context) http.Header {
+	header := http.Header{}
+	if pin := staff.PIN(ctx); pin != "" {
+		header.Set(pinHeader, pin)
+	}
+	if id := staff.MemberID(ctx); id != "" {
+		header.Set(staffIDHeader, id)
+	}
+	return header
+}
+
+func toOrderReq(order orders.Order) orderReq {
+	return orderReq{
+		Vendor:   order.Vendor,
+		Name:     order.Name,
+		Price:    order.Price,
+		Place:    order.Place,
+		Status:   order.Status,
+		Items:    order.Items,
+		Metadata: order.Metadata,
+	}
+}
+
+// listQuery returns the query of the filters of the page metadata, leaving
+// out those not set.
+func listQuery(pm orders.PageMetadata) (url.Values, error) {
+	q := url.Values{}
+	if pm.Offset > 0 {
+		q.Set("offset", strconv.FormatUint(pm.Offset, 10))
+	}
+	if pm.Limit > 0 {
+		q.Set("limit", strconv.FormatUint(pm.Limit, 10))
+	}
+	if pm.Vendor != "" {
+		q.Set("vendor", pm.Vendor)
+	}
+	if pm.Name != "" {
+		q.Set("name", pm.Name)
+	}
+	if pm.Price > 0 {
+		q.Set("price", strconv.FormatUint(pm.Price, 10))
+	}
+	if pm.Place != "" {
+		q.Set("place", pm.Place)
+	}
+	if pm.Status != "" {
+		q.Set("status", pm.Status)
+	}
+	if pm.Outstanding {
+		q.Set("outstanding", "true")
+	}
+	if !pm.From.IsZero() {
+		q.Set("from", pm.From.Format(time.RFC3339))
+	}
+	if !pm.To.IsZero() {
+		q.Set("to", pm.To.Format(time.RFC3339))
+	}
+	if len(pm.Metadata) > 0 {
+		metadata, err := json.Marshal(pm.Metadata)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		q.Set("metadata", string(metadata))
+	}
+	return q, nil
+}
+
+// idFromLocation returns the unique identifier of the order the response
+// locates.
+func idFromLocation(res *http.Response) (string, error) {
+	location := res.Header.Get("Location")
+	id := path.Base(location)
+	if location == "" || id == path.Base(ordersEndpoint) {
+		return "", fmt.Errorf("response to %s %s locates no order", res.Request.Method, res.Request.URL.Path)
+	}
+	return url.PathUnescape(id)
+}
+
+// OrdersIterator iterates over the orders of all the pages of a listing,
+// fetching a page when the one before has been gone through:
+//
+//	it := sdk.Orders(ctx, orders.PageMetadata{Vendor: "jikoni"})
+//	for it.Next() {
+//		order := it.Order()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type OrdersIterator struct {
+	sdk   sdk
+	ctx   context.Context
+	pm    orders.PageMetadata
+	page  []orders.Order
+	order orders.Order
+	done  bool
+	err   error
+}
+
+// Next advances to the next order, fetching the next page when needed. It
+// returns false once there are no orders left or a page failed.
+func (it *OrdersIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		page, err := it.sdk.ListOrders(it.ctx, it.pm)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page.Orders
+		it.pm.Offset += uint64(len(page.Orders))
+		it.done = len(page.Orders) == 0 || it.pm.Offset >= page.Total
+	}
+	it.order, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Order returns the order Next advanced to.
+func (it *OrdersIterator) Order() orders.Order {
+	return it.order
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *OrdersIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,64 @@
+package sdk_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/api"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/sdk"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+func TestListOrdersMetadata(t *testing.T) {
+	r := mux.NewRouter()
+	api.MakeOrdersHandler(orders.NewOrderService(memory.NewOrderRepo()), r, kitlog.NewNopLogger())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+	svc := sdk.NewSDK(sdk.Config{URL: ts.URL, Token: "token", Retries: -1})
+
+	ctx := context.Background()
+	create := func(name string, metadata orders.Metadata) string {
+		order := orders.Order{Vendor: "vendor", Name: name, Price: 100, Place: "inhouse", Status: orders.StatusOrdered, Metadata: metadata}
+		id, err := svc.CreateOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("creating %s: unexpected error: %s", name, err)
+		}
+		return id
+	}
+	ussd := create("Chapati", orders.Metadata{"channel": "ussd", "customer": "+254700000001"})
+	create("Chai", orders.Metadata{"channel": "whatsapp", "customer": "+254700000001"})
+	create("Mandazi", nil)
+
+	cases := []struct {
+		desc     string
+		metadata orders.Metadata
+		ids      []string
+		total    uint64
+	}{
+		{desc: "no filter", total: 3},
+		{desc: "matching one order", metadata: orders.Metadata{"channel": "ussd"}, ids: []string{ussd}, total: 1},
+		{desc: "matching every key", metadata: orders.Metadata{"channel": "ussd", "customer": "+254700000001"}, ids: []string{ussd}, total: 1},
+		{desc: "matching several orders", metadata: orders.Metadata{"customer": "+254700000001"}, total: 2},
+		{desc: "matching no order", metadata: orders.Metadata{"channel": "telegram"}, total: 0},
+	}
+	for _, tc := range cases {
+		page, err := svc.ListOrders(ctx, orders.PageMetadata{Limit: 10, Metadata: tc.metadata})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.desc, err)
+			continue
+		}
+		if page.Total != tc.total || uint64(len(page.Orders)) != tc.total {
+			t.Errorf("%s: got %d orders of %d, want %d", tc.desc, len(page.Orders), page.Total, tc.total)
+			continue
+		}
+		for i, id := range tc.ids {
+			if page.Orders[i].ID != id {
+				t.Errorf("%s: got order %s, want %s", tc.desc, page.Orders[i].ID, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package sdk is the Go client of the orders API. Calls carry the bearer
+// token of the SDK, or the one in their context, and are retried with
+// backoff when the network or the service fails. Orders are only created
+// again on retry under the same idempotency key, so a retried create
+// never creates the order twice.
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/export"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is retried when the
+	// configuration does not say.
+	DefaultRetries = 3
+
+	// DefaultRetryWait is how long the first retry waits when the
+	// configuration does not say.
+	DefaultRetryWait = 200 * time.Millisecond
+
+	// DefaultMaxRetryWait is how long a retry waits at most when the
+	// configuration does not say.
+	DefaultMaxRetryWait = 5 * time.Second
+
+	// MaxLimit is the size of the largest page the service lists.
+	MaxLimit = 100
+
+	contentType          = "application/json"
+	healthEndpoint       = "/health"
+	idempotencyKeyHeader = "Idempotency-Key"
+	pinHeader            = "X-Staff-PIN"
+	staffIDHeader        = "X-Staff-ID"
+)
+
+// Config is the configuration of the SDK.
+type Config struct {
+	URL   string // The address of the orders service i.e. http://localhost:9191.
+	Token string // The bearer token calls are made with.
+
+	// Timeout is how long a call may take, retries included. Zero leaves
+	// the timeout to the context of the call.
+	Timeout time.Duration
+
+	// Retries is how many times a request failed by the network or the
+	// service is retried. Negative disables retries.
+	Retries int
+
+	// RetryWait is how long the first retry waits, every next one waits
+	// twice as long up to MaxRetryWait.
+	RetryWait    time.Duration
+	MaxRetryWait time.Duration
+
+	// Client makes the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// SDK is the orders API, mirroring orders.OrderService.
+type SDK interface {
+	// CreateOrder creates the order and returns its unique identifier.
+	// Adjustments and taxes are worked out by the service.
+	CreateOrder(ctx context.Context, order orders.Order) (string, error)
+
+	// ViewOrder retrieves the order by its unique identifier id.
+	ViewOrder(ctx context.Context, id string) (orders.Order, error)
+
+	// ListOrders retrieves the page of the orders matching the filters of
+	// the page metadata.
+	ListOrders(ctx context.Context, pm orders.PageMetadata) (orders.OrdersPage, error)
+
+	// UpdateOrder updates the name, price, place, status, items and
+	// metadata of the order with the unique identifier of order.
+	UpdateOrder(ctx context.Context, order orders.Order) (string, error)
+
+	// DeleteOrder deletes the order by its unique identifier id.
+	DeleteOrder(ctx context.Context, id string) error
+
+	// Orders iterates over all the orders matching the filters of the page
+	// metadata, a page at a time from its offset.
+	Orders(ctx context.Context, pm orders.PageMetadata) *OrdersIterator
+
+	// ExportOrders writes the file of all the orders matching the query to
+	// w as the service streams it.
+	ExportOrders(ctx context.Context, query export.Query, w io.Writer) error
+
+	// Health returns an error if the service is not up.
+	Health(ctx context.Context) error
+}
+
+type tokenKey struct{}
+
+// WithToken returns a copy of ctx carrying the bearer token of the calls
+// made with it, in place of the token of the SDK.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+var _ SDK = (*sdk)(nil)
+
+type sdk struct {
+	config Config
+	client *http.Client
+}
+
+// NewSDK returns the SDK of the orders service at the URL of the config.
+func NewSDK(config Config) SDK {
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	if config.Retries == 0 {
+		config.Retries = DefaultRetries
+	}
+	if config.RetryWait == 0 {
+		config.RetryWait = DefaultRetryWait
+	}
+	if config.MaxRetryWait == 0 {
+		config.MaxRetryWait = DefaultMaxRetryWait
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &sdk{
+		config: config,
+		client: client,
+	}
+}
+
+func (s sdk) Health(ctx context.Context) error {
+	req := request{
+		method:     http.MethodGet,
+		path:       healthEndpoint,
+		idempotent: true,
+	}
+	_, err := s.do(ctx, req, nil)
+	return err
+}
+
+// request is a request to the service, kept so it can be made again.
+type request struct {
+	method string
+	path   string
+	query  string
+	body   interface{}
+	header http.Header
+
+	// idempotent tells whether the request can be retried. Retrying a
+	// request that is not could do what it does twice.
+	idempotent bool
+}
+
+// do makes the request, retrying it while the network or the service
+// fails, and decodes the response body into out unless it is nil. The body
+// is copied as it is when out is an io.Writer.
+func (s sdk) do(ctx context.Context, req request, out interface{}) (*http.Response, error) {
+	if s.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+	}
+	var body []byte
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := s.send(ctx, req, body)
+		if err == nil && res.StatusCode < http.StatusInternalServerError {
+			defer res.Body.Close()
+			if res.StatusCode >= http.StatusBadRequest {
+				return res, decodeError(res)
+			}
+			switch out := out.(type) {
+			case nil:
+			case io.Writer:
+				if _, err := io.Copy(out, res.Body); err != nil {
+					return res, err
+				}
+			default:
+				if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+					return res, err
+				}
+			}
+			return res, nil
+		}
+		if err == nil {
+			err = decodeError(res)
+			res.Body.Close()
+		}
+		if !req.idempotent || attempt >= s.config.Retries || !retryable(ctx, err) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(s.backoff(attempt)):
+		}
+	}
+}
+
+func (s sdk) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	url := s.config.URL + req.path
+	if req.query != "" {
+		url += "?" + req.query
+	}
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	hr, err := http.NewRequestWithContext(ctx, req.method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.header {
+		hr.Header[k] = v
+	}
+	if body != nil {
+		hr.Header.Set("Content-Type", contentType)
+	}
+	token := s.config.Token
+	if t, ok := ctx.Value(tokenKey{}).(string); ok {
+		token = t
+	}
+	if token != "" {
+		hr.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.client.Do(hr)
+}
+
+// backoff returns how long to wait before the retry following attempt:
+// twice as long as the one before, up to MaxRetryWait, with jitter so
+// clients failed together do not retry together.
+func (s sdk) backoff(attempt int) time.Duration {
+	wait := s.config.RetryWait << attempt
+	if wait <= 0 || wait > s.config.MaxRetryWait {
+		wait = s.config.MaxRetryWait
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// retryable reports whether the request failed by the network or the
+// service, rather than because the call was cancelled or timed out.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	// The client fails requests the network failed with url.Error, a
+	// net.Error.
+	_, ok := err.(net.Error)
+	return ok
+}
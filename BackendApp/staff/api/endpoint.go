@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/go-kit/kit/endpoint"
+)
+
+const (
+	membersPath = "staff"
+	shiftsPath  = "staff/shifts"
+)
+
+func createMemberEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createMemberReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateMember(ctx, req.token, req.member)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{path: membersPath, ID: id}, nil
+	}
+}
+
+func viewMemberEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		member, err := svc.ViewMember(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewMemberRes{Member: member}, nil
+	}
+}
+
+func listMembersEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := staff.PageMetadata{
+			Offset:     req.offset,
+			Limit:      req.limit,
+			Vendor:     req.vendor,
+			Role:       req.role,
+			OnlyActive: req.onlyActive,
+		}
+		page, err := svc.ListMembers(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := membersPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Members: []staff.Member{},
+		}
+		res.Members = append(res.Members, page.Members...)
+		return res, nil
+	}
+}
+
+func updateMemberEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateMemberReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateMember(ctx, req.token, req.member)
+		if err != nil {
+			return nil, err
+		}
+		return updateRes{path: membersPath, ID: id}, nil
+	}
+}
+
+func removeMemberEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveMember(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func createShiftEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createShiftReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateShift(ctx, req.token, req.shift)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{path: shiftsPath, ID: id}, nil
+	}
+}
+
+func viewShiftEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		shift, err := svc.ViewShift(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewShiftRes{Shift: shift}, nil
+	}
+}
+
+func listShiftsEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := staff.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Member: req.member,
+			From:   req.from,
+			To:     req.to,
+		}
+		page, err := svc.ListShifts(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := shiftsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Shifts: []staff.Shift{},
+		}
+		res.Shifts = append(res.Shifts, page.Shifts...)
+		return res, nil
+	}
+}
+
+func updateShiftEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateShiftReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateShift(ctx, req.token, req.shift)
+		if err != nil {
+			return nil, err
+		}
+		return updateRes{path: shiftsPath, ID: id}, nil
+	}
+}
+
+func removeShiftEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveShift(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func clockEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(clockReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		tc, err := svc.Clock(ctx, req.token, req.Vendor, req.Member, req.PIN, req.Action)
+		if err != nil {
+			return nil, err
+		}
+		return timecardRes{Timecard: tc}, nil
+	}
+}
+
+func listTimecardsEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := staff.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Member: req.member,
+			From:   req.from,
+			To:     req.to,
+		}
+		page, err := svc.ListTimecards(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := timecardsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Timecards: []staff.Timecard{},
+		}
+		res.Timecards = append(res.Timecards, page.Timecards...)
+		return res, nil
+	}
+}
+
+func reportEndpoint(svc staff.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(reportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if req.to.IsZero() {
+			req.to = time.Now()
+		}
+		lines, err := svc.Report(ctx, req.token, req.vendor, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+		res := reportRes{
+			Vendor: req.vendor,
+			To:     req.to.Format(time.RFC3339),
+			Lines:  lines,
+		}
+		if !req.from.IsZero() {
+			res.From = req.from.Format(time.RFC3339)
+		}
+		if res.Lines == nil {
+			res.Lines = []staff.ReportLine{}
+		}
+		return res, nil
+	}
+}
@@ -0,0 +1,279 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/go-kit/log"
+)
+
+var _ staff.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    staff.Service
+}
+
+// LoggingMiddleware adds logging facilities to the staff service.
+func LoggingMiddleware(svc staff.Service, logger log.Logger) staff.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "attribute_accepted",
+			"token", token,
+			"order", order.ID,
+			"member", order.AcceptedBy,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPreparing(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "attribute_paid",
+			"token", token,
+			"order", order.ID,
+			"member", order.PaidBy,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPaid(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) CreateMember(ctx context.Context, token string, member staff.Member) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_member",
+			"token", token,
+			"vendor", member.Vendor,
+			"name", member.Name,
+			"role", member.Role,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateMember(ctx, token, member)
+}
+
+func (lm *loggingMiddleware) ViewMember(ctx context.Context, token, id string) (member staff.Member, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_member",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewMember(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListMembers(ctx context.Context, token string, pm staff.PageMetadata) (page staff.MembersPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_members",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListMembers(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateMember(ctx context.Context, token string, member staff.Member) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_member",
+			"token", token,
+			"id", member.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateMember(ctx, token, member)
+}
+
+func (lm *loggingMiddleware) RemoveMember(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_member",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveMember(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CreateShift(ctx context.Context, token string, shift staff.Shift) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_shift",
+			"token", token,
+			"member", shift.Member,
+			"starts_at", shift.StartsAt,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateShift(ctx, token, shift)
+}
+
+func (lm *loggingMiddleware) ViewShift(ctx context.Context, token, id string) (shift staff.Shift, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_shift",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewShift(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListShifts(ctx context.Context, token string, pm staff.PageMetadata) (page staff.ShiftsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_shifts",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListShifts(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateShift(ctx context.Context, token string, shift staff.Shift) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_shift",
+			"token", token,
+			"id", shift.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateShift(ctx, token, shift)
+}
+
+func (lm *loggingMiddleware) RemoveShift(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_shift",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveShift(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Clock(ctx context.Context, token, vendor, member, pin string, action staff.ClockAction) (tc staff.Timecard, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "clock",
+			"token", token,
+			"vendor", vendor,
+			"member", member,
+			"action", action,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Clock(ctx, token, vendor, member, pin, action)
+}
+
+func (lm *loggingMiddleware) ListTimecards(ctx context.Context, token string, pm staff.PageMetadata) (page staff.TimecardsPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_timecards",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListTimecards(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) Identify(ctx context.Context, vendor, memberID, pin string) (member staff.Member, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "identify",
+			"vendor", vendor,
+			"member", memberID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Identify(ctx, vendor, memberID, pin)
+}
+
+func (lm *loggingMiddleware) RecordAction(ctx context.Context, token string, action staff.Action) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "record_action",
+			"token", token,
+			"order", action.OrderID,
+			"type", action.Type,
+			"member", action.Member,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RecordAction(ctx, token, action)
+}
+
+func (lm *loggingMiddleware) Report(ctx context.Context, token, vendor string, from, to time.Time) (lines []staff.ReportLine, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "staff_report",
+			"token", token,
+			"vendor", vendor,
+			"from", from,
+			"to", to,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Report(ctx, token, vendor, from, to)
+}
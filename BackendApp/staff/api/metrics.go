@@ -0,0 +1,183 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ staff.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     staff.Service
+}
+
+// MetricsMiddleware instruments the staff service by tracking request count
+// and latency.
+func MetricsMiddleware(svc staff.Service, counter metrics.Counter, latency metrics.Histogram) staff.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "attribute_accepted").Add(1)
+		ms.latency.With("method", "attribute_accepted").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPreparing(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "attribute_paid").Add(1)
+		ms.latency.With("method", "attribute_paid").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPaid(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) CreateMember(ctx context.Context, token string, member staff.Member) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_member").Add(1)
+		ms.latency.With("method", "create_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateMember(ctx, token, member)
+}
+
+func (ms *metricsMiddleware) ViewMember(ctx context.Context, token, id string) (staff.Member, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_member").Add(1)
+		ms.latency.With("method", "view_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewMember(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListMembers(ctx context.Context, token string, pm staff.PageMetadata) (staff.MembersPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_members").Add(1)
+		ms.latency.With("method", "list_members").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListMembers(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateMember(ctx context.Context, token string, member staff.Member) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_member").Add(1)
+		ms.latency.With("method", "update_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateMember(ctx, token, member)
+}
+
+func (ms *metricsMiddleware) RemoveMember(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_member").Add(1)
+		ms.latency.With("method", "remove_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveMember(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) CreateShift(ctx context.Context, token string, shift staff.Shift) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_shift").Add(1)
+		ms.latency.With("method", "create_shift").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateShift(ctx, token, shift)
+}
+
+func (ms *metricsMiddleware) ViewShift(ctx context.Context, token, id string) (staff.Shift, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_shift").Add(1)
+		ms.latency.With("method", "view_shift").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewShift(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListShifts(ctx context.Context, token string, pm staff.PageMetadata) (staff.ShiftsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_shifts").Add(1)
+		ms.latency.With("method", "list_shifts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListShifts(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateShift(ctx context.Context, token string, shift staff.Shift) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_shift").Add(1)
+		ms.latency.With("method", "update_shift").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateShift(ctx, token, shift)
+}
+
+func (ms *metricsMiddleware) RemoveShift(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_shift").Add(1)
+		ms.latency.With("method", "remove_shift").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveShift(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Clock(ctx context.Context, token, vendor, member, pin string, action staff.ClockAction) (staff.Timecard, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "clock").Add(1)
+		ms.latency.With("method", "clock").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Clock(ctx, token, vendor, member, pin, action)
+}
+
+func (ms *metricsMiddleware) ListTimecards(ctx context.Context, token string, pm staff.PageMetadata) (staff.TimecardsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_timecards").Add(1)
+		ms.latency.With("method", "list_timecards").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListTimecards(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) Identify(ctx context.Context, vendor, member, pin string) (staff.Member, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "identify").Add(1)
+		ms.latency.With("method", "identify").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Identify(ctx, vendor, member, pin)
+}
+
+func (ms *metricsMiddleware) RecordAction(ctx context.Context, token string, action staff.Action) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_action").Add(1)
+		ms.latency.With("method", "record_action").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordAction(ctx, token, action)
+}
+
+func (ms *metricsMiddleware) Report(ctx context.Context, token, vendor string, from, to time.Time) ([]staff.ReportLine, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "staff_report").Add(1)
+		ms.latency.With("method", "staff_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Report(ctx, token, vendor, from, to)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+)
+
+const maxLimitSize = 100
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type createMemberReq struct {
+	token  string
+	member staff.Member
+}
+
+func (req createMemberReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if err := req.member.Validate(); err != nil {
+		return err
+	}
+	return staff.ValidatePIN(req.member.PIN)
+}
+
+type updateMemberReq struct {
+	token  string
+	member staff.Member
+}
+
+func (req updateMemberReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.member.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type createShiftReq struct {
+	token string
+	shift staff.Shift
+}
+
+func (req createShiftReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.shift.Validate()
+}
+
+type updateShiftReq struct {
+	token string
+	shift staff.Shift
+}
+
+func (req updateShiftReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.shift.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type clockReq struct {
+	token  string
+	Vendor string            `json:"vendor"`
+	Member string            `json:"member,omitempty"` // The member clocking, checked against the PIN if given.
+	PIN    string            `json:"pin"`
+	Action staff.ClockAction `json:"action"`
+}
+
+func (req clockReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.Vendor == "" || req.PIN == "" {
+		return errors.ErrMalformedEntity
+	}
+	switch req.Action {
+	case staff.ClockIn, staff.ClockOut, staff.BreakStart, staff.BreakEnd:
+		return nil
+	default:
+		return errors.ErrMalformedEntity
+	}
+}
+
+type listReq struct {
+	token      string
+	vendor     string
+	member     string
+	role       string
+	onlyActive bool
+	from       time.Time
+	to         time.Time
+	offset     uint64
+	limit      uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type reportReq struct {
+	token  string
+	vendor string
+	from   time.Time
+	to     time.Time
+}
+
+func (req reportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
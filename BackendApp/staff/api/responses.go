@@ -0,0 +1,211 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createRes)(nil)
+	_ Response = (*updateRes)(nil)
+	_ Response = (*removeRes)(nil)
+	_ Response = (*viewMemberRes)(nil)
+	_ Response = (*membersPageRes)(nil)
+	_ Response = (*viewShiftRes)(nil)
+	_ Response = (*shiftsPageRes)(nil)
+	_ Response = (*timecardRes)(nil)
+	_ Response = (*timecardsPageRes)(nil)
+	_ Response = (*reportRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+// createRes is the response to a created staff member or shift, path is
+// where it can be found.
+type createRes struct {
+	path string
+	ID   string
+}
+
+func (res createRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/%s/%s", res.path, res.ID),
+	}
+}
+
+func (res createRes) Empty() bool {
+	return true
+}
+
+type updateRes struct {
+	path string
+	ID   string
+}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/%s/%s", res.path, res.ID),
+	}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type viewMemberRes struct {
+	staff.Member
+}
+
+func (res viewMemberRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewMemberRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewMemberRes) Empty() bool {
+	return false
+}
+
+type membersPageRes struct {
+	pageRes
+	Members []staff.Member `json:"members"`
+}
+
+func (res membersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res membersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res membersPageRes) Empty() bool {
+	return false
+}
+
+type viewShiftRes struct {
+	staff.Shift
+}
+
+func (res viewShiftRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewShiftRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewShiftRes) Empty() bool {
+	return false
+}
+
+type shiftsPageRes struct {
+	pageRes
+	Shifts []staff.Shift `json:"shifts"`
+}
+
+func (res shiftsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res shiftsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shiftsPageRes) Empty() bool {
+	return false
+}
+
+type timecardRes struct {
+	staff.Timecard
+}
+
+func (res timecardRes) Code() int {
+	return http.StatusOK
+}
+
+func (res timecardRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res timecardRes) Empty() bool {
+	return false
+}
+
+type timecardsPageRes struct {
+	pageRes
+	Timecards []staff.Timecard `json:"timecards"`
+}
+
+func (res timecardsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res timecardsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res timecardsPageRes) Empty() bool {
+	return false
+}
+
+type reportRes struct {
+	Vendor string             `json:"vendor"`
+	From   string             `json:"from,omitempty"`
+	To     string             `json:"to"`
+	Lines  []staff.ReportLine `json:"lines"`
+}
+
+func (res reportRes) Code() int {
+	return http.StatusOK
+}
+
+func (res reportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res reportRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,374 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	contentType = "application/json"
+	pinHeader   = "X-Staff-PIN"
+	pinKey      = "x-staff-pin"
+	idHeader    = "X-Staff-ID"
+	idKey       = "x-staff-id"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	memberKey   = "member"
+	roleKey     = "role"
+	activeKey   = "active"
+	fromKey     = "from"
+	toKey       = "to"
+)
+
+// PINMiddleware puts the PIN of the staff member acting, sent in the
+// X-Staff-PIN header, in the request context so what they do to orders is
+// attributed to them. The member can be named in the X-Staff-ID header.
+func PINMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pin := r.Header.Get(pinHeader); pin != "" {
+			r = r.WithContext(staff.WithPIN(r.Context(), pin))
+		}
+		if id := r.Header.Get(idHeader); id != "" {
+			r = r.WithContext(staff.WithMemberID(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PINInterceptor does for gRPC calls what PINMiddleware does for HTTP
+// requests, the PIN being sent in the x-staff-pin metadata and the member
+// in x-staff-id.
+func PINInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if pins := md.Get(pinKey); len(pins) > 0 && pins[0] != "" {
+			ctx = staff.WithPIN(ctx, pins[0])
+		}
+		if ids := md.Get(idKey); len(ids) > 0 && ids[0] != "" {
+			ctx = staff.WithMemberID(ctx, ids[0])
+		}
+	}
+	return handler(ctx, req)
+}
+
+// MakeStaffHandler returns a HTTP handler for staff members, shifts and
+// timecards API endpoints.
+func MakeStaffHandler(svc staff.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	// The shifts, clock, timecards and report routes are registered before
+	// /staff/{id} so they are not taken for an identifier.
+	r.Methods("POST").Path("/staff/shifts").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_shift")(createShiftEndpoint(svc)),
+		decodeCreateShift,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff/shifts/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_shift")(viewShiftEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff/shifts").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_shifts")(listShiftsEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/staff/shifts/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_shift")(updateShiftEndpoint(svc)),
+		decodeUpdateShift,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/staff/shifts/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_shift")(removeShiftEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/staff/clock").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint clock")(clockEndpoint(svc)),
+		decodeClock,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff/timecards").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_timecards")(listTimecardsEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff/report").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint staff_report")(reportEndpoint(svc)),
+		decodeReport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/staff").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_member")(createMemberEndpoint(svc)),
+		decodeCreateMember,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_member")(viewMemberEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/staff").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_members")(listMembersEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/staff/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_member")(updateMemberEndpoint(svc)),
+		decodeUpdateMember,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/staff/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_member")(removeMemberEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeCreateMember(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	member := staff.Member{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createMemberReq{
+		token:  decodeToken(r),
+		member: member,
+	}
+	return req, nil
+}
+
+func decodeUpdateMember(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	member := staff.Member{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	member.ID = mux.Vars(r)["id"]
+	req := updateMemberReq{
+		token:  decodeToken(r),
+		member: member,
+	}
+	return req, nil
+}
+
+func decodeCreateShift(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var shift staff.Shift
+	if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createShiftReq{
+		token: decodeToken(r),
+		shift: shift,
+	}
+	return req, nil
+}
+
+func decodeUpdateShift(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var shift staff.Shift
+	if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	shift.ID = mux.Vars(r)["id"]
+	req := updateShiftReq{
+		token: decodeToken(r),
+		shift: shift,
+	}
+	return req, nil
+}
+
+func decodeClock(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := clockReq{token: decodeToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+		member: r.URL.Query().Get(memberKey),
+		role:   r.URL.Query().Get(roleKey),
+	}
+	var err error
+	if r.URL.Query().Has(activeKey) {
+		if req.onlyActive, err = strconv.ParseBool(r.URL.Query().Get(activeKey)); err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if req.offset, req.limit, err = decodePage(r); err != nil {
+		return nil, err
+	}
+	if req.from, req.to, err = decodePeriod(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeReport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := reportReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	var err error
+	if req.from, req.to, err = decodePeriod(r); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodePage(r *http.Request) (uint64, uint64, error) {
+	var offset, limit uint64 = 0, maxLimitSize
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return offset, limit, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return offset, limit, nil
+}
+
+func decodePeriod(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if r.URL.Query().Has(fromKey) {
+		from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return from, to, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return from, to, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, staff.ErrLocked):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, staff.ErrClockedIn),
+		errors.Contains(err, staff.ErrNotClockedIn):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package staff
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+type pinKey struct{}
+
+// WithPIN returns a copy of ctx carrying the PIN a staff member entered to
+// act on orders.
+func WithPIN(ctx context.Context, pin string) context.Context {
+	return context.WithValue(ctx, pinKey{}, pin)
+}
+
+// PIN returns the PIN in ctx, if any.
+func PIN(ctx context.Context) string {
+	pin, _ := ctx.Value(pinKey{}).(string)
+	return pin
+}
+
+type memberIDKey struct{}
+
+// WithMemberID returns a copy of ctx naming the staff member whose PIN is
+// in it, the PIN is then only checked against theirs.
+func WithMemberID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, memberIDKey{}, id)
+}
+
+// MemberID returns the unique identifier of the staff member named in ctx,
+// if any.
+func MemberID(ctx context.Context) string {
+	id, _ := ctx.Value(memberIDKey{}).(string)
+	return id
+}
+
+var _ orders.OrderService = (*attributionMiddleware)(nil)
+
+type attributionMiddleware struct {
+	svc   orders.OrderService
+	staff Service
+}
+
+// AttributionMiddleware attributes what is done to orders to the staff
+// member whose PIN is in the context. The PIN is checked against the
+// staff of the order's vendor, orders acted on without a PIN are left
+// unattributed.
+func AttributionMiddleware(svc orders.OrderService, staff Service) orders.OrderService {
+	return &attributionMiddleware{
+		svc:   svc,
+		staff: staff,
+	}
+}
+
+func (am *attributionMiddleware) CreateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	ctx, member, err := am.identify(ctx, order.Vendor)
+	if err != nil {
+		return "", err
+	}
+	id, err := am.svc.CreateOrder(ctx, token, order)
+	if err != nil || member == "" {
+		return id, err
+	}
+	action := Action{
+		OrderID: id,
+		Type:    Created,
+		Vendor:  order.Vendor,
+		Member:  member,
+	}
+	if err := am.staff.RecordAction(ctx, token, action); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (am *attributionMiddleware) ViewOrder(ctx context.Context, token, id string) (orders.Order, error) {
+	return am.svc.ViewOrder(ctx, token, id)
+}
+
+func (am *attributionMiddleware) ListOrders(ctx context.Context, token string, page orders.PageMetadata) (orders.OrdersPage, error) {
+	return am.svc.ListOrders(ctx, token, page)
+}
+
+func (am *attributionMiddleware) UpdateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	ctx, err := am.identifyOrder(ctx, token, order.ID)
+	if err != nil {
+		return "", err
+	}
+	return am.svc.UpdateOrder(ctx, token, order)
+}
+
+func (am *attributionMiddleware) DeleteOrder(ctx context.Context, token, id string) error {
+	return am.svc.DeleteOrder(ctx, token, id)
+}
+
+func (am *attributionMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	ctx, err := am.identifyOrder(ctx, token, id)
+	if err != nil {
+		return orders.Order{}, err
+	}
+	return am.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
+
+// identifyOrder identifies the staff member acting on the order with the
+// unique identifier id.
+func (am *attributionMiddleware) identifyOrder(ctx context.Context, token, id string) (context.Context, error) {
+	if PIN(ctx) == "" {
+		return ctx, nil
+	}
+	order, err := am.svc.ViewOrder(ctx, token, id)
+	if err != nil {
+		return ctx, err
+	}
+	ctx, _, err = am.identify(ctx, order.Vendor)
+	return ctx, err
+}
+
+// identify returns a copy of ctx carrying the staff member of the vendor
+// whose PIN is in ctx.
+func (am *attributionMiddleware) identify(ctx context.Context, vendor string) (context.Context, string, error) {
+	pin := PIN(ctx)
+	if pin == "" {
+		return ctx, "", nil
+	}
+	member, err := am.staff.Identify(ctx, vendor, MemberID(ctx), pin)
+	if err != nil {
+		return ctx, "", err
+	}
+	return orders.WithStaff(ctx, member.ID), member.ID, nil
+}
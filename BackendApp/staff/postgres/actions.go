@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ staff.ActionRepository = (*actionsRepo)(nil)
+
+type actionsRepo struct {
+	db *sqlx.DB
+}
+
+// NewActionsRepo instantiates a PostgreSQL
+// implementation of order actions repository.
+func NewActionsRepo(db *sqlx.DB) staff.ActionRepository {
+	return &actionsRepo{
+		db: db,
+	}
+}
+
+func (repo actionsRepo) Save(ctx context.Context, action staff.Action) error {
+	q := `INSERT INTO staff_actions (order_id, type, vendor, member, created_at)
+		  VALUES (:order_id, :type, :vendor, :member, :created_at) ON CONFLICT (order_id, type) DO NOTHING`
+
+	dba := dbAction{
+		OrderID:   action.OrderID,
+		Type:      string(action.Type),
+		Vendor:    action.Vendor,
+		Member:    action.Member,
+		CreatedAt: action.CreatedAt,
+	}
+	if _, err := sqlx.NamedExecContext(ctx, pgutil.DB(ctx, repo.db), q, dba); err != nil {
+		return handleError(err, errors.ErrCreateEntity)
+	}
+	return nil
+}
+
+func (repo actionsRepo) Count(ctx context.Context, vendor string, from, to time.Time) ([]staff.ActionCount, error) {
+	q := `SELECT member, type, COUNT(*) AS orders FROM staff_actions
+		  WHERE vendor = :vendor AND created_at >= :from AND created_at < :to
+		  GROUP BY member, type`
+
+	params := map[string]interface{}{
+		"vendor": vendor,
+		"from":   from,
+		"to":     to,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var counts []staff.ActionCount
+	for rows.Next() {
+		var c dbActionCount
+		if err := rows.StructScan(&c); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		counts = append(counts, staff.ActionCount{
+			Member: c.Member,
+			Type:   staff.ActionType(c.Type),
+			Orders: c.Orders,
+		})
+	}
+	return counts, nil
+}
+
+type dbAction struct {
+	OrderID   string    `db:"order_id"`
+	Type      string    `db:"type"`
+	Vendor    string    `db:"vendor"`
+	Member    string    `db:"member"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type dbActionCount struct {
+	Member string `db:"member"`
+	Type   string `db:"type"`
+	Orders uint64 `db:"orders"`
+}
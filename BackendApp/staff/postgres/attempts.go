@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ staff.AttemptRepository = (*attemptsRepo)(nil)
+
+type attemptsRepo struct {
+	db *sqlx.DB
+}
+
+// NewAttemptsRepo instantiates a PostgreSQL
+// implementation of PIN attempts repository.
+func NewAttemptsRepo(db *sqlx.DB) staff.AttemptRepository {
+	return &attemptsRepo{
+		db: db,
+	}
+}
+
+func (repo attemptsRepo) Fail(ctx context.Context, key string, at, since time.Time) (uint64, error) {
+	// Counting starts over once the wrong PINs counted so far are too old.
+	q := `INSERT INTO staff_pin_attempts (key, failures, since) VALUES ($1, 1, $2)
+		  ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN staff_pin_attempts.since < $3 THEN 1 ELSE staff_pin_attempts.failures + 1 END,
+			since = CASE WHEN staff_pin_attempts.since < $3 THEN $2 ELSE staff_pin_attempts.since END
+		  RETURNING failures`
+
+	var failures uint64
+	if err := repo.db.QueryRowxContext(ctx, q, key, at.UTC(), since.UTC()).Scan(&failures); err != nil {
+		return 0, handleError(err, errors.ErrCreateEntity)
+	}
+	return failures, nil
+}
+
+func (repo attemptsRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	q := `UPDATE staff_pin_attempts SET locked_until = $2 WHERE key = $1`
+
+	if _, err := repo.db.ExecContext(ctx, q, key, until.UTC()); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return nil
+}
+
+func (repo attemptsRepo) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	q := `SELECT locked_until FROM staff_pin_attempts WHERE key = $1`
+
+	var until sql.NullTime
+	switch err := repo.db.QueryRowxContext(ctx, q, key).Scan(&until); err {
+	case nil:
+	case sql.ErrNoRows:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	// The time is stored in UTC without its zone, and read back in UTC.
+	return until.Time, nil
+}
+
+func (repo attemptsRepo) Reset(ctx context.Context, key string) error {
+	q := `DELETE FROM staff_pin_attempts WHERE key = $1`
+
+	if _, err := repo.db.ExecContext(ctx, q, key); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
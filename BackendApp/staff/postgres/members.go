@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/jmoiron/sqlx"
+)
+
+// The hashed PIN is written but never read back.
+const memberColumns = `id, vendor, name, role, phone, active, created_at, updated_at`
+
+var _ staff.MemberRepository = (*membersRepo)(nil)
+
+type membersRepo struct {
+	db *sqlx.DB
+}
+
+// NewMembersRepo instantiates a PostgreSQL
+// implementation of staff members repository.
+func NewMembersRepo(db *sqlx.DB) staff.MemberRepository {
+	return &membersRepo{
+		db: db,
+	}
+}
+
+func (repo membersRepo) Save(ctx context.Context, member staff.Member) (string, error) {
+	q := `INSERT INTO staff_members (` + memberColumns + `, pin)
+		  VALUES (:id, :vendor, :name, :role, :phone, :active, :created_at, :updated_at, :pin) RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBMember(member))
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo membersRepo) RetrieveByID(ctx context.Context, id string) (staff.Member, error) {
+	q := `SELECT ` + memberColumns + ` FROM staff_members WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo membersRepo) RetrieveByPIN(ctx context.Context, vendor, pin string) (staff.Member, error) {
+	q := `SELECT ` + memberColumns + ` FROM staff_members WHERE vendor = $1 AND pin = $2`
+
+	return repo.retrieve(ctx, q, vendor, pin)
+}
+
+func (repo membersRepo) retrieve(ctx context.Context, q string, args ...interface{}) (staff.Member, error) {
+	dbm := dbMember{}
+	if err := repo.db.QueryRowxContext(ctx, q, args...).StructScan(&dbm); err != nil {
+		if err == sql.ErrNoRows {
+			return staff.Member{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return staff.Member{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toMember(dbm), nil
+}
+
+func (repo membersRepo) RetrieveAll(ctx context.Context, pm staff.PageMetadata) (staff.MembersPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"role":   pm.Role,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Role != "" {
+		query = append(query, "role = :role")
+	}
+	if pm.OnlyActive {
+		query = append(query, "active")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+memberColumns+` FROM staff_members %s ORDER BY name LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return staff.MembersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []staff.Member
+	for rows.Next() {
+		dbm := dbMember{}
+		if err := rows.StructScan(&dbm); err != nil {
+			return staff.MembersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, toMember(dbm))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM staff_members %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return staff.MembersPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := staff.MembersPage{
+		Members: items,
+		PageMetadata: staff.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo membersRepo) Update(ctx context.Context, member staff.Member) (string, error) {
+	q := `UPDATE staff_members SET name = :name, role = :role, phone = :phone, active = :active, updated_at = :updated_at,
+		  pin = CASE WHEN :pin = '' THEN pin ELSE :pin END
+		  WHERE id = :id RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBMember(member))
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo membersRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM staff_members WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbMember{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbMember struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Name      string    `db:"name"`
+	Role      string    `db:"role"`
+	PIN       string    `db:"pin"`
+	Phone     string    `db:"phone"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBMember(member staff.Member) dbMember {
+	return dbMember{
+		ID:        member.ID,
+		Vendor:    member.Vendor,
+		Name:      member.Name,
+		Role:      string(member.Role),
+		PIN:       member.PIN,
+		Phone:     member.Phone,
+		Active:    member.Active,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
+
+func toMember(dbm dbMember) staff.Member {
+	return staff.Member{
+		ID:        dbm.ID,
+		Vendor:    dbm.Vendor,
+		Name:      dbm.Name,
+		Role:      staff.Role(dbm.Role),
+		Phone:     dbm.Phone,
+		Active:    dbm.Active,
+		CreatedAt: dbm.CreatedAt,
+		UpdatedAt: dbm.UpdatedAt,
+	}
+}
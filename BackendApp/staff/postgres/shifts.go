@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/jmoiron/sqlx"
+)
+
+const shiftColumns = `id, vendor, member, starts_at, ends_at, notes, created_at, updated_at`
+
+var _ staff.ShiftRepository = (*shiftsRepo)(nil)
+
+type shiftsRepo struct {
+	db *sqlx.DB
+}
+
+// NewShiftsRepo instantiates a PostgreSQL
+// implementation of shifts repository.
+func NewShiftsRepo(db *sqlx.DB) staff.ShiftRepository {
+	return &shiftsRepo{
+		db: db,
+	}
+}
+
+func (repo shiftsRepo) Save(ctx context.Context, shift staff.Shift) (string, error) {
+	q := `INSERT INTO shifts (` + shiftColumns + `)
+		  VALUES (:id, :vendor, :member, :starts_at, :ends_at, :notes, :created_at, :updated_at) RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBShift(shift))
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo shiftsRepo) RetrieveByID(ctx context.Context, id string) (staff.Shift, error) {
+	q := `SELECT ` + shiftColumns + ` FROM shifts WHERE id = $1`
+
+	dbs := dbShift{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return staff.Shift{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return staff.Shift{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toShift(dbs), nil
+}
+
+func (repo shiftsRepo) RetrieveAll(ctx context.Context, pm staff.PageMetadata) (staff.ShiftsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"member": pm.Member,
+		"from":   pm.From,
+		"to":     pm.To,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Member != "" {
+		query = append(query, "member = :member")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "ends_at > :from")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "starts_at < :to")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+shiftColumns+` FROM shifts %s ORDER BY starts_at LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return staff.ShiftsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []staff.Shift
+	for rows.Next() {
+		dbs := dbShift{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return staff.ShiftsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, toShift(dbs))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM shifts %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return staff.ShiftsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := staff.ShiftsPage{
+		Shifts: items,
+		PageMetadata: staff.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo shiftsRepo) Update(ctx context.Context, shift staff.Shift) (string, error) {
+	q := `UPDATE shifts SET starts_at = :starts_at, ends_at = :ends_at, notes = :notes, updated_at = :updated_at
+		  WHERE id = :id RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBShift(shift))
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo shiftsRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM shifts WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbShift{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+type dbShift struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Member    string    `db:"member"`
+	StartsAt  time.Time `db:"starts_at"`
+	EndsAt    time.Time `db:"ends_at"`
+	Notes     string    `db:"notes"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBShift(shift staff.Shift) dbShift {
+	return dbShift{
+		ID:        shift.ID,
+		Vendor:    shift.Vendor,
+		Member:    shift.Member,
+		StartsAt:  shift.StartsAt,
+		EndsAt:    shift.EndsAt,
+		Notes:     shift.Notes,
+		CreatedAt: shift.CreatedAt,
+		UpdatedAt: shift.UpdatedAt,
+	}
+}
+
+func toShift(dbs dbShift) staff.Shift {
+	return staff.Shift{
+		ID:        dbs.ID,
+		Vendor:    dbs.Vendor,
+		Member:    dbs.Member,
+		StartsAt:  dbs.StartsAt,
+		EndsAt:    dbs.EndsAt,
+		Notes:     dbs.Notes,
+		CreatedAt: dbs.CreatedAt,
+		UpdatedAt: dbs.UpdatedAt,
+	}
+}
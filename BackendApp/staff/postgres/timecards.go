@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+	"github.com/jmoiron/sqlx"
+)
+
+const timecardColumns = `id, vendor, member, clock_in, clock_out, breaks`
+
+var _ staff.TimecardRepository = (*timecardsRepo)(nil)
+
+type timecardsRepo struct {
+	db *sqlx.DB
+}
+
+// NewTimecardsRepo instantiates a PostgreSQL
+// implementation of timecards repository.
+func NewTimecardsRepo(db *sqlx.DB) staff.TimecardRepository {
+	return &timecardsRepo{
+		db: db,
+	}
+}
+
+func (repo timecardsRepo) Save(ctx context.Context, tc staff.Timecard) (string, error) {
+	q := `INSERT INTO timecards (` + timecardColumns + `)
+		  VALUES (:id, :vendor, :member, :clock_in, :clock_out, :breaks) RETURNING id`
+
+	dbtc, err := toDBTimecard(tc)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbtc)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo timecardsRepo) RetrieveOpen(ctx context.Context, member string) (staff.Timecard, error) {
+	q := `SELECT ` + timecardColumns + ` FROM timecards WHERE member = $1 AND clock_out IS NULL`
+
+	dbtc := dbTimecard{}
+	if err := repo.db.QueryRowxContext(ctx, q, member).StructScan(&dbtc); err != nil {
+		if err == sql.ErrNoRows {
+			return staff.Timecard{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return staff.Timecard{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toTimecard(dbtc)
+}
+
+func (repo timecardsRepo) RetrieveAll(ctx context.Context, pm staff.PageMetadata) (staff.TimecardsPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"member": pm.Member,
+		"from":   pm.From,
+		"to":     pm.To,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Member != "" {
+		query = append(query, "member = :member")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "(clock_out IS NULL OR clock_out > :from)")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "clock_in < :to")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+timecardColumns+` FROM timecards %s ORDER BY clock_in DESC LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return staff.TimecardsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []staff.Timecard
+	for rows.Next() {
+		dbtc := dbTimecard{}
+		if err := rows.StructScan(&dbtc); err != nil {
+			return staff.TimecardsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		tc, err := toTimecard(dbtc)
+		if err != nil {
+			return staff.TimecardsPage{}, err
+		}
+		items = append(items, tc)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM timecards %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return staff.TimecardsPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := staff.TimecardsPage{
+		Timecards: items,
+		PageMetadata: staff.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo timecardsRepo) Update(ctx context.Context, tc staff.Timecard) error {
+	q := `UPDATE timecards SET clock_out = :clock_out, breaks = :breaks WHERE id = :id`
+
+	dbtc, err := toDBTimecard(tc)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, dbtc)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+type dbTimecard struct {
+	ID       string       `db:"id"`
+	Vendor   string       `db:"vendor"`
+	Member   string       `db:"member"`
+	ClockIn  time.Time    `db:"clock_in"`
+	ClockOut sql.NullTime `db:"clock_out"`
+	Breaks   []byte       `db:"breaks"`
+}
+
+func toDBTimecard(tc staff.Timecard) (dbTimecard, error) {
+	breaks := []byte("[]")
+	if len(tc.Breaks) > 0 {
+		b, err := json.Marshal(tc.Breaks)
+		if err != nil {
+			return dbTimecard{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		breaks = b
+	}
+	return dbTimecard{
+		ID:       tc.ID,
+		Vendor:   tc.Vendor,
+		Member:   tc.Member,
+		ClockIn:  tc.ClockIn,
+		ClockOut: sql.NullTime{Time: tc.ClockOut, Valid: !tc.ClockOut.IsZero()},
+		Breaks:   breaks,
+	}, nil
+}
+
+func toTimecard(dbtc dbTimecard) (staff.Timecard, error) {
+	var breaks []staff.Break
+	if dbtc.Breaks != nil {
+		if err := json.Unmarshal(dbtc.Breaks, &breaks); err != nil {
+			return staff.Timecard{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	tc := staff.Timecard{
+		ID:      dbtc.ID,
+		Vendor:  dbtc.Vendor,
+		Member:  dbtc.Member,
+		ClockIn: dbtc.ClockIn,
+		Breaks:  breaks,
+	}
+	if dbtc.ClockOut.Valid {
+		tc.ClockOut = dbtc.ClockOut.Time
+	}
+	return tc, nil
+}
@@ -0,0 +1,445 @@
+package staff
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+const pageSize = 100
+
+// Defaults of the PIN lockouts.
+const (
+	DefaultMaxAttempts       = 5
+	DefaultMaxVendorAttempts = 20
+	DefaultLockout           = 15 * time.Minute
+)
+
+// Config defines the options the staff service uses.
+type Config struct {
+	PINKey            []byte        // The server secret PINs are hashed with, required.
+	MaxAttempts       uint64        // The wrong PINs a member can be named with before being locked out.
+	MaxVendorAttempts uint64        // The wrong PINs entered at a vendor before its PINs are locked out.
+	Lockout           time.Duration // How long wrong PINs are counted for, and lockouts last.
+}
+
+var _ Service = (*staffService)(nil)
+
+type staffService struct {
+	config    Config
+	members   MemberRepository
+	shifts    ShiftRepository
+	timecards TimecardRepository
+	actions   ActionRepository
+	attempts  AttemptRepository
+}
+
+// NewService instantiates the staff service implementation.
+func NewService(config Config, members MemberRepository, shifts ShiftRepository, timecards TimecardRepository, actions ActionRepository, attempts AttemptRepository) Service {
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.MaxVendorAttempts == 0 {
+		config.MaxVendorAttempts = DefaultMaxVendorAttempts
+	}
+	if config.Lockout == 0 {
+		config.Lockout = DefaultLockout
+	}
+	return &staffService{
+		config:    config,
+		members:   members,
+		shifts:    shifts,
+		timecards: timecards,
+		actions:   actions,
+		attempts:  attempts,
+	}
+}
+
+func (svc staffService) CreateMember(ctx context.Context, token string, member Member) (string, error) {
+	if err := member.Validate(); err != nil {
+		return "", err
+	}
+	if err := ValidatePIN(member.PIN); err != nil {
+		return "", err
+	}
+	member.ID = ulid.Make().String()
+	member.PIN = svc.hashPIN(member.Vendor, member.PIN)
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = time.Now()
+	return svc.members.Save(ctx, member)
+}
+
+func (svc staffService) ViewMember(ctx context.Context, token, id string) (Member, error) {
+	return svc.members.RetrieveByID(ctx, id)
+}
+
+func (svc staffService) ListMembers(ctx context.Context, token string, pm PageMetadata) (MembersPage, error) {
+	return svc.members.RetrieveAll(ctx, pm)
+}
+
+func (svc staffService) UpdateMember(ctx context.Context, token string, member Member) (string, error) {
+	current, err := svc.members.RetrieveByID(ctx, member.ID)
+	if err != nil {
+		return "", err
+	}
+	member.Vendor = current.Vendor
+	if err := member.Validate(); err != nil {
+		return "", err
+	}
+	if member.PIN != "" {
+		if err := ValidatePIN(member.PIN); err != nil {
+			return "", err
+		}
+		member.PIN = svc.hashPIN(member.Vendor, member.PIN)
+	}
+	member.UpdatedAt = time.Now()
+	return svc.members.Update(ctx, member)
+}
+
+func (svc staffService) RemoveMember(ctx context.Context, token, id string) error {
+	return svc.members.Remove(ctx, id)
+}
+
+func (svc staffService) CreateShift(ctx context.Context, token string, shift Shift) (string, error) {
+	if err := shift.Validate(); err != nil {
+		return "", err
+	}
+	member, err := svc.members.RetrieveByID(ctx, shift.Member)
+	if err != nil {
+		return "", err
+	}
+	shift.ID = ulid.Make().String()
+	shift.Vendor = member.Vendor
+	shift.CreatedAt = time.Now()
+	shift.UpdatedAt = time.Now()
+	return svc.shifts.Save(ctx, shift)
+}
+
+func (svc staffService) ViewShift(ctx context.Context, token, id string) (Shift, error) {
+	return svc.shifts.RetrieveByID(ctx, id)
+}
+
+func (svc staffService) ListShifts(ctx context.Context, token string, pm PageMetadata) (ShiftsPage, error) {
+	return svc.shifts.RetrieveAll(ctx, pm)
+}
+
+func (svc staffService) UpdateShift(ctx context.Context, token string, shift Shift) (string, error) {
+	current, err := svc.shifts.RetrieveByID(ctx, shift.ID)
+	if err != nil {
+		return "", err
+	}
+	shift.Vendor = current.Vendor
+	shift.Member = current.Member
+	if err := shift.Validate(); err != nil {
+		return "", err
+	}
+	shift.UpdatedAt = time.Now()
+	return svc.shifts.Update(ctx, shift)
+}
+
+func (svc staffService) RemoveShift(ctx context.Context, token, id string) error {
+	return svc.shifts.Remove(ctx, id)
+}
+
+func (svc staffService) Clock(ctx context.Context, token, vendor, memberID, pin string, action ClockAction) (Timecard, error) {
+	member, err := svc.Identify(ctx, vendor, memberID, pin)
+	if err != nil {
+		return Timecard{}, err
+	}
+	tc, err := svc.timecards.RetrieveOpen(ctx, member.ID)
+	open := err == nil
+	if err != nil && !errors.Contains(err, errors.ErrNotFound) {
+		return Timecard{}, err
+	}
+
+	now := time.Now()
+	switch action {
+	case ClockIn:
+		if open {
+			return Timecard{}, ErrClockedIn
+		}
+		tc = Timecard{
+			ID:      ulid.Make().String(),
+			Vendor:  member.Vendor,
+			Member:  member.ID,
+			ClockIn: now,
+		}
+		// Racing clock ins clash on the member's one open timecard.
+		if _, err := svc.timecards.Save(ctx, tc); err != nil {
+			if errors.Contains(err, errors.ErrConflict) {
+				return Timecard{}, ErrClockedIn
+			}
+			return Timecard{}, err
+		}
+		return tc, nil
+	case ClockOut:
+		if !open {
+			return Timecard{}, ErrNotClockedIn
+		}
+		if tc.OnBreak() {
+			tc.Breaks[len(tc.Breaks)-1].End = now
+		}
+		tc.ClockOut = now
+	case BreakStart:
+		if !open {
+			return Timecard{}, ErrNotClockedIn
+		}
+		if tc.OnBreak() {
+			return Timecard{}, errors.ErrConflict
+		}
+		tc.Breaks = append(tc.Breaks, Break{Start: now})
+	case BreakEnd:
+		if !open || !tc.OnBreak() {
+			return Timecard{}, ErrNotClockedIn
+		}
+		tc.Breaks[len(tc.Breaks)-1].End = now
+	default:
+		return Timecard{}, errors.ErrMalformedEntity
+	}
+	if err := svc.timecards.Update(ctx, tc); err != nil {
+		return Timecard{}, err
+	}
+	return tc, nil
+}
+
+func (svc staffService) ListTimecards(ctx context.Context, token string, pm PageMetadata) (TimecardsPage, error) {
+	return svc.timecards.RetrieveAll(ctx, pm)
+}
+
+func (svc staffService) Identify(ctx context.Context, vendor, memberID, pin string) (Member, error) {
+	if vendor == "" || ValidatePIN(pin) != nil {
+		return Member{}, errors.ErrAuthentication
+	}
+	// PINs are short, wrong ones are counted against the vendor and the
+	// member named so they can not be guessed one after the other.
+	keys := []string{"vendor:" + vendor}
+	if memberID != "" {
+		keys = append(keys, "member:"+memberID)
+	}
+	now := time.Now()
+	for _, key := range keys {
+		until, err := svc.attempts.LockedUntil(ctx, key)
+		if err != nil {
+			return Member{}, err
+		}
+		if now.Before(until) {
+			return Member{}, errors.Wrap(errors.ErrAuthentication, ErrLocked)
+		}
+	}
+
+	member, err := svc.retrieveByPIN(ctx, vendor, pin)
+	if errors.Contains(err, errors.ErrNotFound) || (err == nil && memberID != "" && member.ID != memberID) {
+		if err := svc.fail(ctx, keys, now); err != nil {
+			return Member{}, err
+		}
+		return Member{}, errors.ErrAuthentication
+	}
+	if err != nil {
+		return Member{}, err
+	}
+	if memberID != "" {
+		if err := svc.attempts.Reset(ctx, "member:"+memberID); err != nil {
+			return Member{}, err
+		}
+	}
+	if !member.Active {
+		return Member{}, errors.ErrAuthentication
+	}
+	return member, nil
+}
+
+// retrieveByPIN retrieves the member of the vendor with the PIN. PINs
+// hashed before they were hashed with the key are hashed again with it
+// once they are entered.
+func (svc staffService) retrieveByPIN(ctx context.Context, vendor, pin string) (Member, error) {
+	member, err := svc.members.RetrieveByPIN(ctx, vendor, svc.hashPIN(vendor, pin))
+	if !errors.Contains(err, errors.ErrNotFound) {
+		return member, err
+	}
+	member, err = svc.members.RetrieveByPIN(ctx, vendor, legacyHashPIN(vendor, pin))
+	if err != nil {
+		return Member{}, err
+	}
+	member.PIN = svc.hashPIN(vendor, pin)
+	if _, err := svc.members.Update(ctx, member); err != nil {
+		return Member{}, err
+	}
+	member.PIN = ""
+	return member, nil
+}
+
+// fail counts a wrong PIN against the keys, locking out those with too many.
+func (svc staffService) fail(ctx context.Context, keys []string, at time.Time) error {
+	for _, key := range keys {
+		failures, err := svc.attempts.Fail(ctx, key, at, at.Add(-svc.config.Lockout))
+		if err != nil {
+			return err
+		}
+		max := svc.config.MaxAttempts
+		if strings.HasPrefix(key, "vendor:") {
+			max = svc.config.MaxVendorAttempts
+		}
+		if failures < max {
+			continue
+		}
+		if err := svc.attempts.Lock(ctx, key, at.Add(svc.config.Lockout)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc staffService) RecordAction(ctx context.Context, token string, action Action) error {
+	if action.OrderID == "" || action.Type == "" || action.Vendor == "" || action.Member == "" {
+		return errors.ErrMalformedEntity
+	}
+	if action.CreatedAt.IsZero() {
+		action.CreatedAt = time.Now()
+	}
+	return svc.actions.Save(ctx, action)
+}
+
+func (svc staffService) Report(ctx context.Context, token, vendor string, from, to time.Time) ([]ReportLine, error) {
+	if vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	now := time.Now()
+	if to.IsZero() {
+		to = now
+	}
+	if to.Before(from) {
+		return nil, errors.ErrMalformedEntity
+	}
+
+	var lines []ReportLine
+	index := make(map[string]int)
+	pm := PageMetadata{Vendor: vendor, Limit: pageSize}
+	for {
+		page, err := svc.members.RetrieveAll(ctx, pm)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page.Members {
+			index[m.ID] = len(lines)
+			lines = append(lines, ReportLine{Member: m.ID, Name: m.Name, Role: m.Role})
+		}
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+
+	var worked, breaks = make(map[string]time.Duration), make(map[string]time.Duration)
+	pm = PageMetadata{Vendor: vendor, From: from, To: to, Limit: pageSize}
+	for {
+		page, err := svc.timecards.RetrieveAll(ctx, pm)
+		if err != nil {
+			return nil, err
+		}
+		for _, tc := range page.Timecards {
+			w, b := tc.Worked(from, to, now)
+			worked[tc.Member] += w
+			breaks[tc.Member] += b
+		}
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+
+	var scheduled = make(map[string]time.Duration)
+	pm.Offset = 0
+	for {
+		page, err := svc.shifts.RetrieveAll(ctx, pm)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range page.Shifts {
+			if i, ok := index[s.Member]; ok {
+				lines[i].Shifts++
+			}
+			scheduled[s.Member] += overlap(s.StartsAt, s.EndsAt, from, to)
+		}
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+
+	counts, err := svc.actions.Count(ctx, vendor, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		i, ok := index[c.Member]
+		if !ok {
+			continue
+		}
+		switch c.Type {
+		case Created:
+			lines[i].Created = c.Orders
+		case Accepted:
+			lines[i].Accepted = c.Orders
+		case Paid:
+			lines[i].Paid = c.Orders
+		}
+	}
+	for i := range lines {
+		lines[i].Scheduled = hours(scheduled[lines[i].Member])
+		lines[i].Worked = hours(worked[lines[i].Member])
+		lines[i].Breaks = hours(breaks[lines[i].Member])
+	}
+	return lines, nil
+}
+
+// OrderPreparing attributes sending the order to the kitchen to the staff
+// member who accepted it.
+func (svc staffService) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	return svc.attribute(ctx, token, order, Accepted, order.AcceptedBy)
+}
+
+// OrderPaid attributes settling the order to the staff member who took the
+// payment.
+func (svc staffService) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	return svc.attribute(ctx, token, order, Paid, order.PaidBy)
+}
+
+func (svc staffService) attribute(ctx context.Context, token string, order orders.Order, typ ActionType, member string) error {
+	if member == "" {
+		return nil
+	}
+	action := Action{
+		OrderID: order.ID,
+		Type:    typ,
+		Vendor:  order.Vendor,
+		Member:  member,
+	}
+	return svc.RecordAction(ctx, token, action)
+}
+
+// hashPIN hashes the PIN with the vendor under the server's key, so PINs
+// can not be worked out from their hashes without the key and the same PIN
+// of staff of different vendors hashes differently.
+func (svc staffService) hashPIN(vendor, pin string) string {
+	mac := hmac.New(sha256.New, svc.config.PINKey)
+	mac.Write([]byte(vendor + ":" + pin))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// legacyHashPIN is how PINs were hashed before they were hashed with a key.
+func legacyHashPIN(vendor, pin string) string {
+	sum := sha256.Sum256([]byte(vendor + ":" + pin))
+	return hex.EncodeToString(sum[:])
+}
+
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
@@ -0,0 +1,183 @@
+package staff_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/staff"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+)
+
+// memberRepo keeps members and their hashed PINs in memory.
+type memberRepo struct {
+	staff.MemberRepository
+	mu      sync.Mutex
+	members map[string]staff.Member
+}
+
+func (repo *memberRepo) Save(ctx context.Context, member staff.Member) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.members[member.ID] = member
+	return member.ID, nil
+}
+
+func (repo *memberRepo) RetrieveByPIN(ctx context.Context, vendor, pin string) (staff.Member, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, m := range repo.members {
+		if m.Vendor == vendor && m.PIN == pin {
+			m.PIN = ""
+			return m, nil
+		}
+	}
+	return staff.Member{}, errors.ErrNotFound
+}
+
+func (repo *memberRepo) Update(ctx context.Context, member staff.Member) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	current := repo.members[member.ID]
+	if member.PIN == "" {
+		member.PIN = current.PIN
+	}
+	repo.members[member.ID] = member
+	return member.ID, nil
+}
+
+type attempt struct {
+	failures uint64
+	since    time.Time
+	until    time.Time
+}
+
+// attemptRepo counts wrong PINs in memory.
+type attemptRepo struct {
+	mu       sync.Mutex
+	attempts map[string]attempt
+}
+
+func (repo *attemptRepo) Fail(ctx context.Context, key string, at, since time.Time) (uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	a := repo.attempts[key]
+	if a.since.Before(since) {
+		a.failures, a.since = 0, at
+	}
+	a.failures++
+	repo.attempts[key] = a
+	return a.failures, nil
+}
+
+func (repo *attemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	a := repo.attempts[key]
+	a.until = until
+	repo.attempts[key] = a
+	return nil
+}
+
+func (repo *attemptRepo) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.attempts[key].until, nil
+}
+
+func (repo *attemptRepo) Reset(ctx context.Context, key string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.attempts, key)
+	return nil
+}
+
+func newService(t *testing.T) (staff.Service, *memberRepo, string) {
+	members := &memberRepo{members: map[string]staff.Member{}}
+	attempts := &attemptRepo{attempts: map[string]attempt{}}
+	config := staff.Config{PINKey: []byte("secret"), MaxAttempts: 3, MaxVendorAttempts: 5, Lockout: time.Minute}
+	svc := staff.NewService(config, members, nil, nil, nil, attempts)
+	id, err := svc.CreateMember(context.Background(), token, staff.Member{Vendor: vendor, Name: "Wanjiku", Role: staff.Cashier, PIN: "1234", Active: true})
+	if err != nil {
+		t.Fatalf("creating member: %s", err)
+	}
+	return svc, members, id
+}
+
+func TestIdentifyHashesWithKey(t *testing.T) {
+	svc, members, id := newService(t)
+
+	legacy := sha256.Sum256([]byte(vendor + ":1234"))
+	if members.members[id].PIN == hex.EncodeToString(legacy[:]) {
+		t.Errorf("PIN hashed without the key")
+	}
+	member, err := svc.Identify(context.Background(), vendor, "", "1234")
+	if err != nil || member.ID != id {
+		t.Errorf("identifying returned %s, %v, want %s", member.ID, err, id)
+	}
+}
+
+func TestIdentifyLegacyPIN(t *testing.T) {
+	ctx := context.Background()
+	svc, members, id := newService(t)
+
+	// A PIN hashed before PINs were hashed with the key is hashed again
+	// once it is entered.
+	legacy := sha256.Sum256([]byte(vendor + ":1234"))
+	m := members.members[id]
+	m.PIN = hex.EncodeToString(legacy[:])
+	members.members[id] = m
+	if _, err := svc.Identify(ctx, vendor, "", "1234"); err != nil {
+		t.Fatalf("identifying with a legacy PIN: %s", err)
+	}
+	if members.members[id].PIN == m.PIN {
+		t.Errorf("legacy PIN was not hashed again")
+	}
+	if _, err := svc.Identify(ctx, vendor, "", "1234"); err != nil {
+		t.Errorf("identifying after the PIN was hashed again: %s", err)
+	}
+}
+
+func TestIdentifyMemberLockout(t *testing.T) {
+	ctx := context.Background()
+	svc, _, id := newService(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Identify(ctx, vendor, id, "9999"); !errors.Contains(err, errors.ErrAuthentication) {
+			t.Fatalf("wrong PIN %d returned %v, want %s", i+1, err, errors.ErrAuthentication)
+		}
+	}
+	// The member named is locked out, even with the right PIN.
+	_, err := svc.Identify(ctx, vendor, id, "1234")
+	if !errors.Contains(err, staff.ErrLocked) || !errors.Contains(err, errors.ErrAuthentication) {
+		t.Errorf("identifying a locked out member returned %v, want %s", err, staff.ErrLocked)
+	}
+	if _, err := svc.Identify(ctx, vendor, "", "1234"); err != nil {
+		t.Errorf("identifying without naming the member: %s", err)
+	}
+}
+
+func TestIdentifyVendorLockout(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newService(t)
+
+	for _, pin := range []string{"0000", "1111", "2222", "3333", "4444"} {
+		if _, err := svc.Identify(ctx, vendor, "", pin); !errors.Contains(err, errors.ErrAuthentication) {
+			t.Fatalf("wrong PIN %s returned %v, want %s", pin, err, errors.ErrAuthentication)
+		}
+	}
+	if _, err := svc.Identify(ctx, vendor, "", "1234"); !errors.Contains(err, staff.ErrLocked) {
+		t.Errorf("identifying at a locked out vendor returned %v, want %s", err, staff.ErrLocked)
+	}
+	if _, err := svc.Identify(ctx, "kibanda", "", "0000"); errors.Contains(err, staff.ErrLocked) {
+		t.Errorf("another vendor was locked out")
+	}
+}
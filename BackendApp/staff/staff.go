@@ -0,0 +1,382 @@
+// Package staff keeps track of who works for a vendor and when. Staff
+// members clock in and out on a shared tablet with their PIN, and what they
+// do to orders is attributed to them.
+package staff
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var (
+	// ErrClockedIn indicates a staff member clocking in twice.
+	ErrClockedIn = errors.New("staff member is already clocked in")
+
+	// ErrNotClockedIn indicates a clock action by a staff member who is
+	// not clocked in, or not on a break when ending one.
+	ErrNotClockedIn = errors.New("staff member is not clocked in")
+
+	// ErrLocked indicates PINs locked out after too many wrong ones were
+	// entered. It is returned wrapped in errors.ErrAuthentication.
+	ErrLocked = errors.New("too many wrong PINs, try again later")
+)
+
+// Role is what a staff member does.
+type Role string
+
+// Staff roles.
+const (
+	Owner   Role = "owner"
+	Manager Role = "manager"
+	Cashier Role = "cashier"
+	Waiter  Role = "waiter"
+	Cook    Role = "cook"
+)
+
+// Roles staff members can have.
+var Roles = []Role{Owner, Manager, Cashier, Waiter, Cook}
+
+// Member is a person working for a vendor.
+type Member struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Role      Role      `json:"role,omitempty"`
+	PIN       string    `json:"pin,omitempty"` // The PIN the member clocks in with, never returned.
+	Phone     string    `json:"phone,omitempty"`
+	Active    bool      `json:"active"`               // Whether the member still works for the vendor.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the member was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the member was created in the system.
+}
+
+// Validate returns an error if the member representation is invalid.
+func (m Member) Validate() error {
+	if m.Vendor == "" || m.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	for _, role := range Roles {
+		if role == m.Role {
+			return nil
+		}
+	}
+	return errors.ErrMalformedEntity
+}
+
+// ValidatePIN returns an error unless pin is 4 to 6 digits long.
+func ValidatePIN(pin string) error {
+	if len(pin) < 4 || len(pin) > 6 {
+		return errors.ErrMalformedEntity
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return errors.ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+// Shift is when a staff member is scheduled to work.
+type Shift struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Member    string    `json:"member,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Notes     string    `json:"notes,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the shift was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the shift was created in the system.
+}
+
+// Validate returns an error if the shift representation is invalid.
+func (s Shift) Validate() error {
+	if s.Member == "" || s.StartsAt.IsZero() || !s.EndsAt.After(s.StartsAt) {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Break is a pause in a staff member's work. An open break has no end.
+type Break struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"`
+}
+
+// Timecard is a stretch of work from clocking in to clocking out. An open
+// timecard has no clock out.
+type Timecard struct {
+	ID       string    `json:"id,omitempty"`
+	Vendor   string    `json:"vendor,omitempty"`
+	Member   string    `json:"member,omitempty"`
+	ClockIn  time.Time `json:"clock_in"`
+	ClockOut time.Time `json:"clock_out,omitempty"`
+	Breaks   []Break   `json:"breaks,omitempty"`
+}
+
+// OnBreak reports whether the staff member is on a break.
+func (tc Timecard) OnBreak() bool {
+	return len(tc.Breaks) > 0 && tc.Breaks[len(tc.Breaks)-1].End.IsZero()
+}
+
+// Worked returns how long was worked, breaks excluded, between from and
+// to. Open timecards and breaks run until now.
+func (tc Timecard) Worked(from, to, now time.Time) (worked, breaks time.Duration) {
+	end := tc.ClockOut
+	if end.IsZero() {
+		end = now
+	}
+	worked = overlap(tc.ClockIn, end, from, to)
+	for _, b := range tc.Breaks {
+		bend := b.End
+		if bend.IsZero() {
+			bend = end
+		}
+		breaks += overlap(b.Start, bend, from, to)
+	}
+	return worked - breaks, breaks
+}
+
+// overlap returns how long start to end overlaps from to to.
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// ClockAction is what a staff member does on the shared tablet.
+type ClockAction string
+
+// Clock actions.
+const (
+	ClockIn    ClockAction = "in"
+	ClockOut   ClockAction = "out"
+	BreakStart ClockAction = "break"
+	BreakEnd   ClockAction = "resume"
+)
+
+// ActionType is what a staff member did to an order.
+type ActionType string
+
+// Order actions attributed to staff.
+const (
+	Created  ActionType = "created"
+	Accepted ActionType = "accepted"
+	Paid     ActionType = "paid"
+)
+
+// Action is something a staff member did to an order.
+type Action struct {
+	OrderID   string     `json:"order_id"`
+	Type      ActionType `json:"type"`
+	Vendor    string     `json:"vendor"`
+	Member    string     `json:"member"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ActionCount is how many orders a staff member did an action to.
+type ActionCount struct {
+	Member string
+	Type   ActionType
+	Orders uint64
+}
+
+// ReportLine sums up the work of a staff member over a period.
+type ReportLine struct {
+	Member    string  `json:"member"`
+	Name      string  `json:"name"`
+	Role      Role    `json:"role"`
+	Shifts    uint64  `json:"shifts"`    // The shifts scheduled.
+	Scheduled float64 `json:"scheduled"` // The hours scheduled.
+	Worked    float64 `json:"worked"`    // The hours worked, breaks excluded.
+	Breaks    float64 `json:"breaks"`    // The hours on breaks.
+	Created   uint64  `json:"created"`   // The orders taken.
+	Accepted  uint64  `json:"accepted"`  // The orders sent to the kitchen.
+	Paid      uint64  `json:"paid"`      // The orders settled.
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Vendor string
+	Member string
+	Role   string
+	From   time.Time
+	To     time.Time
+
+	// OnlyActive limits members to those still working for the vendor.
+	OnlyActive bool
+}
+
+// MembersPage contains a page of staff members.
+type MembersPage struct {
+	PageMetadata
+	Members []Member
+}
+
+// ShiftsPage contains a page of shifts.
+type ShiftsPage struct {
+	PageMetadata
+	Shifts []Shift
+}
+
+// TimecardsPage contains a page of timecards.
+type TimecardsPage struct {
+	PageMetadata
+	Timecards []Timecard
+}
+
+// Service specifies the staff API.
+type Service interface {
+	orders.Hook
+
+	// CreateMember adds a staff member, a PIN is required.
+	CreateMember(ctx context.Context, token string, member Member) (string, error)
+
+	// ViewMember retrieves a staff member by its unique identifier ID.
+	ViewMember(ctx context.Context, token, id string) (Member, error)
+
+	// ListMembers retrieves the staff members for a given pageMetadata.
+	ListMembers(ctx context.Context, token string, pm PageMetadata) (MembersPage, error)
+
+	// UpdateMember updates the name, role, phone and active flag of a staff
+	// member, and the PIN if one is given.
+	UpdateMember(ctx context.Context, token string, member Member) (string, error)
+
+	// RemoveMember removes a staff member.
+	RemoveMember(ctx context.Context, token, id string) error
+
+	// CreateShift schedules a shift for a staff member.
+	CreateShift(ctx context.Context, token string, shift Shift) (string, error)
+
+	// ViewShift retrieves a shift by its unique identifier ID.
+	ViewShift(ctx context.Context, token, id string) (Shift, error)
+
+	// ListShifts retrieves the shifts for a given pageMetadata.
+	ListShifts(ctx context.Context, token string, pm PageMetadata) (ShiftsPage, error)
+
+	// UpdateShift reschedules a shift.
+	UpdateShift(ctx context.Context, token string, shift Shift) (string, error)
+
+	// RemoveShift removes a shift.
+	RemoveShift(ctx context.Context, token, id string) error
+
+	// Clock clocks the staff member of the vendor with the PIN in or out,
+	// or starts or ends their break. The member is identified as Identify
+	// does. The timecard is returned as it is after the action.
+	Clock(ctx context.Context, token, vendor, member, pin string, action ClockAction) (Timecard, error)
+
+	// ListTimecards retrieves the timecards for a given pageMetadata.
+	ListTimecards(ctx context.Context, token string, pm PageMetadata) (TimecardsPage, error)
+
+	// Identify returns the active staff member of the vendor with the PIN.
+	// If the unique identifier of the member is given the PIN must be
+	// theirs. Wrong PINs are counted against the vendor and the member, and
+	// once there are too many the PINs are locked out for a while.
+	Identify(ctx context.Context, vendor, member, pin string) (Member, error)
+
+	// RecordAction attributes an action on an order to a staff member. An
+	// action on an order is only attributed once.
+	RecordAction(ctx context.Context, token string, action Action) error
+
+	// Report sums up the hours worked and the orders handled by each of
+	// a vendor's staff members between from and to.
+	Report(ctx context.Context, token, vendor string, from, to time.Time) ([]ReportLine, error)
+}
+
+// MemberRepository specifies a staff member persistence API.
+type MemberRepository interface {
+	// Save persists the member, its PIN hashed.
+	Save(ctx context.Context, member Member) (string, error)
+
+	// RetrieveByID retrieves a member by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Member, error)
+
+	// RetrieveByPIN retrieves the member of the vendor with the hashed PIN.
+	RetrieveByPIN(ctx context.Context, vendor, pin string) (Member, error)
+
+	// RetrieveAll retrieves the members for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (MembersPage, error)
+
+	// Update updates the name, role, phone and active flag of the member,
+	// and its hashed PIN if one is given.
+	Update(ctx context.Context, member Member) (string, error)
+
+	// Remove removes the member.
+	Remove(ctx context.Context, id string) error
+}
+
+// ShiftRepository specifies a shift persistence API.
+type ShiftRepository interface {
+	// Save persists the shift.
+	Save(ctx context.Context, shift Shift) (string, error)
+
+	// RetrieveByID retrieves a shift by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Shift, error)
+
+	// RetrieveAll retrieves the shifts overlapping the period of a given
+	// pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (ShiftsPage, error)
+
+	// Update updates the times and notes of the shift.
+	Update(ctx context.Context, shift Shift) (string, error)
+
+	// Remove removes the shift.
+	Remove(ctx context.Context, id string) error
+}
+
+// TimecardRepository specifies a timecard persistence API.
+type TimecardRepository interface {
+	// Save persists the timecard. A member has one open timecard at most.
+	Save(ctx context.Context, tc Timecard) (string, error)
+
+	// RetrieveOpen retrieves the open timecard of a member.
+	RetrieveOpen(ctx context.Context, member string) (Timecard, error)
+
+	// RetrieveAll retrieves the timecards overlapping the period of a given
+	// pageMetadata, newest first.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (TimecardsPage, error)
+
+	// Update updates the clock out and breaks of the timecard.
+	Update(ctx context.Context, tc Timecard) error
+}
+
+// ActionRepository specifies an order action persistence API.
+type ActionRepository interface {
+	// Save persists the action, an action already saved for the order is
+	// skipped.
+	Save(ctx context.Context, action Action) error
+
+	// Count counts the orders each of a vendor's staff members did an
+	// action to between from and to.
+	Count(ctx context.Context, vendor string, from, to time.Time) ([]ActionCount, error)
+}
+
+// AttemptRepository specifies a persistence API for the wrong PINs entered,
+// counted against keys such as a vendor or a member.
+type AttemptRepository interface {
+	// Fail counts a wrong PIN entered at against the key, and returns the
+	// number of wrong PINs counted since the given time. Older ones are
+	// forgotten.
+	Fail(ctx context.Context, key string, at, since time.Time) (uint64, error)
+
+	// Lock locks the key out until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// LockedUntil returns when the lockout of the key ends, the zero time if
+	// it is not locked out.
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+
+	// Reset forgets the wrong PINs counted against the key.
+	Reset(ctx context.Context, key string) error
+}
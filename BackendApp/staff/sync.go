@@ -0,0 +1,37 @@
+package staff
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/offline"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var _ offline.Service = (*syncMiddleware)(nil)
+
+type syncMiddleware struct {
+	svc   offline.Service
+	staff Service
+}
+
+// SyncMiddleware attributes the orders devices sync to the staff member
+// whose PIN is in the context, checked against the staff of the vendor
+// synced. Changes synced without a PIN are left unattributed.
+func SyncMiddleware(svc offline.Service, staff Service) offline.Service {
+	return &syncMiddleware{
+		svc:   svc,
+		staff: staff,
+	}
+}
+
+func (sm *syncMiddleware) Sync(ctx context.Context, token string, cs offline.ChangeSet) (offline.Sync, error) {
+	pin := PIN(ctx)
+	if pin == "" {
+		return sm.svc.Sync(ctx, token, cs)
+	}
+	member, err := sm.staff.Identify(ctx, cs.Vendor, MemberID(ctx), pin)
+	if err != nil {
+		return offline.Sync{}, err
+	}
+	return sm.svc.Sync(orders.WithStaff(ctx, member.ID), token, cs)
+}
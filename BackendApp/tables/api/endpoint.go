@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func createTableEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createTableReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateTable(ctx, req.token, req.table)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{ID: id, location: "/tables"}, nil
+	}
+}
+
+func viewTableEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		table, err := svc.ViewTable(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return toViewTableRes(table), nil
+	}
+}
+
+func listTablesEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listTablesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := tables.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Area:   req.area,
+		}
+		page, err := svc.ListTables(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		res := tablesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Tables: []viewTableRes{},
+		}
+		for _, table := range page.Tables {
+			res.Tables = append(res.Tables, toViewTableRes(table))
+		}
+		return res, nil
+	}
+}
+
+func updateTableEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateTableReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		table := tables.Table{
+			ID:       req.id,
+			Number:   req.Number,
+			Area:     req.Area,
+			Seats:    req.Seats,
+			QRCode:   req.QRCode,
+			Metadata: req.Metadata,
+		}
+		id, err := svc.UpdateTable(ctx, req.token, table)
+		if err != nil {
+			return nil, err
+		}
+		return updateTableRes{ID: id}, nil
+	}
+}
+
+func removeTableEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveTable(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeTableRes{}, nil
+	}
+}
+
+func openSessionEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(openSessionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.OpenSession(ctx, req.token, req.tableID, req.Guests)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{ID: id, location: "/sessions"}, nil
+	}
+}
+
+func viewSessionEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		session, err := svc.ViewSession(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewSessionRes{session}, nil
+	}
+}
+
+func activeSessionEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		session, err := svc.ActiveSession(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewSessionRes{session}, nil
+	}
+}
+
+func addOrderEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addOrderReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.AddOrder(ctx, req.token, req.sessionID, req.order)
+		if err != nil {
+			return nil, err
+		}
+		return createRes{ID: id, location: "/orders"}, nil
+	}
+}
+
+func requestBillEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RequestBill(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return sessionRes{}, nil
+	}
+}
+
+func moveSessionEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(moveSessionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.MoveSession(ctx, req.token, req.id, req.TableID); err != nil {
+			return nil, err
+		}
+		return sessionRes{}, nil
+	}
+}
+
+func mergeSessionsEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(mergeSessionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.MergeSessions(ctx, req.token, req.id, req.SessionID); err != nil {
+			return nil, err
+		}
+		return sessionRes{}, nil
+	}
+}
+
+func closeSessionEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		bill, err := svc.CloseSession(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return billRes{bill}, nil
+	}
+}
+
+func floorPlanEndpoint(svc tables.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(floorPlanReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		floor, err := svc.FloorPlan(ctx, req.token, req.vendor, req.area)
+		if err != nil {
+			return nil, err
+		}
+		return floorPlanRes{Tables: floor}, nil
+	}
+}
+
+func toViewTableRes(table tables.Table) viewTableRes {
+	return viewTableRes{
+		ID:        table.ID,
+		Vendor:    table.Vendor,
+		Number:    table.Number,
+		Area:      table.Area,
+		Seats:     table.Seats,
+		QRCode:    table.QRCode,
+		Metadata:  table.Metadata,
+		UpdatedAt: table.UpdatedAt,
+		CreatedAt: table.CreatedAt,
+	}
+}
@@ -0,0 +1,242 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/go-kit/log"
+)
+
+var _ tables.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    tables.Service
+}
+
+// LoggingMiddleware adds logging facilities to the tables service.
+func LoggingMiddleware(svc tables.Service, logger log.Logger) tables.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateTable(ctx context.Context, token string, table tables.Table) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_table",
+			"token", token,
+			"vendor", table.Vendor,
+			"number", table.Number,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateTable(ctx, token, table)
+}
+
+func (lm *loggingMiddleware) ViewTable(ctx context.Context, token, id string) (table tables.Table, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_table",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewTable(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListTables(ctx context.Context, token string, pm tables.PageMetadata) (page tables.TablesPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_tables",
+			"token", token,
+			"vendor", pm.Vendor,
+			"area", pm.Area,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListTables(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) UpdateTable(ctx context.Context, token string, table tables.Table) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_table",
+			"token", token,
+			"id", table.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateTable(ctx, token, table)
+}
+
+func (lm *loggingMiddleware) RemoveTable(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_table",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveTable(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RotateQRCode(ctx context.Context, token, id string) (table tables.Table, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "rotate_table_qr_code",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RotateQRCode(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) OpenSession(ctx context.Context, token, tableID string, guests uint64) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "open_table_session",
+			"token", token,
+			"table_id", tableID,
+			"guests", guests,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OpenSession(ctx, token, tableID, guests)
+}
+
+func (lm *loggingMiddleware) ViewSession(ctx context.Context, token, id string) (session tables.Session, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_table_session",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewSession(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ActiveSession(ctx context.Context, token, tableID string) (session tables.Session, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "active_table_session",
+			"token", token,
+			"table_id", tableID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ActiveSession(ctx, token, tableID)
+}
+
+func (lm *loggingMiddleware) AddOrder(ctx context.Context, token, sessionID string, order orders.Order) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "add_session_order",
+			"token", token,
+			"session_id", sessionID,
+			"order_id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.AddOrder(ctx, token, sessionID, order)
+}
+
+func (lm *loggingMiddleware) RequestBill(ctx context.Context, token, sessionID string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "request_session_bill",
+			"token", token,
+			"session_id", sessionID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RequestBill(ctx, token, sessionID)
+}
+
+func (lm *loggingMiddleware) MoveSession(ctx context.Context, token, sessionID, tableID string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "move_table_session",
+			"token", token,
+			"session_id", sessionID,
+			"table_id", tableID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.MoveSession(ctx, token, sessionID, tableID)
+}
+
+func (lm *loggingMiddleware) MergeSessions(ctx context.Context, token, targetID, sourceID string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "merge_table_sessions",
+			"token", token,
+			"target_id", targetID,
+			"source_id", sourceID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.MergeSessions(ctx, token, targetID, sourceID)
+}
+
+func (lm *loggingMiddleware) CloseSession(ctx context.Context, token, sessionID string) (bill tables.Bill, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "close_table_session",
+			"token", token,
+			"session_id", sessionID,
+			"total", bill.Total,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CloseSession(ctx, token, sessionID)
+}
+
+func (lm *loggingMiddleware) FloorPlan(ctx context.Context, token, vendor, area string) (floor []tables.TableStatus, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "floor_plan",
+			"token", token,
+			"vendor", vendor,
+			"area", area,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.FloorPlan(ctx, token, vendor, area)
+}
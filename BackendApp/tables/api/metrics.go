@@ -0,0 +1,165 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ tables.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     tables.Service
+}
+
+// MetricsMiddleware instruments the tables service by tracking request count
+// and latency.
+func MetricsMiddleware(svc tables.Service, counter metrics.Counter, latency metrics.Histogram) tables.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateTable(ctx context.Context, token string, table tables.Table) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_table").Add(1)
+		ms.latency.With("method", "create_table").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateTable(ctx, token, table)
+}
+
+func (ms *metricsMiddleware) ViewTable(ctx context.Context, token, id string) (tables.Table, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_table").Add(1)
+		ms.latency.With("method", "view_table").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewTable(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListTables(ctx context.Context, token string, pm tables.PageMetadata) (tables.TablesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_tables").Add(1)
+		ms.latency.With("method", "list_tables").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListTables(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) UpdateTable(ctx context.Context, token string, table tables.Table) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_table").Add(1)
+		ms.latency.With("method", "update_table").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateTable(ctx, token, table)
+}
+
+func (ms *metricsMiddleware) RemoveTable(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_table").Add(1)
+		ms.latency.With("method", "remove_table").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveTable(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RotateQRCode(ctx context.Context, token, id string) (tables.Table, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_table_qr_code").Add(1)
+		ms.latency.With("method", "rotate_table_qr_code").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateQRCode(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) OpenSession(ctx context.Context, token, tableID string, guests uint64) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "open_table_session").Add(1)
+		ms.latency.With("method", "open_table_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OpenSession(ctx, token, tableID, guests)
+}
+
+func (ms *metricsMiddleware) ViewSession(ctx context.Context, token, id string) (tables.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_table_session").Add(1)
+		ms.latency.With("method", "view_table_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSession(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ActiveSession(ctx context.Context, token, tableID string) (tables.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "active_table_session").Add(1)
+		ms.latency.With("method", "active_table_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ActiveSession(ctx, token, tableID)
+}
+
+func (ms *metricsMiddleware) AddOrder(ctx context.Context, token, sessionID string, order orders.Order) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_session_order").Add(1)
+		ms.latency.With("method", "add_session_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddOrder(ctx, token, sessionID, order)
+}
+
+func (ms *metricsMiddleware) RequestBill(ctx context.Context, token, sessionID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "request_session_bill").Add(1)
+		ms.latency.With("method", "request_session_bill").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RequestBill(ctx, token, sessionID)
+}
+
+func (ms *metricsMiddleware) MoveSession(ctx context.Context, token, sessionID, tableID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "move_table_session").Add(1)
+		ms.latency.With("method", "move_table_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.MoveSession(ctx, token, sessionID, tableID)
+}
+
+func (ms *metricsMiddleware) MergeSessions(ctx context.Context, token, targetID, sourceID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "merge_table_sessions").Add(1)
+		ms.latency.With("method", "merge_table_sessions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.MergeSessions(ctx, token, targetID, sourceID)
+}
+
+func (ms *metricsMiddleware) CloseSession(ctx context.Context, token, sessionID string) (tables.Bill, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "close_table_session").Add(1)
+		ms.latency.With("method", "close_table_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CloseSession(ctx, token, sessionID)
+}
+
+func (ms *metricsMiddleware) FloorPlan(ctx context.Context, token, vendor, area string) ([]tables.TableStatus, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "floor_plan").Add(1)
+		ms.latency.With("method", "floor_plan").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.FloorPlan(ctx, token, vendor, area)
+}
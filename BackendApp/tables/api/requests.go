@@ -0,0 +1,159 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+const (
+	maxLimitSize = 100
+)
+
+type createTableReq struct {
+	table tables.Table
+	token string
+}
+
+func (req createTableReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.table.Validate()
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listTablesReq struct {
+	token  string
+	vendor string
+	area   string
+	offset uint64
+	limit  uint64
+}
+
+func (req listTablesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.limit > maxLimitSize || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	return nil
+}
+
+type updateTableReq struct {
+	token    string
+	id       string
+	Number   uint64          `json:"number,omitempty"`
+	Area     string          `json:"area,omitempty"`
+	Seats    uint64          `json:"seats,omitempty"`
+	QRCode   string          `json:"qr_code,omitempty"`
+	Metadata tables.Metadata `json:"metadata,omitempty"`
+}
+
+func (req updateTableReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type openSessionReq struct {
+	token   string
+	tableID string
+	Guests  uint64 `json:"guests,omitempty"`
+}
+
+func (req openSessionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.tableID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type addOrderReq struct {
+	token     string
+	sessionID string
+	order     orders.Order
+}
+
+func (req addOrderReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.sessionID == "" {
+		return errors.ErrMissingID
+	}
+	if req.order.Name == "" && len(req.order.Items) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type moveSessionReq struct {
+	token   string
+	id      string
+	TableID string `json:"table_id"`
+}
+
+func (req moveSessionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" || req.TableID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type mergeSessionsReq struct {
+	token     string
+	id        string
+	SessionID string `json:"session_id"`
+}
+
+func (req mergeSessionsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" || req.SessionID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type floorPlanReq struct {
+	token  string
+	vendor string
+	area   string
+}
+
+func (req floorPlanReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
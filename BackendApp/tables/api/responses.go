@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createRes)(nil)
+	_ Response = (*viewTableRes)(nil)
+	_ Response = (*tablesPageRes)(nil)
+	_ Response = (*updateTableRes)(nil)
+	_ Response = (*removeTableRes)(nil)
+	_ Response = (*viewSessionRes)(nil)
+	_ Response = (*sessionRes)(nil)
+	_ Response = (*billRes)(nil)
+	_ Response = (*floorPlanRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+// createRes is returned when a table, session or order round is created.
+// The location is the path of the created resource.
+type createRes struct {
+	ID       string
+	location string
+}
+
+func (res createRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("%s/%s", res.location, res.ID),
+	}
+}
+
+func (res createRes) Empty() bool {
+	return true
+}
+
+type viewTableRes struct {
+	ID        string          `json:"id"`
+	Vendor    string          `json:"vendor"`
+	Number    uint64          `json:"number"`
+	Area      string          `json:"area,omitempty"`
+	Seats     uint64          `json:"seats"`
+	QRCode    string          `json:"qr_code"`
+	Metadata  tables.Metadata `json:"metadata,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at,omitempty"`
+}
+
+func (res viewTableRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewTableRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewTableRes) Empty() bool {
+	return false
+}
+
+type tablesPageRes struct {
+	pageRes
+	Tables []viewTableRes `json:"tables"`
+}
+
+func (res tablesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res tablesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res tablesPageRes) Empty() bool {
+	return false
+}
+
+type updateTableRes struct {
+	ID string
+}
+
+func (res updateTableRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateTableRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/tables/%s", res.ID),
+	}
+}
+
+func (res updateTableRes) Empty() bool {
+	return true
+}
+
+type removeTableRes struct{}
+
+func (res removeTableRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeTableRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeTableRes) Empty() bool {
+	return true
+}
+
+type viewSessionRes struct {
+	tables.Session
+}
+
+func (res viewSessionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSessionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSessionRes) Empty() bool {
+	return false
+}
+
+// sessionRes is returned by session state changes that have no content.
+type sessionRes struct{}
+
+func (res sessionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res sessionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res sessionRes) Empty() bool {
+	return true
+}
+
+type billRes struct {
+	tables.Bill
+}
+
+func (res billRes) Code() int {
+	return http.StatusOK
+}
+
+func (res billRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res billRes) Empty() bool {
+	return false
+}
+
+type floorPlanRes struct {
+	Tables []tables.TableStatus `json:"tables"`
+}
+
+func (res floorPlanRes) Code() int {
+	return http.StatusOK
+}
+
+func (res floorPlanRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res floorPlanRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	vendorKey   = "vendor"
+	areaKey     = "area"
+)
+
+// MakeTablesHandler returns a HTTP handler for tables and table sessions API
+// endpoints.
+func MakeTablesHandler(svc tables.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/tables").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_table")(createTableEndpoint(svc)),
+		decodeCreateTable,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tables/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_table")(viewTableEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tables").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_tables")(listTablesEndpoint(svc)),
+		decodeListTables,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/tables/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_table")(updateTableEndpoint(svc)),
+		decodeUpdateTable,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/tables/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_table")(removeTableEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/tables/{id}/sessions").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint open_table_session")(openSessionEndpoint(svc)),
+		decodeOpenSession,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/tables/{id}/session").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint active_table_session")(activeSessionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/sessions/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_table_session")(viewSessionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/sessions/{id}/orders").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint add_session_order")(addOrderEndpoint(svc)),
+		decodeAddOrder,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/sessions/{id}/bill").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint request_session_bill")(requestBillEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/sessions/{id}/move").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint move_table_session")(moveSessionEndpoint(svc)),
+		decodeMoveSession,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/sessions/{id}/merge").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint merge_table_sessions")(mergeSessionsEndpoint(svc)),
+		decodeMergeSessions,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/sessions/{id}/close").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint close_table_session")(closeSessionEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/floorplan").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint floor_plan")(floorPlanEndpoint(svc)),
+		decodeFloorPlan,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCreateTable(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var table tables.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createTableReq{
+		table: table,
+		token: decodeToken(r),
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeListTables(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listTablesReq{
+		token:  decodeToken(r),
+		limit:  maxLimitSize,
+		vendor: r.URL.Query().Get(vendorKey),
+		area:   r.URL.Query().Get(areaKey),
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func decodeUpdateTable(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := updateTableReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+// decodeOpenSession accepts an empty body, the number of guests is optional.
+func decodeOpenSession(_ context.Context, r *http.Request) (interface{}, error) {
+	req := openSessionReq{
+		token:   decodeToken(r),
+		tableID: mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeAddOrder(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var order orders.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := addOrderReq{
+		token:     decodeToken(r),
+		sessionID: mux.Vars(r)["id"],
+		order:     order,
+	}
+	return req, nil
+}
+
+func decodeMoveSession(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := moveSessionReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeMergeSessions(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	req := mergeSessionsReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return req, nil
+}
+
+func decodeFloorPlan(_ context.Context, r *http.Request) (interface{}, error) {
+	req := floorPlanReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+		area:   r.URL.Query().Get(areaKey),
+	}
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidStatus),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, tables.ErrTableOccupied),
+		errors.Contains(err, tables.ErrSessionClosed):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
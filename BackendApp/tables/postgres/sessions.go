@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/jmoiron/sqlx"
+)
+
+const sessionColumns = `id, vendor, table_id, guests, state, order_ids, merged_into, opened_at, updated_at, closed_at`
+
+var _ tables.SessionRepository = (*sessionsRepo)(nil)
+
+type sessionsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSessionsRepo instantiates a PostgreSQL
+// implementation of table sessions repository.
+func NewSessionsRepo(db *sqlx.DB) tables.SessionRepository {
+	return &sessionsRepo{
+		db: db,
+	}
+}
+
+func (repo sessionsRepo) Save(ctx context.Context, session tables.Session) (string, error) {
+	q := `INSERT INTO table_sessions (id, vendor, table_id, guests, state, order_ids, merged_into, opened_at, updated_at, closed_at)
+		  VALUES (:id, :vendor, :table_id, :guests, :state, :order_ids, :merged_into, :opened_at, :updated_at, :closed_at) RETURNING id`
+
+	dbs, err := toDBSession(session)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbs)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo sessionsRepo) RetrieveByID(ctx context.Context, id string) (tables.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM table_sessions WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo sessionsRepo) RetrieveActive(ctx context.Context, tableID string) (tables.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM table_sessions WHERE table_id = $1 AND state IN ('open', 'billing')`
+
+	return repo.retrieve(ctx, q, tableID)
+}
+
+func (repo sessionsRepo) retrieve(ctx context.Context, q string, arg interface{}) (tables.Session, error) {
+	dbs := dbSession{}
+	if err := repo.db.QueryRowxContext(ctx, q, arg).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return tables.Session{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return tables.Session{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toSession(dbs)
+}
+
+func (repo sessionsRepo) RetrieveAllActive(ctx context.Context, vendor string) ([]tables.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM table_sessions WHERE vendor = :vendor AND state IN ('open', 'billing') ORDER BY opened_at`
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, map[string]interface{}{"vendor": vendor})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var sessions []tables.Session
+	for rows.Next() {
+		dbs := dbSession{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		session, err := toSession(dbs)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (repo sessionsRepo) Update(ctx context.Context, session tables.Session) error {
+	q := `UPDATE table_sessions SET table_id = :table_id, guests = :guests, state = :state, merged_into = :merged_into,
+		  updated_at = :updated_at, closed_at = :closed_at WHERE id = :id`
+
+	dbs, err := toDBSession(session)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, dbs)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (repo sessionsRepo) AppendOrders(ctx context.Context, id string, orderIDs ...string) error {
+	q := `UPDATE table_sessions SET order_ids = order_ids || CAST(:order_ids AS JSONB), updated_at = :updated_at
+		  WHERE id = :id AND state IN ('open', 'billing')`
+
+	ids, err := json.Marshal(orderIDs)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	params := map[string]interface{}{
+		"id":         id,
+		"order_ids":  ids,
+		"updated_at": time.Now(),
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return tables.ErrSessionClosed
+	}
+	return nil
+}
+
+type dbSession struct {
+	ID         string         `db:"id"`
+	Vendor     string         `db:"vendor"`
+	TableID    string         `db:"table_id"`
+	Guests     uint64         `db:"guests"`
+	State      string         `db:"state"`
+	OrderIDs   []byte         `db:"order_ids"`
+	MergedInto sql.NullString `db:"merged_into"`
+	OpenedAt   time.Time      `db:"opened_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+	ClosedAt   sql.NullTime   `db:"closed_at"`
+}
+
+func toDBSession(session tables.Session) (dbSession, error) {
+	ids := session.OrderIDs
+	if ids == nil {
+		ids = []string{}
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return dbSession{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return dbSession{
+		ID:         session.ID,
+		Vendor:     session.Vendor,
+		TableID:    session.TableID,
+		Guests:     session.Guests,
+		State:      string(session.State),
+		OrderIDs:   data,
+		MergedInto: sql.NullString{String: session.MergedInto, Valid: session.MergedInto != ""},
+		OpenedAt:   session.OpenedAt,
+		UpdatedAt:  session.UpdatedAt,
+		ClosedAt:   sql.NullTime{Time: session.ClosedAt, Valid: !session.ClosedAt.IsZero()},
+	}, nil
+}
+
+func toSession(session dbSession) (tables.Session, error) {
+	ids := []string{}
+	if session.OrderIDs != nil {
+		if err := json.Unmarshal(session.OrderIDs, &ids); err != nil {
+			return tables.Session{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return tables.Session{
+		ID:         session.ID,
+		Vendor:     session.Vendor,
+		TableID:    session.TableID,
+		Guests:     session.Guests,
+		State:      tables.State(session.State),
+		OrderIDs:   ids,
+		MergedInto: session.MergedInto.String,
+		OpenedAt:   session.OpenedAt,
+		UpdatedAt:  session.UpdatedAt,
+		ClosedAt:   session.ClosedAt.Time,
+	}, nil
+}
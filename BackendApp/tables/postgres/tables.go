@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ tables.Repository = (*tablesRepo)(nil)
+
+type tablesRepo struct {
+	db *sqlx.DB
+}
+
+// NewTablesRepo instantiates a PostgreSQL
+// implementation of tables repository.
+func NewTablesRepo(db *sqlx.DB) tables.Repository {
+	return &tablesRepo{
+		db: db,
+	}
+}
+
+func (repo tablesRepo) Save(ctx context.Context, table tables.Table) (string, error) {
+	q := `INSERT INTO dining_tables (id, vendor, number, area, seats, qr_code, metadata, created_at, updated_at)
+		  VALUES (:id, :vendor, :number, :area, :seats, :qr_code, :metadata, :created_at, :updated_at) RETURNING id`
+
+	dbt, err := toDBTable(table)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbt)
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo tablesRepo) RetrieveByID(ctx context.Context, id string) (tables.Table, error) {
+	q := `SELECT id, vendor, number, area, seats, qr_code, metadata, created_at, updated_at FROM dining_tables WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo tablesRepo) RetrieveByQRCode(ctx context.Context, code string) (tables.Table, error) {
+	q := `SELECT id, vendor, number, area, seats, qr_code, metadata, created_at, updated_at FROM dining_tables WHERE qr_code = $1`
+
+	return repo.retrieve(ctx, q, code)
+}
+
+func (repo tablesRepo) retrieve(ctx context.Context, q string, arg interface{}) (tables.Table, error) {
+	dbt := dbTable{}
+	if err := repo.db.QueryRowxContext(ctx, q, arg).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return tables.Table{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return tables.Table{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toTable(dbt)
+}
+
+func (repo tablesRepo) RetrieveAll(ctx context.Context, pm tables.PageMetadata) (tables.TablesPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"area":   pm.Area,
+	}
+	if len(pm.Metadata) > 0 {
+		mp, err := json.Marshal(pm.Metadata)
+		if err != nil {
+			return tables.TablesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		params["metadata"] = mp
+		query = append(query, "metadata @> :metadata")
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Area != "" {
+		query = append(query, "area = :area")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT id, vendor, number, area, seats, qr_code, metadata, created_at, updated_at FROM dining_tables %s ORDER BY vendor, number LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return tables.TablesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var items []tables.Table
+	for rows.Next() {
+		dbt := dbTable{}
+		if err := rows.StructScan(&dbt); err != nil {
+			return tables.TablesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		table, err := toTable(dbt)
+		if err != nil {
+			return tables.TablesPage{}, err
+		}
+		items = append(items, table)
+	}
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM dining_tables %s;`, emq)
+
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return tables.TablesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := tables.TablesPage{
+		Tables: items,
+		PageMetadata: tables.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo tablesRepo) Update(ctx context.Context, table tables.Table) (string, error) {
+	q := `UPDATE dining_tables SET number = :number, area = :area, seats = :seats, qr_code = :qr_code,
+		  metadata = :metadata, updated_at = :updated_at WHERE id = :id RETURNING id`
+
+	dbt, err := toDBTable(table)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	row, err := repo.db.NamedQueryContext(ctx, q, dbt)
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo tablesRepo) Delete(ctx context.Context, id string) error {
+	q := `DELETE FROM dining_tables WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbTable{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbTable struct {
+	ID        string         `db:"id"`
+	Vendor    string         `db:"vendor"`
+	Number    uint64         `db:"number"`
+	Area      sql.NullString `db:"area"`
+	Seats     uint64         `db:"seats"`
+	QRCode    string         `db:"qr_code"`
+	Metadata  []byte         `db:"metadata"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func toDBTable(table tables.Table) (dbTable, error) {
+	data := []byte("{}")
+	if len(table.Metadata) > 0 {
+		b, err := json.Marshal(table.Metadata)
+		if err != nil {
+			return dbTable{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		data = b
+	}
+	return dbTable{
+		ID:        table.ID,
+		Vendor:    table.Vendor,
+		Number:    table.Number,
+		Area:      sql.NullString{String: table.Area, Valid: table.Area != ""},
+		Seats:     table.Seats,
+		QRCode:    table.QRCode,
+		Metadata:  data,
+		CreatedAt: table.CreatedAt,
+		UpdatedAt: table.UpdatedAt,
+	}, nil
+}
+
+func toTable(table dbTable) (tables.Table, error) {
+	var metadata map[string]interface{}
+	if table.Metadata != nil {
+		if err := json.Unmarshal(table.Metadata, &metadata); err != nil {
+			return tables.Table{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return tables.Table{
+		ID:        table.ID,
+		Vendor:    table.Vendor,
+		Number:    table.Number,
+		Area:      table.Area.String,
+		Seats:     table.Seats,
+		QRCode:    table.QRCode,
+		Metadata:  metadata,
+		CreatedAt: table.CreatedAt,
+		UpdatedAt: table.UpdatedAt,
+	}, nil
+}
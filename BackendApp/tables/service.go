@@ -0,0 +1,399 @@
+package tables
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	inhouse      = "inhouse"
+	qrCodeSize   = 16
+	floorMaxSize = 1000
+)
+
+var _ Service = (*tablesService)(nil)
+
+type tablesService struct {
+	tables   Repository
+	sessions SessionRepository
+	orders   orders.OrderService
+}
+
+// NewService instantiates the tables service implementation.
+func NewService(tables Repository, sessions SessionRepository, ordersSvc orders.OrderService) Service {
+	return &tablesService{
+		tables:   tables,
+		sessions: sessions,
+		orders:   ordersSvc,
+	}
+}
+
+func (svc tablesService) CreateTable(ctx context.Context, token string, table Table) (string, error) {
+	if err := table.Validate(); err != nil {
+		return "", err
+	}
+	if table.QRCode == "" {
+		code, err := qrCode()
+		if err != nil {
+			return "", errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		table.QRCode = code
+	}
+	table.ID = ulid.Make().String()
+	table.CreatedAt = time.Now()
+	table.UpdatedAt = time.Now()
+	return svc.tables.Save(ctx, table)
+}
+
+func (svc tablesService) ViewTable(ctx context.Context, token, id string) (Table, error) {
+	return svc.tables.RetrieveByID(ctx, id)
+}
+
+func (svc tablesService) ListTables(ctx context.Context, token string, pm PageMetadata) (TablesPage, error) {
+	return svc.tables.RetrieveAll(ctx, pm)
+}
+
+func (svc tablesService) UpdateTable(ctx context.Context, token string, table Table) (string, error) {
+	current, err := svc.tables.RetrieveByID(ctx, table.ID)
+	if err != nil {
+		return "", err
+	}
+	if table.Number != 0 {
+		current.Number = table.Number
+	}
+	if table.Area != "" {
+		current.Area = table.Area
+	}
+	if table.Seats != 0 {
+		current.Seats = table.Seats
+	}
+	if table.QRCode != "" {
+		current.QRCode = table.QRCode
+	}
+	if table.Metadata != nil {
+		current.Metadata = table.Metadata
+	}
+	current.UpdatedAt = time.Now()
+	return svc.tables.Update(ctx, current)
+}
+
+func (svc tablesService) RemoveTable(ctx context.Context, token, id string) error {
+	_, err := svc.sessions.RetrieveActive(ctx, id)
+	switch {
+	case err == nil:
+		return ErrTableOccupied
+	case !errors.Contains(err, errors.ErrNotFound):
+		return err
+	}
+	return svc.tables.Delete(ctx, id)
+}
+
+func (svc tablesService) RotateQRCode(ctx context.Context, token, id string) (Table, error) {
+	table, err := svc.tables.RetrieveByID(ctx, id)
+	if err != nil {
+		return Table{}, err
+	}
+	code, err := qrCode()
+	if err != nil {
+		return Table{}, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	table.QRCode = code
+	table.UpdatedAt = time.Now()
+	if _, err := svc.tables.Update(ctx, table); err != nil {
+		return Table{}, err
+	}
+	return table, nil
+}
+
+func (svc tablesService) OpenSession(ctx context.Context, token, tableID string, guests uint64) (string, error) {
+	table, err := svc.tables.RetrieveByID(ctx, tableID)
+	if err != nil {
+		return "", err
+	}
+	if err := svc.free(ctx, table.ID); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	session := Session{
+		ID:        ulid.Make().String(),
+		Vendor:    table.Vendor,
+		TableID:   table.ID,
+		Guests:    guests,
+		State:     StateOpen,
+		OrderIDs:  []string{},
+		OpenedAt:  now,
+		UpdatedAt: now,
+	}
+	id, err := svc.sessions.Save(ctx, session)
+	if errors.Contains(err, errors.ErrConflict) {
+		return "", ErrTableOccupied
+	}
+	return id, err
+}
+
+func (svc tablesService) ViewSession(ctx context.Context, token, id string) (Session, error) {
+	return svc.sessions.RetrieveByID(ctx, id)
+}
+
+func (svc tablesService) ActiveSession(ctx context.Context, token, tableID string) (Session, error) {
+	return svc.sessions.RetrieveActive(ctx, tableID)
+}
+
+func (svc tablesService) AddOrder(ctx context.Context, token, sessionID string, order orders.Order) (string, error) {
+	session, err := svc.active(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	table, err := svc.tables.RetrieveByID(ctx, session.TableID)
+	if err != nil {
+		return "", err
+	}
+	if order.Metadata == nil {
+		order.Metadata = orders.Metadata{}
+	}
+	order.Metadata[TableKey] = table.Number
+	order.Metadata[SessionKey] = session.ID
+	order.Vendor = session.Vendor
+	order.Place = inhouse
+	if order.Status == "" {
+		order.Status = "ordered"
+	}
+	id, err := svc.orders.CreateOrder(ctx, token, order)
+	if err != nil {
+		return "", err
+	}
+	if err := svc.sessions.AppendOrders(ctx, session.ID, id); err != nil {
+		return "", err
+	}
+	// Another round reopens a session that was waiting for its bill.
+	if session.State == StateBilling {
+		session.State = StateOpen
+		session.UpdatedAt = time.Now()
+		if err := svc.sessions.Update(ctx, session); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (svc tablesService) RequestBill(ctx context.Context, token, sessionID string) error {
+	session, err := svc.active(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	session.State = StateBilling
+	session.UpdatedAt = time.Now()
+	return svc.sessions.Update(ctx, session)
+}
+
+func (svc tablesService) MoveSession(ctx context.Context, token, sessionID, tableID string) error {
+	session, err := svc.active(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.TableID == tableID {
+		return nil
+	}
+	table, err := svc.tables.RetrieveByID(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if table.Vendor != session.Vendor {
+		return errors.ErrMalformedEntity
+	}
+	if err := svc.free(ctx, table.ID); err != nil {
+		return err
+	}
+	session.TableID = table.ID
+	session.UpdatedAt = time.Now()
+	if err := svc.sessions.Update(ctx, session); err != nil {
+		if errors.Contains(err, errors.ErrConflict) {
+			return ErrTableOccupied
+		}
+		return err
+	}
+	return nil
+}
+
+func (svc tablesService) MergeSessions(ctx context.Context, token, targetID, sourceID string) error {
+	if targetID == sourceID {
+		return errors.ErrMalformedEntity
+	}
+	target, err := svc.active(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	source, err := svc.active(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if target.Vendor != source.Vendor {
+		return errors.ErrMalformedEntity
+	}
+
+	// Close the source first so no round can be added to it while its
+	// orders are being moved.
+	now := time.Now()
+	source.State = StateMerged
+	source.MergedInto = target.ID
+	source.UpdatedAt = now
+	source.ClosedAt = now
+	if err := svc.sessions.Update(ctx, source); err != nil {
+		return err
+	}
+	if len(source.OrderIDs) > 0 {
+		if err := svc.sessions.AppendOrders(ctx, target.ID, source.OrderIDs...); err != nil {
+			return err
+		}
+	}
+	target, err = svc.sessions.RetrieveByID(ctx, target.ID)
+	if err != nil {
+		return err
+	}
+	target.Guests += source.Guests
+	target.UpdatedAt = now
+	return svc.sessions.Update(ctx, target)
+}
+
+func (svc tablesService) CloseSession(ctx context.Context, token, sessionID string) (Bill, error) {
+	session, err := svc.active(ctx, sessionID)
+	if err != nil {
+		return Bill{}, err
+	}
+	table, err := svc.tables.RetrieveByID(ctx, session.TableID)
+	if err != nil {
+		return Bill{}, err
+	}
+	bill := Bill{
+		SessionID:   session.ID,
+		TableID:     table.ID,
+		TableNumber: table.Number,
+		Orders:      []orders.Order{},
+		Items:       []orders.Item{},
+	}
+	for _, id := range session.OrderIDs {
+		order, err := svc.orders.ViewOrder(ctx, token, id)
+		if err != nil {
+			return Bill{}, err
+		}
+		bill.Orders = append(bill.Orders, order)
+		bill.Total += order.Price
+		bill.Items = combine(bill.Items, lines(order)...)
+	}
+
+	now := time.Now()
+	session.State = StateClosed
+	session.UpdatedAt = now
+	session.ClosedAt = now
+	if err := svc.sessions.Update(ctx, session); err != nil {
+		return Bill{}, err
+	}
+	bill.ClosedAt = now
+	return bill, nil
+}
+
+func (svc tablesService) FloorPlan(ctx context.Context, token, vendor, area string) ([]TableStatus, error) {
+	page, err := svc.tables.RetrieveAll(ctx, PageMetadata{
+		Limit:  floorMaxSize,
+		Vendor: vendor,
+		Area:   area,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := svc.sessions.RetrieveAllActive(ctx, vendor)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[string]Session, len(sessions))
+	for _, session := range sessions {
+		active[session.TableID] = session
+	}
+
+	floor := make([]TableStatus, 0, len(page.Tables))
+	for _, table := range page.Tables {
+		ts := TableStatus{Table: table, Status: StatusFree}
+		if session, ok := active[table.ID]; ok {
+			ts.SessionID = session.ID
+			ts.Guests = session.Guests
+			ts.Orders = len(session.OrderIDs)
+			switch {
+			case session.State == StateBilling:
+				ts.Status = StatusAwaitingBill
+			case len(session.OrderIDs) > 0:
+				ts.Status = StatusOrdered
+			default:
+				ts.Status = StatusSeated
+			}
+		}
+		floor = append(floor, ts)
+	}
+	return floor, nil
+}
+
+// active retrieves a session guests are still seated in.
+func (svc tablesService) active(ctx context.Context, id string) (Session, error) {
+	session, err := svc.sessions.RetrieveByID(ctx, id)
+	if err != nil {
+		return Session{}, err
+	}
+	if !session.Active() {
+		return Session{}, ErrSessionClosed
+	}
+	return session, nil
+}
+
+// free returns ErrTableOccupied if the table has an active session.
+func (svc tablesService) free(ctx context.Context, tableID string) error {
+	_, err := svc.sessions.RetrieveActive(ctx, tableID)
+	switch {
+	case err == nil:
+		return ErrTableOccupied
+	case errors.Contains(err, errors.ErrNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+// lines returns the bill lines of an order. Orders placed without item
+// lines are billed as a single line.
+func lines(order orders.Order) []orders.Item {
+	if len(order.Items) > 0 {
+		return order.Items
+	}
+	return []orders.Item{{Name: order.Name, Quantity: 1, Price: order.Price}}
+}
+
+// combine adds the lines to the bill, merging lines of the same item sold
+// at the same price.
+func combine(bill []orders.Item, lines ...orders.Item) []orders.Item {
+	for _, line := range lines {
+		merged := false
+		for i := range bill {
+			if bill[i].ID == line.ID && bill[i].Name == line.Name && bill[i].Price == line.Price {
+				bill[i].Quantity += line.Quantity
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			bill = append(bill, line)
+		}
+	}
+	return bill
+}
+
+func qrCode() (string, error) {
+	b := make([]byte, qrCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
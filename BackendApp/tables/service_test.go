@@ -0,0 +1,299 @@
+package tables_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/tables"
+)
+
+const (
+	token  = "token"
+	vendor = "vendor"
+)
+
+var (
+	chapati = orders.Item{ID: "chapati", Name: "Chapati", Quantity: 1, Price: 30}
+	chai    = orders.Item{ID: "chai", Name: "Chai", Quantity: 1, Price: 50}
+)
+
+type tableRepo struct {
+	tables.Repository
+	mu     sync.Mutex
+	tables map[string]tables.Table
+}
+
+func (repo *tableRepo) Save(_ context.Context, table tables.Table) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.tables[table.ID] = table
+	return table.ID, nil
+}
+
+func (repo *tableRepo) RetrieveByID(_ context.Context, id string) (tables.Table, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	table, ok := repo.tables[id]
+	if !ok {
+		return tables.Table{}, errors.ErrNotFound
+	}
+	return table, nil
+}
+
+// sessionRepo keeps sessions in memory, refusing a second active session
+// for a table as the postgres repository does.
+type sessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]tables.Session
+}
+
+func (repo *sessionRepo) occupied(session tables.Session) bool {
+	for _, s := range repo.sessions {
+		if s.ID != session.ID && s.TableID == session.TableID && s.Active() && session.Active() {
+			return true
+		}
+	}
+	return false
+}
+
+func (repo *sessionRepo) Save(_ context.Context, session tables.Session) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.occupied(session) {
+		return "", errors.ErrConflict
+	}
+	repo.sessions[session.ID] = session
+	return session.ID, nil
+}
+
+func (repo *sessionRepo) RetrieveByID(_ context.Context, id string) (tables.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	session, ok := repo.sessions[id]
+	if !ok {
+		return tables.Session{}, errors.ErrNotFound
+	}
+	session.OrderIDs = append([]string{}, session.OrderIDs...)
+	return session, nil
+}
+
+func (repo *sessionRepo) RetrieveActive(_ context.Context, tableID string) (tables.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, session := range repo.sessions {
+		if session.TableID == tableID && session.Active() {
+			session.OrderIDs = append([]string{}, session.OrderIDs...)
+			return session, nil
+		}
+	}
+	return tables.Session{}, errors.ErrNotFound
+}
+
+func (repo *sessionRepo) RetrieveAllActive(_ context.Context, vendor string) ([]tables.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var sessions []tables.Session
+	for _, session := range repo.sessions {
+		if session.Vendor == vendor && session.Active() {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (repo *sessionRepo) Update(_ context.Context, session tables.Session) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, ok := repo.sessions[session.ID]; !ok {
+		return errors.ErrNotFound
+	}
+	if repo.occupied(session) {
+		return errors.ErrConflict
+	}
+	repo.sessions[session.ID] = session
+	return nil
+}
+
+func (repo *sessionRepo) AppendOrders(_ context.Context, id string, orderIDs ...string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	session, ok := repo.sessions[id]
+	if !ok || !session.Active() {
+		return errors.ErrNotFound
+	}
+	session.OrderIDs = append(session.OrderIDs, orderIDs...)
+	repo.sessions[id] = session
+	return nil
+}
+
+func newService() tables.Service {
+	return tables.NewService(
+		&tableRepo{tables: map[string]tables.Table{}},
+		&sessionRepo{sessions: map[string]tables.Session{}},
+		orders.NewOrderService(memory.NewOrderRepo()),
+	)
+}
+
+func createTable(t *testing.T, svc tables.Service, vendor string, number uint64) string {
+	id, err := svc.CreateTable(context.Background(), token, tables.Table{Vendor: vendor, Number: number})
+	if err != nil {
+		t.Fatalf("creating table %d: unexpected error: %s", number, err)
+	}
+	return id
+}
+
+func openSession(t *testing.T, svc tables.Service, tableID string, guests uint64, rounds ...orders.Order) string {
+	ctx := context.Background()
+	id, err := svc.OpenSession(ctx, token, tableID, guests)
+	if err != nil {
+		t.Fatalf("opening a session: unexpected error: %s", err)
+	}
+	for _, round := range rounds {
+		if _, err := svc.AddOrder(ctx, token, id, round); err != nil {
+			t.Fatalf("adding a round: unexpected error: %s", err)
+		}
+	}
+	return id
+}
+
+func TestMoveSession(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	one := createTable(t, svc, vendor, 1)
+	two := createTable(t, svc, vendor, 2)
+	three := createTable(t, svc, vendor, 3)
+	other := createTable(t, svc, "other", 1)
+	session := openSession(t, svc, one, 2, orders.Order{Items: []orders.Item{chapati}})
+	openSession(t, svc, three, 4)
+
+	cases := []struct {
+		desc  string
+		table string
+		err   error
+	}{
+		{desc: "move to an occupied table", table: three, err: tables.ErrTableOccupied},
+		{desc: "move to a table of another vendor", table: other, err: errors.ErrMalformedEntity},
+		{desc: "move to an unknown table", table: "unknown", err: errors.ErrNotFound},
+		{desc: "move to the same table", table: one},
+		{desc: "move to a free table", table: two},
+	}
+	for _, tc := range cases {
+		if err := svc.MoveSession(ctx, token, session, tc.table); !errors.Contains(err, tc.err) {
+			t.Errorf("%s: got error %v, want %v", tc.desc, err, tc.err)
+		}
+	}
+
+	if _, err := svc.ActiveSession(ctx, token, one); !errors.Contains(err, errors.ErrNotFound) {
+		t.Errorf("moving a session: got error %v for the table left, want %s", err, errors.ErrNotFound)
+	}
+	moved, err := svc.ActiveSession(ctx, token, two)
+	if err != nil {
+		t.Fatalf("moving a session: unexpected error: %s", err)
+	}
+	if moved.ID != session || len(moved.OrderIDs) != 1 {
+		t.Errorf("moving a session: got session %s with %d orders, want %s with 1", moved.ID, len(moved.OrderIDs), session)
+	}
+
+	// Rounds placed after the move carry the number of the new table.
+	id, err := svc.AddOrder(ctx, token, session, orders.Order{Items: []orders.Item{chai}})
+	if err != nil {
+		t.Fatalf("adding a round after moving: unexpected error: %s", err)
+	}
+	bill, err := svc.CloseSession(ctx, token, session)
+	if err != nil {
+		t.Fatalf("closing a moved session: unexpected error: %s", err)
+	}
+	if bill.TableID != two || bill.TableNumber != 2 || len(bill.Orders) != 2 {
+		t.Errorf("closing a moved session: got a bill of table %d with %d orders, want table 2 with 2", bill.TableNumber, len(bill.Orders))
+	}
+	for _, order := range bill.Orders {
+		if order.ID == id && order.Metadata[tables.TableKey] != float64(2) {
+			t.Errorf("adding a round after moving: got table %v, want 2", order.Metadata[tables.TableKey])
+		}
+	}
+	if err := svc.MoveSession(ctx, token, session, one); !errors.Contains(err, tables.ErrSessionClosed) {
+		t.Errorf("moving a closed session: got error %v, want %s", err, tables.ErrSessionClosed)
+	}
+}
+
+func TestMergeSessions(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	one := createTable(t, svc, vendor, 1)
+	two := createTable(t, svc, vendor, 2)
+	other := createTable(t, svc, "other", 1)
+
+	twoChapati := chapati
+	twoChapati.Quantity = 2
+	target := openSession(t, svc, one, 2, orders.Order{Items: []orders.Item{twoChapati}})
+	source := openSession(t, svc, two, 3,
+		orders.Order{Items: []orders.Item{chapati, chai}},
+		orders.Order{Name: "Corkage", Price: 200},
+	)
+	foreign := openSession(t, svc, other, 1)
+
+	cases := []struct {
+		desc   string
+		target string
+		source string
+		err    error
+	}{
+		{desc: "merge a session into itself", target: target, source: target, err: errors.ErrMalformedEntity},
+		{desc: "merge a session of another vendor", target: target, source: foreign, err: errors.ErrMalformedEntity},
+		{desc: "merge an unknown session", target: target, source: "unknown", err: errors.ErrNotFound},
+		{desc: "merge a session", target: target, source: source},
+		{desc: "merge a merged session", target: target, source: source, err: tables.ErrSessionClosed},
+	}
+	for _, tc := range cases {
+		if err := svc.MergeSessions(ctx, token, tc.target, tc.source); !errors.Contains(err, tc.err) {
+			t.Errorf("%s: got error %v, want %v", tc.desc, err, tc.err)
+		}
+	}
+
+	merged, err := svc.ViewSession(ctx, token, source)
+	if err != nil {
+		t.Fatalf("viewing the merged session: unexpected error: %s", err)
+	}
+	if merged.State != tables.StateMerged || merged.MergedInto != target {
+		t.Errorf("merging sessions: got the source %s into %q, want %s into %s", merged.State, merged.MergedInto, tables.StateMerged, target)
+	}
+	if _, err := svc.ActiveSession(ctx, token, two); !errors.Contains(err, errors.ErrNotFound) {
+		t.Errorf("merging sessions: got error %v for the source table, want %s", err, errors.ErrNotFound)
+	}
+	if _, err := svc.AddOrder(ctx, token, source, orders.Order{Items: []orders.Item{chai}}); !errors.Contains(err, tables.ErrSessionClosed) {
+		t.Errorf("adding a round to a merged session: got error %v, want %s", err, tables.ErrSessionClosed)
+	}
+	session, err := svc.ViewSession(ctx, token, target)
+	if err != nil {
+		t.Fatalf("viewing the target session: unexpected error: %s", err)
+	}
+	if session.Guests != 5 || len(session.OrderIDs) != 3 {
+		t.Errorf("merging sessions: got %d guests and %d orders, want 5 and 3", session.Guests, len(session.OrderIDs))
+	}
+
+	// The combined bill lists every round and sums the lines of the same
+	// item, the round without item lines is billed as one line.
+	bill, err := svc.CloseSession(ctx, token, target)
+	if err != nil {
+		t.Fatalf("closing the merged session: unexpected error: %s", err)
+	}
+	if len(bill.Orders) != 3 || bill.Total != 3*30+50+200 {
+		t.Errorf("closing the merged session: got %d orders for %d, want 3 for %d", len(bill.Orders), bill.Total, 3*30+50+200)
+	}
+	want := map[string]uint64{"Chapati": 3, "Chai": 1, "Corkage": 1}
+	if len(bill.Items) != len(want) {
+		t.Errorf("closing the merged session: got %d lines, want %d", len(bill.Items), len(want))
+	}
+	for _, line := range bill.Items {
+		if line.Quantity != want[line.Name] {
+			t.Errorf("closing the merged session: got %d %s, want %d", line.Quantity, line.Name, want[line.Name])
+		}
+	}
+	if _, err := svc.CloseSession(ctx, token, target); !errors.Contains(err, tables.ErrSessionClosed) {
+		t.Errorf("closing a closed session: got error %v, want %s", err, tables.ErrSessionClosed)
+	}
+}
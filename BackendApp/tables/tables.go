@@ -0,0 +1,232 @@
+// Package tables manages a vendor's dining tables and the dine-in sessions
+// that group the order rounds served at them.
+package tables
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// Metadata keys set on orders placed through a table session.
+const (
+	TableKey   = "table"
+	SessionKey = "table_session"
+)
+
+var (
+	// ErrTableOccupied indicates that the table already has an active session.
+	ErrTableOccupied = errors.New("table already has an active session")
+
+	// ErrSessionClosed indicates an operation on a closed or merged session.
+	ErrSessionClosed = errors.New("table session is closed")
+)
+
+// Metadata to be used for customized
+// describing of particular table.
+type Metadata map[string]interface{}
+
+// Status is the state of a table on the floor plan.
+type Status string
+
+const (
+	// StatusFree is a table without an active session.
+	StatusFree Status = "free"
+	// StatusSeated is a table whose guests have not ordered yet.
+	StatusSeated Status = "seated"
+	// StatusOrdered is a table with at least one order round.
+	StatusOrdered Status = "ordered"
+	// StatusAwaitingBill is a table whose guests asked for the bill.
+	StatusAwaitingBill Status = "awaiting_bill"
+)
+
+// State is the lifecycle state of a table session.
+type State string
+
+const (
+	// StateOpen is a session guests can keep ordering on.
+	StateOpen State = "open"
+	// StateBilling is a session whose guests asked for the bill.
+	StateBilling State = "billing"
+	// StateClosed is a session that has been billed.
+	StateClosed State = "closed"
+	// StateMerged is a session whose orders were moved into another session.
+	StateMerged State = "merged"
+)
+
+// Table is a table guests are seated at.
+type Table struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`     // The name of the vendor the table belongs to.
+	Number    uint64    `json:"number,omitempty"`     // The number of the table, unique per vendor.
+	Area      string    `json:"area,omitempty"`       // The area the table is in i.e. terrace.
+	Seats     uint64    `json:"seats,omitempty"`      // How many guests the table seats.
+	QRCode    string    `json:"qr_code,omitempty"`    // The code printed on the table's QR sticker.
+	Metadata  Metadata  `json:"metadata,omitempty"`   // Metadata contains extra information about the table.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the table was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the table was created in the system.
+}
+
+// Session is a party's stay at a table. Every round the party orders is a
+// separate order and the session keeps them together for a single bill.
+type Session struct {
+	ID         string    `json:"id"`
+	Vendor     string    `json:"vendor"`
+	TableID    string    `json:"table_id"`
+	Guests     uint64    `json:"guests,omitempty"`
+	State      State     `json:"state"`
+	OrderIDs   []string  `json:"order_ids"`
+	MergedInto string    `json:"merged_into,omitempty"` // The session the orders were moved to when merged.
+	OpenedAt   time.Time `json:"opened_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ClosedAt   time.Time `json:"closed_at,omitempty"`
+}
+
+// Active reports whether guests are still at the table.
+func (s Session) Active() bool {
+	return s.State == StateOpen || s.State == StateBilling
+}
+
+// Bill is the combined bill of every order round of a session.
+type Bill struct {
+	SessionID   string         `json:"session_id"`
+	TableID     string         `json:"table_id"`
+	TableNumber uint64         `json:"table_number"`
+	Orders      []orders.Order `json:"orders"`
+	Items       []orders.Item  `json:"items"` // The lines of all rounds with the same items combined.
+	Total       uint64         `json:"total"`
+	ClosedAt    time.Time      `json:"closed_at"`
+}
+
+// TableStatus is a table as it appears on the floor plan.
+type TableStatus struct {
+	Table
+	Status    Status `json:"status"`
+	SessionID string `json:"session_id,omitempty"`
+	Guests    uint64 `json:"guests,omitempty"`
+	Orders    int    `json:"orders"`
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Vendor   string
+	Area     string
+	Metadata Metadata
+}
+
+// TablesPage contains a page of tables.
+type TablesPage struct {
+	PageMetadata
+	Tables []Table
+}
+
+// Service describes the management of tables and dine-in sessions.
+type Service interface {
+	// CreateTable adds a table. A QR code is generated if none is given.
+	CreateTable(ctx context.Context, token string, table Table) (string, error)
+
+	// ViewTable retrieves a Table by its unique identifier ID.
+	ViewTable(ctx context.Context, token string, id string) (Table, error)
+
+	// ListTables retrieves all tables for a given pageMetadata.
+	ListTables(ctx context.Context, token string, pm PageMetadata) (TablesPage, error)
+
+	// UpdateTable updates the number, area, seats and metadata of a table.
+	UpdateTable(ctx context.Context, token string, table Table) (string, error)
+
+	// RemoveTable removes a table that has no active session.
+	RemoveTable(ctx context.Context, token string, id string) error
+
+	// RotateQRCode replaces the QR code of a table so previously printed
+	// codes stop working.
+	RotateQRCode(ctx context.Context, token string, id string) (Table, error)
+
+	// OpenSession seats guests at a free table and returns the session ID.
+	OpenSession(ctx context.Context, token string, tableID string, guests uint64) (string, error)
+
+	// ViewSession retrieves a Session by its unique identifier ID.
+	ViewSession(ctx context.Context, token string, id string) (Session, error)
+
+	// ActiveSession retrieves the open or billing session of a table. If
+	// the table is free errors.ErrNotFound is returned.
+	ActiveSession(ctx context.Context, token string, tableID string) (Session, error)
+
+	// AddOrder places an order round on the session and returns its ID.
+	AddOrder(ctx context.Context, token string, sessionID string, order orders.Order) (string, error)
+
+	// RequestBill marks the session as waiting for its bill.
+	RequestBill(ctx context.Context, token string, sessionID string) error
+
+	// MoveSession moves the session to another, free, table.
+	MoveSession(ctx context.Context, token string, sessionID, tableID string) error
+
+	// MergeSessions moves the guests and orders of the source session into
+	// the target session and frees the source table.
+	MergeSessions(ctx context.Context, token string, targetID, sourceID string) error
+
+	// CloseSession closes the session, freeing the table, and returns the
+	// combined bill of all its orders.
+	CloseSession(ctx context.Context, token string, sessionID string) (Bill, error)
+
+	// FloorPlan returns the status of every table of the vendor, optionally
+	// only those in an area.
+	FloorPlan(ctx context.Context, token string, vendor, area string) ([]TableStatus, error)
+}
+
+// Repository specifies a table persistence API.
+type Repository interface {
+	// Save persists the table.
+	Save(ctx context.Context, table Table) (string, error)
+
+	// RetrieveByID retrieves a table by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Table, error)
+
+	// RetrieveByQRCode retrieves the table carrying the QR code.
+	RetrieveByQRCode(ctx context.Context, code string) (Table, error)
+
+	// RetrieveAll retrieves all tables for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (TablesPage, error)
+
+	// Update replaces the stored table with the given one.
+	Update(ctx context.Context, table Table) (string, error)
+
+	// Delete deletes the table and its session history.
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionRepository specifies a table session persistence API.
+type SessionRepository interface {
+	// Save persists the session. Saving a second active session for a
+	// table returns errors.ErrConflict.
+	Save(ctx context.Context, session Session) (string, error)
+
+	// RetrieveByID retrieves a session by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Session, error)
+
+	// RetrieveActive retrieves the open or billing session of a table. If
+	// the table is free errors.ErrNotFound is returned.
+	RetrieveActive(ctx context.Context, tableID string) (Session, error)
+
+	// RetrieveAllActive retrieves the open and billing sessions of a vendor.
+	RetrieveAllActive(ctx context.Context, vendor string) ([]Session, error)
+
+	// Update replaces the stored session with the given one.
+	Update(ctx context.Context, session Session) error
+
+	// AppendOrders adds order IDs to an active session in a single step so
+	// rounds placed at the same time are not lost.
+	AppendOrders(ctx context.Context, id string, orderIDs ...string) error
+}
+
+// Validate returns an error if table representation is invalid.
+func (table Table) Validate() error {
+	if table.Vendor == "" || table.Number == 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func createRateEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.CreateRate(ctx, req.token, req.rate)
+		if err != nil {
+			return nil, err
+		}
+		return createRateRes{ID: id}, nil
+	}
+}
+
+func viewRateEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		rate, err := svc.ViewRate(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return viewRateRes{Rate: rate}, nil
+	}
+}
+
+func listRatesEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRatesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		rates, err := svc.ListRates(ctx, req.token, req.vendor)
+		if err != nil {
+			return nil, err
+		}
+		res := ratesRes{Rates: []viewRateRes{}}
+		for _, rate := range rates {
+			res.Rates = append(res.Rates, viewRateRes{Rate: rate})
+		}
+		return res, nil
+	}
+}
+
+func updateRateEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateRateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		id, err := svc.UpdateRate(ctx, req.token, req.rate)
+		if err != nil {
+			return nil, err
+		}
+		return updateRateRes{ID: id}, nil
+	}
+}
+
+func removeRateEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RemoveRate(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRateRes{}, nil
+	}
+}
+
+func issueInvoiceEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		inv, err := svc.IssueInvoice(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return toInvoiceRes(inv), nil
+	}
+}
+
+func viewInvoiceEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		inv, err := svc.ViewInvoice(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return toInvoiceRes(inv), nil
+	}
+}
+
+func listInvoicesEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listInvoicesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		pm := tax.PageMetadata{
+			Offset: req.offset,
+			Limit:  req.limit,
+			Vendor: req.vendor,
+			Status: tax.Status(req.status),
+			From:   req.from,
+			To:     req.to,
+		}
+		page, err := svc.ListInvoices(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+		if req.csv {
+			return registerRes{invoices: page.Invoices}, nil
+		}
+		res := invoicesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Invoices: []invoiceRes{},
+		}
+		for _, inv := range page.Invoices {
+			res.Invoices = append(res.Invoices, toInvoiceRes(inv))
+		}
+		return res, nil
+	}
+}
+
+func submitInvoiceEndpoint(svc tax.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		inv, err := svc.SubmitInvoice(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		return toInvoiceRes(inv), nil
+	}
+}
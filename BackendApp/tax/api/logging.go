@@ -0,0 +1,197 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/go-kit/log"
+)
+
+var _ tax.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    tax.Service
+}
+
+// LoggingMiddleware adds logging facilities to the tax service.
+func LoggingMiddleware(svc tax.Service, logger log.Logger) tax.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateRate(ctx context.Context, token string, rate tax.Rate) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "create_tax_rate",
+			"token", token,
+			"vendor", rate.Vendor,
+			"kind", rate.Kind,
+			"rate", rate.Rate,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.CreateRate(ctx, token, rate)
+}
+
+func (lm *loggingMiddleware) ViewRate(ctx context.Context, token, id string) (rate tax.Rate, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_tax_rate",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewRate(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListRates(ctx context.Context, token, vendor string) (rates []tax.Rate, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_tax_rates",
+			"token", token,
+			"vendor", vendor,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListRates(ctx, token, vendor)
+}
+
+func (lm *loggingMiddleware) UpdateRate(ctx context.Context, token string, rate tax.Rate) (id string, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "update_tax_rate",
+			"token", token,
+			"id", rate.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.UpdateRate(ctx, token, rate)
+}
+
+func (lm *loggingMiddleware) RemoveRate(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "remove_tax_rate",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.RemoveRate(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Calculate(ctx context.Context, token string, order orders.Order) (taxes []orders.Tax, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "calculate_taxes",
+			"token", token,
+			"vendor", order.Vendor,
+			"price", order.Price,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Calculate(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) IssueInvoice(ctx context.Context, token, orderID string) (inv tax.Invoice, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "issue_invoice",
+			"token", token,
+			"order", orderID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.IssueInvoice(ctx, token, orderID)
+}
+
+func (lm *loggingMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "order_preparing",
+			"token", token,
+			"order", order.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPreparing(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "invoice_order",
+			"token", token,
+			"order", order.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.OrderPaid(ctx, token, order)
+}
+
+func (lm *loggingMiddleware) ViewInvoice(ctx context.Context, token, id string) (inv tax.Invoice, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "view_invoice",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ViewInvoice(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListInvoices(ctx context.Context, token string, pm tax.PageMetadata) (page tax.InvoicesPage, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "list_invoices",
+			"token", token,
+			"vendor", pm.Vendor,
+			"offset", pm.Offset,
+			"limit", pm.Limit,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.ListInvoices(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) SubmitInvoice(ctx context.Context, token, id string) (inv tax.Invoice, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "submit_invoice",
+			"token", token,
+			"id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.SubmitInvoice(ctx, token, id)
+}
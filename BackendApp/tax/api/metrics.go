@@ -0,0 +1,138 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ tax.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     tax.Service
+}
+
+// MetricsMiddleware instruments the tax service by tracking request count
+// and latency.
+func MetricsMiddleware(svc tax.Service, counter metrics.Counter, latency metrics.Histogram) tax.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateRate(ctx context.Context, token string, rate tax.Rate) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_tax_rate").Add(1)
+		ms.latency.With("method", "create_tax_rate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRate(ctx, token, rate)
+}
+
+func (ms *metricsMiddleware) ViewRate(ctx context.Context, token, id string) (tax.Rate, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_tax_rate").Add(1)
+		ms.latency.With("method", "view_tax_rate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRate(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListRates(ctx context.Context, token, vendor string) ([]tax.Rate, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_tax_rates").Add(1)
+		ms.latency.With("method", "list_tax_rates").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRates(ctx, token, vendor)
+}
+
+func (ms *metricsMiddleware) UpdateRate(ctx context.Context, token string, rate tax.Rate) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_tax_rate").Add(1)
+		ms.latency.With("method", "update_tax_rate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateRate(ctx, token, rate)
+}
+
+func (ms *metricsMiddleware) RemoveRate(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_tax_rate").Add(1)
+		ms.latency.With("method", "remove_tax_rate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRate(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Calculate(ctx context.Context, token string, order orders.Order) ([]orders.Tax, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "calculate_taxes").Add(1)
+		ms.latency.With("method", "calculate_taxes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Calculate(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) IssueInvoice(ctx context.Context, token, orderID string) (tax.Invoice, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_invoice").Add(1)
+		ms.latency.With("method", "issue_invoice").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueInvoice(ctx, token, orderID)
+}
+
+func (ms *metricsMiddleware) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "order_preparing").Add(1)
+		ms.latency.With("method", "order_preparing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPreparing(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "invoice_order").Add(1)
+		ms.latency.With("method", "invoice_order").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OrderPaid(ctx, token, order)
+}
+
+func (ms *metricsMiddleware) ViewInvoice(ctx context.Context, token, id string) (tax.Invoice, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_invoice").Add(1)
+		ms.latency.With("method", "view_invoice").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewInvoice(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListInvoices(ctx context.Context, token string, pm tax.PageMetadata) (tax.InvoicesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_invoices").Add(1)
+		ms.latency.With("method", "list_invoices").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListInvoices(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) SubmitInvoice(ctx context.Context, token, id string) (tax.Invoice, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "submit_invoice").Add(1)
+		ms.latency.With("method", "submit_invoice").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SubmitInvoice(ctx, token, id)
+}
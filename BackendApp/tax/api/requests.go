@@ -0,0 +1,103 @@
+package api
+
+import (
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+)
+
+const (
+	maxLimitSize  = 100
+	maxExportSize = 10000
+)
+
+type createRateReq struct {
+	rate  tax.Rate
+	token string
+}
+
+func (req createRateReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	return req.rate.Validate()
+}
+
+type entityReq struct {
+	token string
+	id    string
+}
+
+func (req entityReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.id == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listRatesReq struct {
+	token  string
+	vendor string
+}
+
+func (req listRatesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.vendor == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type updateRateReq struct {
+	token string
+	rate  tax.Rate
+}
+
+func (req updateRateReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	if req.rate.ID == "" {
+		return errors.ErrMissingID
+	}
+	return nil
+}
+
+type listInvoicesReq struct {
+	token  string
+	vendor string
+	status string
+	from   time.Time
+	to     time.Time
+	csv    bool
+	offset uint64
+	limit  uint64
+}
+
+func (req listInvoicesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrBearerToken
+	}
+	max := uint64(maxLimitSize)
+	if req.csv {
+		max = maxExportSize
+	}
+	if req.limit > max || req.limit < 1 {
+		return errors.ErrLimitSize
+	}
+	switch tax.Status(req.status) {
+	case "", tax.Pending, tax.Submitted, tax.Failed:
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+	if !req.from.IsZero() && !req.to.IsZero() && !req.from.Before(req.to) {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+)
+
+// Response contains HTTP response specific methods.
+type Response interface {
+	// Code returns HTTP response code.
+	Code() int
+
+	// Headers returns map of HTTP headers with their values.
+	Headers() map[string]string
+
+	// Empty indicates if HTTP response has content.
+	Empty() bool
+}
+
+var (
+	_ Response = (*createRateRes)(nil)
+	_ Response = (*viewRateRes)(nil)
+	_ Response = (*ratesRes)(nil)
+	_ Response = (*updateRateRes)(nil)
+	_ Response = (*removeRateRes)(nil)
+	_ Response = (*invoiceRes)(nil)
+	_ Response = (*invoicesPageRes)(nil)
+)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type createRateRes struct {
+	ID string
+}
+
+func (res createRateRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRateRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/taxes/rates/%s", res.ID),
+	}
+}
+
+func (res createRateRes) Empty() bool {
+	return true
+}
+
+type viewRateRes struct {
+	tax.Rate
+}
+
+func (res viewRateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRateRes) Empty() bool {
+	return false
+}
+
+type ratesRes struct {
+	Rates []viewRateRes `json:"rates"`
+}
+
+func (res ratesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ratesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ratesRes) Empty() bool {
+	return false
+}
+
+type updateRateRes struct {
+	ID string
+}
+
+func (res updateRateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRateRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/taxes/rates/%s", res.ID),
+	}
+}
+
+func (res updateRateRes) Empty() bool {
+	return true
+}
+
+type removeRateRes struct{}
+
+func (res removeRateRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRateRes) Empty() bool {
+	return true
+}
+
+type invoiceRes struct {
+	ID            string       `json:"id"`
+	Number        string       `json:"number"`
+	Vendor        string       `json:"vendor"`
+	OrderID       string       `json:"order_id"`
+	Customer      string       `json:"customer,omitempty"`
+	Gross         uint64       `json:"gross"`
+	Discount      uint64       `json:"discount"`
+	Net           uint64       `json:"net"`
+	Tax           uint64       `json:"tax"`
+	Total         uint64       `json:"total"`
+	Taxes         []orders.Tax `json:"taxes,omitempty"`
+	Status        tax.Status   `json:"status"`
+	ControlNumber string       `json:"control_number,omitempty"`
+	Error         string       `json:"error,omitempty"`
+	IssuedAt      time.Time    `json:"issued_at"`
+	SubmittedAt   *time.Time   `json:"submitted_at,omitempty"`
+}
+
+func toInvoiceRes(inv tax.Invoice) invoiceRes {
+	res := invoiceRes{
+		ID:            inv.ID,
+		Number:        inv.Number(),
+		Vendor:        inv.Vendor,
+		OrderID:       inv.OrderID,
+		Customer:      inv.Customer,
+		Gross:         inv.Gross,
+		Discount:      inv.Discount,
+		Net:           inv.Net,
+		Tax:           inv.Tax,
+		Total:         inv.Total,
+		Taxes:         inv.Taxes,
+		Status:        inv.Status,
+		ControlNumber: inv.ControlNumber,
+		Error:         inv.Error,
+		IssuedAt:      inv.IssuedAt,
+	}
+	if !inv.SubmittedAt.IsZero() {
+		res.SubmittedAt = &inv.SubmittedAt
+	}
+	return res
+}
+
+func (res invoiceRes) Code() int {
+	return http.StatusOK
+}
+
+func (res invoiceRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res invoiceRes) Empty() bool {
+	return false
+}
+
+type invoicesPageRes struct {
+	pageRes
+	Invoices []invoiceRes `json:"invoices"`
+}
+
+func (res invoicesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res invoicesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res invoicesPageRes) Empty() bool {
+	return false
+}
+
+// registerRes is the invoice register exported as CSV.
+type registerRes struct {
+	invoices []tax.Invoice
+}
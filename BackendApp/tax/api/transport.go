@@ -0,0 +1,283 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/apiutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentType    = "application/json"
+	csvContentType = "text/csv"
+	offsetKey      = "offset"
+	limitKey       = "limit"
+	vendorKey      = "vendor"
+	statusKey      = "status"
+	fromKey        = "from"
+	toKey          = "to"
+	formatKey      = "format"
+	csvFormat      = "csv"
+)
+
+// MakeTaxHandler returns a HTTP handler for tax rates and invoices API
+// endpoints.
+func MakeTaxHandler(svc tax.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerErrorLogger(logger),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/taxes/rates").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint create_tax_rate")(createRateEndpoint(svc)),
+		decodeCreateRate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/taxes/rates/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_tax_rate")(viewRateEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/taxes/rates").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_tax_rates")(listRatesEndpoint(svc)),
+		decodeListRates,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("PUT").Path("/taxes/rates/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint update_tax_rate")(updateRateEndpoint(svc)),
+		decodeUpdateRate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("DELETE").Path("/taxes/rates/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint remove_tax_rate")(removeRateEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/orders/{id}/invoice").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint issue_invoice")(issueInvoiceEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/invoices/{id}").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint view_invoice")(viewInvoiceEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("GET").Path("/invoices").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint list_invoices")(listInvoicesEndpoint(svc)),
+		decodeListInvoices,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Methods("POST").Path("/invoices/{id}/submit").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint submit_invoice")(submitInvoiceEndpoint(svc)),
+		decodeEntity,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCreateRate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var rate tax.Rate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	req := createRateReq{
+		rate:  rate,
+		token: decodeToken(r),
+	}
+	return req, nil
+}
+
+func decodeEntity(_ context.Context, r *http.Request) (interface{}, error) {
+	req := entityReq{
+		token: decodeToken(r),
+		id:    mux.Vars(r)["id"],
+	}
+	return req, nil
+}
+
+func decodeListRates(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listRatesReq{
+		token:  decodeToken(r),
+		vendor: r.URL.Query().Get(vendorKey),
+	}
+	return req, nil
+}
+
+func decodeUpdateRate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var rate tax.Rate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	rate.ID = mux.Vars(r)["id"]
+	req := updateRateReq{
+		token: decodeToken(r),
+		rate:  rate,
+	}
+	return req, nil
+}
+
+// decodeListInvoices reads the register query. With format=csv the whole
+// register is exported in one response.
+func decodeListInvoices(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listInvoicesReq{
+		token:  decodeToken(r),
+		limit:  maxLimitSize,
+		vendor: r.URL.Query().Get(vendorKey),
+		status: r.URL.Query().Get(statusKey),
+		csv:    r.URL.Query().Get(formatKey) == csvFormat,
+	}
+	if req.csv {
+		req.limit = maxExportSize
+	}
+	var err error
+	if r.URL.Query().Has(offsetKey) {
+		req.offset, err = strconv.ParseUint(r.URL.Query().Get(offsetKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(limitKey) {
+		req.limit, err = strconv.ParseUint(r.URL.Query().Get(limitKey), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(fromKey) {
+		req.from, err = time.Parse(time.RFC3339, r.URL.Query().Get(fromKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	if r.URL.Query().Has(toKey) {
+		req.to, err = time.Parse(time.RFC3339, r.URL.Query().Get(toKey))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+	return req, nil
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if rr, ok := response.(registerRes); ok {
+		return encodeRegister(ctx, w, rr)
+	}
+	if ar, ok := response.(Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+		if ar.Empty() {
+			return nil
+		}
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeRegister writes the invoice register with a row per invoice.
+func encodeRegister(_ context.Context, w http.ResponseWriter, res registerRes) error {
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="invoices.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	header := []string{"number", "vendor", "order_id", "customer", "issued_at", "gross", "discount", "net", "tax", "total", "status", "control_number"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, inv := range res.invoices {
+		row := []string{
+			inv.Number(),
+			inv.Vendor,
+			inv.OrderID,
+			inv.Customer,
+			inv.IssuedAt.Format(time.RFC3339),
+			strconv.FormatUint(inv.Gross, 10),
+			strconv.FormatUint(inv.Discount, 10),
+			strconv.FormatUint(inv.Net, 10),
+			strconv.FormatUint(inv.Tax, 10),
+			strconv.FormatUint(inv.Total, 10),
+			string(inv.Status),
+			inv.ControlNumber,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func decodeToken(r *http.Request) string {
+	tokenString := r.Header.Get("Authorization")
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+	return tokenString
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+	switch {
+	case errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrMissingID),
+		errors.Contains(err, errors.ErrLimitSize),
+		errors.Contains(err, errors.ErrOffsetSize):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrAuthentication),
+		errors.Contains(err, errors.ErrBearerToken):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, errors.ErrConflict),
+		errors.Contains(err, tax.ErrNotPaid):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, tax.ErrSubmission):
+		w.WriteHeader(http.StatusBadGateway)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(apiutil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package tax
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// line is an amount of an order charged under the rates of one category.
+type line struct {
+	category string
+	amount   uint64
+}
+
+// applicable returns the rates levied on items in the category. For every
+// kind the category's own rate wins over the vendor wide one.
+func applicable(rates []Rate, category string) []Rate {
+	chosen := map[Kind]Rate{}
+	for _, rate := range rates {
+		switch {
+		case rate.Category == "":
+			if _, ok := chosen[rate.Kind]; !ok {
+				chosen[rate.Kind] = rate
+			}
+		case rate.Category == category:
+			chosen[rate.Kind] = rate
+		}
+	}
+	var levied []Rate
+	for _, rate := range rates {
+		if c, ok := chosen[rate.Kind]; ok && c.ID == rate.ID {
+			levied = append(levied, rate)
+		}
+	}
+	return levied
+}
+
+// calculate returns the taxes levied on the lines, one per rate in the
+// order the rates are given. Inclusive rates are taken out of the line
+// amounts together and exclusive ones are added on the amount that remains.
+func calculate(rates []Rate, lines []line) []orders.Tax {
+	amounts := map[string]uint64{}
+	var categories []string
+	for _, l := range lines {
+		if _, ok := amounts[l.category]; !ok {
+			categories = append(categories, l.category)
+		}
+		amounts[l.category] += l.amount
+	}
+
+	taxes := map[string]orders.Tax{}
+	for _, category := range categories {
+		levied := applicable(rates, category)
+		if len(levied) == 0 {
+			continue
+		}
+		amount := amounts[category]
+		var inclusive uint64
+		for _, rate := range levied {
+			if rate.Inclusive {
+				inclusive += rate.Rate
+			}
+		}
+		base := divide(amount*basisPoints, basisPoints+inclusive)
+		// The inclusive taxes have to add up to what was taken out of the
+		// amount, the last one absorbs rounding.
+		remaining := amount - base
+		for i, rate := range levied {
+			tax := taxes[rate.ID]
+			tax.Name = rate.Name
+			tax.Code = rate.Code
+			tax.Rate = rate.Rate
+			tax.Inclusive = rate.Inclusive
+			tax.Base += base
+			if !rate.Inclusive {
+				tax.Amount += divide(base*rate.Rate, basisPoints)
+				taxes[rate.ID] = tax
+				continue
+			}
+			value := divide(base*rate.Rate, basisPoints)
+			if value > remaining || lastInclusive(levied, i) {
+				value = remaining
+			}
+			remaining -= value
+			tax.Amount += value
+			taxes[rate.ID] = tax
+		}
+	}
+
+	var levied []orders.Tax
+	for _, rate := range rates {
+		if tax, ok := taxes[rate.ID]; ok {
+			levied = append(levied, tax)
+		}
+	}
+	return levied
+}
+
+// split divides the net price of the order into lines by the category of
+// each item. Discounts are spread over the items in proportion to their
+// totals.
+func split(order orders.Order, categories map[string]string) []line {
+	gross := order.Price
+	if gross == 0 {
+		gross = order.ItemsTotal()
+	}
+	var net uint64
+	if discount := order.Discount(); discount < gross {
+		net = gross - discount
+	}
+	if len(order.Items) == 0 || order.ItemsTotal() == 0 {
+		return []line{{amount: net}}
+	}
+
+	total := order.ItemsTotal()
+	lines := make([]line, 0, len(order.Items))
+	var allocated uint64
+	for i, item := range order.Items {
+		amount := item.Total() * net / total
+		if i == len(order.Items)-1 {
+			amount = net - allocated
+		}
+		allocated += amount
+		lines = append(lines, line{category: categories[item.ID], amount: amount})
+	}
+	return lines
+}
+
+func lastInclusive(rates []Rate, i int) bool {
+	for _, rate := range rates[i+1:] {
+		if rate.Inclusive {
+			return false
+		}
+	}
+	return true
+}
+
+// divide returns a/b rounded half up.
+func divide(a, b uint64) uint64 {
+	return (a + b/2) / b
+}
@@ -0,0 +1,83 @@
+// Package etims contains a local fake of the KRA electronic Tax Invoice
+// Management System so invoices can be submitted before a vendor's device
+// is registered with KRA.
+package etims
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+)
+
+// DefaultSerial is the control unit serial number the fake signs with.
+const DefaultSerial = "KRACU0000000001"
+
+var _ tax.Submitter = (*Fake)(nil)
+
+// Fake accepts invoices the way eTIMS does and keeps every invoice it signs.
+// Submitting an invoice again returns the receipt it was first signed with.
+type Fake struct {
+	serial string
+
+	mu       sync.Mutex
+	err      error
+	invoices []tax.Invoice
+	receipts map[string]tax.Receipt
+}
+
+// NewFake instantiates a fake that signs with the control unit serial. If
+// serial is empty DefaultSerial is used.
+func NewFake(serial string) *Fake {
+	if serial == "" {
+		serial = DefaultSerial
+	}
+	return &Fake{
+		serial:   serial,
+		receipts: map[string]tax.Receipt{},
+	}
+}
+
+// Submit signs the invoice.
+func (f *Fake) Submit(_ context.Context, inv tax.Invoice) (tax.Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return tax.Receipt{}, f.err
+	}
+	if inv.Vendor == "" || inv.Sequence == 0 || inv.Net+inv.Tax != inv.Total {
+		return tax.Receipt{}, errors.ErrMalformedEntity
+	}
+	key := fmt.Sprintf("%s/%s", inv.Vendor, inv.Number())
+	if receipt, ok := f.receipts[key]; ok {
+		return receipt, nil
+	}
+	f.invoices = append(f.invoices, inv)
+	receipt := tax.Receipt{
+		ControlNumber: fmt.Sprintf("%s/%d", f.serial, len(f.invoices)),
+		SignedAt:      time.Now(),
+	}
+	f.receipts[key] = receipt
+	return receipt, nil
+}
+
+// Fail makes every submission fail with err until it is called with nil,
+// as if eTIMS could not be reached.
+func (f *Fake) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+// Invoices returns every invoice signed so far.
+func (f *Fake) Invoices() []tax.Invoice {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]tax.Invoice(nil), f.invoices...)
+}
@@ -0,0 +1,57 @@
+package tax
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var _ orders.OrderService = (*invoicingMiddleware)(nil)
+
+type invoicingMiddleware struct {
+	svc   orders.OrderService
+	taxes Service
+}
+
+// InvoicingMiddleware charges the vendor's taxes on orders as they are
+// created. Discounts must be on the order before it reaches the middleware
+// so taxes are worked out on the net price. The invoices of the orders paid
+// are issued by the tax service as a hook of the orders.
+func InvoicingMiddleware(svc orders.OrderService, taxes Service) orders.OrderService {
+	return &invoicingMiddleware{
+		svc:   svc,
+		taxes: taxes,
+	}
+}
+
+func (im *invoicingMiddleware) CreateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	if order.Price == 0 {
+		order.Price = order.ItemsTotal()
+	}
+	taxes, err := im.taxes.Calculate(ctx, token, order)
+	if err != nil {
+		return "", err
+	}
+	order.Taxes = taxes
+	return im.svc.CreateOrder(ctx, token, order)
+}
+
+func (im *invoicingMiddleware) ViewOrder(ctx context.Context, token, id string) (orders.Order, error) {
+	return im.svc.ViewOrder(ctx, token, id)
+}
+
+func (im *invoicingMiddleware) ListOrders(ctx context.Context, token string, page orders.PageMetadata) (orders.OrdersPage, error) {
+	return im.svc.ListOrders(ctx, token, page)
+}
+
+func (im *invoicingMiddleware) UpdateOrder(ctx context.Context, token string, order orders.Order) (string, error) {
+	return im.svc.UpdateOrder(ctx, token, order)
+}
+
+func (im *invoicingMiddleware) DeleteOrder(ctx context.Context, token, id string) error {
+	return im.svc.DeleteOrder(ctx, token, id)
+}
+
+func (im *invoicingMiddleware) RecordPayment(ctx context.Context, token, id, method string, amount, tip uint64) (orders.Order, error) {
+	return im.svc.RecordPayment(ctx, token, id, method, amount, tip)
+}
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/pgutil"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/jmoiron/sqlx"
+)
+
+const invoiceColumns = `id, vendor, prefix, sequence, order_id, customer, gross, discount, net, tax, total, taxes,
+	status, control_number, error, issued_at, submitted_at`
+
+var _ tax.InvoiceRepository = (*invoicesRepo)(nil)
+
+type invoicesRepo struct {
+	db *sqlx.DB
+}
+
+// NewInvoicesRepo instantiates a PostgreSQL
+// implementation of invoices repository.
+func NewInvoicesRepo(db *sqlx.DB) tax.InvoiceRepository {
+	return &invoicesRepo{
+		db: db,
+	}
+}
+
+func (repo invoicesRepo) Issue(ctx context.Context, inv tax.Invoice) (tax.Invoice, error) {
+	// The sequence row stays locked until the invoice is committed, a
+	// failed insert rolls the number back so none is ever skipped.
+	sq := `INSERT INTO invoice_sequences (vendor, last) VALUES (:vendor, 1)
+		   ON CONFLICT (vendor) DO UPDATE SET last = invoice_sequences.last + 1 RETURNING last`
+	q := `INSERT INTO invoices (` + invoiceColumns + `)
+		  VALUES (:id, :vendor, :prefix, :sequence, :order_id, :customer, :gross, :discount, :net, :tax, :total, :taxes,
+		  :status, :control_number, :error, :issued_at, :submitted_at)`
+
+	err := pgutil.InTx(ctx, repo.db, errors.ErrCreateEntity, func(tx *sqlx.Tx) error {
+		rows, err := sqlx.NamedQueryContext(ctx, tx, sq, map[string]interface{}{"vendor": inv.Vendor})
+		if err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		if rows.Next() {
+			if err := rows.Scan(&inv.Sequence); err != nil {
+				rows.Close()
+				return errors.Wrap(errors.ErrCreateEntity, err)
+			}
+		}
+		rows.Close()
+
+		dbi, err := toDBInvoice(inv)
+		if err != nil {
+			return errors.Wrap(errors.ErrCreateEntity, err)
+		}
+		if _, err := tx.NamedExecContext(ctx, q, dbi); err != nil {
+			return handleError(err, errors.ErrCreateEntity)
+		}
+		return nil
+	})
+	if err != nil {
+		return tax.Invoice{}, err
+	}
+	return inv, nil
+}
+
+func (repo invoicesRepo) RetrieveByID(ctx context.Context, id string) (tax.Invoice, error) {
+	q := `SELECT ` + invoiceColumns + ` FROM invoices WHERE id = $1`
+
+	return repo.retrieve(ctx, q, id)
+}
+
+func (repo invoicesRepo) RetrieveByOrder(ctx context.Context, orderID string) (tax.Invoice, error) {
+	q := `SELECT ` + invoiceColumns + ` FROM invoices WHERE order_id = $1`
+
+	return repo.retrieve(ctx, q, orderID)
+}
+
+func (repo invoicesRepo) retrieve(ctx context.Context, q string, args ...interface{}) (tax.Invoice, error) {
+	dbi := dbInvoice{}
+	if err := repo.db.QueryRowxContext(ctx, q, args...).StructScan(&dbi); err != nil {
+		if err == sql.ErrNoRows {
+			return tax.Invoice{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return tax.Invoice{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toInvoice(dbi)
+}
+
+func (repo invoicesRepo) RetrieveAll(ctx context.Context, pm tax.PageMetadata) (tax.InvoicesPage, error) {
+	var query []string
+	var emq string
+	params := map[string]interface{}{
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"vendor": pm.Vendor,
+		"status": string(pm.Status),
+		"from":   pm.From,
+		"to":     pm.To,
+	}
+	if pm.Vendor != "" {
+		query = append(query, "vendor = :vendor")
+	}
+	if pm.Status != "" {
+		query = append(query, "status = :status")
+	}
+	if !pm.From.IsZero() {
+		query = append(query, "issued_at >= :from")
+	}
+	if !pm.To.IsZero() {
+		query = append(query, "issued_at < :to")
+	}
+	if len(query) > 0 {
+		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT `+invoiceColumns+` FROM invoices %s ORDER BY vendor, sequence LIMIT :limit OFFSET :offset;`, emq)
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return tax.InvoicesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var invoices []tax.Invoice
+	for rows.Next() {
+		dbi := dbInvoice{}
+		if err := rows.StructScan(&dbi); err != nil {
+			return tax.InvoicesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		inv, err := toInvoice(dbi)
+		if err != nil {
+			return tax.InvoicesPage{}, err
+		}
+		invoices = append(invoices, inv)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM invoices %s;`, emq)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return tax.InvoicesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	page := tax.InvoicesPage{
+		Invoices: invoices,
+		PageMetadata: tax.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}
+	return page, nil
+}
+
+func (repo invoicesRepo) UpdateSubmission(ctx context.Context, inv tax.Invoice) error {
+	q := `UPDATE invoices SET status = :status, control_number = :control_number, error = :error, submitted_at = :submitted_at
+		  WHERE id = :id`
+
+	dbi, err := toDBInvoice(inv)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	res, err := repo.db.NamedExecContext(ctx, q, dbi)
+	if err != nil {
+		return handleError(err, errors.ErrUpdateEntity)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt != 1 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbInvoice struct {
+	ID            string       `db:"id"`
+	Vendor        string       `db:"vendor"`
+	Prefix        string       `db:"prefix"`
+	Sequence      uint64       `db:"sequence"`
+	OrderID       string       `db:"order_id"`
+	Customer      string       `db:"customer"`
+	Gross         uint64       `db:"gross"`
+	Discount      uint64       `db:"discount"`
+	Net           uint64       `db:"net"`
+	Tax           uint64       `db:"tax"`
+	Total         uint64       `db:"total"`
+	Taxes         []byte       `db:"taxes"`
+	Status        string       `db:"status"`
+	ControlNumber string       `db:"control_number"`
+	Error         string       `db:"error"`
+	IssuedAt      time.Time    `db:"issued_at"`
+	SubmittedAt   sql.NullTime `db:"submitted_at"`
+}
+
+func toDBInvoice(inv tax.Invoice) (dbInvoice, error) {
+	taxes := []byte("[]")
+	if len(inv.Taxes) > 0 {
+		b, err := json.Marshal(inv.Taxes)
+		if err != nil {
+			return dbInvoice{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		taxes = b
+	}
+	return dbInvoice{
+		ID:            inv.ID,
+		Vendor:        inv.Vendor,
+		Prefix:        inv.Prefix,
+		Sequence:      inv.Sequence,
+		OrderID:       inv.OrderID,
+		Customer:      inv.Customer,
+		Gross:         inv.Gross,
+		Discount:      inv.Discount,
+		Net:           inv.Net,
+		Tax:           inv.Tax,
+		Total:         inv.Total,
+		Taxes:         taxes,
+		Status:        string(inv.Status),
+		ControlNumber: inv.ControlNumber,
+		Error:         inv.Error,
+		IssuedAt:      inv.IssuedAt,
+		SubmittedAt:   sql.NullTime{Time: inv.SubmittedAt, Valid: !inv.SubmittedAt.IsZero()},
+	}, nil
+}
+
+func toInvoice(inv dbInvoice) (tax.Invoice, error) {
+	var taxes []orders.Tax
+	if inv.Taxes != nil {
+		if err := json.Unmarshal(inv.Taxes, &taxes); err != nil {
+			return tax.Invoice{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	return tax.Invoice{
+		ID:            inv.ID,
+		Vendor:        inv.Vendor,
+		Prefix:        inv.Prefix,
+		Sequence:      inv.Sequence,
+		OrderID:       inv.OrderID,
+		Customer:      inv.Customer,
+		Gross:         inv.Gross,
+		Discount:      inv.Discount,
+		Net:           inv.Net,
+		Tax:           inv.Tax,
+		Total:         inv.Total,
+		Taxes:         taxes,
+		Status:        tax.Status(inv.Status),
+		ControlNumber: inv.ControlNumber,
+		Error:         inv.Error,
+		IssuedAt:      inv.IssuedAt,
+		SubmittedAt:   inv.SubmittedAt.Time,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package postgres_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax/postgres"
+	"github.com/oklog/ulid/v2"
+)
+
+const vendor = "mama-oliech"
+
+func TestIssueSequence(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewInvoicesRepo(openTestDB(t))
+
+	invoice := func(vendor, orderID string) tax.Invoice {
+		return tax.Invoice{
+			ID:       ulid.Make().String(),
+			Vendor:   vendor,
+			Prefix:   "INV",
+			OrderID:  orderID,
+			Gross:    100,
+			Net:      100,
+			Total:    100,
+			Status:   tax.Pending,
+			IssuedAt: time.Now().UTC(),
+		}
+	}
+	taken, err := repo.Issue(ctx, invoice(vendor, ulid.Make().String()))
+	if err != nil {
+		t.Fatalf("issuing the first invoice: %s", err)
+	}
+	if taken.Sequence != 1 {
+		t.Fatalf("issuing the first invoice: got number %d, want 1", taken.Sequence)
+	}
+
+	// Invoices issued at the same time take consecutive numbers, and the
+	// numbers of those that fail to be saved go to the next ones.
+	const issued, failed = 20, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, issued+failed)
+	for i := 0; i < issued+failed; i++ {
+		orderID := ulid.Make().String()
+		if i%3 == 0 {
+			orderID = taken.OrderID
+		}
+		wg.Add(1)
+		go func(orderID string) {
+			defer wg.Done()
+			_, err := repo.Issue(ctx, invoice(vendor, orderID))
+			errs <- err
+		}(orderID)
+	}
+	wg.Wait()
+	close(errs)
+	var conflicts int
+	for err := range errs {
+		switch {
+		case errors.Contains(err, errors.ErrConflict):
+			conflicts++
+		case err != nil:
+			t.Errorf("issuing invoices concurrently: %s", err)
+		}
+	}
+	if conflicts != failed {
+		t.Errorf("issuing invoices of an invoiced order: got %d conflicts, want %d", conflicts, failed)
+	}
+
+	page, err := repo.RetrieveAll(ctx, tax.PageMetadata{Limit: 100, Vendor: vendor})
+	if err != nil {
+		t.Fatalf("listing invoices: %s", err)
+	}
+	if len(page.Invoices) != issued+1 {
+		t.Fatalf("listing invoices: got %d invoices, want %d", len(page.Invoices), issued+1)
+	}
+	for i, inv := range page.Invoices {
+		if inv.Sequence != uint64(i+1) {
+			t.Errorf("listing invoices: got number %d at position %d, want %d", inv.Sequence, i, i+1)
+		}
+	}
+
+	// Every vendor numbers its invoices on its own.
+	other, err := repo.Issue(ctx, invoice("other", ulid.Make().String()))
+	if err != nil {
+		t.Fatalf("issuing an invoice of another vendor: %s", err)
+	}
+	if other.Sequence != 1 {
+		t.Errorf("issuing an invoice of another vendor: got number %d, want 1", other.Sequence)
+	}
+}
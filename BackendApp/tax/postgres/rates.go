@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/tax"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ tax.RateRepository = (*ratesRepo)(nil)
+
+type ratesRepo struct {
+	db *sqlx.DB
+}
+
+// NewRatesRepo instantiates a PostgreSQL
+// implementation of tax rates repository.
+func NewRatesRepo(db *sqlx.DB) tax.RateRepository {
+	return &ratesRepo{
+		db: db,
+	}
+}
+
+func (repo ratesRepo) Save(ctx context.Context, rate tax.Rate) (string, error) {
+	q := `INSERT INTO tax_rates (id, vendor, name, kind, code, category, rate, inclusive, created_at, updated_at)
+		  VALUES (:id, :vendor, :name, :kind, :code, :category, :rate, :inclusive, :created_at, :updated_at) RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBRate(rate))
+	if err != nil {
+		return "", handleError(err, errors.ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (repo ratesRepo) RetrieveByID(ctx context.Context, id string) (tax.Rate, error) {
+	q := `SELECT id, vendor, name, kind, code, category, rate, inclusive, created_at, updated_at FROM tax_rates WHERE id = $1`
+
+	dbr := dbRate{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return tax.Rate{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return tax.Rate{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	return toRate(dbr), nil
+}
+
+func (repo ratesRepo) RetrieveAll(ctx context.Context, vendor string) ([]tax.Rate, error) {
+	q := `SELECT id, vendor, name, kind, code, category, rate, inclusive, created_at, updated_at FROM tax_rates
+		  WHERE vendor = :vendor ORDER BY kind DESC, created_at`
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, map[string]interface{}{"vendor": vendor})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+	var rates []tax.Rate
+	for rows.Next() {
+		dbr := dbRate{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		rates = append(rates, toRate(dbr))
+	}
+	return rates, nil
+}
+
+func (repo ratesRepo) Update(ctx context.Context, rate tax.Rate) (string, error) {
+	q := `UPDATE tax_rates SET name = :name, kind = :kind, code = :code, category = :category, rate = :rate,
+		  inclusive = :inclusive, updated_at = :updated_at WHERE id = :id RETURNING id`
+
+	row, err := repo.db.NamedQueryContext(ctx, q, toDBRate(rate))
+	if err != nil {
+		return "", handleError(err, errors.ErrUpdateEntity)
+	}
+	defer row.Close()
+	if !row.Next() {
+		return "", errors.ErrNotFound
+	}
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return "", errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	return id, nil
+}
+
+func (repo ratesRepo) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM tax_rates WHERE id = :id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbRate{ID: id}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+type dbRate struct {
+	ID        string    `db:"id"`
+	Vendor    string    `db:"vendor"`
+	Name      string    `db:"name"`
+	Kind      string    `db:"kind"`
+	Code      string    `db:"code"`
+	Category  string    `db:"category"`
+	Rate      uint64    `db:"rate"`
+	Inclusive bool      `db:"inclusive"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBRate(rate tax.Rate) dbRate {
+	return dbRate{
+		ID:        rate.ID,
+		Vendor:    rate.Vendor,
+		Name:      rate.Name,
+		Kind:      string(rate.Kind),
+		Code:      rate.Code,
+		Category:  rate.Category,
+		Rate:      rate.Rate,
+		Inclusive: rate.Inclusive,
+		CreatedAt: rate.CreatedAt,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func toRate(rate dbRate) tax.Rate {
+	return tax.Rate{
+		ID:        rate.ID,
+		Vendor:    rate.Vendor,
+		Name:      rate.Name,
+		Kind:      tax.Kind(rate.Kind),
+		Code:      rate.Code,
+		Category:  rate.Category,
+		Rate:      rate.Rate,
+		Inclusive: rate.Inclusive,
+		CreatedAt: rate.CreatedAt,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package tax
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/oklog/ulid/v2"
+)
+
+// DefaultPrefix is the prefix of invoice numbers if none is configured.
+const DefaultPrefix = "INV"
+
+// Config defines the options the tax service uses.
+type Config struct {
+	Prefix string // The prefix of invoice numbers. Defaults to DefaultPrefix.
+}
+
+var _ Service = (*taxService)(nil)
+
+type taxService struct {
+	config    Config
+	rates     RateRepository
+	invoices  InvoiceRepository
+	orders    orders.OrderService
+	menu      menu.Service
+	submitter Submitter
+}
+
+// NewService instantiates the tax service implementation. Invoices are left
+// pending if submitter is nil.
+func NewService(config Config, rates RateRepository, invoices InvoiceRepository, ordersSvc orders.OrderService, menuSvc menu.Service, submitter Submitter) Service {
+	if config.Prefix == "" {
+		config.Prefix = DefaultPrefix
+	}
+	return &taxService{
+		config:    config,
+		rates:     rates,
+		invoices:  invoices,
+		orders:    ordersSvc,
+		menu:      menuSvc,
+		submitter: submitter,
+	}
+}
+
+func (svc taxService) CreateRate(ctx context.Context, token string, rate Rate) (string, error) {
+	if err := rate.Validate(); err != nil {
+		return "", err
+	}
+	rate.ID = ulid.Make().String()
+	rate.CreatedAt = time.Now()
+	rate.UpdatedAt = time.Now()
+	return svc.rates.Save(ctx, rate)
+}
+
+func (svc taxService) ViewRate(ctx context.Context, token, id string) (Rate, error) {
+	return svc.rates.RetrieveByID(ctx, id)
+}
+
+func (svc taxService) ListRates(ctx context.Context, token, vendor string) ([]Rate, error) {
+	return svc.rates.RetrieveAll(ctx, vendor)
+}
+
+func (svc taxService) UpdateRate(ctx context.Context, token string, rate Rate) (string, error) {
+	current, err := svc.rates.RetrieveByID(ctx, rate.ID)
+	if err != nil {
+		return "", err
+	}
+	rate.Vendor = current.Vendor
+	if err := rate.Validate(); err != nil {
+		return "", err
+	}
+	rate.CreatedAt = current.CreatedAt
+	rate.UpdatedAt = time.Now()
+	return svc.rates.Update(ctx, rate)
+}
+
+func (svc taxService) RemoveRate(ctx context.Context, token, id string) error {
+	return svc.rates.Remove(ctx, id)
+}
+
+func (svc taxService) Calculate(ctx context.Context, token string, order orders.Order) ([]orders.Tax, error) {
+	if order.Vendor == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	rates, err := svc.rates.RetrieveAll(ctx, order.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, nil
+	}
+	categories := map[string]string{}
+	for _, item := range order.Items {
+		if item.ID == "" {
+			continue
+		}
+		if _, ok := categories[item.ID]; ok {
+			continue
+		}
+		mi, err := svc.menu.ViewItem(ctx, token, item.ID)
+		switch {
+		case err == nil:
+			categories[item.ID] = mi.Category
+		case errors.Contains(err, errors.ErrNotFound):
+			// Items no longer on the menu are taxed at the vendor wide rates.
+			categories[item.ID] = ""
+		default:
+			return nil, err
+		}
+	}
+	return calculate(rates, split(order, categories)), nil
+}
+
+func (svc taxService) IssueInvoice(ctx context.Context, token, orderID string) (Invoice, error) {
+	order, err := svc.orders.ViewOrder(ctx, token, orderID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	if order.Status != orders.StatusPaid {
+		return Invoice{}, ErrNotPaid
+	}
+	customer, _ := order.Metadata[orders.CustomerKey].(string)
+	inv := Invoice{
+		ID:       ulid.Make().String(),
+		Vendor:   order.Vendor,
+		Prefix:   svc.config.Prefix,
+		OrderID:  order.ID,
+		Customer: customer,
+		Gross:    order.Gross(),
+		Discount: order.Discount(),
+		Net:      order.Price - order.TaxTotal(),
+		Tax:      order.TaxTotal(),
+		Total:    order.Price,
+		Taxes:    order.Taxes,
+		Status:   Pending,
+		IssuedAt: time.Now(),
+	}
+	inv, err = svc.invoices.Issue(ctx, inv)
+	if errors.Contains(err, errors.ErrConflict) {
+		return svc.invoices.RetrieveByOrder(ctx, orderID)
+	}
+	if err != nil {
+		return Invoice{}, err
+	}
+	// The invoice stands even if the tax authority cannot be reached, it
+	// is marked failed and can be resubmitted.
+	inv, err = svc.submit(ctx, inv)
+	if err != nil && !errors.Contains(err, ErrSubmission) {
+		return inv, err
+	}
+	return inv, nil
+}
+
+// OrderPreparing does nothing, orders are invoiced once they are paid.
+func (svc taxService) OrderPreparing(ctx context.Context, token string, order orders.Order) error {
+	return nil
+}
+
+// OrderPaid issues the invoice of the order. Orders no longer paid by the
+// time they are notified are left uninvoiced.
+func (svc taxService) OrderPaid(ctx context.Context, token string, order orders.Order) error {
+	_, err := svc.IssueInvoice(ctx, token, order.ID)
+	if errors.Contains(err, ErrNotPaid) {
+		return nil
+	}
+	return err
+}
+
+func (svc taxService) ViewInvoice(ctx context.Context, token, id string) (Invoice, error) {
+	return svc.invoices.RetrieveByID(ctx, id)
+}
+
+func (svc taxService) ListInvoices(ctx context.Context, token string, pm PageMetadata) (InvoicesPage, error) {
+	return svc.invoices.RetrieveAll(ctx, pm)
+}
+
+func (svc taxService) SubmitInvoice(ctx context.Context, token, id string) (Invoice, error) {
+	inv, err := svc.invoices.RetrieveByID(ctx, id)
+	if err != nil {
+		return Invoice{}, err
+	}
+	if inv.Status == Submitted {
+		return inv, nil
+	}
+	return svc.submit(ctx, inv)
+}
+
+// submit reports the invoice to the tax authority and records the outcome.
+func (svc taxService) submit(ctx context.Context, inv Invoice) (Invoice, error) {
+	if svc.submitter == nil {
+		return inv, nil
+	}
+	receipt, serr := svc.submitter.Submit(ctx, inv)
+	if serr != nil {
+		inv.Status = Failed
+		inv.Error = serr.Error()
+	} else {
+		inv.Status = Submitted
+		inv.Error = ""
+		inv.ControlNumber = receipt.ControlNumber
+		inv.SubmittedAt = receipt.SignedAt
+	}
+	if err := svc.invoices.UpdateSubmission(ctx, inv); err != nil {
+		return inv, err
+	}
+	if serr != nil {
+		return inv, errors.Wrap(ErrSubmission, serr)
+	}
+	return inv, nil
+}
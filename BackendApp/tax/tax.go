@@ -0,0 +1,214 @@
+// Package tax works out the VAT and service charges levied on orders and
+// issues the sequentially numbered invoices of paid orders.
+package tax
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+var (
+	// ErrNotPaid indicates an invoice was requested for an order that has
+	// not been paid.
+	ErrNotPaid = errors.New("order has not been paid")
+
+	// ErrSubmission indicates the invoice could not be submitted to the tax
+	// authority.
+	ErrSubmission = errors.New("failed to submit invoice")
+)
+
+// Kind describes what a rate is levied as.
+type Kind string
+
+// Kinds of rates.
+const (
+	VAT           Kind = "vat"
+	ServiceCharge Kind = "service_charge"
+)
+
+// Status is the state of an invoice's submission to the tax authority.
+type Status string
+
+// Submission statuses.
+const (
+	Pending   Status = "pending"   // Not submitted yet.
+	Submitted Status = "submitted" // Accepted by the tax authority.
+	Failed    Status = "failed"    // Rejected or not reachable, can be resubmitted.
+)
+
+// basisPoints is a hundred percent in hundredths of a percent.
+const basisPoints = 10000
+
+// Rate is a tax or charge a vendor levies. A rate for a menu category
+// replaces the vendor wide rate of the same kind for items in that category
+// so zero rated or exempt goods can be priced next to standard rated ones.
+type Rate struct {
+	ID        string    `json:"id,omitempty"`
+	Vendor    string    `json:"vendor,omitempty"`     // The vendor levying the rate.
+	Name      string    `json:"name,omitempty"`       // The name printed on invoices i.e. "VAT 16%".
+	Kind      Kind      `json:"kind,omitempty"`       // What the rate is levied as.
+	Code      string    `json:"code,omitempty"`       // The tax band reported to the tax authority i.e. "B".
+	Category  string    `json:"category,omitempty"`   // The menu category the rate is limited to, all items if empty.
+	Rate      uint64    `json:"rate"`                 // The rate in hundredths of a percent, 1600 is 16%.
+	Inclusive bool      `json:"inclusive"`            // Whether menu prices already contain the tax.
+	UpdatedAt time.Time `json:"updated_at,omitempty"` // When the rate was updated.
+	CreatedAt time.Time `json:"created_at,omitempty"` // When the rate was created in the system.
+}
+
+// Validate returns an error if the rate representation is invalid.
+func (rate Rate) Validate() error {
+	if rate.Vendor == "" || rate.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	if rate.Kind != VAT && rate.Kind != ServiceCharge {
+		return errors.ErrMalformedEntity
+	}
+	if rate.Rate > basisPoints {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+// Invoice is the tax invoice of a paid order. Invoice numbers run without
+// gaps per vendor in the order the invoices were issued.
+type Invoice struct {
+	ID            string       `json:"id,omitempty"`
+	Vendor        string       `json:"vendor,omitempty"`
+	Prefix        string       `json:"prefix,omitempty"`         // The prefix of the invoice number.
+	Sequence      uint64       `json:"sequence,omitempty"`       // The position of the invoice in the vendor's sequence.
+	OrderID       string       `json:"order_id,omitempty"`       // The invoiced order.
+	Customer      string       `json:"customer,omitempty"`       // Who placed the order, if known.
+	Gross         uint64       `json:"gross"`                    // The price before discounts.
+	Discount      uint64       `json:"discount"`                 // The discounts given.
+	Net           uint64       `json:"net"`                      // The price charged excluding taxes.
+	Tax           uint64       `json:"tax"`                      // The taxes charged.
+	Total         uint64       `json:"total"`                    // The price charged.
+	Taxes         []orders.Tax `json:"taxes,omitempty"`          // The taxes charged by rate.
+	Status        Status       `json:"status,omitempty"`         // The state of the submission to the tax authority.
+	ControlNumber string       `json:"control_number,omitempty"` // The number the tax authority signed the invoice with.
+	Error         string       `json:"error,omitempty"`          // Why the last submission failed.
+	IssuedAt      time.Time    `json:"issued_at,omitempty"`      // When the invoice was issued.
+	SubmittedAt   time.Time    `json:"submitted_at,omitempty"`   // When the tax authority accepted the invoice.
+}
+
+// Number returns the invoice number i.e. "INV-00000042".
+func (inv Invoice) Number() string {
+	return fmt.Sprintf("%s-%08d", inv.Prefix, inv.Sequence)
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Vendor string
+	Status Status
+	From   time.Time
+	To     time.Time
+}
+
+// InvoicesPage contains a page of invoices.
+type InvoicesPage struct {
+	PageMetadata
+	Invoices []Invoice
+}
+
+// Receipt is the acknowledgement of the tax authority for an invoice.
+type Receipt struct {
+	ControlNumber string
+	SignedAt      time.Time
+}
+
+// Submitter submits invoices to an electronic tax invoicing system such as
+// KRA eTIMS.
+type Submitter interface {
+	// Submit reports the invoice and returns the acknowledgement.
+	Submit(ctx context.Context, inv Invoice) (Receipt, error)
+}
+
+// Service specifies the tax API. As a hook of the orders, it issues the
+// invoice of every order that gets paid, whether by a payment or by marking
+// it paid.
+type Service interface {
+	orders.Hook
+
+	// CreateRate adds a rate to the vendor's rates.
+	CreateRate(ctx context.Context, token string, rate Rate) (string, error)
+
+	// ViewRate retrieves a rate by its unique identifier ID.
+	ViewRate(ctx context.Context, token, id string) (Rate, error)
+
+	// ListRates retrieves the rates of a vendor.
+	ListRates(ctx context.Context, token, vendor string) ([]Rate, error)
+
+	// UpdateRate updates a rate. Orders already placed keep the taxes
+	// they were charged.
+	UpdateRate(ctx context.Context, token string, rate Rate) (string, error)
+
+	// RemoveRate removes a rate.
+	RemoveRate(ctx context.Context, token, id string) error
+
+	// Calculate returns the taxes levied on the order, net of its
+	// discounts.
+	Calculate(ctx context.Context, token string, order orders.Order) ([]orders.Tax, error)
+
+	// IssueInvoice issues the invoice of a paid order, allocating the next
+	// number of the vendor's sequence. An order is only ever invoiced
+	// once, issuing it again returns the existing invoice.
+	IssueInvoice(ctx context.Context, token, orderID string) (Invoice, error)
+
+	// ViewInvoice retrieves an invoice by its unique identifier ID.
+	ViewInvoice(ctx context.Context, token, id string) (Invoice, error)
+
+	// ListInvoices retrieves the invoice register for a given
+	// pageMetadata, ordered by invoice number.
+	ListInvoices(ctx context.Context, token string, pm PageMetadata) (InvoicesPage, error)
+
+	// SubmitInvoice submits an invoice that has not been accepted by the
+	// tax authority yet.
+	SubmitInvoice(ctx context.Context, token, id string) (Invoice, error)
+}
+
+// RateRepository specifies a rate persistence API.
+type RateRepository interface {
+	// Save persists the rate. If the vendor already has a rate of the same
+	// kind for the category errors.ErrConflict is returned.
+	Save(ctx context.Context, rate Rate) (string, error)
+
+	// RetrieveByID retrieves a rate by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Rate, error)
+
+	// RetrieveAll retrieves the rates of a vendor.
+	RetrieveAll(ctx context.Context, vendor string) ([]Rate, error)
+
+	// Update updates the rate.
+	Update(ctx context.Context, rate Rate) (string, error)
+
+	// Remove removes the rate.
+	Remove(ctx context.Context, id string) error
+}
+
+// InvoiceRepository specifies an invoice persistence API.
+type InvoiceRepository interface {
+	// Issue allocates the next number of the vendor's sequence to the
+	// invoice and persists it in one transaction so numbers are never
+	// skipped. If the order was already invoiced errors.ErrConflict is
+	// returned.
+	Issue(ctx context.Context, inv Invoice) (Invoice, error)
+
+	// RetrieveByID retrieves an invoice by its unique identifier ID.
+	RetrieveByID(ctx context.Context, id string) (Invoice, error)
+
+	// RetrieveByOrder retrieves the invoice of an order.
+	RetrieveByOrder(ctx context.Context, orderID string) (Invoice, error)
+
+	// RetrieveAll retrieves all invoices for a given pageMetadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (InvoicesPage, error)
+
+	// UpdateSubmission records the outcome of submitting the invoice.
+	UpdateSubmission(ctx context.Context, inv Invoice) error
+}
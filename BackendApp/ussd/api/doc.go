@@ -0,0 +1,3 @@
+// Package api contains the HTTP callback handler and middlewares of the USSD
+// service.
+package api
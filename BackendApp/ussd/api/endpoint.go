@@ -0,0 +1,18 @@
+package api
+
+import (
+	"context"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func callbackEndpoint(svc ussd.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(callbackReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		return svc.Handle(ctx, req.Request)
+	}
+}
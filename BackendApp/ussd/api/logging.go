@@ -0,0 +1,38 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+	"github.com/go-kit/log"
+)
+
+var _ ussd.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    ussd.Service
+}
+
+// LoggingMiddleware adds logging facilities to the USSD service.
+func LoggingMiddleware(svc ussd.Service, logger log.Logger) ussd.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Handle(ctx context.Context, req ussd.Request) (res ussd.Response, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"method", "handle_callback",
+			"session_id", req.SessionID,
+			"phone_number", req.PhoneNumber,
+			"end", res.End,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+
+	return lm.svc.Handle(ctx, req)
+}
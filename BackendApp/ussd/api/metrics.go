@@ -0,0 +1,38 @@
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ ussd.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     ussd.Service
+}
+
+// MetricsMiddleware instruments the USSD service by tracking callback count
+// and latency.
+func MetricsMiddleware(svc ussd.Service, counter metrics.Counter, latency metrics.Histogram) ussd.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Handle(ctx context.Context, req ussd.Request) (ussd.Response, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "handle_callback").Add(1)
+		ms.latency.With("method", "handle_callback").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Handle(ctx, req)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+)
+
+type callbackReq struct {
+	ussd.Request
+}
+
+func (req callbackReq) validate() error {
+	if req.SessionID == "" || req.PhoneNumber == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+	kitoc "github.com/go-kit/kit/tracing/opencensus"
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	formContentType = "application/x-www-form-urlencoded"
+	textContentType = "text/plain"
+	maxBodySize     = 1 << 16
+	msgUnavailable  = "Sorry, we could not process your request. Please try again later."
+)
+
+// MakeHandler registers the USSD gateway callback on the router. The gateway
+// posts a form with sessionId, serviceCode, phoneNumber, networkCode and
+// text and expects a plain text screen starting with CON or END back.
+func MakeHandler(svc ussd.Service, r *mux.Router, logger kitlog.Logger) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		kithttp.ServerErrorEncoder(encodeError),
+		kitoc.HTTPServerTrace(),
+	}
+
+	r.Methods("POST").Path("/ussd").Handler(kithttp.NewServer(
+		kitoc.TraceEndpoint("gokit:endpoint ussd_callback")(callbackEndpoint(svc)),
+		decodeCallback,
+		encodeResponse,
+		opts...,
+	))
+}
+
+func decodeCallback(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), formContentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBodySize)
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return callbackReq{
+		Request: ussd.Request{
+			SessionID:   r.PostForm.Get("sessionId"),
+			ServiceCode: r.PostForm.Get("serviceCode"),
+			PhoneNumber: r.PostForm.Get("phoneNumber"),
+			NetworkCode: r.PostForm.Get("networkCode"),
+			Text:        r.PostForm.Get("text"),
+		},
+	}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(ussd.Response)
+	w.Header().Set("Content-Type", textContentType)
+	w.WriteHeader(http.StatusOK)
+	_, err := io.WriteString(w, res.String())
+	return err
+}
+
+// encodeError ends the session on the handset with a short message, the
+// status code tells the gateway what went wrong.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", textContentType)
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	io.WriteString(w, ussd.Response{Text: msgUnavailable, End: true}.String())
+}
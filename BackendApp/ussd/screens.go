@@ -0,0 +1,146 @@
+package ussd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+)
+
+const (
+	msgWelcome         = "%s menu (KES)"
+	msgMenuEmpty       = "Sorry, the menu is not available right now."
+	msgInvalid         = "Invalid choice."
+	msgInvalidQuantity = "Enter a quantity from 1 to %d."
+	msgAdded           = "Added %dx %s. Total KES %d"
+	msgResume          = "Welcome back. Your order is KES %d."
+	msgQuantity        = "How many %s at KES %d each?"
+	msgPlace           = "Total KES %d"
+	msgAddress         = "Enter your delivery location"
+	msgCancelled       = "Your order has been cancelled."
+	msgOrdered         = "Order placed, total KES %d. Pay on collection or delivery. Ref %s"
+	msgOrderedTill     = "Order placed, total KES %d. Pay via M-Pesa till %s, account %s"
+)
+
+// Screen layout limits. Every menu page leaves room for the longest header
+// and footer so pages stay the same between callbacks.
+const (
+	maxHeader = 40
+	maxLine   = 32
+	maxFooter = len("98. More\n0. Back\n99. Checkout")
+	pageSpace = MaxScreen - maxHeader - maxFooter - 2
+)
+
+func (svc ussdService) render(s Session) string {
+	switch s.Step {
+	case StepResume:
+		return screen(s.Notice, []string{fmt.Sprintf(msgResume, s.Total())}, "1. Continue", "2. Start over")
+	case StepQuantity:
+		item := s.Menu[s.Selected]
+		return screen(s.Notice, []string{fmt.Sprintf(msgQuantity, item.Name, item.Price)}, "0. Back")
+	case StepPlace:
+		return screen(s.Notice, []string{fmt.Sprintf(msgPlace, s.Total())}, "1. Eat in", "2. Delivery", "0. Back")
+	case StepAddress:
+		return screen(s.Notice, []string{msgAddress}, "0. Back")
+	case StepConfirm:
+		var body []string
+		for _, item := range s.Cart {
+			body = append(body, truncate(fmt.Sprintf("%dx %s %d", item.Quantity, item.Name, item.Total()), maxLine))
+		}
+		place := "Eat in"
+		if s.Place == "delivery" {
+			place = "Deliver to " + s.Address
+		}
+		footer := []string{truncate(fmt.Sprintf("Total KES %d, %s", s.Total(), place), maxHeader), "1. Place order", "2. Add items", "3. Cancel"}
+		return screen(s.Notice, body, footer...)
+	default:
+		return svc.renderMenu(s)
+	}
+}
+
+func (svc ussdService) renderMenu(s Session) string {
+	pages := paginate(s.Menu)
+	page := s.Page
+	if page >= len(pages) {
+		page = len(pages) - 1
+	}
+	header := s.Notice
+	if header == "" {
+		header = fmt.Sprintf(msgWelcome, svc.config.Vendor)
+	}
+	var body []string
+	for i := pages[page][0]; i < pages[page][1]; i++ {
+		body = append(body, menuLine(i, s.Menu[i]))
+	}
+	var footer []string
+	if page < len(pages)-1 {
+		footer = append(footer, inputMore+". More")
+	}
+	if page > 0 {
+		footer = append(footer, inputBack+". Back")
+	}
+	if len(s.Cart) > 0 {
+		footer = append(footer, inputCheckout+". Checkout")
+	}
+	return screen(header, body, footer...)
+}
+
+// paginate splits the menu into pages of [start, end) indexes that fit a
+// screen along with the header and footer.
+func paginate(items []menu.Item) [][2]int {
+	var pages [][2]int
+	start, used := 0, 0
+	for i, item := range items {
+		n := len([]rune(menuLine(i, item))) + 1
+		if used+n > pageSpace && i > start {
+			pages = append(pages, [2]int{start, i})
+			start, used = i, 0
+		}
+		used += n
+	}
+	return append(pages, [2]int{start, len(items)})
+}
+
+// menuLine numbers items across pages so a choice means the same item
+// whichever page it was made on.
+func menuLine(i int, item menu.Item) string {
+	price := fmt.Sprintf(" %d", item.Price)
+	prefix := fmt.Sprintf("%d. ", i+1)
+	name := truncate(item.Name, maxLine-len(prefix)-len(price))
+	return prefix + name + price
+}
+
+// screen lays out a screen and makes sure it fits, dropping body lines that
+// do not.
+func screen(header string, body []string, footer ...string) string {
+	var top []string
+	if header != "" {
+		top = append(top, truncate(header, maxHeader))
+	}
+	for {
+		lines := append(append(append([]string{}, top...), body...), footer...)
+		text := strings.Join(lines, "\n")
+		if len([]rune(text)) <= MaxScreen || len(body) == 0 {
+			return truncate(text, MaxScreen)
+		}
+		body = body[:len(body)-1]
+		if len(body) > 0 {
+			body[len(body)-1] = "..."
+		}
+	}
+}
+
+// truncate shortens s to at most n characters.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 {
+		return ""
+	}
+	if len(r) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(r[:n])
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,286 @@
+package ussd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// Inputs with a fixed meaning on the screens that list them.
+const (
+	inputBack     = "0"
+	inputMore     = "98"
+	inputCheckout = "99"
+)
+
+const (
+	addressKey  = "address"
+	menuLimit   = 100
+	maxQuantity = 99
+)
+
+// Config defines the options the USSD service uses when placing orders.
+type Config struct {
+	Vendor string // The vendor orders are placed with.
+	Token  string // The token the service authenticates to the order and menu services with.
+	Till   string // The M-Pesa till number customers pay to. Optional.
+}
+
+var _ Service = (*ussdService)(nil)
+
+type ussdService struct {
+	config   Config
+	orders   orders.OrderService
+	menu     menu.Service
+	sessions SessionRepository
+}
+
+// NewService instantiates the USSD service implementation.
+func NewService(config Config, ordersSvc orders.OrderService, menuSvc menu.Service, sessions SessionRepository) Service {
+	return &ussdService{
+		config:   config,
+		orders:   ordersSvc,
+		menu:     menuSvc,
+		sessions: sessions,
+	}
+}
+
+func (svc ussdService) Handle(ctx context.Context, req Request) (Response, error) {
+	if req.SessionID == "" || req.PhoneNumber == "" {
+		return Response{}, errors.ErrMalformedEntity
+	}
+	s, err := svc.session(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+	if len(s.Menu) == 0 {
+		return Response{Text: msgMenuEmpty, End: true}, nil
+	}
+
+	// Gateways send every input of the session with each callback, only the
+	// ones that have not been seen yet move the session forward. A retried
+	// callback therefore renders the same screen again.
+	inputs := split(req.Text)
+	if s.Inputs > len(inputs) {
+		s.Inputs = len(inputs)
+	}
+	for _, input := range inputs[s.Inputs:] {
+		res, done, err := svc.input(ctx, &s, input)
+		if err != nil {
+			return Response{}, err
+		}
+		if done {
+			if err := svc.sessions.Remove(ctx, s.Phone); err != nil {
+				return Response{}, err
+			}
+			return res, nil
+		}
+	}
+	s.Inputs = len(inputs)
+	s.UpdatedAt = time.Now()
+	if err := svc.sessions.Save(ctx, s); err != nil {
+		return Response{}, err
+	}
+	return Response{Text: svc.render(s)}, nil
+}
+
+// session returns the session of the caller. Dialling in again while an
+// unfinished order exists offers to resume it.
+func (svc ussdService) session(ctx context.Context, req Request) (Session, error) {
+	s, err := svc.sessions.Retrieve(ctx, req.PhoneNumber)
+	switch {
+	case err == nil && s.SessionID == req.SessionID:
+		return s, nil
+	case err == nil && len(s.Cart) > 0:
+		if s.Step != StepResume {
+			s.Resume = s.Step
+		}
+		s.SessionID = req.SessionID
+		s.Inputs = 0
+		s.Notice = ""
+		s.Step = StepResume
+		return s, nil
+	case err == nil, errors.Contains(err, errors.ErrNotFound):
+		s = Session{
+			Phone:     req.PhoneNumber,
+			SessionID: req.SessionID,
+		}
+		if err := svc.start(ctx, &s); err != nil {
+			return Session{}, err
+		}
+		return s, nil
+	default:
+		return Session{}, err
+	}
+}
+
+// start loads the menu and clears the order so the session begins afresh.
+func (svc ussdService) start(ctx context.Context, s *Session) error {
+	pm := menu.PageMetadata{
+		Limit:         menuLimit,
+		Vendor:        svc.config.Vendor,
+		OnlyAvailable: true,
+	}
+	page, err := svc.menu.ListItems(ctx, svc.config.Token, pm)
+	if err != nil {
+		return err
+	}
+	s.Menu = page.Items
+	s.Step = StepMenu
+	s.Resume = ""
+	s.Page = 0
+	s.Cart = nil
+	s.Place = ""
+	s.Address = ""
+	return nil
+}
+
+// input applies a single user input to the session. It reports done when
+// the session is over and res is the final screen.
+func (svc ussdService) input(ctx context.Context, s *Session, input string) (res Response, done bool, err error) {
+	input = strings.TrimSpace(input)
+	s.Notice = ""
+
+	switch s.Step {
+	case StepResume:
+		switch input {
+		case "1":
+			s.Step = s.Resume
+			s.Resume = ""
+		case "2":
+			return Response{}, false, svc.start(ctx, s)
+		default:
+			s.Notice = msgInvalid
+		}
+	case StepMenu:
+		pages := paginate(s.Menu)
+		switch n, _ := strconv.Atoi(input); {
+		case input == inputMore && s.Page < len(pages)-1:
+			s.Page++
+		case input == inputBack && s.Page > 0:
+			s.Page--
+		case input == inputCheckout && len(s.Cart) > 0:
+			s.Step = StepPlace
+		case n > 0 && n <= len(s.Menu):
+			s.Selected = n - 1
+			s.Step = StepQuantity
+		default:
+			s.Notice = msgInvalid
+		}
+	case StepQuantity:
+		switch n, err := strconv.Atoi(input); {
+		case input == inputBack:
+			s.Step = StepMenu
+		case err != nil || n < 1 || n > maxQuantity:
+			s.Notice = fmt.Sprintf(msgInvalidQuantity, maxQuantity)
+		default:
+			item := s.Menu[s.Selected]
+			s.add(item, uint64(n))
+			s.Notice = fmt.Sprintf(msgAdded, n, item.Name, s.Total())
+			s.Step = StepMenu
+		}
+	case StepPlace:
+		switch input {
+		case "1":
+			s.Place = "inhouse"
+			s.Address = ""
+			s.Step = StepConfirm
+		case "2":
+			s.Place = "delivery"
+			s.Step = StepAddress
+		case inputBack:
+			s.Step = StepMenu
+		default:
+			s.Notice = msgInvalid
+		}
+	case StepAddress:
+		switch input {
+		case "":
+			s.Notice = msgInvalid
+		case inputBack:
+			s.Step = StepPlace
+		default:
+			s.Address = input
+			s.Step = StepConfirm
+		}
+	case StepConfirm:
+		switch input {
+		case "1":
+			res, err := svc.placeOrder(ctx, *s)
+			return res, err == nil, err
+		case "2":
+			s.Step = StepMenu
+		case "3":
+			return Response{Text: msgCancelled, End: true}, true, nil
+		case inputBack:
+			s.Step = StepPlace
+		default:
+			s.Notice = msgInvalid
+		}
+	}
+	return Response{}, false, nil
+}
+
+func (svc ussdService) placeOrder(ctx context.Context, s Session) (Response, error) {
+	metadata := orders.Metadata{
+		orders.ChannelKey:  ChannelName,
+		orders.CustomerKey: s.Phone,
+	}
+	if s.Address != "" {
+		metadata[addressKey] = s.Address
+	}
+	order := orders.Order{
+		Vendor:   svc.config.Vendor,
+		Place:    s.Place,
+		Status:   "ordered",
+		Items:    s.Cart,
+		Metadata: metadata,
+	}
+	// The gateway session is the idempotency key, so a retried final
+	// callback replaying the inputs into a new session answers with the
+	// order placed the first time.
+	ctx = orders.WithIdempotencyKey(ctx, "ussd:"+s.SessionID)
+	id, err := svc.orders.CreateOrder(ctx, svc.config.Token, order)
+	if err != nil {
+		return Response{}, err
+	}
+	created, err := svc.orders.ViewOrder(ctx, svc.config.Token, id)
+	if err != nil {
+		return Response{}, err
+	}
+	text := fmt.Sprintf(msgOrdered, created.Price, created.ID)
+	if svc.config.Till != "" {
+		text = fmt.Sprintf(msgOrderedTill, created.Price, svc.config.Till, created.ID)
+	}
+	return Response{Text: truncate(text, MaxScreen), End: true}, nil
+}
+
+func (s *Session) add(item menu.Item, qty uint64) {
+	for i := range s.Cart {
+		if s.Cart[i].ID == item.ID {
+			s.Cart[i].Quantity += qty
+			return
+		}
+	}
+	s.Cart = append(s.Cart, orders.Item{
+		ID:       item.ID,
+		Name:     item.Name,
+		Quantity: qty,
+		Price:    item.Price,
+	})
+}
+
+// split returns the inputs of a gateway session. An empty text is the
+// initial dial and carries no input.
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "*")
+}
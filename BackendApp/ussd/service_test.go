@@ -0,0 +1,84 @@
+package ussd_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders/memory"
+	"github.com/0x6flab/jikoniApp/BackendApp/ussd"
+)
+
+const (
+	token  = "token"
+	vendor = "mama-oliech"
+	phone  = "+254700000000"
+)
+
+// menuService lists a fixed menu.
+type menuService struct {
+	menu.Service
+	items []menu.Item
+}
+
+func (svc menuService) ListItems(ctx context.Context, token string, pm menu.PageMetadata) (menu.ItemsPage, error) {
+	return menu.ItemsPage{Items: svc.items}, nil
+}
+
+func TestHandleRetriedOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOrderRepo()
+	ordersSvc := orders.IdempotencyMiddleware(orders.NewOrderService(repo), repo)
+	menuSvc := menuService{items: []menu.Item{{ID: "chapati", Vendor: vendor, Name: "Chapati", Price: 30, Available: true}}}
+	svc := ussd.NewService(ussd.Config{Vendor: vendor, Token: token}, ordersSvc, menuSvc, ussd.NewMemorySessionRepository(time.Hour))
+
+	// Two chapatis, eaten in, and the order placed.
+	var res ussd.Response
+	for _, text := range []string{"", "1", "1*2", "1*2*99", "1*2*99*1", "1*2*99*1*1"} {
+		var err error
+		res, err = svc.Handle(ctx, ussd.Request{SessionID: "ATUid_1", PhoneNumber: phone, Text: text})
+		if err != nil {
+			t.Fatalf("handling %q: unexpected error: %s", text, err)
+		}
+	}
+	if !res.End {
+		t.Fatalf("expected the session to end once the order is placed, got %q", res)
+	}
+
+	// The gateway did not get the answer and retries the final callback,
+	// the session is gone so the inputs are replayed into a new one.
+	retried, err := svc.Handle(ctx, ussd.Request{SessionID: "ATUid_1", PhoneNumber: phone, Text: "1*2*99*1*1"})
+	if err != nil {
+		t.Fatalf("retrying the final callback: unexpected error: %s", err)
+	}
+	if retried != res {
+		t.Errorf("expected the retry to be answered with %q, got %q", res, retried)
+	}
+
+	page, err := ordersSvc.ListOrders(ctx, token, orders.PageMetadata{Limit: 10})
+	if err != nil {
+		t.Fatalf("listing orders: unexpected error: %s", err)
+	}
+	if len(page.Orders) != 1 {
+		t.Fatalf("expected a single order to be placed, got %d", len(page.Orders))
+	}
+	if got := page.Orders[0].Price; got != 60 {
+		t.Errorf("expected the order to cost 60, got %d", got)
+	}
+
+	// Another gateway session is another order.
+	for _, text := range []string{"", "1", "1*1", "1*1*99", "1*1*99*1", "1*1*99*1*1"} {
+		if _, err := svc.Handle(ctx, ussd.Request{SessionID: "ATUid_2", PhoneNumber: phone, Text: text}); err != nil {
+			t.Fatalf("handling %q: unexpected error: %s", text, err)
+		}
+	}
+	page, err = ordersSvc.ListOrders(ctx, token, orders.PageMetadata{Limit: 10})
+	if err != nil {
+		t.Fatalf("listing orders: unexpected error: %s", err)
+	}
+	if len(page.Orders) != 2 {
+		t.Errorf("expected a second session to place a second order, got %d orders", len(page.Orders))
+	}
+}
@@ -0,0 +1,75 @@
+package ussd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/internal/errors"
+)
+
+var _ SessionRepository = (*memorySessions)(nil)
+
+type memorySessions struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	swept    time.Time
+	sessions map[string]Session
+}
+
+// NewMemorySessionRepository instantiates an in-memory session repository.
+// Sessions that have not been updated within ttl are treated as expired and
+// can no longer be resumed.
+func NewMemorySessionRepository(ttl time.Duration) SessionRepository {
+	return &memorySessions{
+		ttl:      ttl,
+		sessions: make(map[string]Session),
+	}
+}
+
+func (repo *memorySessions) Save(_ context.Context, session Session) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.sessions[session.Phone] = session
+	repo.expire()
+	return nil
+}
+
+func (repo *memorySessions) Retrieve(_ context.Context, phone string) (Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	session, ok := repo.sessions[phone]
+	if !ok || repo.expired(session) {
+		delete(repo.sessions, phone)
+		return Session{}, errors.ErrNotFound
+	}
+	return session, nil
+}
+
+func (repo *memorySessions) Remove(_ context.Context, phone string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.sessions, phone)
+	return nil
+}
+
+func (repo *memorySessions) expired(session Session) bool {
+	return repo.ttl > 0 && time.Since(session.UpdatedAt) > repo.ttl
+}
+
+// expire drops expired sessions at most once per ttl so the map does not
+// grow without bound.
+func (repo *memorySessions) expire() {
+	if repo.ttl == 0 || time.Since(repo.swept) < repo.ttl {
+		return
+	}
+	repo.swept = time.Now()
+	for phone, session := range repo.sessions {
+		if repo.expired(session) {
+			delete(repo.sessions, phone)
+		}
+	}
+}
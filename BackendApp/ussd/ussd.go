@@ -0,0 +1,103 @@
+// Package ussd implements ordering over USSD so customers on feature phones
+// can browse the menu and place orders.
+package ussd
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x6flab/jikoniApp/BackendApp/menu"
+	"github.com/0x6flab/jikoniApp/BackendApp/orders"
+)
+
+// ChannelName is recorded in the metadata of orders placed over USSD.
+const ChannelName = "ussd"
+
+// MaxScreen is the number of characters a USSD screen can display.
+const MaxScreen = 182
+
+// Step describes which screen of the ordering flow a session is on.
+type Step string
+
+const (
+	// StepResume offers to continue an order left by a dropped session.
+	StepResume Step = "resume"
+	// StepMenu lists the menu, a page at a time.
+	StepMenu Step = "menu"
+	// StepQuantity asks how many units of the selected item to add.
+	StepQuantity Step = "quantity"
+	// StepPlace asks whether the order is eaten in or delivered.
+	StepPlace Step = "place"
+	// StepAddress asks for the delivery location.
+	StepAddress Step = "address"
+	// StepConfirm shows the order summary for confirmation.
+	StepConfirm Step = "confirm"
+)
+
+// Request is a gateway callback in the style of Africa's Talking. Text holds
+// every input of the gateway session so far separated by '*' i.e. "1*2*98".
+type Request struct {
+	SessionID   string
+	ServiceCode string
+	PhoneNumber string
+	NetworkCode string
+	Text        string
+}
+
+// Response is the screen returned to the gateway. End closes the session on
+// the handset.
+type Response struct {
+	Text string
+	End  bool
+}
+
+// String formats the response the way gateways expect it, prefixed with CON
+// when the session continues and END when it is over.
+func (res Response) String() string {
+	if res.End {
+		return "END " + res.Text
+	}
+	return "CON " + res.Text
+}
+
+// Session holds the order a phone number is building. It outlives the
+// gateway session so a dropped call can be resumed by dialling again.
+type Session struct {
+	Phone     string        `json:"phone"`
+	SessionID string        `json:"session_id"` // The gateway session the inputs belong to.
+	Inputs    int           `json:"inputs"`     // How many of the gateway session inputs have been handled.
+	Step      Step          `json:"step"`
+	Resume    Step          `json:"resume,omitempty"` // The step to return to when the user resumes.
+	Page      int           `json:"page"`
+	Selected  int           `json:"selected"` // The menu index of the item whose quantity is asked for.
+	Notice    string        `json:"notice,omitempty"`
+	Menu      []menu.Item   `json:"menu,omitempty"`
+	Cart      []orders.Item `json:"cart,omitempty"`
+	Place     string        `json:"place,omitempty"`
+	Address   string        `json:"address,omitempty"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Total returns the price of everything in the cart.
+func (s Session) Total() uint64 {
+	return orders.Order{Items: s.Cart}.ItemsTotal()
+}
+
+// SessionRepository specifies a USSD session persistence API.
+type SessionRepository interface {
+	// Save persists the session.
+	Save(ctx context.Context, session Session) error
+
+	// Retrieve retrieves the session of a phone number. If the session does
+	// not exist or has expired errors.ErrNotFound is returned.
+	Retrieve(ctx context.Context, phone string) (Session, error)
+
+	// Remove removes the session of a phone number.
+	Remove(ctx context.Context, phone string) error
+}
+
+// Service specifies an API for handling USSD gateway callbacks.
+type Service interface {
+	// Handle processes the callback and returns the next screen.
+	Handle(ctx context.Context, req Request) (Response, error)
+}